@@ -0,0 +1,217 @@
+// Package alerting fires alerts when operational conditions, such as a low
+// number of peers or a lagging block watcher, hold for some time. Alerts are
+// sent to Notifiers, e.g. a webhook, so that operators of small nodes get
+// actionable signals without running external monitoring.
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Names of the conditions which are checked by a Monitor.
+const (
+	ConditionEthRPCUnreachable = "ETH_RPC_UNREACHABLE"
+	ConditionLowPeerCount      = "LOW_PEER_COUNT"
+	ConditionStorageNearlyFull = "STORAGE_NEARLY_FULL"
+	ConditionBlockLag          = "BLOCK_LAG"
+)
+
+// Alert states.
+const (
+	// StateFiring means that the condition started to hold.
+	StateFiring = "FIRING"
+	// StateResolved means that the condition no longer holds.
+	StateResolved = "RESOLVED"
+)
+
+// Alert is sent to Notifiers when a condition starts or stops to hold.
+type Alert struct {
+	// Condition is the name of the condition, e.g. ConditionLowPeerCount.
+	Condition string `json:"condition"`
+	// State is either StateFiring or StateResolved.
+	State string `json:"state"`
+	// Message describes the readings which caused the state change.
+	Message string `json:"message"`
+	// Time is when the state changed.
+	Time time.Time `json:"time"`
+}
+
+// Readings are the values that conditions are checked against.
+type Readings struct {
+	// Time is when the readings were taken.
+	Time time.Time
+	// EthRPCReachable is false if the last request to the Ethereum RPC
+	// endpoint failed.
+	EthRPCReachable bool
+	// NumPeers is the number of connected peers.
+	NumPeers int
+	// NumOrders is the number of stored orders, including orders which were
+	// flagged for removal.
+	NumOrders int
+	// MaxOrders is the maximum number of orders that can be stored.
+	MaxOrders int
+	// BlockLag is the number of blocks the latest block processed by Mesh is
+	// behind the latest block of the Ethereum RPC endpoint. It is only
+	// meaningful if EthRPCReachable is true.
+	BlockLag int
+}
+
+// Thresholds configure when conditions hold. Conditions whose threshold is 0
+// are disabled.
+type Thresholds struct {
+	// EthRPCUnreachableFor is how long the Ethereum RPC endpoint must be
+	// unreachable before ConditionEthRPCUnreachable fires.
+	EthRPCUnreachableFor time.Duration
+	// MinPeers is the number of peers below which ConditionLowPeerCount fires.
+	MinPeers int
+	// StoragePercent is the percentage of MaxOrders above which
+	// ConditionStorageNearlyFull fires.
+	StoragePercent float64
+	// MaxBlockLag is the number of blocks above which ConditionBlockLag fires.
+	MaxBlockLag int
+	// HoldFor is how long all other conditions must hold before they fire,
+	// so that short fluctuations (e.g. losing peers while Mesh is starting) do
+	// not cause alerts.
+	HoldFor time.Duration
+}
+
+// Enabled returns true if at least one condition is enabled.
+func (t Thresholds) Enabled() bool {
+	return t.EthRPCUnreachableFor != 0 || t.MinPeers != 0 || t.StoragePercent != 0 || t.MaxBlockLag != 0
+}
+
+// condition is a condition checked by a Monitor.
+type condition struct {
+	name string
+	// holdFor is how long the condition must hold before it fires.
+	holdFor time.Duration
+	// check returns whether the condition holds and a message describing the
+	// readings. ok is false if the readings are insufficient to tell, in
+	// which case the state of the condition is left unchanged.
+	check func(Readings) (holds bool, message string, ok bool)
+}
+
+// conditionState is the state of a condition between checks.
+type conditionState struct {
+	// holdsSince is when the condition started to hold. It is the zero time
+	// if the condition does not hold.
+	holdsSince time.Time
+	firing     bool
+}
+
+// Monitor checks conditions against readings and returns alerts when a
+// condition has held for long enough or stops holding. It is not safe for
+// concurrent use.
+type Monitor struct {
+	conditions []*condition
+	states     map[string]*conditionState
+}
+
+// NewMonitor returns a Monitor which checks the conditions enabled in the
+// given thresholds.
+func NewMonitor(thresholds Thresholds) *Monitor {
+	conditions := []*condition{}
+	if thresholds.EthRPCUnreachableFor != 0 {
+		conditions = append(conditions, &condition{
+			name:    ConditionEthRPCUnreachable,
+			holdFor: thresholds.EthRPCUnreachableFor,
+			check: func(r Readings) (bool, string, bool) {
+				if r.EthRPCReachable {
+					return false, "Ethereum RPC endpoint is reachable again", true
+				}
+				return true, fmt.Sprintf("Ethereum RPC endpoint has been unreachable for at least %s", thresholds.EthRPCUnreachableFor), true
+			},
+		})
+	}
+	if thresholds.MinPeers != 0 {
+		conditions = append(conditions, &condition{
+			name:    ConditionLowPeerCount,
+			holdFor: thresholds.HoldFor,
+			check: func(r Readings) (bool, string, bool) {
+				return r.NumPeers < thresholds.MinPeers, fmt.Sprintf("connected to %d peers (minimum: %d)", r.NumPeers, thresholds.MinPeers), true
+			},
+		})
+	}
+	if thresholds.StoragePercent != 0 {
+		conditions = append(conditions, &condition{
+			name:    ConditionStorageNearlyFull,
+			holdFor: thresholds.HoldFor,
+			check: func(r Readings) (bool, string, bool) {
+				if r.MaxOrders == 0 {
+					return false, "", false
+				}
+				percent := 100 * float64(r.NumOrders) / float64(r.MaxOrders)
+				return percent > thresholds.StoragePercent, fmt.Sprintf("storing %d of %d orders (%.1f%%, threshold: %.1f%%)", r.NumOrders, r.MaxOrders, percent, thresholds.StoragePercent), true
+			},
+		})
+	}
+	if thresholds.MaxBlockLag != 0 {
+		conditions = append(conditions, &condition{
+			name:    ConditionBlockLag,
+			holdFor: thresholds.HoldFor,
+			check: func(r Readings) (bool, string, bool) {
+				if !r.EthRPCReachable {
+					// The lag is unknown.
+					return false, "", false
+				}
+				return r.BlockLag > thresholds.MaxBlockLag, fmt.Sprintf("%d blocks behind the Ethereum RPC endpoint (maximum: %d)", r.BlockLag, thresholds.MaxBlockLag), true
+			},
+		})
+	}
+	states := map[string]*conditionState{}
+	for _, cond := range conditions {
+		states[cond.name] = &conditionState{}
+	}
+	return &Monitor{
+		conditions: conditions,
+		states:     states,
+	}
+}
+
+// Check checks all conditions against the given readings. It returns an alert
+// with StateFiring for each condition which has now held for long enough and
+// an alert with StateResolved for each firing condition which no longer
+// holds.
+func (m *Monitor) Check(readings Readings) []*Alert {
+	alerts := []*Alert{}
+	for _, cond := range m.conditions {
+		state := m.states[cond.name]
+		holds, message, ok := cond.check(readings)
+		if !ok {
+			continue
+		}
+		if !holds {
+			if state.firing {
+				alerts = append(alerts, &Alert{
+					Condition: cond.name,
+					State:     StateResolved,
+					Message:   message,
+					Time:      readings.Time,
+				})
+			}
+			state.holdsSince = time.Time{}
+			state.firing = false
+			continue
+		}
+		if state.holdsSince.IsZero() {
+			state.holdsSince = readings.Time
+		}
+		if !state.firing && readings.Time.Sub(state.holdsSince) >= cond.holdFor {
+			state.firing = true
+			alerts = append(alerts, &Alert{
+				Condition: cond.name,
+				State:     StateFiring,
+				Message:   message,
+				Time:      readings.Time,
+			})
+		}
+	}
+	return alerts
+}
+
+// Notifier is notified of alerts.
+type Notifier interface {
+	Notify(ctx context.Context, alert *Alert) error
+}
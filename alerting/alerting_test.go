@@ -0,0 +1,134 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func conditionStates(alerts []*Alert) map[string]string {
+	states := map[string]string{}
+	for _, alert := range alerts {
+		states[alert.Condition] = alert.State
+	}
+	return states
+}
+
+func TestMonitor(t *testing.T) {
+	monitor := NewMonitor(Thresholds{
+		EthRPCUnreachableFor: 5 * time.Minute,
+		MinPeers:             5,
+		StoragePercent:       90,
+		MaxBlockLag:          10,
+		HoldFor:              time.Minute,
+	})
+	start := time.Now()
+	healthy := Readings{
+		Time:            start,
+		EthRPCReachable: true,
+		NumPeers:        10,
+		NumOrders:       100,
+		MaxOrders:       1000,
+		BlockLag:        0,
+	}
+	assert.Empty(t, monitor.Check(healthy))
+
+	// Conditions fire once they have held for long enough.
+	unhealthy := Readings{
+		Time:            start.Add(time.Minute),
+		EthRPCReachable: true,
+		NumPeers:        2,
+		NumOrders:       950,
+		MaxOrders:       1000,
+		BlockLag:        20,
+	}
+	assert.Empty(t, monitor.Check(unhealthy))
+	unhealthy.Time = start.Add(2 * time.Minute)
+	assert.Equal(t, map[string]string{
+		ConditionLowPeerCount:      StateFiring,
+		ConditionStorageNearlyFull: StateFiring,
+		ConditionBlockLag:          StateFiring,
+	}, conditionStates(monitor.Check(unhealthy)))
+
+	// Firing conditions don't fire again.
+	unhealthy.Time = start.Add(3 * time.Minute)
+	assert.Empty(t, monitor.Check(unhealthy))
+
+	// While the Ethereum RPC endpoint is unreachable, the block lag is
+	// unknown, so BLOCK_LAG keeps firing.
+	unreachable := unhealthy
+	unreachable.EthRPCReachable = false
+	unreachable.NumPeers = 10
+	unreachable.NumOrders = 100
+	unreachable.BlockLag = 0
+	unreachable.Time = start.Add(4 * time.Minute)
+	assert.Equal(t, map[string]string{
+		ConditionLowPeerCount:      StateResolved,
+		ConditionStorageNearlyFull: StateResolved,
+	}, conditionStates(monitor.Check(unreachable)))
+	unreachable.Time = start.Add(9 * time.Minute)
+	assert.Equal(t, map[string]string{
+		ConditionEthRPCUnreachable: StateFiring,
+	}, conditionStates(monitor.Check(unreachable)))
+
+	healthy.Time = start.Add(10 * time.Minute)
+	assert.Equal(t, map[string]string{
+		ConditionEthRPCUnreachable: StateResolved,
+		ConditionBlockLag:          StateResolved,
+	}, conditionStates(monitor.Check(healthy)))
+}
+
+func TestMonitorDisabledConditions(t *testing.T) {
+	thresholds := Thresholds{MinPeers: 5}
+	assert.True(t, thresholds.Enabled())
+	assert.False(t, Thresholds{HoldFor: time.Minute}.Enabled())
+
+	monitor := NewMonitor(thresholds)
+	alerts := monitor.Check(Readings{
+		Time:      time.Now(),
+		NumPeers:  0,
+		NumOrders: 1000,
+		MaxOrders: 1000,
+		BlockLag:  100,
+	})
+	assert.Equal(t, map[string]string{
+		ConditionLowPeerCount: StateFiring,
+	}, conditionStates(alerts))
+}
+
+func TestWebhook(t *testing.T) {
+	payloads := make(chan map[string]interface{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var payload map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		payloads <- payload
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhook(server.URL, "16Uiu2HAmGd949LwaV4KNvK2WDSiMVy7xEmW983VH75CMmefmMpP7")
+	require.NoError(t, err)
+	alert := &Alert{
+		Condition: ConditionLowPeerCount,
+		State:     StateFiring,
+		Message:   "connected to 2 peers (minimum: 5)",
+		Time:      time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, webhook.Notify(context.Background(), alert))
+	assert.Equal(t, map[string]interface{}{
+		"condition": ConditionLowPeerCount,
+		"state":     StateFiring,
+		"message":   "connected to 2 peers (minimum: 5)",
+		"time":      "2020-04-01T12:00:00Z",
+		"peerID":    "16Uiu2HAmGd949LwaV4KNvK2WDSiMVy7xEmW983VH75CMmefmMpP7",
+	}, <-payloads)
+
+	_, err = NewWebhook("ftp://example.com", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// webhookRequestTimeout is the maximum amount of time to wait for a response
+// from a webhook.
+const webhookRequestTimeout = 10 * time.Second
+
+// Webhook is a Notifier which sends each alert as a JSON-encoded POST request
+// to a URL, e.g.:
+//
+//	{"condition": "LOW_PEER_COUNT", "state": "FIRING", "message": "connected to 2 peers (minimum: 5)", "time": "2020-04-01T12:00:00Z", "peerID": "16Uiu2..."}
+type Webhook struct {
+	url    string
+	peerID string
+	client *http.Client
+}
+
+type webhookPayload struct {
+	*Alert
+	PeerID string `json:"peerID"`
+}
+
+// NewWebhook returns a Webhook which sends alerts to the given URL. peerID is
+// included in every request, so that a webhook can be shared by several
+// nodes.
+func NewWebhook(webhookURL string, peerID string) (*Webhook, error) {
+	parsedURL, err := url.Parse(webhookURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid alert webhook URL %q: scheme must be http or https", webhookURL)
+	}
+	return &Webhook{
+		url:    webhookURL,
+		peerID: peerID,
+		client: &http.Client{Timeout: webhookRequestTimeout},
+	}, nil
+}
+
+// Notify sends the alert to the webhook.
+func (w *Webhook) Notify(ctx context.Context, alert *Alert) error {
+	body, err := json.Marshal(webhookPayload{
+		Alert:  alert,
+		PeerID: w.peerID,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("alert webhook responded with status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+// +build !js
+
+// generate-order-test-vectors is a short program that generates the order
+// hashing and signing test vectors in zeroex/testdata/order_test_vectors.json.
+// It only needs to be run if vectors are added. The vectors of existing orders
+// must never change.
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"math/big"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/plaid/go-envvar/envvar"
+)
+
+type envVars struct {
+	// OutputPath is the path where the test vectors will be written.
+	OutputPath string `envvar:"OUTPUT_PATH" default:"zeroex/testdata/order_test_vectors.json"`
+}
+
+type vectorInput struct {
+	description string
+	signer      common.Address
+	order       *zeroex.Order
+}
+
+var (
+	maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	ganacheExchange = common.HexToAddress("0x48bacb9266a570d521063ef5dd96e61686dbe788")
+	mainnetExchange = common.HexToAddress("0x61935cbdd02287b511119ddb11aeb42f1593b7ef")
+	ropstenExchange = common.HexToAddress("0xfb2dd2a1366de37f7241c83d47da58fd503e2c64")
+
+	ganacheZRXAssetData  = common.Hex2Bytes("f47261b0000000000000000000000000871dd7c2b4b25e1aa18728e9d5f2af4c4e431f5c")
+	ganacheWETHAssetData = common.Hex2Bytes("f47261b00000000000000000000000000b1ba0af832d7c05fd64161e0db78e85978e8082")
+	mainnetWETHAssetData = common.Hex2Bytes("f47261b0000000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	erc721AssetData      = common.Hex2Bytes("025717920000000000000000000000001dc4c1cefef38a777b15aa20260a54e584b16c480000000000000000000000000000000000000000000000000000000000000001")
+	multiAssetData       = common.Hex2Bytes("94cfcdd7000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a00000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004600000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a00000000000000000000000000000000000000000000000000000000000000024f47261b00000000000000000000000001dc4c1cefef38a777b15aa20260a54e584b16c48000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000044025717920000000000000000000000001dc4c1cefef38a777b15aa20260a54e584b16c48000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000")
+)
+
+func mustParseBig(s string) *big.Int {
+	value, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		log.Fatalf("invalid number: %s", s)
+	}
+	return value
+}
+
+// vectorInputs are the orders included in the test vectors. New orders must
+// only ever be appended.
+var vectorInputs = []vectorInput{
+	{
+		description: "order with all fields set to zero (matches the canonical order hashing test of @0x/order-utils)",
+		signer:      constants.GanacheAccount0,
+		order: &zeroex.Order{
+			ChainID:               big.NewInt(constants.TestChainID),
+			ExchangeAddress:       common.HexToAddress("0x1dc4c1cefef38a777b15aa20260a54e584b16c48"),
+			MakerAddress:          constants.NullAddress,
+			TakerAddress:          constants.NullAddress,
+			SenderAddress:         constants.NullAddress,
+			FeeRecipientAddress:   constants.NullAddress,
+			MakerAssetData:        constants.NullAddress.Bytes(),
+			MakerFeeAssetData:     constants.NullAddress.Bytes(),
+			TakerAssetData:        constants.NullAddress.Bytes(),
+			TakerFeeAssetData:     constants.NullAddress.Bytes(),
+			Salt:                  big.NewInt(0),
+			MakerFee:              big.NewInt(0),
+			TakerFee:              big.NewInt(0),
+			MakerAssetAmount:      big.NewInt(0),
+			TakerAssetAmount:      big.NewInt(0),
+			ExpirationTimeSeconds: big.NewInt(0),
+		},
+	},
+	{
+		description: "ERC20 order with a maker fee and a fee recipient",
+		signer:      constants.GanacheAccount0,
+		order: &zeroex.Order{
+			ChainID:               big.NewInt(constants.TestChainID),
+			ExchangeAddress:       ganacheExchange,
+			MakerAddress:          constants.GanacheAccount0,
+			TakerAddress:          constants.NullAddress,
+			SenderAddress:         constants.NullAddress,
+			FeeRecipientAddress:   constants.GanacheAccount2,
+			MakerAssetData:        ganacheZRXAssetData,
+			MakerFeeAssetData:     ganacheZRXAssetData,
+			TakerAssetData:        ganacheWETHAssetData,
+			TakerFeeAssetData:     constants.NullBytes,
+			Salt:                  mustParseBig("48128453606684653105952683301312821720867493716494911784363103883716429240740"),
+			MakerFee:              mustParseBig("100000000000000000"),
+			TakerFee:              big.NewInt(0),
+			MakerAssetAmount:      mustParseBig("1000000000000000000"),
+			TakerAssetAmount:      mustParseBig("2000000000000000000"),
+			ExpirationTimeSeconds: big.NewInt(1600000000),
+		},
+	},
+	{
+		description: "ERC721 order on mainnet",
+		signer:      constants.GanacheAccount1,
+		order: &zeroex.Order{
+			ChainID:               big.NewInt(1),
+			ExchangeAddress:       mainnetExchange,
+			MakerAddress:          constants.GanacheAccount1,
+			TakerAddress:          constants.NullAddress,
+			SenderAddress:         constants.NullAddress,
+			FeeRecipientAddress:   constants.NullAddress,
+			MakerAssetData:        erc721AssetData,
+			MakerFeeAssetData:     constants.NullBytes,
+			TakerAssetData:        mainnetWETHAssetData,
+			TakerFeeAssetData:     constants.NullBytes,
+			Salt:                  big.NewInt(1548619145450),
+			MakerFee:              big.NewInt(0),
+			TakerFee:              big.NewInt(0),
+			MakerAssetAmount:      big.NewInt(1),
+			TakerAssetAmount:      mustParseBig("500000000000000000"),
+			ExpirationTimeSeconds: big.NewInt(1700000000),
+		},
+	},
+	{
+		description: "MultiAsset order on Ropsten",
+		signer:      constants.GanacheAccount2,
+		order: &zeroex.Order{
+			ChainID:               big.NewInt(3),
+			ExchangeAddress:       ropstenExchange,
+			MakerAddress:          constants.GanacheAccount2,
+			TakerAddress:          constants.NullAddress,
+			SenderAddress:         constants.NullAddress,
+			FeeRecipientAddress:   constants.NullAddress,
+			MakerAssetData:        multiAssetData,
+			MakerFeeAssetData:     constants.NullBytes,
+			TakerAssetData:        ganacheWETHAssetData,
+			TakerFeeAssetData:     constants.NullBytes,
+			Salt:                  big.NewInt(42),
+			MakerFee:              big.NewInt(0),
+			TakerFee:              big.NewInt(0),
+			MakerAssetAmount:      big.NewInt(1),
+			TakerAssetAmount:      mustParseBig("1000000000000000000"),
+			ExpirationTimeSeconds: big.NewInt(1800000000),
+		},
+	},
+	{
+		description: "order with a taker, a sender, taker fee asset data and maximum uint256 values",
+		signer:      constants.GanacheAccount3,
+		order: &zeroex.Order{
+			ChainID:               big.NewInt(constants.TestChainID),
+			ExchangeAddress:       ganacheExchange,
+			MakerAddress:          constants.GanacheAccount3,
+			TakerAddress:          constants.GanacheAccount4,
+			SenderAddress:         constants.GanacheAccount1,
+			FeeRecipientAddress:   constants.GanacheAccount2,
+			MakerAssetData:        ganacheWETHAssetData,
+			MakerFeeAssetData:     ganacheWETHAssetData,
+			TakerAssetData:        ganacheZRXAssetData,
+			TakerFeeAssetData:     ganacheZRXAssetData,
+			Salt:                  maxUint256,
+			MakerFee:              maxUint256,
+			TakerFee:              maxUint256,
+			MakerAssetAmount:      maxUint256,
+			TakerAssetAmount:      maxUint256,
+			ExpirationTimeSeconds: maxUint256,
+		},
+	},
+}
+
+func main() {
+	env := envVars{}
+	if err := envvar.Parse(&env); err != nil {
+		log.Fatal(err)
+	}
+	vectors := &zeroex.OrderTestVectors{}
+	for _, input := range vectorInputs {
+		privateKey, err := crypto.ToECDSA(constants.GanacheAccountToPrivateKey[input.signer])
+		if err != nil {
+			log.Fatal(err)
+		}
+		vector, err := zeroex.NewOrderTestVector(input.description, privateKey, input.order)
+		if err != nil {
+			log.Fatal(err)
+		}
+		vectors.Vectors = append(vectors.Vectors, vector)
+	}
+	encoded, err := zeroex.MarshalOrderTestVectors(vectors)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(env.OutputPath, encoded, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
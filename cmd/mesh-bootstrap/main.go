@@ -290,17 +290,15 @@ func main() {
 		if config.BootstrapList != "" {
 			bootstrapList = strings.Split(config.BootstrapList, ",")
 		}
-		if err := p2p.ConnectToBootstrapList(ctx, basicHost, bootstrapList); err != nil {
-			log.WithField("error", err).Fatal("could not connect to bootstrap peers")
+		bootstrapAddrInfos, err := p2p.ResolveBootstrapList(ctx, bootstrapList)
+		if err != nil {
+			log.WithField("error", err).Fatal("could not parse bootstrap list")
 		}
+		p2p.ConnectToBootstrapPeers(ctx, basicHost, bootstrapAddrInfos)
 
 		// Protect each other bootstrap peer via the connection manager so that we
 		// maintain an active connection to them. Also prevent other bootstrap nodes
 		// from being banned.
-		bootstrapAddrInfos, err := p2p.BootstrapListToAddrInfos(bootstrapList)
-		if err != nil {
-			log.WithField("error", err).Fatal("could not parse bootstrap list")
-		}
 
 		for _, addrInfo := range bootstrapAddrInfos {
 			connManager.Protect(addrInfo.ID, "bootstrap-peer")
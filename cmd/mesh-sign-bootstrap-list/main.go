@@ -0,0 +1,63 @@
+// +build !js
+
+// mesh-sign-bootstrap-list is a short program that can be used to sign a
+// bootstrap list which is served at the BOOTSTRAP_LIST_URL of Mesh nodes. The
+// signed list is written to stdout.
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/0xProject/0x-mesh/keys"
+	"github.com/0xProject/0x-mesh/p2p"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/plaid/go-envvar/envvar"
+)
+
+type envVars struct {
+	// PrivateKeyPath is the path of the private key used to sign the list. The
+	// peer ID corresponding to this key must be used as the
+	// BOOTSTRAP_LIST_SIGNER of Mesh nodes.
+	PrivateKeyPath string `envvar:"PRIVATE_KEY_PATH" default:"0x_mesh/keys/privkey"`
+	// BootstrapList is a comma-separated list of multiaddresses to sign.
+	BootstrapList string `envvar:"BOOTSTRAP_LIST"`
+}
+
+func main() {
+	env := envVars{}
+	if err := envvar.Parse(&env); err != nil {
+		log.Fatal(err)
+	}
+	privKey, err := keys.GetPrivateKeyFromPath(env.PrivateKeyPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	list := &p2p.SignedBootstrapList{
+		Addrs:     strings.Split(env.BootstrapList, ","),
+		Timestamp: time.Now().UTC(),
+	}
+	// Make sure that the list can be parsed before signing it.
+	for _, addr := range list.Addrs {
+		if _, err := ma.NewMultiaddr(addr); err != nil {
+			log.Fatalf("invalid multiaddress %q: %s", addr, err)
+		}
+	}
+	if err := list.Sign(privKey); err != nil {
+		log.Fatal(err)
+	}
+	signer, err := peer.IDFromPrivateKey(privKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("signed bootstrap list with the key of peer %s", signer.Pretty())
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(list); err != nil {
+		log.Fatal(err)
+	}
+}
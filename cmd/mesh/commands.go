@@ -0,0 +1,770 @@
+// +build !js
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/core"
+	"github.com/0xProject/0x-mesh/db"
+	"github.com/0xProject/0x-mesh/dbsnapshot"
+	"github.com/0xProject/0x-mesh/ethereum"
+	"github.com/0xProject/0x-mesh/keys"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/orderexport"
+	"github.com/0xProject/0x-mesh/rpc"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/plaid/go-envvar/envvar"
+)
+
+const usage = `Usage: mesh <command> [arguments]
+
+Commands:
+  init                     Generate a private key and a config file for a new node
+  start                    Start a 0x Mesh node (the default if no command is given)
+  sign-order               Sign the order read from stdin and print the signed order
+  validate-order [file]    Check orders against a custom order filter without a running node
+  decode-assetdata <hex>   Print asset data in a human-readable form
+  orders list              List the orders stored by a running node
+  orders add <file>        Add the signed orders in a JSON file ("-" for stdin) to a running node
+  orders remove <hash>...  Remove orders from the database of a stopped node
+  orders export [file]     Download the orders of a running node as JSON Lines or CSV
+  orders import <file>     Add the orders in an export ("-" for stdin) to a running node
+  peers list               List the peers a running node is connected to
+  peers ban <peerID>...    Ban peers from a running node
+  db compact               Compact the database of a stopped node
+  db export [file]         Export the orders in the database of a stopped node as JSON
+  db snapshot <dir>        Write a signed snapshot of the database of a stopped node
+  db verify                Check the database of a stopped node for corruption
+
+Commands which talk to a running node accept the -rpc-addr flag. Commands which
+operate on the database directly accept the -data-dir flag and require the node
+to be stopped. Run "mesh <command> <subcommand> -h" for the flags of a command.
+`
+
+// defaultOrdersPerPage is the number of orders requested per page by
+// "orders list".
+const defaultOrdersPerPage = 1000
+
+// defaultImportBatchSize is the number of orders sent per AddOrders request by
+// "orders import".
+const defaultImportBatchSize = 500
+
+// usageError is returned when a command is invoked incorrectly.
+type usageError struct {
+	message string
+}
+
+func (e usageError) Error() string {
+	return e.message
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		runStart(nil)
+		return
+	}
+
+	command, args := os.Args[1], os.Args[2:]
+	var err error
+	switch command {
+	case "start":
+		runStart(args)
+		return
+	case "init":
+		err = runInit(args)
+	case "sign-order":
+		err = runSignOrder(args)
+	case "validate-order":
+		err = runValidateOrder(args)
+	case "decode-assetdata":
+		err = runDecodeAssetData(args)
+	case "orders":
+		err = runSubcommand(command, args, map[string]func([]string) error{
+			"list":   ordersList,
+			"add":    ordersAdd,
+			"remove": ordersRemove,
+			"export": ordersExport,
+			"import": ordersImport,
+		})
+	case "peers":
+		err = runSubcommand(command, args, map[string]func([]string) error{
+			"list": peersList,
+			"ban":  peersBan,
+		})
+	case "db":
+		err = runSubcommand(command, args, map[string]func([]string) error{
+			"compact":  dbCompact,
+			"export":   dbExport,
+			"snapshot": dbSnapshot,
+			"verify":   dbVerify,
+		})
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		err = usageError{message: fmt.Sprintf("unknown command: %q", command)}
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+		if _, ok := err.(usageError); ok {
+			fmt.Fprintf(os.Stderr, "\n%s", usage)
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+// runSubcommand runs the subcommand named by the first of the given args.
+func runSubcommand(command string, args []string, subcommands map[string]func([]string) error) error {
+	if len(args) == 0 {
+		return usageError{message: fmt.Sprintf("missing subcommand for %q", command)}
+	}
+	run, found := subcommands[args[0]]
+	if !found {
+		return usageError{message: fmt.Sprintf("unknown subcommand for %q: %q", command, args[0])}
+	}
+	return run(args[1:])
+}
+
+// rpcFlags holds the flags of commands which talk to a running node.
+type rpcFlags struct {
+	rpcAddr string
+}
+
+func newRPCFlags(flags *flag.FlagSet) (*rpcFlags, error) {
+	var config standaloneConfig
+	if err := envvar.Parse(&config); err != nil {
+		return nil, err
+	}
+	f := &rpcFlags{}
+	flags.StringVar(&f.rpcAddr, "rpc-addr", "ws://"+config.WSRPCAddr, "URL of the JSON-RPC API of the node (defaults to the WS_RPC_ADDR the node uses)")
+	return f, nil
+}
+
+func (f *rpcFlags) dial() (*rpc.Client, error) {
+	client, err := rpc.NewClient(f.rpcAddr)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to the node at %s (is it running?): %s", f.rpcAddr, err.Error())
+	}
+	return client, nil
+}
+
+// dbConfig is the subset of core.Config which is needed to open the database
+// of a stopped node.
+type dbConfig struct {
+	DataDir                 string `envvar:"DATA_DIR" default:"0x_mesh"`
+	EthereumChainID         int    `envvar:"ETHEREUM_CHAIN_ID" default:"0"`
+	CustomContractAddresses string `envvar:"CUSTOM_CONTRACT_ADDRESSES" default:""`
+}
+
+// dbFlags holds the flags of commands which operate on the database directly.
+type dbFlags struct {
+	config dbConfig
+}
+
+func newDBFlags(flags *flag.FlagSet) (*dbFlags, error) {
+	f := &dbFlags{}
+	if err := envvar.Parse(&f.config); err != nil {
+		return nil, err
+	}
+	flags.StringVar(&f.config.DataDir, "data-dir", f.config.DataDir, "data directory of the node (defaults to DATA_DIR)")
+	flags.IntVar(&f.config.EthereumChainID, "chain-id", f.config.EthereumChainID, "chain ID of the node (defaults to ETHEREUM_CHAIN_ID)")
+	return f, nil
+}
+
+func (f *dbFlags) databasePath() string {
+	return filepath.Join(f.config.DataDir, "db")
+}
+
+// openMeshDB opens the database of a stopped node. The contract addresses are
+// needed to maintain the indexes of the orders collection.
+func (f *dbFlags) openMeshDB() (*meshdb.MeshDB, error) {
+	if f.config.EthereumChainID == 0 {
+		return nil, usageError{message: "ETHEREUM_CHAIN_ID or -chain-id is required"}
+	}
+	contractAddresses, err := getContractAddresses(f.config.EthereumChainID, f.config.CustomContractAddresses)
+	if err != nil {
+		return nil, err
+	}
+	meshDB, err := meshdb.New(f.databasePath(), contractAddresses)
+	if err != nil {
+		return nil, fmt.Errorf("could not open database (is the node still running?): %s", err.Error())
+	}
+	// Databases created by newer versions of Mesh might not be read correctly.
+	if _, err := meshDB.PendingMigrations(); err != nil {
+		meshDB.Close()
+		return nil, err
+	}
+	return meshDB, nil
+}
+
+// getContractAddresses returns the contract addresses for the given chain ID
+// or, if customContractAddresses is not empty, the JSON encoded contract
+// addresses it holds (see CUSTOM_CONTRACT_ADDRESSES).
+func getContractAddresses(chainID int, customContractAddresses string) (ethereum.ContractAddresses, error) {
+	if customContractAddresses == "" {
+		return ethereum.NewContractAddressesForChainID(chainID)
+	}
+	var contractAddresses ethereum.ContractAddresses
+	if err := json.Unmarshal([]byte(customContractAddresses), &contractAddresses); err != nil {
+		return ethereum.ContractAddresses{}, fmt.Errorf("CUSTOM_CONTRACT_ADDRESSES is invalid: %s", err.Error())
+	}
+	return contractAddresses, nil
+}
+
+// printMigrationPlan prints the schema version of the database of a stopped
+// node and the migrations which would be applied when the node is started
+// with the given config, without changing the database.
+func printMigrationPlan(coreConfig core.Config) error {
+	dbFlags := &dbFlags{
+		config: dbConfig{
+			DataDir:                 coreConfig.DataDir,
+			EthereumChainID:         coreConfig.EthereumChainID,
+			CustomContractAddresses: coreConfig.CustomContractAddresses,
+		},
+	}
+	meshDB, err := dbFlags.openMeshDB()
+	if err != nil {
+		return err
+	}
+	defer meshDB.Close()
+	version, err := meshDB.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	pendingMigrations, err := meshDB.PendingMigrations()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("database schema version: %d (latest: %d)\n", version, meshdb.CurrentSchemaVersion)
+	if len(pendingMigrations) == 0 {
+		fmt.Println("no migrations are pending")
+		return nil
+	}
+	fmt.Println("pending migrations:")
+	for _, migration := range pendingMigrations {
+		fmt.Printf("  %d: %s\n", migration.Version, migration.Description)
+	}
+	return nil
+}
+
+// ordersList prints the orders stored by a running node.
+func ordersList(args []string) error {
+	flags := flag.NewFlagSet("orders list", flag.ExitOnError)
+	rpcFlags, err := newRPCFlags(flags)
+	if err != nil {
+		return err
+	}
+	perPage := flags.Int("per-page", defaultOrdersPerPage, "number of orders to request at once")
+	_ = flags.Parse(args)
+
+	client, err := rpcFlags.dial()
+	if err != nil {
+		return err
+	}
+	ordersInfos := []*types.OrderInfo{}
+	snapshotID := ""
+	for page := 0; ; page++ {
+		response, err := client.GetOrders(page, *perPage, snapshotID)
+		if err != nil {
+			return err
+		}
+		if len(response.OrdersInfos) == 0 {
+			break
+		}
+		ordersInfos = append(ordersInfos, response.OrdersInfos...)
+		snapshotID = response.SnapshotID
+	}
+	return printJSON(os.Stdout, ordersInfos)
+}
+
+// ordersAdd adds the signed orders in a JSON file to a running node and prints
+// the validation results.
+func ordersAdd(args []string) error {
+	flags := flag.NewFlagSet("orders add", flag.ExitOnError)
+	rpcFlags, err := newRPCFlags(flags)
+	if err != nil {
+		return err
+	}
+	pinned := flags.Bool("pinned", true, "whether the orders should be pinned")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return usageError{message: "orders add requires exactly one file argument"}
+	}
+
+	var input io.Reader = os.Stdin
+	if path := flags.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+	data, err := ioutil.ReadAll(input)
+	if err != nil {
+		return err
+	}
+	var signedOrders []*zeroex.SignedOrder
+	if err := json.Unmarshal(data, &signedOrders); err != nil {
+		return fmt.Errorf("expected a JSON array of signed orders: %s", err.Error())
+	}
+
+	client, err := rpcFlags.dial()
+	if err != nil {
+		return err
+	}
+	validationResults, err := client.AddOrders(signedOrders, types.AddOrdersOpts{Pinned: *pinned})
+	if err != nil {
+		return err
+	}
+	return printJSON(os.Stdout, validationResults)
+}
+
+// ordersExport downloads the orders of a running node from the /orders/export
+// endpoint of its HTTP RPC server and writes them to a file or stdout.
+func ordersExport(args []string) error {
+	flags := flag.NewFlagSet("orders export", flag.ExitOnError)
+	var config standaloneConfig
+	if err := envvar.Parse(&config); err != nil {
+		return err
+	}
+	httpAddr := flags.String("http-addr", "http://"+config.HTTPRPCAddr, "URL of the HTTP RPC server of the node (defaults to the HTTP_RPC_ADDR the node uses)")
+	format := flags.String("format", string(orderexport.FormatJSONL), "format of the export (jsonl or csv)")
+	compress := flags.Bool("gzip", false, "whether to compress the export with gzip")
+	status := flags.String("status", "fillable", "which orders to export (fillable, removed or all)")
+	makerAddress := flags.String("maker", "", "only export orders from this maker address")
+	makerAssetData := flags.String("maker-asset-data", "", "only export orders with this maker asset data")
+	takerAssetData := flags.String("taker-asset-data", "", "only export orders with this taker asset data")
+	_ = flags.Parse(args)
+	if flags.NArg() > 1 {
+		return usageError{message: "orders export accepts at most one file argument"}
+	}
+	if _, err := orderexport.ParseFormat(*format); err != nil {
+		return usageError{message: err.Error()}
+	}
+
+	query := url.Values{}
+	query.Set("format", *format)
+	query.Set("gzip", strconv.FormatBool(*compress))
+	query.Set("status", *status)
+	if *makerAddress != "" {
+		query.Set("makerAddress", *makerAddress)
+	}
+	if *makerAssetData != "" {
+		query.Set("makerAssetData", *makerAssetData)
+	}
+	if *takerAssetData != "" {
+		query.Set("takerAssetData", *takerAssetData)
+	}
+	exportURL := strings.TrimSuffix(*httpAddr, "/") + "/orders/export?" + query.Encode()
+	res, err := http.Get(exportURL)
+	if err != nil {
+		return fmt.Errorf("could not connect to the node at %s (is it running?): %s", *httpAddr, err.Error())
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		message, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("export failed with status code %d: %s", res.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var output io.Writer = os.Stdout
+	if flags.NArg() == 1 && flags.Arg(0) != "-" {
+		file, err := os.Create(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+	_, err = io.Copy(output, res.Body)
+	return err
+}
+
+// ordersImportResult is the output of "orders import".
+type ordersImportResult struct {
+	NumAccepted int                                 `json:"numAccepted"`
+	NumRejected int                                 `json:"numRejected"`
+	Rejected    []*ordervalidator.RejectedOrderInfo `json:"rejected"`
+}
+
+// ordersImport adds the orders in an export created by "orders export" or
+// mesh_exportOrders to a running node. The orders are validated like any
+// other orders added via AddOrders.
+func ordersImport(args []string) error {
+	flags := flag.NewFlagSet("orders import", flag.ExitOnError)
+	rpcFlags, err := newRPCFlags(flags)
+	if err != nil {
+		return err
+	}
+	formatName := flags.String("format", string(orderexport.FormatJSONL), "format of the export (jsonl or csv); gzip-compressed input is detected automatically")
+	pinned := flags.Bool("pinned", true, "whether the orders should be pinned")
+	batchSize := flags.Int("batch-size", defaultImportBatchSize, "number of orders to add at once")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return usageError{message: "orders import requires exactly one file argument"}
+	}
+	format, err := orderexport.ParseFormat(*formatName)
+	if err != nil {
+		return usageError{message: err.Error()}
+	}
+	if *batchSize <= 0 {
+		return usageError{message: "-batch-size must be positive"}
+	}
+
+	var input io.Reader = os.Stdin
+	if path := flags.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+	reader, err := orderexport.NewReader(input, format)
+	if err != nil {
+		return err
+	}
+
+	client, err := rpcFlags.dial()
+	if err != nil {
+		return err
+	}
+	result := &ordersImportResult{
+		Rejected: []*ordervalidator.RejectedOrderInfo{},
+	}
+	addBatch := func(signedOrders []*zeroex.SignedOrder) error {
+		validationResults, err := client.AddOrders(signedOrders, types.AddOrdersOpts{Pinned: *pinned})
+		if err != nil {
+			return err
+		}
+		result.NumAccepted += len(validationResults.Accepted)
+		result.NumRejected += len(validationResults.Rejected)
+		result.Rejected = append(result.Rejected, validationResults.Rejected...)
+		return nil
+	}
+	batch := []*zeroex.SignedOrder{}
+	for i := 0; ; i++ {
+		signedOrderRaw, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("could not read order %d: %s", i, err.Error())
+		}
+		var signedOrder zeroex.SignedOrder
+		if err := json.Unmarshal(*signedOrderRaw, &signedOrder); err != nil {
+			return fmt.Errorf("order %d is not a valid signed order: %s", i, err.Error())
+		}
+		batch = append(batch, &signedOrder)
+		if len(batch) == *batchSize {
+			if err := addBatch(batch); err != nil {
+				return err
+			}
+			batch = []*zeroex.SignedOrder{}
+		}
+	}
+	if len(batch) > 0 {
+		if err := addBatch(batch); err != nil {
+			return err
+		}
+	}
+	return printJSON(os.Stdout, result)
+}
+
+// ordersRemove permanently deletes orders from the database of a stopped
+// node. The node does not emit order events for the removed orders.
+func ordersRemove(args []string) error {
+	flags := flag.NewFlagSet("orders remove", flag.ExitOnError)
+	dbFlags, err := newDBFlags(flags)
+	if err != nil {
+		return err
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() == 0 {
+		return usageError{message: "orders remove requires at least one order hash"}
+	}
+	orderHashes := make([]common.Hash, flags.NArg())
+	for i, arg := range flags.Args() {
+		if len(common.FromHex(arg)) != common.HashLength {
+			return fmt.Errorf("invalid order hash: %q", arg)
+		}
+		orderHashes[i] = common.HexToHash(arg)
+	}
+
+	meshDB, err := dbFlags.openMeshDB()
+	if err != nil {
+		return err
+	}
+	defer meshDB.Close()
+	for _, orderHash := range orderHashes {
+		if err := meshDB.Orders.Delete(orderHash.Bytes()); err != nil {
+			if _, ok := err.(db.NotFoundError); ok {
+				return fmt.Errorf("order not found: %s", orderHash.Hex())
+			}
+			return err
+		}
+		fmt.Printf("removed order %s\n", orderHash.Hex())
+	}
+	return nil
+}
+
+// peersList prints the peers a running node is connected to.
+func peersList(args []string) error {
+	flags := flag.NewFlagSet("peers list", flag.ExitOnError)
+	rpcFlags, err := newRPCFlags(flags)
+	if err != nil {
+		return err
+	}
+	_ = flags.Parse(args)
+
+	client, err := rpcFlags.dial()
+	if err != nil {
+		return err
+	}
+	peers, err := client.GetPeers()
+	if err != nil {
+		return err
+	}
+	return printJSON(os.Stdout, peers)
+}
+
+// peersBan bans peers from a running node.
+func peersBan(args []string) error {
+	flags := flag.NewFlagSet("peers ban", flag.ExitOnError)
+	rpcFlags, err := newRPCFlags(flags)
+	if err != nil {
+		return err
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() == 0 {
+		return usageError{message: "peers ban requires at least one peer ID"}
+	}
+	peerIDs := make([]peer.ID, flags.NArg())
+	for i, arg := range flags.Args() {
+		peerID, err := peer.IDB58Decode(arg)
+		if err != nil {
+			return fmt.Errorf("invalid peer ID %q: %s", arg, err.Error())
+		}
+		peerIDs[i] = peerID
+	}
+
+	client, err := rpcFlags.dial()
+	if err != nil {
+		return err
+	}
+	for _, peerID := range peerIDs {
+		if err := client.BanPeer(peerID); err != nil {
+			return fmt.Errorf("could not ban peer %s: %s", peerID.Pretty(), err.Error())
+		}
+		fmt.Printf("banned peer %s\n", peerID.Pretty())
+	}
+	return nil
+}
+
+// dbCompact compacts the database of a stopped node.
+func dbCompact(args []string) error {
+	flags := flag.NewFlagSet("db compact", flag.ExitOnError)
+	dbFlags, err := newDBFlags(flags)
+	if err != nil {
+		return err
+	}
+	_ = flags.Parse(args)
+
+	database, err := db.Open(dbFlags.databasePath())
+	if err != nil {
+		return fmt.Errorf("could not open database (is the node still running?): %s", err.Error())
+	}
+	defer database.Close()
+	return database.Compact()
+}
+
+// dbExport writes the signed orders stored in the database of a stopped node
+// to a file or stdout. The output can be imported into a running node with
+// "orders add".
+func dbExport(args []string) error {
+	flags := flag.NewFlagSet("db export", flag.ExitOnError)
+	dbFlags, err := newDBFlags(flags)
+	if err != nil {
+		return err
+	}
+	includeRemoved := flags.Bool("include-removed", false, "whether to include orders which have been flagged for removal")
+	_ = flags.Parse(args)
+	if flags.NArg() > 1 {
+		return usageError{message: "db export accepts at most one file argument"}
+	}
+
+	meshDB, err := dbFlags.openMeshDB()
+	if err != nil {
+		return err
+	}
+	defer meshDB.Close()
+	var orders []*meshdb.Order
+	if *includeRemoved {
+		err = meshDB.Orders.FindAll(&orders)
+	} else {
+		notRemovedFilter := meshDB.Orders.IsRemovedIndex.ValueFilter([]byte{0})
+		err = meshDB.Orders.NewQuery(notRemovedFilter).Run(&orders)
+	}
+	if err != nil {
+		return err
+	}
+	signedOrders := make([]*zeroex.SignedOrder, len(orders))
+	for i, order := range orders {
+		signedOrders[i] = order.SignedOrder
+	}
+
+	var output io.Writer = os.Stdout
+	if flags.NArg() == 1 && flags.Arg(0) != "-" {
+		file, err := os.Create(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+	return printJSON(output, signedOrders)
+}
+
+// dbSnapshot writes a snapshot of the database of a stopped node and a signed
+// manifest describing it to a directory. Both files should be uploaded to the
+// same location, which is then used as the DB_SNAPSHOT_URL of new nodes.
+func dbSnapshot(args []string) error {
+	flags := flag.NewFlagSet("db snapshot", flag.ExitOnError)
+	dbFlags, err := newDBFlags(flags)
+	if err != nil {
+		return err
+	}
+	keyPath := flags.String("key", "", "path of the private key used to sign the manifest (defaults to the key of the node)")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return usageError{message: "db snapshot requires exactly one directory argument"}
+	}
+	if *keyPath == "" {
+		*keyPath = filepath.Join(dbFlags.config.DataDir, "keys", "privkey")
+	}
+	privKey, err := keys.GetPrivateKeyFromPath(*keyPath)
+	if err != nil {
+		return err
+	}
+	signer, err := peer.IDFromPrivateKey(privKey)
+	if err != nil {
+		return err
+	}
+
+	meshDB, err := dbFlags.openMeshDB()
+	if err != nil {
+		return err
+	}
+	defer meshDB.Close()
+	dir := flags.Arg(0)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	tmpPath := filepath.Join(dir, "db_snapshot.tmp")
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+	hash := sha256.New()
+	header, err := dbsnapshot.Create(meshDB, io.MultiWriter(file, hash))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	snapshotName := fmt.Sprintf("db_snapshot_%d_%d.jsonl.gz", header.ChainID, header.BlockNumber)
+	if err := os.Rename(tmpPath, filepath.Join(dir, snapshotName)); err != nil {
+		return err
+	}
+
+	manifest := &dbsnapshot.Manifest{
+		SnapshotURL: snapshotName,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		ChainID:     header.ChainID,
+		BlockNumber: header.BlockNumber,
+		BlockHash:   header.BlockHash,
+		NumOrders:   header.NumOrders,
+		CreatedAt:   header.CreatedAt,
+	}
+	if err := manifest.Sign(privKey); err != nil {
+		return err
+	}
+	manifestFile, err := os.Create(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return err
+	}
+	defer manifestFile.Close()
+	if err := printJSON(manifestFile, manifest); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote snapshot of %d orders at block %d signed by %s to %s\n", header.NumOrders, header.BlockNumber, signer.Pretty(), dir)
+	return nil
+}
+
+// errDatabaseCorrupted is returned by "db verify" if problems were found and
+// not repaired, so that the command exits with a non-zero status.
+var errDatabaseCorrupted = errors.New("the database is corrupted (run with -repair to fix it)")
+
+// dbVerify checks every model and index in the database of a stopped node and
+// prints the problems found for each collection. Orders are also checked for
+// mismatching hashes and invalid signatures. With -repair, invalid models are
+// deleted and the indexes are rebuilt.
+func dbVerify(args []string) error {
+	flags := flag.NewFlagSet("db verify", flag.ExitOnError)
+	dbFlags, err := newDBFlags(flags)
+	if err != nil {
+		return err
+	}
+	repair := flags.Bool("repair", false, "whether to delete invalid models and rebuild the indexes")
+	_ = flags.Parse(args)
+
+	meshDB, err := dbFlags.openMeshDB()
+	if err != nil {
+		return err
+	}
+	defer meshDB.Close()
+	results, err := meshDB.Verify(*repair)
+	if err != nil {
+		return err
+	}
+	if err := printJSON(os.Stdout, results); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if !result.OK() && !result.Repaired {
+			return errDatabaseCorrupted
+		}
+	}
+	return nil
+}
+
+// printJSON writes the indented JSON encoding of v to w.
+func printJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return errors.New("could not encode output as JSON: " + err.Error())
+	}
+	return nil
+}
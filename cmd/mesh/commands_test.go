@@ -0,0 +1,110 @@
+// +build !js
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/ethereum"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/scenario"
+	"github.com/0xProject/0x-mesh/scenario/orderopts"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSubcommand(t *testing.T) {
+	var receivedArgs []string
+	subcommands := map[string]func([]string) error{
+		"list": func(args []string) error {
+			receivedArgs = args
+			return nil
+		},
+	}
+
+	require.NoError(t, runSubcommand("orders", []string{"list", "-per-page", "10"}, subcommands))
+	assert.Equal(t, []string{"-per-page", "10"}, receivedArgs)
+
+	err := runSubcommand("orders", []string{}, subcommands)
+	assert.IsType(t, usageError{}, err)
+	err = runSubcommand("orders", []string{"frobnicate"}, subcommands)
+	assert.IsType(t, usageError{}, err)
+}
+
+func TestGetContractAddresses(t *testing.T) {
+	contractAddresses, err := getContractAddresses(constants.TestChainID, "")
+	require.NoError(t, err)
+	assert.Equal(t, ethereum.GanacheAddresses, contractAddresses)
+
+	customContractAddresses, err := json.Marshal(ethereum.GanacheAddresses)
+	require.NoError(t, err)
+	contractAddresses, err = getContractAddresses(12345, string(customContractAddresses))
+	require.NoError(t, err)
+	assert.Equal(t, ethereum.GanacheAddresses, contractAddresses)
+
+	_, err = getContractAddresses(12345, "not json")
+	assert.Error(t, err)
+}
+
+func TestOrdersRemoveAndDBExport(t *testing.T) {
+	dataDir := filepath.Join("/tmp/mesh_commands_testing", uuid.New().String())
+	require.NoError(t, os.MkdirAll(dataDir, os.ModePerm))
+	defer os.RemoveAll(dataDir)
+
+	// Store two orders in the database of a stopped node.
+	meshDB, err := meshdb.New(filepath.Join(dataDir, "db"), ethereum.GanacheAddresses)
+	require.NoError(t, err)
+	removedOrder := storeTestOrder(t, meshDB, scenario.NewSignedTestOrder(t, orderopts.Salt(big.NewInt(1))))
+	keptOrder := storeTestOrder(t, meshDB, scenario.NewSignedTestOrder(t, orderopts.Salt(big.NewInt(2))))
+	meshDB.Close()
+
+	dbArgs := []string{"-data-dir", dataDir, "-chain-id", strconv.Itoa(constants.TestChainID)}
+
+	// The chain ID is required to open the database.
+	err = ordersRemove([]string{"-data-dir", dataDir, "-chain-id", "0", removedOrder.Hash.Hex()})
+	assert.IsType(t, usageError{}, err)
+
+	// Invalid and unknown order hashes are rejected.
+	err = ordersRemove(append(dbArgs, "0x1234"))
+	assert.Error(t, err)
+	err = ordersRemove(append(dbArgs, common.HexToHash("0x1").Hex()))
+	assert.EqualError(t, err, "order not found: "+common.HexToHash("0x1").Hex())
+
+	require.NoError(t, ordersRemove(append(dbArgs, removedOrder.Hash.Hex())))
+
+	// Only the remaining order is exported.
+	exportPath := filepath.Join(dataDir, "orders.json")
+	require.NoError(t, dbExport(append(dbArgs, exportPath)))
+	data, err := ioutil.ReadFile(exportPath)
+	require.NoError(t, err)
+	var exportedOrders []*zeroex.SignedOrder
+	require.NoError(t, json.Unmarshal(data, &exportedOrders))
+	require.Len(t, exportedOrders, 1)
+	exportedOrderHash, err := exportedOrders[0].ComputeOrderHash()
+	require.NoError(t, err)
+	assert.Equal(t, keptOrder.Hash, exportedOrderHash)
+}
+
+func storeTestOrder(t *testing.T, meshDB *meshdb.MeshDB, signedOrder *zeroex.SignedOrder) *meshdb.Order {
+	orderHash, err := signedOrder.ComputeOrderHash()
+	require.NoError(t, err)
+	order := &meshdb.Order{
+		Hash:                     orderHash,
+		SignedOrder:              signedOrder,
+		LastUpdated:              time.Now().UTC(),
+		FillableTakerAssetAmount: signedOrder.TakerAssetAmount,
+	}
+	require.NoError(t, meshDB.Orders.Insert(order))
+	return order
+}
@@ -0,0 +1,34 @@
+// +build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// runDecodeAssetData prints a human-readable representation of each of the
+// given hex-encoded asset data. It fails if any of them cannot be decoded.
+func runDecodeAssetData(args []string) error {
+	flags := flag.NewFlagSet("decode-assetdata", flag.ExitOnError)
+	_ = flags.Parse(args)
+	if flags.NArg() == 0 {
+		return usageError{message: "decode-assetdata requires at least one asset data argument"}
+	}
+	decoder := zeroex.NewAssetDataDecoder()
+	for _, arg := range flags.Args() {
+		assetData, err := hexutil.Decode(arg)
+		if err != nil {
+			return fmt.Errorf("invalid asset data %q: %s", arg, err.Error())
+		}
+		description, err := decoder.Describe(assetData)
+		if err != nil {
+			return fmt.Errorf("could not decode %s: %s", arg, err.Error())
+		}
+		fmt.Println(description)
+	}
+	return nil
+}
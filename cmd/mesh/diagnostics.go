@@ -0,0 +1,120 @@
+// +build !js
+
+package main
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// dumpsDirName is the name of the directory in the data directory where
+// goroutine and heap dumps are written.
+const dumpsDirName = "dumps"
+
+// startDiagnosticsServer starts an HTTP server on the given address which
+// exposes the net/http/pprof endpoints under /debug/pprof/, expvar variables
+// under /debug/vars and a trigger for writing goroutine and heap dumps to
+// disk under /debug/dump. It blocks until there is an error or the given
+// context is canceled.
+func startDiagnosticsServer(ctx context.Context, addr string, dataDir string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/dump", func(w http.ResponseWriter, r *http.Request) {
+		handleDumpRequest(w, r, filepath.Join(dataDir, dumpsDirName))
+	})
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.WithField("address", listener.Addr().String()).Info("started diagnostics server")
+	server := &http.Server{Handler: mux}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// handleDumpRequest writes a goroutine or heap dump (depending on the "type"
+// query parameter) to a new file in dumpsDir and responds with the path of the
+// file. Writing dumps to disk makes it possible to capture the state of a node
+// at a specific point in time and analyze it later with `go tool pprof`.
+func handleDumpRequest(w http.ResponseWriter, r *http.Request, dumpsDir string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed (use POST)", http.StatusMethodNotAllowed)
+		return
+	}
+	dumpType := r.URL.Query().Get("type")
+	var profile *runtimepprof.Profile
+	debug := 0
+	switch dumpType {
+	case "goroutine":
+		profile = runtimepprof.Lookup("goroutine")
+		// Print the stack of every goroutine in the same format as an unrecovered
+		// panic.
+		debug = 2
+	case "heap":
+		// Run a GC so that the profile reflects the current heap.
+		runtime.GC()
+		profile = runtimepprof.Lookup("heap")
+	default:
+		http.Error(w, `invalid dump type (must be "goroutine" or "heap")`, http.StatusBadRequest)
+		return
+	}
+
+	path, err := writeDump(dumpsDir, dumpType, profile, debug)
+	if err != nil {
+		log.WithError(err).WithField("type", dumpType).Error("could not write dump")
+		http.Error(w, "could not write dump (check logs for details)", http.StatusInternalServerError)
+		return
+	}
+	log.WithFields(log.Fields{
+		"type": dumpType,
+		"path": path,
+	}).Info("wrote dump")
+	fmt.Fprintln(w, path)
+}
+
+func writeDump(dumpsDir string, dumpType string, profile *runtimepprof.Profile, debug int) (string, error) {
+	if err := os.MkdirAll(dumpsDir, os.ModePerm); err != nil {
+		return "", err
+	}
+	extension := "pprof"
+	if debug > 0 {
+		extension = "txt"
+	}
+	fileName := fmt.Sprintf("%s-%s.%s", dumpType, time.Now().UTC().Format("20060102T150405.000Z"), extension)
+	path := filepath.Join(dumpsDir, fileName)
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := profile.WriteTo(file, debug); err != nil {
+		_ = file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,264 @@
+// +build !js
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/0xProject/0x-mesh/ethereum/ethrpcclient"
+	"github.com/0xProject/0x-mesh/keys"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// initConnectivityCheckTimeout is the maximum amount of time "mesh init"
+// waits for the Ethereum RPC endpoint to respond.
+const initConnectivityCheckTimeout = 15 * time.Second
+
+// chainDefaults holds the settings which differ between the supported chains.
+type chainDefaults struct {
+	// Name is the human-readable name of the chain.
+	Name string
+	// BlockTime is the approximate time between blocks.
+	BlockTime time.Duration
+	// BlockPollingInterval is the recommended BLOCK_POLLING_INTERVAL.
+	BlockPollingInterval time.Duration
+	// UseBootstrapList is the recommended USE_BOOTSTRAP_LIST. The public
+	// bootstrap nodes are of no use on local development chains.
+	UseBootstrapList bool
+}
+
+var defaultsByChainID = map[int]chainDefaults{
+	1:    {Name: "Mainnet", BlockTime: 13 * time.Second, BlockPollingInterval: 5 * time.Second, UseBootstrapList: true},
+	3:    {Name: "Ropsten", BlockTime: 13 * time.Second, BlockPollingInterval: 5 * time.Second, UseBootstrapList: true},
+	4:    {Name: "Rinkeby", BlockTime: 15 * time.Second, BlockPollingInterval: 5 * time.Second, UseBootstrapList: true},
+	42:   {Name: "Kovan", BlockTime: 4 * time.Second, BlockPollingInterval: 2 * time.Second, UseBootstrapList: true},
+	1337: {Name: "Ganache snapshot", BlockTime: time.Second, BlockPollingInterval: time.Second, UseBootstrapList: false},
+}
+
+// configFileTemplate is the template for the config file written by "mesh
+// init". The config file uses the format of Docker env files, i.e. one
+// KEY=VALUE pair per line without quotes.
+var configFileTemplate = template.Must(template.New("config").Parse(`# 0x Mesh configuration generated by "mesh init" on {{.GeneratedAt}}.
+#
+# Start a node with this configuration with "mesh start -config {{.Path}}" or
+# "docker run --env-file {{.Path}} 0xorg/mesh". Environment variables take
+# precedence over the values in this file. All other settings are documented in
+# docs/deployment.md.
+
+# The ID of the Ethereum chain to use ({{.Chain.Name}}).
+ETHEREUM_CHAIN_ID={{.ChainID}}
+
+# The URL of the Ethereum JSON-RPC endpoint. HTTP(S), WebSocket and IPC
+# (ipc://<path>) endpoints are supported.
+ETHEREUM_RPC_URL={{.EthereumRPCURL}}
+
+# The maximum number of Ethereum JSON-RPC requests Mesh sends per UTC day.
+# Lower it if your Ethereum RPC provider has a stricter limit.
+ETHEREUM_RPC_MAX_REQUESTS_PER_24_HR_UTC=200000
+
+# How often to check for new blocks. Blocks are mined about every
+# {{.Chain.BlockTime}} on {{.Chain.Name}}. Polling more often uses more of the
+# ETHEREUM_RPC_MAX_REQUESTS_PER_24_HR_UTC budget.
+BLOCK_POLLING_INTERVAL={{.Chain.BlockPollingInterval}}
+
+# The directory in which the database and the private key are stored.
+DATA_DIR={{.DataDir}}
+
+# Whether to find peers via the public bootstrap nodes. Set it to false to
+# disable order sharing with the rest of the network.
+USE_BOOTSTRAP_LIST={{.Chain.UseBootstrapList}}
+
+# The ports used for peer-to-peer connections over TCP and WebSockets. They
+# need to be reachable from the internet for other peers to connect to you.
+P2P_TCP_PORT=60558
+P2P_WEBSOCKETS_PORT=60559
+
+# The addresses of the JSON-RPC API over WebSockets and HTTP. The API allows
+# anyone who can reach it to add orders and change the node's settings, so it
+# should not be exposed publicly.
+WS_RPC_ADDR=localhost:60557
+HTTP_RPC_ADDR=localhost:60556
+
+# The logging verbosity: 0=panic, 1=fatal, 2=error, 3=warn, 4=info, 5=debug,
+# 6=trace.
+VERBOSITY=4
+`))
+
+// configFileParams are the parameters of configFileTemplate.
+type configFileParams struct {
+	GeneratedAt    string
+	Path           string
+	ChainID        int
+	Chain          chainDefaults
+	EthereumRPCURL string
+	DataDir        string
+}
+
+// runInit sets up a new node: it generates the private key of the node (unless
+// there already is one), checks that the Ethereum RPC endpoint is reachable
+// and serves the chosen chain, and writes a config file with defaults for the
+// chain.
+func runInit(args []string) error {
+	flags := flag.NewFlagSet("init", flag.ExitOnError)
+	chainID := flags.Int("chain-id", 1, "ID of the Ethereum chain to use")
+	ethereumRPCURL := flags.String("ethereum-rpc-url", "", "URL of the Ethereum JSON-RPC endpoint (required)")
+	dataDir := flags.String("data-dir", "0x_mesh", "directory in which the database and the private key are stored")
+	configPath := flags.String("config", "", "path of the config file to write (default: <data-dir>/mesh.env)")
+	force := flags.Bool("force", false, "overwrite an existing config file")
+	skipCheck := flags.Bool("skip-check", false, "don't check connectivity to the Ethereum RPC endpoint")
+	_ = flags.Parse(args)
+
+	if *ethereumRPCURL == "" {
+		return usageError{message: "init requires -ethereum-rpc-url"}
+	}
+	chain, found := defaultsByChainID[*chainID]
+	if !found {
+		return fmt.Errorf("unsupported chain ID: %d", *chainID)
+	}
+	if *configPath == "" {
+		*configPath = filepath.Join(*dataDir, "mesh.env")
+	}
+	if _, err := os.Stat(*configPath); err == nil && !*force {
+		return fmt.Errorf("config file %s already exists (use -force to overwrite it)", *configPath)
+	}
+
+	if !*skipCheck {
+		fmt.Printf("checking connectivity to %s...\n", *ethereumRPCURL)
+		latestBlockNumber, err := checkEthereumRPC(*ethereumRPCURL, *chainID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("connected to %s (latest block: %d)\n", chain.Name, latestBlockNumber)
+	}
+
+	privKey, created, err := loadOrGeneratePrivateKey(filepath.Join(*dataDir, "keys", "privkey"))
+	if err != nil {
+		return err
+	}
+	peerID, err := peer.IDFromPrivateKey(privKey)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Printf("generated private key for peer ID %s\n", peerID.Pretty())
+	} else {
+		fmt.Printf("using existing private key for peer ID %s\n", peerID.Pretty())
+	}
+
+	if err := writeConfigFile(*configPath, configFileParams{
+		GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
+		Path:           *configPath,
+		ChainID:        *chainID,
+		Chain:          chain,
+		EthereumRPCURL: *ethereumRPCURL,
+		DataDir:        *dataDir,
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("wrote config file %s\n\nstart the node with: mesh start -config %s\n", *configPath, *configPath)
+	return nil
+}
+
+// checkEthereumRPC checks that the Ethereum RPC endpoint at the given URL is
+// reachable and serves the given chain. It returns the number of the latest
+// block.
+func checkEthereumRPC(ethereumRPCURL string, chainID int) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), initConnectivityCheckTimeout)
+	defer cancel()
+	rpcClient, _, err := ethrpcclient.Dial(ctx, ethereumRPCURL)
+	if err != nil {
+		return 0, fmt.Errorf("could not connect to the Ethereum RPC endpoint: %s", err.Error())
+	}
+	defer rpcClient.Close()
+
+	var chainIDRaw string
+	if err := rpcClient.CallContext(ctx, &chainIDRaw, "eth_chainId"); err != nil {
+		return 0, fmt.Errorf("could not get the chain ID from the Ethereum RPC endpoint: %s", err.Error())
+	}
+	rpcChainID, ok := math.ParseBig256(chainIDRaw)
+	if !ok {
+		return 0, fmt.Errorf("could not parse the chain ID returned by the Ethereum RPC endpoint: %q", chainIDRaw)
+	}
+	if rpcChainID.Int64() != int64(chainID) {
+		return 0, fmt.Errorf("the Ethereum RPC endpoint serves chain ID %d instead of %d", rpcChainID.Int64(), chainID)
+	}
+
+	var latestBlockNumber hexutil.Uint64
+	if err := rpcClient.CallContext(ctx, &latestBlockNumber, "eth_blockNumber"); err != nil {
+		return 0, fmt.Errorf("could not get the latest block from the Ethereum RPC endpoint: %s", err.Error())
+	}
+	return uint64(latestBlockNumber), nil
+}
+
+// loadOrGeneratePrivateKey loads the private key at the given path or
+// generates a new one if it doesn't exist. It returns true if the key was
+// generated.
+func loadOrGeneratePrivateKey(path string) (p2pcrypto.PrivKey, bool, error) {
+	privKey, err := keys.GetPrivateKeyFromPath(path)
+	if err == nil {
+		return privKey, false, nil
+	} else if !os.IsNotExist(err) {
+		return nil, false, err
+	}
+	privKey, err = keys.GenerateAndSavePrivateKey(path)
+	if err != nil {
+		return nil, false, err
+	}
+	return privKey, true, nil
+}
+
+// writeConfigFile writes the config file. It is only readable by the current
+// user since the Ethereum RPC URL often contains an API key.
+func writeConfigFile(path string, params configFileParams) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return configFileTemplate.Execute(file, params)
+}
+
+// loadConfigFile sets the environment variables defined in a config file
+// written by "mesh init". Variables which are already set in the environment
+// take precedence over the config file.
+func loadConfigFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNumber)
+		}
+		if _, isSet := os.LookupEnv(key); isSet {
+			continue
+		}
+		if err := os.Setenv(key, strings.TrimSpace(parts[1])); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
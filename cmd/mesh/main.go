@@ -2,13 +2,16 @@
 
 // package mesh is a standalone 0x Mesh node that can be run from the command
 // line. It uses environment variables for configuration and exposes a JSON RPC
-// endpoint over WebSockets.
+// endpoint over WebSockets. It also includes subcommands for managing a node
+// (see commands.go).
 package main
 
 import (
 	"context"
+	"flag"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/0xProject/0x-mesh/core"
 	"github.com/0xProject/0x-mesh/rpc"
@@ -22,21 +25,67 @@ type standaloneConfig struct {
 	// WSRPCAddr is the interface and port to use for the JSON-RPC API over
 	// WebSockets. By default, 0x Mesh will listen on localhost and port 60557.
 	WSRPCAddr string `envvar:"WS_RPC_ADDR" default:"localhost:60557"`
+	// WSPingInterval is the interval at which the WebSocket RPC server sends
+	// ping frames to clients. This keeps long-lived connections alive behind
+	// load balancers and proxies which drop idle connections. If 0, no pings
+	// are sent.
+	WSPingInterval time.Duration `envvar:"WS_PING_INTERVAL" default:"30s"`
+	// WSIdleTimeout is the amount of time after which the WebSocket RPC server
+	// closes connections on which nothing (including responses to pings) was
+	// received. It must be greater than WSPingInterval. If 0, idle connections
+	// are never closed.
+	WSIdleTimeout time.Duration `envvar:"WS_IDLE_TIMEOUT" default:"90s"`
 	// HTTPRPCAddr is the interface and port to use for the JSON-RPC API over
-	// HTTP. By default, 0x Mesh will listen on localhost and port 60556.
+	// HTTP. By default, 0x Mesh will listen on localhost and port 60556. The
+	// HTTP server also exposes a readiness check under /readyz, streams order
+	// exports under /orders/export and exposes Prometheus metrics under
+	// /metrics.
 	HTTPRPCAddr string `envvar:"HTTP_RPC_ADDR" default:"localhost:60556"`
+	// DiagnosticsAddr is the interface and port to use for the diagnostics HTTP
+	// server, which exposes net/http/pprof under /debug/pprof/, expvar under
+	// /debug/vars and a trigger for writing goroutine and heap dumps to the
+	// data directory via POST /debug/dump?type=goroutine|heap. The diagnostics
+	// server is disabled by default and should never be exposed publicly.
+	DiagnosticsAddr string `envvar:"DIAGNOSTICS_ADDR" default:""`
 }
 
-func main() {
+// runStart starts a 0x Mesh node and blocks until it exits.
+func runStart(args []string) {
+	flags := flag.NewFlagSet("start", flag.ExitOnError)
+	configPath := flags.String("config", "", "path of a config file generated by \"mesh init\" (environment variables take precedence)")
+	forceChainSwitch := flags.Bool("force-chain-switch", false, "archive the orders in the database if it was created for a different chain than ETHEREUM_CHAIN_ID (same as FORCE_CHAIN_SWITCH=true)")
+	migrateDryRun := flags.Bool("migrate-dry-run", false, "print the database migrations which would be applied on startup and exit without starting the node")
+	_ = flags.Parse(args)
+	if *configPath != "" {
+		if err := loadConfigFile(*configPath); err != nil {
+			log.WithField("error", err.Error()).Fatal("could not load config file")
+		}
+	}
+
 	// Parse env vars
 	var coreConfig core.Config
 	if err := envvar.Parse(&coreConfig); err != nil {
 		log.WithField("error", err.Error()).Fatal("could not parse environment variables")
 	}
+	if *forceChainSwitch {
+		coreConfig.ForceChainSwitch = true
+	}
 	var config standaloneConfig
 	if err := envvar.Parse(&config); err != nil {
 		log.WithField("error", err.Error()).Fatal("could not parse environment variables")
 	}
+	if config.WSPingInterval < 0 || config.WSIdleTimeout < 0 {
+		log.Fatal("WS_PING_INTERVAL and WS_IDLE_TIMEOUT cannot be negative")
+	}
+	if config.WSPingInterval > 0 && config.WSIdleTimeout > 0 && config.WSIdleTimeout <= config.WSPingInterval {
+		log.Fatal("WS_IDLE_TIMEOUT must be greater than WS_PING_INTERVAL")
+	}
+	if *migrateDryRun {
+		if err := printMigrationPlan(coreConfig); err != nil {
+			log.WithField("error", err.Error()).Fatal("could not determine database migrations")
+		}
+		return
+	}
 
 	// Start core.App.
 	app, err := core.New(coreConfig)
@@ -67,6 +116,7 @@ func main() {
 		defer wg.Done()
 		log.WithField("ws_rpc_addr", config.WSRPCAddr).Info("starting WS RPC server")
 		rpcServer := instantiateServer(ctx, app, config.WSRPCAddr)
+		rpcServer.SetWebsocketKeepalive(config.WSPingInterval, config.WSIdleTimeout)
 		go func() {
 			selectedRPCAddr, err := waitForSelectedAddress(ctx, rpcServer)
 			if err != nil {
@@ -86,6 +136,9 @@ func main() {
 		defer wg.Done()
 		log.WithField("http_rpc_addr", config.HTTPRPCAddr).Info("starting HTTP RPC server")
 		rpcServer := instantiateServer(ctx, app, config.HTTPRPCAddr)
+		rpcServer.Handle("/readyz", newReadyzHandler(app))
+		rpcServer.Handle("/orders/export", newExportOrdersHandler(app))
+		rpcServer.Handle("/metrics", newMetricsHandler(app))
 		go func() {
 			selectedRPCAddr, err := waitForSelectedAddress(ctx, rpcServer)
 			if err != nil {
@@ -98,6 +151,19 @@ func main() {
 		}
 	}()
 
+	// Start diagnostics server if enabled.
+	diagnosticsErrChan := make(chan error, 1)
+	if config.DiagnosticsAddr != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.WithField("diagnostics_addr", config.DiagnosticsAddr).Info("starting diagnostics server")
+			if err := startDiagnosticsServer(ctx, config.DiagnosticsAddr, coreConfig.DataDir); err != nil {
+				diagnosticsErrChan <- err
+			}
+		}()
+	}
+
 	// Block until there is an error or the app is closed.
 	select {
 	case <-ctx.Done():
@@ -114,6 +180,9 @@ func main() {
 	case err := <-httpRPCErrChan:
 		cancel()
 		log.WithField("error", err.Error()).Error("HTTP RPC server returned error")
+	case err := <-diagnosticsErrChan:
+		cancel()
+		log.WithField("error", err.Error()).Error("diagnostics server returned error")
 	}
 
 	// If we reached here it means there was an error. Wait for all goroutines
@@ -0,0 +1,26 @@
+// +build !js
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/0xProject/0x-mesh/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// newMetricsHandler returns an HTTP handler which responds with the metrics of
+// the app in the Prometheus text exposition format, so that the /metrics
+// endpoint can be scraped by Prometheus.
+func newMetricsHandler(app *core.App) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed (use GET)", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := app.WritePrometheusMetrics(w); err != nil {
+			log.WithError(err).Debug("could not write /metrics response")
+		}
+	})
+}
@@ -0,0 +1,104 @@
+// +build !js
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/core"
+	"github.com/0xProject/0x-mesh/orderexport"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	log "github.com/sirupsen/logrus"
+)
+
+// newExportOrdersHandler returns an HTTP handler which streams all orders
+// matching the options given in the query string. It accepts the same options
+// as mesh_exportOrders: format, gzip, status, makerAddress, makerAssetData and
+// takerAssetData. Unlike mesh_exportOrders, the orders are not written to a
+// file on the node.
+func newExportOrdersHandler(app *core.App) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed (use GET)", http.StatusMethodNotAllowed)
+			return
+		}
+		opts, err := parseExportOrdersQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		format, err := orderexport.ParseFormat(opts.Format)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.WithField("opts", opts).Info("received order export request via HTTP")
+
+		switch {
+		case opts.Gzip:
+			w.Header().Set("Content-Type", "application/gzip")
+		case format == orderexport.FormatCSV:
+			w.Header().Set("Content-Type", "text/csv")
+		default:
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "orders"+orderexport.FileExtension(format, opts.Gzip)))
+		numOrders, err := app.ExportOrders(w, opts)
+		if err != nil {
+			if _, ok := err.(core.ErrInvalidExportOrdersOpts); ok {
+				// Nothing has been written yet if the options are invalid.
+				w.Header().Del("Content-Disposition")
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			// The status code has already been sent, so the only way to
+			// signal the error is to cut the export short.
+			log.WithError(err).Error("could not export orders via HTTP")
+			return
+		}
+		log.WithField("numOrders", numOrders).Info("exported orders via HTTP")
+	})
+}
+
+// parseExportOrdersQuery parses the options of an order export from the query
+// string of r.
+func parseExportOrdersQuery(r *http.Request) (types.ExportOrdersOpts, error) {
+	query := r.URL.Query()
+	opts := types.ExportOrdersOpts{
+		Format: query.Get("format"),
+		Status: query.Get("status"),
+	}
+	if gzipParam := query.Get("gzip"); gzipParam != "" {
+		compress, err := strconv.ParseBool(gzipParam)
+		if err != nil {
+			return opts, fmt.Errorf("invalid gzip parameter: %q", gzipParam)
+		}
+		opts.Gzip = compress
+	}
+	if makerAddressParam := query.Get("makerAddress"); makerAddressParam != "" {
+		if !common.IsHexAddress(makerAddressParam) {
+			return opts, fmt.Errorf("invalid makerAddress parameter: %q", makerAddressParam)
+		}
+		makerAddress := common.HexToAddress(makerAddressParam)
+		opts.MakerAddress = &makerAddress
+	}
+	if makerAssetDataParam := query.Get("makerAssetData"); makerAssetDataParam != "" {
+		makerAssetData, err := hexutil.Decode(makerAssetDataParam)
+		if err != nil {
+			return opts, fmt.Errorf("invalid makerAssetData parameter: %q", makerAssetDataParam)
+		}
+		opts.MakerAssetData = makerAssetData
+	}
+	if takerAssetDataParam := query.Get("takerAssetData"); takerAssetDataParam != "" {
+		takerAssetData, err := hexutil.Decode(takerAssetDataParam)
+		if err != nil {
+			return opts, fmt.Errorf("invalid takerAssetData parameter: %q", takerAssetDataParam)
+		}
+		opts.TakerAssetData = takerAssetData
+	}
+	return opts, nil
+}
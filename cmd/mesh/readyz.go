@@ -0,0 +1,42 @@
+// +build !js
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// readyzResponse is the body of a response from the /readyz endpoint.
+type readyzResponse struct {
+	Ready               bool                           `json:"ready"`
+	StartupRevalidation types.StartupRevalidationStats `json:"startupRevalidation"`
+}
+
+// newReadyzHandler returns an HTTP handler which responds with status code 200
+// if the app is ready and 503 otherwise. The body of the response contains the
+// progress of the re-validation of stored orders on startup, which can take a
+// while for nodes with many orders.
+func newReadyzHandler(app *core.App) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed (use GET)", http.StatusMethodNotAllowed)
+			return
+		}
+		response := readyzResponse{
+			Ready:               app.IsReady(),
+			StartupRevalidation: app.GetStartupRevalidationStats(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !response.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.WithError(err).Debug("could not write /readyz response")
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"net"
 	"runtime/debug"
 	"strings"
@@ -15,10 +16,15 @@ import (
 	"github.com/0xProject/0x-mesh/common/types"
 	"github.com/0xProject/0x-mesh/constants"
 	"github.com/0xProject/0x-mesh/core"
+	"github.com/0xProject/0x-mesh/ethereum/blockwatch"
+	"github.com/0xProject/0x-mesh/p2p"
+	"github.com/0xProject/0x-mesh/p2p/banner"
 	"github.com/0xProject/0x-mesh/rpc"
 	"github.com/0xProject/0x-mesh/zeroex"
 	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/ethereum/go-ethereum/common"
 	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/libp2p/go-libp2p-core/peer"
 	peerstore "github.com/libp2p/go-libp2p-peerstore"
 	log "github.com/sirupsen/logrus"
 )
@@ -27,6 +33,20 @@ import (
 // the buffer is full, any additional events won't be processed.
 const orderEventsBufferSize = 8000
 
+// orderSubscriptionHeartbeatInterval is the maximum amount of time between two
+// notifications sent to resumable order event subscriptions. If no order
+// events were emitted in the meantime, a notification without order events is
+// sent, so that clients can detect half-open connections.
+const orderSubscriptionHeartbeatInterval = 30 * time.Second
+
+// blockEventsBufferSize is the buffer size for the blockEvents channel. If
+// the buffer is full, any additional events won't be processed.
+const blockEventsBufferSize = 1000
+
+// contractEventsBufferSize is the buffer size for the contractEvents channel.
+// If the buffer is full, any additional events won't be processed.
+const contractEventsBufferSize = 1000
+
 type rpcHandler struct {
 	app *core.App
 	ctx context.Context
@@ -97,11 +117,100 @@ func (handler *rpcHandler) GetOrders(page, perPage int, snapshotID string) (resu
 	return getOrdersResponse, nil
 }
 
+// GetOrderDiff is called when an RPC client calls GetOrderDiff.
+func (handler *rpcHandler) GetOrderDiff(sinceSnapshotID string) (result *types.GetOrderDiffResponse, err error) {
+	log.WithField("sinceSnapshotID", sinceSnapshotID).Debug("received GetOrderDiff request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "GetOrderDiff",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in GetOrderDiff RPC call (check logs for stack trace)")
+		}
+	}()
+	response, err := handler.app.GetOrderDiff(sinceSnapshotID)
+	if err != nil {
+		if _, ok := err.(core.ErrSnapshotNotFound); ok {
+			return nil, err
+		}
+		log.WithField("error", err.Error()).Error("internal error in GetOrderDiff RPC call")
+		return nil, constants.ErrInternal
+	}
+	return response, nil
+}
+
+// GetOrderByHash is called when an RPC client calls GetOrderByHash.
+func (handler *rpcHandler) GetOrderByHash(orderHash common.Hash) (result *types.GetOrderByHashResponse, err error) {
+	log.WithField("orderHash", orderHash.Hex()).Debug("received GetOrderByHash request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "GetOrderByHash",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in GetOrderByHash RPC call (check logs for stack trace)")
+		}
+	}()
+	response, err := handler.app.GetOrderByHash(orderHash)
+	if err != nil {
+		log.WithField("error", err.Error()).Error("internal error in GetOrderByHash RPC call")
+		return nil, constants.ErrInternal
+	}
+	return response, nil
+}
+
+// GetOrderCounts is called when an RPC client calls GetOrderCounts.
+func (handler *rpcHandler) GetOrderCounts(groupBy string) (result *types.GetOrderCountsResponse, err error) {
+	log.WithField("groupBy", groupBy).Debug("received GetOrderCounts request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "GetOrderCounts",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in GetOrderCounts RPC call (check logs for stack trace)")
+		}
+	}()
+	response, err := handler.app.GetOrderCounts(groupBy)
+	if err != nil {
+		if _, ok := err.(core.ErrInvalidOrderCountsGroupBy); ok {
+			return nil, err
+		}
+		log.WithField("error", err.Error()).Error("internal error in GetOrderCounts RPC call")
+		return nil, constants.ErrInternal
+	}
+	return response, nil
+}
+
 // AddOrders is called when an RPC client calls AddOrders.
 func (handler *rpcHandler) AddOrders(signedOrdersRaw []*json.RawMessage, opts types.AddOrdersOpts) (results *ordervalidator.ValidationResults, err error) {
 	log.WithFields(log.Fields{
-		"count":  len(signedOrdersRaw),
-		"pinned": opts.Pinned,
+		"count":           len(signedOrdersRaw),
+		"pinned":          opts.Pinned,
+		"numReplacements": len(opts.ReplacesOrderHashes),
+		"numMetadata":     len(opts.Metadata),
 	}).Info("received AddOrders request via RPC")
 	// Catch panics, log stack trace and return RPC error message
 	defer func() {
@@ -119,8 +228,11 @@ func (handler *rpcHandler) AddOrders(signedOrdersRaw []*json.RawMessage, opts ty
 			err = errors.New("method handler crashed in AddOrders RPC call (check logs for stack trace)")
 		}
 	}()
-	validationResults, err := handler.app.AddOrders(handler.ctx, signedOrdersRaw, opts.Pinned)
+	validationResults, err := handler.app.AddOrders(handler.ctx, signedOrdersRaw, opts)
 	if err != nil {
+		if _, ok := err.(core.ErrInvalidAddOrdersOpts); ok {
+			return nil, err
+		}
 		// We don't want to leak internal error details to the RPC client.
 		log.WithField("error", err.Error()).Error("internal error in AddOrders RPC call")
 		return nil, constants.ErrInternal
@@ -128,6 +240,118 @@ func (handler *rpcHandler) AddOrders(signedOrdersRaw []*json.RawMessage, opts ty
 	return validationResults, nil
 }
 
+// ValidateOrders is called when an RPC client calls ValidateOrders.
+func (handler *rpcHandler) ValidateOrders(signedOrdersRaw []*json.RawMessage, opts types.ValidateOrdersOpts) (results *ordervalidator.ValidationResults, err error) {
+	log.WithFields(log.Fields{
+		"count":             len(signedOrdersRaw),
+		"pending":           opts.Pending,
+		"numStateOverrides": len(opts.StateOverrides),
+	}).Debug("received ValidateOrders request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "ValidateOrders",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in ValidateOrders RPC call (check logs for stack trace)")
+		}
+	}()
+	validationResults, err := handler.app.ValidateOrders(handler.ctx, signedOrdersRaw, opts)
+	if err != nil {
+		if err == ordervalidator.ErrStateOptionsNotSupported || err == core.ErrRelayOnly {
+			return nil, err
+		}
+		// We don't want to leak internal error details to the RPC client.
+		log.WithField("error", err.Error()).Error("internal error in ValidateOrders RPC call")
+		return nil, constants.ErrInternal
+	}
+	return validationResults, nil
+}
+
+// SimulateFill is called when an RPC client calls SimulateFill.
+func (handler *rpcHandler) SimulateFill(orderHash common.Hash, opts types.SimulateFillOpts) (result *types.SimulateFillResult, err error) {
+	log.WithFields(log.Fields{
+		"orderHash":            orderHash.Hex(),
+		"takerAddress":         opts.TakerAddress.Hex(),
+		"takerAssetFillAmount": opts.TakerAssetFillAmount,
+	}).Debug("received SimulateFill request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "SimulateFill",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in SimulateFill RPC call (check logs for stack trace)")
+		}
+	}()
+	result, err = handler.app.SimulateFill(handler.ctx, orderHash, opts)
+	if err != nil {
+		if _, ok := err.(core.ErrOrderNotFound); ok {
+			return nil, err
+		}
+		if _, ok := err.(core.ErrInvalidSimulateFillOpts); ok {
+			return nil, err
+		}
+		if err == ordervalidator.ErrSimulationNotSupported || err == core.ErrRelayOnly {
+			return nil, err
+		}
+		// We don't want to leak internal error details to the RPC client.
+		log.WithField("error", err.Error()).Error("internal error in SimulateFill RPC call")
+		return nil, constants.ErrInternal
+	}
+	return result, nil
+}
+
+// GetPropagationReceipts is called when an RPC client calls
+// GetPropagationReceipts.
+func (handler *rpcHandler) GetPropagationReceipts(orderHashes []common.Hash, opts types.GetPropagationReceiptsOpts) (result *types.PropagationReceipts, err error) {
+	log.WithFields(log.Fields{
+		"numOrderHashes": len(orderHashes),
+		"timeout":        opts.Timeout,
+		"maxPeers":       opts.MaxPeers,
+	}).Debug("received GetPropagationReceipts request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "GetPropagationReceipts",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in GetPropagationReceipts RPC call (check logs for stack trace)")
+		}
+	}()
+	result, err = handler.app.GetPropagationReceipts(handler.ctx, orderHashes, opts)
+	if err != nil {
+		if _, ok := err.(core.ErrInvalidPropagationReceiptsOpts); ok {
+			return nil, err
+		}
+		// We don't want to leak internal error details to the RPC client.
+		log.WithField("error", err.Error()).Error("internal error in GetPropagationReceipts RPC call")
+		return nil, constants.ErrInternal
+	}
+	return result, nil
+}
+
 // AddPeer is called when an RPC client calls AddPeer,
 func (handler *rpcHandler) AddPeer(peerInfo peerstore.PeerInfo) (err error) {
 	log.Debug("received AddPeer request via RPC")
@@ -181,9 +405,9 @@ func (handler *rpcHandler) GetStats() (result *types.Stats, err error) {
 	return getStatsResponse, nil
 }
 
-// SubscribeToOrders is called when an RPC client sends a `mesh_subscribe` request with the `orders` topic parameter
-func (handler *rpcHandler) SubscribeToOrders(ctx context.Context) (result *ethrpc.Subscription, err error) {
-	log.Debug("received order event subscription request via RPC")
+// GetRuntimeStats is called when an RPC client calls GetRuntimeStats.
+func (handler *rpcHandler) GetRuntimeStats() (result *types.RuntimeStats, err error) {
+	log.Debug("received GetRuntimeStats request via RPC")
 	// Catch panics, log stack trace and return RPC error message
 	defer func() {
 		if r := recover(); r != nil {
@@ -194,79 +418,901 @@ func (handler *rpcHandler) SubscribeToOrders(ctx context.Context) (result *ethrp
 			}
 			log.WithFields(log.Fields{
 				"error":      internalErr,
-				"method":     "SubscribeToOrders",
+				"method":     "GetRuntimeStats",
 				"stackTrace": string(debug.Stack()),
 			}).Error("RPC method handler crashed")
-			err = errors.New("method handler crashed in SubscribeToOrders RPC call (check logs for stack trace)")
+			err = errors.New("method handler crashed in GetRuntimeStats RPC call (check logs for stack trace)")
 		}
 	}()
-	subscription, err := SetupOrderStream(ctx, handler.app)
-	if err != nil {
-		log.WithField("error", err.Error()).Error("internal error in `mesh_subscribe` to `orders` RPC call")
-		return nil, constants.ErrInternal
-	}
-	return subscription, nil
+	return handler.app.GetRuntimeStats(), nil
 }
 
-// SetupOrderStream sets up the order stream for a subscription
-func SetupOrderStream(ctx context.Context, app *core.App) (*ethrpc.Subscription, error) {
-	notifier, supported := ethrpc.NotifierFromContext(ctx)
-	if !supported {
-		return &ethrpc.Subscription{}, ethrpc.ErrNotificationsUnsupported
-	}
+// GetRecentRejections is called when an RPC client calls GetRecentRejections.
+func (handler *rpcHandler) GetRecentRejections(opts types.GetRecentRejectionsOpts) (result *types.RecentRejections, err error) {
+	log.Debug("received GetRecentRejections request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "GetRecentRejections",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in GetRecentRejections RPC call (check logs for stack trace)")
+		}
+	}()
+	return handler.app.GetRecentRejections(opts), nil
+}
 
-	rpcSub := notifier.CreateSubscription()
+// GetPeers is called when an RPC client calls GetPeers.
+func (handler *rpcHandler) GetPeers() (result []*types.PeerInfo, err error) {
+	log.Debug("received GetPeers request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "GetPeers",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in GetPeers RPC call (check logs for stack trace)")
+		}
+	}()
+	return handler.app.GetPeers(), nil
+}
 
-	go func() {
-		orderEventsChan := make(chan []*zeroex.OrderEvent, orderEventsBufferSize)
-		orderWatcherSub := app.SubscribeToOrderEvents(orderEventsChan)
-		defer orderWatcherSub.Unsubscribe()
+// BanPeer is called when an RPC client calls BanPeer.
+func (handler *rpcHandler) BanPeer(peerID peer.ID) (err error) {
+	log.Info("received BanPeer request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "BanPeer",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in BanPeer RPC call (check logs for stack trace)")
+		}
+	}()
+	if err := handler.app.BanPeer(peerID); err != nil {
+		if err == p2p.ErrUnknownPeer || err == banner.ErrProtectedIP {
+			return err
+		}
+		log.WithField("error", err.Error()).Error("internal error in BanPeer RPC call")
+		return constants.ErrInternal
+	}
+	return nil
+}
 
-		for {
-			select {
-			case orderEvents := <-orderEventsChan:
-				err := notifier.Notify(rpcSub.ID, orderEvents)
-				if err != nil {
-					// TODO(fabio): The current implementation of `notifier.Notify` returns a
-					// `write: broken pipe` error when it is called _after_ the client has
-					// disconnected but before the corresponding error is received on the
-					// `rpcSub.Err()` channel. This race-condition is not problematic beyond
-					// the unnecessary computation and log spam resulting from it. Once this is
-					// fixed upstream, give all logs an `Error` severity.
-					logEntry := log.WithFields(map[string]interface{}{
-						"error":            err.Error(),
-						"subscriptionType": "orders",
-						"orderEvents":      len(orderEvents),
-					})
-					message := "error while calling notifier.Notify"
-					// If the network connection disconnects for longer then ~2mins and then comes
-					// back up, we've noticed the call to `notifier.Notify` return `i/o timeout`
-					// `net.OpError` errors everytime it's called and no values are sent over
-					// `rpcSub.Err()` nor `notifier.Closed()`. In order to stop the error from
-					// endlessly re-occuring, we unsubscribe and return for encountering this type of
-					// error.
-					if _, ok := err.(*net.OpError); ok {
-						logEntry.Trace(message)
-						return
-					}
-					if strings.Contains(err.Error(), "write: broken pipe") {
-						logEntry.Trace(message)
-					} else {
-						logEntry.Error(message)
-					}
-				}
-			case err := <-rpcSub.Err():
-				if err != nil {
-					log.WithField("err", err).Error("rpcSub returned an error")
-				} else {
-					log.Debug("rpcSub was closed without error")
-				}
-				return
-			case <-notifier.Closed():
-				return
+// GetMakerLists is called when an RPC client calls GetMakerLists.
+func (handler *rpcHandler) GetMakerLists() (result *types.MakerLists, err error) {
+	log.Debug("received GetMakerLists request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
 			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "GetMakerLists",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in GetMakerLists RPC call (check logs for stack trace)")
 		}
 	}()
+	return handler.app.GetMakerLists(), nil
+}
 
-	return rpcSub, nil
+// SetMakerAllowlist is called when an RPC client calls SetMakerAllowlist.
+func (handler *rpcHandler) SetMakerAllowlist(addresses []common.Address) (result *types.SetMakerListResponse, err error) {
+	log.WithField("addresses", addresses).Info("received SetMakerAllowlist request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "SetMakerAllowlist",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in SetMakerAllowlist RPC call (check logs for stack trace)")
+		}
+	}()
+	response, err := handler.app.SetMakerAllowlist(addresses)
+	if err != nil {
+		log.WithField("error", err.Error()).Error("internal error in SetMakerAllowlist RPC call")
+		return nil, constants.ErrInternal
+	}
+	return response, nil
+}
+
+// SetMakerAllowlistNames is called when an RPC client calls SetMakerAllowlistNames.
+func (handler *rpcHandler) SetMakerAllowlistNames(addressesOrNames []string) (result *types.SetMakerListResponse, err error) {
+	log.WithField("addresses", addressesOrNames).Info("received SetMakerAllowlistNames request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "SetMakerAllowlistNames",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in SetMakerAllowlistNames RPC call (check logs for stack trace)")
+		}
+	}()
+	response, err := handler.app.SetMakerAllowlistNames(addressesOrNames)
+	if err != nil {
+		if _, ok := err.(core.ErrInvalidAddressList); ok {
+			return nil, err
+		}
+		if err == core.ErrRelayOnly {
+			return nil, err
+		}
+		log.WithField("error", err.Error()).Error("internal error in SetMakerAllowlistNames RPC call")
+		return nil, constants.ErrInternal
+	}
+	return response, nil
+}
+
+// SetMakerDenylist is called when an RPC client calls SetMakerDenylist.
+func (handler *rpcHandler) SetMakerDenylist(addresses []common.Address) (result *types.SetMakerListResponse, err error) {
+	log.WithField("addresses", addresses).Info("received SetMakerDenylist request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "SetMakerDenylist",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in SetMakerDenylist RPC call (check logs for stack trace)")
+		}
+	}()
+	response, err := handler.app.SetMakerDenylist(addresses)
+	if err != nil {
+		log.WithField("error", err.Error()).Error("internal error in SetMakerDenylist RPC call")
+		return nil, constants.ErrInternal
+	}
+	return response, nil
+}
+
+// SetMakerDenylistNames is called when an RPC client calls SetMakerDenylistNames.
+func (handler *rpcHandler) SetMakerDenylistNames(addressesOrNames []string) (result *types.SetMakerListResponse, err error) {
+	log.WithField("addresses", addressesOrNames).Info("received SetMakerDenylistNames request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "SetMakerDenylistNames",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in SetMakerDenylistNames RPC call (check logs for stack trace)")
+		}
+	}()
+	response, err := handler.app.SetMakerDenylistNames(addressesOrNames)
+	if err != nil {
+		if _, ok := err.(core.ErrInvalidAddressList); ok {
+			return nil, err
+		}
+		if err == core.ErrRelayOnly {
+			return nil, err
+		}
+		log.WithField("error", err.Error()).Error("internal error in SetMakerDenylistNames RPC call")
+		return nil, constants.ErrInternal
+	}
+	return response, nil
+}
+
+// GetAssetDenylist is called when an RPC client calls GetAssetDenylist.
+func (handler *rpcHandler) GetAssetDenylist() (result []common.Address, err error) {
+	log.Debug("received GetAssetDenylist request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "GetAssetDenylist",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in GetAssetDenylist RPC call (check logs for stack trace)")
+		}
+	}()
+	return handler.app.GetAssetDenylist(), nil
+}
+
+// SetAssetDenylist is called when an RPC client calls SetAssetDenylist.
+func (handler *rpcHandler) SetAssetDenylist(addresses []common.Address) (result *types.SetAssetDenylistResponse, err error) {
+	log.WithField("addresses", addresses).Info("received SetAssetDenylist request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "SetAssetDenylist",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in SetAssetDenylist RPC call (check logs for stack trace)")
+		}
+	}()
+	response, err := handler.app.SetAssetDenylist(addresses)
+	if err != nil {
+		log.WithField("error", err.Error()).Error("internal error in SetAssetDenylist RPC call")
+		return nil, constants.ErrInternal
+	}
+	return response, nil
+}
+
+// RecordAudit is called after an RPC client calls a mutating method.
+func (handler *rpcHandler) RecordAudit(record *types.AuditRecord) {
+	if err := handler.app.RecordAudit(record); err != nil {
+		log.WithFields(log.Fields{
+			"error":  err.Error(),
+			"method": record.Method,
+		}).Error("could not record RPC call in audit log")
+	}
+}
+
+// GetAuditLog is called when an RPC client calls GetAuditLog.
+func (handler *rpcHandler) GetAuditLog(since time.Time) (result []*types.AuditRecord, err error) {
+	log.WithField("since", since).Debug("received GetAuditLog request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "GetAuditLog",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in GetAuditLog RPC call (check logs for stack trace)")
+		}
+	}()
+	records, err := handler.app.GetAuditLog(since)
+	if err != nil {
+		if err == core.ErrAuditLogDisabled {
+			return nil, err
+		}
+		log.WithField("error", err.Error()).Error("internal error in GetAuditLog RPC call")
+		return nil, constants.ErrInternal
+	}
+	return records, nil
+}
+
+// GetMetricsHistory is called when an RPC client calls GetMetricsHistory.
+func (handler *rpcHandler) GetMetricsHistory(since time.Time) (result []*types.MetricsSample, err error) {
+	log.WithField("since", since).Debug("received GetMetricsHistory request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "GetMetricsHistory",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in GetMetricsHistory RPC call (check logs for stack trace)")
+		}
+	}()
+	samples, err := handler.app.GetMetricsHistory(since)
+	if err != nil {
+		log.WithField("error", err.Error()).Error("internal error in GetMetricsHistory RPC call")
+		return nil, constants.ErrInternal
+	}
+	return samples, nil
+}
+
+// ExportAuditLog is called when an RPC client calls ExportAuditLog.
+func (handler *rpcHandler) ExportAuditLog() (result *types.ExportAuditLogResponse, err error) {
+	log.Info("received ExportAuditLog request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "ExportAuditLog",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in ExportAuditLog RPC call (check logs for stack trace)")
+		}
+	}()
+	response, err := handler.app.ExportAuditLog()
+	if err != nil {
+		if err == core.ErrAuditLogDisabled {
+			return nil, err
+		}
+		log.WithField("error", err.Error()).Error("internal error in ExportAuditLog RPC call")
+		return nil, constants.ErrInternal
+	}
+	return response, nil
+}
+
+// ExportOrders is called when an RPC client calls ExportOrders.
+func (handler *rpcHandler) ExportOrders(opts types.ExportOrdersOpts) (result *types.ExportOrdersResponse, err error) {
+	log.WithField("opts", opts).Info("received ExportOrders request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "ExportOrders",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in ExportOrders RPC call (check logs for stack trace)")
+		}
+	}()
+	response, err := handler.app.ExportOrdersToFile(opts)
+	if err != nil {
+		if _, ok := err.(core.ErrInvalidExportOrdersOpts); ok {
+			return nil, err
+		}
+		log.WithField("error", err.Error()).Error("internal error in ExportOrders RPC call")
+		return nil, constants.ErrInternal
+	}
+	return response, nil
+}
+
+// BackfillOrderEvents is called when an RPC client calls BackfillOrderEvents.
+func (handler *rpcHandler) BackfillOrderEvents(opts types.BackfillOrderEventsOpts) (result *types.BackfillOrderEventsResponse, err error) {
+	log.WithFields(log.Fields{
+		"numMakers": len(opts.MakerAddresses),
+		"fromBlock": opts.FromBlock,
+		"toBlock":   opts.ToBlock,
+	}).Info("received BackfillOrderEvents request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "BackfillOrderEvents",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in BackfillOrderEvents RPC call (check logs for stack trace)")
+		}
+	}()
+	response, err := handler.app.BackfillOrderEvents(handler.ctx, opts)
+	if err != nil {
+		if _, ok := err.(core.ErrInvalidBackfillOrderEventsOpts); ok {
+			return nil, err
+		}
+		if err == core.ErrRelayOnly {
+			return nil, err
+		}
+		log.WithField("error", err.Error()).Error("internal error in BackfillOrderEvents RPC call")
+		return nil, constants.ErrInternal
+	}
+	return response, nil
+}
+
+// SubscribeToOrders is called when an RPC client sends a `mesh_subscribe` request with the `orders` topic parameter
+func (handler *rpcHandler) SubscribeToOrders(ctx context.Context, opts types.OrderSubscriptionOpts) (result *ethrpc.Subscription, err error) {
+	log.WithFields(log.Fields{
+		"resumable":      opts.Resumable,
+		"hasResumeToken": opts.ResumeToken != "",
+	}).Debug("received order event subscription request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "SubscribeToOrders",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in SubscribeToOrders RPC call (check logs for stack trace)")
+		}
+	}()
+	var subscription *ethrpc.Subscription
+	if opts.Resumable || opts.ResumeToken != "" {
+		subscription, err = SetupResumableOrderStream(ctx, handler.app, opts.ResumeToken)
+	} else {
+		subscription, err = SetupOrderStream(ctx, handler.app)
+	}
+	if err != nil {
+		log.WithField("error", err.Error()).Error("internal error in `mesh_subscribe` to `orders` RPC call")
+		return nil, constants.ErrInternal
+	}
+	return subscription, nil
+}
+
+// SetupOrderStream sets up the order stream for a subscription. Order events
+// are buffered in a core.SubscriptionQueue, so that a slow subscriber does not
+// hold up the order watcher. If order events were dropped because the queue
+// was full, an order event with end state EVENTS_DROPPED is sent first.
+func SetupOrderStream(ctx context.Context, app *core.App) (*ethrpc.Subscription, error) {
+	notifier, supported := ethrpc.NotifierFromContext(ctx)
+	if !supported {
+		return &ethrpc.Subscription{}, ethrpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		queue := app.NewSubscriptionQueue("orders")
+		defer queue.Close()
+		orderEventsChan := make(chan []*zeroex.OrderEvent, orderEventsBufferSize)
+		orderWatcherSub := app.SubscribeToOrderEvents(orderEventsChan)
+		defer orderWatcherSub.Unsubscribe()
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			for {
+				select {
+				case orderEvents := <-orderEventsChan:
+					queue.Push(orderEvents)
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		for {
+			select {
+			case <-queue.Ready():
+				for {
+					item, lastDropped, ok := queue.Pop()
+					if !ok {
+						break
+					}
+					if lastDropped != nil {
+						gapMarker := []*zeroex.OrderEvent{
+							{
+								Timestamp:                time.Now().UTC(),
+								EndState:                 zeroex.ESEventsDropped,
+								FillableTakerAssetAmount: big.NewInt(0),
+							},
+						}
+						if !notifyOrderSubscriber(notifier, rpcSub, gapMarker, 0) {
+							return
+						}
+					}
+					orderEvents := item.([]*zeroex.OrderEvent)
+					if !notifyOrderSubscriber(notifier, rpcSub, orderEvents, len(orderEvents)) {
+						return
+					}
+				}
+			case <-queue.Overflowed():
+				log.WithField("subscriptionType", "orders").Warn("closing slow subscription because its queue is full")
+				return
+			case err := <-rpcSub.Err():
+				if err != nil {
+					log.WithField("err", err).Error("rpcSub returned an error")
+				} else {
+					log.Debug("rpcSub was closed without error")
+				}
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// SetupResumableOrderStream sets up the order stream for a resumable
+// subscription. Each batch of order events is sent along with a resume token.
+// If resumeToken is non-empty, the order events emitted after it are sent
+// first. If no order events are sent for orderSubscriptionHeartbeatInterval, a
+// heartbeat notification without order events is sent. If notifications were
+// dropped because the subscription queue was full, a notification with
+// ResyncRequired set is sent first. Its resume token is the one of the last
+// dropped notification, so that clients can resume from it.
+func SetupResumableOrderStream(ctx context.Context, app *core.App, resumeToken string) (*ethrpc.Subscription, error) {
+	notifier, supported := ethrpc.NotifierFromContext(ctx)
+	if !supported {
+		return &ethrpc.Subscription{}, ethrpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		queue := app.NewSubscriptionQueue("orders")
+		defer queue.Close()
+		notificationsChan := make(chan *types.OrderEventsNotification, orderEventsBufferSize)
+		initialNotifications, journalSub := app.SubscribeToResumableOrderEvents(notificationsChan, resumeToken)
+		defer journalSub.Unsubscribe()
+		for _, notification := range initialNotifications {
+			queue.Push(notification)
+		}
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			for {
+				select {
+				case notification := <-notificationsChan:
+					queue.Push(notification)
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		heartbeatTicker := time.NewTicker(orderSubscriptionHeartbeatInterval)
+		defer heartbeatTicker.Stop()
+		lastResumeToken := ""
+		notifiedSinceLastHeartbeat := false
+
+		// notify returns false if the subscription should be closed.
+		notify := func(notification *types.OrderEventsNotification) bool {
+			lastResumeToken = notification.ResumeToken
+			notifiedSinceLastHeartbeat = true
+			return notifyOrderSubscriber(notifier, rpcSub, notification, len(notification.OrderEvents))
+		}
+
+		for {
+			select {
+			case <-queue.Ready():
+				for {
+					item, lastDropped, ok := queue.Pop()
+					if !ok {
+						break
+					}
+					if lastDropped != nil {
+						gapMarker := &types.OrderEventsNotification{
+							ResumeToken:    lastDropped.(*types.OrderEventsNotification).ResumeToken,
+							ResyncRequired: true,
+							OrderEvents:    []*zeroex.OrderEvent{},
+						}
+						if !notify(gapMarker) {
+							return
+						}
+					}
+					if !notify(item.(*types.OrderEventsNotification)) {
+						return
+					}
+				}
+			case <-queue.Overflowed():
+				log.WithField("subscriptionType", "orders").Warn("closing slow subscription because its queue is full")
+				return
+			case <-heartbeatTicker.C:
+				if notifiedSinceLastHeartbeat {
+					notifiedSinceLastHeartbeat = false
+					continue
+				}
+				heartbeat := &types.OrderEventsNotification{
+					ResumeToken: lastResumeToken,
+					OrderEvents: []*zeroex.OrderEvent{},
+				}
+				if !notify(heartbeat) {
+					return
+				}
+				notifiedSinceLastHeartbeat = false
+			case err := <-rpcSub.Err():
+				if err != nil {
+					log.WithField("err", err).Error("rpcSub returned an error")
+				} else {
+					log.Debug("rpcSub was closed without error")
+				}
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// notifyOrderSubscriber sends data to an order event subscription. It returns
+// false if the subscription should be closed.
+func notifyOrderSubscriber(notifier *ethrpc.Notifier, rpcSub *ethrpc.Subscription, data interface{}, numOrderEvents int) bool {
+	err := notifier.Notify(rpcSub.ID, data)
+	if err == nil {
+		return true
+	}
+	// TODO(fabio): The current implementation of `notifier.Notify` returns a
+	// `write: broken pipe` error when it is called _after_ the client has
+	// disconnected but before the corresponding error is received on the
+	// `rpcSub.Err()` channel. This race-condition is not problematic beyond
+	// the unnecessary computation and log spam resulting from it. Once this is
+	// fixed upstream, give all logs an `Error` severity.
+	logEntry := log.WithFields(map[string]interface{}{
+		"error":            err.Error(),
+		"subscriptionType": "orders",
+		"orderEvents":      numOrderEvents,
+	})
+	message := "error while calling notifier.Notify"
+	// If the network connection disconnects for longer then ~2mins and then comes
+	// back up, we've noticed the call to `notifier.Notify` return `i/o timeout`
+	// `net.OpError` errors everytime it's called and no values are sent over
+	// `rpcSub.Err()` nor `notifier.Closed()`. In order to stop the error from
+	// endlessly re-occuring, we unsubscribe and return for encountering this type of
+	// error.
+	if _, ok := err.(*net.OpError); ok {
+		logEntry.Trace(message)
+		return false
+	}
+	if strings.Contains(err.Error(), "write: broken pipe") {
+		logEntry.Trace(message)
+	} else {
+		logEntry.Error(message)
+	}
+	return true
+}
+
+// SubscribeToBlocks is called when an RPC client sends a `mesh_subscribe` request with the `blocks` topic parameter
+func (handler *rpcHandler) SubscribeToBlocks(ctx context.Context) (result *ethrpc.Subscription, err error) {
+	log.Debug("received block event subscription request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "SubscribeToBlocks",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in SubscribeToBlocks RPC call (check logs for stack trace)")
+		}
+	}()
+	subscription, err := SetupBlockStream(ctx, handler.app)
+	if err != nil {
+		if err == core.ErrRelayOnly {
+			return nil, err
+		}
+		log.WithField("error", err.Error()).Error("internal error in `mesh_subscribe` to `blocks` RPC call")
+		return nil, constants.ErrInternal
+	}
+	return subscription, nil
+}
+
+// SetupBlockStream sets up the block stream for a subscription
+func SetupBlockStream(ctx context.Context, app *core.App) (*ethrpc.Subscription, error) {
+	notifier, supported := ethrpc.NotifierFromContext(ctx)
+	if !supported {
+		return &ethrpc.Subscription{}, ethrpc.ErrNotificationsUnsupported
+	}
+
+	blockEventsChan := make(chan []*blockwatch.Event, blockEventsBufferSize)
+	blockWatcherSub, err := app.SubscribeToBlockEvents(blockEventsChan)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer blockWatcherSub.Unsubscribe()
+
+		for {
+			select {
+			case events := <-blockEventsChan:
+				blockEvents := make([]*types.BlockEvent, len(events))
+				for i, event := range events {
+					blockEvents[i] = newBlockEvent(event)
+				}
+				err := notifier.Notify(rpcSub.ID, blockEvents)
+				if err != nil {
+					// See SetupOrderStream for why some of these errors are only logged
+					// with `Trace` severity.
+					logEntry := log.WithFields(map[string]interface{}{
+						"error":            err.Error(),
+						"subscriptionType": "blocks",
+						"blockEvents":      len(blockEvents),
+					})
+					message := "error while calling notifier.Notify"
+					if _, ok := err.(*net.OpError); ok {
+						logEntry.Trace(message)
+						return
+					}
+					if strings.Contains(err.Error(), "write: broken pipe") {
+						logEntry.Trace(message)
+					} else {
+						logEntry.Error(message)
+					}
+				}
+			case err := <-rpcSub.Err():
+				if err != nil {
+					log.WithField("err", err).Error("rpcSub returned an error")
+				} else {
+					log.Debug("rpcSub was closed without error")
+				}
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// SubscribeToContractEvents is called when an RPC client sends a `mesh_subscribe` request with the `contractEvents` topic parameter
+func (handler *rpcHandler) SubscribeToContractEvents(ctx context.Context, filter types.ContractEventFilter) (result *ethrpc.Subscription, err error) {
+	log.Debug("received contract event subscription request via RPC")
+	// Catch panics, log stack trace and return RPC error message
+	defer func() {
+		if r := recover(); r != nil {
+			internalErr, ok := r.(error)
+			if !ok {
+				// If r is not of type error, convert it.
+				internalErr = fmt.Errorf("Recovered from non-error: (%T) %v", r, r)
+			}
+			log.WithFields(log.Fields{
+				"error":      internalErr,
+				"method":     "SubscribeToContractEvents",
+				"stackTrace": string(debug.Stack()),
+			}).Error("RPC method handler crashed")
+			err = errors.New("method handler crashed in SubscribeToContractEvents RPC call (check logs for stack trace)")
+		}
+	}()
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	subscription, err := SetupContractEventStream(ctx, handler.app, filter)
+	if err != nil {
+		if err == core.ErrRelayOnly {
+			return nil, err
+		}
+		log.WithField("error", err.Error()).Error("internal error in `mesh_subscribe` to `contractEvents` RPC call")
+		return nil, constants.ErrInternal
+	}
+	return subscription, nil
+}
+
+// SetupContractEventStream sets up the contract event stream for a subscription
+func SetupContractEventStream(ctx context.Context, app *core.App, filter types.ContractEventFilter) (*ethrpc.Subscription, error) {
+	notifier, supported := ethrpc.NotifierFromContext(ctx)
+	if !supported {
+		return &ethrpc.Subscription{}, ethrpc.ErrNotificationsUnsupported
+	}
+
+	contractEventsChan := make(chan []*zeroex.ContractEvent, contractEventsBufferSize)
+	orderWatcherSub, err := app.SubscribeToContractEvents(contractEventsChan)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer orderWatcherSub.Unsubscribe()
+
+		for {
+			select {
+			case contractEvents := <-contractEventsChan:
+				filteredEvents := []*zeroex.ContractEvent{}
+				for _, contractEvent := range contractEvents {
+					if filter.Matches(contractEvent) {
+						filteredEvents = append(filteredEvents, contractEvent)
+					}
+				}
+				if len(filteredEvents) == 0 {
+					continue
+				}
+				err := notifier.Notify(rpcSub.ID, filteredEvents)
+				if err != nil {
+					// See SetupOrderStream for why some of these errors are only logged
+					// with `Trace` severity.
+					logEntry := log.WithFields(map[string]interface{}{
+						"error":            err.Error(),
+						"subscriptionType": "contractEvents",
+						"contractEvents":   len(filteredEvents),
+					})
+					message := "error while calling notifier.Notify"
+					if _, ok := err.(*net.OpError); ok {
+						logEntry.Trace(message)
+						return
+					}
+					if strings.Contains(err.Error(), "write: broken pipe") {
+						logEntry.Trace(message)
+					} else {
+						logEntry.Error(message)
+					}
+				}
+			case err := <-rpcSub.Err():
+				if err != nil {
+					log.WithField("err", err).Error("rpcSub returned an error")
+				} else {
+					log.Debug("rpcSub was closed without error")
+				}
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// newBlockEvent converts a block event emitted by the block watcher to the
+// type used in the RPC interface.
+func newBlockEvent(event *blockwatch.Event) *types.BlockEvent {
+	header := event.BlockHeader
+	blockEvent := &types.BlockEvent{
+		Type:       types.BlockAdded,
+		Number:     int(header.Number.Int64()),
+		Hash:       header.Hash,
+		ParentHash: header.Parent,
+		Timestamp:  header.Timestamp,
+		NumLogs:    len(header.Logs),
+	}
+	if event.Type == blockwatch.Removed {
+		blockEvent.Type = types.BlockRemoved
+	}
+	if header.BaseFee != nil {
+		blockEvent.BaseFee = header.BaseFee.String()
+	}
+	return blockEvent
 }
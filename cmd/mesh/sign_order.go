@@ -0,0 +1,151 @@
+// +build !js
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/ethereum"
+	"github.com/0xProject/0x-mesh/ethereum/ethrpcclient"
+	"github.com/0xProject/0x-mesh/ethereum/signer"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// keystorePasswordEnvVar is the environment variable which holds the password
+// of the keystore file used by "sign-order" if no password file is given.
+const keystorePasswordEnvVar = "MESH_KEYSTORE_PASSWORD"
+
+// runSignOrder reads an unsigned order from stdin, signs it and prints the
+// signed order. The order is signed either with the private key in an
+// encrypted keystore file or via eth_sign on an Ethereum RPC endpoint which
+// manages the maker's account (e.g. a node with an attached hardware wallet).
+func runSignOrder(args []string) error {
+	flags := flag.NewFlagSet("sign-order", flag.ExitOnError)
+	keystorePath := flags.String("keystore", "", "path of an encrypted keystore file holding the maker's private key")
+	passwordPath := flags.String("password-file", "", "path of a file holding the password of the keystore file (default: $"+keystorePasswordEnvVar+")")
+	signerRPCURL := flags.String("signer-rpc-url", "", "URL of an Ethereum RPC endpoint which signs with eth_sign for the maker's account")
+	_ = flags.Parse(args)
+	if (*keystorePath == "") == (*signerRPCURL == "") {
+		return usageError{message: "sign-order requires exactly one of -keystore and -signer-rpc-url"}
+	}
+
+	data, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+	order, err := parseUnsignedOrder(data)
+	if err != nil {
+		return err
+	}
+
+	var orderSigner signer.Signer
+	if *keystorePath != "" {
+		key, err := decryptKeystore(*keystorePath, *passwordPath)
+		if err != nil {
+			return err
+		}
+		if order.MakerAddress == constants.NullAddress {
+			order.MakerAddress = key.Address
+		}
+		orderSigner = signer.NewLocalSigner(key.PrivateKey)
+	} else {
+		if order.MakerAddress == constants.NullAddress {
+			return errors.New("makerAddress is required when signing with -signer-rpc-url")
+		}
+		rpcClient, _, err := ethrpcclient.Dial(context.Background(), *signerRPCURL)
+		if err != nil {
+			return fmt.Errorf("could not connect to the signer RPC endpoint: %s", err.Error())
+		}
+		defer rpcClient.Close()
+		orderSigner = signer.NewEthRPCSigner(rpcClient)
+	}
+
+	signedOrder, err := zeroex.SignOrder(orderSigner, order)
+	if err != nil {
+		return err
+	}
+	return printJSON(os.Stdout, signedOrder)
+}
+
+// parseUnsignedOrder parses the JSON representation of an order without a
+// signature. The exchangeAddress defaults to the Exchange contract of the
+// order's chain and a random salt is used if none is given.
+func parseUnsignedOrder(data []byte) (*zeroex.Order, error) {
+	var signedOrder zeroex.SignedOrder
+	if err := json.Unmarshal(data, &signedOrder); err != nil {
+		return nil, fmt.Errorf("expected an order as JSON: %s", err.Error())
+	}
+	order := signedOrder.Order
+	if order.ChainID == nil || order.ChainID.Sign() == 0 {
+		return nil, errors.New("chainId is required")
+	}
+	requiredFields := []struct {
+		name  string
+		value *big.Int
+	}{
+		{"makerAssetAmount", order.MakerAssetAmount},
+		{"makerFee", order.MakerFee},
+		{"takerAssetAmount", order.TakerAssetAmount},
+		{"takerFee", order.TakerFee},
+		{"expirationTimeSeconds", order.ExpirationTimeSeconds},
+	}
+	var missingFields []string
+	for _, field := range requiredFields {
+		if field.value == nil {
+			missingFields = append(missingFields, field.name)
+		}
+	}
+	if len(missingFields) > 0 {
+		return nil, fmt.Errorf("missing or invalid fields: %s", strings.Join(missingFields, ", "))
+	}
+	if order.ExchangeAddress == constants.NullAddress {
+		contractAddresses, err := ethereum.NewContractAddressesForChainID(int(order.ChainID.Int64()))
+		if err != nil {
+			return nil, fmt.Errorf("exchangeAddress is required: %s", err.Error())
+		}
+		order.ExchangeAddress = contractAddresses.Exchange
+	}
+	if order.Salt == nil {
+		salt, err := rand.Int(rand.Reader, math.MaxBig256)
+		if err != nil {
+			return nil, err
+		}
+		order.Salt = salt
+	}
+	return &order, nil
+}
+
+// decryptKeystore decrypts the keystore file at the given path. The password
+// is read from the file at passwordPath or, if passwordPath is empty, from the
+// keystorePasswordEnvVar environment variable.
+func decryptKeystore(keystorePath string, passwordPath string) (*keystore.Key, error) {
+	keyJSON, err := ioutil.ReadFile(keystorePath)
+	if err != nil {
+		return nil, err
+	}
+	password := os.Getenv(keystorePasswordEnvVar)
+	if passwordPath != "" {
+		passwordBytes, err := ioutil.ReadFile(passwordPath)
+		if err != nil {
+			return nil, err
+		}
+		password = strings.TrimRight(string(passwordBytes), "\r\n")
+	}
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt keystore file: %s", err.Error())
+	}
+	return key, nil
+}
@@ -0,0 +1,135 @@
+// +build !js
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/0xProject/0x-mesh/orderfilter"
+	"github.com/plaid/go-envvar/envvar"
+)
+
+// errOrdersDoNotMatchFilter is returned by "validate-order" if at least one of
+// the orders does not match the filter, so that the command exits with a
+// non-zero status.
+var errOrdersDoNotMatchFilter = errors.New("not all orders match the filter")
+
+// filterConfig is the subset of core.Config which determines the order
+// filter of a node.
+type filterConfig struct {
+	EthereumChainID         int    `envvar:"ETHEREUM_CHAIN_ID" default:"0"`
+	CustomOrderFilter       string `envvar:"CUSTOM_ORDER_FILTER" default:"{}"`
+	FeeRecipientAllowlist   string `envvar:"FEE_RECIPIENT_ALLOWLIST" default:""`
+	CustomContractAddresses string `envvar:"CUSTOM_CONTRACT_ADDRESSES" default:""`
+}
+
+// runValidateOrder checks signed orders against a custom order filter with the
+// same rules a node uses, without a running node. The orders are read from a
+// file or stdin and may either be a single order or an array of orders. The
+// filter is either the custom order schema in the file given by -filter, the
+// schema in CUSTOM_ORDER_FILTER or the filter of the given pubsub topic. Unless
+// a topic is given, FEE_RECIPIENT_ALLOWLIST is applied as well.
+func runValidateOrder(args []string) error {
+	var config filterConfig
+	if err := envvar.Parse(&config); err != nil {
+		return err
+	}
+	flags := flag.NewFlagSet("validate-order", flag.ExitOnError)
+	filterPath := flags.String("filter", "", "path of a file holding the custom order schema (defaults to CUSTOM_ORDER_FILTER)")
+	topic := flags.String("topic", "", "pubsub topic of a node (see pubSubTopic in mesh_getStats) to take the filter from instead")
+	flags.IntVar(&config.EthereumChainID, "chain-id", config.EthereumChainID, "chain ID of the orders (defaults to ETHEREUM_CHAIN_ID)")
+	_ = flags.Parse(args)
+	if flags.NArg() > 1 {
+		return usageError{message: "validate-order accepts at most one file argument"}
+	}
+	if *filterPath != "" && *topic != "" {
+		return usageError{message: "validate-order accepts only one of -filter and -topic"}
+	}
+	if config.EthereumChainID == 0 {
+		return usageError{message: "ETHEREUM_CHAIN_ID or -chain-id is required"}
+	}
+
+	contractAddresses, err := getContractAddresses(config.EthereumChainID, config.CustomContractAddresses)
+	if err != nil {
+		return err
+	}
+	feeRecipientAllowlist, err := orderfilter.ParseFeeRecipientAllowlist(config.FeeRecipientAllowlist)
+	if err != nil {
+		return fmt.Errorf("invalid FEE_RECIPIENT_ALLOWLIST: %s", err.Error())
+	}
+	var filter *orderfilter.Filter
+	if *topic != "" {
+		filter, err = orderfilter.NewFromTopic(*topic, contractAddresses)
+	} else {
+		customOrderSchema := config.CustomOrderFilter
+		if *filterPath != "" {
+			schemaBytes, err := ioutil.ReadFile(*filterPath)
+			if err != nil {
+				return err
+			}
+			customOrderSchema = string(schemaBytes)
+		}
+		customOrderSchema, err = orderfilter.WithFeeRecipientAllowlist(customOrderSchema, feeRecipientAllowlist)
+		if err != nil {
+			return fmt.Errorf("invalid filter: %s", err.Error())
+		}
+		filter, err = orderfilter.New(config.EthereumChainID, customOrderSchema, contractAddresses)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid filter: %s", err.Error())
+	}
+
+	var input io.Reader = os.Stdin
+	if flags.NArg() == 1 && flags.Arg(0) != "-" {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+	data, err := ioutil.ReadAll(input)
+	if err != nil {
+		return err
+	}
+
+	// The orders are checked in their original JSON encoding so that the
+	// result is exactly the same as for orders received from peers.
+	isArray := len(bytes.TrimSpace(data)) > 0 && bytes.TrimSpace(data)[0] == '['
+	ordersJSON := []json.RawMessage{data}
+	if isArray {
+		if err := json.Unmarshal(data, &ordersJSON); err != nil {
+			return fmt.Errorf("expected an order or an array of orders as JSON: %s", err.Error())
+		}
+	}
+	results := make([]*orderfilter.CheckResult, len(ordersJSON))
+	allValid := true
+	for i, orderJSON := range ordersJSON {
+		result, err := filter.CheckOrderJSON(orderJSON)
+		if err != nil {
+			return fmt.Errorf("could not check order %d: %s", i, err.Error())
+		}
+		results[i] = result
+		allValid = allValid && result.Valid
+	}
+
+	if isArray {
+		err = printJSON(os.Stdout, results)
+	} else {
+		err = printJSON(os.Stdout, results[0])
+	}
+	if err != nil {
+		return err
+	}
+	if !allValid {
+		return errOrdersDoNotMatchFilter
+	}
+	return nil
+}
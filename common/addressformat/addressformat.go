@@ -0,0 +1,84 @@
+// Package addressformat determines how Ethereum addresses are rendered in the
+// JSON emitted by Mesh, e.g. in orders, order events and RPC responses. All
+// JSON emitters use the same format so that addresses can be compared as
+// strings. Parsing addresses is case-insensitive regardless of the format.
+package addressformat
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Format is a way of rendering addresses.
+type Format string
+
+const (
+	// Lowercase renders addresses as lowercase hex strings, e.g.
+	// "0xe36ea790bc9d7ab70c55260c66d52b1eca985f84". It is the default.
+	Lowercase Format = "lowercase"
+	// Checksum renders addresses as EIP-55 mixed-case checksummed hex strings,
+	// e.g. "0xE36Ea790bc9d7AB70C55260C66D52b1eca985f84".
+	Checksum Format = "checksum"
+)
+
+// current holds the Format used by String. It is stored as an atomic.Value
+// because JSON marshallers read it from many goroutines.
+var current atomic.Value
+
+func init() {
+	current.Store(Lowercase)
+}
+
+// Parse returns the Format with the given name. The empty string is parsed as
+// Lowercase.
+func Parse(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case "":
+		return Lowercase, nil
+	case Lowercase, Checksum:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid address format: %q (must be %q or %q)", name, Lowercase, Checksum)
+	}
+}
+
+// Set sets the Format used by String. It affects all subsequently emitted
+// JSON.
+func Set(format Format) error {
+	if _, err := Parse(string(format)); err != nil {
+		return err
+	}
+	if format == "" {
+		format = Lowercase
+	}
+	current.Store(format)
+	return nil
+}
+
+// Get returns the Format used by String.
+func Get() Format {
+	return current.Load().(Format)
+}
+
+// String renders the given address in the current Format.
+func String(address common.Address) string {
+	if Get() == Checksum {
+		return address.Hex()
+	}
+	return strings.ToLower(address.Hex())
+}
+
+// IsHexAddress returns true if s is a 0x-prefixed hex-encoded address in any
+// case.
+func IsHexAddress(s string) bool {
+	return len(s) == 2+2*common.AddressLength && (strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X")) && common.IsHexAddress(s)
+}
+
+// Reformat renders the given hex-encoded address in the current Format. s
+// must satisfy IsHexAddress.
+func Reformat(s string) string {
+	return String(common.HexToAddress(s))
+}
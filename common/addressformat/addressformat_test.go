@@ -0,0 +1,46 @@
+package addressformat
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	defer func() {
+		require.NoError(t, Set(Lowercase))
+	}()
+	address := common.HexToAddress("0xe36ea790bc9d7ab70c55260c66d52b1eca985f84")
+
+	assert.Equal(t, Lowercase, Get())
+	assert.Equal(t, "0xe36ea790bc9d7ab70c55260c66d52b1eca985f84", String(address))
+
+	require.NoError(t, Set(Checksum))
+	assert.Equal(t, "0xE36Ea790bc9d7AB70C55260C66D52b1eca985f84", String(address))
+	assert.Equal(t, "0xE36Ea790bc9d7AB70C55260C66D52b1eca985f84", Reformat("0XE36EA790BC9D7AB70C55260C66D52B1ECA985F84"))
+
+	assert.Error(t, Set("uppercase"))
+	assert.Equal(t, Checksum, Get())
+}
+
+func TestParse(t *testing.T) {
+	format, err := Parse("")
+	require.NoError(t, err)
+	assert.Equal(t, Lowercase, format)
+
+	format, err = Parse("Checksum")
+	require.NoError(t, err)
+	assert.Equal(t, Checksum, format)
+
+	_, err = Parse("eip55")
+	assert.Error(t, err)
+}
+
+func TestIsHexAddress(t *testing.T) {
+	assert.True(t, IsHexAddress("0xE36Ea790bc9d7AB70C55260C66D52b1eca985f84"))
+	assert.False(t, IsHexAddress("e36ea790bc9d7ab70c55260c66d52b1eca985f84"))
+	assert.False(t, IsHexAddress("0xe36ea790bc9d7ab70c55260c66d52b1eca985f8"))
+	assert.False(t, IsHexAddress("0xe36ea790bc9d7ab70c55260c66d52b1eca985f84ab"))
+}
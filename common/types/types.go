@@ -5,32 +5,136 @@ package types
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
 	"time"
 
 	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 )
 
 // Stats is the return value for core.GetStats. Also used in the browser and RPC
 // interface.
 type Stats struct {
-	Version                           string      `json:"version"`
-	PubSubTopic                       string      `json:"pubSubTopic"`
-	Rendezvous                        string      `json:"rendezvous"`
-	SecondaryRendezvous               []string    `json:"secondaryRendezvous"`
-	PeerID                            string      `json:"peerID"`
-	EthereumChainID                   int         `json:"ethereumChainID"`
-	LatestBlock                       LatestBlock `json:"latestBlock"`
-	NumPeers                          int         `json:"numPeers"`
-	NumOrders                         int         `json:"numOrders"`
-	NumOrdersIncludingRemoved         int         `json:"numOrdersIncludingRemoved"`
-	NumPinnedOrders                   int         `json:"numPinnedOrders"`
-	MaxExpirationTime                 string      `json:"maxExpirationTime"`
-	StartOfCurrentUTCDay              time.Time   `json:"startOfCurrentUTCDay"`
-	EthRPCRequestsSentInCurrentUTCDay int         `json:"ethRPCRequestsSentInCurrentUTCDay"`
-	EthRPCRateLimitExpiredRequests    int64       `json:"ethRPCRateLimitExpiredRequests"`
+	Version                           string                   `json:"version"`
+	PubSubTopic                       string                   `json:"pubSubTopic"`
+	Rendezvous                        string                   `json:"rendezvous"`
+	SecondaryRendezvous               []string                 `json:"secondaryRendezvous"`
+	PeerID                            string                   `json:"peerID"`
+	EthereumChainID                   int                      `json:"ethereumChainID"`
+	RelayOnly                         bool                     `json:"relayOnly"`
+	LatestBlock                       LatestBlock              `json:"latestBlock"`
+	NumPeers                          int                      `json:"numPeers"`
+	PeerLatencies                     map[string]time.Duration `json:"peerLatencies"`
+	NumOrders                         int                      `json:"numOrders"`
+	NumOrdersIncludingRemoved         int                      `json:"numOrdersIncludingRemoved"`
+	NumPinnedOrders                   int                      `json:"numPinnedOrders"`
+	MaxExpirationTime                 string                   `json:"maxExpirationTime"`
+	StartOfCurrentUTCDay              time.Time                `json:"startOfCurrentUTCDay"`
+	EthRPCRequestsSentInCurrentUTCDay int                      `json:"ethRPCRequestsSentInCurrentUTCDay"`
+	EthRPCRateLimitExpiredRequests    int64                    `json:"ethRPCRateLimitExpiredRequests"`
+	NumPendingValidation              int                      `json:"numPendingValidation"`
+	RateLimiting                      RateLimitStats           `json:"rateLimiting"`
+	SignatureCacheHitRate             float64                  `json:"signatureCacheHitRate"`
+	LastCleanup                       CleanupStats             `json:"lastCleanup"`
+	StartupRevalidation               StartupRevalidationStats `json:"startupRevalidation"`
+	Reachability                      ReachabilityStats        `json:"reachability"`
+	Subscriptions                     []SubscriptionStats      `json:"subscriptions"`
+	Chain                             ChainStats               `json:"chain"`
+	IngestionLatency                  IngestionLatencyStats    `json:"ingestionLatency"`
+	ShadowFilter                      *ShadowFilterStats       `json:"shadowFilter,omitempty"`
+}
+
+// ChainStats contains counters about the blocks and contract events processed
+// by the node since it started. They help to correlate anomalies in order
+// events with the behavior of the chain.
+type ChainStats struct {
+	// NumBlocksProcessed is the number of blocks added to the chain that were
+	// processed.
+	NumBlocksProcessed uint64 `json:"numBlocksProcessed"`
+	// NumReorgs is the number of chain re-orgs observed.
+	NumReorgs uint64 `json:"numReorgs"`
+	// ReorgsByDepth is the number of re-orgs observed for each depth, i.e.
+	// number of blocks removed from the chain.
+	ReorgsByDepth map[int]uint64 `json:"reorgsByDepth"`
+	// NumLogsDecoded is the number of logs decoded into contract events for
+	// each kind of contract event (e.g. "ERC20TransferEvent").
+	NumLogsDecoded map[string]uint64 `json:"numLogsDecoded"`
+	// NumOrdersRevalidated is the number of orders re-validated because of
+	// contract events or expirations.
+	NumOrdersRevalidated uint64 `json:"numOrdersRevalidated"`
+	// NumValidationCalls is the number of eth_call requests made to
+	// re-validate them.
+	NumValidationCalls uint64 `json:"numValidationCalls"`
+	// LastBlock contains the counters for the most recently processed blocks.
+	LastBlock BlockProcessingStats `json:"lastBlock"`
+}
+
+// BlockProcessingStats contains counters about the processing of the blocks
+// received at once, which is usually a single block.
+type BlockProcessingStats struct {
+	// Number is the number of the latest block. It is 0 if no blocks have been
+	// processed yet.
+	Number               int    `json:"number"`
+	NumLogsDecoded       int    `json:"numLogsDecoded"`
+	NumOrdersRevalidated int    `json:"numOrdersRevalidated"`
+	NumValidationCalls   uint64 `json:"numValidationCalls"`
+}
+
+// IngestionLatencyStats contains the latencies of the stages through which
+// new orders received via GossipSub pass until their ADDED event is emitted.
+// Only orders which were accepted and not already stored are measured.
+type IngestionLatencyStats struct {
+	// Queue is the time spent waiting in the validation queue.
+	Queue LatencyStats `json:"queue"`
+	// Decoding is the time spent decoding messages and verifying signatures.
+	Decoding LatencyStats `json:"decoding"`
+	// Validation is the time spent validating and storing orders.
+	Validation LatencyStats `json:"validation"`
+	// Emission is the time from an order being stored until its ADDED event
+	// is emitted, including ORDER_EVENT_COALESCING_WINDOW.
+	Emission LatencyStats `json:"emission"`
+	// Total is the time from the receipt of an order until its ADDED event is
+	// emitted.
+	Total LatencyStats `json:"total"`
+}
+
+// LatencyStats contains the percentiles of the most recent latencies of a
+// stage of the ingestion pipeline. The percentiles are 0 if nothing has been
+// measured yet.
+type LatencyStats struct {
+	// Count is the number of latencies measured since the node started.
+	Count uint64        `json:"count"`
+	P50   time.Duration `json:"p50"`
+	P95   time.Duration `json:"p95"`
+	P99   time.Duration `json:"p99"`
+}
+
+// ShadowFilterStats counts how many of the orders received since the node
+// started the shadow order filter would have accepted or rejected, compared to
+// the active order filter. Orders received via ordersync are evaluated every
+// time they are received.
+type ShadowFilterStats struct {
+	// PubSubTopic is the pubsub topic the node would use with the shadow
+	// filter.
+	PubSubTopic string `json:"pubSubTopic"`
+	// Since is when the node started evaluating the shadow filter.
+	Since time.Time `json:"since"`
+	// NumEvaluated is the number of orders evaluated.
+	NumEvaluated uint64 `json:"numEvaluated"`
+	// NumAcceptedByBoth is the number of orders accepted by both filters.
+	NumAcceptedByBoth uint64 `json:"numAcceptedByBoth"`
+	// NumAcceptedOnlyByShadow is the number of orders which the active filter
+	// rejected but the shadow filter would have accepted.
+	NumAcceptedOnlyByShadow uint64 `json:"numAcceptedOnlyByShadow"`
+	// NumRejectedOnlyByShadow is the number of orders which the active filter
+	// accepted but the shadow filter would have rejected.
+	NumRejectedOnlyByShadow uint64 `json:"numRejectedOnlyByShadow"`
+	// NumRejectedByBoth is the number of orders rejected by both filters.
+	NumRejectedByBoth uint64 `json:"numRejectedByBoth"`
 }
 
 // LatestBlock is the latest block processed by the Mesh node.
@@ -39,6 +143,252 @@ type LatestBlock struct {
 	Hash   common.Hash `json:"hash"`
 }
 
+// RateLimitStats contains the number of GossipSub messages which were dropped
+// before validation because of the rate limits.
+type RateLimitStats struct {
+	// PerPeerLimitViolations is the number of messages dropped because the
+	// sender exceeded the per-peer rate limit or was banned.
+	PerPeerLimitViolations uint64 `json:"perPeerLimitViolations"`
+	// GlobalLimitViolations is the number of messages dropped because of the
+	// global rate limit.
+	GlobalLimitViolations uint64 `json:"globalLimitViolations"`
+	// OversizedMessages is the number of messages dropped because they were
+	// too large.
+	OversizedMessages uint64 `json:"oversizedMessages"`
+	// NumBans is the number of times a peer was temporarily banned for
+	// repeatedly exceeding the per-peer rate limit.
+	NumBans uint64 `json:"numBans"`
+}
+
+// CleanupStats contains information about the most recent periodic re-validation
+// of orders which have not been updated recently.
+type CleanupStats struct {
+	// StartTime is when the cleanup started. It is the zero time if no cleanup
+	// has run yet.
+	StartTime time.Time `json:"startTime"`
+	// Duration is how long the cleanup took.
+	Duration time.Duration `json:"duration"`
+	// NumOrdersChecked is the number of orders that were re-validated.
+	NumOrdersChecked int `json:"numOrdersChecked"`
+	// NumOrdersDeferred is the number of orders that needed to be re-validated
+	// but were left for the next cleanup because of ORDER_CLEANUP_MAX_ORDERS.
+	NumOrdersDeferred int `json:"numOrdersDeferred"`
+	// NumOrderEvents is the number of order events emitted by the cleanup.
+	NumOrderEvents int `json:"numOrderEvents"`
+	// Error is the error that caused the cleanup to fail, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// StartupRevalidationStats contains information about the progress of the
+// re-validation of all stored orders, which happens on startup if Mesh was
+// offline for too long to catch up on contract events.
+type StartupRevalidationStats struct {
+	// StartTime is when the re-validation started. It is the zero time if no
+	// re-validation was needed.
+	StartTime time.Time `json:"startTime"`
+	// EndTime is when the re-validation finished. It is the zero time if the
+	// re-validation has not finished yet.
+	EndTime time.Time `json:"endTime"`
+	// NumOrdersTotal is the number of orders to be re-validated.
+	NumOrdersTotal int `json:"numOrdersTotal"`
+	// NumOrdersRevalidated is the number of orders re-validated so far.
+	NumOrdersRevalidated int `json:"numOrdersRevalidated"`
+	// Error is the error that caused the re-validation to fail, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// SubscriptionStats describes the queue of notifications which have not yet
+// been sent to the client of an order event subscription.
+type SubscriptionStats struct {
+	// ID identifies the subscription within the stats. It is unrelated to the
+	// ID of the JSON-RPC subscription.
+	ID    uint64 `json:"id"`
+	Topic string `json:"topic"`
+	// QueueDepth is the number of queued notifications.
+	QueueDepth int `json:"queueDepth"`
+	// QueueSize is the maximum number of queued notifications.
+	QueueSize int `json:"queueSize"`
+	// NumDropped is the number of notifications that were dropped because
+	// the queue was full.
+	NumDropped int `json:"numDropped"`
+}
+
+// ReachabilityStats describes whether the Mesh node can be dialed by peers
+// from the public internet, as determined by AutoNAT.
+type ReachabilityStats struct {
+	// NATStatus is "unknown" until AutoNAT has asked enough peers to dial the
+	// node back, and "public" or "private" afterwards.
+	NATStatus string `json:"natStatus"`
+	// ConfirmedPublicAddrs are the public multiaddresses at which peers
+	// successfully dialed the node.
+	ConfirmedPublicAddrs []string `json:"confirmedPublicAddrs"`
+	// AdvertisedPublicAddrs are the public multiaddresses which the node
+	// advertises to peers, including ports mapped via UPnP or NAT-PMP.
+	AdvertisedPublicAddrs []string `json:"advertisedPublicAddrs"`
+}
+
+// RuntimeStats is the return value for core.GetRuntimeStats. Also used in the
+// RPC interface.
+type RuntimeStats struct {
+	// NumGoroutines is the number of goroutines that currently exist.
+	NumGoroutines int `json:"numGoroutines"`
+	// HeapAlloc is the number of bytes of allocated heap objects.
+	HeapAlloc uint64 `json:"heapAlloc"`
+	// HeapInuse is the number of bytes in in-use heap spans.
+	HeapInuse uint64 `json:"heapInuse"`
+	// HeapSys is the number of bytes of heap memory obtained from the OS.
+	HeapSys uint64 `json:"heapSys"`
+	// HeapObjects is the number of allocated heap objects.
+	HeapObjects uint64 `json:"heapObjects"`
+	// NumGC is the number of completed GC cycles.
+	NumGC uint32 `json:"numGC"`
+	// GCPauseTotal is the cumulative time spent in GC stop-the-world pauses.
+	GCPauseTotal time.Duration `json:"gcPauseTotal"`
+	// RecentGCPauses are the durations of the most recent GC pauses (up to 16),
+	// most recent first.
+	RecentGCPauses []time.Duration `json:"recentGCPauses"`
+	// NumOpenFDs is the number of open file descriptors, or -1 if it cannot be
+	// determined on this platform.
+	NumOpenFDs int `json:"numOpenFDs"`
+}
+
+// PeerInfo is the return value for core.GetPeers. Also used in the RPC
+// interface.
+type PeerInfo struct {
+	// PeerID is the base58-encoded ID of the peer.
+	PeerID string `json:"peerID"`
+	// Multiaddrs are the remote addresses of the open connections to the peer.
+	Multiaddrs []string `json:"multiaddrs"`
+	// UserAgent is the user agent the peer advertised via the libp2p identify
+	// protocol. It is empty if the peer hasn't been identified yet.
+	UserAgent string `json:"userAgent"`
+	// MeshVersion, ChainID and TopicHash are the Mesh version, the chain ID
+	// and the Keccak256 hash of the topic orders are shared on, as advertised
+	// in the user agent of the peer. They are empty if the peer doesn't run a
+	// version of Mesh which advertises them.
+	MeshVersion string `json:"meshVersion"`
+	ChainID     int    `json:"chainID"`
+	TopicHash   string `json:"topicHash"`
+	// SameTopic is true if the peer shares orders on the same topic as this
+	// node, i.e. it uses the same order filter on the same chain.
+	SameTopic bool `json:"sameTopic"`
+	// RelayOnly is true if the peer advertised that it runs in relay-only
+	// mode, i.e. that it doesn't validate orders on-chain.
+	RelayOnly bool `json:"relayOnly"`
+	// Connections are the open connections to the peer.
+	Connections []*PeerConnectionInfo `json:"connections"`
+	// ConnectionAge is how long the oldest open connection to the peer has
+	// been open.
+	ConnectionAge time.Duration `json:"connectionAge"`
+	// Protocols are the libp2p protocols the peer supports.
+	Protocols []string `json:"protocols"`
+	// Topics are the GossipSub topics Mesh shares orders on which the peer is
+	// subscribed to.
+	Topics []string `json:"topics"`
+	// Score is the current score of the peer. Peers with a lower score are
+	// disconnected first when Mesh has too many peers.
+	Score int `json:"score"`
+	// MessagesReceived is the number of GossipSub messages received from the
+	// peer since Mesh started and MessagesRejected is the number of them
+	// which were invalid.
+	MessagesReceived uint64 `json:"messagesReceived"`
+	MessagesRejected uint64 `json:"messagesRejected"`
+	// BytesReceived and BytesSent are the total number of bytes received from
+	// and sent to the peer since Mesh started.
+	BytesReceived int64 `json:"bytesReceived"`
+	BytesSent     int64 `json:"bytesSent"`
+}
+
+// PeerConnectionInfo contains information about an open connection to a
+// peer. Used in PeerInfo.
+type PeerConnectionInfo struct {
+	// Multiaddr is the remote address of the connection.
+	Multiaddr string `json:"multiaddr"`
+	// Direction is "inbound" if the peer opened the connection and "outbound"
+	// if Mesh did.
+	Direction string `json:"direction"`
+	// OpenedAt is the time at which the connection was opened.
+	OpenedAt time.Time `json:"openedAt"`
+}
+
+// GetPropagationReceiptsOpts are the options for core.GetPropagationReceipts.
+// Also used in the RPC interface.
+type GetPropagationReceiptsOpts struct {
+	// Timeout is the maximum amount of time to wait for peers to respond.
+	// Defaults to 5 seconds and may be at most 30 seconds.
+	Timeout time.Duration `json:"timeout"`
+	// MaxPeers is the maximum number of peers to ask. Defaults to 8 and may be
+	// at most 32.
+	MaxPeers int `json:"maxPeers"`
+}
+
+// PropagationReceipts is the return value for core.GetPropagationReceipts.
+// Also used in the RPC interface.
+type PropagationReceipts struct {
+	// NumTopicPeers is the number of connected peers subscribed to the order
+	// topic.
+	NumTopicPeers int `json:"numTopicPeers"`
+	// NumPeersQueried is the number of peers which were sampled and asked
+	// whether they have stored the orders.
+	NumPeersQueried int `json:"numPeersQueried"`
+	// NumPeersResponded is the number of sampled peers which responded within
+	// the timeout.
+	NumPeersResponded int `json:"numPeersResponded"`
+	// Receipts contains a receipt for each of the requested order hashes, in
+	// the same order.
+	Receipts []*PropagationReceipt `json:"receipts"`
+}
+
+// PropagationReceipt describes how far an order has propagated.
+type PropagationReceipt struct {
+	OrderHash common.Hash `json:"orderHash"`
+	// NumPeersStored is the number of sampled peers which have stored the
+	// order.
+	NumPeersStored int `json:"numPeersStored"`
+	// EstimatedPropagationCount is the estimated number of peers subscribed to
+	// the order topic which have stored the order. It is extrapolated from the
+	// responses of the sampled peers.
+	EstimatedPropagationCount int `json:"estimatedPropagationCount"`
+}
+
+// GetRecentRejectionsOpts are the options for core.GetRecentRejections.
+type GetRecentRejectionsOpts struct {
+	// MakerAddress limits the returned rejections to orders from this maker.
+	// If nil, rejections of orders from all makers are returned.
+	MakerAddress *common.Address `json:"makerAddress,omitempty"`
+}
+
+// RecentRejections is the return value for core.GetRecentRejections. Also used
+// in the RPC interface.
+type RecentRejections struct {
+	// CountsByCode is the number of orders rejected since the node started by
+	// the code of the RejectedOrderStatus.
+	CountsByCode map[string]uint64 `json:"countsByCode"`
+	// Rejections are the most recent rejections, most recent first.
+	Rejections []*RejectionRecord `json:"rejections"`
+}
+
+// RejectionRecord describes a rejected order.
+type RejectionRecord struct {
+	// Time is when the order was rejected.
+	Time time.Time `json:"time"`
+	// OrderHash is the hash of the order. It is the zero hash if the order
+	// could not be decoded.
+	OrderHash common.Hash `json:"orderHash"`
+	// MakerAddress is the maker of the order. It is the zero address if the
+	// order could not be decoded.
+	MakerAddress common.Address `json:"makerAddress"`
+	// Code and Message are taken from the RejectedOrderStatus.
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Source is how the order was received: "api" (added via the JSON-RPC
+	// API or in the browser), "gossipsub" or "ordersync".
+	Source string `json:"source"`
+	// PeerID is the ID of the peer the order was received from. It is empty
+	// if the order was not received from a peer.
+	PeerID string `json:"peerID,omitempty"`
+}
+
 // GetOrdersResponse is the return value for core.GetOrders. Also used in the
 // browser and RPC interface.
 type GetOrdersResponse struct {
@@ -47,6 +397,41 @@ type GetOrdersResponse struct {
 	OrdersInfos       []*OrderInfo `json:"ordersInfos"`
 }
 
+// GetOrderDiffResponse is the return value for core.GetOrderDiff. Also used
+// in the RPC interface.
+type GetOrderDiffResponse struct {
+	// SnapshotID identifies the state of the orderbook the diff leads to. It
+	// can be passed to the next GetOrderDiff call.
+	SnapshotID        string    `json:"snapshotID"`
+	SnapshotTimestamp time.Time `json:"snapshotTimestamp"`
+	// Added are the orders which were not in the previous snapshot.
+	Added []*OrderInfo `json:"added"`
+	// Changed are the orders whose fillable taker asset amount changed since
+	// the previous snapshot.
+	Changed []*OrderInfo `json:"changed"`
+	// Removed are the hashes of the orders which are no longer fillable.
+	Removed []common.Hash `json:"removed"`
+}
+
+// GetOrderCountsResponse is the return value for core.GetOrderCounts. Also
+// used in the RPC interface.
+type GetOrderCountsResponse struct {
+	// GroupBy is "pair", "maker" or "status".
+	GroupBy string `json:"groupBy"`
+	// Counts are sorted by count in descending order.
+	Counts []*OrderCount `json:"counts"`
+}
+
+// OrderCount is the number of orders in a group. Only the fields which
+// identify the group are set, depending on GroupBy.
+type OrderCount struct {
+	MakerAssetData string `json:"makerAssetData,omitempty"`
+	TakerAssetData string `json:"takerAssetData,omitempty"`
+	MakerAddress   string `json:"makerAddress,omitempty"`
+	Status         string `json:"status,omitempty"`
+	Count          int    `json:"count"`
+}
+
 // AddOrdersOpts is a set of options for core.AddOrders. Also used in the
 // browser and RPC interface.
 type AddOrdersOpts struct {
@@ -55,6 +440,307 @@ type AddOrdersOpts struct {
 	// and will always stay in storage until they are no longer fillable. Defaults
 	// to true.
 	Pinned bool `json:"pinned"`
+	// ReplacesOrderHashes maps the hash of a new order to the hash of a stored
+	// order which it replaces. If the new order is accepted and both orders
+	// have the same maker and asset pair, the replaced order is removed and a
+	// REPLACED order event is emitted for it.
+	ReplacesOrderHashes map[common.Hash]common.Hash `json:"replacesOrderHashes,omitempty"`
+	// Metadata maps order hashes to opaque JSON values (e.g. client order IDs
+	// or strategy tags) which are stored along with the orders. Metadata is
+	// only stored locally and never shared with peers. It is returned by
+	// GetOrders and included in order events.
+	Metadata map[common.Hash]json.RawMessage `json:"metadata,omitempty"`
+}
+
+// ValidateOrdersOpts is a set of options for core.ValidateOrders. Also used in
+// the RPC interface.
+type ValidateOrdersOpts struct {
+	// Pending determines whether orders are validated against the pending state
+	// of the Ethereum node Mesh is connected to instead of the latest block.
+	Pending bool `json:"pending"`
+	// StateOverrides are applied to the state orders are validated against.
+	// They use the same format as the state override set of eth_call and
+	// require an Ethereum node which supports them.
+	StateOverrides ordervalidator.StateOverrides `json:"stateOverrides,omitempty"`
+}
+
+// SimulateFillOpts is a set of options for core.SimulateFill. Also used in the
+// RPC interface. Amounts are encoded as decimal strings.
+type SimulateFillOpts struct {
+	// TakerAddress is the address the fill is simulated from.
+	TakerAddress common.Address `json:"takerAddress"`
+	// TakerAssetFillAmount is the amount of the taker asset to fill.
+	TakerAssetFillAmount string `json:"takerAssetFillAmount"`
+	// GasPrice is the gas price of the simulated transaction, which determines
+	// the protocol fee paid by the taker. If empty, no protocol fee is paid.
+	GasPrice string `json:"gasPrice,omitempty"`
+}
+
+// SimulateFillResult is the return value for core.SimulateFill. Also used in
+// the RPC interface. Amounts are encoded as decimal strings.
+type SimulateFillResult struct {
+	// Success is true if the simulated fill did not revert.
+	Success bool `json:"success"`
+	// The amounts filled and fees paid. They are only set if Success is true.
+	MakerAssetFilledAmount string `json:"makerAssetFilledAmount,omitempty"`
+	TakerAssetFilledAmount string `json:"takerAssetFilledAmount,omitempty"`
+	MakerFeePaid           string `json:"makerFeePaid,omitempty"`
+	TakerFeePaid           string `json:"takerFeePaid,omitempty"`
+	ProtocolFeePaid        string `json:"protocolFeePaid,omitempty"`
+	// EstimatedGas is the amount of gas the fill is expected to use. It is only
+	// set if Success is true.
+	EstimatedGas uint64 `json:"estimatedGas,omitempty"`
+	// RevertReason explains why the simulated fill reverted. It is only set if
+	// Success is false.
+	RevertReason string `json:"revertReason,omitempty"`
+}
+
+// MakerLists is the return value for core.GetMakerLists. Also used in the RPC
+// interface.
+type MakerLists struct {
+	// Allowlist is the set of makers whose orders are accepted. If empty, orders
+	// from any maker not on the denylist are accepted.
+	Allowlist []common.Address `json:"allowlist"`
+	// Denylist is the set of makers whose orders are rejected.
+	Denylist []common.Address `json:"denylist"`
+}
+
+// SetMakerListResponse is the return value for core.SetMakerAllowlist and
+// core.SetMakerDenylist. Also used in the RPC interface.
+type SetMakerListResponse struct {
+	// NumOrdersRemoved is the number of stored orders that were removed because
+	// their maker is no longer allowed.
+	NumOrdersRemoved int `json:"numOrdersRemoved"`
+}
+
+// SetAssetDenylistResponse is the return value for core.SetAssetDenylist. Also
+// used in the RPC interface.
+type SetAssetDenylistResponse struct {
+	// NumOrdersRemoved is the number of stored orders that were removed because
+	// they involve a token on the new denylist.
+	NumOrdersRemoved int `json:"numOrdersRemoved"`
+}
+
+// AuditRecord is an entry in the audit log of mutating RPC calls. Also used in
+// the RPC interface.
+type AuditRecord struct {
+	// Time is when the call was made.
+	Time time.Time `json:"time"`
+	// Method is the name of the RPC method that was called, e.g.
+	// "mesh_addOrders".
+	Method string `json:"method"`
+	// Caller identifies the caller by the remote address of the HTTP request
+	// or WebSocket connection. It is empty if the caller is unknown.
+	Caller string `json:"caller,omitempty"`
+	// ParamsDigest is the Keccak256 hash of the JSON-encoded parameters.
+	ParamsDigest string `json:"paramsDigest"`
+	// Success is true if the call did not return an error.
+	Success bool `json:"success"`
+	// Error is the error returned by the call, if any.
+	Error string `json:"error,omitempty"`
+	// Result is a short summary of the outcome of a successful call, e.g.
+	// "accepted=2 rejected=1".
+	Result string `json:"result,omitempty"`
+}
+
+// ExportAuditLogResponse is the return value for core.ExportAuditLog. Also used
+// in the RPC interface.
+type ExportAuditLogResponse struct {
+	// Path is the path of the file the audit log was exported to.
+	Path string `json:"path"`
+	// NumRecords is the number of records that were exported.
+	NumRecords int `json:"numRecords"`
+}
+
+// MetricsSample is a sample of key metrics taken at a point in time. Returned
+// by core.GetMetricsHistory and also used in the RPC interface.
+type MetricsSample struct {
+	// Time is when the sample was taken.
+	Time time.Time `json:"time"`
+	// NumOrders is the number of stored orders which were not flagged for
+	// removal.
+	NumOrders int `json:"numOrders"`
+	// NumPeers is the number of peers Mesh was connected to.
+	NumPeers int `json:"numPeers"`
+	// ValidationLatency is the average amount of time it took to validate new
+	// orders since the previous sample. It is 0 if no orders were validated.
+	ValidationLatency time.Duration `json:"validationLatency"`
+	// NumValidations is the number of times new orders were validated since
+	// the previous sample.
+	NumValidations int `json:"numValidations"`
+	// EthRPCRequestsSentInCurrentUTCDay is the number of Ethereum JSON-RPC
+	// requests sent in the current UTC day.
+	EthRPCRequestsSentInCurrentUTCDay int `json:"ethRPCRequestsSentInCurrentUTCDay"`
+	// EthRPCMaxRequestsPer24HrUTC is the maximum number of Ethereum JSON-RPC
+	// requests (or compute units, depending on the provider profile) per UTC
+	// day. It is 0 if rate limiting is disabled.
+	EthRPCMaxRequestsPer24HrUTC int `json:"ethRPCMaxRequestsPer24HrUTC"`
+}
+
+// ExportOrdersOpts is a set of options for core.ExportOrders. Also used in the
+// RPC interface.
+type ExportOrdersOpts struct {
+	// Format is the format of the export: "jsonl" (the default), with one
+	// JSON-encoded OrderInfo per line, or "csv".
+	Format string `json:"format,omitempty"`
+	// Gzip determines whether the export is compressed with gzip.
+	Gzip bool `json:"gzip,omitempty"`
+	// Status determines which orders are exported: "fillable" (the default)
+	// for the orders that are currently fillable, "removed" for orders that
+	// have been flagged for removal or "all".
+	Status string `json:"status,omitempty"`
+	// MakerAddress limits the export to orders from this maker. If nil, orders
+	// from all makers are exported.
+	MakerAddress *common.Address `json:"makerAddress,omitempty"`
+	// MakerAssetData limits the export to orders with this maker asset. If
+	// empty, orders with any maker asset are exported.
+	MakerAssetData hexutil.Bytes `json:"makerAssetData,omitempty"`
+	// TakerAssetData limits the export to orders with this taker asset. If
+	// empty, orders with any taker asset are exported.
+	TakerAssetData hexutil.Bytes `json:"takerAssetData,omitempty"`
+}
+
+// ExportOrdersResponse is the return value for core.ExportOrdersToFile. Also
+// used in the RPC interface.
+type ExportOrdersResponse struct {
+	// Path is the path of the file the orders were exported to.
+	Path string `json:"path"`
+	// NumOrders is the number of orders that were exported.
+	NumOrders int `json:"numOrders"`
+}
+
+// BackfillOrderEventsOpts is a set of options for core.BackfillOrderEvents.
+// Also used in the RPC interface.
+type BackfillOrderEventsOpts struct {
+	// MakerAddresses are the makers whose Exchange logs are scanned.
+	MakerAddresses []common.Address `json:"makerAddresses"`
+	// FromBlock is the first block that is scanned.
+	FromBlock int64 `json:"fromBlock"`
+	// ToBlock is the last block that is scanned. If 0, the latest block
+	// processed by Mesh is used.
+	ToBlock int64 `json:"toBlock,omitempty"`
+}
+
+// BackfillOrderEventsResponse is the return value for
+// core.BackfillOrderEvents. Also used in the RPC interface.
+type BackfillOrderEventsResponse struct {
+	// FromBlock is the first block that was scanned.
+	FromBlock int64 `json:"fromBlock"`
+	// ToBlock is the last block that was scanned.
+	ToBlock int64 `json:"toBlock"`
+	// NumLogs is the number of Fill, Cancel and CancelUpTo logs that were
+	// found.
+	NumLogs int `json:"numLogs"`
+	// NumOrdersChecked is the number of stored orders affected by the logs
+	// which were re-validated.
+	NumOrdersChecked int `json:"numOrdersChecked"`
+	// NumOrderEvents is the number of corrective order events that were
+	// emitted.
+	NumOrderEvents int `json:"numOrderEvents"`
+}
+
+// OrderSubscriptionOpts are the options for subscriptions to the `orders`
+// topic. Also used in the RPC interface.
+type OrderSubscriptionOpts struct {
+	// Resumable determines whether order events are sent as
+	// OrderEventsNotifications which include a resume token. If false, order
+	// events are sent as plain arrays.
+	Resumable bool `json:"resumable"`
+	// ResumeToken is the resume token of the last notification received before
+	// a previous subscription was interrupted. If set, the subscription is
+	// resumable and the order events emitted since then are sent first.
+	ResumeToken string `json:"resumeToken,omitempty"`
+}
+
+// OrderEventsNotification is a batch of order events sent to resumable
+// subscriptions to the `orders` topic.
+type OrderEventsNotification struct {
+	// ResumeToken can be passed to a new subscription in order to receive all
+	// order events emitted after this notification.
+	ResumeToken string `json:"resumeToken"`
+	// ResyncRequired is set if the subscription could not be resumed because
+	// some of the order events emitted since the given resume token are no
+	// longer available. Subscribers should then resync their orderbook, e.g.
+	// via GetOrders.
+	ResyncRequired bool `json:"resyncRequired,omitempty"`
+	// OrderEvents are the order events. Empty for the first notification of a
+	// subscription unless it was resumed.
+	OrderEvents []*zeroex.OrderEvent `json:"orderEvents"`
+}
+
+// ContractEventFilter determines which contract events are sent to
+// subscribers of the `contractEvents` topic. Also used in the RPC interface.
+type ContractEventFilter struct {
+	// Addresses are the addresses of the contracts whose events should be
+	// sent. If empty, events emitted by any contract are sent.
+	Addresses []common.Address `json:"addresses,omitempty"`
+	// Kinds are the kinds of events which should be sent (e.g.
+	// "ExchangeFillEvent"). If empty, events of any kind are sent.
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+// Validate returns an error if the filter contains unknown event kinds.
+func (f ContractEventFilter) Validate() error {
+	for _, kind := range f.Kinds {
+		if !containsString(zeroex.ContractEventKinds, kind) {
+			return fmt.Errorf("unknown contract event kind: %q", kind)
+		}
+	}
+	return nil
+}
+
+// Matches returns whether the given contract event passes the filter.
+func (f ContractEventFilter) Matches(contractEvent *zeroex.ContractEvent) bool {
+	if len(f.Kinds) > 0 && !containsString(f.Kinds, contractEvent.Kind) {
+		return false
+	}
+	if len(f.Addresses) == 0 {
+		return true
+	}
+	for _, address := range f.Addresses {
+		if address == contractEvent.Address {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// BlockEventType is the type of a BlockEvent.
+type BlockEventType string
+
+// BlockEventType values
+const (
+	// BlockAdded is emitted when a block is added to Mesh's view of the chain.
+	BlockAdded BlockEventType = "added"
+	// BlockRemoved is emitted when a block is removed from Mesh's view of the
+	// chain because of a block re-org.
+	BlockRemoved BlockEventType = "removed"
+)
+
+// BlockEvent is emitted to subscribers of the `blocks` topic whenever a block
+// is added to or removed from the chain as seen by Mesh. Also used in the RPC
+// interface.
+type BlockEvent struct {
+	Type       BlockEventType `json:"type"`
+	Number     int            `json:"number"`
+	Hash       common.Hash    `json:"hash"`
+	ParentHash common.Hash    `json:"parentHash"`
+	Timestamp  time.Time      `json:"timestamp"`
+	// BaseFee is the base fee per gas of the block (EIP-1559), encoded as a
+	// decimal string. It is omitted for blocks mined before the London hard
+	// fork.
+	BaseFee string `json:"baseFee,omitempty"`
+	// NumLogs is the number of logs in the block which are relevant to Mesh.
+	NumLogs int `json:"numLogs"`
 }
 
 // OrderInfo represents an fillable order and how much it could be filled for.
@@ -62,21 +748,80 @@ type OrderInfo struct {
 	OrderHash                common.Hash         `json:"orderHash"`
 	SignedOrder              *zeroex.SignedOrder `json:"signedOrder"`
 	FillableTakerAssetAmount *big.Int            `json:"fillableTakerAssetAmount"`
+	// TransferSimulationFailed is true if simulating the transfer of the maker's
+	// assets failed when the order was added. It is only ever set when Mesh is
+	// configured with TRANSFER_SIMULATION_MODE=warn.
+	TransferSimulationFailed bool `json:"transferSimulationFailed,omitempty"`
+	// NotionalUSD is the approximate USD value of the remaining fillable
+	// portion of the order. It is nil if Mesh is not configured with a price
+	// oracle or if the price of the order's assets is unknown.
+	NotionalUSD *float64 `json:"notionalUSD,omitempty"`
+	// LastValidatedBlockNumber is the number of the block at which the order
+	// was last validated. It is nil if unknown.
+	LastValidatedBlockNumber *big.Int `json:"lastValidatedBlockNumber,omitempty"`
+	// LastValidatedBlockHash is the hash of the block at which the order was
+	// last validated. It is the zero hash if unknown.
+	LastValidatedBlockHash common.Hash `json:"lastValidatedBlockHash,omitempty"`
+	// LastValidationResult is the result of the last validation: "FILLABLE"
+	// or the code of the reason the order was rejected. It is empty if
+	// unknown.
+	LastValidationResult string `json:"lastValidationResult,omitempty"`
+	// IsTakerRestricted is true if the order has a non-null takerAddress, i.e.
+	// it can only be filled by a specific taker.
+	IsTakerRestricted bool `json:"isTakerRestricted,omitempty"`
+	// IsSenderRestricted is true if the order has a non-null senderAddress,
+	// i.e. it can only be submitted to the Exchange contract by a specific
+	// sender.
+	IsSenderRestricted bool `json:"isSenderRestricted,omitempty"`
+	// Metadata is the opaque metadata which was attached to the order when it
+	// was added via AddOrders.
+	Metadata json.RawMessage `json:"metadata,omitempty"`
 }
 
 type orderInfoJSON struct {
 	OrderHash                string              `json:"orderHash"`
 	SignedOrder              *zeroex.SignedOrder `json:"signedOrder"`
 	FillableTakerAssetAmount string              `json:"fillableTakerAssetAmount"`
+	TransferSimulationFailed bool                `json:"transferSimulationFailed"`
+	NotionalUSD              *float64            `json:"notionalUSD"`
+	LastValidatedBlockNumber *big.Int            `json:"lastValidatedBlockNumber"`
+	LastValidatedBlockHash   common.Hash         `json:"lastValidatedBlockHash"`
+	LastValidationResult     string              `json:"lastValidationResult"`
+	IsTakerRestricted        bool                `json:"isTakerRestricted"`
+	IsSenderRestricted       bool                `json:"isSenderRestricted"`
+	Metadata                 json.RawMessage     `json:"metadata"`
 }
 
 // MarshalJSON is a custom Marshaler for OrderInfo
 func (o OrderInfo) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]interface{}{
+	orderInfoJSON := map[string]interface{}{
 		"orderHash":                o.OrderHash.Hex(),
 		"signedOrder":              o.SignedOrder,
 		"fillableTakerAssetAmount": o.FillableTakerAssetAmount.String(),
-	})
+	}
+	if o.TransferSimulationFailed {
+		orderInfoJSON["transferSimulationFailed"] = true
+	}
+	if o.NotionalUSD != nil {
+		orderInfoJSON["notionalUSD"] = *o.NotionalUSD
+	}
+	if o.LastValidatedBlockNumber != nil {
+		orderInfoJSON["lastValidatedBlockNumber"] = o.LastValidatedBlockNumber
+		orderInfoJSON["lastValidatedBlockHash"] = o.LastValidatedBlockHash.Hex()
+	}
+	if o.LastValidationResult != "" {
+		orderInfoJSON["lastValidationResult"] = o.LastValidationResult
+	}
+	if o.IsTakerRestricted {
+		orderInfoJSON["isTakerRestricted"] = true
+	}
+	if o.IsSenderRestricted {
+		orderInfoJSON["isSenderRestricted"] = true
+	}
+	if len(o.Metadata) > 0 {
+		orderInfoJSON["metadata"] = o.Metadata
+	}
+	return json.Marshal(orderInfoJSON)
 }
 
 // UnmarshalJSON implements a custom JSON unmarshaller for the OrderEvent type
@@ -89,6 +834,16 @@ func (o *OrderInfo) UnmarshalJSON(data []byte) error {
 
 	o.OrderHash = common.HexToHash(orderInfoJSON.OrderHash)
 	o.SignedOrder = orderInfoJSON.SignedOrder
+	o.TransferSimulationFailed = orderInfoJSON.TransferSimulationFailed
+	o.NotionalUSD = orderInfoJSON.NotionalUSD
+	o.LastValidatedBlockNumber = orderInfoJSON.LastValidatedBlockNumber
+	o.LastValidatedBlockHash = orderInfoJSON.LastValidatedBlockHash
+	o.LastValidationResult = orderInfoJSON.LastValidationResult
+	o.IsTakerRestricted = orderInfoJSON.IsTakerRestricted
+	o.IsSenderRestricted = orderInfoJSON.IsSenderRestricted
+	if len(orderInfoJSON.Metadata) > 0 {
+		o.Metadata = orderInfoJSON.Metadata
+	}
 	var ok bool
 	o.FillableTakerAssetAmount, ok = math.ParseBig256(orderInfoJSON.FillableTakerAssetAmount)
 	if !ok {
@@ -96,3 +851,71 @@ func (o *OrderInfo) UnmarshalJSON(data []byte) error {
 	}
 	return nil
 }
+
+// GetOrderByHashResponse is the response of GetOrderByHash. It contains the
+// stored order along with everything Mesh knows about its lifecycle.
+type GetOrderByHashResponse struct {
+	OrderInfo *OrderInfo `json:"orderInfo"`
+	// IsRemoved is true if the order is no longer fillable and will be
+	// deleted soon.
+	IsRemoved bool `json:"isRemoved"`
+	IsPinned  bool `json:"isPinned"`
+	// LastUpdated is when the order was last validated or updated.
+	LastUpdated time.Time `json:"lastUpdated"`
+	// Provenance describes how the order was received. It is nil for orders
+	// stored by older versions of Mesh.
+	Provenance *OrderProvenance `json:"provenance,omitempty"`
+	// History contains the most recent state changes of the order, oldest
+	// first. It only covers the state changes since the node was started and
+	// is limited in length.
+	History []*OrderStateChange `json:"history"`
+}
+
+// OrderProvenance describes how an order was received.
+type OrderProvenance struct {
+	// Source is "api", "gossipsub" or "ordersync".
+	Source string `json:"source"`
+	// PeerID is the ID of the peer the order was received from. It is empty
+	// if the order was not received from a peer.
+	PeerID     string    `json:"peerID,omitempty"`
+	ReceivedAt time.Time `json:"receivedAt"`
+}
+
+// OrderStateChange is an entry in the state history of an order. It
+// corresponds to an order event.
+type OrderStateChange struct {
+	Timestamp                time.Time                 `json:"timestamp"`
+	EndState                 zeroex.OrderEventEndState `json:"endState"`
+	FillableTakerAssetAmount *big.Int                  `json:"fillableTakerAssetAmount"`
+}
+
+type orderStateChangeJSON struct {
+	Timestamp                time.Time                 `json:"timestamp"`
+	EndState                 zeroex.OrderEventEndState `json:"endState"`
+	FillableTakerAssetAmount string                    `json:"fillableTakerAssetAmount"`
+}
+
+// MarshalJSON implements a custom JSON marshaller for the OrderStateChange type
+func (c OrderStateChange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(orderStateChangeJSON{
+		Timestamp:                c.Timestamp,
+		EndState:                 c.EndState,
+		FillableTakerAssetAmount: c.FillableTakerAssetAmount.String(),
+	})
+}
+
+// UnmarshalJSON implements a custom JSON unmarshaller for the OrderStateChange type
+func (c *OrderStateChange) UnmarshalJSON(data []byte) error {
+	var changeJSON orderStateChangeJSON
+	if err := json.Unmarshal(data, &changeJSON); err != nil {
+		return err
+	}
+	c.Timestamp = changeJSON.Timestamp
+	c.EndState = changeJSON.EndState
+	var ok bool
+	c.FillableTakerAssetAmount, ok = math.ParseBig256(changeJSON.FillableTakerAssetAmount)
+	if !ok {
+		return errors.New("Invalid uint256 number encountered for FillableTakerAssetAmount")
+	}
+	return nil
+}
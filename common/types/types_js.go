@@ -4,6 +4,7 @@ package types
 
 import (
 	"encoding/json"
+	"strconv"
 	"syscall/js"
 )
 
@@ -25,20 +26,148 @@ func (l LatestBlock) JSValue() js.Value {
 	})
 }
 
+func (c CleanupStats) JSValue() js.Value {
+	value := map[string]interface{}{
+		"startTime":         c.StartTime.String(),
+		"duration":          int64(c.Duration),
+		"numOrdersChecked":  c.NumOrdersChecked,
+		"numOrdersDeferred": c.NumOrdersDeferred,
+		"numOrderEvents":    c.NumOrderEvents,
+	}
+	if c.Error != "" {
+		value["error"] = c.Error
+	}
+	return js.ValueOf(value)
+}
+
+func (r StartupRevalidationStats) JSValue() js.Value {
+	value := map[string]interface{}{
+		"startTime":            r.StartTime.String(),
+		"endTime":              r.EndTime.String(),
+		"numOrdersTotal":       r.NumOrdersTotal,
+		"numOrdersRevalidated": r.NumOrdersRevalidated,
+	}
+	if r.Error != "" {
+		value["error"] = r.Error
+	}
+	return js.ValueOf(value)
+}
+
+func (s SubscriptionStats) JSValue() js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"id":         s.ID,
+		"topic":      s.Topic,
+		"queueDepth": s.QueueDepth,
+		"queueSize":  s.QueueSize,
+		"numDropped": s.NumDropped,
+	})
+}
+
+func (r ReachabilityStats) JSValue() js.Value {
+	confirmedPublicAddrs := make([]interface{}, len(r.ConfirmedPublicAddrs))
+	for i, addr := range r.ConfirmedPublicAddrs {
+		confirmedPublicAddrs[i] = addr
+	}
+	advertisedPublicAddrs := make([]interface{}, len(r.AdvertisedPublicAddrs))
+	for i, addr := range r.AdvertisedPublicAddrs {
+		advertisedPublicAddrs[i] = addr
+	}
+	return js.ValueOf(map[string]interface{}{
+		"natStatus":             r.NATStatus,
+		"confirmedPublicAddrs":  confirmedPublicAddrs,
+		"advertisedPublicAddrs": advertisedPublicAddrs,
+	})
+}
+
+func (r RateLimitStats) JSValue() js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"perPeerLimitViolations": r.PerPeerLimitViolations,
+		"globalLimitViolations":  r.GlobalLimitViolations,
+		"oversizedMessages":      r.OversizedMessages,
+		"numBans":                r.NumBans,
+	})
+}
+
+func (s ShadowFilterStats) JSValue() js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"pubSubTopic":             s.PubSubTopic,
+		"since":                   s.Since.String(),
+		"numEvaluated":            s.NumEvaluated,
+		"numAcceptedByBoth":       s.NumAcceptedByBoth,
+		"numAcceptedOnlyByShadow": s.NumAcceptedOnlyByShadow,
+		"numRejectedOnlyByShadow": s.NumRejectedOnlyByShadow,
+		"numRejectedByBoth":       s.NumRejectedByBoth,
+	})
+}
+
+func (c ChainStats) JSValue() js.Value {
+	reorgsByDepth := make(map[string]interface{}, len(c.ReorgsByDepth))
+	for depth, count := range c.ReorgsByDepth {
+		reorgsByDepth[strconv.Itoa(depth)] = count
+	}
+	numLogsDecoded := make(map[string]interface{}, len(c.NumLogsDecoded))
+	for kind, count := range c.NumLogsDecoded {
+		numLogsDecoded[kind] = count
+	}
+	return js.ValueOf(map[string]interface{}{
+		"numBlocksProcessed":   c.NumBlocksProcessed,
+		"numReorgs":            c.NumReorgs,
+		"reorgsByDepth":        reorgsByDepth,
+		"numLogsDecoded":       numLogsDecoded,
+		"numOrdersRevalidated": c.NumOrdersRevalidated,
+		"numValidationCalls":   c.NumValidationCalls,
+		"lastBlock": map[string]interface{}{
+			"number":               c.LastBlock.Number,
+			"numLogsDecoded":       c.LastBlock.NumLogsDecoded,
+			"numOrdersRevalidated": c.LastBlock.NumOrdersRevalidated,
+			"numValidationCalls":   c.LastBlock.NumValidationCalls,
+		},
+	})
+}
+
+func (i IngestionLatencyStats) JSValue() js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"queue":      i.Queue.JSValue(),
+		"decoding":   i.Decoding.JSValue(),
+		"validation": i.Validation.JSValue(),
+		"emission":   i.Emission.JSValue(),
+		"total":      i.Total.JSValue(),
+	})
+}
+
+func (l LatencyStats) JSValue() js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"count": l.Count,
+		"p50":   int64(l.P50),
+		"p95":   int64(l.P95),
+		"p99":   int64(l.P99),
+	})
+}
+
 func (s Stats) JSValue() js.Value {
 	secondaryRendezvous := make([]interface{}, len(s.SecondaryRendezvous))
 	for i, rendezvousPoint := range s.SecondaryRendezvous {
 		secondaryRendezvous[i] = rendezvousPoint
 	}
-	return js.ValueOf(map[string]interface{}{
+	subscriptions := make([]interface{}, len(s.Subscriptions))
+	for i, subscription := range s.Subscriptions {
+		subscriptions[i] = subscription.JSValue()
+	}
+	peerLatencies := make(map[string]interface{}, len(s.PeerLatencies))
+	for peerID, latency := range s.PeerLatencies {
+		peerLatencies[peerID] = int64(latency)
+	}
+	value := map[string]interface{}{
 		"version":                           s.Version,
 		"pubSubTopic":                       s.PubSubTopic,
 		"rendezvous":                        s.Rendezvous,
 		"secondaryRendezvous":               secondaryRendezvous,
 		"peerID":                            s.PeerID,
 		"ethereumChainID":                   s.EthereumChainID,
+		"relayOnly":                         s.RelayOnly,
 		"latestBlock":                       s.LatestBlock.JSValue(),
 		"numPeers":                          s.NumPeers,
+		"peerLatencies":                     peerLatencies,
 		"numOrders":                         s.NumOrders,
 		"numOrdersIncludingRemoved":         s.NumOrdersIncludingRemoved,
 		"numPinnedOrders":                   s.NumPinnedOrders,
@@ -46,5 +175,18 @@ func (s Stats) JSValue() js.Value {
 		"startOfCurrentUTCDay":              s.StartOfCurrentUTCDay.String(),
 		"ethRPCRequestsSentInCurrentUTCDay": s.EthRPCRequestsSentInCurrentUTCDay,
 		"ethRPCRateLimitExpiredRequests":    s.EthRPCRateLimitExpiredRequests,
-	})
+		"numPendingValidation":              s.NumPendingValidation,
+		"rateLimiting":                      s.RateLimiting.JSValue(),
+		"signatureCacheHitRate":             s.SignatureCacheHitRate,
+		"lastCleanup":                       s.LastCleanup.JSValue(),
+		"startupRevalidation":               s.StartupRevalidation.JSValue(),
+		"reachability":                      s.Reachability.JSValue(),
+		"subscriptions":                     subscriptions,
+		"chain":                             s.Chain.JSValue(),
+		"ingestionLatency":                  s.IngestionLatency.JSValue(),
+	}
+	if s.ShadowFilter != nil {
+		value["shadowFilter"] = s.ShadowFilter.JSValue()
+	}
+	return js.ValueOf(value)
 }
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractEventFilter(t *testing.T) {
+	exchangeAddress := common.HexToAddress("0x48bacb9266a570d521063ef5dd96e61686dbe788")
+	tokenAddress := common.HexToAddress("0x1dc4c1cefef38a777b15aa20260a54e584b16c48")
+	fillEvent := &zeroex.ContractEvent{Address: exchangeAddress, Kind: "ExchangeFillEvent"}
+	transferEvent := &zeroex.ContractEvent{Address: tokenAddress, Kind: "ERC20TransferEvent"}
+
+	testCases := []struct {
+		name                  string
+		filter                ContractEventFilter
+		expectedFillMatch     bool
+		expectedTransferMatch bool
+	}{
+		{
+			name:                  "empty filter",
+			filter:                ContractEventFilter{},
+			expectedFillMatch:     true,
+			expectedTransferMatch: true,
+		},
+		{
+			name:                  "address",
+			filter:                ContractEventFilter{Addresses: []common.Address{tokenAddress}},
+			expectedFillMatch:     false,
+			expectedTransferMatch: true,
+		},
+		{
+			name:                  "kind",
+			filter:                ContractEventFilter{Kinds: []string{"ExchangeFillEvent", "ExchangeCancelEvent"}},
+			expectedFillMatch:     true,
+			expectedTransferMatch: false,
+		},
+		{
+			name: "address and kind",
+			filter: ContractEventFilter{
+				Addresses: []common.Address{exchangeAddress},
+				Kinds:     []string{"ERC20TransferEvent"},
+			},
+			expectedFillMatch:     false,
+			expectedTransferMatch: false,
+		},
+	}
+	for _, testCase := range testCases {
+		assert.NoError(t, testCase.filter.Validate(), testCase.name)
+		assert.Equal(t, testCase.expectedFillMatch, testCase.filter.Matches(fillEvent), testCase.name)
+		assert.Equal(t, testCase.expectedTransferMatch, testCase.filter.Matches(transferEvent), testCase.name)
+	}
+}
+
+func TestContractEventFilterValidate(t *testing.T) {
+	filter := ContractEventFilter{Kinds: []string{"ExchangeFillEvent", "NotAnEvent"}}
+	assert.Error(t, filter.Validate())
+}
+
+func TestOrderInfoMetadataJSON(t *testing.T) {
+	orderInfo := &OrderInfo{
+		OrderHash:                common.HexToHash("0x01"),
+		FillableTakerAssetAmount: big.NewInt(42),
+		Metadata:                 json.RawMessage(`{"clientOrderId":"abc-123"}`),
+	}
+	encoded, err := json.Marshal(orderInfo)
+	require.NoError(t, err)
+	var decoded OrderInfo
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.JSONEq(t, `{"clientOrderId":"abc-123"}`, string(decoded.Metadata))
+	assert.Equal(t, big.NewInt(42), decoded.FillableTakerAssetAmount)
+}
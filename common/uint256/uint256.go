@@ -0,0 +1,169 @@
+// Package uint256 implements a fixed-width unsigned 256-bit integer type. It
+// is used instead of *big.Int on hot paths such as decoding orders and
+// checking their fillable amounts during validation, where allocating many
+// short-lived big.Ints causes significant GC pressure. Values are converted to
+// *big.Int at API boundaries.
+package uint256
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// Int is an unsigned 256-bit integer. The zero value is 0. Words are stored in
+// little-endian order, i.e. Int[0] holds the least significant 64 bits.
+type Int [4]uint64
+
+// FromUint64 returns x as an Int.
+func FromUint64(x uint64) Int {
+	return Int{x}
+}
+
+// FromBig converts x to an Int. It returns false if x is nil, negative or
+// doesn't fit in 256 bits.
+func FromBig(x *big.Int) (Int, bool) {
+	var z Int
+	if x == nil || x.Sign() < 0 || x.BitLen() > 256 {
+		return z, false
+	}
+	words := x.Bits()
+	if bits.UintSize == 64 {
+		for i, word := range words {
+			z[i] = uint64(word)
+		}
+		return z, true
+	}
+	// On 32-bit platforms each big.Word only holds 32 bits.
+	for i, word := range words {
+		z[i/2] |= uint64(word) << (32 * uint(i%2))
+	}
+	return z, true
+}
+
+// ParseDecimal parses a decimal number without allocating. Leading zeroes are
+// allowed. It returns false if b is empty, contains anything other than the
+// digits 0-9 or represents a number that doesn't fit in 256 bits.
+func ParseDecimal(b []byte) (Int, bool) {
+	var z Int
+	if len(b) == 0 {
+		return z, false
+	}
+	// Digits are consumed in chunks of up to 19, which is the most that always
+	// fit in a uint64, so that only one multiplication is needed per chunk.
+	for len(b) > 0 {
+		n := len(b)
+		if n > maxChunkDigits {
+			n = maxChunkDigits
+		}
+		var chunk uint64
+		for _, c := range b[:n] {
+			if c < '0' || c > '9' {
+				return Int{}, false
+			}
+			chunk = chunk*10 + uint64(c-'0')
+		}
+		var overflow bool
+		z, overflow = z.mulAddSmall(powersOfTen[n], chunk)
+		if overflow {
+			return Int{}, false
+		}
+		b = b[n:]
+	}
+	return z, true
+}
+
+// maxChunkDigits is the number of decimal digits that always fit in a uint64.
+const maxChunkDigits = 19
+
+// powersOfTen[i] is 10^i.
+var powersOfTen = func() [maxChunkDigits + 1]uint64 {
+	var powers [maxChunkDigits + 1]uint64
+	powers[0] = 1
+	for i := 1; i < len(powers); i++ {
+		powers[i] = powers[i-1] * 10
+	}
+	return powers
+}()
+
+// mulAddSmall returns z*m + a and whether the result overflowed.
+func (z Int) mulAddSmall(m uint64, a uint64) (Int, bool) {
+	var result Int
+	carry := a
+	for i, word := range z {
+		hi, lo := bits.Mul64(word, m)
+		var c uint64
+		result[i], c = bits.Add64(lo, carry, 0)
+		carry = hi + c
+	}
+	return result, carry != 0
+}
+
+// ToBig returns z as a new *big.Int.
+func (z Int) ToBig() *big.Int {
+	if z.IsZero() {
+		// Match the representation of 0 used by math/big, which doesn't
+		// allocate.
+		return new(big.Int)
+	}
+	if bits.UintSize == 64 {
+		words := make([]big.Word, 4)
+		for i, word := range z {
+			words[i] = big.Word(word)
+		}
+		return new(big.Int).SetBits(words)
+	}
+	words := make([]big.Word, 8)
+	for i, word := range z {
+		words[2*i] = big.Word(word)
+		words[2*i+1] = big.Word(word >> 32)
+	}
+	return new(big.Int).SetBits(words)
+}
+
+// IsZero returns true if z is 0.
+func (z Int) IsZero() bool {
+	return z == Int{}
+}
+
+// IsUint64 returns true if z fits in a uint64.
+func (z Int) IsUint64() bool {
+	return z[1] == 0 && z[2] == 0 && z[3] == 0
+}
+
+// Uint64 returns the least significant 64 bits of z.
+func (z Int) Uint64() uint64 {
+	return z[0]
+}
+
+// Cmp compares z and x and returns -1 if z < x, 0 if z == x and +1 if z > x.
+func (z Int) Cmp(x Int) int {
+	for i := len(z) - 1; i >= 0; i-- {
+		switch {
+		case z[i] < x[i]:
+			return -1
+		case z[i] > x[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Add returns z + x and whether the addition overflowed.
+func (z Int) Add(x Int) (Int, bool) {
+	var result Int
+	var carry uint64
+	for i := range z {
+		result[i], carry = bits.Add64(z[i], x[i], carry)
+	}
+	return result, carry != 0
+}
+
+// Sub returns z - x and whether the subtraction underflowed (i.e. x > z).
+func (z Int) Sub(x Int) (Int, bool) {
+	var result Int
+	var borrow uint64
+	for i := range z {
+		result[i], borrow = bits.Sub64(z[i], x[i], borrow)
+	}
+	return result, borrow != 0
+}
@@ -0,0 +1,73 @@
+package uint256
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	testCases := []struct {
+		input     string
+		expectOK  bool
+		expectBig *big.Int
+	}{
+		{input: "0", expectOK: true, expectBig: big.NewInt(0)},
+		{input: "000123", expectOK: true, expectBig: big.NewInt(123)},
+		{input: "1234567890123456789012", expectOK: true, expectBig: new(big.Int).Add(new(big.Int).Mul(big.NewInt(1234567890123456789), big.NewInt(1000)), big.NewInt(12))},
+		{input: "18446744073709551616", expectOK: true, expectBig: new(big.Int).Lsh(big.NewInt(1), 64)},
+		{input: math.MaxBig256.String(), expectOK: true, expectBig: math.MaxBig256},
+		{input: new(big.Int).Add(math.MaxBig256, big.NewInt(1)).String(), expectOK: false},
+		{input: "", expectOK: false},
+		{input: "-1", expectOK: false},
+		{input: "0x10", expectOK: false},
+		{input: "12a", expectOK: false},
+		{input: "1234567890123456789012a", expectOK: false},
+	}
+	for _, tc := range testCases {
+		value, ok := ParseDecimal([]byte(tc.input))
+		require.Equal(t, tc.expectOK, ok, tc.input)
+		if tc.expectOK {
+			assert.Equal(t, tc.expectBig, value.ToBig(), tc.input)
+		}
+	}
+}
+
+func TestFromBigToBig(t *testing.T) {
+	for _, x := range []*big.Int{big.NewInt(0), big.NewInt(42), new(big.Int).Lsh(big.NewInt(3), 130), math.MaxBig256} {
+		value, ok := FromBig(x)
+		require.True(t, ok, x.String())
+		assert.Equal(t, x, value.ToBig())
+	}
+	for _, x := range []*big.Int{nil, big.NewInt(-1), new(big.Int).Lsh(big.NewInt(1), 256)} {
+		_, ok := FromBig(x)
+		assert.False(t, ok)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	maxValue, ok := FromBig(math.MaxBig256)
+	require.True(t, ok)
+	one := FromUint64(1)
+
+	sum, overflow := maxValue.Add(one)
+	assert.True(t, overflow)
+	assert.True(t, sum.IsZero())
+
+	difference, underflow := Int{}.Sub(one)
+	assert.True(t, underflow)
+	assert.Equal(t, maxValue, difference)
+
+	x, _ := FromBig(new(big.Int).Lsh(big.NewInt(1), 64))
+	difference, underflow = x.Sub(one)
+	assert.False(t, underflow)
+	assert.True(t, difference.IsUint64())
+	assert.Equal(t, uint64(1<<64-1), difference.Uint64())
+
+	assert.Equal(t, -1, one.Cmp(x))
+	assert.Equal(t, 1, x.Cmp(one))
+	assert.Equal(t, 0, x.Cmp(x))
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xProject/0x-mesh/alerting"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// alertCheckInterval is how often alert conditions are checked.
+	alertCheckInterval = 30 * time.Second
+	// alertHoldFor is how long the low peer count, storage and block lag
+	// conditions must hold before an alert fires.
+	alertHoldFor = 2 * time.Minute
+	// alertEthRPCTimeout is the maximum amount of time to wait for the latest
+	// block header when checking whether the Ethereum RPC endpoint is
+	// reachable.
+	alertEthRPCTimeout = 10 * time.Second
+)
+
+// newAlertMonitor returns a Monitor for the alert conditions enabled in the
+// given config or nil if none are enabled.
+func newAlertMonitor(config Config) (*alerting.Monitor, error) {
+	if config.AlertEthRPCUnreachableFor < 0 {
+		return nil, errors.New("ALERT_ETH_RPC_UNREACHABLE_FOR cannot be negative")
+	}
+	if config.AlertMinPeers < 0 {
+		return nil, errors.New("ALERT_MIN_PEERS cannot be negative")
+	}
+	if config.AlertStoragePercent < 0 || config.AlertStoragePercent > 100 {
+		return nil, errors.New("ALERT_STORAGE_PERCENT must be between 0 and 100")
+	}
+	if config.AlertMaxBlockLag < 0 {
+		return nil, errors.New("ALERT_MAX_BLOCK_LAG cannot be negative")
+	}
+	thresholds := alerting.Thresholds{
+		EthRPCUnreachableFor: config.AlertEthRPCUnreachableFor,
+		MinPeers:             config.AlertMinPeers,
+		StoragePercent:       config.AlertStoragePercent,
+		MaxBlockLag:          config.AlertMaxBlockLag,
+		HoldFor:              alertHoldFor,
+	}
+	if !thresholds.Enabled() {
+		return nil, nil
+	}
+	return alerting.NewMonitor(thresholds), nil
+}
+
+// periodicallyCheckAlerts checks the alert conditions every
+// alertCheckInterval until the given context is canceled. Alerts are logged
+// and sent to the webhook, if configured.
+func (app *App) periodicallyCheckAlerts(ctx context.Context) {
+	<-app.started
+
+	ticker := time.NewTicker(alertCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, alert := range app.alertMonitor.Check(app.alertReadings(ctx)) {
+			app.handleAlert(ctx, alert)
+		}
+	}
+}
+
+// alertReadings returns the current values that alert conditions are checked
+// against.
+func (app *App) alertReadings(ctx context.Context) alerting.Readings {
+	readings := alerting.Readings{
+		Time:            time.Now(),
+		EthRPCReachable: true,
+		NumPeers:        app.node.GetNumPeers(),
+		MaxOrders:       app.config.MaxOrdersInStorage,
+	}
+	numOrders, err := app.db.Orders.Count()
+	if err != nil {
+		log.WithError(err).Warn("could not count orders for alerts")
+	}
+	readings.NumOrders = numOrders
+
+	// Only send requests to the Ethereum RPC endpoint if they are needed, since
+	// they count towards the daily limit.
+	if app.config.AlertEthRPCUnreachableFor == 0 && app.config.AlertMaxBlockLag == 0 {
+		return readings
+	}
+	headerCtx, cancel := context.WithTimeout(ctx, alertEthRPCTimeout)
+	defer cancel()
+	latestBlock, err := app.ethRPCClient.HeaderByNumber(headerCtx, nil)
+	if err != nil {
+		readings.EthRPCReachable = false
+		return readings
+	}
+	latestBlockStored, err := app.db.FindLatestMiniHeader()
+	if err != nil {
+		log.WithError(err).Warn("could not find latest block for alerts")
+		return readings
+	}
+	readings.BlockLag = int(latestBlock.Number.Int64() - latestBlockStored.Number.Int64())
+	return readings
+}
+
+// handleAlert logs the given alert and sends it to the webhook, if configured.
+func (app *App) handleAlert(ctx context.Context, alert *alerting.Alert) {
+	logger := log.WithFields(log.Fields{
+		"condition": alert.Condition,
+		"state":     alert.State,
+		"message":   alert.Message,
+	})
+	if alert.State == alerting.StateFiring {
+		logger.Error("alert fired")
+	} else {
+		logger.Info("alert resolved")
+	}
+	if app.alertWebhook == nil {
+		return
+	}
+	if err := app.alertWebhook.Notify(ctx, alert); err != nil {
+		log.WithError(err).WithField("condition", alert.Condition).Error("could not send alert to webhook")
+	}
+}
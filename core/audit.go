@@ -0,0 +1,131 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/meshdb"
+	log "github.com/sirupsen/logrus"
+)
+
+// auditLogPruneInterval is how often records older than
+// AuditLogRetentionPeriod are deleted from the audit log.
+const auditLogPruneInterval = 1 * time.Hour
+
+// ErrAuditLogDisabled is returned when the audit log is accessed even though
+// it was not enabled via ENABLE_AUDIT_LOG.
+var ErrAuditLogDisabled = errors.New("audit log is disabled (set ENABLE_AUDIT_LOG to enable it)")
+
+// RecordAudit stores the given record in the audit log. It does nothing if the
+// audit log is disabled.
+func (app *App) RecordAudit(record *types.AuditRecord) error {
+	if !app.config.EnableAuditLog {
+		return nil
+	}
+	return app.db.InsertAuditRecord(&meshdb.AuditRecord{
+		Time:         record.Time,
+		Method:       record.Method,
+		Caller:       record.Caller,
+		ParamsDigest: record.ParamsDigest,
+		Success:      record.Success,
+		Error:        record.Error,
+		Result:       record.Result,
+	})
+}
+
+// GetAuditLog returns all audit records created at or after the given time in
+// chronological order. If since is the zero time, all records are returned.
+func (app *App) GetAuditLog(since time.Time) ([]*types.AuditRecord, error) {
+	if !app.config.EnableAuditLog {
+		return nil, ErrAuditLogDisabled
+	}
+	if since.IsZero() {
+		since = time.Unix(0, 0)
+	}
+	dbRecords, err := app.db.FindAuditRecordsSince(since)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*types.AuditRecord, len(dbRecords))
+	for i, dbRecord := range dbRecords {
+		records[i] = &types.AuditRecord{
+			Time:         dbRecord.Time,
+			Method:       dbRecord.Method,
+			Caller:       dbRecord.Caller,
+			ParamsDigest: dbRecord.ParamsDigest,
+			Success:      dbRecord.Success,
+			Error:        dbRecord.Error,
+			Result:       dbRecord.Result,
+		}
+	}
+	return records, nil
+}
+
+// ExportAuditLog writes the entire audit log to the file at AuditLogExportPath,
+// overwriting it if it already exists. Each line of the file is a JSON-encoded
+// audit record.
+func (app *App) ExportAuditLog() (*types.ExportAuditLogResponse, error) {
+	records, err := app.GetAuditLog(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+	path := app.auditLogExportPath()
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return nil, err
+	}
+	return &types.ExportAuditLogResponse{
+		Path:       path,
+		NumRecords: len(records),
+	}, nil
+}
+
+func (app *App) auditLogExportPath() string {
+	if app.config.AuditLogExportPath != "" {
+		return app.config.AuditLogExportPath
+	}
+	return filepath.Join(app.config.DataDir, "audit_log.jsonl")
+}
+
+// periodicallyPruneAuditLog deletes records older than AuditLogRetentionPeriod
+// from the audit log every auditLogPruneInterval until the given context is
+// canceled.
+func (app *App) periodicallyPruneAuditLog(ctx context.Context) {
+	ticker := time.NewTicker(auditLogPruneInterval)
+	defer ticker.Stop()
+	for {
+		if err := app.pruneAuditLog(time.Now()); err != nil {
+			log.WithError(err).Error("could not prune audit log")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pruneAuditLog deletes records which are older than AuditLogRetentionPeriod
+// at the given time from the audit log.
+func (app *App) pruneAuditLog(now time.Time) error {
+	numDeleted, err := app.db.DeleteAuditRecordsBefore(now.Add(-app.config.AuditLogRetentionPeriod))
+	if err != nil {
+		return err
+	}
+	if numDeleted > 0 {
+		log.WithField("numRecordsDeleted", numDeleted).Debug("pruned audit log")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xProject/0x-mesh/common/types"
+)
+
+// maxBackfillBlockRange is the maximum number of blocks that can be scanned by
+// a single call to BackfillOrderEvents. Logs are requested in chunks of a few
+// dozen blocks, so larger ranges would use up a lot of the Ethereum RPC
+// request budget.
+const maxBackfillBlockRange = 20000
+
+// ErrInvalidBackfillOrderEventsOpts is the error returned when a
+// BackfillOrderEvents request has invalid options.
+type ErrInvalidBackfillOrderEventsOpts struct {
+	reason string
+}
+
+func (e ErrInvalidBackfillOrderEventsOpts) Error() string {
+	return fmt.Sprintf("invalid backfillOrderEvents options: %s", e.reason)
+}
+
+// BackfillOrderEvents scans the Exchange logs of the given makers in the given
+// block range for fills and cancellations of stored orders which may have been
+// missed, e.g. during downtime, and emits corrective order events for all
+// orders whose state changed. If opts.ToBlock is 0, the range ends at the
+// latest block processed by Mesh.
+func (app *App) BackfillOrderEvents(ctx context.Context, opts types.BackfillOrderEventsOpts) (*types.BackfillOrderEventsResponse, error) {
+	<-app.started
+
+	if app.config.RelayOnly {
+		return nil, ErrRelayOnly
+	}
+	if len(opts.MakerAddresses) == 0 {
+		return nil, ErrInvalidBackfillOrderEventsOpts{reason: "makerAddresses cannot be empty"}
+	}
+	latestBlock, err := app.db.FindLatestMiniHeader()
+	if err != nil {
+		return nil, err
+	}
+	latestBlockNumber := latestBlock.Number.Int64()
+	toBlock := opts.ToBlock
+	if toBlock == 0 {
+		toBlock = latestBlockNumber
+	}
+	if opts.FromBlock < 0 || toBlock < opts.FromBlock {
+		return nil, ErrInvalidBackfillOrderEventsOpts{reason: fmt.Sprintf("invalid block range %d-%d", opts.FromBlock, toBlock)}
+	}
+	if toBlock > latestBlockNumber {
+		return nil, ErrInvalidBackfillOrderEventsOpts{reason: fmt.Sprintf("toBlock %d has not been processed yet (latest block: %d)", toBlock, latestBlockNumber)}
+	}
+	if toBlock-opts.FromBlock+1 > maxBackfillBlockRange {
+		return nil, ErrInvalidBackfillOrderEventsOpts{reason: fmt.Sprintf("block range cannot span more than %d blocks", maxBackfillBlockRange)}
+	}
+
+	result, err := app.orderWatcher.BackfillOrderEvents(ctx, opts.MakerAddresses, int(opts.FromBlock), int(toBlock))
+	if err != nil {
+		return nil, err
+	}
+	return &types.BackfillOrderEventsResponse{
+		FromBlock:        opts.FromBlock,
+		ToBlock:          toBlock,
+		NumLogs:          result.NumLogs,
+		NumOrdersChecked: result.NumOrdersChecked,
+		NumOrderEvents:   result.NumOrderEvents,
+	}, nil
+}
@@ -0,0 +1,48 @@
+// +build !js
+
+package core
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/ethereum/miniheader"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillOrderEventsInvalidOpts(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+	require.NoError(t, meshDB.MiniHeaders.Insert(&miniheader.MiniHeader{
+		Hash:   common.HexToHash("0x1"),
+		Number: big.NewInt(50000),
+	}))
+	app := &App{
+		db:      meshDB,
+		started: make(chan struct{}),
+	}
+	close(app.started)
+
+	makerAddresses := []common.Address{constants.GanacheAccount0}
+	testCases := []types.BackfillOrderEventsOpts{
+		{FromBlock: 100, ToBlock: 200},
+		{MakerAddresses: makerAddresses, FromBlock: -1, ToBlock: 200},
+		{MakerAddresses: makerAddresses, FromBlock: 200, ToBlock: 100},
+		{MakerAddresses: makerAddresses, FromBlock: 49000, ToBlock: 50001},
+		{MakerAddresses: makerAddresses, FromBlock: 1000, ToBlock: 1000 + maxBackfillBlockRange},
+		// ToBlock defaults to the latest block.
+		{MakerAddresses: makerAddresses, FromBlock: 50001},
+	}
+	for i, opts := range testCases {
+		_, err := app.BackfillOrderEvents(context.Background(), opts)
+		assert.IsType(t, ErrInvalidBackfillOrderEventsOpts{}, err, "test case %d", i)
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/zeroex"
+	log "github.com/sirupsen/logrus"
+)
+
+// switchChain prepares a database which was created for a different chain for
+// use with the chain with the given ID. The signed orders of the previous chain
+// are written to a file in archiveDir, which can be imported into a node on
+// that chain with "mesh orders add". Afterwards, all orders, mini headers and
+// pending order events are removed from the database and the metadata is reset
+// for the new chain.
+// If switchChain is interrupted, it can safely be called again.
+func switchChain(chainID int, meshDB *meshdb.MeshDB, metadata *meshdb.Metadata, archiveDir string) (*meshdb.Metadata, error) {
+	var orders []*meshdb.Order
+	if err := meshDB.Orders.FindAll(&orders); err != nil {
+		return nil, err
+	}
+	if len(orders) > 0 {
+		signedOrders := make([]*zeroex.SignedOrder, len(orders))
+		for i, order := range orders {
+			signedOrders[i] = order.SignedOrder
+		}
+		encodedOrders, err := json.MarshalIndent(signedOrders, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(archiveDir, os.ModePerm); err != nil {
+			return nil, err
+		}
+		archivePath := filepath.Join(archiveDir, fmt.Sprintf("orders_chain_%d_%s.json", metadata.EthereumChainID, time.Now().UTC().Format("20060102T150405Z")))
+		if err := ioutil.WriteFile(archivePath, encodedOrders, 0644); err != nil {
+			return nil, err
+		}
+		log.WithFields(log.Fields{
+			"previousChainID": metadata.EthereumChainID,
+			"chainID":         chainID,
+			"numOrders":       len(orders),
+			"archivePath":     archivePath,
+		}).Warn("archived the orders of the previous chain")
+	}
+	if err := meshDB.ClearAllOrders(); err != nil {
+		return nil, err
+	}
+	if err := meshDB.ClearAllMiniHeaders(); err != nil {
+		return nil, err
+	}
+	if err := meshDB.ClearAllPendingOrderEvents(); err != nil {
+		return nil, err
+	}
+
+	var newMetadata meshdb.Metadata
+	if err := meshDB.UpdateMetadata(func(oldMetadata meshdb.Metadata) meshdb.Metadata {
+		newMetadata = oldMetadata
+		newMetadata.EthereumChainID = chainID
+		newMetadata.MaxExpirationTime = constants.UnlimitedExpirationTime
+		return newMetadata
+	}); err != nil {
+		return nil, err
+	}
+	log.WithFields(log.Fields{
+		"previousChainID": metadata.EthereumChainID,
+		"chainID":         chainID,
+	}).Warn("switched the database to a different chain")
+	return &newMetadata, nil
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -13,6 +15,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/0xProject/0x-mesh/alerting"
+	"github.com/0xProject/0x-mesh/common/addressformat"
 	"github.com/0xProject/0x-mesh/common/types"
 	"github.com/0xProject/0x-mesh/constants"
 	"github.com/0xProject/0x-mesh/core/ordersync"
@@ -20,24 +24,30 @@ import (
 	"github.com/0xProject/0x-mesh/encoding"
 	"github.com/0xProject/0x-mesh/ethereum"
 	"github.com/0xProject/0x-mesh/ethereum/blockwatch"
+	"github.com/0xProject/0x-mesh/ethereum/ens"
 	"github.com/0xProject/0x-mesh/ethereum/ethrpcclient"
+	"github.com/0xProject/0x-mesh/ethereum/multicall"
 	"github.com/0xProject/0x-mesh/ethereum/ratelimit"
 	"github.com/0xProject/0x-mesh/ethereum/simplestack"
 	"github.com/0xProject/0x-mesh/expirationwatch"
 	"github.com/0xProject/0x-mesh/keys"
 	"github.com/0xProject/0x-mesh/loghooks"
 	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/orderbooksnapshot"
 	"github.com/0xProject/0x-mesh/orderfilter"
 	"github.com/0xProject/0x-mesh/p2p"
+	"github.com/0xProject/0x-mesh/p2p/ratevalidator"
+	"github.com/0xProject/0x-mesh/workerpool"
 	"github.com/0xProject/0x-mesh/zeroex"
 	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
 	"github.com/0xProject/0x-mesh/zeroex/orderwatch"
 	"github.com/albrow/stringset"
 	"github.com/benbjohnson/clock"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/event"
-	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/google/uuid"
 	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
 	peer "github.com/libp2p/go-libp2p-core/peer"
@@ -50,8 +60,14 @@ const (
 	ethereumRPCRequestTimeout     = 30 * time.Second
 	peerConnectTimeout            = 60 * time.Second
 	checkNewAddrInterval          = 20 * time.Second
-	expirationPollingInterval     = 50 * time.Millisecond
 	rateLimiterCheckpointInterval = 1 * time.Minute
+	// ethereumRPCRetryMinBackoff and ethereumRPCRetryMaxBackoff bound the delay
+	// between retries of failed Ethereum RPC requests.
+	ethereumRPCRetryMinBackoff = 250 * time.Millisecond
+	ethereumRPCRetryMaxBackoff = 8 * time.Second
+	// ethereumRPCRateLimitedBackoffFactor multiplies the delay before retrying
+	// requests which were rate limited by the provider.
+	ethereumRPCRateLimitedBackoffFactor = 4
 	// estimatedNonPollingEthereumRPCRequestsPer24Hrs is an estimate of the
 	// minimum number of RPC requests Mesh needs to send (not including block
 	// polling). It's based on real-world data from a mainnet Mesh node. This
@@ -90,6 +106,45 @@ func defaultPrivateConfig() privateConfig {
 type Config struct {
 	// Verbosity is the logging verbosity: 0=panic, 1=fatal, 2=error, 3=warn, 4=info, 5=debug 6=trace
 	Verbosity int `envvar:"VERBOSITY" default:"2"`
+	// LogLevels is a comma-separated list of subsystem=level pairs which
+	// override Verbosity for specific subsystems, e.g. "p2p=warn,orderwatch=debug".
+	// Levels are panic, fatal, error, warn, info, debug or trace. Subsystems
+	// include p2p, core, ordersync, orderwatch, ordervalidator, blockwatch,
+	// ethrpc and rpc. Setting LogLevels adds the subsystem to each log entry.
+	// This requires looking up the caller of each log entry, which makes
+	// logging slower.
+	LogLevels string `envvar:"LOG_LEVELS" default:""`
+	// LogFormat is the format of log output. Can be "json" (the default), which
+	// is suitable for log aggregation, or "console", which is human-readable.
+	LogFormat string `envvar:"LOG_FORMAT" default:"json"`
+	// LogSamplingInitial is the number of log entries with the same message
+	// that are logged per second at info level or more verbose before sampling
+	// kicks in. This reduces the volume of high-frequency messages such as
+	// those for each order received from peers. Zero disables sampling.
+	LogSamplingInitial int `envvar:"LOG_SAMPLING_INITIAL" default:"0"`
+	// LogSamplingThereafter determines which log entries are logged once
+	// LogSamplingInitial has been reached within a second: every
+	// LogSamplingThereafter'th entry with the same message is logged and the
+	// rest are dropped.
+	LogSamplingThereafter int `envvar:"LOG_SAMPLING_THEREAFTER" default:"100"`
+	// LogShippingTarget is the type of external log store that logs are shipped
+	// to in addition to being written to stdout. Can be "elasticsearch", "loki"
+	// or "" (the default), which disables log shipping. Logs are buffered and
+	// shipped asynchronously in batches, so logging never blocks on the log
+	// store. Buffered logs are shipped before Mesh exits. LogLevels and
+	// sampling apply to shipped logs just like to logs written to stdout.
+	LogShippingTarget string `envvar:"LOG_SHIPPING_TARGET" default:""`
+	// LogShippingURL is the base URL of the log store, e.g.
+	// "http://localhost:9200" for Elasticsearch or "http://localhost:3100" for
+	// Loki.
+	LogShippingURL string `envvar:"LOG_SHIPPING_URL" default:""`
+	// LogShippingIndex is the Elasticsearch index that logs are added to. For
+	// Loki, it is used as the value of the "job" label.
+	LogShippingIndex string `envvar:"LOG_SHIPPING_INDEX" default:"0x-mesh"`
+	// LogShippingBufferSize is the maximum number of log entries buffered in
+	// memory while waiting to be shipped. If the log store is slow or
+	// unreachable and the buffer fills up, new log entries are not shipped.
+	LogShippingBufferSize int `envvar:"LOG_SHIPPING_BUFFER_SIZE" default:"10000"`
 	// DataDir is the directory to use for persisting all data, including the
 	// database and private key files.
 	DataDir string `envvar:"DATA_DIR" default:"0x_mesh"`
@@ -99,20 +154,125 @@ type Config struct {
 	// P2PWebSocketsPort is the port on which to listen for new WebSockets
 	// connections from peers in the network. Set to 60559 by default.
 	P2PWebSocketsPort int `envvar:"P2P_WEBSOCKETS_PORT" default:"60559"`
+	// P2PListenAddrs is a comma-separated list of multiaddresses to listen on
+	// for connections from peers, e.g. "/ip6/::/tcp/60558,/ip6/::/tcp/60559/ws"
+	// for an IPv6-only deployment. WebSockets addresses end in /ws. If empty,
+	// Mesh listens on all IPv4 and IPv6 interfaces at P2PTCPPort and
+	// P2PWebSocketsPort. Mesh advertises the public IP address of each IP
+	// version it listens on all interfaces of, as far as it can be determined.
+	P2PListenAddrs string `envvar:"P2P_LISTEN_ADDRS" default:""`
+	// ProxyURL is the URL of a SOCKS5 proxy (e.g. "socks5://127.0.0.1:9050"
+	// for a local Tor daemon) through which all outgoing connections to peers
+	// and to the Ethereum RPC endpoint are made. Host names are resolved by
+	// the proxy. While a proxy is configured, WebSockets connections to peers
+	// are disabled, the public IP address of the node is not advertised and
+	// WebSocket Ethereum RPC URLs are not supported. If empty, no proxy is
+	// used.
+	ProxyURL string `envvar:"PROXY_URL" default:""`
+	// P2PSecurityTransports is a comma-separated list of the security
+	// transports used to encrypt connections to peers, in order of
+	// preference. Supported values are "tls" (TLS 1.3) and "secio". If empty,
+	// "secio,tls" is used. "noise" is not supported until go-libp2p is
+	// upgraded and is rejected.
+	P2PSecurityTransports string `envvar:"P2P_SECURITY_TRANSPORTS" default:""`
+	// P2PMinSecurityLevel is the minimum acceptable security level of
+	// connections to peers. If set to "modern", only TLS 1.3 handshakes are
+	// accepted and secio is disabled even if it is included in
+	// P2PSecurityTransports. Set to "legacy" by default.
+	P2PMinSecurityLevel string `envvar:"P2P_MIN_SECURITY_LEVEL" default:"legacy"`
 	// EthereumRPCURL is the URL of an Etheruem node which supports the JSON RPC
-	// API.
+	// API. HTTP(S) (e.g. "https://mainnet.infura.io/v3/..."), WebSocket
+	// (e.g. "wss://...") and IPC (e.g. "ipc:///home/user/.ethereum/geth.ipc"
+	// or just the path of the IPC socket) endpoints are supported. IPC offers
+	// the lowest latency for nodes running on the same machine as the Ethereum
+	// node. It is not required in relay-only mode.
 	EthereumRPCURL string `envvar:"ETHEREUM_RPC_URL" json:"-"`
+	// RelayOnly makes Mesh run without an Ethereum RPC endpoint. Orders are
+	// still shared via GossipSub and ordersync, but they are only checked
+	// against the JSON schema, the Mesh-specific criteria and their EIP712 or
+	// EthSign signatures, and they are removed once they expire according to
+	// the local clock. Orders whose fillability has changed on-chain (e.g.
+	// because they were filled or cancelled) are not removed, orders with
+	// signatures that can only be validated on-chain are rejected and
+	// features which depend on Ethereum (e.g. mesh_validateOrders,
+	// mesh_simulateFill, mesh_backfillOrderEvents, ENS names on the maker
+	// lists and orderbook snapshots) are unavailable. Peers are told that the
+	// node runs in relay-only mode via its user agent.
+	RelayOnly bool `envvar:"RELAY_ONLY" default:"false"`
 	// EthereumChainID is the chain ID specifying which Ethereum chain you wish to
 	// run your Mesh node for
 	EthereumChainID int `envvar:"ETHEREUM_CHAIN_ID"`
+	// ForceChainSwitch allows Mesh to start with a database which was created
+	// for a different chain than EthereumChainID. The orders in the database
+	// are archived to a JSON file in the archive directory inside DataDir and
+	// removed from the database before the node starts on the new chain.
+	ForceChainSwitch bool `envvar:"FORCE_CHAIN_SWITCH" default:"false"`
 	// UseBootstrapList is whether to bootstrap the DHT by connecting to a
 	// specific set of peers.
 	UseBootstrapList bool `envvar:"USE_BOOTSTRAP_LIST" default:"true"`
 	// BootstrapList is a comma-separated list of multiaddresses to use for
 	// bootstrapping the DHT (e.g.,
 	// "/ip4/3.214.190.67/tcp/60558/ipfs/16Uiu2HAmGx8Z6gdq5T5AQE54GMtqDhDFhizywTy1o28NJbAMMumF").
-	// If empty, the default bootstrap list will be used.
+	// If empty, the default bootstrap list will be used. Addresses may also
+	// start with /dnsaddr (e.g. "/dnsaddr/bootstrap.example.org"), in which
+	// case the addresses of the bootstrap peers are looked up in the TXT
+	// records of the domain.
 	BootstrapList string `envvar:"BOOTSTRAP_LIST" default:""`
+	// BootstrapListURL is an optional HTTPS URL from which a signed bootstrap
+	// list is fetched on startup and every BootstrapListRefreshInterval. This
+	// allows the set of bootstrap peers to change without releasing a new
+	// version of Mesh. If the list can't be fetched, BootstrapList is used.
+	BootstrapListURL string `envvar:"BOOTSTRAP_LIST_URL" default:""`
+	// BootstrapListSigner is the peer ID whose private key must have signed
+	// the list fetched from BootstrapListURL. Lists with an invalid signature
+	// and lists which are older than the last fetched list (even before a
+	// restart) are ignored. It is required if BootstrapListURL is set.
+	BootstrapListSigner string `envvar:"BOOTSTRAP_LIST_SIGNER" default:""`
+	// BootstrapListRefreshInterval is how often the bootstrap list is fetched
+	// from BootstrapListURL.
+	BootstrapListRefreshInterval time.Duration `envvar:"BOOTSTRAP_LIST_REFRESH_INTERVAL" default:"1h"`
+	// MinPeerGroups is the minimum number of different groups of peers Mesh
+	// tries to stay connected to, which makes the node more resistant to
+	// network partitions. Peers are grouped by autonomous system if
+	// ASNDatabasePath is set and by IPv4 /16 or IPv6 /32 subnet otherwise. One
+	// peer in each of MinPeerGroups groups is never disconnected by the
+	// connection manager, and Mesh keeps looking for peers in new groups until
+	// there are enough groups. If 0, the diversity of peers is not considered.
+	MinPeerGroups int `envvar:"MIN_PEER_GROUPS" default:"0"`
+	// ASNDatabasePath is the path of an offline IP to ASN database used to
+	// group peers by autonomous system. It must use the tab-separated format of
+	// the databases published at https://iptoasn.com (e.g. ip2asn-combined.tsv).
+	// If empty, peers are grouped by subnet.
+	ASNDatabasePath string `envvar:"ASN_DATABASE_PATH" default:""`
+	// EnablePeerExchange determines whether Mesh asks newly connected peers
+	// for other peers which share orders on the same topic and connects to
+	// them. This helps nodes with a custom order filter, which are subscribed
+	// to topics with few other peers, find each other much faster than via the
+	// DHT alone.
+	EnablePeerExchange bool `envvar:"ENABLE_PEER_EXCHANGE" default:"true"`
+	// EnableNATPortMap determines whether Mesh tries to forward
+	// P2PTCPPort and P2PWebSocketsPort on the router via UPnP or NAT-PMP, so
+	// that nodes running behind a home or office router can be dialed by
+	// other peers. Whether this worked is reported by getStats.
+	EnableNATPortMap bool `envvar:"ENABLE_NAT_PORT_MAP" default:"true"`
+	// EnableRelayService makes Mesh additionally act as a bootstrap node and
+	// relay, so that operators can contribute infrastructure to the network
+	// without running the separate mesh-bootstrap binary. The node relays
+	// connections for peers which can't be dialed directly, answers AutoNAT
+	// dial-back requests and keeps its DHT routing table refreshed. Orders are
+	// processed as usual. The node must be reachable from the public internet
+	// for this to be useful.
+	EnableRelayService bool `envvar:"ENABLE_RELAY_SERVICE" default:"false"`
+	// MaxRelayStreams is the maximum number of open circuit relay streams if
+	// EnableRelayService is true. Each relayed connection uses two streams.
+	// Streams above the limit are reset. If 0, the number of streams is not
+	// limited.
+	MaxRelayStreams int `envvar:"MAX_RELAY_STREAMS" default:"200"`
+	// MaxRelayBytesPerSecond is the maximum bandwidth in bytes per second used
+	// for relaying connections if EnableRelayService is true. If the limit is
+	// exceeded, all relayed connections are reset. If 0, the bandwidth is not
+	// limited. Defaults to 5 MiB.
+	MaxRelayBytesPerSecond float64 `envvar:"MAX_RELAY_BYTES_PER_SECOND" default:"5242880"`
 	// BlockPollingInterval is the polling interval to wait before checking for a new Ethereum block
 	// that might contain transactions that impact the fillability of orders stored by Mesh. Different
 	// chains have different block producing intervals: POW chains are typically slower (e.g., Mainnet)
@@ -130,26 +290,77 @@ type Config struct {
 	// Disabling Ethereum RPC rate limiting can reduce latency for receiving order
 	// events in some network conditions, but can also potentially lead to higher
 	// costs or other rate limiting issues outside of Mesh, depending on your
-	// Ethereum RPC provider. If set to false, ethereumRPCMaxRequestsPer24HrUTC
-	// and ethereumRPCMaxRequestsPerSecond will have no effect.
+	// Ethereum RPC provider. If set to false, ethereumRPCMaxRequestsPer24HrUTC,
+	// ethereumRPCMaxRequestsPerSecond and ethereumRPCProviderProfile will have
+	// no effect.
 	EnableEthereumRPCRateLimiting bool `envvar:"ENABLE_ETHEREUM_RPC_RATE_LIMITING" default:"true"`
 	// EthereumRPCMaxRequestsPer24HrUTC caps the number of Ethereum JSON-RPC requests a Mesh node will make
 	// per 24hr UTC time window (time window starts and ends at midnight UTC). It defaults to 200k but
 	// can be increased well beyond this limit depending on your infrastructure or Ethereum RPC provider.
+	// It only applies to the "generic" provider profile (see EthereumRPCProviderProfile).
 	EthereumRPCMaxRequestsPer24HrUTC int `envvar:"ETHEREUM_RPC_MAX_REQUESTS_PER_24_HR_UTC" default:"200000"`
 	// EthereumRPCMaxRequestsPerSecond caps the number of Ethereum JSON-RPC requests a Mesh node will make per
 	// second. This limits the concurrency of these requests and prevents the Mesh node from getting rate-limited.
 	// It defaults to the recommended 30 rps for Infura's free tier, and can be increased to 100 rpc for pro users,
 	// and potentially higher on alternative infrastructure.
+	// It only applies to the "generic" provider profile (see EthereumRPCProviderProfile).
 	EthereumRPCMaxRequestsPerSecond float64 `envvar:"ETHEREUM_RPC_MAX_REQUESTS_PER_SECOND" default:"30"`
+	// EthereumRPCProviderProfile selects the default rate limits for the
+	// Ethereum RPC provider. It can be "generic" (the default), "auto",
+	// "infura", "alchemy" or "alchemy-growth". The "generic" profile uses
+	// ETHEREUM_RPC_MAX_REQUESTS_PER_24_HR_UTC and
+	// ETHEREUM_RPC_MAX_REQUESTS_PER_SECOND. The other profiles ignore these
+	// settings and use the limits of the provider's plan instead. "auto"
+	// detects Infura and Alchemy from ETHEREUM_RPC_URL and falls back to
+	// "generic" for other providers. The Alchemy profiles limit compute units
+	// rather than requests, based on the cost of each JSON-RPC method. Since
+	// requests and compute units can't be compared, the number of units used
+	// up in the current UTC day is reset when switching between such profiles.
+	EthereumRPCProviderProfile string `envvar:"ETHEREUM_RPC_PROVIDER_PROFILE" default:"generic"`
+	// EthereumRPCMaxRetries is the maximum number of times a failed Ethereum
+	// JSON-RPC request is retried (with exponential back-off and jitter).
+	// Only errors which are likely to be transient, such as timeouts,
+	// connection errors, 5xx responses and rate limit errors returned by
+	// providers like Infura and Alchemy, are retried. Reverted calls and other
+	// errors are returned immediately.
+	EthereumRPCMaxRetries int `envvar:"ETHEREUM_RPC_MAX_RETRIES" default:"3"`
+	// EthereumRPCCircuitBreakerThreshold is the number of consecutive failed
+	// Ethereum JSON-RPC requests after which Mesh stops sending requests to the
+	// endpoint for ETHEREUM_RPC_CIRCUIT_BREAKER_COOLDOWN, giving it time to
+	// recover. The circuit breaker is disabled if set to 0.
+	EthereumRPCCircuitBreakerThreshold int `envvar:"ETHEREUM_RPC_CIRCUIT_BREAKER_THRESHOLD" default:"20"`
+	// EthereumRPCCircuitBreakerCooldown is how long the circuit breaker stays
+	// open before Mesh probes the Ethereum JSON-RPC endpoint again.
+	EthereumRPCCircuitBreakerCooldown time.Duration `envvar:"ETHEREUM_RPC_CIRCUIT_BREAKER_COOLDOWN" default:"30s"`
+	// EthereumRPCCallGasLimit is the gas limit of the eth_call requests used to
+	// validate orders. Some providers reject calls with a higher gas limit than
+	// their cap. If 0, the default for the Ethereum RPC provider profile (see
+	// ETHEREUM_RPC_PROVIDER_PROFILE) is used, which for the "generic" profile
+	// leaves the gas limit up to the Ethereum node.
+	EthereumRPCCallGasLimit uint64 `envvar:"ETHEREUM_RPC_CALL_GAS_LIMIT" default:"0"`
+	// EthereumRPCCallTimeout is the maximum amount of time to wait for each
+	// eth_call request used to validate orders. Timed out requests are retried
+	// like other failed requests. If 0, the default for the Ethereum RPC
+	// provider profile is used, which for the "generic" profile means requests
+	// don't time out.
+	EthereumRPCCallTimeout time.Duration `envvar:"ETHEREUM_RPC_CALL_TIMEOUT" default:"0s"`
+	// EthereumRPCCallBlock determines the block at which orders are validated.
+	// Can be "pinned" (the default), which validates orders at the block Mesh
+	// has processed, or "latest", which validates them at the latest block
+	// known to the Ethereum node. "latest" avoids errors from load-balanced
+	// providers whose nodes have not all processed the pinned block yet, but
+	// validation results may then correspond to a slightly newer block.
+	EthereumRPCCallBlock string `envvar:"ETHEREUM_RPC_CALL_BLOCK" default:"pinned"`
 	// CustomContractAddresses is a JSON-encoded string representing a set of
 	// custom addresses to use for the configured chain ID. The contract
 	// addresses for most common chains/networks are already included by default, so this
 	// is typically only needed for testing on custom chains/networks. The given
 	// addresses are added to the default list of addresses for known chains/networks and
 	// overriding any contract addresses for known chains/networks is not allowed. The
-	// addresses for exchange, devUtils, erc20Proxy, erc721Proxy and erc1155Proxy are required
-	// for each chain/network. For example:
+	// addresses for exchange, erc20Proxy, erc721Proxy and erc1155Proxy are required
+	// for each chain/network. The devUtils address is required unless
+	// VALIDATION_STRATEGY is "direct", in which case the optional multicall
+	// address is used to batch contract reads. For example:
 	//
 	//    {
 	//        "exchange":"0x48bacb9266a570d521063ef5dd96e61686dbe788",
@@ -165,6 +376,141 @@ type Config struct {
 	// enforcing a limit on maximum expiration time for incoming orders and remove
 	// any orders with an expiration time too far in the future.
 	MaxOrdersInStorage int `envvar:"MAX_ORDERS_IN_STORAGE" default:"100000"`
+	// MaxOrderSizeInBytes is the maximum size of orders encoded as JSON. Larger
+	// orders are rejected with the MaxOrderSizeExceeded code. It cannot exceed
+	// 16000 bytes, which is the maximum size of orders shared between peers.
+	MaxOrderSizeInBytes int `envvar:"MAX_ORDER_SIZE_IN_BYTES" default:"16000"`
+	// MaxAssetDataSizeInBytes is the maximum size of each of the encoded asset
+	// data fields of an order, including nested MultiAsset asset data. Orders
+	// exceeding it are rejected with the AssetDataTooLarge code before their
+	// asset data is decoded. If 0, asset data is only limited by
+	// MaxOrderSizeInBytes.
+	MaxAssetDataSizeInBytes int `envvar:"MAX_ASSET_DATA_SIZE_IN_BYTES" default:"0"`
+	// MaxMultiAssetNestingDepth is the maximum number of MultiAsset asset data
+	// nested in each other. A MultiAsset which doesn't contain other MultiAssets
+	// has a nesting depth of 1. Orders exceeding it are rejected with the
+	// MultiAssetNestingTooDeep code. If 0, the nesting depth is not limited.
+	MaxMultiAssetNestingDepth int `envvar:"MAX_MULTI_ASSET_NESTING_DEPTH" default:"2"`
+	// OrderEventConfirmationDepth is the number of blocks that must be mined
+	// on top of the latest block at the time an order event was generated
+	// before the event is sent to subscribers. This avoids sending order events
+	// for short-lived re-orgs: order events which were only caused by blocks
+	// that are removed before then are never sent, and neither are the order
+	// events caused by removing those blocks. Order events which were not
+	// caused by contract events (e.g. ADDED) are sent immediately unless
+	// earlier order events for the same order are still held back. Held back
+	// order events are stored in the database, so they are still sent after a
+	// restart. It does not delay any changes to the stored orders. If 0, order
+	// events are sent immediately.
+	OrderEventConfirmationDepth int `envvar:"ORDER_EVENT_CONFIRMATION_DEPTH" default:"0"`
+	// MaxPendingValidationMessages is the maximum number of GossipSub messages
+	// received from peers which can be waiting to be validated. When the limit
+	// is reached (e.g. during a gossip storm), additional messages are ignored
+	// (i.e. neither validated nor forwarded to other peers) until the backlog
+	// has been processed.
+	MaxPendingValidationMessages int `envvar:"MAX_PENDING_VALIDATION_MESSAGES" default:"10000"`
+	// ValidationMemoryBudget is the maximum total size in bytes of the GossipSub
+	// messages waiting to be validated. When the budget is exhausted, additional
+	// messages are ignored until the backlog has been processed.
+	ValidationMemoryBudget int `envvar:"VALIDATION_MEMORY_BUDGET" default:"67108864"`
+	// ValidationWorkers is the number of workers used for CPU-bound validation
+	// of messages received from peers, i.e. decoding orders, computing order
+	// hashes and recovering signatures. If 0, GOMAXPROCS workers are used.
+	ValidationWorkers int `envvar:"VALIDATION_WORKERS" default:"0"`
+	// MaxConcurrentOrderValidations is the maximum number of batches of new
+	// orders (from peers or added via the JSON-RPC API) which are validated
+	// against the blockchain at the same time. Additional batches wait in one
+	// of two lanes: one for orders added locally and one for orders received
+	// from peers. If 0, the default of 4 is used.
+	MaxConcurrentOrderValidations int `envvar:"MAX_CONCURRENT_ORDER_VALIDATIONS" default:"4"`
+	// LocalOrderValidationWeight and RemoteOrderValidationWeight determine how
+	// free validation slots are shared between orders added locally and
+	// orders received from peers while both are waiting. With the defaults of
+	// 4 and 1, local orders get 4 slots for every slot given to orders from
+	// peers, so that they go live quickly even during a burst of orders from
+	// the network. If 0, the defaults are used.
+	LocalOrderValidationWeight  int `envvar:"LOCAL_ORDER_VALIDATION_WEIGHT" default:"4"`
+	RemoteOrderValidationWeight int `envvar:"REMOTE_ORDER_VALIDATION_WEIGHT" default:"1"`
+	// PeerRateLimitBanThreshold is the number of GossipSub messages exceeding
+	// the per-peer rate limit within a minute after which the IP addresses of
+	// the peer are temporarily banned. If 0, peers are never banned for
+	// exceeding the rate limit.
+	PeerRateLimitBanThreshold int `envvar:"PEER_RATE_LIMIT_BAN_THRESHOLD" default:"100"`
+	// PeerRateLimitBanDuration is how long peers exceeding
+	// PeerRateLimitBanThreshold are banned for.
+	PeerRateLimitBanDuration time.Duration `envvar:"PEER_RATE_LIMIT_BAN_DURATION" default:"10m"`
+	// SignatureCacheSize is the maximum number of order signature verification
+	// results to cache. Since the same order is usually received from several
+	// peers, caching avoids repeating expensive ECDSA recovery operations. If 0,
+	// results are not cached.
+	SignatureCacheSize int `envvar:"SIGNATURE_CACHE_SIZE" default:"10000"`
+	// RecentRejectionsSize is the number of recently rejected orders which are
+	// kept in memory and returned by mesh_getRecentRejections. If 0, rejected
+	// orders are only counted.
+	RecentRejectionsSize int `envvar:"RECENT_REJECTIONS_SIZE" default:"1000"`
+	// OrderEventReplayBufferSize is the number of recent order events which are
+	// kept in memory so that interrupted order event subscriptions can be
+	// resumed with a resume token. If 0, subscriptions can only be resumed if
+	// no order events were emitted in the meantime.
+	OrderEventReplayBufferSize int `envvar:"ORDER_EVENT_REPLAY_BUFFER_SIZE" default:"10000"`
+	// SubscriptionQueueSize is the maximum number of notifications (i.e.
+	// batches of order events) which are queued for a single order event
+	// subscription whose client does not keep up.
+	SubscriptionQueueSize int `envvar:"SUBSCRIPTION_QUEUE_SIZE" default:"1000"`
+	// SubscriptionOverflowPolicy determines what happens when the queue of an
+	// order event subscription is full. If "drop-oldest", the oldest
+	// notifications are dropped and the subscriber receives a gap marker
+	// instead: an EVENTS_DROPPED order event or, for resumable subscriptions,
+	// a notification with resyncRequired set. If "disconnect", the
+	// subscription is terminated and no further notifications are sent.
+	// Clients of resumable subscriptions notice the missing heartbeats and can
+	// resume from their last resume token.
+	SubscriptionOverflowPolicy string `envvar:"SUBSCRIPTION_OVERFLOW_POLICY" default:"drop-oldest"`
+	// OrderEventCoalescingWindow enables coalescing of the order events sent
+	// to order event subscribers. Order events are held back for this window
+	// and only the latest order event for each order is sent, along with the
+	// contract events of all the order events it replaced. This reduces the
+	// number of order events for orders whose state changes many times within
+	// a short period, at the cost of delaying all order events by up to the
+	// window. Coalescing is disabled if set to 0.
+	OrderEventCoalescingWindow time.Duration `envvar:"ORDER_EVENT_COALESCING_WINDOW" default:"0"`
+	// AddressFormat determines how Ethereum addresses are rendered in the JSON
+	// emitted by Mesh, including orders, order events and JSON-RPC responses.
+	// Can be "lowercase" (the default) or "checksum" (EIP-55 mixed-case
+	// checksums). Addresses are accepted in either case regardless.
+	AddressFormat string `envvar:"ADDRESS_FORMAT" default:"lowercase"`
+	// OrderCleanupInterval is the minimum amount of time between periodic
+	// cleanups, which re-validate orders that have not been updated recently in
+	// order to catch any changes that were missed by the event watcher.
+	OrderCleanupInterval time.Duration `envvar:"ORDER_CLEANUP_INTERVAL" default:"1h"`
+	// OrderCleanupJitter is the maximum random delay added to
+	// OrderCleanupInterval. It can be used to avoid several Mesh nodes sharing
+	// an Ethereum RPC endpoint running cleanups at the same time.
+	OrderCleanupJitter time.Duration `envvar:"ORDER_CLEANUP_JITTER" default:"0s"`
+	// OrderCleanupMaxOrders is the maximum number of orders re-validated by a
+	// single periodic cleanup. The least recently updated orders are
+	// re-validated first and any remaining orders are left for the next
+	// cleanup. Lowering it spreads out Ethereum RPC requests. If 0, there is no
+	// limit.
+	OrderCleanupMaxOrders int `envvar:"ORDER_CLEANUP_MAX_ORDERS" default:"0"`
+	// OrderCleanupStalenessThreshold is how long it must have been since an
+	// order was last updated for it to be re-validated by a periodic cleanup.
+	OrderCleanupStalenessThreshold time.Duration `envvar:"ORDER_CLEANUP_STALENESS_THRESHOLD" default:"30m"`
+	// StartupRevalidationBatchSize is the number of orders re-validated at a
+	// time when Mesh re-validates all stored orders on startup, which happens
+	// if it was offline for too long to catch up on contract events. Orders
+	// that expire soonest are re-validated first. If 0, all orders are
+	// re-validated at once.
+	StartupRevalidationBatchSize int `envvar:"STARTUP_REVALIDATION_BATCH_SIZE" default:"1000"`
+	// StartupRevalidationBatchDelay is the delay between batches of orders
+	// re-validated on startup. It can be increased to avoid overwhelming the
+	// Ethereum RPC endpoint.
+	StartupRevalidationBatchDelay time.Duration `envvar:"STARTUP_REVALIDATION_BATCH_DELAY" default:"1s"`
+	// StartupRevalidationInBackground determines whether the re-validation of
+	// stored orders on startup happens in the background. If false, Mesh does
+	// not connect to peers or become ready until all orders have been
+	// re-validated.
+	StartupRevalidationInBackground bool `envvar:"STARTUP_REVALIDATION_IN_BACKGROUND" default:"false"`
 	// CustomOrderFilter is a stringified JSON Schema which will be used for
 	// validating incoming orders. If provided, Mesh will only receive orders from
 	// other peers in the network with the same filter.
@@ -185,10 +531,218 @@ type Config struct {
 	// all the required fields) are automatically included. For more information
 	// on JSON Schemas, see https://json-schema.org/
 	CustomOrderFilter string `envvar:"CUSTOM_ORDER_FILTER" default:"{}"`
+	// FeeRecipientAllowlist is a comma-separated list of fee recipient
+	// addresses. If non-empty, Mesh will only accept orders whose
+	// feeRecipientAddress is on the list. This is meant for relayers which only
+	// want to store orders that pay fees to themselves. The allowlist is
+	// combined with CustomOrderFilter, so it also determines the pubsub topic:
+	// Mesh will only receive orders from peers with the same filter and
+	// allowlist.
+	FeeRecipientAllowlist string `envvar:"FEE_RECIPIENT_ALLOWLIST" default:""`
+	// ShadowOrderFilter is a candidate order filter in the same format as
+	// CustomOrderFilter. If provided, it is evaluated against every order
+	// received from peers or added via the API in parallel with the active
+	// filter, without affecting which orders are accepted. The number of
+	// orders it would have accepted or rejected is included in the stats, so
+	// that the impact of a filter change can be assessed before switching to
+	// the new pubsub topic. Like CustomOrderFilter, it is combined with
+	// FeeRecipientAllowlist.
+	ShadowOrderFilter string `envvar:"SHADOW_ORDER_FILTER" default:""`
+	// MakerAllowlist is a comma-separated list of maker addresses or ENS names
+	// (e.g. "maker.eth"). If non-empty, Mesh will only accept and store orders
+	// from these makers. The allowlist can be changed at runtime via the
+	// mesh_setMakerAllowlist and mesh_setMakerAllowlistNames RPC methods, in
+	// which case any stored orders from makers that are no longer allowed are
+	// removed.
+	MakerAllowlist string `envvar:"MAKER_ALLOWLIST" default:""`
+	// MakerDenylist is a comma-separated list of maker addresses or ENS names
+	// whose orders Mesh will reject. It takes precedence over MakerAllowlist and
+	// can be changed at runtime via the mesh_setMakerDenylist and
+	// mesh_setMakerDenylistNames RPC methods, in which case any stored orders
+	// from newly denied makers are removed.
+	MakerDenylist string `envvar:"MAKER_DENYLIST" default:""`
+	// ENSRefreshInterval is how often ENS names on the maker allowlist and
+	// denylist are re-resolved via the Ethereum RPC endpoint, so that the lists
+	// follow changes of the addresses the names resolve to. Set to 0 to only
+	// resolve names once.
+	ENSRefreshInterval time.Duration `envvar:"ENS_REFRESH_INTERVAL" default:"1h"`
+	// AssetDenylistPath is the path to a file containing token addresses, one
+	// per line. Mesh will reject any orders whose asset data involves one of
+	// these tokens (e.g. malicious tokens which use transfer hooks to grief
+	// order validation). Lines starting with "#" are ignored. The denylist can
+	// be changed at runtime via the mesh_setAssetDenylist RPC method.
+	AssetDenylistPath string `envvar:"ASSET_DENYLIST_PATH" default:""`
+	// TransferSimulationMode determines whether Mesh simulates the transfer of
+	// the maker's assets for new orders in order to detect tokens which revert
+	// (e.g. because they are paused), charge a fee on transfer, rebase, or
+	// otherwise deliver a different amount than was transferred despite the
+	// order passing validation. Detecting the received amount requires an
+	// Ethereum node which supports eth_call state overrides (e.g. Geth). Can
+	// be "off", "warn" (orders are stored but flagged
+	// with transferSimulationFailed), or "strict" (orders are rejected with the
+	// TransferSimulationFailed code). Requires the MaximumGasPrice contract to
+	// be deployed on the configured chain.
+	TransferSimulationMode string `envvar:"TRANSFER_SIMULATION_MODE" default:"off"`
+	// TakerRestrictedOrders determines how orders with a non-null
+	// takerAddress, which can only be filled by that taker, are handled. Can
+	// be "accept" (orders are stored and shared with peers), "local" (orders
+	// are stored and returned by mesh_getOrders but never shared with peers),
+	// or "reject" (orders are rejected with the TakerAddressNotAllowed code).
+	TakerRestrictedOrders string `envvar:"TAKER_RESTRICTED_ORDERS" default:"accept"`
+	// SenderRestrictedOrders determines how orders with a non-null
+	// senderAddress, which can only be submitted to the Exchange contract by
+	// that sender, are handled. Accepts the same values as
+	// TakerRestrictedOrders; orders are rejected with the
+	// SenderAddressNotAllowed code. Such orders can be canceled off-chain (e.g.
+	// via the Coordinator API) without Mesh noticing, which is why they are
+	// rejected by default.
+	SenderRestrictedOrders string `envvar:"SENDER_RESTRICTED_ORDERS" default:"reject"`
+	// AutoReplaceOrders enables automatic replacement of orders for market
+	// makers which keep a single order per asset pair and increase the salt of
+	// each new order (e.g. by using a timestamp). When a new order is added,
+	// stored orders from the same maker for the same asset pair with a lower
+	// salt are removed and a REPLACED order event is emitted for each of them.
+	// New orders with a lower salt than such a stored order are rejected with
+	// the OrderSuperseded code. Salts are not ordered by the protocol, so this
+	// should only be enabled if all makers increase their salts, otherwise
+	// orders with random salts are rejected or replaced arbitrarily. Orders can
+	// also be replaced explicitly via the replacesOrderHashes option of
+	// mesh_addOrders, regardless of this setting.
+	AutoReplaceOrders bool `envvar:"AUTO_REPLACE_ORDERS" default:"false"`
+	// ValidationStrategy determines how the on-chain state of orders is read.
+	// Can be "devutils" (a single call to the DevUtils contract per batch of
+	// orders) or "direct" (individual calls to the Exchange and ERC20 token
+	// contracts, batched via Multicall if its address is configured). The
+	// direct strategy is meant for private chains on which DevUtils is not
+	// deployed. It only supports orders whose maker asset and maker fee asset
+	// are ERC20 tokens and cannot be combined with TRANSFER_SIMULATION_MODE.
+	ValidationStrategy string `envvar:"VALIDATION_STRATEGY" default:"devutils"`
+	// MulticallBatchWindow enables batching of read-only contract calls (e.g.
+	// order validation and price oracle reads) via the Multicall contract. Calls
+	// made within this window of each other are coalesced into a single
+	// eth_call, which reduces the number of Ethereum RPC requests. Batching is
+	// disabled if set to 0. Requires the Multicall contract address to be
+	// configured.
+	MulticallBatchWindow time.Duration `envvar:"MULTICALL_BATCH_WINDOW" default:"0"`
+	// PriceOracle is the source of token prices used to compute approximate USD
+	// notional values for orders. Can be "none", "chainlink" (on-chain Chainlink
+	// USD price feeds configured via PRICE_ORACLE_CHAINLINK_FEEDS) or "http"
+	// (an external endpoint configured via PRICE_ORACLE_HTTP_URL). When a price
+	// oracle is configured, notional values are included in mesh_getOrders
+	// responses and the least valuable orders are removed first when order
+	// storage is full.
+	PriceOracle string `envvar:"PRICE_ORACLE" default:"none"`
+	// PriceOracleChainlinkFeeds is a comma-separated list of token:aggregator
+	// address pairs, mapping ERC20 token addresses to the addresses of their
+	// Chainlink USD price feeds.
+	PriceOracleChainlinkFeeds string `envvar:"PRICE_ORACLE_CHAINLINK_FEEDS" default:""`
+	// PriceOracleHTTPURL is the URL of an HTTP price oracle. Mesh sends GET
+	// requests of the form <url>?token=<address> and expects JSON responses of
+	// the form {"priceUSD": "1.23", "decimals": 18}.
+	PriceOracleHTTPURL string `envvar:"PRICE_ORACLE_HTTP_URL" default:""`
+	// MinOrderNotionalUSD is the minimum approximate USD notional value of new
+	// orders. Orders below it are rejected with the OrderNotionalTooLow code.
+	// Orders whose value cannot be determined are not affected. Requires
+	// PriceOracle to be set.
+	MinOrderNotionalUSD float64 `envvar:"MIN_ORDER_NOTIONAL_USD" default:"0"`
+	// EnableAuditLog determines whether Mesh records every mutating RPC call
+	// (mesh_addOrders, mesh_addPeer, mesh_setMakerAllowlist,
+	// mesh_setMakerAllowlistNames, mesh_setMakerDenylist,
+	// mesh_setMakerDenylistNames and mesh_setAssetDenylist) in the database
+	// along with the caller (if known), a digest of the parameters and the
+	// outcome. The audit log can be retrieved via mesh_getAuditLog and exported
+	// via mesh_exportAuditLog.
+	EnableAuditLog bool `envvar:"ENABLE_AUDIT_LOG" default:"false"`
+	// AuditLogExportPath is the file the audit log is written to when
+	// mesh_exportAuditLog is called. Each line of the file is a JSON-encoded
+	// audit record. Defaults to audit_log.jsonl in DataDir.
+	AuditLogExportPath string `envvar:"AUDIT_LOG_EXPORT_PATH" default:""`
+	// AuditLogRetentionPeriod is how long records are kept in the audit log.
+	// Older records are deleted once per hour. If it is 0, records are never
+	// deleted.
+	AuditLogRetentionPeriod time.Duration `envvar:"AUDIT_LOG_RETENTION_PERIOD" default:"720h"`
+	// OrderbookSnapshotInterval is how often Mesh writes a compact snapshot of
+	// its orderbook (the hash and fillable taker asset amount of each order,
+	// grouped by asset pair) to OrderbookSnapshotDestination. Snapshots are
+	// disabled if it is 0.
+	OrderbookSnapshotInterval time.Duration `envvar:"ORDERBOOK_SNAPSHOT_INTERVAL" default:"0"`
+	// OrderbookSnapshotDestination is where orderbook snapshots are stored.
+	// It is either a local directory or a bucket in S3-compatible storage of
+	// the form s3://bucket/prefix. S3 credentials are read from the
+	// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and (optionally)
+	// AWS_SESSION_TOKEN environment variables. Defaults to the
+	// orderbook_snapshots directory in DataDir.
+	OrderbookSnapshotDestination string `envvar:"ORDERBOOK_SNAPSHOT_DESTINATION" default:""`
+	// OrderbookSnapshotS3Endpoint is the base URL of the S3-compatible storage
+	// service snapshots are uploaded to. Buckets are addressed with path-style
+	// URLs.
+	OrderbookSnapshotS3Endpoint string `envvar:"ORDERBOOK_SNAPSHOT_S3_ENDPOINT" default:"https://s3.amazonaws.com"`
+	// OrderbookSnapshotS3Region is the region of the bucket snapshots are
+	// uploaded to.
+	OrderbookSnapshotS3Region string `envvar:"ORDERBOOK_SNAPSHOT_S3_REGION" default:"us-east-1"`
+	// DBSnapshotURL is the URL of the manifest of a database snapshot which is
+	// restored when the node starts for the first time, so that it does not
+	// have to discover all orders via ordersync. The manifest must be signed by
+	// DBSnapshotSigner. HTTP(S), s3://bucket/key and gs://bucket/key URLs are
+	// supported. All restored orders are re-validated before the node starts.
+	// If the snapshot cannot be restored, the node starts with an empty
+	// database.
+	DBSnapshotURL string `envvar:"DB_SNAPSHOT_URL" default:""`
+	// DBSnapshotSigner is the peer ID of the key which signs the database
+	// snapshot manifest at DBSnapshotURL. It is required if DBSnapshotURL is
+	// set.
+	DBSnapshotSigner string `envvar:"DB_SNAPSHOT_SIGNER" default:""`
+	// AlertWebhookURL is a URL alerts are sent to as JSON-encoded POST
+	// requests. Alerts are always logged (as errors when they fire), even if
+	// no webhook is configured. Alerts are only sent for the conditions
+	// enabled via the ALERT_* environment variables below.
+	AlertWebhookURL string `envvar:"ALERT_WEBHOOK_URL" default:""`
+	// AlertEthRPCUnreachableFor enables an alert which fires when the Ethereum
+	// RPC endpoint has been unreachable for the given amount of time. It is
+	// disabled if set to 0.
+	AlertEthRPCUnreachableFor time.Duration `envvar:"ALERT_ETH_RPC_UNREACHABLE_FOR" default:"0"`
+	// AlertMinPeers enables an alert which fires when Mesh is connected to
+	// fewer peers than the given number. It is disabled if set to 0.
+	AlertMinPeers int `envvar:"ALERT_MIN_PEERS" default:"0"`
+	// AlertStoragePercent enables an alert which fires when the number of
+	// stored orders exceeds the given percentage of MaxOrdersInStorage. It is
+	// disabled if set to 0.
+	AlertStoragePercent float64 `envvar:"ALERT_STORAGE_PERCENT" default:"0"`
+	// AlertMaxBlockLag enables an alert which fires when the latest block
+	// processed by Mesh is more than the given number of blocks behind the
+	// latest block of the Ethereum RPC endpoint. It is disabled if set to 0.
+	AlertMaxBlockLag int `envvar:"ALERT_MAX_BLOCK_LAG" default:"0"`
+	// DryRun makes Mesh fully validate incoming orders (including the custom
+	// order filter and on-chain validation) without storing them, sharing them
+	// or forwarding them to peers. What Mesh would have done with each order
+	// is logged and, if DryRunExportPath is set, exported. It is useful for
+	// testing new filters and policy settings against live traffic. Orders
+	// which were stored before dry run mode was enabled are still watched.
+	DryRun bool `envvar:"DRY_RUN" default:"false"`
+	// DryRunExportPath is a file to which the outcome of validating each order
+	// is appended as a JSON line in dry run mode.
+	DryRunExportPath string `envvar:"DRY_RUN_EXPORT_PATH" default:""`
 	// EthereumRPCClient is the client to use for all Ethereum RPC reuqests. It is only
 	// settable in browsers and cannot be set via environment variable. If
 	// provided, EthereumRPCURL will be ignored.
 	EthereumRPCClient ethclient.RPCClient `envvar:"-"`
+	// CustomContracts are additional contracts, such as custom settlement
+	// contracts or forked Exchange contracts, whose events are used to decide
+	// which orders need to be re-validated. They can only be set by programs
+	// which embed Mesh and cannot be set via environment variable.
+	CustomContracts []orderwatch.CustomContract `envvar:"-" json:"-"`
+	// ConnectionGater restricts which IP addresses and peer IDs Mesh connects
+	// to and accepts connections from (e.g. only the nodes of a private fleet
+	// and the bootstrap nodes). It can only be set by programs which embed Mesh
+	// and cannot be set via environment variable. If nil, all peers are
+	// allowed.
+	ConnectionGater *p2p.ConnectionGater `envvar:"-" json:"-"`
+	// InMemoryStorage determines whether the database and the private key are
+	// only kept in memory instead of being persisted in DataDir, so that the
+	// node starts from scratch with a new peer ID every time. It is intended
+	// for transient nodes in the browser and cannot be set via environment
+	// variable.
+	InMemoryStorage bool `envvar:"-"`
 }
 
 type snapshotInfo struct {
@@ -208,38 +762,61 @@ type App struct {
 	orderWatcher              *orderwatch.Watcher
 	orderValidator            *ordervalidator.OrderValidator
 	orderFilter               *orderfilter.Filter
+	shadowFilter              *shadowFilter
 	snapshotExpirationWatcher *expirationwatch.Watcher
 	muIdToSnapshotInfo        sync.Mutex
 	idToSnapshotInfo          map[string]snapshotInfo
 	ethRPCRateLimiter         ratelimit.RateLimiter
+	ethRPCMaxUnitsPer24HrUTC  int
 	ethRPCClient              ethrpcclient.Client
+	multicallBatcher          *multicall.Batcher
 	db                        *meshdb.MeshDB
 	ordersyncService          *ordersync.Service
 	contractAddresses         *ethereum.ContractAddresses
+	workerPool                *workerpool.Pool
+	signatureCache            *signatureCache
+	rejectionLog              *rejectionLog
+	orderEventJournal         *orderEventJournal
+	orderEventCoalescer       *orderEventCoalescer
+	orderHistory              *orderHistory
+	ingestionLatency          *ingestionLatencyTracker
+	subscriptionQueuesMu      sync.Mutex
+	subscriptionQueues        map[uint64]*SubscriptionQueue
+	nextSubscriptionQueueID   uint64
+	ensResolver               *ens.Resolver
+	makerListsMu              sync.Mutex
+	makerAllowlistEntries     []string
+	makerDenylistEntries      []string
+	orderbookSnapshotStore    orderbooksnapshot.Store
+	alertMonitor              *alerting.Monitor
+	alertWebhook              *alerting.Webhook
+	dryRunExportMu            sync.Mutex
+	restoredDBSnapshot        bool
+	proxyURL                  *url.URL
+	startupProgressFeed       event.Feed
 
 	// started is closed to signal that the App has been started. Some methods
 	// will block until after the App is started.
 	started chan struct{}
 }
 
-var setupLoggerOnce = &sync.Once{}
-
 func New(config Config) (*App, error) {
 	return newWithPrivateConfig(config, defaultPrivateConfig())
 }
 
 func newWithPrivateConfig(config Config, pConfig privateConfig) (*App, error) {
 	// Configure logger
-	// TODO(albrow): Don't use global variables for log settings.
-	setupLoggerOnce.Do(func() {
-		log.SetFormatter(&log.JSONFormatter{})
-		log.SetLevel(log.Level(config.Verbosity))
-		log.AddHook(loghooks.NewKeySuffixHook())
-	})
+	if err := setupLogger(config); err != nil {
+		return nil, err
+	}
+
+	proxyURL, err := parseProxyURL(config)
+	if err != nil {
+		return nil, err
+	}
 
 	// Add custom contract addresses if needed.
 	var contractAddresses ethereum.ContractAddresses
-	var err error
 	if config.CustomContractAddresses != "" {
 		contractAddresses, err = parseAndValidateCustomContractAddresses(config.EthereumChainID, config.CustomContractAddresses)
 	} else {
@@ -250,8 +827,13 @@ func newWithPrivateConfig(config Config, pConfig privateConfig) (*App, error) {
 	}
 
 	// Load private key and add peer ID hook.
-	privKeyPath := filepath.Join(config.DataDir, "keys", "privkey")
-	privKey, err := initPrivateKey(privKeyPath)
+	var privKey p2pcrypto.PrivKey
+	if config.InMemoryStorage {
+		privKey, err = keys.GeneratePrivateKey()
+	} else {
+		privKeyPath := filepath.Join(config.DataDir, "keys", "privkey")
+		privKey, err = initPrivateKey(privKeyPath)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -259,48 +841,154 @@ func newWithPrivateConfig(config Config, pConfig privateConfig) (*App, error) {
 	if err != nil {
 		return nil, err
 	}
-	log.AddHook(loghooks.NewPeerIDHook(peerID))
+	addLogHook(loghooks.NewPeerIDHook(peerID))
+	// Log shipping is set up after the peer ID hook so that shipped logs include
+	// the peer ID.
+	if err := setupLogShipping(config); err != nil {
+		return nil, err
+	}
 
 	if config.EthereumRPCMaxContentLength < constants.MaxOrderSizeInBytes {
 		return nil, fmt.Errorf("Cannot set `EthereumRPCMaxContentLength` to be less then MaxOrderSizeInBytes: %d", constants.MaxOrderSizeInBytes)
 	}
 	config = unquoteConfig(config)
 
-	if config.EnableEthereumRPCRateLimiting {
-		// Ensure ETHEREUM_RPC_MAX_REQUESTS_PER_24_HR_UTC is reasonably set given BLOCK_POLLING_INTERVAL
+	if config.RelayOnly {
+		if err := validateRelayOnlyConfig(config); err != nil {
+			return nil, err
+		}
+	}
+
+	var ethRPCRateLimitProfile ratelimit.Profile
+	if config.EnableEthereumRPCRateLimiting && !config.RelayOnly {
+		ethRPCRateLimitProfile, err = ratelimit.GetProfile(
+			config.EthereumRPCProviderProfile,
+			config.EthereumRPCURL,
+			config.EthereumRPCMaxRequestsPer24HrUTC,
+			config.EthereumRPCMaxRequestsPerSecond,
+		)
+		if err != nil {
+			return nil, err
+		}
+		// Ensure the 24hr budget of the profile is reasonably set given BLOCK_POLLING_INTERVAL
 		per24HrPollingRequests := int((24 * time.Hour) / config.BlockPollingInterval)
-		minNumOfEthRPCRequestsIn24HrPeriod := per24HrPollingRequests + estimatedNonPollingEthereumRPCRequestsPer24Hrs
-		if minNumOfEthRPCRequestsIn24HrPeriod > config.EthereumRPCMaxRequestsPer24HrUTC {
+		minNumOfEthRPCUnitsIn24HrPeriod := per24HrPollingRequests*ethRPCRateLimitProfile.Cost("eth_getBlockByNumber") +
+			estimatedNonPollingEthereumRPCRequestsPer24Hrs*ethRPCRateLimitProfile.Cost("eth_call")
+		if minNumOfEthRPCUnitsIn24HrPeriod > ethRPCRateLimitProfile.MaxUnitsPer24HrUTC {
+			if ethRPCRateLimitProfile.Name == ratelimit.ProfileNameGeneric {
+				return nil, fmt.Errorf(
+					"Given BLOCK_POLLING_INTERVAL (%s), there are insufficient remaining ETH RPC requests in a 24hr period for Mesh to function properly. Increase ETHEREUM_RPC_MAX_REQUESTS_PER_24_HR_UTC to at least %d (currently configured to: %d)",
+					config.BlockPollingInterval,
+					minNumOfEthRPCUnitsIn24HrPeriod,
+					config.EthereumRPCMaxRequestsPer24HrUTC,
+				)
+			}
 			return nil, fmt.Errorf(
-				"Given BLOCK_POLLING_INTERVAL (%s), there are insufficient remaining ETH RPC requests in a 24hr period for Mesh to function properly. Increase ETHEREUM_RPC_MAX_REQUESTS_PER_24_HR_UTC to at least %d (currently configured to: %d)",
+				"Given BLOCK_POLLING_INTERVAL (%s), the %q Ethereum RPC provider profile does not allow enough ETH RPC requests in a 24hr period for Mesh to function properly. Increase BLOCK_POLLING_INTERVAL or set ETHEREUM_RPC_PROVIDER_PROFILE to \"generic\" and configure custom limits",
 				config.BlockPollingInterval,
-				minNumOfEthRPCRequestsIn24HrPeriod,
-				config.EthereumRPCMaxRequestsPer24HrUTC,
+				ethRPCRateLimitProfile.Name,
 			)
 		}
+		log.WithFields(log.Fields{
+			"profile":            ethRPCRateLimitProfile.Name,
+			"maxUnitsPerSecond":  ethRPCRateLimitProfile.MaxUnitsPerSecond,
+			"maxUnitsPer24HrUTC": ethRPCRateLimitProfile.MaxUnitsPer24HrUTC,
+			"computeUnits":       ethRPCRateLimitProfile.ComputeUnitCosts != nil,
+		}).Info("using Ethereum RPC rate limiting profile")
+	}
+
+	makerAllowlistEntries, err := parseAddressList(config.MakerAllowlist)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve MAKER_ALLOWLIST: %s", err.Error())
+	}
+	makerDenylistEntries, err := parseAddressList(config.MakerDenylist)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAKER_DENYLIST: %s", err.Error())
+	}
+	if config.ENSRefreshInterval < 0 {
+		return nil, errors.New("ENS_REFRESH_INTERVAL cannot be negative")
+	}
+	assetDenylist, err := loadAssetDenylist(config.AssetDenylistPath)
+	if err != nil {
+		return nil, err
+	}
+	transferSimulationMode := orderwatch.TransferSimulationMode(config.TransferSimulationMode)
+	if transferSimulationMode == "" {
+		transferSimulationMode = orderwatch.TransferSimulationOff
+	}
+	switch transferSimulationMode {
+	case orderwatch.TransferSimulationOff:
+	case orderwatch.TransferSimulationWarn, orderwatch.TransferSimulationStrict:
+		if contractAddresses.MaximumGasPrice == constants.NullAddress {
+			return nil, fmt.Errorf("TRANSFER_SIMULATION_MODE %q requires the MaximumGasPrice contract address to be configured", config.TransferSimulationMode)
+		}
+	default:
+		return nil, fmt.Errorf("invalid TRANSFER_SIMULATION_MODE: %q (must be one of \"off\", \"warn\" or \"strict\")", config.TransferSimulationMode)
+	}
+	takerRestrictedPolicy, err := parseRestrictedOrderPolicy("TAKER_RESTRICTED_ORDERS", config.TakerRestrictedOrders, orderwatch.RestrictedOrdersAccept)
+	if err != nil {
+		return nil, err
+	}
+	senderRestrictedPolicy, err := parseRestrictedOrderPolicy("SENDER_RESTRICTED_ORDERS", config.SenderRestrictedOrders, orderwatch.RestrictedOrdersReject)
+	if err != nil {
+		return nil, err
+	}
+	validationStrategy := ordervalidator.ValidationStrategy(config.ValidationStrategy)
+	if validationStrategy == "" {
+		validationStrategy = ordervalidator.ValidationStrategyDevUtils
+	}
+	switch validationStrategy {
+	case ordervalidator.ValidationStrategyDevUtils:
+		if contractAddresses.DevUtils == constants.NullAddress {
+			return nil, errors.New("VALIDATION_STRATEGY \"devutils\" requires the DevUtils contract address to be configured")
+		}
+	case ordervalidator.ValidationStrategyDirect:
+		if transferSimulationMode != orderwatch.TransferSimulationOff {
+			return nil, errors.New("TRANSFER_SIMULATION_MODE requires VALIDATION_STRATEGY \"devutils\"")
+		}
+	default:
+		return nil, fmt.Errorf("invalid VALIDATION_STRATEGY: %q (must be one of \"devutils\" or \"direct\")", config.ValidationStrategy)
+	}
+	ethCallOptions, err := getEthCallOptions(config)
+	if err != nil {
+		return nil, err
 	}
 
 	// Initialize db
-	databasePath := filepath.Join(config.DataDir, "db")
-	meshDB, err := meshdb.New(databasePath, contractAddresses)
+	var meshDB *meshdb.MeshDB
+	if config.InMemoryStorage {
+		meshDB, err = meshdb.NewInMemory(contractAddresses)
+	} else {
+		databasePath := filepath.Join(config.DataDir, "db")
+		meshDB, err = meshdb.New(databasePath, contractAddresses)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := meshDB.Migrate(); err != nil {
+		return nil, err
+	}
+
+	// Restore a database snapshot on first startup if configured.
+	restoredDBSnapshot, err := restoreDBSnapshot(config, meshDB)
 	if err != nil {
 		return nil, err
 	}
 
 	// Initialize metadata and check stored chain id (if any).
-	metadata, err := initMetadata(config.EthereumChainID, meshDB)
+	metadata, err := initMetadata(config.EthereumChainID, meshDB, config.ForceChainSwitch, filepath.Join(config.DataDir, "archive"))
 	if err != nil {
 		return nil, err
 	}
 
 	// Initialize ETH JSON-RPC RateLimiter
 	var ethRPCRateLimiter ratelimit.RateLimiter
-	if config.EnableEthereumRPCRateLimiting == false {
+	if config.EnableEthereumRPCRateLimiting == false || config.RelayOnly {
 		ethRPCRateLimiter = ratelimit.NewUnlimited()
 	} else {
 		clock := clock.New()
 		var err error
-		ethRPCRateLimiter, err = ratelimit.New(config.EthereumRPCMaxRequestsPer24HrUTC, config.EthereumRPCMaxRequestsPerSecond, meshDB, clock)
+		ethRPCRateLimiter, err = ratelimit.NewWithProfile(ethRPCRateLimitProfile, meshDB, clock)
 		if err != nil {
 			return nil, err
 		}
@@ -308,23 +996,86 @@ func newWithPrivateConfig(config Config, pConfig privateConfig) (*App, error) {
 
 	// Initialize the ETH client, which will be used by various watchers.
 	var ethRPCClient ethclient.RPCClient
-	if config.EthereumRPCClient != nil {
+	if config.RelayOnly {
+		if config.EthereumRPCClient != nil || config.EthereumRPCURL != "" {
+			log.Warn("Ignoring EthereumRPCURL and EthereumRPCClient in relay-only mode")
+		}
+	} else if config.EthereumRPCClient != nil {
 		if config.EthereumRPCURL != "" {
 			log.Warn("Ignoring EthereumRPCURL and using the provided EthereumRPCClient")
 		}
 		ethRPCClient = config.EthereumRPCClient
 	} else if config.EthereumRPCURL != "" {
-		ethRPCClient, err = rpc.Dial(config.EthereumRPCURL)
+		var transport ethrpcclient.Transport
+		transport, _, err = ethrpcclient.ParseRPCURL(config.EthereumRPCURL)
 		if err != nil {
 			log.WithError(err).Error("Could not dial EthereumRPCURL")
 			return nil, err
 		}
+		transportOpts := ethrpcclient.DefaultTransportOptions(transport)
+		transportOpts.ProxyURL = proxyURL
+		ethRPCClient, err = ethrpcclient.DialWithOptions(context.Background(), config.EthereumRPCURL, transportOpts)
+		if err != nil {
+			log.WithError(err).Error("Could not dial EthereumRPCURL")
+			return nil, err
+		}
+		log.WithField("transport", transport).Info("connected to Ethereum RPC endpoint")
 	} else {
 		return nil, errors.New("cannot initialize core.App: neither EthereumRPCURL or EthereumRPCClient were provided")
 	}
-	ethClient, err := ethrpcclient.New(ethRPCClient, ethereumRPCRequestTimeout, ethRPCRateLimiter)
+	retryPolicy := ethrpcclient.RetryPolicy{
+		MaxRetries:               config.EthereumRPCMaxRetries,
+		MinBackoff:               ethereumRPCRetryMinBackoff,
+		MaxBackoff:               ethereumRPCRetryMaxBackoff,
+		RateLimitedBackoffFactor: ethereumRPCRateLimitedBackoffFactor,
+		CircuitBreakerThreshold:  config.EthereumRPCCircuitBreakerThreshold,
+		CircuitBreakerCooldown:   config.EthereumRPCCircuitBreakerCooldown,
+	}
+	// In relay-only mode, ethClient and contractCaller are nil and nothing
+	// which depends on them is initialized.
+	var ethClient ethrpcclient.Client
+	if !config.RelayOnly {
+		ethClient, err = ethrpcclient.NewWithRetryPolicy(ethRPCClient, ethereumRPCRequestTimeout, ethRPCRateLimiter, retryPolicy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// All read-only contract calls are made via contractCaller, which
+	// optionally coalesces them into aggregated Multicall requests.
+	var contractCaller bind.ContractCaller = ethClient
+	var multicallBatcher *multicall.Batcher
+	if config.MulticallBatchWindow > 0 && !config.RelayOnly {
+		multicallBatcher, err = multicall.NewBatcher(multicall.BatcherConfig{
+			ContractCaller:   ethClient,
+			MulticallAddress: contractAddresses.Multicall,
+			Window:           config.MulticallBatchWindow,
+			// Leave room for the ABI encoding overhead of the aggregated call.
+			MaxCallDataBytesPerBatch: config.EthereumRPCMaxContentLength / 2,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid MULTICALL_BATCH_WINDOW: %s", err.Error())
+		}
+		contractCaller = multicallBatcher
+	}
+
+	// Resolve any ENS names on the maker lists.
+	var ensResolver *ens.Resolver
+	if !config.RelayOnly {
+		ensResolver, err = ens.New(contractCaller)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ensCtx, cancelENS := context.WithTimeout(context.Background(), ensResolutionTimeout)
+	defer cancelENS()
+	makerAllowlist, err := resolveAddressList(ensCtx, ensResolver, makerAllowlistEntries)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("could not resolve MAKER_ALLOWLIST: %s", err.Error())
+	}
+	makerDenylist, err := resolveAddressList(ensCtx, ensResolver, makerDenylistEntries)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve MAKER_DENYLIST: %s", err.Error())
 	}
 
 	// Initialize block watcher (but don't start it yet).
@@ -358,6 +1109,11 @@ func newWithPrivateConfig(config Config, pConfig privateConfig) (*App, error) {
 	}
 
 	topics := orderwatch.GetRelevantTopics()
+	customContractTopics, err := orderwatch.GetCustomContractTopics(config.CustomContracts)
+	if err != nil {
+		return nil, err
+	}
+	topics = append(topics, customContractTopics...)
 	miniHeaders, err := meshDB.FindAllMiniHeadersSortedByNumber()
 	if err != nil {
 		return nil, err
@@ -373,38 +1129,149 @@ func newWithPrivateConfig(config Config, pConfig privateConfig) (*App, error) {
 	blockWatcher := blockwatch.New(blockWatcherConfig)
 
 	// Initialize the order validator
-	orderValidator, err := ordervalidator.New(
-		ethClient,
-		config.EthereumChainID,
-		config.EthereumRPCMaxContentLength,
-		contractAddresses,
-	)
-	if err != nil {
-		return nil, err
+	var orderValidator *ordervalidator.OrderValidator
+	if !config.RelayOnly {
+		orderValidator, err = ordervalidator.New(
+			contractCaller,
+			config.EthereumChainID,
+			config.EthereumRPCMaxContentLength,
+			contractAddresses,
+			validationStrategy,
+			ethCallOptions,
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Initialize order watcher (but don't start it yet).
+	priceOracle, err := newPriceOracle(config, contractCaller)
+	if err != nil {
+		return nil, err
+	}
 	orderWatcher, err := orderwatch.New(orderwatch.Config{
-		MeshDB:            meshDB,
-		BlockWatcher:      blockWatcher,
-		OrderValidator:    orderValidator,
-		ChainID:           config.EthereumChainID,
-		ContractAddresses: contractAddresses,
-		MaxOrders:         config.MaxOrdersInStorage,
-		MaxExpirationTime: metadata.MaxExpirationTime,
+		MeshDB:                      meshDB,
+		BlockWatcher:                blockWatcher,
+		OrderValidator:              orderValidator,
+		ChainID:                     config.EthereumChainID,
+		ContractAddresses:           contractAddresses,
+		MaxOrders:                   config.MaxOrdersInStorage,
+		MaxExpirationTime:           metadata.MaxExpirationTime,
+		MakerAllowlist:              makerAllowlist,
+		MakerDenylist:               makerDenylist,
+		AssetDenylist:               assetDenylist,
+		TransferSimulationMode:      transferSimulationMode,
+		TakerRestrictedOrderPolicy:  takerRestrictedPolicy,
+		SenderRestrictedOrderPolicy: senderRestrictedPolicy,
+		ReplaceSupersededOrders:     config.AutoReplaceOrders,
+		DryRun:                      config.DryRun,
+		PriceOracle:                 priceOracle,
+		MinOrderNotionalUSD:         config.MinOrderNotionalUSD,
+		CleanupInterval:             config.OrderCleanupInterval,
+		CleanupJitter:               config.OrderCleanupJitter,
+		CleanupMaxOrdersPerRun:      config.OrderCleanupMaxOrders,
+		CleanupLastUpdatedBuffer:    config.OrderCleanupStalenessThreshold,
+		CustomContracts:             config.CustomContracts,
+		MaxOrderSizeInBytes:         config.MaxOrderSizeInBytes,
+		AssetDataLimits: zeroex.AssetDataLimits{
+			MaxSizeInBytes:            config.MaxAssetDataSizeInBytes,
+			MaxMultiAssetNestingDepth: config.MaxMultiAssetNestingDepth,
+		},
+		OrderEventConfirmationDepth: config.OrderEventConfirmationDepth,
+		MaxConcurrentValidations:    config.MaxConcurrentOrderValidations,
+		LocalValidationWeight:       config.LocalOrderValidationWeight,
+		RemoteValidationWeight:      config.RemoteOrderValidationWeight,
+		RelayOnly:                   config.RelayOnly,
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	// Initialize the order filter
-	orderFilter, err := orderfilter.New(config.EthereumChainID, config.CustomOrderFilter, contractAddresses)
+	feeRecipientAllowlist, err := orderfilter.ParseFeeRecipientAllowlist(config.FeeRecipientAllowlist)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FEE_RECIPIENT_ALLOWLIST: %s", err.Error())
+	}
+	customOrderFilter, err := orderfilter.WithFeeRecipientAllowlist(config.CustomOrderFilter, feeRecipientAllowlist)
 	if err != nil {
 		return nil, fmt.Errorf("invalid custom order filter: %s", err.Error())
 	}
+	orderFilter, err := orderfilter.New(config.EthereumChainID, customOrderFilter, contractAddresses)
+	if err != nil {
+		return nil, fmt.Errorf("invalid custom order filter: %s", err.Error())
+	}
+	shadowFilter, err := newShadowFilter(config, feeRecipientAllowlist, contractAddresses)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shadow order filter: %s", err.Error())
+	}
 
 	// Initialize remaining fields.
 	snapshotExpirationWatcher := expirationwatch.New()
+	if config.SignatureCacheSize < 0 {
+		return nil, errors.New("SIGNATURE_CACHE_SIZE cannot be negative")
+	}
+	sigCache, err := newSignatureCache(config.SignatureCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	orderHistory, err := newOrderHistory(orderHistoryCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	ingestionLatency, err := newIngestionLatencyTracker(ingestionLatencyPendingSize)
+	if err != nil {
+		return nil, err
+	}
+	if config.MinPeerGroups < 0 {
+		return nil, errors.New("MIN_PEER_GROUPS cannot be negative")
+	}
+	if config.RecentRejectionsSize < 0 {
+		return nil, errors.New("RECENT_REJECTIONS_SIZE cannot be negative")
+	}
+	if config.OrderEventReplayBufferSize < 0 {
+		return nil, errors.New("ORDER_EVENT_REPLAY_BUFFER_SIZE cannot be negative")
+	}
+	if config.SubscriptionQueueSize < 0 {
+		return nil, errors.New("SUBSCRIPTION_QUEUE_SIZE cannot be negative")
+	}
+	if err := parseSubscriptionOverflowPolicy(config.SubscriptionOverflowPolicy); err != nil {
+		return nil, err
+	}
+	addressFormat, err := addressformat.Parse(config.AddressFormat)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ADDRESS_FORMAT: %q (must be %q or %q)", config.AddressFormat, addressformat.Lowercase, addressformat.Checksum)
+	}
+	if err := addressformat.Set(addressFormat); err != nil {
+		return nil, err
+	}
+	if config.OrderEventCoalescingWindow < 0 {
+		return nil, errors.New("ORDER_EVENT_COALESCING_WINDOW cannot be negative")
+	}
+	var orderEventCoalescer *orderEventCoalescer
+	if config.OrderEventCoalescingWindow > 0 {
+		orderEventCoalescer = newOrderEventCoalescer(config.OrderEventCoalescingWindow)
+	}
+	if config.PeerRateLimitBanThreshold < 0 {
+		return nil, errors.New("PEER_RATE_LIMIT_BAN_THRESHOLD cannot be negative")
+	}
+	if config.PeerRateLimitBanDuration < 0 {
+		return nil, errors.New("PEER_RATE_LIMIT_BAN_DURATION cannot be negative")
+	}
+	orderbookSnapshotStore, err := newOrderbookSnapshotStore(config)
+	if err != nil {
+		return nil, err
+	}
+	alertMonitor, err := newAlertMonitor(config)
+	if err != nil {
+		return nil, err
+	}
+	var alertWebhook *alerting.Webhook
+	if config.AlertWebhookURL != "" {
+		alertWebhook, err = alerting.NewWebhook(config.AlertWebhookURL, peerID.Pretty())
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	app := &App{
 		started:                   make(chan struct{}),
@@ -420,9 +1287,27 @@ func newWithPrivateConfig(config Config, pConfig privateConfig) (*App, error) {
 		snapshotExpirationWatcher: snapshotExpirationWatcher,
 		idToSnapshotInfo:          map[string]snapshotInfo{},
 		ethRPCRateLimiter:         ethRPCRateLimiter,
+		ethRPCMaxUnitsPer24HrUTC:  ethRPCRateLimitProfile.MaxUnitsPer24HrUTC,
 		ethRPCClient:              ethClient,
+		multicallBatcher:          multicallBatcher,
 		db:                        meshDB,
 		contractAddresses:         &contractAddresses,
+		workerPool:                workerpool.New(config.ValidationWorkers),
+		signatureCache:            sigCache,
+		rejectionLog:              newRejectionLog(config.RecentRejectionsSize),
+		orderEventJournal:         newOrderEventJournal(config.OrderEventReplayBufferSize),
+		orderEventCoalescer:       orderEventCoalescer,
+		orderHistory:              orderHistory,
+		ingestionLatency:          ingestionLatency,
+		subscriptionQueues:        map[uint64]*SubscriptionQueue{},
+		ensResolver:               ensResolver,
+		makerAllowlistEntries:     makerAllowlistEntries,
+		makerDenylistEntries:      makerDenylistEntries,
+		orderbookSnapshotStore:    orderbookSnapshotStore,
+		alertMonitor:              alertMonitor,
+		alertWebhook:              alertWebhook,
+		restoredDBSnapshot:        restoredDBSnapshot,
+		proxyURL:                  proxyURL,
 	}
 
 	log.WithFields(map[string]interface{}{
@@ -497,7 +1382,11 @@ func initPrivateKey(path string) (p2pcrypto.PrivKey, error) {
 	return nil, err
 }
 
-func initMetadata(chainID int, meshDB *meshdb.MeshDB) (*meshdb.Metadata, error) {
+// initMetadata creates the metadata on first startup and checks that the
+// database was created for the given chain on subsequent startups. If
+// forceChainSwitch is true, the orders of a database created for a different
+// chain are archived to archiveDir and removed instead.
+func initMetadata(chainID int, meshDB *meshdb.MeshDB, forceChainSwitch bool, archiveDir string) (*meshdb.Metadata, error) {
 	metadata, err := meshDB.GetMetadata()
 	if err != nil {
 		if _, ok := err.(db.NotFoundError); ok {
@@ -516,20 +1405,39 @@ func initMetadata(chainID int, meshDB *meshdb.MeshDB) (*meshdb.Metadata, error)
 
 	// on subsequent startups, verify we are on the same chain
 	if metadata.EthereumChainID != chainID {
-		err := fmt.Errorf("expected chainID to be %d but got %d", metadata.EthereumChainID, chainID)
-		log.WithError(err).Error("Mesh previously started on different Ethereum chain; switch chainId or remove DB")
+		if forceChainSwitch {
+			return switchChain(chainID, meshDB, metadata, archiveDir)
+		}
+		err := fmt.Errorf("the database was created for chain ID %d but ETHEREUM_CHAIN_ID is %d; set ETHEREUM_CHAIN_ID and ETHEREUM_RPC_URL back to chain %d, or set FORCE_CHAIN_SWITCH to archive the existing orders and switch to chain %d", metadata.EthereumChainID, chainID, metadata.EthereumChainID, chainID)
+		log.WithError(err).Error("Mesh previously started on different Ethereum chain")
 		return nil, err
 	}
 	return metadata, nil
 }
 
 func (app *App) Start(ctx context.Context) error {
+	// Ship any buffered log entries before the App stops, since the process
+	// usually exits right afterwards.
+	defer stopLogShipping()
+
 	// Get the publish topics depending on our custom order filter.
 	publishTopics, err := getPublishTopics(app.config.EthereumChainID, *app.contractAddresses, app.orderFilter)
 	if err != nil {
 		return err
 	}
 
+	// Ensure that RPC client is on the same ChainID as is configured with
+	// ETHEREUM_CHAIN_ID before anything is started, so that orders for one chain
+	// are never validated against another chain.
+	if app.config.RelayOnly {
+		log.Warn("running in relay-only mode: not connecting to Ethereum and orders are only validated offline")
+	} else {
+		app.reportStartupPhase(StartupPhaseConnectingToEthereum)
+		if err := app.checkEthRPCChainID(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Create a child context so that we can preemptively cancel if there is an
 	// error.
 	innerCtx, cancel := context.WithCancel(ctx)
@@ -551,6 +1459,17 @@ func (app *App) Start(ctx context.Context) error {
 		app.db.Close()
 	}()
 
+	// Stop the worker pool when the context is canceled.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			log.Debug("closing app.workerPool")
+		}()
+		<-innerCtx.Done()
+		app.workerPool.Close()
+	}()
+
 	// Start rateLimiter
 	ethRPCRateLimiterErrChan := make(chan error, 1)
 	wg.Add(1)
@@ -569,88 +1488,168 @@ func (app *App) Start(ctx context.Context) error {
 		defer func() {
 			log.Debug("closing snapshot expiration watcher")
 		}()
-		ticker := time.NewTicker(expirationPollingInterval)
-		for {
-			select {
-			case <-innerCtx.Done():
-				return
-			case now := <-ticker.C:
-				expiredSnapshots := app.snapshotExpirationWatcher.Prune(now)
-				for _, expiredSnapshot := range expiredSnapshots {
-					app.muIdToSnapshotInfo.Lock()
-					delete(app.idToSnapshotInfo, expiredSnapshot.ID)
-					app.muIdToSnapshotInfo.Unlock()
-				}
+		app.snapshotExpirationWatcher.PruneOnExpiration(innerCtx, func(expiredSnapshots []expirationwatch.ExpiredItem) {
+			app.muIdToSnapshotInfo.Lock()
+			defer app.muIdToSnapshotInfo.Unlock()
+			for _, expiredSnapshot := range expiredSnapshots {
+				delete(app.idToSnapshotInfo, expiredSnapshot.ID)
 			}
-		}
+		})
 	}()
 
-	// Start the order watcher.
-	orderWatcherErrChan := make(chan error, 1)
+	// Periodically re-resolve ENS names on the maker lists.
+	if app.config.ENSRefreshInterval > 0 && (hasENSNames(app.makerAllowlistEntries) || hasENSNames(app.makerDenylistEntries)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				log.Debug("closing ENS name refresher")
+			}()
+			app.refreshENSNamesPeriodically(innerCtx)
+		}()
+	}
+
+	// Coalesce the order events sent to subscribers if enabled. We subscribe
+	// before starting the order watcher so that no events are missed.
+	if app.orderEventCoalescer != nil {
+		coalescerOrderEventsChan := make(chan []*zeroex.OrderEvent, coalescerOrderEventsBufferSize)
+		coalescerOrderEventsSub := app.orderWatcher.Subscribe(coalescerOrderEventsChan)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				log.Debug("closing order event coalescer")
+			}()
+			defer coalescerOrderEventsSub.Unsubscribe()
+			app.orderEventCoalescer.run(innerCtx, coalescerOrderEventsChan)
+		}()
+	}
+
+	// Record the state history of orders for GetOrderByHash. We subscribe
+	// before starting the order watcher so that no events are missed.
+	historyOrderEventsChan := make(chan []*zeroex.OrderEvent, historyOrderEventsBufferSize)
+	historyOrderEventsSub := app.orderWatcher.Subscribe(historyOrderEventsChan)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		defer func() {
-			log.Debug("closing order watcher")
+			log.Debug("closing order history")
 		}()
-		log.Info("starting order watcher")
-		orderWatcherErrChan <- app.orderWatcher.Watch(innerCtx)
+		defer historyOrderEventsSub.Unsubscribe()
+		app.orderHistory.record(innerCtx, historyOrderEventsChan)
 	}()
 
-	// Ensure that RPC client is on the same ChainID as is configured with ETHEREUM_CHAIN_ID
-	chainIDMismatchErrChan := make(chan error, 1)
+	// Record order events so that order event subscriptions can be resumed. We
+	// subscribe before starting the order watcher so that no events are missed.
+	journalOrderEventsChan := make(chan []*zeroex.OrderEvent, journalOrderEventsBufferSize)
+	journalOrderEventsSub := app.SubscribeToOrderEvents(journalOrderEventsChan)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		defer func() {
-			log.Debug("closing chainID checker")
+			log.Debug("closing order event journal")
 		}()
-
-		chainID, err := app.getEthRPCChainID(innerCtx)
-		if err != nil {
-			chainIDMismatchErrChan <- err
-			return
-		}
-
-		configChainID := app.config.EthereumChainID
-		if int64(configChainID) != chainID.Int64() {
-			chainIDMismatchErrChan <- fmt.Errorf("ChainID mismatch between RPC client (chainID: %d) and configured environment variable ETHEREUM_CHAIN_ID: %d", chainID, configChainID)
-		}
+		defer journalOrderEventsSub.Unsubscribe()
+		app.orderEventJournal.record(innerCtx, journalOrderEventsChan)
 	}()
 
-	// Note: this is a blocking call so we won't continue set up until its finished.
-	blocksElapsed, err := app.blockWatcher.FastSyncToLatestBlock(innerCtx)
-	if err != nil {
-		return err
-	}
+	// Measure the latency until ADDED events of orders received via GossipSub
+	// are emitted to subscribers. We subscribe before starting the order
+	// watcher so that no events are missed.
+	latencyOrderEventsChan := make(chan []*zeroex.OrderEvent, ingestionLatencyOrderEventsBufferSize)
+	latencyOrderEventsSub := app.SubscribeToOrderEvents(latencyOrderEventsChan)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			log.Debug("closing ingestion latency tracker")
+		}()
+		defer latencyOrderEventsSub.Unsubscribe()
+		app.ingestionLatency.record(innerCtx, latencyOrderEventsChan)
+	}()
 
-	// Start the block watcher.
-	blockWatcherErrChan := make(chan error, 1)
+	// Start the order watcher.
+	orderWatcherErrChan := make(chan error, 1)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		defer func() {
-			log.Debug("closing block watcher")
+			log.Debug("closing order watcher")
 		}()
-		log.Info("starting block watcher")
-		blockWatcherErrChan <- app.blockWatcher.Watch(innerCtx)
+		log.Info("starting order watcher")
+		orderWatcherErrChan <- app.orderWatcher.Watch(innerCtx)
 	}()
 
-	// If Mesh is not caught up with the latest block found via Ethereum RPC, ensure orderWatcher
-	// has processed at least one recent block before starting the P2P node and completing app start,
-	// so that Mesh does not validate any orders at outdated block heights
-	isCaughtUp := app.IsCaughtUpToLatestBlock(innerCtx)
-	if !isCaughtUp {
-		if err := app.orderWatcher.WaitForAtLeastOneBlockToBeProcessed(ctx); err != nil {
+	// In relay-only mode, there are no blocks to sync and no orders to
+	// re-validate.
+	blockWatcherErrChan := make(chan error, 1)
+	if !app.config.RelayOnly {
+		// Note: this is a blocking call so we won't continue set up until its finished.
+		app.reportStartupPhase(StartupPhaseSyncingBlocks)
+		blocksElapsed, err := app.blockWatcher.FastSyncToLatestBlock(innerCtx)
+		if err != nil {
 			return err
 		}
-	}
 
-	if blocksElapsed >= constants.MaxBlocksStoredInNonArchiveNode {
-		log.WithField("blocksElapsed", blocksElapsed).Info("More than 128 blocks have elapsed since last boot. Re-validating all orders stored (this can take a while)...")
-		// Re-validate all orders since too many blocks have elapsed to fast-sync events
-		if err := app.orderWatcher.Cleanup(innerCtx, 0*time.Minute); err != nil {
-			return err
+		// Start the block watcher.
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				log.Debug("closing block watcher")
+			}()
+			log.Info("starting block watcher")
+			blockWatcherErrChan <- app.blockWatcher.Watch(innerCtx)
+		}()
+
+		// If Mesh is not caught up with the latest block found via Ethereum RPC, ensure orderWatcher
+		// has processed at least one recent block before starting the P2P node and completing app start,
+		// so that Mesh does not validate any orders at outdated block heights
+		isCaughtUp := app.IsCaughtUpToLatestBlock(innerCtx)
+		if !isCaughtUp {
+			if err := app.orderWatcher.WaitForAtLeastOneBlockToBeProcessed(ctx); err != nil {
+				return err
+			}
+		}
+
+		if blocksElapsed >= constants.MaxBlocksStoredInNonArchiveNode || app.restoredDBSnapshot {
+			// Re-validate all orders since too many blocks have elapsed to fast-sync
+			// events or since the orders were restored from a database snapshot,
+			// which is not trusted to be up to date.
+			reason := "More than 128 blocks have elapsed since last boot."
+			if app.restoredDBSnapshot {
+				reason = "Orders were restored from a database snapshot."
+			}
+			if app.config.StartupRevalidationInBackground {
+				log.WithField("blocksElapsed", blocksElapsed).Info(reason + " Re-validating all orders stored in the background...")
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() {
+						log.Debug("closing startup revalidation")
+					}()
+					// Re-validation is interrupted without an error being
+					// logged if the App is stopped.
+					if err := app.revalidateAllOrders(innerCtx); err != nil && innerCtx.Err() == nil {
+						log.WithError(err).Error("could not re-validate stored orders")
+					}
+				}()
+			} else {
+				log.WithField("blocksElapsed", blocksElapsed).Info(reason + " Re-validating all orders stored (this can take a while)...")
+				app.reportStartupPhase(StartupPhaseRevalidatingOrders)
+				progressCtx, stopProgress := context.WithCancel(innerCtx)
+				progressDone := make(chan struct{})
+				go func() {
+					defer close(progressDone)
+					app.reportRevalidationProgress(progressCtx)
+				}()
+				err := app.revalidateAllOrders(innerCtx)
+				stopProgress()
+				<-progressDone
+				if err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -661,27 +1660,75 @@ func (app *App) Start(ctx context.Context) error {
 	// app.node will be nil and attempting to call any methods on app.node will
 	// panic with a nil pointer exception. All the other fields of core.App that
 	// we need to use will have already been initialized and are ready to use.
+	app.reportStartupPhase(StartupPhaseStartingP2P)
 	bootstrapList := p2p.DefaultBootstrapList
 	if app.config.BootstrapList != "" {
 		bootstrapList = strings.Split(app.config.BootstrapList, ",")
 	}
+	var listenAddrs []string
+	if app.config.P2PListenAddrs != "" {
+		listenAddrs = strings.Split(app.config.P2PListenAddrs, ",")
+	}
+	var securityTransports []string
+	if app.config.P2PSecurityTransports != "" {
+		securityTransports = strings.Split(app.config.P2PSecurityTransports, ",")
+	}
 	rendezvousPoints, err := app.getRendezvousPoints()
 	if err != nil {
 		return err
 	}
+	initialPeers, err := app.loadInitialPeers()
+	if err != nil {
+		return err
+	}
+	var bootstrapListSigner peer.ID
+	if app.config.BootstrapListSigner != "" {
+		bootstrapListSigner, err = peer.IDB58Decode(app.config.BootstrapListSigner)
+		if err != nil {
+			return fmt.Errorf("invalid BOOTSTRAP_LIST_SIGNER: %s", err.Error())
+		}
+	}
+	metadata, err := app.db.GetMetadata()
+	if err != nil {
+		return err
+	}
 	nodeConfig := p2p.Config{
-		SubscribeTopic:         app.orderFilter.Topic(),
-		PublishTopics:          publishTopics,
-		TCPPort:                app.config.P2PTCPPort,
-		WebSocketsPort:         app.config.P2PWebSocketsPort,
-		Insecure:               false,
-		PrivateKey:             app.privKey,
-		MessageHandler:         app,
-		RendezvousPoints:       rendezvousPoints,
-		UseBootstrapList:       app.config.UseBootstrapList,
-		BootstrapList:          bootstrapList,
-		DataDir:                filepath.Join(app.config.DataDir, "p2p"),
-		CustomMessageValidator: app.orderFilter.ValidatePubSubMessage,
+		SubscribeTopic:               app.orderFilter.Topic(),
+		PublishTopics:                publishTopics,
+		TCPPort:                      app.config.P2PTCPPort,
+		WebSocketsPort:               app.config.P2PWebSocketsPort,
+		ListenAddrs:                  listenAddrs,
+		ProxyURL:                     app.proxyURL,
+		Insecure:                     false,
+		SecurityTransports:           securityTransports,
+		MinSecurityLevel:             app.config.P2PMinSecurityLevel,
+		PrivateKey:                   app.privKey,
+		MessageHandler:               app,
+		RendezvousPoints:             rendezvousPoints,
+		UseBootstrapList:             app.config.UseBootstrapList,
+		BootstrapList:                bootstrapList,
+		BootstrapListURL:             app.config.BootstrapListURL,
+		BootstrapListSigner:          bootstrapListSigner,
+		BootstrapListRefreshInterval: app.config.BootstrapListRefreshInterval,
+		LastBootstrapListTimestamp:   metadata.BootstrapListTimestamp,
+		BootstrapListUpdated:         app.saveBootstrapListTimestamp,
+		DataDir:                      filepath.Join(app.config.DataDir, "p2p"),
+		CustomMessageValidator:       app.validatePubSubMessage,
+		DisableForwarding:            app.config.DryRun,
+		MaxPendingValidationMessages: app.config.MaxPendingValidationMessages,
+		ValidationMemoryBudget:       app.config.ValidationMemoryBudget,
+		MinPeerGroups:                app.config.MinPeerGroups,
+		ASNDatabasePath:              app.config.ASNDatabasePath,
+		PeerRateLimitBanThreshold:    app.config.PeerRateLimitBanThreshold,
+		PeerRateLimitBanDuration:     app.config.PeerRateLimitBanDuration,
+		ConnectionGater:              app.config.ConnectionGater,
+		EnablePeerExchange:           app.config.EnablePeerExchange,
+		UserAgent:                    newUserAgent(version, app.config.EthereumChainID, app.orderFilter.Topic(), app.config.RelayOnly),
+		InitialPeers:                 initialPeers,
+		EnableNATPortMap:             app.config.EnableNATPortMap,
+		EnableRelayService:           app.config.EnableRelayService,
+		MaxRelayStreams:              app.config.MaxRelayStreams,
+		MaxRelayBytesPerSecond:       app.config.MaxRelayBytesPerSecond,
 	}
 	app.node, err = p2p.New(innerCtx, nodeConfig)
 	if err != nil {
@@ -693,6 +1740,9 @@ func (app *App) Start(ctx context.Context) error {
 		NewFilteredPaginationSubprotocol(app, app.privateConfig.paginationSubprotocolPerPage),
 	}
 	app.ordersyncService = ordersync.New(innerCtx, app.node, ordersyncSubprotocols)
+
+	// Respond to requests from peers asking which orders we have stored.
+	app.node.SetStreamHandler(propagationReceiptsProtocolID, app.handlePropagationReceiptsStream)
 	orderSyncErrChan := make(chan error, 1)
 	wg.Add(1)
 	go func() {
@@ -737,6 +1787,73 @@ func (app *App) Start(ctx context.Context) error {
 		p2pErrChan <- app.node.Start()
 	}()
 
+	// Report the progress of the peer discovery and the initial order sync.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			log.Debug("closing startup progress reporter")
+		}()
+		app.reportPeerDiscoveryAndSyncProgress(innerCtx)
+	}()
+
+	// Start loop for periodically saving the connected peers, so that they can
+	// be dialed right away after a restart.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			log.Debug("closing known peers saver")
+		}()
+		app.periodicallySaveKnownPeers(innerCtx)
+	}()
+
+	// Start loop for periodically pruning the audit log if enabled.
+	if app.config.EnableAuditLog && app.config.AuditLogRetentionPeriod > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				log.Debug("closing audit log pruner")
+			}()
+			app.periodicallyPruneAuditLog(innerCtx)
+		}()
+	}
+
+	// Start loop for periodically writing orderbook snapshots if enabled.
+	if app.orderbookSnapshotStore != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				log.Debug("closing orderbook snapshotter")
+			}()
+			app.periodicallySnapshotOrderbook(innerCtx)
+		}()
+	}
+
+	// Start loop for periodically sampling metrics.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			log.Debug("closing metrics sampler")
+		}()
+		app.periodicallySampleMetrics(innerCtx)
+	}()
+
+	// Start loop for periodically checking alert conditions if enabled.
+	if app.alertMonitor != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				log.Debug("closing alert checker")
+			}()
+			app.periodicallyCheckAlerts(innerCtx)
+		}()
+	}
+
 	// Start loop for periodically logging stats.
 	wg.Add(1)
 	go func() {
@@ -793,12 +1910,6 @@ func (app *App) Start(ctx context.Context) error {
 				cancel()
 				return err
 			}
-		case err := <-chainIDMismatchErrChan:
-			if err != nil {
-				log.WithError(err).Error("ETH chain id matcher exited with error")
-				cancel()
-				return err
-			}
 		case <-appClosed:
 			// If we reached here it means we are done and there are no errors.
 			log.Debug("app successfully closed")
@@ -807,6 +1918,18 @@ func (app *App) Start(ctx context.Context) error {
 	}
 }
 
+// saveBootstrapListTimestamp stores the timestamp of the bootstrap list which
+// was fetched from BootstrapListURL, so that older lists are rejected after a
+// restart.
+func (app *App) saveBootstrapListTimestamp(timestamp time.Time) {
+	if err := app.db.UpdateMetadata(func(metadata meshdb.Metadata) meshdb.Metadata {
+		metadata.BootstrapListTimestamp = timestamp
+		return metadata
+	}); err != nil {
+		log.WithError(err).Error("could not save bootstrap list timestamp")
+	}
+}
+
 func (app *App) periodicallyCheckForNewAddrs(ctx context.Context, startingAddrs []ma.Multiaddr) {
 	<-app.started
 
@@ -845,6 +1968,36 @@ func (e ErrSnapshotNotFound) Error() string {
 	return fmt.Sprintf("No snapshot found with id: %s. To create a new snapshot, send a request with an empty snapshotID", e.id)
 }
 
+// ErrOrderNotFound is the error returned when no order with a particular hash
+// is stored
+type ErrOrderNotFound struct {
+	orderHash common.Hash
+}
+
+func (e ErrOrderNotFound) Error() string {
+	return fmt.Sprintf("No order found with hash: %s", e.orderHash.Hex())
+}
+
+// ErrInvalidSimulateFillOpts is the error returned when a SimulateFill request
+// contains invalid options
+type ErrInvalidSimulateFillOpts struct {
+	reason string
+}
+
+func (e ErrInvalidSimulateFillOpts) Error() string {
+	return fmt.Sprintf("invalid simulateFill options: %s", e.reason)
+}
+
+// ErrInvalidAddressList is the error returned when a maker list contains an
+// entry which is neither an Ethereum address nor an ENS name
+type ErrInvalidAddressList struct {
+	reason string
+}
+
+func (e ErrInvalidAddressList) Error() string {
+	return fmt.Sprintf("invalid address list: %s", e.reason)
+}
+
 // ErrPerPageZero is the error returned when a GetOrders request specifies perPage to 0
 type ErrPerPageZero struct{}
 
@@ -913,12 +2066,9 @@ func (app *App) GetOrders(page, perPage int, snapshotID string) (*types.GetOrder
 		return nil, err
 	}
 	for _, order := range selectedOrders {
-		ordersInfos = append(ordersInfos, &types.OrderInfo{
-			OrderHash:                order.Hash,
-			SignedOrder:              order.SignedOrder,
-			FillableTakerAssetAmount: order.FillableTakerAssetAmount,
-		})
+		ordersInfos = append(ordersInfos, orderToOrderInfo(order))
 	}
+	app.addNotionalValues(ordersInfos)
 
 	getOrdersResponse := &types.GetOrdersResponse{
 		SnapshotID:        snapshotID,
@@ -931,48 +2081,201 @@ func (app *App) GetOrders(page, perPage int, snapshotID string) (*types.GetOrder
 
 // AddOrders can be used to add orders to Mesh. It validates the given orders
 // and if they are valid, will store and eventually broadcast the orders to
-// peers. If pinned is true, the orders will be marked as pinned, which means
-// they will only be removed if they become unfillable and will not be removed
-// due to having a high expiration time or any incentive mechanisms.
-func (app *App) AddOrders(ctx context.Context, signedOrdersRaw []*json.RawMessage, pinned bool) (*ordervalidator.ValidationResults, error) {
+// peers. If opts.Pinned is true, the orders will be marked as pinned, which
+// means they will only be removed if they become unfillable and will not be
+// removed due to having a high expiration time or any incentive mechanisms.
+// Stored orders which are replaced by accepted orders according to
+// opts.ReplacesOrderHashes are removed.
+func (app *App) AddOrders(ctx context.Context, signedOrdersRaw []*json.RawMessage, opts types.AddOrdersOpts) (*ordervalidator.ValidationResults, error) {
 	<-app.started
 
 	allValidationResults := &ordervalidator.ValidationResults{
 		Accepted: []*ordervalidator.AcceptedOrderInfo{},
 		Rejected: []*ordervalidator.RejectedOrderInfo{},
 	}
+	schemaValidOrders, schemaRejectedOrderInfos, err := app.validateOrdersAgainstSchema(signedOrdersRaw)
+	if err != nil {
+		return nil, err
+	}
+	allValidationResults.Rejected = append(allValidationResults.Rejected, schemaRejectedOrderInfos...)
+
+	if err := validateOrderMetadata(opts.Metadata); err != nil {
+		return nil, err
+	}
+	storeOpts := orderwatch.StoreOrdersOpts{
+		Pinned:   opts.Pinned,
+		Metadata: opts.Metadata,
+		Source:   orderSourceAPI,
+		Lane:     orderwatch.LocalValidationLane,
+	}
+	validationResults, err := app.orderWatcher.ValidateAndStoreValidOrdersWithOpts(ctx, schemaValidOrders, storeOpts, app.chainID)
+	if err != nil {
+		return nil, err
+	}
+	app.recordDryRun(storeOpts, validationResults)
+	if len(opts.ReplacesOrderHashes) > 0 && !app.config.DryRun {
+		replacements := map[common.Hash]common.Hash{}
+		for _, acceptedOrderInfo := range validationResults.Accepted {
+			if replacedOrderHash, found := opts.ReplacesOrderHashes[acceptedOrderInfo.OrderHash]; found {
+				replacements[acceptedOrderInfo.OrderHash] = replacedOrderHash
+			}
+		}
+		if _, err := app.orderWatcher.ReplaceOrders(replacements); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, orderInfo := range validationResults.Accepted {
+		allValidationResults.Accepted = append(allValidationResults.Accepted, orderInfo)
+	}
+	for _, orderInfo := range validationResults.Rejected {
+		allValidationResults.Rejected = append(allValidationResults.Rejected, orderInfo)
+	}
+	app.rejectionLog.addValidationResults(orderSourceAPI, "", allValidationResults)
+
+	for _, acceptedOrderInfo := range allValidationResults.Accepted {
+		// If the order isn't new, we don't add to OrderWatcher, log it's receipt
+		// or share the order with peers.
+		if !acceptedOrderInfo.IsNew {
+			continue
+		}
+
+		log.WithFields(log.Fields{
+			"orderHash": acceptedOrderInfo.OrderHash.String(),
+		}).Debug("added new valid order via RPC or browser callback")
+
+		// Share the order with our peers.
+		if app.config.DryRun || app.isLocalOnlyOrder(acceptedOrderInfo.SignedOrder) {
+			continue
+		}
+		if err := app.shareOrder(acceptedOrderInfo.SignedOrder); err != nil {
+			return nil, err
+		}
+	}
+
+	return allValidationResults, nil
+}
+
+// ValidateOrders validates the given orders without storing or sharing them.
+// Depending on opts, orders can be validated against the pending state of the
+// blockchain and/or with state overrides applied, which lets takers check
+// whether orders will still be fillable after their own pending transactions
+// have been mined. Only the orders themselves and their on-chain state are
+// validated. Node-specific restrictions such as the maker allowlist or the
+// maximum expiration time are not applied.
+func (app *App) ValidateOrders(ctx context.Context, signedOrdersRaw []*json.RawMessage, opts types.ValidateOrdersOpts) (*ordervalidator.ValidationResults, error) {
+	<-app.started
+
+	if app.config.RelayOnly {
+		return nil, ErrRelayOnly
+	}
+	schemaValidOrders, schemaRejectedOrderInfos, err := app.validateOrdersAgainstSchema(signedOrdersRaw)
+	if err != nil {
+		return nil, err
+	}
+	stateOpts := ordervalidator.StateOptions{
+		Pending:        opts.Pending,
+		StateOverrides: opts.StateOverrides,
+	}
+	validationResults, err := app.orderValidator.BatchValidateAtState(ctx, schemaValidOrders, stateOpts)
+	if err != nil {
+		return nil, err
+	}
+	allValidationResults := &ordervalidator.ValidationResults{
+		Accepted: []*ordervalidator.AcceptedOrderInfo{},
+		Rejected: schemaRejectedOrderInfos,
+	}
+	allValidationResults.Accepted = append(allValidationResults.Accepted, validationResults.Accepted...)
+	allValidationResults.Rejected = append(allValidationResults.Rejected, validationResults.Rejected...)
+	return allValidationResults, nil
+}
+
+// SimulateFill simulates filling the stored order with the given hash via
+// eth_call from opts.TakerAddress and returns the expected fill results and gas
+// usage, or the reason the fill would revert.
+func (app *App) SimulateFill(ctx context.Context, orderHash common.Hash, opts types.SimulateFillOpts) (*types.SimulateFillResult, error) {
+	<-app.started
+
+	if app.config.RelayOnly {
+		return nil, ErrRelayOnly
+	}
+	takerAssetFillAmount, ok := math.ParseBig256(opts.TakerAssetFillAmount)
+	if !ok || takerAssetFillAmount.Sign() <= 0 {
+		return nil, ErrInvalidSimulateFillOpts{reason: "takerAssetFillAmount must be a positive uint256"}
+	}
+	var gasPrice *big.Int
+	if opts.GasPrice != "" {
+		gasPrice, ok = math.ParseBig256(opts.GasPrice)
+		if !ok {
+			return nil, ErrInvalidSimulateFillOpts{reason: "gasPrice must be a uint256"}
+		}
+	}
+
+	var order meshdb.Order
+	if err := app.db.Orders.FindByID(orderHash.Bytes(), &order); err != nil {
+		if _, ok := err.(db.NotFoundError); ok {
+			return nil, ErrOrderNotFound{orderHash: orderHash}
+		}
+		return nil, err
+	}
+
+	simulation, err := app.orderValidator.SimulateFill(ctx, order.SignedOrder, ordervalidator.SimulateFillOpts{
+		TakerAddress:         opts.TakerAddress,
+		TakerAssetFillAmount: takerAssetFillAmount,
+		GasPrice:             gasPrice,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !simulation.Success {
+		return &types.SimulateFillResult{RevertReason: simulation.RevertReason}, nil
+	}
+	return &types.SimulateFillResult{
+		Success:                true,
+		MakerAssetFilledAmount: simulation.FillResults.MakerAssetFilledAmount.String(),
+		TakerAssetFilledAmount: simulation.FillResults.TakerAssetFilledAmount.String(),
+		MakerFeePaid:           simulation.FillResults.MakerFeePaid.String(),
+		TakerFeePaid:           simulation.FillResults.TakerFeePaid.String(),
+		ProtocolFeePaid:        simulation.FillResults.ProtocolFeePaid.String(),
+		EstimatedGas:           simulation.EstimatedGas,
+	}, nil
+}
+
+// validateOrdersAgainstSchema decodes the given orders and validates them
+// against the JSON schema used by this node. It returns the decoded orders
+// which are valid, without duplicates, and the rejected order infos for the
+// ones which are not.
+func (app *App) validateOrdersAgainstSchema(signedOrdersRaw []*json.RawMessage) ([]*zeroex.SignedOrder, []*ordervalidator.RejectedOrderInfo, error) {
+	rejectedOrderInfos := []*ordervalidator.RejectedOrderInfo{}
 	orderHashesSeen := map[common.Hash]struct{}{}
 	schemaValidOrders := []*zeroex.SignedOrder{}
 	for _, signedOrderRaw := range signedOrdersRaw {
 		signedOrderBytes := []byte(*signedOrderRaw)
 		result, err := app.orderFilter.ValidateOrderJSON(signedOrderBytes)
+		if err == nil && app.shadowFilter != nil {
+			app.shadowFilter.evaluateOrderJSON(signedOrderBytes, result.Valid())
+		}
 		if err != nil {
 			signedOrder := &zeroex.SignedOrder{}
 			if err := signedOrder.UnmarshalJSON(signedOrderBytes); err != nil {
 				signedOrder = nil
 			}
 			log.WithField("signedOrderRaw", string(signedOrderBytes)).Info("Unexpected error while attempting to validate signedOrderJSON against schema")
-			allValidationResults.Rejected = append(allValidationResults.Rejected, &ordervalidator.RejectedOrderInfo{
+			rejectedOrderInfos = append(rejectedOrderInfos, &ordervalidator.RejectedOrderInfo{
 				SignedOrder: signedOrder,
 				Kind:        ordervalidator.MeshValidation,
-				Status: ordervalidator.RejectedOrderStatus{
-					Code:    ordervalidator.ROInvalidSchemaCode,
-					Message: "order did not pass JSON-schema validation: Malformed JSON or empty payload",
-				},
+				Status:      ordervalidator.ROInvalidSchema.WithMessage("order did not pass JSON-schema validation: Malformed JSON or empty payload"),
 			})
 			continue
 		}
 		if !result.Valid() {
 			log.WithField("signedOrderRaw", string(signedOrderBytes)).Info("Order failed schema validation")
-			status := ordervalidator.RejectedOrderStatus{
-				Code:    ordervalidator.ROInvalidSchemaCode,
-				Message: fmt.Sprintf("order did not pass JSON-schema validation: %s", result.Errors()),
-			}
+			status := ordervalidator.ROInvalidSchema.WithMessage(fmt.Sprintf("order did not pass JSON-schema validation: %s", result.Errors()))
 			signedOrder := &zeroex.SignedOrder{}
 			if err := signedOrder.UnmarshalJSON(signedOrderBytes); err != nil {
 				signedOrder = nil
 			}
-			allValidationResults.Rejected = append(allValidationResults.Rejected, &ordervalidator.RejectedOrderInfo{
+			rejectedOrderInfos = append(rejectedOrderInfos, &ordervalidator.RejectedOrderInfo{
 				SignedOrder: signedOrder,
 				Kind:        ordervalidator.MeshValidation,
 				Status:      status,
@@ -984,12 +2287,12 @@ func (app *App) AddOrders(ctx context.Context, signedOrdersRaw []*json.RawMessag
 		if err := signedOrder.UnmarshalJSON(signedOrderBytes); err != nil {
 			// This error should never happen since the signedOrder already passed the JSON schema validation above
 			log.WithField("signedOrderRaw", string(signedOrderBytes)).Error("Failed to unmarshal SignedOrder")
-			return nil, err
+			return nil, nil, err
 		}
 
 		orderHash, err := signedOrder.ComputeOrderHash()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if _, alreadySeen := orderHashesSeen[orderHash]; alreadySeen {
 			continue
@@ -999,36 +2302,7 @@ func (app *App) AddOrders(ctx context.Context, signedOrdersRaw []*json.RawMessag
 		orderHashesSeen[orderHash] = struct{}{}
 	}
 
-	validationResults, err := app.orderWatcher.ValidateAndStoreValidOrders(ctx, schemaValidOrders, pinned, app.chainID)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, orderInfo := range validationResults.Accepted {
-		allValidationResults.Accepted = append(allValidationResults.Accepted, orderInfo)
-	}
-	for _, orderInfo := range validationResults.Rejected {
-		allValidationResults.Rejected = append(allValidationResults.Rejected, orderInfo)
-	}
-
-	for _, acceptedOrderInfo := range allValidationResults.Accepted {
-		// If the order isn't new, we don't add to OrderWatcher, log it's receipt
-		// or share the order with peers.
-		if !acceptedOrderInfo.IsNew {
-			continue
-		}
-
-		log.WithFields(log.Fields{
-			"orderHash": acceptedOrderInfo.OrderHash.String(),
-		}).Debug("added new valid order via RPC or browser callback")
-
-		// Share the order with our peers.
-		if err := app.shareOrder(acceptedOrderInfo.SignedOrder); err != nil {
-			return nil, err
-		}
-	}
-
-	return allValidationResults, nil
+	return schemaValidOrders, rejectedOrderInfos, nil
 }
 
 // shareOrder immediately shares the given order on the GossipSub network.
@@ -1049,17 +2323,74 @@ func (app *App) AddPeer(peerInfo peerstore.PeerInfo) error {
 	return app.node.Connect(peerInfo, peerConnectTimeout)
 }
 
+// GetPeers returns the peers the node is currently connected to.
+func (app *App) GetPeers() []*types.PeerInfo {
+	<-app.started
+
+	ownTopicHash := topicHash(app.orderFilter.Topic())
+	now := time.Now()
+	peerDetails := app.node.PeerDetails()
+	peerInfos := make([]*types.PeerInfo, len(peerDetails))
+	for i, details := range peerDetails {
+		peerInfo := &types.PeerInfo{
+			PeerID:           details.ID.Pretty(),
+			Multiaddrs:       make([]string, len(details.Connections)),
+			UserAgent:        details.UserAgent,
+			Connections:      make([]*types.PeerConnectionInfo, len(details.Connections)),
+			Protocols:        details.Protocols,
+			Topics:           details.Topics,
+			Score:            details.Score,
+			MessagesReceived: details.MessagesReceived,
+			MessagesRejected: details.MessagesRejected,
+			BytesReceived:    details.BytesReceived,
+			BytesSent:        details.BytesSent,
+		}
+		for j, conn := range details.Connections {
+			peerInfo.Multiaddrs[j] = conn.RemoteMultiaddr
+			peerInfo.Connections[j] = &types.PeerConnectionInfo{
+				Multiaddr: conn.RemoteMultiaddr,
+				Direction: conn.Direction,
+				OpenedAt:  conn.OpenedAt,
+			}
+			if !conn.OpenedAt.IsZero() && now.Sub(conn.OpenedAt) > peerInfo.ConnectionAge {
+				peerInfo.ConnectionAge = now.Sub(conn.OpenedAt)
+			}
+		}
+		if parsed, ok := parseUserAgent(peerInfo.UserAgent); ok {
+			peerInfo.MeshVersion = parsed.version
+			peerInfo.ChainID = parsed.chainID
+			peerInfo.TopicHash = parsed.topicHash
+			peerInfo.SameTopic = parsed.topicHash == ownTopicHash
+			peerInfo.RelayOnly = parsed.relayOnly
+		}
+		peerInfos[i] = peerInfo
+	}
+	return peerInfos
+}
+
+// BanPeer bans the IP addresses of the given peer and disconnects from it.
+func (app *App) BanPeer(peerID peer.ID) error {
+	<-app.started
+
+	log.WithField("peerID", peerID.Pretty()).Info("banning peer")
+	return app.node.BanPeer(peerID)
+}
+
 // GetStats retrieves stats about the Mesh node
 func (app *App) GetStats() (*types.Stats, error) {
 	<-app.started
 
-	latestBlockHeader, err := app.db.FindLatestMiniHeader()
-	if err != nil {
-		return nil, err
-	}
-	latestBlock := types.LatestBlock{
-		Number: int(latestBlockHeader.Number.Int64()),
-		Hash:   latestBlockHeader.Hash,
+	// No blocks are stored in relay-only mode.
+	var latestBlock types.LatestBlock
+	if !app.config.RelayOnly {
+		latestBlockHeader, err := app.db.FindLatestMiniHeader()
+		if err != nil {
+			return nil, err
+		}
+		latestBlock = types.LatestBlock{
+			Number: int(latestBlockHeader.Number.Int64()),
+			Hash:   latestBlockHeader.Hash,
+		}
 	}
 	notRemovedFilter := app.db.Orders.IsRemovedIndex.ValueFilter([]byte{0})
 	numOrders, err := app.db.Orders.NewQuery(notRemovedFilter).Count()
@@ -1082,6 +2413,10 @@ func (app *App) GetStats() (*types.Stats, error) {
 	if err != nil {
 		return nil, err
 	}
+	peerLatencies := map[string]time.Duration{}
+	for peerID, latency := range app.node.PeerLatencies() {
+		peerLatencies[peerID.Pretty()] = latency
+	}
 
 	response := &types.Stats{
 		Version:                           version,
@@ -1090,19 +2425,142 @@ func (app *App) GetStats() (*types.Stats, error) {
 		SecondaryRendezvous:               rendezvousPoints[1:],
 		PeerID:                            app.peerID.String(),
 		EthereumChainID:                   app.config.EthereumChainID,
+		RelayOnly:                         app.config.RelayOnly,
 		LatestBlock:                       latestBlock,
 		NumOrders:                         numOrders,
 		NumPeers:                          app.node.GetNumPeers(),
+		PeerLatencies:                     peerLatencies,
 		NumOrdersIncludingRemoved:         numOrdersIncludingRemoved,
 		NumPinnedOrders:                   numPinnedOrders,
 		MaxExpirationTime:                 app.orderWatcher.MaxExpirationTime().String(),
 		StartOfCurrentUTCDay:              metadata.StartOfCurrentUTCDay,
 		EthRPCRequestsSentInCurrentUTCDay: metadata.EthRPCRequestsSentInCurrentUTCDay,
-		EthRPCRateLimitExpiredRequests:    app.ethRPCClient.GetRateLimitDroppedRequests(),
+		NumPendingValidation:              app.node.ValidationQueueStats().NumPending,
+		RateLimiting:                      rateLimitStatsToTypes(app.node.RateLimitStats()),
+		SignatureCacheHitRate:             app.signatureCache.hitRate(),
+		LastCleanup:                       cleanupStatsToTypes(app.orderWatcher.LastCleanupStats()),
+		StartupRevalidation:               app.GetStartupRevalidationStats(),
+		Reachability:                      reachabilityToTypes(app.node.Reachability()),
+		Subscriptions:                     app.getSubscriptionStats(),
+		Chain:                             chainStatsToTypes(app.orderWatcher.ChainStats()),
+		IngestionLatency:                  app.ingestionLatency.getStats(),
+	}
+	if app.ethRPCClient != nil {
+		response.EthRPCRateLimitExpiredRequests = app.ethRPCClient.GetRateLimitDroppedRequests()
+	}
+	if app.shadowFilter != nil {
+		response.ShadowFilter = app.shadowFilter.getStats()
 	}
 	return response, nil
 }
 
+func chainStatsToTypes(stats orderwatch.ChainStats) types.ChainStats {
+	result := types.ChainStats{
+		NumBlocksProcessed:   stats.NumBlocksProcessed,
+		NumReorgs:            stats.NumReorgs,
+		ReorgsByDepth:        stats.ReorgsByDepth,
+		NumLogsDecoded:       stats.NumLogsDecoded,
+		NumOrdersRevalidated: stats.NumOrdersRevalidated,
+		NumValidationCalls:   stats.NumValidationCalls,
+		LastBlock: types.BlockProcessingStats{
+			NumLogsDecoded:       stats.LastBlock.NumLogsDecoded,
+			NumOrdersRevalidated: stats.LastBlock.NumOrdersRevalidated,
+			NumValidationCalls:   stats.LastBlock.NumValidationCalls,
+		},
+	}
+	if stats.LastBlock.Number != nil {
+		result.LastBlock.Number = int(stats.LastBlock.Number.Int64())
+	}
+	return result
+}
+
+func reachabilityToTypes(reachability p2p.Reachability) types.ReachabilityStats {
+	result := types.ReachabilityStats{
+		NATStatus:             reachability.NATStatus,
+		ConfirmedPublicAddrs:  make([]string, len(reachability.ConfirmedPublicAddrs)),
+		AdvertisedPublicAddrs: make([]string, len(reachability.AdvertisedPublicAddrs)),
+	}
+	for i, addr := range reachability.ConfirmedPublicAddrs {
+		result.ConfirmedPublicAddrs[i] = addr.String()
+	}
+	for i, addr := range reachability.AdvertisedPublicAddrs {
+		result.AdvertisedPublicAddrs[i] = addr.String()
+	}
+	return result
+}
+
+func rateLimitStatsToTypes(stats ratevalidator.Stats) types.RateLimitStats {
+	return types.RateLimitStats{
+		PerPeerLimitViolations: stats.PerPeerLimitViolations,
+		GlobalLimitViolations:  stats.GlobalLimitViolations,
+		OversizedMessages:      stats.OversizedMessages,
+		NumBans:                stats.NumBans,
+	}
+}
+
+// revalidateAllOrders re-validates all stored orders according to the
+// configured batch size and delay.
+func (app *App) revalidateAllOrders(ctx context.Context) error {
+	err := app.orderWatcher.RevalidateAllOrders(ctx, app.config.StartupRevalidationBatchSize, app.config.StartupRevalidationBatchDelay)
+	if err != nil {
+		return err
+	}
+	progress := app.orderWatcher.RevalidationProgress()
+	log.WithFields(log.Fields{
+		"numOrdersRevalidated": progress.NumOrdersRevalidated,
+		"duration":             progress.EndTime.Sub(progress.StartTime).String(),
+	}).Info("finished re-validating stored orders")
+	return nil
+}
+
+// IsReady returns true if the App has started, i.e. it is connected to the
+// Ethereum RPC endpoint, has caught up with the latest block and has finished
+// re-validating stored orders (unless STARTUP_REVALIDATION_IN_BACKGROUND is
+// set). Unlike most other methods, it does not block until the App has
+// started.
+func (app *App) IsReady() bool {
+	select {
+	case <-app.started:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetStartupRevalidationStats returns information about the progress of the
+// re-validation of stored orders on startup. It does not block until the App
+// has started.
+func (app *App) GetStartupRevalidationStats() types.StartupRevalidationStats {
+	return revalidationProgressToTypes(app.orderWatcher.RevalidationProgress())
+}
+
+func revalidationProgressToTypes(progress orderwatch.RevalidationProgress) types.StartupRevalidationStats {
+	result := types.StartupRevalidationStats{
+		StartTime:            progress.StartTime,
+		EndTime:              progress.EndTime,
+		NumOrdersTotal:       progress.NumOrdersTotal,
+		NumOrdersRevalidated: progress.NumOrdersRevalidated,
+	}
+	if progress.Err != nil {
+		result.Error = progress.Err.Error()
+	}
+	return result
+}
+
+func cleanupStatsToTypes(stats orderwatch.CleanupStats) types.CleanupStats {
+	result := types.CleanupStats{
+		StartTime:         stats.StartTime,
+		Duration:          stats.Duration,
+		NumOrdersChecked:  stats.NumOrdersChecked,
+		NumOrdersDeferred: stats.NumOrdersDeferred,
+		NumOrderEvents:    stats.NumOrderEvents,
+	}
+	if stats.Err != nil {
+		result.Error = stats.Err.Error()
+	}
+	return result
+}
+
 func (app *App) periodicallyLogStats(ctx context.Context) {
 	<-app.started
 
@@ -1120,6 +2578,14 @@ func (app *App) periodicallyLogStats(ctx context.Context) {
 			log.WithError(err).Error("could not get stats")
 			continue
 		}
+		if app.multicallBatcher != nil {
+			batcherStats := app.multicallBatcher.Stats()
+			log.WithFields(log.Fields{
+				"numCalls":          batcherStats.NumCalls,
+				"numBatches":        batcherStats.NumBatches,
+				"numUnbatchedCalls": batcherStats.NumUnbatchedCalls,
+			}).Info("multicall batching stats")
+		}
 		log.WithFields(log.Fields{
 			"version":                           stats.Version,
 			"pubSubTopic":                       stats.PubSubTopic,
@@ -1134,17 +2600,57 @@ func (app *App) periodicallyLogStats(ctx context.Context) {
 			"startOfCurrentUTCDay":              stats.StartOfCurrentUTCDay,
 			"ethRPCRequestsSentInCurrentUTCDay": stats.EthRPCRequestsSentInCurrentUTCDay,
 			"ethRPCRateLimitExpiredRequests":    stats.EthRPCRateLimitExpiredRequests,
+			"numPendingValidation":              stats.NumPendingValidation,
+			"signatureCacheHitRate":             stats.SignatureCacheHitRate,
+			"lastCleanup":                       stats.LastCleanup,
+			"startupRevalidation":               stats.StartupRevalidation,
 		}).Info("current stats")
 	}
 }
 
-// SubscribeToOrderEvents let's one subscribe to order events emitted by the OrderWatcher
+// SubscribeToOrderEvents let's one subscribe to order events emitted by the OrderWatcher.
+// If ORDER_EVENT_COALESCING_WINDOW is set, the order events are coalesced.
 func (app *App) SubscribeToOrderEvents(sink chan<- []*zeroex.OrderEvent) event.Subscription {
 	// app.orderWatcher is guaranteed to be initialized. No need to wait.
+	if app.orderEventCoalescer != nil {
+		return app.orderEventCoalescer.Subscribe(sink)
+	}
 	subscription := app.orderWatcher.Subscribe(sink)
 	return subscription
 }
 
+// SubscribeToResumableOrderEvents subscribes sink to order events like
+// SubscribeToOrderEvents, but each batch of order events is sent along with a
+// resume token. If resumeToken is non-empty, the order events emitted after
+// it are returned so that they can be sent before any events received on
+// sink. If they are no longer available, a notification with ResyncRequired
+// set is returned instead. See orderEventJournal.subscribe for details.
+func (app *App) SubscribeToResumableOrderEvents(sink chan<- *types.OrderEventsNotification, resumeToken string) ([]*types.OrderEventsNotification, event.Subscription) {
+	return app.orderEventJournal.subscribe(sink, resumeToken)
+}
+
+// SubscribeToContractEvents let's one subscribe to all contract events decoded
+// by the OrderWatcher. It returns ErrRelayOnly in relay-only mode, since no
+// contract events are decoded without an Ethereum RPC endpoint.
+func (app *App) SubscribeToContractEvents(sink chan<- []*zeroex.ContractEvent) (event.Subscription, error) {
+	if app.config.RelayOnly {
+		return nil, ErrRelayOnly
+	}
+	// app.orderWatcher is guaranteed to be initialized. No need to wait.
+	return app.orderWatcher.SubscribeToContractEvents(sink), nil
+}
+
+// SubscribeToBlockEvents let's one subscribe to the block events emitted by the
+// block watcher, i.e. blocks being added to or removed from Mesh's view of the
+// chain. It returns ErrRelayOnly in relay-only mode, since the block watcher
+// is not running.
+func (app *App) SubscribeToBlockEvents(sink chan<- []*blockwatch.Event) (event.Subscription, error) {
+	if app.config.RelayOnly {
+		return nil, ErrRelayOnly
+	}
+	return app.blockWatcher.Subscribe(sink), nil
+}
+
 // IsCaughtUpToLatestBlock returns whether or not the latest block stored by Mesh corresponds
 // to the latest block retrieved from it's Ethereum RPC endpoint
 func (app *App) IsCaughtUpToLatestBlock(ctx context.Context) bool {
@@ -4,7 +4,11 @@ package core
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -39,18 +43,60 @@ func TestEthereumChainDetection(t *testing.T) {
 	defer meshDB.Close()
 
 	// simulate starting up on mainnet
-	_, err = initMetadata(1, meshDB)
+	_, err = initMetadata(1, meshDB, false, "")
 	require.NoError(t, err)
 
 	// simulate restart on same chain
-	_, err = initMetadata(1, meshDB)
+	_, err = initMetadata(1, meshDB, false, "")
 	require.NoError(t, err)
 
 	// should error when attempting to start on different chain
-	_, err = initMetadata(2, meshDB)
+	_, err = initMetadata(2, meshDB, false, "")
 	assert.Error(t, err)
 }
 
+func TestForceChainSwitch(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+	_, err = initMetadata(constants.TestChainID, meshDB, false, "")
+	require.NoError(t, err)
+
+	signedOrder := scenario.NewSignedTestOrder(t, orderopts.SetupMakerState(false))
+	orderHash, err := signedOrder.ComputeOrderHash()
+	require.NoError(t, err)
+	require.NoError(t, meshDB.Orders.Insert(&meshdb.Order{
+		Hash:                     orderHash,
+		SignedOrder:              signedOrder,
+		FillableTakerAssetAmount: big.NewInt(1),
+		LastUpdated:              time.Now(),
+	}))
+
+	archiveDir := "/tmp/test_archive/" + uuid.New().String()
+	metadata, err := initMetadata(42, meshDB, true, archiveDir)
+	require.NoError(t, err)
+	assert.Equal(t, 42, metadata.EthereumChainID)
+	storedMetadata, err := meshDB.GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, 42, storedMetadata.EthereumChainID)
+
+	// The orders of the previous chain must have been archived and removed.
+	count, err := meshDB.Orders.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	archiveFiles, err := ioutil.ReadDir(archiveDir)
+	require.NoError(t, err)
+	require.Len(t, archiveFiles, 1)
+	archivedData, err := ioutil.ReadFile(filepath.Join(archiveDir, archiveFiles[0].Name()))
+	require.NoError(t, err)
+	var archivedOrders []*zeroex.SignedOrder
+	require.NoError(t, json.Unmarshal(archivedData, &archivedOrders))
+	require.Len(t, archivedOrders, 1)
+	archivedOrderHash, err := archivedOrders[0].ComputeOrderHash()
+	require.NoError(t, err)
+	assert.Equal(t, orderHash, archivedOrderHash)
+}
+
 func TestConfigChainIDAndRPCMatchDetection(t *testing.T) {
 	if !serialTestsEnabled {
 		t.Skip("Serial tests (tests which cannot run in parallel) are disabled. You can enable them with the --serial flag")
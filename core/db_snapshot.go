@@ -0,0 +1,113 @@
+// +build !js
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xProject/0x-mesh/db"
+	"github.com/0xProject/0x-mesh/dbsnapshot"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/libp2p/go-libp2p-core/peer"
+	log "github.com/sirupsen/logrus"
+)
+
+// dbSnapshotTimeout is the maximum amount of time spent on downloading and
+// restoring a database snapshot.
+const dbSnapshotTimeout = 30 * time.Minute
+
+// restoreDBSnapshot restores the database snapshot described by the manifest
+// at DBSnapshotURL if the database has not been initialized yet. It returns
+// true if a snapshot was restored. Problems with the snapshot itself are
+// logged and cause the node to start with an empty database instead, so that
+// an unavailable snapshot never prevents a node from starting.
+func restoreDBSnapshot(config Config, meshDB *meshdb.MeshDB) (bool, error) {
+	if config.DBSnapshotURL == "" {
+		return false, nil
+	}
+	if config.DBSnapshotSigner == "" {
+		return false, errors.New("DB_SNAPSHOT_SIGNER is required if DB_SNAPSHOT_URL is set")
+	}
+	signer, err := peer.IDB58Decode(config.DBSnapshotSigner)
+	if err != nil {
+		return false, fmt.Errorf("invalid DB_SNAPSHOT_SIGNER: %s", err.Error())
+	}
+	// Snapshots are only restored on first startup, i.e. before the metadata
+	// has been stored.
+	if _, err := meshDB.GetMetadata(); err == nil {
+		return false, nil
+	} else if _, ok := err.(db.NotFoundError); !ok {
+		return false, err
+	}
+
+	// Remove any records left over from an interrupted restore.
+	if err := clearOrdersAndMiniHeaders(meshDB); err != nil {
+		return false, err
+	}
+	start := time.Now()
+	header, err := downloadAndRestoreDBSnapshot(config, meshDB, signer)
+	if err != nil {
+		log.WithError(err).WithField("url", config.DBSnapshotURL).Warn("could not restore database snapshot; starting with an empty database")
+		if err := clearOrdersAndMiniHeaders(meshDB); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+	log.WithFields(log.Fields{
+		"url":         config.DBSnapshotURL,
+		"numOrders":   header.NumOrders,
+		"blockNumber": header.BlockNumber,
+		"createdAt":   header.CreatedAt,
+		"duration":    time.Since(start).String(),
+	}).Info("restored database snapshot")
+	return true, nil
+}
+
+func downloadAndRestoreDBSnapshot(config Config, meshDB *meshdb.MeshDB, signer peer.ID) (*dbsnapshot.Header, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbSnapshotTimeout)
+	defer cancel()
+	manifest, err := dbsnapshot.FetchManifest(ctx, config.DBSnapshotURL, signer)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.ChainID != config.EthereumChainID {
+		return nil, fmt.Errorf("database snapshot was created for chain ID %d but ETHEREUM_CHAIN_ID is %d", manifest.ChainID, config.EthereumChainID)
+	}
+	log.WithFields(log.Fields{
+		"snapshotURL": manifest.SnapshotURL,
+		"numOrders":   manifest.NumOrders,
+		"blockNumber": manifest.BlockNumber,
+		"createdAt":   manifest.CreatedAt,
+	}).Info("downloading database snapshot")
+
+	// The snapshot is written to a temporary file so that it is only restored
+	// after its checksum has been verified.
+	path := filepath.Join(config.DataDir, "db_snapshot.tmp")
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(path)
+	}()
+	if err := dbsnapshot.Download(ctx, manifest, file); err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		return nil, err
+	}
+	return dbsnapshot.Restore(meshDB, file, config.EthereumChainID)
+}
+
+func clearOrdersAndMiniHeaders(meshDB *meshdb.MeshDB) error {
+	if err := meshDB.ClearAllOrders(); err != nil {
+		return err
+	}
+	return meshDB.ClearAllMiniHeaders()
+}
@@ -0,0 +1,19 @@
+// +build js,wasm
+
+package core
+
+import (
+	"errors"
+
+	"github.com/0xProject/0x-mesh/meshdb"
+)
+
+// restoreDBSnapshot never restores a database snapshot, since database
+// snapshots are not supported in the browser. An error is returned if
+// DBSnapshotURL is set anyway.
+func restoreDBSnapshot(config Config, meshDB *meshdb.MeshDB) (bool, error) {
+	if config.DBSnapshotURL != "" {
+		return false, errors.New("DB_SNAPSHOT_URL is not supported in the browser")
+	}
+	return false, nil
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/0xProject/0x-mesh/zeroex/orderwatch"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// Actions recorded in dry run mode.
+const (
+	dryRunActionStore  = "store"
+	dryRunActionReject = "reject"
+)
+
+// dryRunRecord describes what Mesh would have done with an order if it was
+// not in dry run mode. Records are written to DryRunExportPath as JSON lines.
+type dryRunRecord struct {
+	Time         time.Time      `json:"time"`
+	Source       string         `json:"source"`
+	PeerID       string         `json:"peerID,omitempty"`
+	OrderHash    common.Hash    `json:"orderHash"`
+	MakerAddress common.Address `json:"makerAddress"`
+	Action       string         `json:"action"`
+	Code         string         `json:"code,omitempty"`
+	Message      string         `json:"message,omitempty"`
+}
+
+// recordDryRun logs what would have happened to the orders in the given
+// validation results and appends it to DryRunExportPath, if set. It does
+// nothing unless Mesh is in dry run mode.
+func (app *App) recordDryRun(opts orderwatch.StoreOrdersOpts, results *ordervalidator.ValidationResults) {
+	if !app.config.DryRun {
+		return
+	}
+	now := time.Now().UTC()
+	records := []*dryRunRecord{}
+	for _, acceptedOrderInfo := range results.Accepted {
+		if !acceptedOrderInfo.IsNew {
+			continue
+		}
+		records = append(records, &dryRunRecord{
+			Time:         now,
+			Source:       opts.Source,
+			PeerID:       opts.SourcePeerIDs[acceptedOrderInfo.OrderHash],
+			OrderHash:    acceptedOrderInfo.OrderHash,
+			MakerAddress: acceptedOrderInfo.SignedOrder.MakerAddress,
+			Action:       dryRunActionStore,
+		})
+	}
+	for _, rejectedOrderInfo := range results.Rejected {
+		record := &dryRunRecord{
+			Time:      now,
+			Source:    opts.Source,
+			PeerID:    opts.SourcePeerIDs[rejectedOrderInfo.OrderHash],
+			OrderHash: rejectedOrderInfo.OrderHash,
+			Action:    dryRunActionReject,
+			Code:      rejectedOrderInfo.Status.Code,
+			Message:   rejectedOrderInfo.Status.Message,
+		}
+		if rejectedOrderInfo.SignedOrder != nil {
+			record.MakerAddress = rejectedOrderInfo.SignedOrder.MakerAddress
+		}
+		records = append(records, record)
+	}
+
+	for _, record := range records {
+		logger := log.WithFields(log.Fields{
+			"orderHash": record.OrderHash.Hex(),
+			"source":    record.Source,
+			"peerID":    record.PeerID,
+		})
+		if record.Action == dryRunActionStore {
+			logger.Info("dry run: would have stored order")
+		} else {
+			logger.WithField("code", record.Code).Debug("dry run: would have rejected order")
+		}
+	}
+	if app.config.DryRunExportPath == "" || len(records) == 0 {
+		return
+	}
+	if err := app.exportDryRunRecords(records); err != nil {
+		log.WithError(err).Error("could not export dry run records")
+	}
+}
+
+// exportDryRunRecords appends the given records to the file at
+// DryRunExportPath as JSON lines.
+func (app *App) exportDryRunRecords(records []*dryRunRecord) error {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	app.dryRunExportMu.Lock()
+	defer app.dryRunExportMu.Unlock()
+	file, err := os.OpenFile(app.config.DryRunExportPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,106 @@
+// +build !js
+
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/0xProject/0x-mesh/zeroex/orderwatch"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordDryRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dry_run")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	exportPath := filepath.Join(dir, "dry_run.jsonl")
+	app := &App{
+		config: Config{
+			DryRun:           true,
+			DryRunExportPath: exportPath,
+		},
+	}
+
+	order := &zeroex.SignedOrder{Order: zeroex.Order{MakerAddress: constants.GanacheAccount0}}
+	opts := orderwatch.StoreOrdersOpts{
+		Source: orderSourceGossipSub,
+		SourcePeerIDs: map[common.Hash]string{
+			common.HexToHash("0x1"): "peer1",
+			common.HexToHash("0x2"): "peer2",
+			common.HexToHash("0x3"): "peer3",
+		},
+	}
+	results := &ordervalidator.ValidationResults{
+		Accepted: []*ordervalidator.AcceptedOrderInfo{
+			{OrderHash: common.HexToHash("0x1"), SignedOrder: order, IsNew: true},
+			// Orders which are not new are not recorded.
+			{OrderHash: common.HexToHash("0x2"), SignedOrder: order, IsNew: false},
+		},
+		Rejected: []*ordervalidator.RejectedOrderInfo{
+			{OrderHash: common.HexToHash("0x3"), SignedOrder: order, Status: ordervalidator.ROExpired},
+		},
+	}
+	app.recordDryRun(opts, results)
+	app.recordDryRun(orderwatch.StoreOrdersOpts{Source: orderSourceAPI}, &ordervalidator.ValidationResults{
+		Accepted: []*ordervalidator.AcceptedOrderInfo{
+			{OrderHash: common.HexToHash("0x4"), SignedOrder: order, IsNew: true},
+		},
+	})
+
+	file, err := os.Open(exportPath)
+	require.NoError(t, err)
+	defer file.Close()
+	records := []*dryRunRecord{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record dryRunRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, &record)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, records, 3)
+
+	assert.Equal(t, common.HexToHash("0x1"), records[0].OrderHash)
+	assert.Equal(t, dryRunActionStore, records[0].Action)
+	assert.Equal(t, orderSourceGossipSub, records[0].Source)
+	assert.Equal(t, "peer1", records[0].PeerID)
+	assert.Equal(t, constants.GanacheAccount0, records[0].MakerAddress)
+
+	assert.Equal(t, common.HexToHash("0x3"), records[1].OrderHash)
+	assert.Equal(t, dryRunActionReject, records[1].Action)
+	assert.Equal(t, ordervalidator.ROExpired.Code, records[1].Code)
+	assert.Equal(t, "peer3", records[1].PeerID)
+
+	assert.Equal(t, common.HexToHash("0x4"), records[2].OrderHash)
+	assert.Equal(t, orderSourceAPI, records[2].Source)
+	assert.Empty(t, records[2].PeerID)
+}
+
+func TestRecordDryRunDisabled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dry_run")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	exportPath := filepath.Join(dir, "dry_run.jsonl")
+	app := &App{
+		config: Config{
+			DryRunExportPath: exportPath,
+		},
+	}
+	app.recordDryRun(orderwatch.StoreOrdersOpts{Source: orderSourceAPI}, &ordervalidator.ValidationResults{
+		Accepted: []*ordervalidator.AcceptedOrderInfo{
+			{OrderHash: common.HexToHash("0x1"), SignedOrder: &zeroex.SignedOrder{}, IsNew: true},
+		},
+	})
+	_, err = os.Stat(exportPath)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xProject/0x-mesh/ethereum/ratelimit"
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+)
+
+// Values of Config.EthereumRPCCallBlock.
+const (
+	ethRPCCallBlockPinned = "pinned"
+	ethRPCCallBlockLatest = "latest"
+)
+
+// defaultEthCallGasLimit is the gas limit of eth_call requests for providers
+// which cap it. It matches the default RPC gas cap of Geth.
+const defaultEthCallGasLimit = 50000000
+
+// defaultEthCallOptions are the default eth_call options for each Ethereum RPC
+// provider profile. Profiles which are missing use the zero value, which
+// leaves the gas limit and timeout up to the Ethereum node.
+var defaultEthCallOptions = map[string]ordervalidator.CallOptions{
+	ratelimit.ProfileNameInfura: {
+		GasLimit: defaultEthCallGasLimit,
+		Timeout:  20 * time.Second,
+	},
+	ratelimit.ProfileNameAlchemy: {
+		GasLimit: defaultEthCallGasLimit,
+		Timeout:  20 * time.Second,
+	},
+	ratelimit.ProfileNameAlchemyGrowth: {
+		GasLimit: defaultEthCallGasLimit,
+		Timeout:  20 * time.Second,
+	},
+}
+
+// getEthCallOptions returns the options for the eth_call requests used to
+// validate orders. Settings which are not configured fall back to the defaults
+// for the Ethereum RPC provider profile.
+func getEthCallOptions(config Config) (ordervalidator.CallOptions, error) {
+	profileName := ratelimit.ResolveProfileName(config.EthereumRPCProviderProfile, config.EthereumRPCURL)
+	callOptions := defaultEthCallOptions[profileName]
+	if config.EthereumRPCCallGasLimit != 0 {
+		callOptions.GasLimit = config.EthereumRPCCallGasLimit
+	}
+	if config.EthereumRPCCallTimeout < 0 {
+		return ordervalidator.CallOptions{}, errors.New("ETHEREUM_RPC_CALL_TIMEOUT cannot be negative")
+	}
+	if config.EthereumRPCCallTimeout != 0 {
+		callOptions.Timeout = config.EthereumRPCCallTimeout
+	}
+	switch config.EthereumRPCCallBlock {
+	case "", ethRPCCallBlockPinned:
+	case ethRPCCallBlockLatest:
+		callOptions.UseLatestBlock = true
+	default:
+		return ordervalidator.CallOptions{}, fmt.Errorf("invalid ETHEREUM_RPC_CALL_BLOCK: %q (must be one of \"pinned\" or \"latest\")", config.EthereumRPCCallBlock)
+	}
+	return callOptions, nil
+}
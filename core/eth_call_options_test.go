@@ -0,0 +1,66 @@
+// +build !js
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEthCallOptions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   Config
+		expected ordervalidator.CallOptions
+	}{
+		{
+			name: "generic provider defaults",
+			config: Config{
+				EthereumRPCURL:             "http://localhost:8545",
+				EthereumRPCProviderProfile: "auto",
+				EthereumRPCCallBlock:       "pinned",
+			},
+			expected: ordervalidator.CallOptions{},
+		},
+		{
+			name: "detected provider defaults",
+			config: Config{
+				EthereumRPCURL:             "https://mainnet.infura.io/v3/abc",
+				EthereumRPCProviderProfile: "auto",
+			},
+			expected: ordervalidator.CallOptions{
+				GasLimit: defaultEthCallGasLimit,
+				Timeout:  20 * time.Second,
+			},
+		},
+		{
+			name: "overridden settings",
+			config: Config{
+				EthereumRPCURL:             "https://mainnet.infura.io/v3/abc",
+				EthereumRPCProviderProfile: "alchemy",
+				EthereumRPCCallGasLimit:    10000000,
+				EthereumRPCCallTimeout:     5 * time.Second,
+				EthereumRPCCallBlock:       "latest",
+			},
+			expected: ordervalidator.CallOptions{
+				GasLimit:       10000000,
+				Timeout:        5 * time.Second,
+				UseLatestBlock: true,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		callOptions, err := getEthCallOptions(testCase.config)
+		require.NoError(t, err, testCase.name)
+		assert.Equal(t, testCase.expected, callOptions, testCase.name)
+	}
+
+	_, err := getEthCallOptions(Config{EthereumRPCCallBlock: "pending"})
+	assert.Error(t, err)
+	_, err = getEthCallOptions(Config{EthereumRPCCallTimeout: -time.Second})
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common/math"
@@ -25,3 +26,17 @@ func (app *App) getEthRPCChainID(ctx context.Context) (*big.Int, error) {
 
 	return rpcChainID, nil
 }
+
+// checkEthRPCChainID returns an error if the Ethereum RPC endpoint is not on
+// the chain configured with ETHEREUM_CHAIN_ID.
+func (app *App) checkEthRPCChainID(ctx context.Context) error {
+	chainID, err := app.getEthRPCChainID(ctx)
+	if err != nil {
+		return err
+	}
+	configChainID := app.config.EthereumChainID
+	if int64(configChainID) != chainID.Int64() {
+		return fmt.Errorf("ChainID mismatch between RPC client (chainID: %d) and configured environment variable ETHEREUM_CHAIN_ID: %d; if ETHEREUM_RPC_URL was intentionally pointed at a different chain, set ETHEREUM_CHAIN_ID to %d and FORCE_CHAIN_SWITCH to archive the orders of the previous chain", chainID, configChainID, chainID)
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// ingestionLatencyPendingSize is the maximum number of orders received via
+	// GossipSub for which no ADDED event has been emitted yet that are
+	// tracked. Orders for which no event is ever emitted are eventually
+	// evicted.
+	ingestionLatencyPendingSize = 10000
+	// ingestionLatencySampleSize is the number of most recent samples per
+	// stage from which the percentiles are computed.
+	ingestionLatencySampleSize = 1024
+	// ingestionLatencyOrderEventsBufferSize is the buffer size of the channel
+	// on which the ingestion latency tracker receives order events.
+	ingestionLatencyOrderEventsBufferSize = 100
+)
+
+// ingestionStage is a stage of the pipeline through which orders received via
+// GossipSub pass before their ADDED event is emitted.
+type ingestionStage int
+
+const (
+	// stageQueue is the time a message spends in the validation queue, from
+	// its receipt until HandleMessages is called with it.
+	stageQueue ingestionStage = iota
+	// stageDecoding is the time it takes to decode the message and to verify
+	// the signature of the order.
+	stageDecoding
+	// stageValidation is the time it takes to validate and store the order.
+	stageValidation
+	// stageEmission is the time from the order being stored until its ADDED
+	// event is emitted to subscribers, including ORDER_EVENT_COALESCING_WINDOW.
+	stageEmission
+	// stageTotal is the time from the receipt of the message until the ADDED
+	// event is emitted.
+	stageTotal
+	numIngestionStages
+)
+
+var ingestionStageNames = [numIngestionStages]string{
+	stageQueue:      "queue",
+	stageDecoding:   "decoding",
+	stageValidation: "validation",
+	stageEmission:   "emission",
+	stageTotal:      "total",
+}
+
+// ingestionLatencyBuckets are the upper bounds of the buckets of the latency
+// histograms exposed to Prometheus.
+var ingestionLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	60 * time.Second,
+	300 * time.Second,
+}
+
+// pendingIngestion holds the timestamps of an order which is making its way
+// through the ingestion pipeline.
+type pendingIngestion struct {
+	receivedAt  time.Time
+	handledAt   time.Time
+	decodedAt   time.Time
+	validatedAt time.Time
+	emittedAt   time.Time
+}
+
+// latencyHistogram keeps the most recent samples of a stage for computing
+// percentiles and cumulative bucket counts for Prometheus.
+type latencyHistogram struct {
+	samples []time.Duration
+	next    int
+	buckets []uint64
+	count   uint64
+	sum     time.Duration
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{
+		samples: make([]time.Duration, 0, ingestionLatencySampleSize),
+		buckets: make([]uint64, len(ingestionLatencyBuckets)),
+	}
+}
+
+func (h *latencyHistogram) observe(latency time.Duration) {
+	if latency < 0 {
+		latency = 0
+	}
+	if len(h.samples) < ingestionLatencySampleSize {
+		h.samples = append(h.samples, latency)
+	} else {
+		h.samples[h.next] = latency
+		h.next = (h.next + 1) % ingestionLatencySampleSize
+	}
+	for i, upperBound := range ingestionLatencyBuckets {
+		if latency <= upperBound {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += latency
+}
+
+func (h *latencyHistogram) stats() types.LatencyStats {
+	stats := types.LatencyStats{Count: h.count}
+	if len(h.samples) == 0 {
+		return stats
+	}
+	sorted := make([]time.Duration, len(h.samples))
+	copy(sorted, h.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	stats.P50 = percentile(sorted, 50)
+	stats.P95 = percentile(sorted, 95)
+	stats.P99 = percentile(sorted, 99)
+	return stats
+}
+
+// percentile returns the p-th percentile of the given sorted samples using the
+// nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// ingestionLatencyTracker measures how long it takes for new orders received
+// via GossipSub to pass through each stage of the ingestion pipeline until
+// their ADDED event is emitted. Only orders which are accepted and new are
+// measured.
+type ingestionLatencyTracker struct {
+	mu         sync.Mutex
+	pending    *lru.Cache
+	histograms [numIngestionStages]*latencyHistogram
+}
+
+func newIngestionLatencyTracker(size int) (*ingestionLatencyTracker, error) {
+	pending, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	tracker := &ingestionLatencyTracker{pending: pending}
+	for i := range tracker.histograms {
+		tracker.histograms[i] = newLatencyHistogram()
+	}
+	return tracker, nil
+}
+
+// decoded starts tracking an order whose message was received at receivedAt
+// (which may be the zero time if unknown), handled at handledAt and decoded
+// at decodedAt.
+func (t *ingestionLatencyTracker) decoded(orderHash common.Hash, receivedAt, handledAt, decodedAt time.Time) {
+	if receivedAt.IsZero() {
+		receivedAt = handledAt
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending.Add(orderHash, &pendingIngestion{
+		receivedAt: receivedAt,
+		handledAt:  handledAt,
+		decodedAt:  decodedAt,
+	})
+}
+
+// validated marks a tracked order as validated and stored. The ADDED event
+// may already have been emitted at this point.
+func (t *ingestionLatencyTracker) validated(orderHash common.Hash, validatedAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	value, found := t.pending.Get(orderHash)
+	if !found {
+		return
+	}
+	pending := value.(*pendingIngestion)
+	pending.validatedAt = validatedAt
+	t.observeIfDone(orderHash, pending)
+}
+
+// forget stops tracking an order, e.g. because it was rejected or already
+// stored.
+func (t *ingestionLatencyTracker) forget(orderHash common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending.Remove(orderHash)
+}
+
+// emitted marks the tracked orders with ADDED events among orderEvents as
+// emitted.
+func (t *ingestionLatencyTracker) emitted(orderEvents []*zeroex.OrderEvent, emittedAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, orderEvent := range orderEvents {
+		if orderEvent.EndState != zeroex.ESOrderAdded {
+			continue
+		}
+		value, found := t.pending.Get(orderEvent.OrderHash)
+		if !found {
+			continue
+		}
+		pending := value.(*pendingIngestion)
+		pending.emittedAt = emittedAt
+		t.observeIfDone(orderEvent.OrderHash, pending)
+	}
+}
+
+// observeIfDone records the latencies of an order once it has been both
+// validated and emitted. t.mu must be held.
+func (t *ingestionLatencyTracker) observeIfDone(orderHash common.Hash, pending *pendingIngestion) {
+	if pending.validatedAt.IsZero() || pending.emittedAt.IsZero() {
+		return
+	}
+	t.pending.Remove(orderHash)
+	t.histograms[stageQueue].observe(pending.handledAt.Sub(pending.receivedAt))
+	t.histograms[stageDecoding].observe(pending.decodedAt.Sub(pending.handledAt))
+	t.histograms[stageValidation].observe(pending.validatedAt.Sub(pending.decodedAt))
+	t.histograms[stageEmission].observe(pending.emittedAt.Sub(pending.validatedAt))
+	t.histograms[stageTotal].observe(pending.emittedAt.Sub(pending.receivedAt))
+}
+
+// record marks the ADDED events received on orderEventsChan as emitted until
+// ctx is canceled.
+func (t *ingestionLatencyTracker) record(ctx context.Context, orderEventsChan <-chan []*zeroex.OrderEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case orderEvents := <-orderEventsChan:
+			t.emitted(orderEvents, time.Now())
+		}
+	}
+}
+
+// getStats returns the percentiles of the most recent latencies of each stage.
+func (t *ingestionLatencyTracker) getStats() types.IngestionLatencyStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return types.IngestionLatencyStats{
+		Queue:      t.histograms[stageQueue].stats(),
+		Decoding:   t.histograms[stageDecoding].stats(),
+		Validation: t.histograms[stageValidation].stats(),
+		Emission:   t.histograms[stageEmission].stats(),
+		Total:      t.histograms[stageTotal].stats(),
+	}
+}
+
+// writePrometheusMetrics writes the latency histograms of all stages to w in
+// the Prometheus text exposition format.
+func (t *ingestionLatencyTracker) writePrometheusMetrics(w io.Writer) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	const name = "mesh_order_ingestion_latency_seconds"
+	if _, err := fmt.Fprintf(w, "# HELP %s Latency from the receipt of an order via GossipSub to the emission of its ADDED event, by stage.\n# TYPE %s histogram\n", name, name); err != nil {
+		return err
+	}
+	for stage, histogram := range t.histograms {
+		stageName := ingestionStageNames[stage]
+		for i, upperBound := range ingestionLatencyBuckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket{stage=%q,le=%q} %d\n", name, stageName, fmt.Sprint(upperBound.Seconds()), histogram.buckets[i]); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket{stage=%q,le=\"+Inf\"} %d\n", name, stageName, histogram.count); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s_sum{stage=%q} %v\n", name, stageName, histogram.sum.Seconds()); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s_count{stage=%q} %d\n", name, stageName, histogram.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WritePrometheusMetrics writes the metrics of the app to w in the Prometheus
+// text exposition format.
+func (app *App) WritePrometheusMetrics(w io.Writer) error {
+	return app.ingestionLatency.writePrometheusMetrics(w)
+}
@@ -0,0 +1,70 @@
+// +build !js
+
+package core
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngestionLatencyTracker(t *testing.T) {
+	tracker, err := newIngestionLatencyTracker(10)
+	require.NoError(t, err)
+	start := time.Unix(1600000000, 0)
+
+	// The ADDED event is emitted after the order was validated.
+	first := common.HexToHash("0x01")
+	tracker.decoded(first, start, start.Add(10*time.Millisecond), start.Add(12*time.Millisecond))
+	tracker.validated(first, start.Add(100*time.Millisecond))
+	tracker.emitted([]*zeroex.OrderEvent{{OrderHash: first, EndState: zeroex.ESOrderAdded}}, start.Add(101*time.Millisecond))
+
+	// The ADDED event is emitted before ValidateAndStoreValidOrders returns.
+	second := common.HexToHash("0x02")
+	tracker.decoded(second, start, start.Add(20*time.Millisecond), start.Add(22*time.Millisecond))
+	tracker.emitted([]*zeroex.OrderEvent{{OrderHash: second, EndState: zeroex.ESOrderAdded}}, start.Add(200*time.Millisecond))
+	tracker.validated(second, start.Add(201*time.Millisecond))
+
+	// Rejected orders and other events are not measured.
+	rejected := common.HexToHash("0x03")
+	tracker.decoded(rejected, start, start, start)
+	tracker.forget(rejected)
+	tracker.emitted([]*zeroex.OrderEvent{{OrderHash: rejected, EndState: zeroex.ESOrderAdded}}, start)
+	tracker.emitted([]*zeroex.OrderEvent{{OrderHash: first, EndState: zeroex.ESOrderFilled}}, start)
+
+	stats := tracker.getStats()
+	assert.Equal(t, uint64(2), stats.Total.Count)
+	assert.Equal(t, 10*time.Millisecond, stats.Queue.P50)
+	assert.Equal(t, 20*time.Millisecond, stats.Queue.P99)
+	assert.Equal(t, 2*time.Millisecond, stats.Decoding.P95)
+	assert.Equal(t, 88*time.Millisecond, stats.Validation.P50)
+	// The negative emission latency of the second order is recorded as 0.
+	assert.Equal(t, time.Duration(0), stats.Emission.P50)
+	assert.Equal(t, time.Millisecond, stats.Emission.P99)
+	assert.Equal(t, 101*time.Millisecond, stats.Total.P50)
+	assert.Equal(t, 200*time.Millisecond, stats.Total.P99)
+
+	var metrics bytes.Buffer
+	require.NoError(t, tracker.writePrometheusMetrics(&metrics))
+	assert.Contains(t, metrics.String(), "# TYPE mesh_order_ingestion_latency_seconds histogram\n")
+	assert.Contains(t, metrics.String(), `mesh_order_ingestion_latency_seconds_bucket{stage="total",le="0.1"} 0`+"\n")
+	assert.Contains(t, metrics.String(), `mesh_order_ingestion_latency_seconds_bucket{stage="total",le="0.25"} 2`+"\n")
+	assert.Contains(t, metrics.String(), `mesh_order_ingestion_latency_seconds_bucket{stage="total",le="+Inf"} 2`+"\n")
+	assert.Contains(t, metrics.String(), `mesh_order_ingestion_latency_seconds_count{stage="emission"} 2`+"\n")
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(sorted, 50))
+	assert.Equal(t, 95*time.Millisecond, percentile(sorted, 95))
+	assert.Equal(t, 99*time.Millisecond, percentile(sorted, 99))
+	assert.Equal(t, time.Millisecond, percentile(sorted[:1], 99))
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// knownPeersSaveInterval is how often the peers Mesh is connected to are
+	// saved in the database.
+	knownPeersSaveInterval = 1 * time.Minute
+	// knownPeerExpiration is how long a peer is remembered after Mesh was last
+	// connected to it.
+	knownPeerExpiration = 7 * 24 * time.Hour
+	// maxInitialPeers is the maximum number of known peers dialed when Mesh
+	// starts.
+	maxInitialPeers = 50
+	// maxKnownPeerAddrs is the maximum number of addresses saved per peer.
+	maxKnownPeerAddrs = 8
+)
+
+// loadInitialPeers returns the best peers Mesh was connected to recently, so
+// that they can be dialed right away instead of waiting for them to be found
+// via the bootstrap nodes and the DHT.
+func (app *App) loadInitialPeers() ([]peer.AddrInfo, error) {
+	knownPeers, err := app.db.FindBestKnownPeers(time.Now().Add(-knownPeerExpiration), maxInitialPeers)
+	if err != nil {
+		return nil, err
+	}
+	peerInfos := []peer.AddrInfo{}
+	for _, knownPeer := range knownPeers {
+		peerID, err := peer.IDB58Decode(knownPeer.PeerID)
+		if err != nil {
+			continue
+		}
+		peerInfo := peer.AddrInfo{ID: peerID}
+		for _, addrString := range knownPeer.Addrs {
+			addr, err := ma.NewMultiaddr(addrString)
+			if err != nil {
+				continue
+			}
+			peerInfo.Addrs = append(peerInfo.Addrs, addr)
+		}
+		if len(peerInfo.Addrs) > 0 {
+			peerInfos = append(peerInfos, peerInfo)
+		}
+	}
+	return peerInfos, nil
+}
+
+// periodicallySaveKnownPeers saves the connected peers in the database every
+// knownPeersSaveInterval until the given context is canceled.
+func (app *App) periodicallySaveKnownPeers(ctx context.Context) {
+	ticker := time.NewTicker(knownPeersSaveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := app.saveKnownPeers(); err != nil {
+			log.WithError(err).Error("could not save known peers")
+		}
+	}
+}
+
+// saveKnownPeers saves the connected peers which share orders on our topic in
+// the database and removes peers which haven't been seen for
+// knownPeerExpiration.
+func (app *App) saveKnownPeers() error {
+	now := time.Now()
+	knownPeers := []*meshdb.KnownPeer{}
+	for _, details := range app.node.PeerDetails() {
+		if len(details.Topics) == 0 || len(details.Addrs) == 0 {
+			continue
+		}
+		addrs := details.Addrs
+		if len(addrs) > maxKnownPeerAddrs {
+			addrs = addrs[:maxKnownPeerAddrs]
+		}
+		knownPeers = append(knownPeers, &meshdb.KnownPeer{
+			PeerID:   details.ID.Pretty(),
+			Addrs:    addrs,
+			Score:    details.Score,
+			LastSeen: now,
+		})
+	}
+	if err := app.db.SaveKnownPeers(knownPeers); err != nil {
+		return err
+	}
+	_, err := app.db.DeleteKnownPeersLastSeenBefore(now.Add(-knownPeerExpiration))
+	return err
+}
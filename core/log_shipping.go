@@ -0,0 +1,85 @@
+// +build !js
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/0xProject/0x-mesh/loghooks"
+	log "github.com/sirupsen/logrus"
+)
+
+// Log shipping targets supported by the LOG_SHIPPING_TARGET environment
+// variable.
+const (
+	logShippingTargetElasticsearch = "elasticsearch"
+	logShippingTargetLoki          = "loki"
+)
+
+// logShippingStopTimeout is the maximum amount of time to wait for buffered
+// log entries to be shipped when Mesh shuts down.
+const logShippingStopTimeout = 10 * time.Second
+
+var (
+	setupLogShippingOnce = &sync.Once{}
+	logShippingHook      *loghooks.ShippingHook
+)
+
+// setupLogShipping adds a hook which ships logs to the external log store
+// configured via LOG_SHIPPING_TARGET, if any. Only the first call has any
+// effect. It must be called after setupLogger. Only entries which are also
+// written to stdout are shipped, i.e. the level of each subsystem and sampling
+// apply to shipped entries as well.
+func setupLogShipping(config Config) error {
+	var shipper loghooks.Shipper
+	switch config.LogShippingTarget {
+	case "":
+		return nil
+	case logShippingTargetElasticsearch:
+		shipper = loghooks.NewElasticsearchShipper(config.LogShippingURL, config.LogShippingIndex)
+	case logShippingTargetLoki:
+		shipper = loghooks.NewLokiShipper(config.LogShippingURL, config.LogShippingIndex)
+	default:
+		return fmt.Errorf("invalid LOG_SHIPPING_TARGET: %q (must be %q or %q)", config.LogShippingTarget, logShippingTargetElasticsearch, logShippingTargetLoki)
+	}
+	if config.LogShippingURL == "" {
+		return errors.New("LOG_SHIPPING_URL is required when LOG_SHIPPING_TARGET is set")
+	}
+
+	var err error
+	setupLogShippingOnce.Do(func() {
+		var hook *loghooks.ShippingHook
+		hook, err = loghooks.NewShippingHook(loghooks.ShippingHookConfig{
+			Shipper:    shipper,
+			BufferSize: config.LogShippingBufferSize,
+		})
+		if err != nil {
+			return
+		}
+		logShippingHook = hook
+		addLogHook(hook)
+		// Ship buffered entries before exiting because of a fatal error.
+		log.RegisterExitHandler(stopLogShipping)
+	})
+	return err
+}
+
+// stopLogShipping ships any buffered log entries and stops log shipping. It
+// waits for at most logShippingStopTimeout. It does nothing if log shipping is
+// not enabled.
+func stopLogShipping() {
+	if logShippingHook == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), logShippingStopTimeout)
+	defer cancel()
+	if err := logShippingHook.Stop(ctx); err != nil {
+		// The logger can't be used here since entries are no longer shipped.
+		fmt.Fprintf(os.Stderr, "could not ship buffered log entries: %s\n", err.Error())
+	}
+}
@@ -0,0 +1,18 @@
+// +build js,wasm
+
+package core
+
+import "errors"
+
+// setupLogShipping returns an error if LogShippingTarget is set, since log
+// shipping is not supported in the browser.
+func setupLogShipping(config Config) error {
+	if config.LogShippingTarget != "" {
+		return errors.New("LOG_SHIPPING_TARGET is not supported in the browser")
+	}
+	return nil
+}
+
+// stopLogShipping does nothing, since log shipping is not supported in the
+// browser.
+func stopLogShipping() {}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/0xProject/0x-mesh/loghooks"
+	log "github.com/sirupsen/logrus"
+)
+
+// Log formats supported by the LOG_FORMAT environment variable.
+const (
+	logFormatJSON    = "json"
+	logFormatConsole = "console"
+)
+
+var (
+	setupLoggerOnce    = &sync.Once{}
+	logFilterFormatter *loghooks.FilterFormatter
+)
+
+// setupLogger configures the global logger according to the given config.
+// Only the first call has any effect.
+//
+// TODO(albrow): Don't use global variables for log settings.
+func setupLogger(config Config) error {
+	subsystemLevels, err := loghooks.ParseSubsystemLevels(config.LogLevels)
+	if err != nil {
+		return fmt.Errorf("invalid LOG_LEVELS: %s", err.Error())
+	}
+	var formatter log.Formatter
+	switch config.LogFormat {
+	case logFormatJSON:
+		formatter = &log.JSONFormatter{}
+	case logFormatConsole:
+		formatter = loghooks.NewConsoleFormatter()
+	default:
+		return fmt.Errorf("invalid LOG_FORMAT: %q (must be %q or %q)", config.LogFormat, logFormatJSON, logFormatConsole)
+	}
+	if config.LogSamplingInitial < 0 || config.LogSamplingThereafter < 0 {
+		return fmt.Errorf("LOG_SAMPLING_INITIAL and LOG_SAMPLING_THEREAFTER cannot be negative")
+	}
+
+	setupLoggerOnce.Do(func() {
+		logFilterFormatter = loghooks.NewFilterFormatter(formatter, loghooks.FilterConfig{
+			DefaultLevel:       log.Level(config.Verbosity),
+			SubsystemLevels:    subsystemLevels,
+			SamplingInitial:    config.LogSamplingInitial,
+			SamplingThereafter: config.LogSamplingThereafter,
+		})
+		log.SetFormatter(logFilterFormatter)
+		log.SetLevel(logFilterFormatter.MaxLevel())
+		if config.LogFormat == logFormatJSON {
+			// Key suffixes make JSON logs easier to index but only add noise to
+			// console output.
+			addLogHook(loghooks.NewKeySuffixHook())
+		}
+		// The caller is used to determine the subsystem of each log entry.
+		// Looking up the caller is expensive, so it is only done if levels
+		// are configured for specific subsystems.
+		if len(subsystemLevels) > 0 {
+			log.SetReportCaller(true)
+			addLogHook(loghooks.NewSubsystemHook())
+		}
+	})
+	return nil
+}
+
+// addLogHook adds a hook to the global logger. Hooks are fired by the
+// formatter so that they are not fired for entries which are dropped because
+// of LOG_LEVELS or sampling. It must be called after setupLogger.
+func addLogHook(hook log.Hook) {
+	logFilterFormatter.AddHook(hook)
+}
@@ -0,0 +1,286 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/ethereum/ens"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// ensResolutionTimeout is the maximum amount of time to spend resolving the
+// ENS names on a maker list.
+const ensResolutionTimeout = 30 * time.Second
+
+// GetMakerLists returns the maker allowlist and denylist currently enforced by
+// the node.
+func (app *App) GetMakerLists() *types.MakerLists {
+	<-app.started
+
+	return &types.MakerLists{
+		Allowlist: app.orderWatcher.MakerAllowlist(),
+		Denylist:  app.orderWatcher.MakerDenylist(),
+	}
+}
+
+// SetMakerAllowlist replaces the maker allowlist. If the new allowlist is
+// non-empty, only orders from makers on it will be accepted. Any stored orders
+// from makers that are no longer allowed are removed immediately.
+func (app *App) SetMakerAllowlist(addresses []common.Address) (*types.SetMakerListResponse, error) {
+	return app.SetMakerAllowlistNames(addressesToEntries(addresses))
+}
+
+// SetMakerAllowlistNames is like SetMakerAllowlist but entries may be
+// addresses or ENS names. ENS names are resolved immediately and re-resolved
+// periodically (see ENSRefreshInterval).
+func (app *App) SetMakerAllowlistNames(addressesOrNames []string) (*types.SetMakerListResponse, error) {
+	<-app.started
+
+	entries, err := normalizeAddressList(addressesOrNames)
+	if err != nil {
+		return nil, ErrInvalidAddressList{reason: err.Error()}
+	}
+	app.makerListsMu.Lock()
+	defer app.makerListsMu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), ensResolutionTimeout)
+	defer cancel()
+	addresses, err := resolveAddressList(ctx, app.ensResolver, entries)
+	if err != nil {
+		return nil, err
+	}
+	numOrdersRemoved, err := app.orderWatcher.SetMakerAllowlist(addresses)
+	if err != nil {
+		return nil, err
+	}
+	app.makerAllowlistEntries = entries
+	return &types.SetMakerListResponse{NumOrdersRemoved: numOrdersRemoved}, nil
+}
+
+// SetMakerDenylist replaces the maker denylist. Orders from makers on the
+// denylist will be rejected and any stored orders from them are removed
+// immediately.
+func (app *App) SetMakerDenylist(addresses []common.Address) (*types.SetMakerListResponse, error) {
+	return app.SetMakerDenylistNames(addressesToEntries(addresses))
+}
+
+// SetMakerDenylistNames is like SetMakerDenylist but entries may be addresses
+// or ENS names. ENS names are resolved immediately and re-resolved
+// periodically (see ENSRefreshInterval).
+func (app *App) SetMakerDenylistNames(addressesOrNames []string) (*types.SetMakerListResponse, error) {
+	<-app.started
+
+	entries, err := normalizeAddressList(addressesOrNames)
+	if err != nil {
+		return nil, ErrInvalidAddressList{reason: err.Error()}
+	}
+	app.makerListsMu.Lock()
+	defer app.makerListsMu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), ensResolutionTimeout)
+	defer cancel()
+	addresses, err := resolveAddressList(ctx, app.ensResolver, entries)
+	if err != nil {
+		return nil, err
+	}
+	numOrdersRemoved, err := app.orderWatcher.SetMakerDenylist(addresses)
+	if err != nil {
+		return nil, err
+	}
+	app.makerDenylistEntries = entries
+	return &types.SetMakerListResponse{NumOrdersRemoved: numOrdersRemoved}, nil
+}
+
+// GetAssetDenylist returns the token addresses on the asset denylist currently
+// enforced by the node.
+func (app *App) GetAssetDenylist() []common.Address {
+	<-app.started
+
+	return app.orderWatcher.AssetDenylist()
+}
+
+// SetAssetDenylist replaces the asset denylist. Orders involving any of the
+// given token addresses will be rejected and any stored orders involving them
+// are removed immediately.
+func (app *App) SetAssetDenylist(addresses []common.Address) (*types.SetAssetDenylistResponse, error) {
+	<-app.started
+
+	numOrdersRemoved, err := app.orderWatcher.SetAssetDenylist(addresses)
+	if err != nil {
+		return nil, err
+	}
+	return &types.SetAssetDenylistResponse{NumOrdersRemoved: numOrdersRemoved}, nil
+}
+
+// loadAssetDenylist reads the asset denylist file at the given path. The file
+// contains one token address per line. Empty lines and lines starting with "#"
+// are ignored. If path is empty, an empty list is returned.
+func loadAssetDenylist(path string) ([]common.Address, error) {
+	addresses := []common.Address{}
+	if path == "" {
+		return addresses, nil
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read ASSET_DENYLIST_PATH: %s", err.Error())
+	}
+	for i, line := range strings.Split(string(contents), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !common.IsHexAddress(line) {
+			return nil, fmt.Errorf("invalid Ethereum address on line %d of ASSET_DENYLIST_PATH: %q", i+1, line)
+		}
+		addresses = append(addresses, common.HexToAddress(line))
+	}
+	return addresses, nil
+}
+
+// parseAddressList parses a comma-separated list of Ethereum addresses and
+// ENS names. An empty string results in an empty list.
+func parseAddressList(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return []string{}, nil
+	}
+	return normalizeAddressList(strings.Split(list, ","))
+}
+
+// normalizeAddressList checks that each entry is either an Ethereum address or
+// an ENS name and normalizes it. Addresses are checksummed and ENS names are
+// lower-cased.
+func normalizeAddressList(addressesOrNames []string) ([]string, error) {
+	entries := make([]string, len(addressesOrNames))
+	for i, entry := range addressesOrNames {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case common.IsHexAddress(entry):
+			entries[i] = common.HexToAddress(entry).Hex()
+		case ens.IsName(entry):
+			entries[i] = ens.Normalize(entry)
+		default:
+			return nil, fmt.Errorf("invalid Ethereum address or ENS name: %q", entry)
+		}
+	}
+	return entries, nil
+}
+
+// addressesToEntries converts the given addresses to normalized maker list
+// entries.
+func addressesToEntries(addresses []common.Address) []string {
+	entries := make([]string, len(addresses))
+	for i, address := range addresses {
+		entries[i] = address.Hex()
+	}
+	return entries
+}
+
+// hasENSNames returns true if any of the given entries is an ENS name.
+func hasENSNames(entries []string) bool {
+	for _, entry := range entries {
+		if !common.IsHexAddress(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveAddressList resolves the ENS names in the given list of normalized
+// entries and returns the resulting addresses. ErrRelayOnly is returned if the
+// list contains ENS names and there is no resolver. Any other error means that
+// a name could not be resolved, e.g. because the Ethereum RPC endpoint is
+// unavailable, and is not the fault of the caller.
+func resolveAddressList(ctx context.Context, resolver *ens.Resolver, entries []string) ([]common.Address, error) {
+	addresses := make([]common.Address, len(entries))
+	for i, entry := range entries {
+		if common.IsHexAddress(entry) {
+			addresses[i] = common.HexToAddress(entry)
+			continue
+		}
+		if resolver == nil {
+			return nil, ErrRelayOnly
+		}
+		address, err := resolver.Resolve(ctx, entry)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve ENS name %q: %s", entry, err.Error())
+		}
+		addresses[i] = address
+	}
+	return addresses, nil
+}
+
+// refreshENSNamesPeriodically re-resolves the ENS names on the maker allowlist
+// and denylist every ENSRefreshInterval until ctx is canceled, so that the
+// lists follow changes of the addresses the names resolve to.
+func (app *App) refreshENSNamesPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(app.config.ENSRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			app.refreshENSNames(ctx)
+		}
+	}
+}
+
+// refreshENSNames re-resolves the ENS names on the maker allowlist and
+// denylist. If a name cannot be resolved, the list it is on is left unchanged.
+func (app *App) refreshENSNames(ctx context.Context) {
+	app.makerListsMu.Lock()
+	defer app.makerListsMu.Unlock()
+	ctx, cancel := context.WithTimeout(ctx, ensResolutionTimeout)
+	defer cancel()
+
+	lists := []struct {
+		name    string
+		entries []string
+		current []common.Address
+		set     func([]common.Address) (int, error)
+	}{
+		{"allowlist", app.makerAllowlistEntries, app.orderWatcher.MakerAllowlist(), app.orderWatcher.SetMakerAllowlist},
+		{"denylist", app.makerDenylistEntries, app.orderWatcher.MakerDenylist(), app.orderWatcher.SetMakerDenylist},
+	}
+	for _, list := range lists {
+		if !hasENSNames(list.entries) {
+			continue
+		}
+		addresses, err := resolveAddressList(ctx, app.ensResolver, list.entries)
+		if err != nil {
+			log.WithError(err).WithField("list", list.name).Warn("could not re-resolve ENS names on maker list")
+			continue
+		}
+		if sameAddresses(addresses, list.current) {
+			continue
+		}
+		numOrdersRemoved, err := list.set(addresses)
+		if err != nil {
+			log.WithError(err).WithField("list", list.name).Error("could not update maker list after re-resolving ENS names")
+			continue
+		}
+		log.WithFields(log.Fields{
+			"list":             list.name,
+			"numOrdersRemoved": numOrdersRemoved,
+		}).Info("updated maker list after ENS names changed")
+	}
+}
+
+// sameAddresses returns true if a and b contain the same set of addresses.
+func sameAddresses(a, b []common.Address) bool {
+	setA := map[common.Address]struct{}{}
+	for _, address := range a {
+		setA[address] = struct{}{}
+	}
+	setB := map[common.Address]struct{}{}
+	for _, address := range b {
+		if _, found := setA[address]; !found {
+			return false
+		}
+		setB[address] = struct{}{}
+	}
+	return len(setA) == len(setB)
+}
@@ -0,0 +1,78 @@
+// +build !js
+
+package core
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddressList(t *testing.T) {
+	addresses, err := parseAddressList("")
+	require.NoError(t, err)
+	assert.Len(t, addresses, 0)
+
+	addresses, err = parseAddressList("0x6ecbe1db9ef729cbe972c83fb886247691fb6beb, 0xE36Ea790bc9d7AB70C55260C66D52b1eca985f84, Maker.eth")
+	require.NoError(t, err)
+	assert.Equal(t, []string{constants.GanacheAccount1.Hex(), constants.GanacheAccount2.Hex(), "maker.eth"}, addresses)
+
+	_, err = parseAddressList("0x6ecbe1db9ef729cbe972c83fb886247691fb6beb,foo")
+	assert.Error(t, err)
+}
+
+func TestResolveAddressListWithoutResolver(t *testing.T) {
+	addresses, err := resolveAddressList(context.Background(), nil, []string{constants.GanacheAccount1.Hex()})
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{constants.GanacheAccount1}, addresses)
+
+	_, err = resolveAddressList(context.Background(), nil, []string{constants.GanacheAccount1.Hex(), "maker.eth"})
+	assert.Equal(t, ErrRelayOnly, err)
+}
+
+func TestSameAddresses(t *testing.T) {
+	assert.True(t, sameAddresses(
+		[]common.Address{constants.GanacheAccount1, constants.GanacheAccount2},
+		[]common.Address{constants.GanacheAccount2, constants.GanacheAccount1, constants.GanacheAccount2},
+	))
+	assert.False(t, sameAddresses(
+		[]common.Address{constants.GanacheAccount1, constants.GanacheAccount2},
+		[]common.Address{constants.GanacheAccount1, constants.GanacheAccount1},
+	))
+	assert.False(t, sameAddresses(
+		[]common.Address{constants.GanacheAccount1},
+		[]common.Address{constants.GanacheAccount1, constants.GanacheAccount2},
+	))
+}
+
+func TestLoadAssetDenylist(t *testing.T) {
+	addresses, err := loadAssetDenylist("")
+	require.NoError(t, err)
+	assert.Len(t, addresses, 0)
+
+	dir := filepath.Join("/tmp/asset_denylist_testing", uuid.New().String())
+	require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "denylist.txt")
+	contents := "# scam tokens\n0x6ecbe1db9ef729cbe972c83fb886247691fb6beb\n\n  0xe36ea790bc9d7ab70c55260c66d52b1eca985f84  \n"
+	require.NoError(t, ioutil.WriteFile(path, []byte(contents), os.ModePerm))
+	addresses, err = loadAssetDenylist(path)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{constants.GanacheAccount1, constants.GanacheAccount2}, addresses)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("not an address\n"), os.ModePerm))
+	_, err = loadAssetDenylist(path)
+	assert.Error(t, err)
+
+	_, err = loadAssetDenylist(filepath.Join(dir, "missing.txt"))
+	assert.Error(t, err)
+}
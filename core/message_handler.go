@@ -2,16 +2,23 @@ package core
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/0xProject/0x-mesh/constants"
 	"github.com/0xProject/0x-mesh/encoding"
 	"github.com/0xProject/0x-mesh/p2p"
 	"github.com/0xProject/0x-mesh/zeroex"
 	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/0xProject/0x-mesh/zeroex/orderwatch"
 	"github.com/ethereum/go-ethereum/common"
 	log "github.com/sirupsen/logrus"
 )
 
+// messageDecodingTimeout is the maximum amount of time to wait for a message
+// received from a peer to be decoded and for its signature to be verified.
+const messageDecodingTimeout = 5 * time.Second
+
 // Ensure that App implements p2p.MessageHandler.
 var _ p2p.MessageHandler = &App{}
 
@@ -27,53 +34,62 @@ func (app *App) HandleMessages(ctx context.Context, messages []*p2p.Message) err
 	orders := []*zeroex.SignedOrder{}
 	orderHashToMessage := map[common.Hash]*p2p.Message{}
 
-	for _, msg := range messages {
-		if err := validateMessageSize(msg); err != nil {
-			log.WithFields(map[string]interface{}{
-				"error":                 err,
-				"from":                  msg.From,
-				"maxMessageSizeInBytes": constants.MaxMessageSizeInBytes,
-				"actualSizeInBytes":     len(msg.Data),
-			}).Trace("received message that exceeds maximum size")
-			app.handlePeerScoreEvent(msg.From, psInvalidMessage)
+	handledAt := time.Now()
+	decodedMessages := app.decodeMessages(ctx, messages)
+	decodedAt := time.Now()
+	for _, decoded := range decodedMessages {
+		if decoded == nil {
+			// The message could not be decoded in time.
 			continue
 		}
-
-		order, err := encoding.RawMessageToOrder(msg.Data)
-		if err != nil {
-			log.WithFields(map[string]interface{}{
-				"error": err,
-				"from":  msg.From,
-			}).Trace("could not decode received message")
-			app.handlePeerScoreEvent(msg.From, psInvalidMessage)
-			continue
+		if decoded.err != nil {
+			return decoded.err
 		}
-		orderHash, err := order.ComputeOrderHash()
-		if err != nil {
-			return err
+		if decoded.isInvalid {
+			if decoded.order != nil {
+				// The order was decoded but its signature is invalid.
+				app.rejectionLog.add(orderSourceGossipSub, decoded.msg.From, decoded.orderHash, decoded.order, ordervalidator.ROInvalidSignature)
+			}
+			app.handlePeerScoreEvent(decoded.msg.From, psInvalidMessage)
+			continue
 		}
 		// Validate doesn't guarantee there are no duplicates so we keep track of
 		// which orders we've already seen.
-		if _, alreadySeen := orderHashToMessage[orderHash]; alreadySeen {
+		if _, alreadySeen := orderHashToMessage[decoded.orderHash]; alreadySeen {
 			continue
 		}
-		orders = append(orders, order)
-		orderHashToMessage[orderHash] = msg
-		app.handlePeerScoreEvent(msg.From, psValidMessage)
+		orders = append(orders, decoded.order)
+		orderHashToMessage[decoded.orderHash] = decoded.msg
+		app.ingestionLatency.decoded(decoded.orderHash, decoded.msg.ReceivedAt, handledAt, decodedAt)
+		app.handlePeerScoreEvent(decoded.msg.From, psValidMessage)
 	}
 
 	// Next, we validate the orders.
-	validationResults, err := app.orderWatcher.ValidateAndStoreValidOrders(ctx, orders, false, app.chainID)
+	storeOpts := orderwatch.StoreOrdersOpts{
+		Source:        orderSourceGossipSub,
+		SourcePeerIDs: make(map[common.Hash]string, len(orderHashToMessage)),
+	}
+	for orderHash, msg := range orderHashToMessage {
+		storeOpts.SourcePeerIDs[orderHash] = msg.From.Pretty()
+	}
+	validationResults, err := app.orderWatcher.ValidateAndStoreValidOrdersWithOpts(ctx, orders, storeOpts, app.chainID)
 	if err != nil {
+		for orderHash := range orderHashToMessage {
+			app.ingestionLatency.forget(orderHash)
+		}
 		return err
 	}
+	validatedAt := time.Now()
+	app.recordDryRun(storeOpts, validationResults)
 
 	// Store any valid orders and update the peer scores.
 	for _, acceptedOrderInfo := range validationResults.Accepted {
 		// If the order isn't new, we don't log it's receipt or adjust peer scores
 		if !acceptedOrderInfo.IsNew {
+			app.ingestionLatency.forget(acceptedOrderInfo.OrderHash)
 			continue
 		}
+		app.ingestionLatency.validated(acceptedOrderInfo.OrderHash, validatedAt)
 		msg := orderHashToMessage[acceptedOrderInfo.OrderHash]
 		// If we've reached this point, the message is valid, we were able to
 		// decode it into an order and check that this order is valid. Update
@@ -96,12 +112,14 @@ func (app *App) HandleMessages(ctx context.Context, messages []*p2p.Message) err
 	// scores.
 	for _, rejectedOrderInfo := range validationResults.Rejected {
 		msg := orderHashToMessage[rejectedOrderInfo.OrderHash]
+		app.ingestionLatency.forget(rejectedOrderInfo.OrderHash)
+		app.rejectionLog.add(orderSourceGossipSub, msg.From, rejectedOrderInfo.OrderHash, rejectedOrderInfo.SignedOrder, rejectedOrderInfo.Status)
 		log.WithFields(map[string]interface{}{
 			"rejectedOrderInfo": rejectedOrderInfo,
 			"from":              msg.From.String(),
 		}).Trace("not storing rejected order received from peer")
 		switch rejectedOrderInfo.Status {
-		case ordervalidator.ROInternalError, ordervalidator.ROEthRPCRequestFailed, ordervalidator.ROCoordinatorRequestFailed, ordervalidator.RODatabaseFullOfOrders:
+		case ordervalidator.ROInternalError, ordervalidator.ROEthRPCRequestFailed, ordervalidator.ROCoordinatorRequestFailed, ordervalidator.RODatabaseFullOfOrders, ordervalidator.ROMakerNotAllowed, ordervalidator.ROAssetNotAllowed, ordervalidator.ROSenderAddressNotAllowed, ordervalidator.ROTakerAddressNotAllowed, ordervalidator.ROOrderSuperseded, ordervalidator.ROOrderReplaced, ordervalidator.ROTransferSimulationFailed, ordervalidator.ROOrderNotionalTooLow, ordervalidator.ROSignatureRequiresOnChainValidation:
 			// Don't incur a negative score for these status types (it might not be
 			// their fault).
 		default:
@@ -112,6 +130,96 @@ func (app *App) HandleMessages(ctx context.Context, messages []*p2p.Message) err
 	return nil
 }
 
+// decodedMessage is the result of decoding a message received from a peer.
+type decodedMessage struct {
+	msg       *p2p.Message
+	order     *zeroex.SignedOrder
+	orderHash common.Hash
+	// isInvalid is true if the message is invalid and the sender should be
+	// penalized. order and orderHash are set if the message could be decoded
+	// but the order's signature is invalid.
+	isInvalid bool
+	// err is set if an unexpected error occurred.
+	err error
+}
+
+// decodeMessages decodes the given messages into orders, computes their hashes
+// and verifies their signatures (if possible). This work is CPU-bound, so it is
+// done concurrently on the worker pool. Each message has to be decoded within
+// messageDecodingTimeout. The result for each message is at the same index as
+// the message and is nil if the message could not be decoded in time.
+func (app *App) decodeMessages(ctx context.Context, messages []*p2p.Message) []*decodedMessage {
+	results := make([]*decodedMessage, len(messages))
+	wg := &sync.WaitGroup{}
+	for i, msg := range messages {
+		wg.Add(1)
+		go func(i int, msg *p2p.Message) {
+			defer wg.Done()
+			msgCtx, cancel := context.WithTimeout(ctx, messageDecodingTimeout)
+			defer cancel()
+			// Note: decoded must not be used if Do returns an error, since the
+			// worker might still be writing to it.
+			var decoded *decodedMessage
+			if err := app.workerPool.Do(msgCtx, func() {
+				decoded = app.decodeMessage(msg)
+			}); err != nil {
+				log.WithFields(map[string]interface{}{
+					"error": err,
+					"from":  msg.From,
+				}).Trace("could not decode received message in time")
+				return
+			}
+			results[i] = decoded
+		}(i, msg)
+	}
+	wg.Wait()
+	return results
+}
+
+func (app *App) decodeMessage(msg *p2p.Message) *decodedMessage {
+	if err := validateMessageSize(msg); err != nil {
+		log.WithFields(map[string]interface{}{
+			"error":                 err,
+			"from":                  msg.From,
+			"maxMessageSizeInBytes": constants.MaxMessageSizeInBytes,
+			"actualSizeInBytes":     len(msg.Data),
+		}).Trace("received message that exceeds maximum size")
+		return &decodedMessage{msg: msg, isInvalid: true}
+	}
+
+	order, err := encoding.RawMessageToOrder(msg.Data)
+	if err != nil {
+		log.WithFields(map[string]interface{}{
+			"error": err,
+			"from":  msg.From,
+		}).Trace("could not decode received message")
+		return &decodedMessage{msg: msg, isInvalid: true}
+	}
+	orderHash, err := order.ComputeOrderHash()
+	if err != nil {
+		return &decodedMessage{msg: msg, err: err}
+	}
+
+	if !app.signatureCache.isValidSignature(orderHash, order) {
+		log.WithFields(map[string]interface{}{
+			"orderHash": orderHash.Hex(),
+			"from":      msg.From,
+		}).Trace("received order with invalid signature")
+		return &decodedMessage{
+			msg:       msg,
+			order:     order,
+			orderHash: orderHash,
+			isInvalid: true,
+		}
+	}
+
+	return &decodedMessage{
+		msg:       msg,
+		order:     order,
+		orderHash: orderHash,
+	}
+}
+
 func validateMessageSize(message *p2p.Message) error {
 	if len(message.Data) > constants.MaxMessageSizeInBytes {
 		return constants.ErrMaxMessageSize
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/zeroex/orderwatch"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// metricsSampleInterval is how often key metrics are sampled and stored
+	// in the database.
+	metricsSampleInterval = 1 * time.Minute
+	// metricsHistoryRetention is how long metrics samples are kept. Samples
+	// are stored in a ring buffer, so older samples are overwritten.
+	metricsHistoryRetention = 24 * time.Hour
+)
+
+// periodicallySampleMetrics stores a sample of key metrics in the database
+// every metricsSampleInterval until the given context is canceled.
+func (app *App) periodicallySampleMetrics(ctx context.Context) {
+	<-app.started
+
+	ticker := time.NewTicker(metricsSampleInterval)
+	defer ticker.Stop()
+	lastValidationStats := app.orderWatcher.ValidationStats()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		validationStats := app.orderWatcher.ValidationStats()
+		if err := app.sampleMetrics(time.Now(), lastValidationStats, validationStats); err != nil {
+			log.WithError(err).Error("could not sample metrics")
+		}
+		lastValidationStats = validationStats
+	}
+}
+
+// sampleMetrics stores a sample of key metrics in the database. The
+// validation latency is averaged over the validations between the given
+// validation stats.
+func (app *App) sampleMetrics(now time.Time, lastValidationStats, validationStats orderwatch.ValidationStats) error {
+	notRemovedFilter := app.db.Orders.IsRemovedIndex.ValueFilter([]byte{0})
+	numOrders, err := app.db.Orders.NewQuery(notRemovedFilter).Count()
+	if err != nil {
+		return err
+	}
+	metadata, err := app.db.GetMetadata()
+	if err != nil {
+		return err
+	}
+	sample := &meshdb.MetricsSample{
+		Time:                              now,
+		NumOrders:                         numOrders,
+		NumPeers:                          app.node.GetNumPeers(),
+		NumValidations:                    validationStats.NumValidations - lastValidationStats.NumValidations,
+		EthRPCRequestsSentInCurrentUTCDay: metadata.EthRPCRequestsSentInCurrentUTCDay,
+		EthRPCMaxRequestsPer24HrUTC:       app.ethRPCMaxUnitsPer24HrUTC,
+	}
+	if sample.NumValidations > 0 {
+		sample.ValidationLatency = (validationStats.TotalDuration - lastValidationStats.TotalDuration) / time.Duration(sample.NumValidations)
+	}
+	numSlots := int(metricsHistoryRetention / metricsSampleInterval)
+	return app.db.SaveMetricsSample(sample, metricsSampleInterval, numSlots)
+}
+
+// GetMetricsHistory returns the samples of key metrics taken at or after the
+// given time in chronological order. Samples are taken every minute and kept
+// for 24 hours. If since is the zero time, all samples are returned.
+func (app *App) GetMetricsHistory(since time.Time) ([]*types.MetricsSample, error) {
+	<-app.started
+
+	// Samples which were not overwritten because Mesh was not running might
+	// be older than the retention period.
+	if oldest := time.Now().Add(-metricsHistoryRetention); since.Before(oldest) {
+		since = oldest
+	}
+	dbSamples, err := app.db.FindMetricsSamplesSince(since)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]*types.MetricsSample, len(dbSamples))
+	for i, dbSample := range dbSamples {
+		samples[i] = &types.MetricsSample{
+			Time:                              dbSample.Time,
+			NumOrders:                         dbSample.NumOrders,
+			NumPeers:                          dbSample.NumPeers,
+			ValidationLatency:                 dbSample.ValidationLatency,
+			NumValidations:                    dbSample.NumValidations,
+			EthRPCRequestsSentInCurrentUTCDay: dbSample.EthRPCRequestsSentInCurrentUTCDay,
+			EthRPCMaxRequestsPer24HrUTC:       dbSample.EthRPCMaxRequestsPer24HrUTC,
+		}
+	}
+	return samples, nil
+}
@@ -0,0 +1,24 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xProject/0x-mesh/common/types"
+)
+
+// notionalValuesTimeout is the maximum amount of time spent computing notional
+// values for a page of orders returned by GetOrders.
+const notionalValuesTimeout = 10 * time.Second
+
+// addNotionalValues sets NotionalUSD for each of the given orders whose
+// notional value is known.
+func (app *App) addNotionalValues(orderInfos []*types.OrderInfo) {
+	ctx, cancel := context.WithTimeout(context.Background(), notionalValuesTimeout)
+	defer cancel()
+	for _, orderInfo := range orderInfos {
+		if notionalUSD, ok := app.orderWatcher.OrderNotionalUSD(ctx, orderInfo.SignedOrder, orderInfo.FillableTakerAssetAmount); ok {
+			orderInfo.NotionalUSD = &notionalUSD
+		}
+	}
+}
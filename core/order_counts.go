@@ -0,0 +1,95 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/0xProject/0x-mesh/common/types"
+)
+
+// The ways in which GetOrderCounts can group orders.
+const (
+	OrderCountsGroupByPair   = "pair"
+	OrderCountsGroupByMaker  = "maker"
+	OrderCountsGroupByStatus = "status"
+)
+
+// ErrInvalidOrderCountsGroupBy is the error returned when GetOrderCounts is
+// called with an unknown groupBy.
+type ErrInvalidOrderCountsGroupBy struct {
+	groupBy string
+}
+
+func (e ErrInvalidOrderCountsGroupBy) Error() string {
+	return fmt.Sprintf("invalid groupBy: %q (must be one of %q, %q or %q)", e.groupBy, OrderCountsGroupByPair, OrderCountsGroupByMaker, OrderCountsGroupByStatus)
+}
+
+// GetOrderCounts returns the number of stored orders for each asset pair,
+// maker or status, depending on groupBy. Orders which were flagged for
+// removal are only included when grouping by status. The counts are computed
+// from database indexes without decoding any orders, so this is much cheaper
+// than paging through all orders.
+func (app *App) GetOrderCounts(groupBy string) (*types.GetOrderCountsResponse, error) {
+	<-app.started
+
+	counts := []*types.OrderCount{}
+	switch groupBy {
+	case OrderCountsGroupByPair:
+		pairCounts, err := app.db.CountOrdersByAssetPair()
+		if err != nil {
+			return nil, err
+		}
+		for pair, count := range pairCounts {
+			counts = append(counts, &types.OrderCount{
+				MakerAssetData: pair.MakerAssetData,
+				TakerAssetData: pair.TakerAssetData,
+				Count:          count,
+			})
+		}
+	case OrderCountsGroupByMaker:
+		makerCounts, err := app.db.CountOrdersByMakerAddress()
+		if err != nil {
+			return nil, err
+		}
+		for makerAddress, count := range makerCounts {
+			counts = append(counts, &types.OrderCount{
+				MakerAddress: makerAddress,
+				Count:        count,
+			})
+		}
+	case OrderCountsGroupByStatus:
+		statusCounts, err := app.db.CountOrdersByStatus()
+		if err != nil {
+			return nil, err
+		}
+		for status, count := range statusCounts {
+			counts = append(counts, &types.OrderCount{
+				Status: status,
+				Count:  count,
+			})
+		}
+	default:
+		return nil, ErrInvalidOrderCountsGroupBy{groupBy: groupBy}
+	}
+	sortOrderCounts(counts)
+	return &types.GetOrderCountsResponse{
+		GroupBy: groupBy,
+		Counts:  counts,
+	}, nil
+}
+
+// sortOrderCounts sorts counts by count in descending order. Groups with the
+// same count are sorted by their identifying fields so that the order is
+// deterministic.
+func sortOrderCounts(counts []*types.OrderCount) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return orderCountKey(counts[i]) < orderCountKey(counts[j])
+	})
+}
+
+func orderCountKey(count *types.OrderCount) string {
+	return count.MakerAssetData + "|" + count.TakerAssetData + "|" + count.MakerAddress + "|" + count.Status
+}
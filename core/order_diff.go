@@ -0,0 +1,140 @@
+package core
+
+import (
+	"time"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/db"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// orderDiffSnapshotExpiration is how long snapshots created by GetOrderDiff
+// are kept. It is longer than the expiration of GetOrders snapshots since
+// clients which mirror the orderbook typically poll less frequently than they
+// page through orders.
+const orderDiffSnapshotExpiration = 10 * time.Minute
+
+// GetOrderDiff returns the orders which were added, changed or removed since
+// the snapshot with the given ID was created, along with the ID of a new
+// snapshot of the current orderbook which can be passed to the next call. If
+// sinceSnapshotID is empty, all fillable orders are returned as added. The ID
+// of a snapshot returned by GetOrders can also be used. Snapshots created by
+// GetOrderDiff expire after 10 minutes.
+func (app *App) GetOrderDiff(sinceSnapshotID string) (*types.GetOrderDiffResponse, error) {
+	<-app.started
+
+	var previousOrders []*meshdb.Order
+	if sinceSnapshotID != "" {
+		app.muIdToSnapshotInfo.Lock()
+		info, ok := app.idToSnapshotInfo[sinceSnapshotID]
+		app.muIdToSnapshotInfo.Unlock()
+		if !ok {
+			return nil, ErrSnapshotNotFound{id: sinceSnapshotID}
+		}
+		var err error
+		previousOrders, err = app.findFillableOrders(info.Snapshot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	snapshot, err := app.db.Orders.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	createdAt := time.Now().UTC()
+	currentOrders, err := app.findFillableOrders(snapshot)
+	if err != nil {
+		snapshot.Release()
+		return nil, err
+	}
+	snapshotID := uuid.New().String()
+	expirationTimestamp := createdAt.Add(orderDiffSnapshotExpiration)
+	app.snapshotExpirationWatcher.Add(expirationTimestamp, snapshotID)
+	app.muIdToSnapshotInfo.Lock()
+	app.idToSnapshotInfo[snapshotID] = snapshotInfo{
+		Snapshot:            snapshot,
+		CreatedAt:           createdAt,
+		ExpirationTimestamp: expirationTimestamp,
+	}
+	app.muIdToSnapshotInfo.Unlock()
+
+	added, changed, removed := diffOrders(previousOrders, currentOrders)
+	response := &types.GetOrderDiffResponse{
+		SnapshotID:        snapshotID,
+		SnapshotTimestamp: createdAt,
+		Added:             make([]*types.OrderInfo, len(added)),
+		Changed:           make([]*types.OrderInfo, len(changed)),
+		Removed:           removed,
+	}
+	for i, order := range added {
+		response.Added[i] = orderToOrderInfo(order)
+	}
+	for i, order := range changed {
+		response.Changed[i] = orderToOrderInfo(order)
+	}
+	app.addNotionalValues(response.Added)
+	app.addNotionalValues(response.Changed)
+	return response, nil
+}
+
+// findFillableOrders returns all orders in the given snapshot which have not
+// been flagged for removal.
+func (app *App) findFillableOrders(snapshot *db.Snapshot) ([]*meshdb.Order, error) {
+	var orders []*meshdb.Order
+	notRemovedFilter := app.db.Orders.IsRemovedIndex.ValueFilter([]byte{0})
+	if err := snapshot.NewQuery(notRemovedFilter).Run(&orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// diffOrders compares two sets of orders. It returns the orders in
+// currentOrders which are not in previousOrders, the orders whose fillable
+// taker asset amount is different in currentOrders, and the hashes of the
+// orders in previousOrders which are not in currentOrders.
+func diffOrders(previousOrders, currentOrders []*meshdb.Order) (added, changed []*meshdb.Order, removed []common.Hash) {
+	added = []*meshdb.Order{}
+	changed = []*meshdb.Order{}
+	removed = []common.Hash{}
+	previousOrdersByHash := make(map[common.Hash]*meshdb.Order, len(previousOrders))
+	for _, order := range previousOrders {
+		previousOrdersByHash[order.Hash] = order
+	}
+	for _, order := range currentOrders {
+		previousOrder, found := previousOrdersByHash[order.Hash]
+		if !found {
+			added = append(added, order)
+			continue
+		}
+		delete(previousOrdersByHash, order.Hash)
+		if previousOrder.FillableTakerAssetAmount.Cmp(order.FillableTakerAssetAmount) != 0 {
+			changed = append(changed, order)
+		}
+	}
+	// Iterate over previousOrders instead of the map so that the order of the
+	// removed hashes is deterministic.
+	for _, order := range previousOrders {
+		if _, found := previousOrdersByHash[order.Hash]; found {
+			removed = append(removed, order.Hash)
+		}
+	}
+	return added, changed, removed
+}
+
+func orderToOrderInfo(order *meshdb.Order) *types.OrderInfo {
+	return &types.OrderInfo{
+		OrderHash:                order.Hash,
+		SignedOrder:              order.SignedOrder,
+		FillableTakerAssetAmount: order.FillableTakerAssetAmount,
+		TransferSimulationFailed: order.TransferSimulationFailed,
+		LastValidatedBlockNumber: order.LastValidatedBlockNumber,
+		LastValidatedBlockHash:   order.LastValidatedBlockHash,
+		LastValidationResult:     order.LastValidationResult,
+		IsTakerRestricted:        order.SignedOrder.IsTakerRestricted(),
+		IsSenderRestricted:       order.SignedOrder.IsSenderRestricted(),
+		Metadata:                 order.Metadata,
+	}
+}
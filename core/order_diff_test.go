@@ -0,0 +1,40 @@
+// +build !js
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffOrders(t *testing.T) {
+	newOrder := func(hash string, fillableTakerAssetAmount int64) *meshdb.Order {
+		return &meshdb.Order{
+			Hash:                     common.HexToHash(hash),
+			FillableTakerAssetAmount: big.NewInt(fillableTakerAssetAmount),
+		}
+	}
+	unchanged := newOrder("0x1", 100)
+	partiallyFilled := newOrder("0x2", 100)
+	partiallyFilledNow := newOrder("0x2", 50)
+	filled := newOrder("0x3", 100)
+	newlyAdded := newOrder("0x4", 100)
+
+	added, changed, removed := diffOrders(
+		[]*meshdb.Order{unchanged, partiallyFilled, filled},
+		[]*meshdb.Order{unchanged, partiallyFilledNow, newlyAdded},
+	)
+	assert.Equal(t, []*meshdb.Order{newlyAdded}, added)
+	assert.Equal(t, []*meshdb.Order{partiallyFilledNow}, changed)
+	assert.Equal(t, []common.Hash{filled.Hash}, removed)
+
+	// Without a previous snapshot, all orders are added.
+	added, changed, removed = diffOrders(nil, []*meshdb.Order{unchanged})
+	assert.Equal(t, []*meshdb.Order{unchanged}, added)
+	assert.Empty(t, changed)
+	assert.Empty(t, removed)
+}
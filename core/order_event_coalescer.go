@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// coalescerOrderEventsBufferSize is the buffer size of the channel on which
+// the order event coalescer receives order events from the order watcher.
+const coalescerOrderEventsBufferSize = 100
+
+// pendingOrderEvent is the latest order event for an order which has not been
+// sent to subscribers yet.
+type pendingOrderEvent struct {
+	// seq determines the order in which pending order events are sent.
+	seq        uint64
+	orderEvent *zeroex.OrderEvent
+}
+
+// orderEventCoalescer holds back the order events emitted by the order watcher
+// for a fixed window and only sends the latest order event for each order to
+// its subscribers. It reduces the number of order events for orders whose
+// state changes back and forth, e.g. between UNFUNDED and
+// FILLABILITY_INCREASED, many times within a short period.
+type orderEventCoalescer struct {
+	window  time.Duration
+	seq     uint64
+	pending map[common.Hash]*pendingOrderEvent
+	feed    event.Feed
+	scope   event.SubscriptionScope
+}
+
+func newOrderEventCoalescer(window time.Duration) *orderEventCoalescer {
+	return &orderEventCoalescer{
+		window:  window,
+		pending: map[common.Hash]*pendingOrderEvent{},
+	}
+}
+
+// Subscribe subscribes sink to the coalesced order events.
+func (c *orderEventCoalescer) Subscribe(sink chan<- []*zeroex.OrderEvent) event.Subscription {
+	return c.scope.Track(c.feed.Subscribe(sink))
+}
+
+// run coalesces the order events received on orderEventsChan until ctx is
+// canceled. Order events are sent to subscribers at most one window after the
+// first order event which has not been sent yet was received.
+func (c *orderEventCoalescer) run(ctx context.Context, orderEventsChan <-chan []*zeroex.OrderEvent) {
+	defer c.scope.Close()
+	var flushTimer <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case orderEvents := <-orderEventsChan:
+			if flushTimer == nil {
+				flushTimer = time.After(c.window)
+			}
+			c.add(orderEvents)
+		case <-flushTimer:
+			flushTimer = nil
+			if orderEvents := c.flush(); len(orderEvents) > 0 {
+				c.feed.Send(orderEvents)
+			}
+		}
+	}
+}
+
+// add replaces the pending order event of each order with the given one. The
+// contract events and superseded order events of the replaced order event are
+// carried over, so that subscribers still receive all of them.
+func (c *orderEventCoalescer) add(orderEvents []*zeroex.OrderEvent) {
+	for _, orderEvent := range orderEvents {
+		c.seq++
+		pending, found := c.pending[orderEvent.OrderHash]
+		if !found {
+			c.pending[orderEvent.OrderHash] = &pendingOrderEvent{
+				seq:        c.seq,
+				orderEvent: orderEvent,
+			}
+			continue
+		}
+		// The order event is shared with other subscribers of the order
+		// watcher, so it must not be modified.
+		merged := *orderEvent
+		merged.ContractEvents = append(append([]*zeroex.ContractEvent{}, pending.orderEvent.ContractEvents...), orderEvent.ContractEvents...)
+		merged.Supersedes = append(append([]*zeroex.SupersededOrderEvent{}, pending.orderEvent.Supersedes...), orderEvent.Supersedes...)
+		pending.seq = c.seq
+		pending.orderEvent = &merged
+	}
+}
+
+// flush returns the pending order events in the order in which they were
+// last updated and clears them.
+func (c *orderEventCoalescer) flush() []*zeroex.OrderEvent {
+	pending := make([]*pendingOrderEvent, 0, len(c.pending))
+	for _, pendingEvent := range c.pending {
+		pending = append(pending, pendingEvent)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].seq < pending[j].seq
+	})
+	orderEvents := make([]*zeroex.OrderEvent, len(pending))
+	for i, pendingEvent := range pending {
+		orderEvents[i] = pendingEvent.orderEvent
+	}
+	c.pending = map[common.Hash]*pendingOrderEvent{}
+	return orderEvents
+}
@@ -0,0 +1,47 @@
+// +build !js
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderEventCoalescer(t *testing.T) {
+	coalescer := newOrderEventCoalescer(time.Second)
+	newOrderEvent := func(orderHash string, endState zeroex.OrderEventEndState, txHash string) *zeroex.OrderEvent {
+		return &zeroex.OrderEvent{
+			OrderHash: common.HexToHash(orderHash),
+			EndState:  endState,
+			ContractEvents: []*zeroex.ContractEvent{
+				{TxHash: common.HexToHash(txHash)},
+			},
+		}
+	}
+	unfunded := newOrderEvent("0x01", zeroex.ESOrderBecameUnfunded, "0xa1")
+	coalescer.add([]*zeroex.OrderEvent{
+		unfunded,
+		newOrderEvent("0x02", zeroex.ESOrderFilled, "0xa2"),
+	})
+	coalescer.add([]*zeroex.OrderEvent{
+		newOrderEvent("0x01", zeroex.ESOrderFillabilityIncreased, "0xa3"),
+	})
+
+	orderEvents := coalescer.flush()
+	require.Len(t, orderEvents, 2)
+	assert.Equal(t, common.HexToHash("0x02"), orderEvents[0].OrderHash)
+	assert.Equal(t, common.HexToHash("0x01"), orderEvents[1].OrderHash)
+	assert.Equal(t, zeroex.ESOrderFillabilityIncreased, orderEvents[1].EndState)
+	require.Len(t, orderEvents[1].ContractEvents, 2)
+	assert.Equal(t, common.HexToHash("0xa1"), orderEvents[1].ContractEvents[0].TxHash)
+	assert.Equal(t, common.HexToHash("0xa3"), orderEvents[1].ContractEvents[1].TxHash)
+	// The original order events are not modified.
+	assert.Len(t, unfunded.ContractEvents, 1)
+
+	assert.Empty(t, coalescer.flush())
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/google/uuid"
+)
+
+// journalOrderEventsBufferSize is the buffer size of the channel on which the
+// journal receives order events from the OrderWatcher.
+const journalOrderEventsBufferSize = 100
+
+// orderEventBatch is a batch of order events emitted by the OrderWatcher along
+// with its sequence number.
+type orderEventBatch struct {
+	seq         uint64
+	orderEvents []*zeroex.OrderEvent
+}
+
+// orderEventJournal keeps the most recent order events in a bounded buffer so
+// that clients whose order event subscription was interrupted can resume it
+// without missing any events. Each batch of order events is identified by a
+// resume token which consists of an ID that is unique to this process and the
+// sequence number of the batch. Tokens issued before a restart can therefore
+// never be resumed.
+type orderEventJournal struct {
+	mu        sync.Mutex
+	id        string
+	maxEvents int
+	numEvents int
+	lastSeq   uint64
+	batches   []*orderEventBatch
+	feed      event.Feed
+}
+
+// newOrderEventJournal creates a journal which keeps up to maxEvents order
+// events.
+func newOrderEventJournal(maxEvents int) *orderEventJournal {
+	return &orderEventJournal{
+		id:        uuid.New().String(),
+		maxEvents: maxEvents,
+		batches:   []*orderEventBatch{},
+	}
+}
+
+// record appends the order events received on orderEventsChan to the journal
+// and sends them to subscribers until ctx is canceled.
+func (j *orderEventJournal) record(ctx context.Context, orderEventsChan <-chan []*zeroex.OrderEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case orderEvents := <-orderEventsChan:
+			j.append(orderEvents)
+		}
+	}
+}
+
+func (j *orderEventJournal) append(orderEvents []*zeroex.OrderEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.lastSeq++
+	batch := &orderEventBatch{
+		seq:         j.lastSeq,
+		orderEvents: orderEvents,
+	}
+	j.batches = append(j.batches, batch)
+	j.numEvents += len(orderEvents)
+	for len(j.batches) > 0 && j.numEvents > j.maxEvents {
+		j.numEvents -= len(j.batches[0].orderEvents)
+		j.batches[0] = nil
+		j.batches = j.batches[1:]
+	}
+	// Sending while holding the lock guarantees that subscribers which are
+	// added concurrently receive each batch exactly once, either as part of
+	// the replayed batches or via the feed.
+	j.feed.Send(j.notification(batch))
+}
+
+// subscribe subscribes sink to all subsequent batches of order events. It
+// returns the notifications which should be sent to the subscriber before any
+// notifications received on sink. If resumeToken is empty, this is a single
+// notification without order events which contains the current resume token.
+// Otherwise it is either the batches that were recorded after resumeToken or,
+// if some of them have already been dropped from the buffer or the token is
+// unknown, a single notification with ResyncRequired set.
+func (j *orderEventJournal) subscribe(sink chan<- *types.OrderEventsNotification, resumeToken string) ([]*types.OrderEventsNotification, event.Subscription) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	subscription := j.feed.Subscribe(sink)
+	current := &types.OrderEventsNotification{
+		ResumeToken: j.resumeToken(j.lastSeq),
+		OrderEvents: []*zeroex.OrderEvent{},
+	}
+	if resumeToken == "" {
+		return []*types.OrderEventsNotification{current}, subscription
+	}
+	seq, ok := j.parseResumeToken(resumeToken)
+	oldestSeq := j.lastSeq - uint64(len(j.batches)) + 1
+	if !ok || seq > j.lastSeq || seq+1 < oldestSeq {
+		current.ResyncRequired = true
+		return []*types.OrderEventsNotification{current}, subscription
+	}
+	notifications := []*types.OrderEventsNotification{}
+	for _, batch := range j.batches {
+		if batch.seq > seq {
+			notifications = append(notifications, j.notification(batch))
+		}
+	}
+	return notifications, subscription
+}
+
+func (j *orderEventJournal) notification(batch *orderEventBatch) *types.OrderEventsNotification {
+	return &types.OrderEventsNotification{
+		ResumeToken: j.resumeToken(batch.seq),
+		OrderEvents: batch.orderEvents,
+	}
+}
+
+func (j *orderEventJournal) resumeToken(seq uint64) string {
+	return fmt.Sprintf("%s:%d", j.id, seq)
+}
+
+// parseResumeToken returns the sequence number encoded in the given resume
+// token. It returns false if the token was not issued by this journal.
+func (j *orderEventJournal) parseResumeToken(resumeToken string) (uint64, bool) {
+	parts := strings.Split(resumeToken, ":")
+	if len(parts) != 2 || parts[0] != j.id {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return seq, true
+}
@@ -0,0 +1,79 @@
+// +build !js
+
+package core
+
+import (
+	"testing"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOrderEvents(orderHashes ...string) []*zeroex.OrderEvent {
+	orderEvents := make([]*zeroex.OrderEvent, len(orderHashes))
+	for i, orderHash := range orderHashes {
+		orderEvents[i] = &zeroex.OrderEvent{
+			OrderHash: common.HexToHash(orderHash),
+			EndState:  zeroex.ESOrderAdded,
+		}
+	}
+	return orderEvents
+}
+
+func TestOrderEventJournalResume(t *testing.T) {
+	journal := newOrderEventJournal(10)
+	sink := make(chan *types.OrderEventsNotification, 10)
+
+	// A new subscription receives the current resume token.
+	initial, sub := journal.subscribe(sink, "")
+	defer sub.Unsubscribe()
+	require.Len(t, initial, 1)
+	assert.False(t, initial[0].ResyncRequired)
+	assert.Empty(t, initial[0].OrderEvents)
+	initialToken := initial[0].ResumeToken
+
+	journal.append(newTestOrderEvents("0x01", "0x02"))
+	first := <-sink
+	assert.Len(t, first.OrderEvents, 2)
+	journal.append(newTestOrderEvents("0x03"))
+	second := <-sink
+	assert.Len(t, second.OrderEvents, 1)
+
+	// Resuming from the initial token replays both batches.
+	replayed, resumedSub := journal.subscribe(make(chan *types.OrderEventsNotification, 10), initialToken)
+	defer resumedSub.Unsubscribe()
+	require.Len(t, replayed, 2)
+	assert.Equal(t, first.ResumeToken, replayed[0].ResumeToken)
+	assert.Equal(t, second.ResumeToken, replayed[1].ResumeToken)
+
+	// Resuming from the latest token replays nothing.
+	replayed, latestSub := journal.subscribe(make(chan *types.OrderEventsNotification, 10), second.ResumeToken)
+	defer latestSub.Unsubscribe()
+	assert.Empty(t, replayed)
+}
+
+func TestOrderEventJournalResyncRequired(t *testing.T) {
+	journal := newOrderEventJournal(2)
+	initial, sub := journal.subscribe(make(chan *types.OrderEventsNotification, 10), "")
+	defer sub.Unsubscribe()
+	initialToken := initial[0].ResumeToken
+
+	// The first batch is dropped since the buffer only holds two events.
+	journal.append(newTestOrderEvents("0x01", "0x02"))
+	journal.append(newTestOrderEvents("0x03"))
+
+	for _, resumeToken := range []string{
+		initialToken,
+		"unknown:1",
+		newOrderEventJournal(2).resumeToken(0),
+	} {
+		notifications, resumedSub := journal.subscribe(make(chan *types.OrderEventsNotification, 10), resumeToken)
+		resumedSub.Unsubscribe()
+		require.Len(t, notifications, 1)
+		assert.True(t, notifications[0].ResyncRequired)
+		assert.Empty(t, notifications[0].OrderEvents)
+	}
+}
@@ -0,0 +1,147 @@
+// +build !js
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/db"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/orderexport"
+)
+
+// exportOrdersPageSize is the number of orders that are read from the database
+// at once while exporting orders.
+const exportOrdersPageSize = 1000
+
+const (
+	exportOrdersStatusFillable = "fillable"
+	exportOrdersStatusRemoved  = "removed"
+	exportOrdersStatusAll      = "all"
+)
+
+// ErrInvalidExportOrdersOpts is the error returned when an ExportOrders request
+// has invalid options.
+type ErrInvalidExportOrdersOpts struct {
+	reason string
+}
+
+func (e ErrInvalidExportOrdersOpts) Error() string {
+	return fmt.Sprintf("invalid exportOrders options: %s", e.reason)
+}
+
+// ExportOrders writes all orders matching the given options to w in the format
+// given by opts.Format and returns the number of orders that were written. The
+// orders are read from a database snapshot, so orders which are added or
+// removed during the export do not affect it.
+func (app *App) ExportOrders(w io.Writer, opts types.ExportOrdersOpts) (int, error) {
+	<-app.started
+
+	format, err := orderexport.ParseFormat(opts.Format)
+	if err != nil {
+		return 0, ErrInvalidExportOrdersOpts{reason: err.Error()}
+	}
+	filter, err := app.exportOrdersFilter(opts.Status)
+	if err != nil {
+		return 0, err
+	}
+
+	snapshot, err := app.db.Orders.GetSnapshot()
+	if err != nil {
+		return 0, err
+	}
+	defer snapshot.Release()
+
+	writer := orderexport.NewWriter(w, format, opts.Gzip)
+	numOrders := 0
+	for page := 0; ; page++ {
+		var orders []*meshdb.Order
+		if err := snapshot.NewQuery(filter).Offset(page * exportOrdersPageSize).Max(exportOrdersPageSize).Run(&orders); err != nil {
+			return numOrders, err
+		}
+		for _, order := range orders {
+			if !exportOrdersOptsMatch(opts, order) {
+				continue
+			}
+			if err := writer.Write(orderToOrderInfo(order)); err != nil {
+				return numOrders, err
+			}
+			numOrders++
+		}
+		if len(orders) < exportOrdersPageSize {
+			break
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return numOrders, err
+	}
+	return numOrders, nil
+}
+
+// ExportOrdersToFile writes all orders matching the given options to a file in
+// the data directory, overwriting it if it already exists. The file is named
+// orders_export followed by the extension of the export format.
+func (app *App) ExportOrdersToFile(opts types.ExportOrdersOpts) (*types.ExportOrdersResponse, error) {
+	format, err := orderexport.ParseFormat(opts.Format)
+	if err != nil {
+		return nil, ErrInvalidExportOrdersOpts{reason: err.Error()}
+	}
+	path := filepath.Join(app.config.DataDir, "orders_export"+orderexport.FileExtension(format, opts.Gzip))
+	// Write to a temporary file first so that a failed export does not
+	// overwrite the previous one.
+	tmpPath := path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	numOrders, err := app.ExportOrders(file, opts)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return nil, err
+	}
+	return &types.ExportOrdersResponse{
+		Path:      path,
+		NumOrders: numOrders,
+	}, nil
+}
+
+func (app *App) exportOrdersFilter(status string) (*db.Filter, error) {
+	switch status {
+	case "", exportOrdersStatusFillable:
+		return app.db.Orders.IsRemovedIndex.ValueFilter([]byte{0}), nil
+	case exportOrdersStatusRemoved:
+		return app.db.Orders.IsRemovedIndex.ValueFilter([]byte{1}), nil
+	case exportOrdersStatusAll:
+		return app.db.Orders.IsRemovedIndex.All(), nil
+	default:
+		return nil, ErrInvalidExportOrdersOpts{
+			reason: fmt.Sprintf("unsupported status: %q (expected %q, %q or %q)", status, exportOrdersStatusFillable, exportOrdersStatusRemoved, exportOrdersStatusAll),
+		}
+	}
+}
+
+// exportOrdersOptsMatch returns whether the given order matches the maker and
+// asset filters of opts.
+func exportOrdersOptsMatch(opts types.ExportOrdersOpts, order *meshdb.Order) bool {
+	if opts.MakerAddress != nil && order.SignedOrder.MakerAddress != *opts.MakerAddress {
+		return false
+	}
+	if len(opts.MakerAssetData) != 0 && !bytes.Equal(order.SignedOrder.MakerAssetData, opts.MakerAssetData) {
+		return false
+	}
+	if len(opts.TakerAssetData) != 0 && !bytes.Equal(order.SignedOrder.TakerAssetData, opts.TakerAssetData) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"sync"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/db"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// orderHistoryCacheSize is the maximum number of orders whose state
+	// history is kept in memory. The history of the least recently updated
+	// orders is evicted first.
+	orderHistoryCacheSize = 10000
+	// maxOrderHistoryLength is the maximum number of state changes which are
+	// kept for each order.
+	maxOrderHistoryLength = 20
+	// historyOrderEventsBufferSize is the buffer size of the channel on which
+	// the order history receives order events from the order watcher.
+	historyOrderEventsBufferSize = 100
+)
+
+// orderHistory keeps the most recent state changes of recently updated
+// orders in memory, so that they can be returned by GetOrderByHash.
+type orderHistory struct {
+	mu    sync.Mutex
+	cache *lru.Cache
+}
+
+func newOrderHistory(size int) (*orderHistory, error) {
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &orderHistory{cache: cache}, nil
+}
+
+// record adds the order events received on orderEventsChan to the history
+// until ctx is canceled.
+func (h *orderHistory) record(ctx context.Context, orderEventsChan <-chan []*zeroex.OrderEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case orderEvents := <-orderEventsChan:
+			h.add(orderEvents)
+		}
+	}
+}
+
+func (h *orderHistory) add(orderEvents []*zeroex.OrderEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, orderEvent := range orderEvents {
+		var history []*types.OrderStateChange
+		if value, found := h.cache.Get(orderEvent.OrderHash); found {
+			history = value.([]*types.OrderStateChange)
+		}
+		history = append(history, &types.OrderStateChange{
+			Timestamp:                orderEvent.Timestamp,
+			EndState:                 orderEvent.EndState,
+			FillableTakerAssetAmount: orderEvent.FillableTakerAssetAmount,
+		})
+		if len(history) > maxOrderHistoryLength {
+			history = append([]*types.OrderStateChange{}, history[len(history)-maxOrderHistoryLength:]...)
+		}
+		h.cache.Add(orderEvent.OrderHash, history)
+	}
+}
+
+// get returns the state history of the given order, oldest first.
+func (h *orderHistory) get(orderHash common.Hash) []*types.OrderStateChange {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	value, found := h.cache.Get(orderHash)
+	if !found {
+		return []*types.OrderStateChange{}
+	}
+	history := value.([]*types.OrderStateChange)
+	return append([]*types.OrderStateChange{}, history...)
+}
+
+// GetOrderByHash returns the stored order with the given hash along with its
+// provenance and recent state history. Orders which are no longer fillable
+// are returned until they are deleted. It returns nil if no order with the
+// given hash is stored.
+func (app *App) GetOrderByHash(orderHash common.Hash) (*types.GetOrderByHashResponse, error) {
+	<-app.started
+
+	var order meshdb.Order
+	if err := app.db.Orders.FindByID(orderHash.Bytes(), &order); err != nil {
+		if _, ok := err.(db.NotFoundError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	orderInfo := orderToOrderInfo(&order)
+	app.addNotionalValues([]*types.OrderInfo{orderInfo})
+	response := &types.GetOrderByHashResponse{
+		OrderInfo:   orderInfo,
+		IsRemoved:   order.IsRemoved,
+		IsPinned:    order.IsPinned,
+		LastUpdated: order.LastUpdated,
+		History:     app.orderHistory.get(orderHash),
+	}
+	if order.Source != "" {
+		response.Provenance = &types.OrderProvenance{
+			Source:     order.Source,
+			PeerID:     order.SourcePeerID,
+			ReceivedAt: order.ReceivedAt,
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,36 @@
+// +build !js
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderHistory(t *testing.T) {
+	history, err := newOrderHistory(10)
+	require.NoError(t, err)
+	orderHash := common.HexToHash("0x01")
+
+	for i := 0; i < maxOrderHistoryLength+5; i++ {
+		history.add([]*zeroex.OrderEvent{
+			{
+				OrderHash:                orderHash,
+				EndState:                 zeroex.ESOrderFilled,
+				FillableTakerAssetAmount: big.NewInt(int64(i)),
+			},
+		})
+	}
+	changes := history.get(orderHash)
+	require.Len(t, changes, maxOrderHistoryLength)
+	// Only the most recent state changes are kept, oldest first.
+	assert.Equal(t, big.NewInt(5), changes[0].FillableTakerAssetAmount)
+	assert.Equal(t, big.NewInt(maxOrderHistoryLength+4), changes[maxOrderHistoryLength-1].FillableTakerAssetAmount)
+
+	assert.Empty(t, history.get(common.HexToHash("0x02")))
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// maxOrderMetadataSize is the maximum size in bytes of the metadata which can
+// be attached to a single order.
+const maxOrderMetadataSize = 1024
+
+// ErrInvalidAddOrdersOpts is the error returned when an AddOrders request has
+// invalid options.
+type ErrInvalidAddOrdersOpts struct {
+	reason string
+}
+
+func (e ErrInvalidAddOrdersOpts) Error() string {
+	return fmt.Sprintf("invalid addOrders options: %s", e.reason)
+}
+
+// validateOrderMetadata checks that the metadata of each order is valid JSON
+// and does not exceed maxOrderMetadataSize.
+func validateOrderMetadata(metadata map[common.Hash]json.RawMessage) error {
+	for orderHash, orderMetadata := range metadata {
+		if len(orderMetadata) > maxOrderMetadataSize {
+			return ErrInvalidAddOrdersOpts{
+				reason: fmt.Sprintf("metadata of order %s exceeds %d bytes", orderHash.Hex(), maxOrderMetadataSize),
+			}
+		}
+		if !json.Valid(orderMetadata) {
+			return ErrInvalidAddOrdersOpts{
+				reason: fmt.Sprintf("metadata of order %s is not valid JSON", orderHash.Hex()),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+// +build !js
+
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOrderMetadata(t *testing.T) {
+	orderHash := common.HexToHash("0x4e7269386c8f2234305aafb421ba470f39064d79c4826006eaffe723b2066272")
+
+	assert.NoError(t, validateOrderMetadata(nil))
+	assert.NoError(t, validateOrderMetadata(map[common.Hash]json.RawMessage{
+		orderHash: json.RawMessage(`{"clientOrderId":"abc-123","strategy":"twap"}`),
+	}))
+
+	err := validateOrderMetadata(map[common.Hash]json.RawMessage{
+		orderHash: json.RawMessage(`{"clientOrderId":`),
+	})
+	assert.IsType(t, ErrInvalidAddOrdersOpts{}, err)
+
+	tooLarge := `"` + strings.Repeat("a", maxOrderMetadataSize) + `"`
+	err = validateOrderMetadata(map[common.Hash]json.RawMessage{
+		orderHash: json.RawMessage(tooLarge),
+	})
+	assert.IsType(t, ErrInvalidAddOrdersOpts{}, err)
+}
@@ -0,0 +1,106 @@
+// +build !js
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/orderbooksnapshot"
+	log "github.com/sirupsen/logrus"
+)
+
+// orderbookSnapshotPageSize is the number of orders that are read from the
+// database at once while taking an orderbook snapshot.
+const orderbookSnapshotPageSize = 1000
+
+// newOrderbookSnapshotStore returns the store for orderbook snapshots, or nil
+// if snapshots are disabled.
+func newOrderbookSnapshotStore(config Config) (orderbooksnapshot.Store, error) {
+	if config.OrderbookSnapshotInterval < 0 {
+		return nil, errors.New("ORDERBOOK_SNAPSHOT_INTERVAL cannot be negative")
+	}
+	if config.OrderbookSnapshotInterval == 0 {
+		return nil, nil
+	}
+	destination := config.OrderbookSnapshotDestination
+	if destination == "" {
+		destination = filepath.Join(config.DataDir, "orderbook_snapshots")
+	}
+	return orderbooksnapshot.NewStore(destination, orderbooksnapshot.S3Config{
+		Endpoint:        config.OrderbookSnapshotS3Endpoint,
+		Region:          config.OrderbookSnapshotS3Region,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	})
+}
+
+// periodicallySnapshotOrderbook stores an orderbook snapshot every
+// OrderbookSnapshotInterval until the given context is canceled.
+func (app *App) periodicallySnapshotOrderbook(ctx context.Context) {
+	ticker := time.NewTicker(app.config.OrderbookSnapshotInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := app.snapshotOrderbook(ctx); err != nil {
+			log.WithError(err).Error("could not store orderbook snapshot")
+		}
+	}
+}
+
+// snapshotOrderbook takes a snapshot of all fillable orders and stores it in
+// app.orderbookSnapshotStore.
+func (app *App) snapshotOrderbook(ctx context.Context) error {
+	start := time.Now()
+	latestBlockHeader, err := app.db.FindLatestMiniHeader()
+	if err != nil {
+		return err
+	}
+	dbSnapshot, err := app.db.Orders.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer dbSnapshot.Release()
+
+	builder := orderbooksnapshot.NewBuilder()
+	notRemovedFilter := app.db.Orders.IsRemovedIndex.ValueFilter([]byte{0})
+	for page := 0; ; page++ {
+		var orders []*meshdb.Order
+		if err := dbSnapshot.NewQuery(notRemovedFilter).Offset(page * orderbookSnapshotPageSize).Max(orderbookSnapshotPageSize).Run(&orders); err != nil {
+			return err
+		}
+		for _, order := range orders {
+			builder.Add(order.SignedOrder.MakerAssetData, order.SignedOrder.TakerAssetData, order.Hash, order.FillableTakerAssetAmount)
+		}
+		if len(orders) < orderbookSnapshotPageSize {
+			break
+		}
+	}
+	snapshot := builder.Snapshot(start, app.chainID, latestBlockHeader.Number.Int64(), latestBlockHeader.Hash)
+
+	buf := &bytes.Buffer{}
+	if err := snapshot.Encode(buf); err != nil {
+		return err
+	}
+	if err := app.orderbookSnapshotStore.Put(ctx, snapshot.FileName(), buf.Bytes()); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"name":      snapshot.FileName(),
+		"numOrders": snapshot.NumOrders,
+		"numPairs":  len(snapshot.Pairs),
+		"size":      buf.Len(),
+		"duration":  time.Since(start).String(),
+	}).Info("stored orderbook snapshot")
+	return nil
+}
@@ -0,0 +1,24 @@
+// +build js,wasm
+
+package core
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xProject/0x-mesh/orderbooksnapshot"
+)
+
+// newOrderbookSnapshotStore always returns nil, since orderbook snapshots are
+// not supported in the browser. An error is returned if
+// OrderbookSnapshotInterval is set anyway.
+func newOrderbookSnapshotStore(config Config) (orderbooksnapshot.Store, error) {
+	if config.OrderbookSnapshotInterval != 0 {
+		return nil, errors.New("ORDERBOOK_SNAPSHOT_INTERVAL is not supported in the browser")
+	}
+	return nil, nil
+}
+
+// periodicallySnapshotOrderbook is never called in the browser, since
+// newOrderbookSnapshotStore always returns nil.
+func (app *App) periodicallySnapshotOrderbook(ctx context.Context) {}
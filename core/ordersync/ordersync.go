@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/0xProject/0x-mesh/p2p"
@@ -114,6 +115,10 @@ type Service struct {
 	// requestRateLimiter is a rate limiter for incoming ordersync requests. It's
 	// shared between all peers.
 	requestRateLimiter *rate.Limiter
+	// initialSyncDone is closed once PeriodicallyGetOrders has gotten orders
+	// from enough peers for the first time.
+	initialSyncDone     chan struct{}
+	initialSyncDoneOnce sync.Once
 }
 
 // SupportedSubprotocols returns the subprotocols that are supported by the service.
@@ -165,6 +170,7 @@ func New(ctx context.Context, node *p2p.Node, subprotocols []Subprotocol) *Servi
 		node:               node,
 		subprotocols:       supportedSubprotocols,
 		requestRateLimiter: rate.NewLimiter(maxRequestsPerSecond, requestsBurst),
+		initialSyncDone:    make(chan struct{}),
 	}
 	s.node.SetStreamHandler(ID, s.HandleStream)
 	return s
@@ -341,6 +347,12 @@ func (s *Service) GetOrders(ctx context.Context, minPeers int) error {
 	return nil
 }
 
+// InitialSyncDone returns a channel which is closed once PeriodicallyGetOrders
+// has gotten orders from enough peers for the first time.
+func (s *Service) InitialSyncDone() <-chan struct{} {
+	return s.initialSyncDone
+}
+
 // PeriodicallyGetOrders periodically calls GetOrders. It waits a minimum of
 // approxDelay (with some random jitter) between each call. It will block until
 // there is a critical error or the given context is canceled.
@@ -355,6 +367,9 @@ func (s *Service) PeriodicallyGetOrders(ctx context.Context, minPeers int, appro
 		if err := s.GetOrders(ctx, minPeers); err != nil {
 			return err
 		}
+		s.initialSyncDoneOnce.Do(func() {
+			close(s.initialSyncDone)
+		})
 
 		// Note(albrow): The random jitter here helps smooth out the frequency of ordersync
 		// requests and helps prevent a situation where a large number of nodes are requesting
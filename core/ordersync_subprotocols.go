@@ -9,6 +9,8 @@ import (
 	"github.com/0xProject/0x-mesh/core/ordersync"
 	"github.com/0xProject/0x-mesh/orderfilter"
 	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/orderwatch"
+	"github.com/ethereum/go-ethereum/common"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -95,8 +97,12 @@ func (p *FilteredPaginationSubProtocol) HandleOrderSyncRequest(ctx context.Conte
 			// No more orders left.
 			break
 		}
-		// Filter the orders for this page.
+		// Filter the orders for this page. Orders which are only stored locally
+		// are never sent to peers.
 		for _, orderInfo := range ordersResp.OrdersInfos {
+			if p.app.isLocalOnlyOrder(orderInfo.SignedOrder) {
+				continue
+			}
 			if matches, err := p.orderFilter.MatchOrder(orderInfo.SignedOrder); err != nil {
 				return nil, err
 			} else if matches {
@@ -136,18 +142,37 @@ func (p *FilteredPaginationSubProtocol) HandleOrderSyncResponse(ctx context.Cont
 	}
 	filteredOrders := []*zeroex.SignedOrder{}
 	for _, order := range res.Orders {
-		if matches, err := p.orderFilter.MatchOrder(order); err != nil {
+		matches, err := p.orderFilter.MatchOrder(order)
+		if err != nil {
 			return nil, err
-		} else if matches {
+		}
+		if p.app.shadowFilter != nil {
+			p.app.shadowFilter.evaluateOrder(order, matches)
+		}
+		if matches {
 			filteredOrders = append(filteredOrders, order)
-		} else if !matches {
+		} else {
+			p.app.rejectionLog.addFilterMismatch(orderSourceOrderSync, res.ProviderID, order)
 			p.app.handlePeerScoreEvent(res.ProviderID, psReceivedOrderDoesNotMatchFilter)
 		}
 	}
-	validationResults, err := p.app.orderWatcher.ValidateAndStoreValidOrders(ctx, filteredOrders, false, p.app.chainID)
+	storeOpts := orderwatch.StoreOrdersOpts{
+		Source:        orderSourceOrderSync,
+		SourcePeerIDs: make(map[common.Hash]string, len(filteredOrders)),
+	}
+	for _, order := range filteredOrders {
+		orderHash, err := order.ComputeOrderHash()
+		if err != nil {
+			continue
+		}
+		storeOpts.SourcePeerIDs[orderHash] = res.ProviderID.Pretty()
+	}
+	validationResults, err := p.app.orderWatcher.ValidateAndStoreValidOrdersWithOpts(ctx, filteredOrders, storeOpts, p.app.chainID)
 	if err != nil {
 		return nil, err
 	}
+	p.app.recordDryRun(storeOpts, validationResults)
+	p.app.rejectionLog.addValidationResults(orderSourceOrderSync, res.ProviderID, validationResults)
 	for _, acceptedOrderInfo := range validationResults.Accepted {
 		if acceptedOrderInfo.IsNew {
 			log.WithFields(map[string]interface{}{
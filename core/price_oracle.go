@@ -0,0 +1,48 @@
+// +build !js
+
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xProject/0x-mesh/priceoracle"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// newPriceOracle returns the PriceOracle described by the given config, or nil
+// if no price oracle is configured.
+func newPriceOracle(config Config, contractCaller bind.ContractCaller) (priceoracle.PriceOracle, error) {
+	var oracle priceoracle.PriceOracle
+	switch priceoracle.Kind(config.PriceOracle) {
+	case priceoracle.KindNone:
+		if config.MinOrderNotionalUSD > 0 {
+			return nil, errors.New("MIN_ORDER_NOTIONAL_USD requires PRICE_ORACLE to be set")
+		}
+		return nil, nil
+	case priceoracle.KindChainlink:
+		feeds, err := priceoracle.ParseChainlinkFeeds(config.PriceOracleChainlinkFeeds)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PRICE_ORACLE_CHAINLINK_FEEDS: %s", err.Error())
+		}
+		if len(feeds) == 0 {
+			return nil, errors.New("PRICE_ORACLE_CHAINLINK_FEEDS is required when PRICE_ORACLE is \"chainlink\"")
+		}
+		oracle, err = priceoracle.NewChainlink(contractCaller, feeds)
+		if err != nil {
+			return nil, err
+		}
+	case priceoracle.KindHTTP:
+		if config.PriceOracleHTTPURL == "" {
+			return nil, errors.New("PRICE_ORACLE_HTTP_URL is required when PRICE_ORACLE is \"http\"")
+		}
+		var err error
+		oracle, err = priceoracle.NewHTTP(config.PriceOracleHTTPURL)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid PRICE_ORACLE: %q (must be one of \"none\", \"chainlink\" or \"http\")", config.PriceOracle)
+	}
+	return priceoracle.NewCached(oracle, priceoracle.DefaultCacheTTL), nil
+}
@@ -0,0 +1,24 @@
+// +build js,wasm
+
+package core
+
+import (
+	"errors"
+
+	"github.com/0xProject/0x-mesh/priceoracle"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// newPriceOracle always returns nil, since price oracles are not supported in
+// the browser. An error is returned if one is configured anyway.
+func newPriceOracle(config Config, contractCaller bind.ContractCaller) (priceoracle.PriceOracle, error) {
+	switch priceoracle.Kind(config.PriceOracle) {
+	case "", priceoracle.KindNone:
+		if config.MinOrderNotionalUSD > 0 {
+			return nil, errors.New("MIN_ORDER_NOTIONAL_USD is not supported in the browser")
+		}
+		return nil, nil
+	default:
+		return nil, errors.New("PRICE_ORACLE is not supported in the browser")
+	}
+}
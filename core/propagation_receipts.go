@@ -0,0 +1,221 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/db"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	log "github.com/sirupsen/logrus"
+)
+
+// propagationReceiptsProtocolID is the ID of the protocol used to ask peers
+// which of the given orders they have stored.
+const propagationReceiptsProtocolID = protocol.ID("/0x-mesh/propagation-receipts/version/0")
+
+const (
+	// maxPropagationReceiptOrderHashes is the maximum number of order hashes
+	// in a single propagation receipts request.
+	maxPropagationReceiptOrderHashes = 100
+	// maxPropagationReceiptRequestSize is the maximum size in bytes of an
+	// encoded propagation receipts request.
+	maxPropagationReceiptRequestSize = 16 * 1024
+	// propagationReceiptStreamTimeout is the maximum amount of time a peer may
+	// take to send a propagation receipts request.
+	propagationReceiptStreamTimeout = 10 * time.Second
+	// defaultPropagationReceiptsTimeout and maxPropagationReceiptsTimeout are
+	// the default and maximum amount of time to wait for peers to respond.
+	defaultPropagationReceiptsTimeout = 5 * time.Second
+	maxPropagationReceiptsTimeout     = 30 * time.Second
+	// defaultPropagationReceiptsMaxPeers and maxPropagationReceiptsMaxPeers are
+	// the default and maximum number of peers sampled.
+	defaultPropagationReceiptsMaxPeers = 8
+	maxPropagationReceiptsMaxPeers     = 32
+)
+
+// ErrInvalidPropagationReceiptsOpts is the error returned when a
+// GetPropagationReceipts request contains invalid order hashes or options.
+type ErrInvalidPropagationReceiptsOpts struct {
+	reason string
+}
+
+func (e ErrInvalidPropagationReceiptsOpts) Error() string {
+	return fmt.Sprintf("invalid propagation receipts request: %s", e.reason)
+}
+
+type propagationReceiptsRequest struct {
+	OrderHashes []common.Hash `json:"orderHashes"`
+}
+
+type propagationReceiptsResponse struct {
+	StoredOrderHashes []common.Hash `json:"storedOrderHashes"`
+}
+
+// handlePropagationReceiptsStream responds to a request from a peer with the
+// subset of the requested order hashes which belong to orders that are stored
+// and have not been removed.
+func (app *App) handlePropagationReceiptsStream(stream network.Stream) {
+	defer func() {
+		_ = stream.Close()
+	}()
+	requesterID := stream.Conn().RemotePeer()
+	_ = stream.SetDeadline(time.Now().Add(propagationReceiptStreamTimeout))
+
+	var req propagationReceiptsRequest
+	if err := json.NewDecoder(io.LimitReader(stream, maxPropagationReceiptRequestSize)).Decode(&req); err != nil {
+		log.WithFields(log.Fields{
+			"error":     err.Error(),
+			"requester": requesterID.Pretty(),
+		}).Trace("could not decode propagation receipts request")
+		app.handlePeerScoreEvent(requesterID, psInvalidMessage)
+		return
+	}
+	if len(req.OrderHashes) > maxPropagationReceiptOrderHashes {
+		app.handlePeerScoreEvent(requesterID, psInvalidMessage)
+		return
+	}
+
+	res := propagationReceiptsResponse{StoredOrderHashes: []common.Hash{}}
+	for _, orderHash := range req.OrderHashes {
+		var order meshdb.Order
+		if err := app.db.Orders.FindByID(orderHash.Bytes(), &order); err != nil {
+			if _, ok := err.(db.NotFoundError); !ok {
+				log.WithError(err).Error("could not look up order for propagation receipts request")
+				return
+			}
+			continue
+		}
+		if !order.IsRemoved {
+			res.StoredOrderHashes = append(res.StoredOrderHashes, orderHash)
+		}
+	}
+	if err := json.NewEncoder(stream).Encode(res); err != nil {
+		log.WithFields(log.Fields{
+			"error":     err.Error(),
+			"requester": requesterID.Pretty(),
+		}).Trace("could not encode propagation receipts response")
+	}
+}
+
+// requestPropagationReceipts asks the given peer which of the given orders it
+// has stored.
+func (app *App) requestPropagationReceipts(ctx context.Context, peerID peer.ID, orderHashes []common.Hash) ([]common.Hash, error) {
+	stream, err := app.node.NewStream(ctx, peerID, propagationReceiptsProtocolID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = stream.SetDeadline(deadline)
+	}
+	if err := json.NewEncoder(stream).Encode(propagationReceiptsRequest{OrderHashes: orderHashes}); err != nil {
+		return nil, err
+	}
+	var res propagationReceiptsResponse
+	if err := json.NewDecoder(stream).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.StoredOrderHashes, nil
+}
+
+// GetPropagationReceipts estimates how far the given orders have propagated
+// through the network. It samples up to opts.MaxPeers of the peers subscribed
+// to the order topic, asks each of them whether they have stored the orders
+// and waits up to opts.Timeout for their responses. The number of peers which
+// have stored an order is extrapolated to all subscribed peers to estimate its
+// propagation count.
+func (app *App) GetPropagationReceipts(ctx context.Context, orderHashes []common.Hash, opts types.GetPropagationReceiptsOpts) (*types.PropagationReceipts, error) {
+	<-app.started
+
+	if len(orderHashes) == 0 || len(orderHashes) > maxPropagationReceiptOrderHashes {
+		return nil, ErrInvalidPropagationReceiptsOpts{reason: fmt.Sprintf("between 1 and %d order hashes are required", maxPropagationReceiptOrderHashes)}
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = defaultPropagationReceiptsTimeout
+	} else if timeout < 0 || timeout > maxPropagationReceiptsTimeout {
+		return nil, ErrInvalidPropagationReceiptsOpts{reason: fmt.Sprintf("timeout must be between 0 and %s", maxPropagationReceiptsTimeout)}
+	}
+	maxPeers := opts.MaxPeers
+	if maxPeers == 0 {
+		maxPeers = defaultPropagationReceiptsMaxPeers
+	} else if maxPeers < 0 || maxPeers > maxPropagationReceiptsMaxPeers {
+		return nil, ErrInvalidPropagationReceiptsOpts{reason: fmt.Sprintf("maxPeers must be between 0 and %d", maxPropagationReceiptsMaxPeers)}
+	}
+
+	topicPeers := app.node.TopicPeers()
+	sampledPeers := make([]peer.ID, len(topicPeers))
+	copy(sampledPeers, topicPeers)
+	rand.Shuffle(len(sampledPeers), func(i, j int) {
+		sampledPeers[i], sampledPeers[j] = sampledPeers[j], sampledPeers[i]
+	})
+	if len(sampledPeers) > maxPeers {
+		sampledPeers = sampledPeers[:maxPeers]
+	}
+
+	requestCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	mu := sync.Mutex{}
+	numPeersResponded := 0
+	orderHashToNumPeersStored := map[common.Hash]int{}
+	wg := &sync.WaitGroup{}
+	for _, peerID := range sampledPeers {
+		wg.Add(1)
+		go func(peerID peer.ID) {
+			defer wg.Done()
+			storedOrderHashes, err := app.requestPropagationReceipts(requestCtx, peerID, orderHashes)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"error":  err.Error(),
+					"peerID": peerID.Pretty(),
+				}).Trace("could not get propagation receipts from peer")
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			numPeersResponded++
+			// Only count each requested order hash once per peer.
+			seen := map[common.Hash]bool{}
+			for _, orderHash := range storedOrderHashes {
+				if !seen[orderHash] {
+					seen[orderHash] = true
+					orderHashToNumPeersStored[orderHash]++
+				}
+			}
+		}(peerID)
+	}
+	wg.Wait()
+
+	receipts := &types.PropagationReceipts{
+		NumTopicPeers:     len(topicPeers),
+		NumPeersQueried:   len(sampledPeers),
+		NumPeersResponded: numPeersResponded,
+		Receipts:          make([]*types.PropagationReceipt, len(orderHashes)),
+	}
+	for i, orderHash := range orderHashes {
+		numPeersStored := orderHashToNumPeersStored[orderHash]
+		estimatedCount := 0
+		if numPeersResponded > 0 {
+			estimatedCount = int(math.Round(float64(numPeersStored) / float64(numPeersResponded) * float64(len(topicPeers))))
+		}
+		receipts.Receipts[i] = &types.PropagationReceipt{
+			OrderHash:                 orderHash,
+			NumPeersStored:            numPeersStored,
+			EstimatedPropagationCount: estimatedCount,
+		}
+	}
+	return receipts, nil
+}
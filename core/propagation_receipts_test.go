@@ -0,0 +1,101 @@
+// +build !js
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/scenario"
+	"github.com/0xProject/0x-mesh/scenario/orderopts"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropagationReceipts(t *testing.T) {
+	if !serialTestsEnabled {
+		t.Skip("Serial tests (tests which cannot run in parallel) are disabled. You can enable them with the --serial flag")
+	}
+
+	teardownSubTest := setupSubTest(t)
+	defer teardownSubTest(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	wg := &sync.WaitGroup{}
+
+	// Set up two Mesh nodes. storingNode has an order stored which
+	// requestingNode asks about.
+	storingNode := newTestApp(t)
+	requestingNode := newTestApp(t)
+	for _, app := range []*App{storingNode, requestingNode} {
+		wg.Add(1)
+		go func(app *App) {
+			defer wg.Done()
+			if err := app.Start(ctx); err != nil && err != context.Canceled {
+				// context.Canceled is expected. For any other error, fail the test.
+				require.NoError(t, err)
+			}
+		}(app)
+	}
+	<-storingNode.started
+	<-requestingNode.started
+
+	// We have to wait for latest block to be processed by the Mesh node.
+	time.Sleep(blockProcessingWaitTime)
+
+	signedOrder := scenario.NewSignedTestOrder(t, orderopts.SetupMakerState(true))
+	results, err := storingNode.orderWatcher.ValidateAndStoreValidOrders(ctx, []*zeroex.SignedOrder{signedOrder}, true, constants.TestChainID)
+	require.NoError(t, err)
+	require.Empty(t, results.Rejected, "tried to add order but it was invalid: \n%s\n", spew.Sdump(results))
+	storedOrderHash, err := signedOrder.ComputeOrderHash()
+	require.NoError(t, err)
+	unknownOrderHash := common.HexToHash("0x1")
+
+	err = requestingNode.AddPeer(peer.AddrInfo{
+		ID:    storingNode.node.ID(),
+		Addrs: storingNode.node.Multiaddrs(),
+	})
+	require.NoError(t, err)
+
+	// Only the order hashes of stored orders are returned.
+	storedOrderHashes, err := requestingNode.requestPropagationReceipts(ctx, storingNode.node.ID(), []common.Hash{storedOrderHash, unknownOrderHash})
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{storedOrderHash}, storedOrderHashes)
+
+	// Requests with too many order hashes are not answered.
+	tooManyOrderHashes := make([]common.Hash, maxPropagationReceiptOrderHashes+1)
+	tooManyOrderHashes[0] = storedOrderHash
+	_, err = requestingNode.requestPropagationReceipts(ctx, storingNode.node.ID(), tooManyOrderHashes)
+	assert.Error(t, err)
+
+	// Requests which exceed the size limit are not answered.
+	stream, err := requestingNode.node.NewStream(ctx, storingNode.node.ID(), propagationReceiptsProtocolID)
+	require.NoError(t, err)
+	defer func() {
+		_ = stream.Close()
+	}()
+	require.NoError(t, stream.SetDeadline(time.Now().Add(propagationReceiptStreamTimeout)))
+	padding := strings.Repeat(" ", maxPropagationReceiptRequestSize)
+	oversizedRequest, err := json.Marshal(propagationReceiptsRequest{OrderHashes: []common.Hash{storedOrderHash}})
+	require.NoError(t, err)
+	oversizedRequest = append([]byte(padding), oversizedRequest...)
+	_, err = stream.Write(oversizedRequest)
+	require.NoError(t, err)
+	response, _ := ioutil.ReadAll(stream)
+	assert.Empty(t, response)
+
+	// Wait for nodes to exit without error.
+	cancel()
+	wg.Wait()
+}
@@ -0,0 +1,23 @@
+// +build !js
+
+package core
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/0xProject/0x-mesh/socksproxy"
+)
+
+// parseProxyURL returns the SOCKS5 proxy URL described by the given config, or
+// nil if no proxy is configured.
+func parseProxyURL(config Config) (*url.URL, error) {
+	if config.ProxyURL == "" {
+		return nil, nil
+	}
+	proxyURL, err := socksproxy.ParseURL(config.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROXY_URL: %s", err.Error())
+	}
+	return proxyURL, nil
+}
@@ -0,0 +1,17 @@
+// +build js,wasm
+
+package core
+
+import (
+	"errors"
+	"net/url"
+)
+
+// parseProxyURL always returns nil, since the browser can't dial peers through
+// a SOCKS5 proxy. An error is returned if one is configured anyway.
+func parseProxyURL(config Config) (*url.URL, error) {
+	if config.ProxyURL != "" {
+		return nil, errors.New("PROXY_URL is not supported in the browser")
+	}
+	return nil, nil
+}
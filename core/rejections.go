@@ -0,0 +1,208 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/encoding"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+// Sources of orders, which are recorded in the rejection log and stored along
+// with accepted orders. Orders added via the JSON-RPC API or in the browser
+// have the source orderSourceAPI.
+const (
+	orderSourceAPI       = "api"
+	orderSourceGossipSub = "gossipsub"
+	orderSourceOrderSync = "ordersync"
+)
+
+// rejectionLog counts the rejected orders by status code and keeps the most
+// recent rejections in a ring buffer. It is used to debug why orders are not
+// propagating through the network.
+type rejectionLog struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+	// recent is a ring buffer. next is the index at which the next rejection
+	// is written and full is true once the buffer has wrapped around.
+	recent []*rejection
+	next   int
+	full   bool
+}
+
+// rejection is an entry of the ring buffer of recent rejections. If the
+// rejected order was received in a GossipSub message which has not been
+// decoded, the message is kept in rawMessage and only decoded when the
+// rejection is read.
+type rejection struct {
+	record     *types.RejectionRecord
+	rawMessage []byte
+}
+
+// newRejectionLog returns a rejectionLog which keeps up to size recent
+// rejections. If size is 0, rejections are only counted.
+func newRejectionLog(size int) *rejectionLog {
+	return &rejectionLog{
+		counts: map[string]uint64{},
+		recent: make([]*rejection, size),
+	}
+}
+
+// add records the rejection of an order. order may be nil if the order could
+// not be decoded. peerID is empty if the order was not received from a peer.
+func (l *rejectionLog) add(source string, peerID peer.ID, orderHash common.Hash, order *zeroex.SignedOrder, status ordervalidator.RejectedOrderStatus) {
+	l.addRejection(&rejection{record: newRejectionRecord(source, peerID, orderHash, order, status)})
+}
+
+// addRejection adds the given rejection to the counts and to the ring buffer
+// of recent rejections.
+func (l *rejectionLog) addRejection(r *rejection) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.counts[r.record.Code]++
+	if len(l.recent) == 0 {
+		return
+	}
+	l.recent[l.next] = r
+	l.next = (l.next + 1) % len(l.recent)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// newRejectionRecord returns the record of the rejection of an order. order may
+// be nil if the order could not be decoded. peerID is empty if the order was not
+// received from a peer.
+func newRejectionRecord(source string, peerID peer.ID, orderHash common.Hash, order *zeroex.SignedOrder, status ordervalidator.RejectedOrderStatus) *types.RejectionRecord {
+	record := &types.RejectionRecord{
+		Time:      time.Now().UTC(),
+		OrderHash: orderHash,
+		Code:      status.Code,
+		Message:   status.Message,
+		Source:    source,
+	}
+	if order != nil {
+		record.MakerAddress = order.MakerAddress
+	}
+	if peerID != "" {
+		record.PeerID = peerID.Pretty()
+	}
+	return record
+}
+
+// addValidationResults records all rejected orders in the given validation
+// results.
+func (l *rejectionLog) addValidationResults(source string, peerID peer.ID, results *ordervalidator.ValidationResults) {
+	for _, rejectedOrderInfo := range results.Rejected {
+		l.add(source, peerID, rejectedOrderInfo.OrderHash, rejectedOrderInfo.SignedOrder, rejectedOrderInfo.Status)
+	}
+}
+
+// addFilterMismatch records the rejection of an order received from a peer
+// which doesn't match the custom order filter.
+func (l *rejectionLog) addFilterMismatch(source string, peerID peer.ID, order *zeroex.SignedOrder) {
+	var orderHash common.Hash
+	if order != nil {
+		// The order hash is only informational, so errors are ignored.
+		orderHash, _ = order.ComputeOrderHash()
+	}
+	l.add(source, peerID, orderHash, order, ordervalidator.RODoesNotMatchFilter)
+}
+
+// addFilterMismatchMessage records the rejection of a GossipSub message
+// received from a peer which doesn't match the custom order filter. Such
+// messages are dropped before they are decoded, so the message is only
+// decoded if the rejection is read via recentRejections.
+func (l *rejectionLog) addFilterMismatchMessage(source string, peerID peer.ID, rawMessage []byte) {
+	r := &rejection{
+		record: newRejectionRecord(source, peerID, common.Hash{}, nil, ordervalidator.RODoesNotMatchFilter),
+	}
+	if len(l.recent) != 0 {
+		r.rawMessage = rawMessage
+	}
+	l.addRejection(r)
+}
+
+// countsByCode returns a copy of the number of rejections by status code.
+func (l *rejectionLog) countsByCode() map[string]uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	counts := make(map[string]uint64, len(l.counts))
+	for code, count := range l.counts {
+		counts[code] = count
+	}
+	return counts
+}
+
+// recentRejections returns the recent rejections of orders from the given
+// maker, most recent first. If makerAddress is nil, rejections of orders from
+// all makers are returned.
+func (l *rejectionLog) recentRejections(makerAddress *common.Address) []*types.RejectionRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	numRecords := l.next
+	if l.full {
+		numRecords = len(l.recent)
+	}
+	records := []*types.RejectionRecord{}
+	for i := 0; i < numRecords; i++ {
+		index := (l.next - 1 - i + len(l.recent)) % len(l.recent)
+		record := l.recent[index].decodedRecord()
+		if makerAddress != nil && record.MakerAddress != *makerAddress {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// decodedRecord returns the record of the rejection, decoding the rejected
+// GossipSub message first if that hasn't happened yet. It must be called with
+// the lock of the rejectionLog held.
+func (r *rejection) decodedRecord() *types.RejectionRecord {
+	if r.rawMessage == nil {
+		return r.record
+	}
+	// The order is nil if the message can't be decoded, in which case the
+	// record keeps the zero order hash and maker address.
+	if order, err := encoding.RawMessageToOrder(r.rawMessage); err == nil && order != nil {
+		r.record.MakerAddress = order.MakerAddress
+		// The order hash is only informational, so errors are ignored.
+		r.record.OrderHash, _ = order.ComputeOrderHash()
+	}
+	r.rawMessage = nil
+	return r.record
+}
+
+// GetRecentRejections returns the number of rejected orders by status code
+// and the most recent rejections. If opts.MakerAddress is set, only the
+// rejections of orders from that maker are returned.
+func (app *App) GetRecentRejections(opts types.GetRecentRejectionsOpts) *types.RecentRejections {
+	return &types.RecentRejections{
+		CountsByCode: app.rejectionLog.countsByCode(),
+		Rejections:   app.rejectionLog.recentRejections(opts.MakerAddress),
+	}
+}
+
+// validatePubSubMessage validates GossipSub messages against the custom order
+// filter and records the rejection of messages received from peers which
+// don't match it.
+func (app *App) validatePubSubMessage(ctx context.Context, sender peer.ID, msg *pubsub.Message) bool {
+	isValid := app.orderFilter.ValidatePubSubMessage(ctx, sender, msg)
+	if app.shadowFilter != nil && sender != app.peerID {
+		app.shadowFilter.evaluateMessage(msg.Data, isValid)
+	}
+	if isValid {
+		return true
+	}
+	if sender != app.peerID {
+		app.rejectionLog.addFilterMismatchMessage(orderSourceGossipSub, sender, msg.Data)
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+// +build !js
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/encoding"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejectionLog(t *testing.T) {
+	rejections := newRejectionLog(2)
+	order0 := &zeroex.SignedOrder{Order: zeroex.Order{MakerAddress: constants.GanacheAccount0}}
+	order1 := &zeroex.SignedOrder{Order: zeroex.Order{MakerAddress: constants.GanacheAccount1}}
+	rejections.add(orderSourceAPI, "", common.HexToHash("0x1"), order0, ordervalidator.ROExpired)
+	rejections.add(orderSourceAPI, "", common.HexToHash("0x2"), order1, ordervalidator.ROUnfunded)
+	rejections.add(orderSourceAPI, "", common.HexToHash("0x3"), order0, ordervalidator.ROExpired)
+
+	assert.Equal(t, map[string]uint64{
+		ordervalidator.ROExpired.Code:  2,
+		ordervalidator.ROUnfunded.Code: 1,
+	}, rejections.countsByCode())
+
+	// Only the two most recent rejections are kept, most recent first.
+	records := rejections.recentRejections(nil)
+	require.Len(t, records, 2)
+	assert.Equal(t, common.HexToHash("0x3"), records[0].OrderHash)
+	assert.Equal(t, common.HexToHash("0x2"), records[1].OrderHash)
+	assert.Equal(t, ordervalidator.ROUnfunded.Code, records[1].Code)
+	assert.Equal(t, constants.GanacheAccount1, records[1].MakerAddress)
+
+	maker := constants.GanacheAccount0
+	records = rejections.recentRejections(&maker)
+	require.Len(t, records, 1)
+	assert.Equal(t, common.HexToHash("0x3"), records[0].OrderHash)
+}
+
+func TestRejectionLogWithoutRecentRejections(t *testing.T) {
+	rejections := newRejectionLog(0)
+	rejections.add(orderSourceAPI, "", common.HexToHash("0x1"), nil, ordervalidator.ROExpired)
+	assert.Equal(t, map[string]uint64{ordervalidator.ROExpired.Code: 1}, rejections.countsByCode())
+	assert.Empty(t, rejections.recentRejections(nil))
+}
+
+func TestRejectionLogFilterMismatchMessage(t *testing.T) {
+	rejections := newRejectionLog(2)
+	order := &zeroex.SignedOrder{Order: zeroex.Order{
+		MakerAddress:          constants.GanacheAccount0,
+		MakerAssetAmount:      big.NewInt(1),
+		TakerAssetAmount:      big.NewInt(1),
+		MakerFee:              big.NewInt(0),
+		TakerFee:              big.NewInt(0),
+		ExpirationTimeSeconds: big.NewInt(1),
+		Salt:                  big.NewInt(1),
+		ChainID:               big.NewInt(constants.TestChainID),
+	}}
+	orderHash, err := order.ComputeOrderHash()
+	require.NoError(t, err)
+	rawMessage, err := encoding.OrderToRawMessage("topic", order)
+	require.NoError(t, err)
+	rejections.addFilterMismatchMessage(orderSourceGossipSub, "", rawMessage)
+	rejections.addFilterMismatchMessage(orderSourceGossipSub, "", []byte("not an order message"))
+
+	assert.Equal(t, map[string]uint64{ordervalidator.RODoesNotMatchFilter.Code: 2}, rejections.countsByCode())
+
+	// The messages are decoded when the rejections are read. Messages which
+	// can't be decoded are recorded without an order hash and maker.
+	records := rejections.recentRejections(nil)
+	require.Len(t, records, 2)
+	assert.Equal(t, common.Hash{}, records[0].OrderHash)
+	assert.Equal(t, common.Address{}, records[0].MakerAddress)
+	assert.Equal(t, orderHash, records[1].OrderHash)
+	assert.Equal(t, constants.GanacheAccount0, records[1].MakerAddress)
+	assert.Equal(t, ordervalidator.RODoesNotMatchFilter.Code, records[1].Code)
+	assert.Equal(t, orderSourceGossipSub, records[1].Source)
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/0xProject/0x-mesh/priceoracle"
+	"github.com/0xProject/0x-mesh/zeroex/orderwatch"
+)
+
+// ErrRelayOnly is returned by methods which depend on Ethereum when Mesh runs
+// in relay-only mode.
+var ErrRelayOnly = errors.New("not supported in relay-only mode (unset RELAY_ONLY and set ETHEREUM_RPC_URL to enable it)")
+
+// validateRelayOnlyConfig returns an error if an option which depends on
+// Ethereum is set in relay-only mode.
+func validateRelayOnlyConfig(config Config) error {
+	if config.TransferSimulationMode != "" && orderwatch.TransferSimulationMode(config.TransferSimulationMode) != orderwatch.TransferSimulationOff {
+		return errors.New("TRANSFER_SIMULATION_MODE must be \"off\" in relay-only mode")
+	}
+	if priceoracle.Kind(config.PriceOracle) == priceoracle.KindChainlink {
+		return errors.New("PRICE_ORACLE \"chainlink\" is not supported in relay-only mode")
+	}
+	if config.OrderEventConfirmationDepth != 0 {
+		return errors.New("ORDER_EVENT_CONFIRMATION_DEPTH must be 0 in relay-only mode")
+	}
+	if config.OrderbookSnapshotInterval != 0 {
+		return errors.New("ORDERBOOK_SNAPSHOT_INTERVAL must be 0 in relay-only mode")
+	}
+	if config.AlertEthRPCUnreachableFor != 0 || config.AlertMaxBlockLag != 0 {
+		return errors.New("ALERT_ETH_RPC_UNREACHABLE_FOR and ALERT_MAX_BLOCK_LAG must be 0 in relay-only mode")
+	}
+	return nil
+}
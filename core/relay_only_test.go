@@ -0,0 +1,30 @@
+// +build !js
+
+package core
+
+import (
+	"testing"
+
+	"github.com/0xProject/0x-mesh/ethereum/blockwatch"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionsInRelayOnlyMode(t *testing.T) {
+	app := &App{config: Config{RelayOnly: true}}
+
+	blockSub, err := app.SubscribeToBlockEvents(make(chan []*blockwatch.Event))
+	assert.Equal(t, ErrRelayOnly, err)
+	assert.Nil(t, blockSub)
+
+	contractEventsSub, err := app.SubscribeToContractEvents(make(chan []*zeroex.ContractEvent))
+	assert.Equal(t, ErrRelayOnly, err)
+	assert.Nil(t, contractEventsSub)
+}
+
+func TestValidateRelayOnlyConfig(t *testing.T) {
+	assert.NoError(t, validateRelayOnlyConfig(Config{RelayOnly: true, TransferSimulationMode: "off"}))
+	assert.Error(t, validateRelayOnlyConfig(Config{RelayOnly: true, TransferSimulationMode: "strict"}))
+	assert.Error(t, validateRelayOnlyConfig(Config{RelayOnly: true, PriceOracle: "chainlink"}))
+	assert.Error(t, validateRelayOnlyConfig(Config{RelayOnly: true, OrderEventConfirmationDepth: 2}))
+}
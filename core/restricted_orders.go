@@ -0,0 +1,36 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/orderwatch"
+)
+
+// parseRestrictedOrderPolicy parses the value of the TAKER_RESTRICTED_ORDERS
+// or SENDER_RESTRICTED_ORDERS environment variable. An empty value (e.g. if
+// the config was not loaded from environment variables) results in
+// defaultPolicy.
+func parseRestrictedOrderPolicy(envVarName string, value string, defaultPolicy orderwatch.RestrictedOrderPolicy) (orderwatch.RestrictedOrderPolicy, error) {
+	policy := orderwatch.RestrictedOrderPolicy(value)
+	switch policy {
+	case "":
+		return defaultPolicy, nil
+	case orderwatch.RestrictedOrdersAccept, orderwatch.RestrictedOrdersLocal, orderwatch.RestrictedOrdersReject:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid %s: %q (must be one of \"accept\", \"local\" or \"reject\")", envVarName, value)
+	}
+}
+
+// isLocalOnlyOrder returns true if the given order is stored by this node but
+// must not be shared with peers, either via GossipSub or via ordersync.
+func (app *App) isLocalOnlyOrder(order *zeroex.SignedOrder) bool {
+	if order.IsTakerRestricted() && orderwatch.RestrictedOrderPolicy(app.config.TakerRestrictedOrders) == orderwatch.RestrictedOrdersLocal {
+		return true
+	}
+	if order.IsSenderRestricted() && orderwatch.RestrictedOrderPolicy(app.config.SenderRestrictedOrders) == orderwatch.RestrictedOrdersLocal {
+		return true
+	}
+	return false
+}
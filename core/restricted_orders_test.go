@@ -0,0 +1,56 @@
+// +build !js
+
+package core
+
+import (
+	"testing"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/orderwatch"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRestrictedOrderPolicy(t *testing.T) {
+	for _, value := range []string{"accept", "local", "reject"} {
+		policy, err := parseRestrictedOrderPolicy("TAKER_RESTRICTED_ORDERS", value, orderwatch.RestrictedOrdersAccept)
+		assert.NoError(t, err)
+		assert.Equal(t, orderwatch.RestrictedOrderPolicy(value), policy)
+	}
+	policy, err := parseRestrictedOrderPolicy("SENDER_RESTRICTED_ORDERS", "", orderwatch.RestrictedOrdersReject)
+	assert.NoError(t, err)
+	assert.Equal(t, orderwatch.RestrictedOrdersReject, policy)
+	_, err = parseRestrictedOrderPolicy("TAKER_RESTRICTED_ORDERS", "drop", orderwatch.RestrictedOrdersAccept)
+	assert.Error(t, err)
+}
+
+func TestIsLocalOnlyOrder(t *testing.T) {
+	newOrder := func(takerAddress, senderAddress common.Address) *zeroex.SignedOrder {
+		return &zeroex.SignedOrder{
+			Order: zeroex.Order{
+				TakerAddress:  takerAddress,
+				SenderAddress: senderAddress,
+			},
+		}
+	}
+	unrestricted := newOrder(constants.NullAddress, constants.NullAddress)
+	takerRestricted := newOrder(constants.GanacheAccount1, constants.NullAddress)
+	senderRestricted := newOrder(constants.NullAddress, constants.GanacheAccount2)
+
+	app := &App{
+		config: Config{
+			TakerRestrictedOrders:  "local",
+			SenderRestrictedOrders: "accept",
+		},
+	}
+	assert.False(t, app.isLocalOnlyOrder(unrestricted))
+	assert.True(t, app.isLocalOnlyOrder(takerRestricted))
+	assert.False(t, app.isLocalOnlyOrder(senderRestricted))
+
+	app.config.TakerRestrictedOrders = "accept"
+	app.config.SenderRestrictedOrders = "local"
+	assert.False(t, app.isLocalOnlyOrder(unrestricted))
+	assert.False(t, app.isLocalOnlyOrder(takerRestricted))
+	assert.True(t, app.isLocalOnlyOrder(senderRestricted))
+}
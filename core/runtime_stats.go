@@ -0,0 +1,56 @@
+package core
+
+import (
+	"io/ioutil"
+	"runtime"
+	"time"
+
+	"github.com/0xProject/0x-mesh/common/types"
+)
+
+// maxRecentGCPauses is the maximum number of GC pauses included in
+// types.RuntimeStats.
+const maxRecentGCPauses = 16
+
+// GetRuntimeStats returns statistics about the Go runtime, such as the number
+// of goroutines, heap usage and GC pauses. It is useful for debugging memory
+// growth and goroutine leaks. Note that reading the memory statistics briefly
+// stops the world.
+func (app *App) GetRuntimeStats() *types.RuntimeStats {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	// PauseNs is a circular buffer. The most recent pause is at
+	// PauseNs[(NumGC+255)%256].
+	numPauses := int(memStats.NumGC)
+	if numPauses > maxRecentGCPauses {
+		numPauses = maxRecentGCPauses
+	}
+	recentGCPauses := make([]time.Duration, numPauses)
+	for i := 0; i < numPauses; i++ {
+		index := (int(memStats.NumGC) - 1 - i + len(memStats.PauseNs)) % len(memStats.PauseNs)
+		recentGCPauses[i] = time.Duration(memStats.PauseNs[index])
+	}
+
+	return &types.RuntimeStats{
+		NumGoroutines:  runtime.NumGoroutine(),
+		HeapAlloc:      memStats.HeapAlloc,
+		HeapInuse:      memStats.HeapInuse,
+		HeapSys:        memStats.HeapSys,
+		HeapObjects:    memStats.HeapObjects,
+		NumGC:          memStats.NumGC,
+		GCPauseTotal:   time.Duration(memStats.PauseTotalNs),
+		RecentGCPauses: recentGCPauses,
+		NumOpenFDs:     numOpenFDs(),
+	}
+}
+
+// numOpenFDs returns the number of open file descriptors of the current
+// process or -1 if it cannot be determined. Only Linux is supported.
+func numOpenFDs() int {
+	fds, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		return -1
+	}
+	return len(fds)
+}
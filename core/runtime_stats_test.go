@@ -0,0 +1,24 @@
+// +build !js
+
+package core
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRuntimeStats(t *testing.T) {
+	runtime.GC()
+	runtime.GC()
+
+	app := &App{}
+	stats := app.GetRuntimeStats()
+	assert.True(t, stats.NumGoroutines > 0)
+	assert.True(t, stats.HeapAlloc > 0)
+	assert.True(t, stats.NumGC >= 2)
+	assert.True(t, len(stats.RecentGCPauses) >= 2)
+	assert.True(t, len(stats.RecentGCPauses) <= maxRecentGCPauses)
+	assert.NotEqual(t, 0, stats.NumOpenFDs)
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/ethereum"
+	"github.com/0xProject/0x-mesh/orderfilter"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// shadowFilter evaluates a candidate order filter against the orders received
+// by Mesh in parallel with the active filter, without affecting which orders
+// are accepted. It counts how many orders the candidate filter would accept
+// or reject, so that operators can assess a filter change before switching to
+// the new pubsub topic.
+type shadowFilter struct {
+	filter *orderfilter.Filter
+	mu     sync.Mutex
+	stats  types.ShadowFilterStats
+}
+
+// newShadowFilter returns a shadowFilter for the ShadowOrderFilter in the
+// given config or nil if none is configured. Like the active filter, it is
+// combined with the fee recipient allowlist.
+func newShadowFilter(config Config, feeRecipientAllowlist []common.Address, contractAddresses ethereum.ContractAddresses) (*shadowFilter, error) {
+	if config.ShadowOrderFilter == "" {
+		return nil, nil
+	}
+	customOrderFilter, err := orderfilter.WithFeeRecipientAllowlist(config.ShadowOrderFilter, feeRecipientAllowlist)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := orderfilter.New(config.EthereumChainID, customOrderFilter, contractAddresses)
+	if err != nil {
+		return nil, err
+	}
+	return &shadowFilter{
+		filter: filter,
+		stats: types.ShadowFilterStats{
+			PubSubTopic: filter.Topic(),
+			Since:       time.Now().UTC(),
+		},
+	}, nil
+}
+
+// evaluateMessage evaluates the shadow filter against the order in the given
+// GossipSub message. activeMatch is whether the active filter accepted it.
+func (s *shadowFilter) evaluateMessage(data []byte, activeMatch bool) {
+	shadowMatch, err := s.filter.MatchOrderMessageJSON(data)
+	if err != nil {
+		log.WithError(err).Trace("could not evaluate shadow order filter")
+		return
+	}
+	s.record(activeMatch, shadowMatch)
+}
+
+// evaluateOrderJSON evaluates the shadow filter against the given JSON
+// encoded order. activeMatch is whether the active filter accepted it.
+func (s *shadowFilter) evaluateOrderJSON(orderJSON []byte, activeMatch bool) {
+	result, err := s.filter.ValidateOrderJSON(orderJSON)
+	if err != nil {
+		log.WithError(err).Trace("could not evaluate shadow order filter")
+		return
+	}
+	s.record(activeMatch, result.Valid())
+}
+
+// evaluateOrder evaluates the shadow filter against the given order.
+// activeMatch is whether the active filter accepted it.
+func (s *shadowFilter) evaluateOrder(order *zeroex.SignedOrder, activeMatch bool) {
+	shadowMatch, err := s.filter.MatchOrder(order)
+	if err != nil {
+		log.WithError(err).Trace("could not evaluate shadow order filter")
+		return
+	}
+	s.record(activeMatch, shadowMatch)
+}
+
+func (s *shadowFilter) record(activeMatch bool, shadowMatch bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.NumEvaluated++
+	switch {
+	case activeMatch && shadowMatch:
+		s.stats.NumAcceptedByBoth++
+	case !activeMatch && shadowMatch:
+		s.stats.NumAcceptedOnlyByShadow++
+	case activeMatch && !shadowMatch:
+		s.stats.NumRejectedOnlyByShadow++
+	default:
+		s.stats.NumRejectedByBoth++
+	}
+}
+
+func (s *shadowFilter) getStats() *types.ShadowFilterStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	return &stats
+}
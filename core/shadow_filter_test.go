@@ -0,0 +1,74 @@
+// +build !js
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/ethereum"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newShadowFilterTestOrder(t *testing.T, feeRecipientAddress common.Address) *zeroex.SignedOrder {
+	order := &zeroex.Order{
+		ChainID:               big.NewInt(constants.TestChainID),
+		ExchangeAddress:       ethereum.GanacheAddresses.Exchange,
+		MakerAddress:          constants.GanacheAccount0,
+		MakerAssetData:        common.FromHex("0xf47261b0000000000000000000000000871dd7c2b4b25e1aa18728e9d5f2af4c4e431f5c"),
+		MakerAssetAmount:      math.MustParseBig256("1000"),
+		MakerFee:              math.MustParseBig256("0"),
+		TakerAssetData:        common.FromHex("0xf47261b00000000000000000000000000b1ba0af832d7c05fd64161e0db78e85978e8082"),
+		TakerAssetAmount:      math.MustParseBig256("2000"),
+		TakerFee:              math.MustParseBig256("0"),
+		FeeRecipientAddress:   feeRecipientAddress,
+		ExpirationTimeSeconds: math.MustParseBig256("1574532801"),
+		Salt:                  math.MustParseBig256("1548619145450"),
+	}
+	signedOrder, err := zeroex.SignTestOrder(order)
+	require.NoError(t, err)
+	return signedOrder
+}
+
+func TestShadowFilter(t *testing.T) {
+	allowedFeeRecipient := common.HexToAddress("0xa258b39954cef5cb142fd567a46cddb31a670124")
+	otherFeeRecipient := common.HexToAddress("0x00000000000000000000000000000000000000fe")
+	config := Config{
+		EthereumChainID:   constants.TestChainID,
+		ShadowOrderFilter: "{}",
+	}
+	shadowFilter, err := newShadowFilter(config, []common.Address{allowedFeeRecipient}, ethereum.GanacheAddresses)
+	require.NoError(t, err)
+	require.NotNil(t, shadowFilter)
+
+	allowedOrder := newShadowFilterTestOrder(t, allowedFeeRecipient)
+	otherOrder := newShadowFilterTestOrder(t, otherFeeRecipient)
+	shadowFilter.evaluateOrder(allowedOrder, true)
+	shadowFilter.evaluateOrder(allowedOrder, false)
+	shadowFilter.evaluateOrder(otherOrder, true)
+	shadowFilter.evaluateOrder(otherOrder, true)
+	shadowFilter.evaluateOrder(otherOrder, false)
+
+	stats := shadowFilter.getStats()
+	assert.Equal(t, shadowFilter.filter.Topic(), stats.PubSubTopic)
+	assert.False(t, stats.Since.IsZero())
+	assert.Equal(t, uint64(5), stats.NumEvaluated)
+	assert.Equal(t, uint64(1), stats.NumAcceptedByBoth)
+	assert.Equal(t, uint64(1), stats.NumAcceptedOnlyByShadow)
+	assert.Equal(t, uint64(2), stats.NumRejectedOnlyByShadow)
+	assert.Equal(t, uint64(1), stats.NumRejectedByBoth)
+}
+
+func TestShadowFilterDisabled(t *testing.T) {
+	shadowFilter, err := newShadowFilter(Config{EthereumChainID: constants.TestChainID}, nil, ethereum.GanacheAddresses)
+	require.NoError(t, err)
+	assert.Nil(t, shadowFilter)
+
+	_, err = newShadowFilter(Config{EthereumChainID: constants.TestChainID, ShadowOrderFilter: "{"}, nil, ethereum.GanacheAddresses)
+	assert.Error(t, err)
+}
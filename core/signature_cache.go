@@ -0,0 +1,66 @@
+package core
+
+import (
+	"sync/atomic"
+
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// signatureCache caches the results of verifying order signatures, keyed by
+// order hash and signature. Since the same order is usually received from
+// several peers, this avoids repeating expensive ECDSA recovery operations.
+type signatureCache struct {
+	// cache is nil if caching is disabled.
+	cache  *lru.Cache
+	hits   uint64
+	misses uint64
+}
+
+// newSignatureCache returns a signatureCache which holds up to size results.
+// If size is 0, results are not cached.
+func newSignatureCache(size int) (*signatureCache, error) {
+	if size == 0 {
+		return &signatureCache{}, nil
+	}
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &signatureCache{cache: cache}, nil
+}
+
+// isValidSignature returns false if the signature of the given order is
+// invalid according to zeroex.ValidateSignatureOffline. Signatures which can
+// only be verified on-chain (e.g. Wallet signatures) are considered valid.
+func (c *signatureCache) isValidSignature(orderHash common.Hash, order *zeroex.SignedOrder) bool {
+	// Note that the order hash includes the maker address, so the order hash and
+	// signature are enough to determine whether the signature is valid.
+	key := string(orderHash.Bytes()) + string(order.Signature)
+	if c.cache != nil {
+		if isValid, found := c.cache.Get(key); found {
+			atomic.AddUint64(&c.hits, 1)
+			return isValid.(bool)
+		}
+	}
+	atomic.AddUint64(&c.misses, 1)
+
+	status, _ := zeroex.ValidateSignatureOffline(orderHash, order.MakerAddress, order.Signature)
+	isValid := status != zeroex.SignatureInvalid
+	if c.cache != nil {
+		c.cache.Add(key, isValid)
+	}
+	return isValid
+}
+
+// hitRate returns the ratio of cache hits to total lookups, or 0 if there
+// have not been any lookups yet.
+func (c *signatureCache) hitRate() float64 {
+	hits := atomic.LoadUint64(&c.hits)
+	misses := atomic.LoadUint64(&c.misses)
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
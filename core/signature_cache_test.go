@@ -0,0 +1,70 @@
+// +build !js
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatureCache(t *testing.T) {
+	signedOrder, err := zeroex.SignTestOrder(&zeroex.Order{
+		ChainID:               big.NewInt(constants.TestChainID),
+		MakerAddress:          constants.GanacheAccount0,
+		TakerAddress:          constants.NullAddress,
+		SenderAddress:         constants.NullAddress,
+		FeeRecipientAddress:   constants.NullAddress,
+		MakerAssetData:        constants.NullBytes,
+		MakerFeeAssetData:     constants.NullBytes,
+		TakerAssetData:        constants.NullBytes,
+		TakerFeeAssetData:     constants.NullBytes,
+		Salt:                  big.NewInt(1),
+		MakerFee:              big.NewInt(0),
+		TakerFee:              big.NewInt(0),
+		MakerAssetAmount:      big.NewInt(1),
+		TakerAssetAmount:      big.NewInt(1),
+		ExpirationTimeSeconds: big.NewInt(1),
+	})
+	require.NoError(t, err)
+	orderHash, err := signedOrder.ComputeOrderHash()
+	require.NoError(t, err)
+
+	cache, err := newSignatureCache(10)
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), cache.hitRate())
+
+	assert.True(t, cache.isValidSignature(orderHash, signedOrder))
+	assert.Equal(t, float64(0), cache.hitRate())
+	assert.True(t, cache.isValidSignature(orderHash, signedOrder))
+	assert.Equal(t, 0.5, cache.hitRate())
+
+	// A valid signature from someone other than the maker is invalid.
+	forgedOrder := *signedOrder
+	forgedOrder.MakerAddress = constants.GanacheAccount1
+	forgedOrder.ResetHash()
+	forgedOrderHash, err := forgedOrder.ComputeOrderHash()
+	require.NoError(t, err)
+	assert.False(t, cache.isValidSignature(forgedOrderHash, &forgedOrder))
+	assert.False(t, cache.isValidSignature(forgedOrderHash, &forgedOrder))
+
+	// Signatures which can only be verified on-chain are considered valid.
+	walletOrder := *signedOrder
+	walletOrder.Signature = []byte{byte(zeroex.WalletSignature)}
+	assert.True(t, cache.isValidSignature(orderHash, &walletOrder))
+}
+
+func TestSignatureCacheDisabled(t *testing.T) {
+	cache, err := newSignatureCache(0)
+	require.NoError(t, err)
+	signedOrder := &zeroex.SignedOrder{Signature: []byte{byte(zeroex.WalletSignature)}}
+	orderHash := common.HexToHash("0x1")
+	assert.True(t, cache.isValidSignature(orderHash, signedOrder))
+	assert.True(t, cache.isValidSignature(orderHash, signedOrder))
+	assert.Equal(t, float64(0), cache.hitRate())
+}
@@ -0,0 +1,149 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// StartupPhase is a phase of App.Start.
+type StartupPhase string
+
+// StartupPhase values, in the order in which they occur.
+const (
+	// StartupPhaseConnectingToEthereum is when the chain ID of the Ethereum
+	// RPC endpoint is checked.
+	StartupPhaseConnectingToEthereum StartupPhase = "CONNECTING_TO_ETHEREUM"
+	// StartupPhaseSyncingBlocks is when Mesh catches up with the blocks that
+	// were mined while it was offline.
+	StartupPhaseSyncingBlocks StartupPhase = "SYNCING_BLOCKS"
+	// StartupPhaseRevalidatingOrders is when all stored orders are
+	// re-validated because Mesh was offline for too long. It is skipped if no
+	// re-validation is needed or if it happens in the background.
+	StartupPhaseRevalidatingOrders StartupPhase = "REVALIDATING_ORDERS"
+	// StartupPhaseStartingP2P is when the p2p node is started.
+	StartupPhaseStartingP2P StartupPhase = "STARTING_P2P"
+	// StartupPhaseDiscoveringPeers is when Mesh is looking for its first peer.
+	StartupPhaseDiscoveringPeers StartupPhase = "DISCOVERING_PEERS"
+	// StartupPhaseSyncingOrders is when Mesh is getting the orders of its
+	// peers for the first time.
+	StartupPhaseSyncingOrders StartupPhase = "SYNCING_ORDERS"
+	// StartupPhaseReady is when the initial order sync has finished.
+	StartupPhaseReady StartupPhase = "READY"
+)
+
+// startupPhasePercents is the overall progress at the beginning of each
+// phase. The phases before StartupPhaseConnectingToEthereum (e.g. loading the
+// WebAssembly in the browser and initializing the database and private key
+// in New) make up the first 20 percent.
+var startupPhasePercents = map[StartupPhase]int{
+	StartupPhaseConnectingToEthereum: 20,
+	StartupPhaseSyncingBlocks:        25,
+	StartupPhaseRevalidatingOrders:   40,
+	StartupPhaseStartingP2P:          70,
+	StartupPhaseDiscoveringPeers:     75,
+	StartupPhaseSyncingOrders:        85,
+	StartupPhaseReady:                100,
+}
+
+const (
+	// startupProgressCheckInterval is how often the progress of the startup
+	// re-validation and the number of peers are checked.
+	startupProgressCheckInterval = 250 * time.Millisecond
+)
+
+// StartupProgress is sent to the subscribers of SubscribeToStartupProgress
+// as the App starts.
+type StartupProgress struct {
+	Phase StartupPhase `json:"phase"`
+	// Percent is an estimate of the overall startup progress between 0 and
+	// 100.
+	Percent int `json:"percent"`
+}
+
+// SubscribeToStartupProgress subscribes sink to the progress of App.Start.
+// Progress is reported from the beginning of App.Start until the initial
+// order sync has finished (i.e. until after App.Start has returned control to
+// the caller in the browser), so it should be called before App.Start.
+func (app *App) SubscribeToStartupProgress(sink chan<- StartupProgress) event.Subscription {
+	return app.startupProgressFeed.Subscribe(sink)
+}
+
+// reportStartupPhase reports that the given phase has begun.
+func (app *App) reportStartupPhase(phase StartupPhase) {
+	app.startupProgressFeed.Send(StartupProgress{
+		Phase:   phase,
+		Percent: startupPhasePercents[phase],
+	})
+}
+
+// reportRevalidationProgress periodically reports the progress of the startup
+// re-validation until ctx is canceled. See revalidationPercent for how the
+// percentage is computed.
+func (app *App) reportRevalidationProgress(ctx context.Context) {
+	lastPercent := startupPhasePercents[StartupPhaseRevalidatingOrders]
+	ticker := time.NewTicker(startupProgressCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		progress := app.orderWatcher.RevalidationProgress()
+		if progress.NumOrdersTotal == 0 {
+			continue
+		}
+		percent := revalidationPercent(progress.NumOrdersRevalidated, progress.NumOrdersTotal)
+		if percent == lastPercent {
+			continue
+		}
+		lastPercent = percent
+		app.startupProgressFeed.Send(StartupProgress{
+			Phase:   StartupPhaseRevalidatingOrders,
+			Percent: percent,
+		})
+	}
+}
+
+// revalidationPercent returns the overall startup progress after
+// numOrdersRevalidated of numOrdersTotal orders have been re-validated. It is
+// interpolated between the beginning of StartupPhaseRevalidatingOrders and the
+// beginning of the next phase, which is only reached once the next phase has
+// actually begun.
+func revalidationPercent(numOrdersRevalidated int, numOrdersTotal int) int {
+	startPercent := startupPhasePercents[StartupPhaseRevalidatingOrders]
+	endPercent := startupPhasePercents[StartupPhaseStartingP2P]
+	if numOrdersTotal <= 0 {
+		return startPercent
+	}
+	percent := startPercent + (endPercent-startPercent)*numOrdersRevalidated/numOrdersTotal
+	if percent >= endPercent {
+		percent = endPercent - 1
+	}
+	return percent
+}
+
+// reportPeerDiscoveryAndSyncProgress reports the phases after the p2p node
+// has been started. It returns once the initial order sync has finished or ctx
+// is canceled.
+func (app *App) reportPeerDiscoveryAndSyncProgress(ctx context.Context) {
+	app.reportStartupPhase(StartupPhaseDiscoveringPeers)
+	ticker := time.NewTicker(startupProgressCheckInterval)
+	defer ticker.Stop()
+	for app.node.GetNumPeers() == 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+	app.reportStartupPhase(StartupPhaseSyncingOrders)
+	select {
+	case <-ctx.Done():
+		return
+	case <-app.ordersyncService.InitialSyncDone():
+	}
+	app.reportStartupPhase(StartupPhaseReady)
+}
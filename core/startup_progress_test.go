@@ -0,0 +1,75 @@
+// +build !js
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderedStartupPhases are the startup phases in the order in which they occur.
+var orderedStartupPhases = []StartupPhase{
+	StartupPhaseConnectingToEthereum,
+	StartupPhaseSyncingBlocks,
+	StartupPhaseRevalidatingOrders,
+	StartupPhaseStartingP2P,
+	StartupPhaseDiscoveringPeers,
+	StartupPhaseSyncingOrders,
+	StartupPhaseReady,
+}
+
+func TestStartupPhasePercents(t *testing.T) {
+	require.Len(t, startupPhasePercents, len(orderedStartupPhases))
+	lastPercent := 0
+	for _, phase := range orderedStartupPhases {
+		percent, found := startupPhasePercents[phase]
+		require.True(t, found, "no percentage for phase %s", phase)
+		assert.True(t, percent > lastPercent, "phase %s does not come after the previous phase", phase)
+		lastPercent = percent
+	}
+	assert.Equal(t, 100, lastPercent)
+}
+
+func TestRevalidationPercent(t *testing.T) {
+	startPercent := startupPhasePercents[StartupPhaseRevalidatingOrders]
+	endPercent := startupPhasePercents[StartupPhaseStartingP2P]
+	testCases := []struct {
+		numOrdersRevalidated int
+		numOrdersTotal       int
+		expectedPercent      int
+	}{
+		{0, 0, startPercent},
+		{0, 100, startPercent},
+		{50, 100, startPercent + (endPercent-startPercent)/2},
+		{1, 3, startPercent + (endPercent-startPercent)/3},
+		// The beginning of the next phase is only reported once it has begun.
+		{100, 100, endPercent - 1},
+		{150, 100, endPercent - 1},
+	}
+	for _, testCase := range testCases {
+		percent := revalidationPercent(testCase.numOrdersRevalidated, testCase.numOrdersTotal)
+		assert.Equal(t, testCase.expectedPercent, percent, "%d of %d orders", testCase.numOrdersRevalidated, testCase.numOrdersTotal)
+	}
+}
+
+func TestReportStartupPhase(t *testing.T) {
+	app := &App{}
+	progressChan := make(chan StartupProgress, len(orderedStartupPhases))
+	subscription := app.SubscribeToStartupProgress(progressChan)
+	defer subscription.Unsubscribe()
+
+	for _, phase := range orderedStartupPhases {
+		app.reportStartupPhase(phase)
+	}
+	for _, phase := range orderedStartupPhases {
+		select {
+		case progress := <-progressChan:
+			assert.Equal(t, StartupProgress{Phase: phase, Percent: startupPhasePercents[phase]}, progress)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for startup phase %s", phase)
+		}
+	}
+}
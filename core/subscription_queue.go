@@ -0,0 +1,183 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/0xProject/0x-mesh/common/types"
+)
+
+const (
+	// SubscriptionOverflowDropOldest means that the oldest notifications are
+	// dropped when the queue of a subscription is full. Subscribers are
+	// notified of the gap.
+	SubscriptionOverflowDropOldest = "drop-oldest"
+	// SubscriptionOverflowDisconnect means that a subscription is terminated
+	// when its queue is full.
+	SubscriptionOverflowDisconnect = "disconnect"
+)
+
+// defaultSubscriptionQueueSize is the size of subscription queues if
+// SubscriptionQueueSize is not set.
+const defaultSubscriptionQueueSize = 1000
+
+// parseSubscriptionOverflowPolicy returns an error if policy is not a known
+// overflow policy. An empty policy defaults to SubscriptionOverflowDropOldest.
+func parseSubscriptionOverflowPolicy(policy string) error {
+	switch policy {
+	case "", SubscriptionOverflowDropOldest, SubscriptionOverflowDisconnect:
+		return nil
+	default:
+		return fmt.Errorf("SUBSCRIPTION_OVERFLOW_POLICY must be %q or %q (got %q)", SubscriptionOverflowDropOldest, SubscriptionOverflowDisconnect, policy)
+	}
+}
+
+// SubscriptionQueue is a bounded queue of notifications which have not yet
+// been sent to a subscriber. It decouples slow subscribers from the rest of
+// Mesh. When the queue is full, the configured overflow policy is applied.
+type SubscriptionQueue struct {
+	app        *App
+	id         uint64
+	topic      string
+	maxSize    int
+	policy     string
+	mu         sync.Mutex
+	items      []interface{}
+	lastDrop   interface{}
+	numDropped int
+	ready      chan struct{}
+	overflowed chan struct{}
+	closed     bool
+}
+
+// NewSubscriptionQueue creates a queue for a subscription to the given topic
+// with the configured size and overflow policy. Its depth is included in the
+// stats until it is closed.
+func (app *App) NewSubscriptionQueue(topic string) *SubscriptionQueue {
+	app.subscriptionQueuesMu.Lock()
+	defer app.subscriptionQueuesMu.Unlock()
+	app.nextSubscriptionQueueID++
+	maxSize := app.config.SubscriptionQueueSize
+	if maxSize == 0 {
+		maxSize = defaultSubscriptionQueueSize
+	}
+	policy := app.config.SubscriptionOverflowPolicy
+	if policy == "" {
+		policy = SubscriptionOverflowDropOldest
+	}
+	queue := &SubscriptionQueue{
+		app:        app,
+		id:         app.nextSubscriptionQueueID,
+		topic:      topic,
+		maxSize:    maxSize,
+		policy:     policy,
+		ready:      make(chan struct{}, 1),
+		overflowed: make(chan struct{}),
+	}
+	app.subscriptionQueues[queue.id] = queue
+	return queue
+}
+
+// Push appends a notification to the queue. If the queue is full, either the
+// oldest notification is dropped or the queue is marked as overflowed,
+// depending on the overflow policy.
+func (q *SubscriptionQueue) Push(item interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	if len(q.items) >= q.maxSize {
+		if q.policy == SubscriptionOverflowDisconnect {
+			q.closed = true
+			q.items = nil
+			close(q.overflowed)
+			return
+		}
+		q.lastDrop = q.items[0]
+		q.items[0] = nil
+		q.items = q.items[1:]
+		q.numDropped++
+	}
+	q.items = append(q.items, item)
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Pop removes the oldest notification from the queue. If notifications were
+// dropped since the last call, lastDropped is the most recent of them, so
+// that the caller can send a gap marker first. ok is false if the queue is
+// empty.
+func (q *SubscriptionQueue) Pop() (item interface{}, lastDropped interface{}, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 {
+		return nil, nil, false
+	}
+	item = q.items[0]
+	q.items[0] = nil
+	q.items = q.items[1:]
+	lastDropped = q.lastDrop
+	q.lastDrop = nil
+	return item, lastDropped, true
+}
+
+// Ready returns a channel which receives a value whenever notifications were
+// pushed to the queue.
+func (q *SubscriptionQueue) Ready() <-chan struct{} {
+	return q.ready
+}
+
+// Overflowed returns a channel which is closed when the queue overflowed and
+// the overflow policy is SubscriptionOverflowDisconnect.
+func (q *SubscriptionQueue) Overflowed() <-chan struct{} {
+	return q.overflowed
+}
+
+// Close removes the queue from the stats. Subsequent calls to Push are
+// ignored.
+func (q *SubscriptionQueue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.items = nil
+	q.mu.Unlock()
+
+	q.app.subscriptionQueuesMu.Lock()
+	defer q.app.subscriptionQueuesMu.Unlock()
+	delete(q.app.subscriptionQueues, q.id)
+}
+
+func (q *SubscriptionQueue) stats() types.SubscriptionStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return types.SubscriptionStats{
+		ID:         q.id,
+		Topic:      q.topic,
+		QueueDepth: len(q.items),
+		QueueSize:  q.maxSize,
+		NumDropped: q.numDropped,
+	}
+}
+
+// getSubscriptionStats returns the stats of all open subscription queues,
+// ordered by ID.
+func (app *App) getSubscriptionStats() []types.SubscriptionStats {
+	app.subscriptionQueuesMu.Lock()
+	queues := make([]*SubscriptionQueue, 0, len(app.subscriptionQueues))
+	for _, queue := range app.subscriptionQueues {
+		queues = append(queues, queue)
+	}
+	app.subscriptionQueuesMu.Unlock()
+
+	stats := make([]types.SubscriptionStats, len(queues))
+	for i, queue := range queues {
+		stats[i] = queue.stats()
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].ID < stats[j].ID
+	})
+	return stats
+}
@@ -0,0 +1,90 @@
+// +build !js
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAppWithSubscriptionQueues(queueSize int, policy string) *App {
+	return &App{
+		config: Config{
+			SubscriptionQueueSize:      queueSize,
+			SubscriptionOverflowPolicy: policy,
+		},
+		subscriptionQueues: map[uint64]*SubscriptionQueue{},
+	}
+}
+
+func TestSubscriptionQueueDropOldest(t *testing.T) {
+	app := newTestAppWithSubscriptionQueues(2, SubscriptionOverflowDropOldest)
+	queue := app.NewSubscriptionQueue("orders")
+
+	queue.Push(1)
+	queue.Push(2)
+	queue.Push(3)
+	queue.Push(4)
+	stats := app.getSubscriptionStats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, 2, stats[0].QueueDepth)
+	assert.Equal(t, 2, stats[0].QueueSize)
+	assert.Equal(t, 2, stats[0].NumDropped)
+
+	// The first item after the gap is returned along with the last dropped
+	// item.
+	item, lastDropped, ok := queue.Pop()
+	require.True(t, ok)
+	assert.Equal(t, 3, item)
+	assert.Equal(t, 2, lastDropped)
+	item, lastDropped, ok = queue.Pop()
+	require.True(t, ok)
+	assert.Equal(t, 4, item)
+	assert.Nil(t, lastDropped)
+	_, _, ok = queue.Pop()
+	assert.False(t, ok)
+
+	select {
+	case <-queue.Overflowed():
+		t.Error("drop-oldest queue should not overflow")
+	default:
+	}
+
+	queue.Close()
+	assert.Empty(t, app.getSubscriptionStats())
+}
+
+func TestSubscriptionQueueDisconnect(t *testing.T) {
+	app := newTestAppWithSubscriptionQueues(2, SubscriptionOverflowDisconnect)
+	queue := app.NewSubscriptionQueue("orders")
+	defer queue.Close()
+
+	queue.Push(1)
+	queue.Push(2)
+	select {
+	case <-queue.Overflowed():
+		t.Fatal("queue overflowed before it was full")
+	default:
+	}
+	queue.Push(3)
+	select {
+	case <-queue.Overflowed():
+	default:
+		t.Fatal("queue did not overflow")
+	}
+
+	// Items pushed after the overflow are ignored.
+	queue.Push(4)
+	_, _, ok := queue.Pop()
+	assert.False(t, ok)
+}
+
+func TestSubscriptionQueueDefaults(t *testing.T) {
+	app := newTestAppWithSubscriptionQueues(0, "")
+	queue := app.NewSubscriptionQueue("orders")
+	defer queue.Close()
+	assert.Equal(t, defaultSubscriptionQueueSize, queue.maxSize)
+	assert.Equal(t, SubscriptionOverflowDropOldest, queue.policy)
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// userAgentFormat is the format of the user agent Mesh advertises to its
+// peers via the libp2p identify protocol. It contains the Mesh version, the
+// chain ID and the hash of the topic orders are shared on, so that peers
+// running a different version or order filter can be told apart.
+const userAgentFormat = "0x-mesh/%s (chainID=%d; topicHash=%s)"
+
+// relayOnlyUserAgentSuffix replaces the closing parenthesis of the user agent
+// of nodes running in relay-only mode, so that peers know that the orders
+// they share have not been validated on-chain.
+const relayOnlyUserAgentSuffix = "; relayOnly)"
+
+// topicHash returns the Keccak256 hash of the given pubsub topic. Topics
+// contain the encoded order filter, so they are too long to be advertised
+// directly.
+func topicHash(topic string) string {
+	return crypto.Keccak256Hash([]byte(topic)).Hex()
+}
+
+// newUserAgent returns the user agent for a node running the given version
+// on the given chain and sharing orders on the given topic. relayOnly is true
+// if the node runs in relay-only mode.
+func newUserAgent(version string, chainID int, topic string, relayOnly bool) string {
+	userAgent := fmt.Sprintf(userAgentFormat, version, chainID, topicHash(topic))
+	if relayOnly {
+		userAgent = strings.TrimSuffix(userAgent, ")") + relayOnlyUserAgentSuffix
+	}
+	return userAgent
+}
+
+// parsedUserAgent contains the values advertised by a peer in its user agent.
+type parsedUserAgent struct {
+	version   string
+	chainID   int
+	topicHash string
+	relayOnly bool
+}
+
+// parseUserAgent parses a user agent returned by newUserAgent. It returns
+// false if the user agent has a different format (e.g. because the peer is
+// running an older version of Mesh or a different libp2p program).
+func parseUserAgent(userAgent string) (parsedUserAgent, bool) {
+	var parsed parsedUserAgent
+	if strings.HasSuffix(userAgent, relayOnlyUserAgentSuffix) {
+		parsed.relayOnly = true
+		userAgent = strings.TrimSuffix(userAgent, relayOnlyUserAgentSuffix) + ")"
+	}
+	if _, err := fmt.Sscanf(userAgent, "0x-mesh/%s (chainID=%d; topicHash=%66s)", &parsed.version, &parsed.chainID, &parsed.topicHash); err != nil {
+		return parsedUserAgent{}, false
+	}
+	// Sscanf ignores trailing input, so make sure the whole user agent
+	// matches the format.
+	if userAgent != fmt.Sprintf(userAgentFormat, parsed.version, parsed.chainID, parsed.topicHash) {
+		return parsedUserAgent{}, false
+	}
+	return parsed, true
+}
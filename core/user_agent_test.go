@@ -0,0 +1,46 @@
+// +build !js
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	topic := "/0x-orders/version/3/chain/1337/schema/e30="
+	userAgent := newUserAgent("9.4.0", 1337, topic, false)
+	parsed, ok := parseUserAgent(userAgent)
+	require.True(t, ok)
+	assert.Equal(t, parsedUserAgent{
+		version:   "9.4.0",
+		chainID:   1337,
+		topicHash: topicHash(topic),
+	}, parsed)
+
+	relayOnlyUserAgent := newUserAgent("9.4.0", 1337, topic, true)
+	assert.Equal(t, "0x-mesh/9.4.0 (chainID=1337; topicHash="+topicHash(topic)+"; relayOnly)", relayOnlyUserAgent)
+	parsed, ok = parseUserAgent(relayOnlyUserAgent)
+	require.True(t, ok)
+	assert.Equal(t, parsedUserAgent{
+		version:   "9.4.0",
+		chainID:   1337,
+		topicHash: topicHash(topic),
+		relayOnly: true,
+	}, parsed)
+
+	for _, invalidUserAgent := range []string{
+		"",
+		"go-libp2p/0.5.1",
+		"0x-mesh/9.4.0",
+		"0x-mesh/9.4.0 (chainID=1337; topicHash=0x1234)",
+		userAgent + " extra",
+		"0x-mesh/9.4.0 (chainID=1337; relayOnly)",
+		relayOnlyUserAgent + " extra",
+	} {
+		_, ok := parseUserAgent(invalidUserAgent)
+		assert.False(t, ok, invalidUserAgent)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"sync"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 // Note about the implementation:
@@ -36,9 +38,27 @@ type DB struct {
 	colLock         sync.Mutex
 }
 
+// OpenInMemory creates a new database which is only stored in memory. All data
+// is lost when the database is closed.
+func OpenInMemory() (*DB, error) {
+	ldb, err := leveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DB{
+		ldb: ldb,
+	}, nil
+}
+
 // Close closes the database. It is not safe to call Close if there are any
 // other methods that have not yet returned. It is safe to call Close multiple
 // times.
 func (db *DB) Close() error {
 	return db.ldb.Close()
 }
+
+// Compact compacts the underlying storage of the entire database, discarding
+// deleted and overwritten data. It may take a long time for large databases.
+func (db *DB) Compact() error {
+	return db.ldb.CompactRange(util.Range{})
+}
@@ -29,3 +29,30 @@ func TestOpen(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, db.Close())
 }
+
+func TestCompact(t *testing.T) {
+	t.Parallel()
+	db := newTestDB(t)
+	defer db.Close()
+	col, err := db.NewCollection("people", &testModel{})
+	require.NoError(t, err)
+	kept := &testModel{
+		Name: "foo",
+		Age:  42,
+	}
+	deleted := &testModel{
+		Name: "bar",
+		Age:  43,
+	}
+	require.NoError(t, col.Insert(kept))
+	require.NoError(t, col.Insert(deleted))
+	require.NoError(t, col.Delete(deleted.ID()))
+
+	require.NoError(t, db.Compact())
+
+	actual := &testModel{}
+	require.NoError(t, col.FindByID(kept.ID(), actual))
+	require.Equal(t, kept, actual)
+	err = col.FindByID(deleted.ID(), &testModel{})
+	require.IsType(t, NotFoundError{}, err)
+}
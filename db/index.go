@@ -74,3 +74,11 @@ func (index *Index) primaryKeyFromIndexKey(key []byte) []byte {
 	split := strings.Split(pkAndVal, ":")
 	return index.colInfo.primaryKeyForIDWithoutEscape([]byte(split[2]))
 }
+
+// valueFromIndexKey extracts and returns the unescaped index value from the
+// given index key.
+func (index *Index) valueFromIndexKey(key []byte) ([]byte, error) {
+	pkAndVal := strings.TrimPrefix(string(key), string(index.prefix()))
+	split := strings.Split(pkAndVal, ":")
+	return unescape([]byte(split[1]))
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/util"
@@ -97,3 +98,173 @@ func (db *DB) checkIndexIntegrity(snapshot *Snapshot, col *Collection, index *In
 	}
 	return nil
 }
+
+// InvalidModel is a model found by Collection.Verify which either could not
+// be unmarshaled or was rejected by the check function.
+type InvalidModel struct {
+	// Key is the primary key of the model.
+	Key string `json:"key"`
+	// Reason describes why the model is invalid.
+	Reason string `json:"reason"`
+}
+
+// VerifyResult describes the problems found by Collection.Verify.
+type VerifyResult struct {
+	// Collection is the name of the collection.
+	Collection string `json:"collection"`
+	// InvalidModels are the models which could not be unmarshaled or were
+	// rejected by the check function.
+	InvalidModels []InvalidModel `json:"invalidModels"`
+	// MissingIndexKeys are index keys which should exist for one of the valid
+	// models but do not.
+	MissingIndexKeys []string `json:"missingIndexKeys"`
+	// OrphanedIndexKeys are index keys which do not belong to any valid model,
+	// e.g. because the model was deleted or its indexed values changed.
+	OrphanedIndexKeys []string `json:"orphanedIndexKeys"`
+	// StoredCount is the number of models according to the stored count.
+	StoredCount int `json:"storedCount"`
+	// ActualCount is the number of valid models.
+	ActualCount int `json:"actualCount"`
+	// Repaired is true if the problems were fixed.
+	Repaired bool `json:"repaired"`
+}
+
+// OK returns true if no problems were found.
+func (r *VerifyResult) OK() bool {
+	return len(r.InvalidModels) == 0 &&
+		len(r.MissingIndexKeys) == 0 &&
+		len(r.OrphanedIndexKeys) == 0 &&
+		r.StoredCount == r.ActualCount
+}
+
+// Verify checks every model in the collection and the consistency of its
+// indexes and count. check is called for each model which could be
+// unmarshaled and may be nil. If it returns an error, the model is considered
+// invalid. Unlike CheckIntegrity, Verify does not stop at the first problem.
+// If repair is true, invalid models are deleted, the indexes are rebuilt from
+// the remaining models and the count is corrected, all in a single atomic
+// write. No other writes can be made to the database while Verify is running.
+func (c *Collection) Verify(check func(Model) error, repair bool) (*VerifyResult, error) {
+	c.info.db.globalWriteLock.Lock()
+	defer c.info.db.globalWriteLock.Unlock()
+	c.info.indexMut.RLock()
+	defer c.info.indexMut.RUnlock()
+
+	result := &VerifyResult{
+		Collection:        c.Name(),
+		InvalidModels:     []InvalidModel{},
+		MissingIndexKeys:  []string{},
+		OrphanedIndexKeys: []string{},
+	}
+	storedCount, err := count(c.info, c.ldb)
+	if err != nil {
+		return nil, err
+	}
+	result.StoredCount = storedCount
+
+	// expectedIndexKeys are the index keys of all valid models.
+	expectedIndexKeys := map[string]struct{}{}
+	slice := util.BytesPrefix([]byte(fmt.Sprintf("%s:", c.info.prefix())))
+	iter := c.ldb.NewIterator(slice, nil)
+	defer iter.Release()
+	for iter.Next() {
+		key := string(iter.Key())
+		model, err := c.unmarshalAndCheckModel(iter.Value(), check)
+		if err != nil {
+			result.InvalidModels = append(result.InvalidModels, InvalidModel{Key: key, Reason: err.Error()})
+			continue
+		}
+		if pk := string(c.info.primaryKeyForModel(model)); pk != key {
+			result.InvalidModels = append(result.InvalidModels, InvalidModel{Key: key, Reason: fmt.Sprintf("model is stored under the wrong primary key (expected %s)", pk)})
+			continue
+		}
+		indexKeys, err := c.indexKeysForModel(model)
+		if err != nil {
+			result.InvalidModels = append(result.InvalidModels, InvalidModel{Key: key, Reason: err.Error()})
+			continue
+		}
+		for _, indexKey := range indexKeys {
+			expectedIndexKeys[string(indexKey)] = struct{}{}
+		}
+		result.ActualCount++
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+
+	existingIndexKeys := map[string]struct{}{}
+	for _, index := range c.info.indexes {
+		slice := util.BytesPrefix([]byte(fmt.Sprintf("%s:", index.prefix())))
+		iter := c.ldb.NewIterator(slice, nil)
+		for iter.Next() {
+			key := string(iter.Key())
+			existingIndexKeys[key] = struct{}{}
+			if _, found := expectedIndexKeys[key]; !found {
+				result.OrphanedIndexKeys = append(result.OrphanedIndexKeys, key)
+			}
+		}
+		iter.Release()
+		if err := iter.Error(); err != nil {
+			return nil, err
+		}
+	}
+	for key := range expectedIndexKeys {
+		if _, found := existingIndexKeys[key]; !found {
+			result.MissingIndexKeys = append(result.MissingIndexKeys, key)
+		}
+	}
+	sort.Strings(result.MissingIndexKeys)
+
+	if !repair || result.OK() {
+		return result, nil
+	}
+	batch := &leveldb.Batch{}
+	for _, invalidModel := range result.InvalidModels {
+		batch.Delete([]byte(invalidModel.Key))
+	}
+	for _, key := range result.OrphanedIndexKeys {
+		batch.Delete([]byte(key))
+	}
+	for _, key := range result.MissingIndexKeys {
+		batch.Put([]byte(key), nil)
+	}
+	if result.ActualCount == 0 {
+		batch.Delete(c.info.countKey())
+	} else {
+		batch.Put(c.info.countKey(), encodeInt(result.ActualCount))
+	}
+	if err := c.ldb.Write(batch, nil); err != nil {
+		return nil, err
+	}
+	result.Repaired = true
+	return result, nil
+}
+
+func (c *Collection) unmarshalAndCheckModel(data []byte, check func(Model) error) (Model, error) {
+	modelVal := reflect.New(c.info.modelType)
+	if err := json.Unmarshal(data, modelVal.Interface()); err != nil {
+		return nil, fmt.Errorf("could not unmarshal model data: %s", err.Error())
+	}
+	model := modelVal.Elem().Interface().(Model)
+	if check != nil {
+		if err := check(model); err != nil {
+			return nil, err
+		}
+	}
+	return model, nil
+}
+
+// indexKeysForModel returns the keys of all indexes for the given model. Index
+// getters may panic for models with unexpected data, in which case an error is
+// returned instead.
+func (c *Collection) indexKeysForModel(model Model) (indexKeys [][]byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("could not compute index keys: %v", r)
+		}
+	}()
+	for _, index := range c.info.indexes {
+		indexKeys = append(indexKeys, index.keysForModel(model)...)
+	}
+	return indexKeys, nil
+}
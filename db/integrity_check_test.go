@@ -1,10 +1,12 @@
 package db
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -73,3 +75,53 @@ func setUpIntegrityCheckTest(t *testing.T) (*DB, *Collection, []*testModel, *Ind
 
 	return db, col, models, ageIndex
 }
+
+func TestVerifyAndRepair(t *testing.T) {
+	t.Parallel()
+	db, col, models, ageIndex := setUpIntegrityCheckTest(t)
+	defer db.Close()
+
+	// Manually break integrity in several ways at once.
+	require.NoError(t, db.ldb.Put(col.info.primaryKeyForModel(models[0]), []byte("invalid data"), nil))
+	require.NoError(t, db.ldb.Delete(ageIndex.keysForModel(models[1])[0], nil))
+	staleIndexKey := fmt.Sprintf("%s:99:%s", ageIndex.prefix(), models[2].Name)
+	require.NoError(t, db.ldb.Put([]byte(staleIndexKey), nil, nil))
+	rejectPerson3 := func(m Model) error {
+		if m.(*testModel).Name == models[3].Name {
+			return errors.New("rejected")
+		}
+		return nil
+	}
+
+	result, err := col.Verify(rejectPerson3, false)
+	require.NoError(t, err)
+	assert.False(t, result.OK())
+	assert.False(t, result.Repaired)
+	require.Len(t, result.InvalidModels, 2)
+	assert.Equal(t, string(col.info.primaryKeyForModel(models[0])), result.InvalidModels[0].Key)
+	assert.Equal(t, InvalidModel{Key: string(col.info.primaryKeyForModel(models[3])), Reason: "rejected"}, result.InvalidModels[1])
+	assert.Equal(t, []string{string(ageIndex.keysForModel(models[1])[0])}, result.MissingIndexKeys)
+	expectedOrphanedIndexKeys := []string{
+		string(ageIndex.keysForModel(models[0])[0]),
+		string(ageIndex.keysForModel(models[3])[0]),
+		staleIndexKey,
+	}
+	assert.Equal(t, expectedOrphanedIndexKeys, result.OrphanedIndexKeys)
+	assert.Equal(t, 5, result.StoredCount)
+	assert.Equal(t, 3, result.ActualCount)
+
+	// Verifying without repairing must not change anything.
+	require.Error(t, db.CheckIntegrity())
+
+	result, err = col.Verify(rejectPerson3, true)
+	require.NoError(t, err)
+	assert.True(t, result.Repaired)
+
+	result, err = col.Verify(rejectPerson3, false)
+	require.NoError(t, err)
+	assert.True(t, result.OK())
+	require.NoError(t, db.CheckIntegrity())
+	actualCount, err := col.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 3, actualCount)
+}
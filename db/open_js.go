@@ -9,7 +9,6 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/storage"
 )
 
 const (
@@ -36,13 +35,7 @@ func Open(path string) (*DB, error) {
 
 func openInMemoryDB() (*DB, error) {
 	log.Warn("BrowserFS not detected. Using in-memory databse.")
-	ldb, err := leveldb.Open(storage.NewMemStorage(), nil)
-	if err != nil {
-		return nil, err
-	}
-	return &DB{
-		ldb: ldb,
-	}, nil
+	return OpenInMemory()
 }
 
 func openBrowserFSDB(path string) (*DB, error) {
@@ -144,6 +144,28 @@ func (q *Query) Count() (int, error) {
 	return len(pkSet), nil
 }
 
+// CountByValue returns the number of models that match the query for each
+// index value. Unlike Run, it only reads the index and never decodes any
+// models, which makes it suitable for computing aggregate statistics. Models
+// are counted once for each of their index values that match the query. It
+// does not respect q.Max or q.Offset.
+func (q *Query) CountByValue() (map[string]int, error) {
+	iter := q.reader.NewIterator(q.filter.slice, nil)
+	defer iter.Release()
+	counts := map[string]int{}
+	for iter.Next() && iter.Error() == nil {
+		value, err := q.filter.index.valueFromIndexKey(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		counts[string(value)]++
+	}
+	if iter.Error() != nil {
+		return nil, iter.Error()
+	}
+	return counts, nil
+}
+
 func (q *Query) getModelsWithIteratorForward(iter iterator.Iterator, models interface{}) error {
 	// MultiIndexes can result in the same model being included more than once. To
 	// prevent this, we keep track of the primaryKeys we have already seen using
@@ -260,6 +260,39 @@ func TestFindWithRangeWithMultiIndex(t *testing.T) {
 	testQueryWithFilter(t, col, filter, expected)
 }
 
+func TestQueryCountByValue(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+	col, err := db.NewCollection("people", &testModel{})
+	require.NoError(t, err)
+
+	nicknameIndex := col.AddMultiIndex("nicknames", func(m Model) [][]byte {
+		nicknames := m.(*testModel).Nicknames
+		values := make([][]byte, len(nicknames))
+		for i, nickname := range nicknames {
+			values[i] = []byte(nickname)
+		}
+		return values
+	})
+
+	models := []*testModel{
+		{Name: "Alice", Nicknames: []string{"al:1", "ally"}},
+		{Name: "Alex", Nicknames: []string{"al:1"}},
+		{Name: "Bob", Nicknames: []string{"bobby"}},
+	}
+	for _, model := range models {
+		require.NoError(t, col.Insert(model))
+	}
+
+	counts, err := col.NewQuery(nicknameIndex.All()).CountByValue()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"al:1": 2, "ally": 1, "bobby": 1}, counts)
+
+	counts, err = col.NewQuery(nicknameIndex.PrefixFilter([]byte("al"))).CountByValue()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"al:1": 2, "ally": 1}, counts)
+}
+
 // testQueryWithFilter runs a comprehensive set of queries based on the given
 // filter and checks that the results are always what we expect.
 func testQueryWithFilter(t *testing.T, col *Collection, filter *Filter, expected []*testModel) {
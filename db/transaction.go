@@ -7,11 +7,15 @@ import (
 	"sync/atomic"
 
 	"github.com/albrow/stringset"
+	"github.com/syndtr/goleveldb/leveldb"
 )
 
 var (
 	ErrDiscarded = errors.New("transaction has already been discarded")
 	ErrCommitted = errors.New("transaction has already been committed")
+	// ErrDifferentDatabases is returned by CommitTransactions if the
+	// transactions were opened on collections of different databases.
+	ErrDifferentDatabases = errors.New("cannot commit transactions of different databases together")
 )
 
 // ConflictingOperationsError is returned when two conflicting operations are attempted within the same
@@ -107,17 +111,75 @@ func (txn *Transaction) Commit() error {
 	}
 	// Right before we commit, we need to update the count with txn.internalCount.
 	if err := updateCountWithTransaction(txn.colInfo, txn.readWriter, int(txn.internalCount)); err != nil {
-		_ = txn.Discard()
+		txn.unsafeDiscard()
 		return err
 	}
 	if err := txn.batchWriter.Write(txn.readWriter.batch, nil); err != nil {
-		_ = txn.Discard()
+		txn.unsafeDiscard()
 		return err
 	}
+	txn.unsafeMarkCommitted()
+	return nil
+}
+
+// CommitTransactions atomically commits the given transactions, which must
+// have been opened on collections of the same database. Either the operations
+// queued in all of the transactions are applied or none of them are. This can
+// be used to keep several collections consistent with each other without
+// blocking writes to all collections like a GlobalTransaction does. If error is
+// not nil, then all of the transactions are discarded.
+func CommitTransactions(txns ...*Transaction) error {
+	if len(txns) == 0 {
+		return nil
+	}
+	for _, txn := range txns {
+		txn.mut.Lock()
+		defer txn.mut.Unlock()
+	}
+	discardAll := func() {
+		for _, txn := range txns {
+			if !txn.committed && !txn.discarded {
+				txn.unsafeDiscard()
+			}
+		}
+	}
+	for _, txn := range txns {
+		if err := txn.unsafeCheckState(); err != nil {
+			discardAll()
+			return err
+		}
+		if txn.db != txns[0].db {
+			discardAll()
+			return ErrDifferentDatabases
+		}
+	}
+	batch := &leveldb.Batch{}
+	for _, txn := range txns {
+		if err := updateCountWithTransaction(txn.colInfo, txn.readWriter, int(txn.internalCount)); err != nil {
+			discardAll()
+			return err
+		}
+		if err := txn.readWriter.batch.Replay(batch); err != nil {
+			discardAll()
+			return err
+		}
+	}
+	if err := txns[0].batchWriter.Write(batch, nil); err != nil {
+		discardAll()
+		return err
+	}
+	for _, txn := range txns {
+		txn.unsafeMarkCommitted()
+	}
+	return nil
+}
+
+// unsafeMarkCommitted marks the transaction as committed and releases its
+// locks, assuming the caller has already acquired a lock on txn.mut.
+func (txn *Transaction) unsafeMarkCommitted() {
 	txn.committed = true
 	txn.colInfo.writeMut.Unlock()
 	txn.db.globalWriteLock.RUnlock()
-	return nil
 }
 
 // Discard discards the transaction.
@@ -133,10 +195,17 @@ func (txn *Transaction) Discard() error {
 	if txn.discarded {
 		return nil
 	}
+	txn.unsafeDiscard()
+	return nil
+}
+
+// unsafeDiscard discards the transaction and releases its locks, assuming the
+// caller has already acquired a lock on txn.mut and checked that the
+// transaction is still open.
+func (txn *Transaction) unsafeDiscard() {
 	txn.discarded = true
 	txn.colInfo.writeMut.Unlock()
 	txn.db.globalWriteLock.RUnlock()
-	return nil
 }
 
 // Insert queues an operation to insert the given model into the database. It
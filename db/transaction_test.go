@@ -259,6 +259,56 @@ func TestTransactionExclusion(t *testing.T) {
 	wg.Wait()
 }
 
+func TestCommitTransactions(t *testing.T) {
+	t.Parallel()
+	db := newTestDB(t)
+	defer db.Close()
+	col0, err := db.NewCollection("people0", &testModel{})
+	require.NoError(t, err)
+	col1, err := db.NewCollection("people1", &testModel{})
+	require.NoError(t, err)
+
+	existing := &testModel{Name: "Existing", Age: 1}
+	require.NoError(t, col1.Insert(existing))
+
+	// The operations of both transactions are applied.
+	txn0 := col0.OpenTransaction()
+	txn1 := col1.OpenTransaction()
+	inserted := &testModel{Name: "Inserted", Age: 2}
+	require.NoError(t, txn0.Insert(inserted))
+	require.NoError(t, txn1.Delete(existing.ID()))
+	require.NoError(t, CommitTransactions(txn0, txn1))
+	assert.Equal(t, ErrCommitted, txn0.Discard())
+	assert.Equal(t, ErrCommitted, txn1.Discard())
+
+	var found testModel
+	require.NoError(t, col0.FindByID(inserted.ID(), &found))
+	assert.Equal(t, inserted, &found)
+	assert.IsType(t, NotFoundError{}, col1.FindByID(existing.ID(), &found))
+	count, err := col0.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	count, err = col1.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// If one of the transactions can't be committed, none of the operations
+	// are applied and all transactions are discarded.
+	txn0 = col0.OpenTransaction()
+	txn1 = col1.OpenTransaction()
+	notInserted := &testModel{Name: "NotInserted", Age: 3}
+	require.NoError(t, txn0.Insert(notInserted))
+	require.NoError(t, txn1.Discard())
+	assert.Equal(t, ErrDiscarded, CommitTransactions(txn0, txn1))
+	assert.Equal(t, ErrDiscarded, txn0.Commit())
+	assert.IsType(t, NotFoundError{}, col0.FindByID(notInserted.ID(), &found))
+
+	// The collections can be written to again after the transactions were
+	// committed or discarded.
+	require.NoError(t, col0.Insert(notInserted))
+	require.NoError(t, col1.Insert(existing))
+}
+
 func TestTransactionDeleteThenInsertSameModel(t *testing.T) {
 	t.Parallel()
 	db := newTestDB(t)
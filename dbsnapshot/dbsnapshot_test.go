@@ -0,0 +1,212 @@
+package dbsnapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/ethereum"
+	"github.com/0xProject/0x-mesh/ethereum/miniheader"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var contractAddresses = ethereum.GanacheAddresses
+
+func newTestMeshDB(t *testing.T) *meshdb.MeshDB {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	return meshDB
+}
+
+func newTestOrder(t *testing.T) *meshdb.Order {
+	signedOrder, err := zeroex.SignTestOrder(&zeroex.Order{
+		ChainID:               big.NewInt(constants.TestChainID),
+		ExchangeAddress:       contractAddresses.Exchange,
+		MakerAddress:          constants.GanacheAccount0,
+		TakerAddress:          constants.NullAddress,
+		SenderAddress:         constants.NullAddress,
+		FeeRecipientAddress:   constants.NullAddress,
+		MakerAssetData:        common.Hex2Bytes("f47261b000000000000000000000000034d402f14d58e001d8efbe6585051bf9706aa064"),
+		MakerFeeAssetData:     constants.NullBytes,
+		TakerAssetData:        common.Hex2Bytes("f47261b0000000000000000000000000871dd7c2b4b25e1aa18728e9d5f2af4c4e431f5c"),
+		TakerFeeAssetData:     constants.NullBytes,
+		Salt:                  big.NewInt(1548619145450),
+		MakerFee:              big.NewInt(0),
+		TakerFee:              big.NewInt(0),
+		MakerAssetAmount:      big.NewInt(1000),
+		TakerAssetAmount:      big.NewInt(2000),
+		ExpirationTimeSeconds: big.NewInt(1893456000),
+	})
+	require.NoError(t, err)
+	orderHash, err := signedOrder.ComputeOrderHash()
+	require.NoError(t, err)
+	return &meshdb.Order{
+		Hash:                     orderHash,
+		SignedOrder:              signedOrder,
+		FillableTakerAssetAmount: big.NewInt(2000),
+		LastUpdated:              time.Now().UTC(),
+		IsPinned:                 true,
+	}
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	sourceDB := newTestMeshDB(t)
+	defer sourceDB.Close()
+	require.NoError(t, sourceDB.SaveMetadata(&meshdb.Metadata{
+		EthereumChainID:   constants.TestChainID,
+		MaxExpirationTime: constants.UnlimitedExpirationTime,
+	}))
+	miniHeader := &miniheader.MiniHeader{
+		Hash:      common.HexToHash("0x1"),
+		Parent:    common.HexToHash("0x0"),
+		Number:    big.NewInt(42),
+		Timestamp: time.Now().UTC(),
+	}
+	require.NoError(t, sourceDB.MiniHeaders.Insert(miniHeader))
+	order := newTestOrder(t)
+	require.NoError(t, sourceDB.Orders.Insert(order))
+
+	buf := &bytes.Buffer{}
+	header, err := Create(sourceDB, buf)
+	require.NoError(t, err)
+	assert.Equal(t, constants.TestChainID, header.ChainID)
+	assert.Equal(t, int64(42), header.BlockNumber)
+	assert.Equal(t, 1, header.NumOrders)
+
+	// Snapshots are only restored on the same chain.
+	otherChainDB := newTestMeshDB(t)
+	defer otherChainDB.Close()
+	_, err = Restore(otherChainDB, bytes.NewReader(buf.Bytes()), constants.TestChainID+1)
+	assert.Error(t, err)
+
+	targetDB := newTestMeshDB(t)
+	defer targetDB.Close()
+	restoredHeader, err := Restore(targetDB, bytes.NewReader(buf.Bytes()), constants.TestChainID)
+	require.NoError(t, err)
+	assert.Equal(t, header.BlockHash, restoredHeader.BlockHash)
+
+	latestMiniHeader, err := targetDB.FindLatestMiniHeader()
+	require.NoError(t, err)
+	assert.Equal(t, miniHeader.Hash, latestMiniHeader.Hash)
+	var restoredOrders []*meshdb.Order
+	require.NoError(t, targetDB.Orders.FindAll(&restoredOrders))
+	require.Len(t, restoredOrders, 1)
+	assert.Equal(t, order.Hash, restoredOrders[0].Hash)
+	assert.Equal(t, order.FillableTakerAssetAmount, restoredOrders[0].FillableTakerAssetAmount)
+	assert.False(t, restoredOrders[0].IsPinned, "restored orders should not be pinned")
+}
+
+func TestRestoreRejectsTruncatedSnapshot(t *testing.T) {
+	// The header claims that the snapshot contains an order, but it is
+	// missing.
+	encodedHeader, err := json.Marshal(Header{
+		Version:   formatVersion,
+		ChainID:   constants.TestChainID,
+		NumOrders: 1,
+	})
+	require.NoError(t, err)
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	_, err = gzipWriter.Write(append(encodedHeader, '\n'))
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	meshDB := newTestMeshDB(t)
+	defer meshDB.Close()
+	_, err = Restore(meshDB, buf, constants.TestChainID)
+	assert.Error(t, err)
+}
+
+func newManifestSigner(t *testing.T) (p2pcrypto.PrivKey, peer.ID) {
+	privKey, _, err := p2pcrypto.GenerateSecp256k1Key(rand.Reader)
+	require.NoError(t, err)
+	signer, err := peer.IDFromPrivateKey(privKey)
+	require.NoError(t, err)
+	return privKey, signer
+}
+
+func TestManifestVerify(t *testing.T) {
+	privKey, signer := newManifestSigner(t)
+	_, otherSigner := newManifestSigner(t)
+
+	manifest := &Manifest{
+		SnapshotURL: "snapshot.jsonl.gz",
+		SHA256:      "00",
+		ChainID:     constants.TestChainID,
+		CreatedAt:   time.Now().UTC(),
+	}
+	require.NoError(t, manifest.Sign(privKey))
+	assert.NoError(t, manifest.Verify(signer))
+	assert.Equal(t, ErrInvalidManifestSignature, manifest.Verify(otherSigner))
+
+	// Changing the manifest invalidates the signature.
+	manifest.SHA256 = "01"
+	assert.Equal(t, ErrInvalidManifestSignature, manifest.Verify(signer))
+}
+
+func TestResolveURL(t *testing.T) {
+	testCases := map[string]string{
+		"https://example.com/manifest.json":   "https://example.com/manifest.json",
+		"s3://bucket/snapshots/manifest.json": "https://bucket.s3.amazonaws.com/snapshots/manifest.json",
+		"gs://bucket/snapshots/manifest.json": "https://storage.googleapis.com/bucket/snapshots/manifest.json",
+	}
+	for input, expected := range testCases {
+		resolved, err := ResolveURL(input)
+		require.NoError(t, err)
+		assert.Equal(t, expected, resolved.String())
+	}
+	_, err := ResolveURL("ftp://example.com/manifest.json")
+	assert.Error(t, err)
+}
+
+func TestFetchManifestAndDownload(t *testing.T) {
+	privKey, signer := newManifestSigner(t)
+	snapshot := []byte("snapshot")
+	checksum := sha256.Sum256(snapshot)
+	manifest := &Manifest{
+		SnapshotURL: "snapshot.jsonl.gz",
+		SHA256:      hex.EncodeToString(checksum[:]),
+		ChainID:     constants.TestChainID,
+		CreatedAt:   time.Now().UTC(),
+	}
+	require.NoError(t, manifest.Sign(privKey))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/snapshots/manifest.json":
+			_ = json.NewEncoder(w).Encode(manifest)
+		case "/snapshots/snapshot.jsonl.gz":
+			_, _ = w.Write(snapshot)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	fetched, err := FetchManifest(context.Background(), server.URL+"/snapshots/manifest.json", signer)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/snapshots/snapshot.jsonl.gz", fetched.SnapshotURL)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, Download(context.Background(), fetched, buf))
+	assert.Equal(t, snapshot, buf.Bytes())
+
+	fetched.SHA256 = hex.EncodeToString(make([]byte, sha256.Size))
+	assert.Equal(t, ErrChecksumMismatch, Download(context.Background(), fetched, &bytes.Buffer{}))
+}
@@ -0,0 +1,130 @@
+package dbsnapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// manifestTimeout is the timeout for fetching a manifest.
+	manifestTimeout = 30 * time.Second
+	// maxManifestSize is the maximum size in bytes of a manifest.
+	maxManifestSize = 1 << 20 // 1 MiB.
+)
+
+// ErrChecksumMismatch is returned when a downloaded snapshot does not match
+// the checksum in its manifest.
+var ErrChecksumMismatch = errors.New("database snapshot does not match the checksum in its manifest")
+
+// ResolveURL returns the HTTP(S) URL for the given URL of an object. In
+// addition to HTTP(S) URLs, it accepts s3://bucket/key and gs://bucket/key,
+// which refer to publicly readable objects in Amazon S3 and Google Cloud
+// Storage. Objects in private buckets can be accessed via presigned HTTPS
+// URLs.
+func ResolveURL(rawURL string) (*url.URL, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	key := strings.TrimPrefix(parsed.Path, "/")
+	switch parsed.Scheme {
+	case "http", "https":
+		return parsed, nil
+	case "s3":
+		return url.Parse(fmt.Sprintf("https://%s.s3.amazonaws.com/%s", parsed.Host, key))
+	case "gs":
+		return url.Parse(fmt.Sprintf("https://storage.googleapis.com/%s/%s", parsed.Host, key))
+	default:
+		return nil, fmt.Errorf("unsupported URL scheme: %q (expected http, https, s3 or gs)", parsed.Scheme)
+	}
+}
+
+// FetchManifest downloads the manifest at the given URL and checks that it was
+// signed by signer. The SnapshotURL of the returned manifest is resolved
+// relative to the URL of the manifest.
+func FetchManifest(ctx context.Context, manifestURL string, signer peer.ID) (*Manifest, error) {
+	resolvedURL, err := ResolveURL(manifestURL)
+	if err != nil {
+		return nil, err
+	}
+	fetchCtx, cancel := context.WithTimeout(ctx, manifestTimeout)
+	defer cancel()
+	body, err := get(fetchCtx, resolvedURL.String())
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(body, maxManifestSize))
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid database snapshot manifest: %s", err.Error())
+	}
+	if err := manifest.Verify(signer); err != nil {
+		return nil, err
+	}
+	snapshotURL, err := url.Parse(manifest.SnapshotURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot URL in manifest: %s", err.Error())
+	}
+	if snapshotURL.Scheme == "" {
+		snapshotURL = resolvedURL.ResolveReference(snapshotURL)
+	}
+	manifest.SnapshotURL = snapshotURL.String()
+	return &manifest, nil
+}
+
+// Download writes the snapshot described by manifest to w and returns
+// ErrChecksumMismatch if it does not match the checksum of the manifest. The
+// data written to w must not be used in that case.
+func Download(ctx context.Context, manifest *Manifest, w io.Writer) error {
+	resolvedURL, err := ResolveURL(manifest.SnapshotURL)
+	if err != nil {
+		return err
+	}
+	body, err := get(ctx, resolvedURL.String())
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(w, hash), body); err != nil {
+		return err
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != strings.ToLower(manifest.SHA256) {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
+
+// get sends a GET request to the given URL and returns the body of the
+// response if the status code is 200.
+func get(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("unexpected status code while fetching %s: %d", rawURL, res.StatusCode)
+	}
+	return res.Body, nil
+}
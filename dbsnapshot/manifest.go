@@ -0,0 +1,81 @@
+package dbsnapshot
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	canonicaljson "github.com/gibson042/canonicaljson-go"
+	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// ErrInvalidManifestSignature is returned when a manifest was not signed by
+// the expected signer.
+var ErrInvalidManifestSignature = errors.New("database snapshot manifest has an invalid signature")
+
+// Manifest describes a database snapshot. It is signed by a libp2p private
+// key so that nodes only restore snapshots from a trusted source, and it
+// contains the checksum of the snapshot so that the snapshot itself can be
+// served from untrusted storage.
+type Manifest struct {
+	// SnapshotURL is the URL of the snapshot. It may be relative to the URL
+	// of the manifest.
+	SnapshotURL string `json:"snapshotURL"`
+	// SHA256 is the hex-encoded SHA-256 checksum of the snapshot.
+	SHA256 string `json:"sha256"`
+	// ChainID is the chain ID of the node the snapshot was created from.
+	ChainID int `json:"chainId"`
+	// BlockNumber and BlockHash identify the latest block stored in the
+	// snapshot.
+	BlockNumber int64       `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+	// NumOrders is the number of orders in the snapshot.
+	NumOrders int `json:"numOrders"`
+	// CreatedAt is when the snapshot was created.
+	CreatedAt time.Time `json:"createdAt"`
+	// Signature is the signature of the canonical JSON encoding of all other
+	// fields.
+	Signature []byte `json:"signature"`
+}
+
+// signedBytes returns the bytes which are signed by the signer of the
+// manifest.
+func (m *Manifest) signedBytes() ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = nil
+	return canonicaljson.Marshal(unsigned)
+}
+
+// Sign sets the signature of the manifest using the given private key.
+func (m *Manifest) Sign(privKey p2pcrypto.PrivKey) error {
+	data, err := m.signedBytes()
+	if err != nil {
+		return err
+	}
+	signature, err := privKey.Sign(data)
+	if err != nil {
+		return err
+	}
+	m.Signature = signature
+	return nil
+}
+
+// Verify returns ErrInvalidManifestSignature if the manifest was not signed by
+// the private key of the given peer.
+func (m *Manifest) Verify(signer peer.ID) error {
+	pubKey, err := signer.ExtractPublicKey()
+	if err != nil {
+		return fmt.Errorf("could not get public key of database snapshot signer: %s", err.Error())
+	}
+	data, err := m.signedBytes()
+	if err != nil {
+		return err
+	}
+	valid, err := pubKey.Verify(data, m.Signature)
+	if err != nil || !valid {
+		return ErrInvalidManifestSignature
+	}
+	return nil
+}
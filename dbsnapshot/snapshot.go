@@ -0,0 +1,190 @@
+// Package dbsnapshot creates and restores snapshots of the database of a Mesh
+// node. A snapshot contains the orders and mini headers of a node, which lets
+// new nodes skip most of the work of discovering orders via ordersync when
+// they start for the first time. Snapshots are described by a signed
+// Manifest and are usually served from object storage (see Fetch).
+//
+// Restored orders are not trusted: nodes re-validate all of them after
+// restoring a snapshot.
+package dbsnapshot
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/0xProject/0x-mesh/ethereum/miniheader"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// formatVersion is the version of the snapshot format. It is increased
+	// whenever the format changes in an incompatible way.
+	formatVersion = 1
+	// restoreBatchSize is the number of orders inserted in a single
+	// transaction while restoring a snapshot.
+	restoreBatchSize = 1000
+	// maxRecordSize is the maximum size of a single line of a snapshot.
+	maxRecordSize = 4 << 20 // 4 MiB.
+)
+
+// Header is the first record of a snapshot.
+type Header struct {
+	Version     int         `json:"version"`
+	ChainID     int         `json:"chainId"`
+	BlockNumber int64       `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+	NumOrders   int         `json:"numOrders"`
+	CreatedAt   time.Time   `json:"createdAt"`
+}
+
+// record is any record of a snapshot after the header. Exactly one of the
+// fields is set.
+type record struct {
+	MiniHeader *miniheader.MiniHeader `json:"miniHeader,omitempty"`
+	Order      *meshdb.Order          `json:"order,omitempty"`
+}
+
+// Create writes a snapshot of all mini headers and all orders which have not
+// been flagged for removal to w. The snapshot is a gzip-compressed JSON Lines
+// file starting with a Header.
+func Create(meshDB *meshdb.MeshDB, w io.Writer) (*Header, error) {
+	metadata, err := meshDB.GetMetadata()
+	if err != nil {
+		return nil, err
+	}
+	latestMiniHeader, err := meshDB.FindLatestMiniHeader()
+	if err != nil {
+		return nil, err
+	}
+	miniHeaders, err := meshDB.FindAllMiniHeadersSortedByNumber()
+	if err != nil {
+		return nil, err
+	}
+	var orders []*meshdb.Order
+	notRemovedFilter := meshDB.Orders.IsRemovedIndex.ValueFilter([]byte{0})
+	if err := meshDB.Orders.NewQuery(notRemovedFilter).Run(&orders); err != nil {
+		return nil, err
+	}
+
+	header := &Header{
+		Version:     formatVersion,
+		ChainID:     metadata.EthereumChainID,
+		BlockNumber: latestMiniHeader.Number.Int64(),
+		BlockHash:   latestMiniHeader.Hash,
+		NumOrders:   len(orders),
+		CreatedAt:   time.Now().UTC(),
+	}
+	gzipWriter := gzip.NewWriter(w)
+	encoder := json.NewEncoder(gzipWriter)
+	if err := encoder.Encode(header); err != nil {
+		return nil, err
+	}
+	for _, miniHeader := range miniHeaders {
+		if err := encoder.Encode(record{MiniHeader: miniHeader}); err != nil {
+			return nil, err
+		}
+	}
+	for _, order := range orders {
+		if err := encoder.Encode(record{Order: order}); err != nil {
+			return nil, err
+		}
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// Restore inserts the mini headers and orders of the snapshot read from r into
+// meshDB, which should be empty. It returns an error if the snapshot was
+// created for a different chain than chainID. Restored orders are never
+// pinned, since pinning is a local decision of the node that created the
+// snapshot. If Restore returns an error, meshDB may contain some of the
+// records of the snapshot.
+func Restore(meshDB *meshdb.MeshDB, r io.Reader, chainID int) (*Header, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(gzipReader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("database snapshot is empty")
+	}
+	var header Header
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return nil, fmt.Errorf("invalid database snapshot header: %s", err.Error())
+	}
+	if header.Version != formatVersion {
+		return nil, fmt.Errorf("unsupported database snapshot version: %d (expected %d)", header.Version, formatVersion)
+	}
+	if header.ChainID != chainID {
+		return nil, fmt.Errorf("database snapshot was created for chain ID %d but the chain ID is %d", header.ChainID, chainID)
+	}
+
+	txn := meshDB.Orders.OpenTransaction()
+	defer func() {
+		_ = txn.Discard()
+	}()
+	numOrders := 0
+	numOrdersInTxn := 0
+	for scanner.Scan() {
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("invalid database snapshot record: %s", err.Error())
+		}
+		switch {
+		case rec.MiniHeader != nil:
+			if err := meshDB.MiniHeaders.Insert(rec.MiniHeader); err != nil {
+				return nil, err
+			}
+		case rec.Order != nil:
+			if rec.Order.SignedOrder == nil {
+				return nil, errors.New("invalid database snapshot record: order without signed order")
+			}
+			// Check that the order hash matches the order, since it is used as
+			// the primary key.
+			orderHash, err := rec.Order.SignedOrder.ComputeOrderHash()
+			if err != nil {
+				return nil, err
+			}
+			if orderHash != rec.Order.Hash {
+				return nil, fmt.Errorf("invalid database snapshot record: order hash %s does not match the order", rec.Order.Hash.Hex())
+			}
+			rec.Order.IsPinned = false
+			if err := txn.Insert(rec.Order); err != nil {
+				return nil, err
+			}
+			numOrders++
+			numOrdersInTxn++
+			if numOrdersInTxn == restoreBatchSize {
+				if err := txn.Commit(); err != nil {
+					return nil, err
+				}
+				txn = meshDB.Orders.OpenTransaction()
+				numOrdersInTxn = 0
+			}
+		default:
+			return nil, errors.New("invalid database snapshot record: expected a mini header or an order")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
+	if numOrders != header.NumOrders {
+		return nil, fmt.Errorf("database snapshot is truncated: expected %d orders but found %d", header.NumOrders, numOrders)
+	}
+	return &header, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/0xProject/0x-mesh/encoding/jsonscan"
 	"github.com/0xProject/0x-mesh/zeroex"
 )
 
@@ -24,6 +25,9 @@ func OrderToRawMessage(topic string, order *zeroex.SignedOrder) ([]byte, error)
 
 // RawMessageToOrder decodes an order message sent over the wire into an order
 func RawMessageToOrder(data []byte) (*zeroex.SignedOrder, error) {
+	if order, ok := rawMessageToOrderFast(data); ok {
+		return order, nil
+	}
 	var orderMessage orderMessage
 	if err := json.Unmarshal(data, &orderMessage); err != nil {
 		return nil, err
@@ -33,3 +37,51 @@ func RawMessageToOrder(data []byte) (*zeroex.SignedOrder, error) {
 	}
 	return orderMessage.Order, nil
 }
+
+// rawMessageToOrderFast decodes an order message without going through
+// encoding/json. It returns false if the message is invalid or uses any JSON
+// features not supported by jsonscan, in which case the caller must fall back
+// to encoding/json.
+func rawMessageToOrderFast(data []byte) (*zeroex.SignedOrder, bool) {
+	var messageType, rawOrder []byte
+	scanner := jsonscan.New(data)
+	err := scanner.ReadObject(func(key []byte) error {
+		kind, err := scanner.Peek()
+		if err != nil {
+			return err
+		}
+		switch {
+		case string(key) == "messageType" && kind == jsonscan.KindString:
+			messageType, err = scanner.ReadString()
+			return err
+		case string(key) == "order" && kind == jsonscan.KindObject:
+			rawOrder, err = scanner.ReadRaw()
+			return err
+		case string(key) == "topics" && kind == jsonscan.KindArray:
+			// Topics are not used when decoding orders but must still be
+			// strings for the message to be valid.
+			return scanner.ReadArray(func() error {
+				if kind, err := scanner.Peek(); err != nil {
+					return err
+				} else if kind != jsonscan.KindString {
+					return jsonscan.ErrUnsupported
+				}
+				_, err := scanner.ReadString()
+				return err
+			})
+		default:
+			return jsonscan.ErrUnsupported
+		}
+	})
+	if err != nil || scanner.End() != nil {
+		return nil, false
+	}
+	if string(messageType) != "order" || rawOrder == nil {
+		return nil, false
+	}
+	var order zeroex.SignedOrder
+	if err := order.UnmarshalJSON(rawOrder); err != nil {
+		return nil, false
+	}
+	return &order, true
+}
@@ -0,0 +1,124 @@
+package encoding
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSignedOrder(t require.TestingT) *zeroex.SignedOrder {
+	order := &zeroex.Order{
+		ChainID:               big.NewInt(constants.TestChainID),
+		MakerAddress:          constants.GanacheAccount0,
+		TakerAddress:          constants.NullAddress,
+		SenderAddress:         constants.NullAddress,
+		FeeRecipientAddress:   constants.NullAddress,
+		MakerAssetData:        constants.NullAddress.Bytes(),
+		MakerFeeAssetData:     constants.NullBytes,
+		TakerAssetData:        constants.NullAddress.Bytes(),
+		TakerFeeAssetData:     constants.NullBytes,
+		Salt:                  big.NewInt(200),
+		MakerFee:              big.NewInt(201),
+		TakerFee:              big.NewInt(202),
+		MakerAssetAmount:      big.NewInt(203),
+		TakerAssetAmount:      big.NewInt(204),
+		ExpirationTimeSeconds: big.NewInt(205),
+		ExchangeAddress:       constants.NullAddress,
+	}
+	signedOrder, err := zeroex.SignTestOrder(order)
+	require.NoError(t, err)
+	return signedOrder
+}
+
+func TestOrderToRawMessageRoundTrip(t *testing.T) {
+	signedOrder := newTestSignedOrder(t)
+	data, err := OrderToRawMessage("/0x-orders/version/3/chain/1337/schema/e30=", signedOrder)
+	require.NoError(t, err)
+
+	decoded, ok := rawMessageToOrderFast(data)
+	require.True(t, ok, "message should be supported by the fast decoder")
+	assert.Equal(t, signedOrder, decoded)
+
+	decoded, err = RawMessageToOrder(data)
+	require.NoError(t, err)
+	assert.Equal(t, signedOrder, decoded)
+}
+
+func TestRawMessageToOrderFallback(t *testing.T) {
+	signedOrder := newTestSignedOrder(t)
+	orderJSON, err := json.Marshal(signedOrder)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		data        string
+		expectError bool
+	}{
+		{
+			name: "escaped message type",
+			data: `{"messageType":"\u006frder","order":` + string(orderJSON) + `,"topics":[]}`,
+		},
+		{
+			name: "null topics",
+			data: `{"messageType":"order","order":` + string(orderJSON) + `,"topics":null}`,
+		},
+		{
+			name:        "wrong message type",
+			data:        `{"messageType":"foo","order":` + string(orderJSON) + `,"topics":[]}`,
+			expectError: true,
+		},
+		{
+			name:        "non-string topic",
+			data:        `{"messageType":"order","order":` + string(orderJSON) + `,"topics":[1]}`,
+			expectError: true,
+		},
+		{
+			name:        "invalid JSON",
+			data:        `{"messageType":"order","order":`,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ok := rawMessageToOrderFast([]byte(tc.data))
+			assert.False(t, ok, "message should not be supported by the fast decoder")
+			decoded, err := RawMessageToOrder([]byte(tc.data))
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, signedOrder, decoded)
+		})
+	}
+}
+
+func BenchmarkRawMessageToOrder(b *testing.B) {
+	signedOrder := newTestSignedOrder(b)
+	data, err := OrderToRawMessage("/0x-orders/version/3/chain/1337/schema/e30=", signedOrder)
+	require.NoError(b, err)
+
+	b.Run("fast", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := RawMessageToOrder(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("standard", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var message orderMessage
+			if err := json.Unmarshal(data, &message); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
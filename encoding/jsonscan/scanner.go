@@ -0,0 +1,275 @@
+// Package jsonscan is a minimal, allocation-free JSON scanner used on hot paths
+// such as decoding orders received from peers. It only supports the subset of
+// JSON that Mesh itself produces: strings without escape sequences or non-ASCII
+// characters, integers and arbitrarily nested objects and arrays. Callers are
+// expected to fall back to encoding/json whenever ErrUnsupported is returned.
+package jsonscan
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported is returned when the input contains JSON which is valid but
+// not supported by the scanner (e.g. escape sequences in strings or
+// fractional numbers).
+var ErrUnsupported = errors.New("jsonscan: unsupported JSON input")
+
+// Kind is the kind of a JSON value.
+type Kind uint8
+
+// Kind values
+const (
+	KindString Kind = iota
+	KindNumber
+	KindLiteral
+	KindObject
+	KindArray
+)
+
+// Scanner reads JSON values from a byte slice. The zero value has no input;
+// use New or Reset to set it.
+type Scanner struct {
+	data []byte
+	pos  int
+}
+
+// New returns a Scanner for the given data.
+func New(data []byte) *Scanner {
+	return &Scanner{data: data}
+}
+
+// Reset resets the scanner to read from the given data. It allows a Scanner
+// to be reused.
+func (s *Scanner) Reset(data []byte) {
+	s.data = data
+	s.pos = 0
+}
+
+// Peek returns the kind of the next value without consuming it.
+func (s *Scanner) Peek() (Kind, error) {
+	s.skipWhitespace()
+	if s.pos >= len(s.data) {
+		return 0, s.syntaxError("unexpected end of input")
+	}
+	switch c := s.data[s.pos]; {
+	case c == '"':
+		return KindString, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		return KindNumber, nil
+	case c == 't' || c == 'f' || c == 'n':
+		return KindLiteral, nil
+	case c == '{':
+		return KindObject, nil
+	case c == '[':
+		return KindArray, nil
+	default:
+		return 0, s.syntaxError(fmt.Sprintf("invalid character %q", c))
+	}
+}
+
+// ReadString reads a string and returns its contents without the quotes. The
+// returned slice references the input data.
+func (s *Scanner) ReadString() ([]byte, error) {
+	if err := s.consume('"'); err != nil {
+		return nil, err
+	}
+	start := s.pos
+	for ; s.pos < len(s.data); s.pos++ {
+		c := s.data[s.pos]
+		switch {
+		case c == '"':
+			value := s.data[start:s.pos]
+			s.pos++
+			return value, nil
+		case c == '\\' || c < 0x20 || c >= 0x80:
+			// Escape sequences and non-ASCII characters require decoding or
+			// validation which we leave to encoding/json.
+			return nil, ErrUnsupported
+		}
+	}
+	return nil, s.syntaxError("unterminated string")
+}
+
+// ReadInteger reads an integer and returns its digits (including a leading
+// minus sign, if any). The returned slice references the input data.
+// Fractional numbers and numbers with exponents are not supported.
+func (s *Scanner) ReadInteger() ([]byte, error) {
+	s.skipWhitespace()
+	start := s.pos
+	if s.pos < len(s.data) && s.data[s.pos] == '-' {
+		s.pos++
+	}
+	digitsStart := s.pos
+	for s.pos < len(s.data) && s.data[s.pos] >= '0' && s.data[s.pos] <= '9' {
+		s.pos++
+	}
+	if s.pos == digitsStart {
+		return nil, s.syntaxError("expected digit")
+	}
+	if s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '.', 'e', 'E':
+			return nil, ErrUnsupported
+		}
+	}
+	if s.pos-digitsStart > 1 && s.data[digitsStart] == '0' {
+		// Leading zeroes are not allowed in JSON.
+		return nil, s.syntaxError("invalid number")
+	}
+	return s.data[start:s.pos], nil
+}
+
+// ReadObject reads an object. fn is called for each key and must consume the
+// corresponding value (e.g. by calling ReadString or Skip).
+func (s *Scanner) ReadObject(fn func(key []byte) error) error {
+	if err := s.consume('{'); err != nil {
+		return err
+	}
+	s.skipWhitespace()
+	if s.pos < len(s.data) && s.data[s.pos] == '}' {
+		s.pos++
+		return nil
+	}
+	for {
+		key, err := s.ReadString()
+		if err != nil {
+			return err
+		}
+		if err := s.consume(':'); err != nil {
+			return err
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+		s.skipWhitespace()
+		if s.pos >= len(s.data) {
+			return s.syntaxError("unterminated object")
+		}
+		switch s.data[s.pos] {
+		case ',':
+			s.pos++
+		case '}':
+			s.pos++
+			return nil
+		default:
+			return s.syntaxError(fmt.Sprintf("invalid character %q after object value", s.data[s.pos]))
+		}
+	}
+}
+
+// ReadRaw consumes the next value and returns its raw bytes. The returned
+// slice references the input data.
+func (s *Scanner) ReadRaw() ([]byte, error) {
+	s.skipWhitespace()
+	start := s.pos
+	if err := s.Skip(); err != nil {
+		return nil, err
+	}
+	return s.data[start:s.pos], nil
+}
+
+// Skip consumes the next value.
+func (s *Scanner) Skip() error {
+	kind, err := s.Peek()
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case KindString:
+		_, err := s.ReadString()
+		return err
+	case KindNumber:
+		_, err := s.ReadInteger()
+		return err
+	case KindLiteral:
+		for _, literal := range []string{"true", "false", "null"} {
+			if s.hasPrefix(literal) {
+				s.pos += len(literal)
+				return nil
+			}
+		}
+		return s.syntaxError("invalid literal")
+	case KindObject:
+		return s.ReadObject(func(key []byte) error {
+			return s.Skip()
+		})
+	case KindArray:
+		return s.ReadArray(s.Skip)
+	}
+	return nil
+}
+
+// ReadArray reads an array. fn is called for each element and must consume it
+// (e.g. by calling ReadString or Skip).
+func (s *Scanner) ReadArray(fn func() error) error {
+	if err := s.consume('['); err != nil {
+		return err
+	}
+	s.skipWhitespace()
+	if s.pos < len(s.data) && s.data[s.pos] == ']' {
+		s.pos++
+		return nil
+	}
+	for {
+		if err := fn(); err != nil {
+			return err
+		}
+		s.skipWhitespace()
+		if s.pos >= len(s.data) {
+			return s.syntaxError("unterminated array")
+		}
+		switch s.data[s.pos] {
+		case ',':
+			s.pos++
+		case ']':
+			s.pos++
+			return nil
+		default:
+			return s.syntaxError(fmt.Sprintf("invalid character %q after array element", s.data[s.pos]))
+		}
+	}
+}
+
+// End returns an error if there is any data other than whitespace left.
+func (s *Scanner) End() error {
+	s.skipWhitespace()
+	if s.pos != len(s.data) {
+		return s.syntaxError("unexpected data after top-level value")
+	}
+	return nil
+}
+
+func (s *Scanner) consume(c byte) error {
+	s.skipWhitespace()
+	if s.pos >= len(s.data) {
+		return s.syntaxError("unexpected end of input")
+	}
+	if s.data[s.pos] != c {
+		return s.syntaxError(fmt.Sprintf("expected %q but found %q", c, s.data[s.pos]))
+	}
+	s.pos++
+	return nil
+}
+
+func (s *Scanner) hasPrefix(prefix string) bool {
+	if len(s.data)-s.pos < len(prefix) {
+		return false
+	}
+	return string(s.data[s.pos:s.pos+len(prefix)]) == prefix
+}
+
+func (s *Scanner) skipWhitespace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *Scanner) syntaxError(msg string) error {
+	return fmt.Errorf("jsonscan: %s at offset %d", msg, s.pos)
+}
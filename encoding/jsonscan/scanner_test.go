@@ -0,0 +1,59 @@
+package jsonscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScannerReadObject(t *testing.T) {
+	scanner := New([]byte(` {"a": "foo", "b": -12, "c": [1, {"d": null}, []], "e": {}, "f": true} `))
+	values := map[string]string{}
+	err := scanner.ReadObject(func(key []byte) error {
+		kind, err := scanner.Peek()
+		if err != nil {
+			return err
+		}
+		var value []byte
+		switch kind {
+		case KindString:
+			value, err = scanner.ReadString()
+		case KindNumber:
+			value, err = scanner.ReadInteger()
+		default:
+			value, err = scanner.ReadRaw()
+		}
+		values[string(key)] = string(value)
+		return err
+	})
+	require.NoError(t, err)
+	require.NoError(t, scanner.End())
+	expected := map[string]string{
+		"a": "foo",
+		"b": "-12",
+		"c": `[1, {"d": null}, []]`,
+		"e": "{}",
+		"f": "true",
+	}
+	assert.Equal(t, expected, values)
+}
+
+func TestScannerUnsupported(t *testing.T) {
+	for _, input := range []string{`"a\"b"`, `"é"`, `1.5`, `1e3`} {
+		scanner := New([]byte(input))
+		assert.Equal(t, ErrUnsupported, scanner.Skip(), input)
+	}
+}
+
+func TestScannerInvalid(t *testing.T) {
+	for _, input := range []string{``, `{`, `{"a"}`, `{"a":1,}`, `[1 2]`, `"abc`, `01`, `nul`, `-`} {
+		scanner := New([]byte(input))
+		err := scanner.Skip()
+		if err == nil {
+			err = scanner.End()
+		}
+		assert.Error(t, err, input)
+		assert.NotEqual(t, ErrUnsupported, err, input)
+	}
+}
@@ -458,6 +458,7 @@ func (w *Watcher) getMissedEventsToBackfill(ctx context.Context, blocksElapsed i
 					Number:    blockNumber,
 					Logs:      []types.Log{},
 					Timestamp: header.Timestamp,
+					BaseFee:   header.BaseFee,
 				}
 				hashToBlockHeader[log.BlockHash] = blockHeader
 			}
@@ -587,6 +588,33 @@ func (w *Watcher) getLogsInBlockRange(ctx context.Context, from, to int) ([]type
 	return allLogs, furthestBlockProcessed
 }
 
+// FilterLogs returns the logs emitted by the given contract between the from
+// and to blocks (inclusive) which match the given topics. Unlike the logs
+// fetched while syncing, they are not turned into block events. The range is
+// split into requests of at most maxBlocksInGetLogsQuery blocks which are
+// sent sequentially.
+func (w *Watcher) FilterLogs(ctx context.Context, contractAddress common.Address, from, to int, topics [][]common.Hash) ([]types.Log, error) {
+	allLogs := []types.Log{}
+	for _, r := range w.getSubBlockRanges(from, to, maxBlocksInGetLogsQuery) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		logs, err := w.client.FilterLogs(ethereum.FilterQuery{
+			FromBlock: big.NewInt(int64(r.FromBlock)),
+			ToBlock:   big.NewInt(int64(r.ToBlock)),
+			Addresses: []common.Address{contractAddress},
+			Topics:    topics,
+		})
+		if err != nil {
+			return nil, err
+		}
+		allLogs = append(allLogs, logs...)
+	}
+	return allLogs, nil
+}
+
 type blockRange struct {
 	FromBlock int
 	ToBlock   int
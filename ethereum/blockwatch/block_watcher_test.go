@@ -534,6 +534,40 @@ func TestGetLogsInBlockRange(t *testing.T) {
 	}
 }
 
+func TestFilterLogs(t *testing.T) {
+	from := 10
+	to := from + maxBlocksInGetLogsQuery + 10
+	fakeLogClient, err := newFakeLogClient(map[string]filterLogsResponse{
+		aRange(from, from+maxBlocksInGetLogsQuery-1): filterLogsResponse{
+			Logs: []types.Log{logStub},
+		},
+		aRange(from+maxBlocksInGetLogsQuery, to): filterLogsResponse{
+			Logs: []types.Log{logStub},
+		},
+	})
+	require.NoError(t, err)
+	config.Stack = simplestack.New(blockRetentionLimit, startMiniHeaders)
+	config.Client = fakeLogClient
+	watcher := New(config)
+
+	logs, err := watcher.FilterLogs(context.Background(), logStub.Address, from, to, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Log{logStub, logStub}, logs)
+	assert.Equal(t, 2, fakeLogClient.Count())
+
+	// Unlike getLogsInBlockRange, FilterLogs fails if any request fails.
+	fakeLogClient, err = newFakeLogClient(map[string]filterLogsResponse{
+		aRange(from, from+10): filterLogsResponse{
+			Err: errUnexpected,
+		},
+	})
+	require.NoError(t, err)
+	config.Client = fakeLogClient
+	watcher = New(config)
+	_, err = watcher.FilterLogs(context.Background(), logStub.Address, from, from+10, nil)
+	assert.Error(t, err)
+}
+
 func TestIsWarning(t *testing.T) {
 	errs := map[error]bool{
 		errors.New("not found"):     true,
@@ -2,7 +2,6 @@ package blockwatch
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"math/big"
 	"time"
@@ -48,6 +47,36 @@ type GetBlockByNumberResponse struct {
 	ParentHash common.Hash `json:"parentHash"`
 	Number     string      `json:"number"`
 	Timestamp  string      `json:"timestamp"`
+	// BaseFeePerGas is only included in blocks mined after the London hard
+	// fork (EIP-1559).
+	BaseFeePerGas string `json:"baseFeePerGas"`
+}
+
+// toMiniHeader converts the response of eth_getBlockByNumber or
+// eth_getBlockByHash to a MiniHeader.
+func (header GetBlockByNumberResponse) toMiniHeader(method string) (*miniheader.MiniHeader, error) {
+	blockNum, ok := math.ParseBig256(header.Number)
+	if !ok {
+		return nil, fmt.Errorf("Failed to parse big.Int value from hex-encoded block number returned from %s", method)
+	}
+	unixTimestamp, ok := math.ParseBig256(header.Timestamp)
+	if !ok {
+		return nil, fmt.Errorf("Failed to parse big.Int value from hex-encoded block timestamp returned from %s", method)
+	}
+	miniHeader := &miniheader.MiniHeader{
+		Hash:      header.Hash,
+		Parent:    header.ParentHash,
+		Number:    blockNum,
+		Timestamp: time.Unix(unixTimestamp.Int64(), 0),
+	}
+	if header.BaseFeePerGas != "" {
+		baseFee, ok := math.ParseBig256(header.BaseFeePerGas)
+		if !ok {
+			return nil, fmt.Errorf("Failed to parse big.Int value from hex-encoded block base fee returned from %s", method)
+		}
+		miniHeader.BaseFee = baseFee
+	}
+	return miniHeader, nil
 }
 
 // UnknownBlockNumberError is the error returned from a filter logs RPC call when the block number
@@ -93,21 +122,7 @@ func (rc *RpcClient) HeaderByNumber(number *big.Int) (*miniheader.MiniHeader, er
 		}
 	}
 
-	blockNum, ok := math.ParseBig256(header.Number)
-	if !ok {
-		return nil, errors.New("Failed to parse big.Int value from hex-encoded block number returned from eth_getBlockByNumber")
-	}
-	unixTimestamp, ok := math.ParseBig256(header.Timestamp)
-	if !ok {
-		return nil, errors.New("Failed to parse big.Int value from hex-encoded block timestamp returned from eth_getBlockByNumber")
-	}
-	miniHeader := &miniheader.MiniHeader{
-		Hash:      header.Hash,
-		Parent:    header.ParentHash,
-		Number:    blockNum,
-		Timestamp: time.Unix(unixTimestamp.Int64(), 0),
-	}
-	return miniHeader, nil
+	return header.toMiniHeader("eth_getBlockByNumber")
 }
 
 // UnknownBlockHashError is the error returned from a filter logs RPC call when the blockHash
@@ -123,25 +138,26 @@ func (e UnknownBlockHashError) Error() string {
 // HeaderByHash fetches a block header by its block hash. If no block exists with this number it will return
 // a `ethereum.NotFound` error.
 func (rc *RpcClient) HeaderByHash(hash common.Hash) (*miniheader.MiniHeader, error) {
+	// Note: Like in HeaderByNumber, we use a raw RPC call so that we can use the
+	// blockHash returned in the RPC response. Re-computing it from the block
+	// header would also result in the wrong hash for blocks with header fields
+	// unknown to go-ethereum's types.Header (e.g. baseFeePerGas).
+	var header GetBlockByNumberResponse
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
 	defer cancel()
-	header, err := rc.ethRPCClient.HeaderByHash(ctx, hash)
+	shouldIncludeTransactions := false
+	err := rc.ethRPCClient.CallContext(ctx, &header, "eth_getBlockByHash", hash, shouldIncludeTransactions)
 	if err != nil {
-		// Add blockHash to error so it gets logged
-		if err.Error() == ethereum.NotFound.Error() {
-			err = UnknownBlockHashError{
-				BlockHash: hash,
-			}
-		}
 		return nil, err
 	}
-	miniHeader := &miniheader.MiniHeader{
-		Hash:      header.Hash(),
-		Parent:    header.ParentHash,
-		Number:    header.Number,
-		Timestamp: time.Unix(int64(header.Time), 0),
+	// If it returned an empty struct
+	if header.Number == "" {
+		// Add blockHash to error so it gets logged
+		return nil, UnknownBlockHashError{
+			BlockHash: hash,
+		}
 	}
-	return miniHeader, nil
+	return header.toMiniHeader("eth_getBlockByHash")
 }
 
 // FilterUnknownBlockError is the error returned from a filter logs RPC call when the blockHash
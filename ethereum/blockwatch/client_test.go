@@ -0,0 +1,50 @@
+// +build !browser
+
+package blockwatch
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBlockByNumberResponseToMiniHeader(t *testing.T) {
+	testCases := []struct {
+		name            string
+		rawResponse     string
+		expectedBaseFee *big.Int
+	}{
+		{
+			name:            "pre-London block",
+			rawResponse:     `{"hash":"0x3c2ed56c4bd22226e4f4e31bd7ad4a2bc6e2bba9fed43c7f0a7ab1a9e15ac0d0","parentHash":"0x5a2d5b5e3d1f0bfa4bd0a6c3c8b5e0d2a0cb5cbef1d7ab2b0f5b1d1e1b9c4f55","number":"0xbd9a9f","timestamp":"0x60e79ec7"}`,
+			expectedBaseFee: nil,
+		},
+		{
+			name:            "post-London block",
+			rawResponse:     `{"hash":"0x3c2ed56c4bd22226e4f4e31bd7ad4a2bc6e2bba9fed43c7f0a7ab1a9e15ac0d0","parentHash":"0x5a2d5b5e3d1f0bfa4bd0a6c3c8b5e0d2a0cb5cbef1d7ab2b0f5b1d1e1b9c4f55","number":"0xc5d488","timestamp":"0x610bb5f5","baseFeePerGas":"0x3b9aca00"}`,
+			expectedBaseFee: big.NewInt(1000000000),
+		},
+	}
+	for _, testCase := range testCases {
+		var response GetBlockByNumberResponse
+		require.NoError(t, json.Unmarshal([]byte(testCase.rawResponse), &response), testCase.name)
+		miniHeader, err := response.toMiniHeader("eth_getBlockByNumber")
+		require.NoError(t, err, testCase.name)
+		assert.Equal(t, common.HexToHash("0x3c2ed56c4bd22226e4f4e31bd7ad4a2bc6e2bba9fed43c7f0a7ab1a9e15ac0d0"), miniHeader.Hash, testCase.name)
+		assert.Equal(t, testCase.expectedBaseFee, miniHeader.BaseFee, testCase.name)
+	}
+}
+
+func TestGetBlockByNumberResponseToMiniHeaderInvalidBaseFee(t *testing.T) {
+	response := GetBlockByNumberResponse{
+		Number:        "0x1",
+		Timestamp:     "0x1",
+		BaseFeePerGas: "not a number",
+	}
+	_, err := response.toMiniHeader("eth_getBlockByNumber")
+	assert.Error(t, err)
+}
@@ -21,6 +21,10 @@ type ContractAddresses struct {
 	ChaiBridge          common.Address `json:"chaiBridge"`
 	ChaiToken           common.Address `json:"chaiToken"`
 	MaximumGasPrice     common.Address `json:"maximumGasPrice"`
+	// Multicall is the address of a MakerDAO Multicall contract. It is optional
+	// and only used to batch contract reads when validating orders without
+	// DevUtils.
+	Multicall common.Address `json:"multicall"`
 }
 
 // GanacheAddresses The addresses that the 0x contracts were deployed to on the Ganache snapshot (chainID = 1337).
@@ -43,6 +47,7 @@ func NewContractAddressesForChainID(chainID int) (ContractAddresses, error) {
 			ChaiBridge:          common.HexToAddress("0x77c31eba23043b9a72d13470f3a3a311344d7438"),
 			ChaiToken:           common.HexToAddress("0x06af07097c9eeb7fd685c692751d5c66db49c215"),
 			MaximumGasPrice:     common.HexToAddress("0xe2bfd35306495d11e3c9db0d8de390cda24563cf"),
+			Multicall:           common.HexToAddress("0xeefba1e63905ef1d7acba5a8513c70307c1ce441"),
 		}, nil
 	case 3:
 		return ContractAddresses{
@@ -58,6 +63,7 @@ func NewContractAddressesForChainID(chainID int) (ContractAddresses, error) {
 			ChaiBridge:          common.HexToAddress("0x0000000000000000000000000000000000000000"),
 			ChaiToken:           common.HexToAddress("0x0000000000000000000000000000000000000000"),
 			MaximumGasPrice:     common.HexToAddress("0x407b4128e9ecad8769b2332312a9f655cb9f5f3a"),
+			Multicall:           common.HexToAddress("0x53c43764255c17bd724f74c4ef150724ac50a3ed"),
 		}, nil
 	case 4:
 		return ContractAddresses{
@@ -73,6 +79,7 @@ func NewContractAddressesForChainID(chainID int) (ContractAddresses, error) {
 			ChaiBridge:          common.HexToAddress("0x0000000000000000000000000000000000000000"),
 			ChaiToken:           common.HexToAddress("0x0000000000000000000000000000000000000000"),
 			MaximumGasPrice:     common.HexToAddress("0x47697b44bd89051e93b4d5857ba8e024800a74ac"),
+			Multicall:           common.HexToAddress("0x42ad527de7d4e9d9d011ac45b31d8551f8fe9821"),
 		}, nil
 	case 42:
 		return ContractAddresses{
@@ -88,6 +95,7 @@ func NewContractAddressesForChainID(chainID int) (ContractAddresses, error) {
 			ChaiBridge:          common.HexToAddress("0x0000000000000000000000000000000000000000"),
 			ChaiToken:           common.HexToAddress("0x0000000000000000000000000000000000000000"),
 			MaximumGasPrice:     common.HexToAddress("0x67a094cf028221ffdd93fc658f963151d05e2a74"),
+			Multicall:           common.HexToAddress("0x2cc8688c5f75e365aaeeb4ea8d6a480405a48d2a"),
 		}, nil
 	case 1337:
 		return ganacheAddresses(), nil
@@ -103,9 +111,6 @@ func ValidateContractAddressesForChainID(chainID int, addresses ContractAddresse
 	if addresses.Exchange == constants.NullAddress {
 		return fmt.Errorf("cannot add contract addresses for chain ID %d: Exchange address is required", chainID)
 	}
-	if addresses.DevUtils == constants.NullAddress {
-		return fmt.Errorf("cannot add contract addresses for chain ID %d: DevUtils address is required", chainID)
-	}
 	if addresses.ERC20Proxy == constants.NullAddress {
 		return fmt.Errorf("cannot add contract addresses for chain ID %d: ERC20Proxy address is required", chainID)
 	}
@@ -0,0 +1,150 @@
+// Package ens resolves Ethereum Name Service (ENS) names to addresses.
+package ens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/0xProject/0x-mesh/constants"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// RegistryAddress is the address of the ENS registry. It is the same on
+// mainnet and the public testnets.
+var RegistryAddress = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+
+// registryABI is the ABI of the resolver method of the ENS registry.
+const registryABI = `[{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"resolver","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// resolverABI is the ABI of the addr method of ENS resolvers.
+const resolverABI = `[{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"addr","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// ErrNotFound is returned when an ENS name does not resolve to an address,
+// either because it has no resolver or because its resolver does not have an
+// address for it.
+var ErrNotFound = errors.New("ENS name does not resolve to an address")
+
+// IsName returns true if s looks like an ENS name (e.g. "maker.eth") rather
+// than a hex-encoded Ethereum address.
+func IsName(s string) bool {
+	if common.IsHexAddress(s) {
+		return false
+	}
+	labels := strings.Split(s, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || strings.ContainsAny(label, " \t\n,/:") {
+			return false
+		}
+	}
+	return true
+}
+
+// Normalize returns the normalized form of the given ENS name. Only
+// lower-casing is performed; names are expected to consist of characters
+// which are unaffected by the rest of UTS #46 normalization.
+func Normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Namehash returns the ENS namehash of the given (normalized) name as
+// specified by EIP-137.
+func Namehash(name string) common.Hash {
+	node := common.Hash{}
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		node = common.BytesToHash(crypto.Keccak256(node.Bytes(), labelHash))
+	}
+	return node
+}
+
+// Resolver resolves ENS names via read-only contract calls.
+type Resolver struct {
+	contractCaller  bind.ContractCaller
+	registryAddress common.Address
+	registryABI     abi.ABI
+	resolverABI     abi.ABI
+}
+
+// New returns a new Resolver which uses the ENS registry at RegistryAddress.
+func New(contractCaller bind.ContractCaller) (*Resolver, error) {
+	return NewWithRegistry(contractCaller, RegistryAddress)
+}
+
+// NewWithRegistry returns a new Resolver which uses the ENS registry at the
+// given address, e.g. a registry deployed to a development chain.
+func NewWithRegistry(contractCaller bind.ContractCaller, registryAddress common.Address) (*Resolver, error) {
+	parsedRegistryABI, err := abi.JSON(strings.NewReader(registryABI))
+	if err != nil {
+		return nil, err
+	}
+	parsedResolverABI, err := abi.JSON(strings.NewReader(resolverABI))
+	if err != nil {
+		return nil, err
+	}
+	return &Resolver{
+		contractCaller:  contractCaller,
+		registryAddress: registryAddress,
+		registryABI:     parsedRegistryABI,
+		resolverABI:     parsedResolverABI,
+	}, nil
+}
+
+// Resolve returns the address the given ENS name currently resolves to. It
+// returns ErrNotFound if the name does not resolve to an address.
+func (r *Resolver) Resolve(ctx context.Context, name string) (common.Address, error) {
+	node := Namehash(Normalize(name))
+	resolverAddress, err := r.callAddressMethod(ctx, r.registryAddress, r.registryABI, "resolver", node)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("could not look up ENS resolver for %q: %s", name, err.Error())
+	}
+	if resolverAddress == constants.NullAddress {
+		return common.Address{}, ErrNotFound
+	}
+	address, err := r.callAddressMethod(ctx, resolverAddress, r.resolverABI, "addr", node)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("could not resolve ENS name %q: %s", name, err.Error())
+	}
+	if address == constants.NullAddress {
+		return common.Address{}, ErrNotFound
+	}
+	return address, nil
+}
+
+// callAddressMethod calls a method which takes an ENS node and returns an
+// address.
+func (r *Resolver) callAddressMethod(ctx context.Context, contractAddress common.Address, contractABI abi.ABI, method string, node common.Hash) (common.Address, error) {
+	data, err := contractABI.Pack(method, [32]byte(node))
+	if err != nil {
+		return common.Address{}, err
+	}
+	output, err := r.contractCaller.CallContract(ctx, ethereum.CallMsg{
+		To:   &contractAddress,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(output) == 0 {
+		// The contract does not exist (e.g. there is no ENS registry on this
+		// chain) or does not implement the method.
+		return common.Address{}, ErrNotFound
+	}
+	var address common.Address
+	if err := contractABI.Unpack(&address, method, output); err != nil {
+		return common.Address{}, err
+	}
+	return address, nil
+}
@@ -0,0 +1,84 @@
+package ens
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	registryAddress = common.HexToAddress("0x1")
+	resolverAddress = common.HexToAddress("0x2")
+	makerAddress    = common.HexToAddress("0x6ecbe1db9ef729cbe972c83fb886247691fb6beb")
+)
+
+// fakeContractCaller is a bind.ContractCaller which implements a registry at
+// registryAddress and a resolver at resolverAddress. Only "maker.eth" resolves
+// to an address.
+type fakeContractCaller struct{}
+
+func (c *fakeContractCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (c *fakeContractCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	node := common.BytesToHash(call.Data[4:])
+	switch *call.To {
+	case registryAddress:
+		if node == Namehash("maker.eth") || node == Namehash("unset.eth") {
+			return common.LeftPadBytes(resolverAddress.Bytes(), 32), nil
+		}
+		return make([]byte, 32), nil
+	case resolverAddress:
+		if node == Namehash("maker.eth") {
+			return common.LeftPadBytes(makerAddress.Bytes(), 32), nil
+		}
+		return make([]byte, 32), nil
+	default:
+		return nil, nil
+	}
+}
+
+func TestNamehash(t *testing.T) {
+	// Test vectors from EIP-137.
+	assert.Equal(t, common.Hash{}, Namehash(""))
+	assert.Equal(t, common.HexToHash("0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae"), Namehash("eth"))
+	assert.Equal(t, common.HexToHash("0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f"), Namehash("foo.eth"))
+}
+
+func TestIsName(t *testing.T) {
+	assert.True(t, IsName("maker.eth"))
+	assert.True(t, IsName("desk.maker.eth"))
+	assert.False(t, IsName("0x6ecbe1db9ef729cbe972c83fb886247691fb6beb"))
+	assert.False(t, IsName("foo"))
+	assert.False(t, IsName("foo..eth"))
+	assert.False(t, IsName(""))
+}
+
+func TestResolve(t *testing.T) {
+	resolver, err := NewWithRegistry(&fakeContractCaller{}, registryAddress)
+	require.NoError(t, err)
+
+	address, err := resolver.Resolve(context.Background(), "Maker.eth")
+	require.NoError(t, err)
+	assert.Equal(t, makerAddress, address)
+
+	_, err = resolver.Resolve(context.Background(), "unset.eth")
+	assert.Equal(t, ErrNotFound, err, "name without an address")
+
+	_, err = resolver.Resolve(context.Background(), "unknown.eth")
+	assert.Equal(t, ErrNotFound, err, "name without a resolver")
+}
+
+func TestResolveWithoutRegistry(t *testing.T) {
+	resolver, err := NewWithRegistry(&fakeContractCaller{}, common.HexToAddress("0x3"))
+	require.NoError(t, err)
+
+	_, err = resolver.Resolve(context.Background(), "maker.eth")
+	assert.Equal(t, ErrNotFound, err)
+}
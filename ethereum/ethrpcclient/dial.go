@@ -0,0 +1,156 @@
+package ethrpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Transport is the transport used to connect to an Ethereum RPC endpoint.
+type Transport string
+
+// Transport values
+const (
+	TransportHTTP      Transport = "http"
+	TransportWebSocket Transport = "websocket"
+	TransportIPC       Transport = "ipc"
+)
+
+// TransportOptions tune the connection to an Ethereum RPC endpoint.
+type TransportOptions struct {
+	// DialTimeout is the maximum amount of time to wait for a connection to be
+	// established.
+	DialTimeout time.Duration
+	// KeepAlive is the interval between TCP keep-alive probes for HTTP
+	// connections. It has no effect for other transports.
+	KeepAlive time.Duration
+	// IdleConnTimeout is how long idle HTTP connections are kept open for
+	// re-use. It has no effect for other transports.
+	IdleConnTimeout time.Duration
+	// MaxIdleConns is the maximum number of idle HTTP connections kept open for
+	// re-use. It should be at least the number of concurrent requests in order
+	// to avoid opening a new connection for most requests. It has no effect
+	// for other transports.
+	MaxIdleConns int
+	// ProxyURL is the URL of a SOCKS5 proxy through which HTTP connections are
+	// made. If nil, the proxy configured in the environment (if any) is used.
+	// Proxies are not supported for WebSocket connections and have no effect
+	// for IPC connections.
+	ProxyURL *url.URL
+}
+
+// DefaultTransportOptions returns the default TransportOptions for the given
+// transport. IPC connections are local, so they fail fast if the node isn't
+// running.
+func DefaultTransportOptions(transport Transport) TransportOptions {
+	switch transport {
+	case TransportIPC:
+		return TransportOptions{
+			DialTimeout: 2 * time.Second,
+		}
+	case TransportHTTP:
+		return TransportOptions{
+			DialTimeout:     10 * time.Second,
+			KeepAlive:       30 * time.Second,
+			IdleConnTimeout: 90 * time.Second,
+			MaxIdleConns:    64,
+		}
+	default:
+		return TransportOptions{
+			DialTimeout: 10 * time.Second,
+		}
+	}
+}
+
+// ParseRPCURL determines the transport for the given Ethereum RPC URL and
+// returns the endpoint to dial. Supported URLs are http(s)://, ws(s)://,
+// ipc:// followed by the path of the IPC socket, and plain file paths (e.g.
+// "/home/user/.ethereum/geth.ipc"), which are also treated as IPC sockets.
+func ParseRPCURL(rawURL string) (Transport, string, error) {
+	if strings.HasPrefix(rawURL, "ipc://") {
+		path := strings.TrimPrefix(rawURL, "ipc://")
+		if path == "" {
+			return "", "", fmt.Errorf("missing IPC socket path in Ethereum RPC URL: %q", rawURL)
+		}
+		return TransportIPC, path, nil
+	}
+	if filepath.IsAbs(rawURL) || strings.HasSuffix(rawURL, ".ipc") {
+		return TransportIPC, rawURL, nil
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	switch parsed.Scheme {
+	case "http", "https":
+		return TransportHTTP, rawURL, nil
+	case "ws", "wss":
+		return TransportWebSocket, rawURL, nil
+	default:
+		return "", "", fmt.Errorf("unsupported Ethereum RPC URL scheme: %q (must be one of \"http\", \"https\", \"ws\", \"wss\" or \"ipc\")", parsed.Scheme)
+	}
+}
+
+// Dial connects to the Ethereum RPC endpoint at the given URL (see
+// ParseRPCURL) using the default TransportOptions for its transport.
+func Dial(ctx context.Context, rawURL string) (*rpc.Client, Transport, error) {
+	transport, _, err := ParseRPCURL(rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+	rpcClient, err := DialWithOptions(ctx, rawURL, DefaultTransportOptions(transport))
+	if err != nil {
+		return nil, "", err
+	}
+	return rpcClient, transport, nil
+}
+
+// DialWithOptions connects to the Ethereum RPC endpoint at the given URL (see
+// ParseRPCURL) using the given TransportOptions.
+func DialWithOptions(ctx context.Context, rawURL string, opts TransportOptions) (*rpc.Client, error) {
+	transport, endpoint, err := ParseRPCURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.DialTimeout)
+		defer cancel()
+	}
+	switch transport {
+	case TransportIPC:
+		return rpc.DialIPC(ctx, endpoint)
+	case TransportWebSocket:
+		if opts.ProxyURL != nil {
+			return nil, errors.New("proxies are not supported for WebSocket Ethereum RPC URLs (use an http:// or https:// URL instead)")
+		}
+		return rpc.DialWebsocket(ctx, endpoint, "")
+	default:
+		dialer := &net.Dialer{
+			Timeout:   opts.DialTimeout,
+			KeepAlive: opts.KeepAlive,
+		}
+		proxy := http.ProxyFromEnvironment
+		if opts.ProxyURL != nil {
+			proxy = http.ProxyURL(opts.ProxyURL)
+		}
+		httpClient := &http.Client{
+			Transport: &http.Transport{
+				Proxy:               proxy,
+				DialContext:         dialer.DialContext,
+				MaxIdleConns:        opts.MaxIdleConns,
+				MaxIdleConnsPerHost: opts.MaxIdleConns,
+				IdleConnTimeout:     opts.IdleConnTimeout,
+			},
+		}
+		return rpc.DialHTTPWithClient(endpoint, httpClient)
+	}
+}
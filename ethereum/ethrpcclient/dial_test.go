@@ -0,0 +1,48 @@
+package ethrpcclient
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRPCURL(t *testing.T) {
+	testCases := []struct {
+		rawURL            string
+		expectedTransport Transport
+		expectedEndpoint  string
+	}{
+		{"http://localhost:8545", TransportHTTP, "http://localhost:8545"},
+		{"https://mainnet.infura.io/v3/abc", TransportHTTP, "https://mainnet.infura.io/v3/abc"},
+		{"ws://localhost:8546", TransportWebSocket, "ws://localhost:8546"},
+		{"wss://mainnet.infura.io/ws/v3/abc", TransportWebSocket, "wss://mainnet.infura.io/ws/v3/abc"},
+		{"ipc:///root/.ethereum/geth.ipc", TransportIPC, "/root/.ethereum/geth.ipc"},
+		{"/root/.ethereum/geth.ipc", TransportIPC, "/root/.ethereum/geth.ipc"},
+		{"geth.ipc", TransportIPC, "geth.ipc"},
+	}
+	for _, testCase := range testCases {
+		transport, endpoint, err := ParseRPCURL(testCase.rawURL)
+		require.NoError(t, err, testCase.rawURL)
+		assert.Equal(t, testCase.expectedTransport, transport, testCase.rawURL)
+		assert.Equal(t, testCase.expectedEndpoint, endpoint, testCase.rawURL)
+	}
+}
+
+func TestParseRPCURLErrors(t *testing.T) {
+	for _, rawURL := range []string{"ipc://", "ftp://localhost:8545", "localhost:8545"} {
+		_, _, err := ParseRPCURL(rawURL)
+		assert.Error(t, err, rawURL)
+	}
+}
+
+func TestDialWithOptionsProxyWebSocket(t *testing.T) {
+	proxyURL, err := url.Parse("socks5://127.0.0.1:9050")
+	require.NoError(t, err)
+	opts := DefaultTransportOptions(TransportWebSocket)
+	opts.ProxyURL = proxyURL
+	_, err = DialWithOptions(context.Background(), "ws://localhost:8546", opts)
+	assert.Error(t, err)
+}
@@ -3,7 +3,6 @@ package ethrpcclient
 import (
 	"context"
 	"math/big"
-	"sync/atomic"
 	"time"
 
 	"github.com/0xProject/0x-mesh/ethereum/miniheader"
@@ -27,7 +26,8 @@ type Client interface {
 }
 
 // client is a Client through which _all_ Ethereum JSON-RPC requests should be routed through. It
-// enforces a max requestTimeout and also rate-limits requests
+// enforces a max requestTimeout, rate-limits requests and retries failed requests according to
+// its RetryPolicy.
 type client struct {
 	// rpcClient is the underlying RPC client or provider
 	rpcClient ethclient.RPCClient
@@ -36,19 +36,29 @@ type client struct {
 	client         *ethclient.Client
 	requestTimeout time.Duration
 	rateLimiter    ratelimit.RateLimiter
+	retryPolicy    RetryPolicy
+	circuitBreaker *circuitBreaker
 	// rateLimitDroppedRequests counts the number of requests that had their context cancelled or expire
 	// and were therefore never granted
 	rateLimitDroppedRequests int64
 }
 
-// New returns a new instance of client
+// New returns a new instance of client which never retries failed requests.
 func New(rpcClient ethclient.RPCClient, requestTimeout time.Duration, rateLimiter ratelimit.RateLimiter) (Client, error) {
+	return NewWithRetryPolicy(rpcClient, requestTimeout, rateLimiter, NoRetryPolicy)
+}
+
+// NewWithRetryPolicy returns a new instance of client which retries failed
+// requests according to the given RetryPolicy.
+func NewWithRetryPolicy(rpcClient ethclient.RPCClient, requestTimeout time.Duration, rateLimiter ratelimit.RateLimiter, retryPolicy RetryPolicy) (Client, error) {
 	ethClient := ethclient.NewClient(rpcClient)
 	return &client{
 		client:         ethClient,
 		rpcClient:      rpcClient,
 		requestTimeout: requestTimeout,
 		rateLimiter:    rateLimiter,
+		retryPolicy:    retryPolicy,
+		circuitBreaker: newCircuitBreaker(retryPolicy.CircuitBreakerThreshold, retryPolicy.CircuitBreakerCooldown),
 	}, nil
 }
 
@@ -58,31 +68,20 @@ func New(rpcClient ethclient.RPCClient, requestTimeout time.Duration, rateLimite
 // The result must be a pointer so that package json can unmarshal into it. You
 // can also pass nil, in which case the result is ignored.
 func (ec *client) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
-	err := ec.rateLimiter.Wait(ctx)
-	if err != nil {
-		atomic.AddInt64(&ec.rateLimitDroppedRequests, 1)
-		// Context cancelled or deadline exceeded
-		return err
-	}
-
-	ctx, cancel := context.WithTimeout(ctx, ec.requestTimeout)
-	defer cancel()
-	return ec.rpcClient.CallContext(ctx, &result, method, args...)
+	return ec.withRetries(ctx, method, func(ctx context.Context) error {
+		return ec.rpcClient.CallContext(ctx, &result, method, args...)
+	})
 }
 
 // HeaderByHash fetches a block header by its block hash. If no block exists with this number it will return
 // a `ethereum.NotFound` error.
 func (ec *client) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
-	err := ec.rateLimiter.Wait(ctx)
-	if err != nil {
-		atomic.AddInt64(&ec.rateLimitDroppedRequests, 1)
-		// Context cancelled or deadline exceeded
-		return nil, err
-	}
-
-	ctx, cancel := context.WithTimeout(ctx, ec.requestTimeout)
-	defer cancel()
-	header, err := ec.client.HeaderByHash(ctx, hash)
+	var header *types.Header
+	err := ec.withRetries(ctx, "eth_getBlockByHash", func(ctx context.Context) error {
+		var err error
+		header, err = ec.client.HeaderByHash(ctx, hash)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -90,14 +89,12 @@ func (ec *client) HeaderByHash(ctx context.Context, hash common.Hash) (*types.He
 }
 
 func (ec *client) HeaderByNumber(ctx context.Context, number *big.Int) (*miniheader.MiniHeader, error) {
-	err := ec.rateLimiter.Wait(ctx)
-	if err != nil {
-		atomic.AddInt64(&ec.rateLimitDroppedRequests, 1)
-		// Context cancelled or deadline exceeded
-		return nil, err
-	}
-
-	header, err := ec.client.HeaderByNumber(ctx, number)
+	var header *types.Header
+	err := ec.withRetries(ctx, "eth_getBlockByNumber", func(ctx context.Context) error {
+		var err error
+		header, err = ec.client.HeaderByNumber(ctx, number)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -113,44 +110,40 @@ func (ec *client) HeaderByNumber(ctx context.Context, number *big.Int) (*minihea
 // CodeAt returns the code of the given account. This is needed to differentiate
 // between contract internal errors and the local chain being out of sync.
 func (ec *client) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
-	err := ec.rateLimiter.Wait(ctx)
+	var code []byte
+	err := ec.withRetries(ctx, "eth_getCode", func(ctx context.Context) error {
+		var err error
+		code, err = ec.client.CodeAt(ctx, contract, blockNumber)
+		return err
+	})
 	if err != nil {
-		atomic.AddInt64(&ec.rateLimitDroppedRequests, 1)
-		// Context cancelled or deadline exceeded
 		return []byte{}, err
 	}
-
-	ctx, cancel := context.WithTimeout(ctx, ec.requestTimeout)
-	defer cancel()
-	return ec.client.CodeAt(ctx, contract, blockNumber)
+	return code, nil
 }
 
 // CallContract executes an Ethereum contract call with the specified data as the input.
 func (ec *client) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
-	err := ec.rateLimiter.Wait(ctx)
+	var result []byte
+	err := ec.withRetries(ctx, "eth_call", func(ctx context.Context) error {
+		var err error
+		result, err = ec.client.CallContract(ctx, call, blockNumber)
+		return err
+	})
 	if err != nil {
-		atomic.AddInt64(&ec.rateLimitDroppedRequests, 1)
-		// Context cancelled or deadline exceeded
 		return []byte{}, err
 	}
-
-	ctx, cancel := context.WithTimeout(ctx, ec.requestTimeout)
-	defer cancel()
-	return ec.client.CallContract(ctx, call, blockNumber)
+	return result, nil
 }
 
 // FilterLogs returns the logs that satisfy the supplied filter query.
 func (ec *client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
-	err := ec.rateLimiter.Wait(ctx)
-	if err != nil {
-		atomic.AddInt64(&ec.rateLimitDroppedRequests, 1)
-		// Context cancelled or deadline exceeded
-		return nil, err
-	}
-
-	ctx, cancel := context.WithTimeout(ctx, ec.requestTimeout)
-	defer cancel()
-	logs, err := ec.client.FilterLogs(ctx, q)
+	var logs []types.Log
+	err := ec.withRetries(ctx, "eth_getLogs", func(ctx context.Context) error {
+		var err error
+		logs, err = ec.client.FilterLogs(ctx, q)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,327 @@
+package ethrpcclient
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrCircuitOpen is returned when requests to the Ethereum RPC endpoint are
+// not attempted because too many consecutive requests failed recently.
+var ErrCircuitOpen = errors.New("Ethereum RPC endpoint is temporarily unavailable (circuit breaker open)")
+
+// ErrorClass classifies errors returned by Ethereum RPC endpoints in order to
+// determine whether a failed request should be retried.
+type ErrorClass int
+
+// ErrorClass values
+const (
+	// ErrorClassPermanent errors are not retried. This includes reverted
+	// calls, missing blocks, invalid requests and unknown errors.
+	ErrorClassPermanent ErrorClass = iota
+	// ErrorClassTransient errors (e.g. timeouts, connection errors and 5xx
+	// responses) are retried.
+	ErrorClassTransient
+	// ErrorClassRateLimited errors are returned when the provider (e.g. Infura
+	// or Alchemy) rejects a request because of its rate limits. They are
+	// retried with a longer back-off.
+	ErrorClassRateLimited
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassTransient:
+		return "transient"
+	case ErrorClassRateLimited:
+		return "rate_limited"
+	default:
+		return "permanent"
+	}
+}
+
+// rateLimitedErrorSubstrings are (lowercase) substrings of error messages
+// returned by common providers when a request is rate limited.
+var rateLimitedErrorSubstrings = []string{
+	"429",
+	"too many requests",
+	"rate limit",
+	"request rate exceeded",           // Infura
+	"daily request count exceeded",    // Infura
+	"project id request rate",         // Infura
+	"exceeded its compute units",      // Alchemy
+	"compute units per second",        // Alchemy
+	"monthly capacity limit exceeded", // Alchemy
+}
+
+// transientErrorSubstrings are (lowercase) substrings of error messages which
+// indicate that a request might succeed if it is retried.
+var transientErrorSubstrings = []string{
+	"timeout",
+	"timed out",
+	"connection refused",
+	"connection reset",
+	"broken pipe",
+	"no such host",
+	"eof",
+	"500 internal server error",
+	"502 bad gateway",
+	"503 service unavailable",
+	"504 gateway timeout",
+	"header not found", // Returned by load-balanced nodes which lag behind.
+	"internal error",
+}
+
+// permanentErrorSubstrings are (lowercase) substrings of error messages which
+// indicate that retrying the request won't help. They take precedence over
+// the other classes, e.g. "execution reverted: timeout" is permanent.
+var permanentErrorSubstrings = []string{
+	"revert",
+	"vm execution error",
+	"invalid opcode",
+	"out of gas",
+	"invalid argument",
+	"method not found",
+}
+
+// errorCoder is implemented by JSON-RPC errors.
+type errorCoder interface {
+	ErrorCode() int
+}
+
+// JSON-RPC error codes with a special meaning.
+const (
+	// jsonRPCLimitExceededCode is returned by Infura and others when a request
+	// exceeds a rate limit.
+	jsonRPCLimitExceededCode = -32005
+	// jsonRPCTooManyRequestsCode is returned by some providers instead of an
+	// HTTP 429 response.
+	jsonRPCTooManyRequestsCode = 429
+)
+
+// ClassifyError determines whether a request which failed with the given error
+// should be retried.
+func ClassifyError(err error) ErrorClass {
+	if err == nil || err == ethereum.NotFound || err == context.Canceled || err == ErrCircuitOpen {
+		return ErrorClassPermanent
+	}
+	if err == context.DeadlineExceeded {
+		return ErrorClassTransient
+	}
+	if coder, ok := err.(errorCoder); ok {
+		switch coder.ErrorCode() {
+		case jsonRPCLimitExceededCode, jsonRPCTooManyRequestsCode:
+			return ErrorClassRateLimited
+		}
+	}
+	message := strings.ToLower(err.Error())
+	for _, substring := range permanentErrorSubstrings {
+		if strings.Contains(message, substring) {
+			return ErrorClassPermanent
+		}
+	}
+	for _, substring := range rateLimitedErrorSubstrings {
+		if strings.Contains(message, substring) {
+			return ErrorClassRateLimited
+		}
+	}
+	for _, substring := range transientErrorSubstrings {
+		if strings.Contains(message, substring) {
+			return ErrorClassTransient
+		}
+	}
+	return ErrorClassPermanent
+}
+
+// RetryPolicy determines how failed requests to the Ethereum RPC endpoint are
+// retried.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a failed request is retried.
+	// Requests are never retried if it is 0.
+	MaxRetries int
+	// MinBackoff is the delay before the first retry. It is doubled for each
+	// subsequent retry.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay between retries.
+	MaxBackoff time.Duration
+	// RateLimitedBackoffFactor multiplies the delay before retrying requests
+	// which were rate limited by the provider.
+	RateLimitedBackoffFactor int
+	// CircuitBreakerThreshold is the number of consecutive failed requests
+	// after which the circuit breaker opens and further requests fail
+	// immediately with ErrCircuitOpen. The circuit breaker is disabled if it
+	// is 0.
+	CircuitBreakerThreshold int
+	// CircuitBreakerCooldown is how long the circuit breaker stays open. After
+	// the cooldown a single request is let through; if it succeeds the
+	// circuit breaker closes again.
+	CircuitBreakerCooldown time.Duration
+}
+
+// NoRetryPolicy never retries failed requests.
+var NoRetryPolicy = RetryPolicy{}
+
+// backoff returns the delay before the given retry (starting at 1). The delay
+// is randomly reduced by up to 50% (jitter) so that clients don't retry in
+// lockstep.
+func (p RetryPolicy) backoff(retry int, class ErrorClass) time.Duration {
+	d := p.MinBackoff
+	for i := 1; i < retry && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if class == ErrorClassRateLimited && p.RateLimitedBackoffFactor > 1 {
+		d *= time.Duration(p.RateLimitedBackoffFactor)
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// circuitState enumerates the states of a circuitBreaker.
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker stops requests to an endpoint after too many consecutive
+// failures, giving it time to recover.
+type circuitBreaker struct {
+	mu                  sync.Mutex
+	threshold           int
+	cooldown            time.Duration
+	state               circuitState
+	consecutiveFailures int
+	openedAt            time.Time
+	now                 func() time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow returns whether a request may be made.
+func (cb *circuitBreaker) allow() bool {
+	if cb.threshold <= 0 {
+		return true
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state {
+	case circuitOpen:
+		if cb.now().Sub(cb.openedAt) < cb.cooldown {
+			return false
+		}
+		// Let a single request through to probe the endpoint.
+		cb.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// record records the outcome of a request. Only errors which indicate that
+// the endpoint is unhealthy count as failures.
+func (cb *circuitBreaker) record(class ErrorClass, failed bool) {
+	if cb.threshold <= 0 {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if !failed || class == ErrorClassPermanent {
+		if cb.state == circuitHalfOpen {
+			log.Info("Ethereum RPC endpoint recovered; closing circuit breaker")
+		}
+		cb.state = circuitClosed
+		cb.consecutiveFailures = 0
+		return
+	}
+	cb.consecutiveFailures++
+	if cb.state == circuitHalfOpen || cb.consecutiveFailures >= cb.threshold {
+		if cb.state != circuitOpen {
+			log.WithFields(log.Fields{
+				"consecutiveFailures": cb.consecutiveFailures,
+				"cooldown":            cb.cooldown,
+			}).Warn("too many failed Ethereum RPC requests; opening circuit breaker")
+		}
+		cb.state = circuitOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+// abort is called if a request which was allowed is not made or its outcome
+// says nothing about the health of the endpoint (e.g. because the caller's
+// context is done). If the request was the probe of a half-open circuit
+// breaker, the next request becomes the probe instead.
+func (cb *circuitBreaker) abort() {
+	if cb.threshold <= 0 {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == circuitHalfOpen {
+		cb.state = circuitOpen
+	}
+}
+
+// withRetries calls fn until it succeeds, fails with an error which should not
+// be retried, or the retry policy is exhausted. method is the JSON-RPC method
+// called by fn, which determines the cost of the request for the rate limiter.
+func (ec *client) withRetries(ctx context.Context, method string, fn func(ctx context.Context) error) error {
+	for retry := 0; ; retry++ {
+		if !ec.circuitBreaker.allow() {
+			return ErrCircuitOpen
+		}
+		err := ec.rateLimiter.WaitForMethod(ctx, method)
+		if err != nil {
+			ec.circuitBreaker.abort()
+			atomic.AddInt64(&ec.rateLimitDroppedRequests, 1)
+			// Context cancelled or deadline exceeded
+			return err
+		}
+		requestCtx, cancel := context.WithTimeout(ctx, ec.requestTimeout)
+		err = fn(requestCtx)
+		cancel()
+		class := ClassifyError(err)
+		if err != nil && ctx.Err() != nil {
+			// The caller's context is done, so the error is not the endpoint's
+			// fault.
+			ec.circuitBreaker.abort()
+			return err
+		}
+		ec.circuitBreaker.record(class, err != nil)
+		if err == nil || class == ErrorClassPermanent || retry >= ec.retryPolicy.MaxRetries {
+			return err
+		}
+		d := ec.retryPolicy.backoff(retry+1, class)
+		log.WithFields(log.Fields{
+			"error":      err.Error(),
+			"errorClass": class.String(),
+			"retry":      retry + 1,
+			"backoff":    d,
+		}).Debug("retrying failed Ethereum RPC request")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	}
+}
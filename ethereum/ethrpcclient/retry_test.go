@@ -0,0 +1,94 @@
+package ethrpcclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/stretchr/testify/assert"
+)
+
+type codedError struct {
+	code int
+}
+
+func (e codedError) Error() string {
+	return "limit exceeded"
+}
+
+func (e codedError) ErrorCode() int {
+	return e.code
+}
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected ErrorClass
+	}{
+		{ethereum.NotFound, ErrorClassPermanent},
+		{context.Canceled, ErrorClassPermanent},
+		{context.DeadlineExceeded, ErrorClassTransient},
+		{errors.New("execution reverted"), ErrorClassPermanent},
+		{errors.New("VM execution error."), ErrorClassPermanent},
+		{errors.New("dial tcp 127.0.0.1:8545: connect: connection refused"), ErrorClassTransient},
+		{errors.New("502 Bad Gateway: <html></html>"), ErrorClassTransient},
+		{errors.New("429 Too Many Requests: {\"jsonrpc\":\"2.0\",\"error\":{\"code\":-32005}}"), ErrorClassRateLimited},
+		{errors.New("daily request count exceeded, request rate limited"), ErrorClassRateLimited},
+		{errors.New("Your app has exceeded its compute units per second capacity"), ErrorClassRateLimited},
+		{codedError{code: -32005}, ErrorClassRateLimited},
+		{errors.New("something unexpected"), ErrorClassPermanent},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, ClassifyError(testCase.err), testCase.err.Error())
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	cb := newCircuitBreaker(2, time.Minute)
+	cb.now = func() time.Time { return now }
+
+	// Permanent errors don't count as failures.
+	cb.record(ErrorClassPermanent, true)
+	cb.record(ErrorClassPermanent, true)
+	assert.True(t, cb.allow())
+
+	// The circuit breaker opens after 2 consecutive failures.
+	cb.record(ErrorClassTransient, true)
+	assert.True(t, cb.allow())
+	cb.record(ErrorClassRateLimited, true)
+	assert.False(t, cb.allow())
+
+	// After the cooldown, a single probe is let through.
+	now = now.Add(time.Minute)
+	assert.True(t, cb.allow())
+	assert.False(t, cb.allow())
+
+	// If the probe fails, the circuit breaker opens again.
+	cb.record(ErrorClassTransient, true)
+	assert.False(t, cb.allow())
+
+	// If the probe succeeds, the circuit breaker closes.
+	now = now.Add(time.Minute)
+	assert.True(t, cb.allow())
+	cb.record(ErrorClassPermanent, false)
+	assert.True(t, cb.allow())
+	assert.True(t, cb.allow())
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		MinBackoff:               100 * time.Millisecond,
+		MaxBackoff:               time.Second,
+		RateLimitedBackoffFactor: 4,
+	}
+	for retry := 1; retry <= 5; retry++ {
+		d := policy.backoff(retry, ErrorClassTransient)
+		assert.True(t, d <= time.Second, "backoff should not exceed MaxBackoff")
+		assert.True(t, d >= 50*time.Millisecond, "backoff should be at least half of MinBackoff")
+	}
+	d := policy.backoff(1, ErrorClassRateLimited)
+	assert.True(t, d >= 200*time.Millisecond, "rate limited requests should back off longer")
+}
@@ -14,7 +14,11 @@ type MiniHeader struct {
 	Parent    common.Hash
 	Number    *big.Int
 	Timestamp time.Time
-	Logs      []types.Log
+	// BaseFee is the base fee per gas of the block, as introduced by EIP-1559
+	// in the London hard fork. It is nil for blocks mined before London and on
+	// chains which don't support EIP-1559.
+	BaseFee *big.Int
+	Logs    []types.Log
 }
 
 // ID returns the MiniHeader's ID
@@ -0,0 +1,221 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xProject/0x-mesh/constants"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrRawCallsNotSupported is returned by Batcher.CallContext if the underlying
+// contract caller does not support making raw JSON-RPC requests.
+var ErrRawCallsNotSupported = errors.New("the underlying Ethereum RPC client does not support raw JSON-RPC requests")
+
+// rawCaller is implemented by Ethereum RPC clients which can make raw
+// JSON-RPC requests, such as ethrpcclient.Client.
+type rawCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// BatcherConfig configures a Batcher.
+type BatcherConfig struct {
+	// ContractCaller is used to make the aggregated calls as well as any calls
+	// which cannot be batched.
+	ContractCaller bind.ContractCaller
+	// MulticallAddress is the address of the Multicall contract. It is
+	// required.
+	MulticallAddress common.Address
+	// Window is how long the Batcher waits for more calls after receiving the
+	// first call of a batch.
+	Window time.Duration
+	// MaxCallsPerBatch is the maximum number of calls in a batch. A batch is
+	// sent as soon as it is full.
+	MaxCallsPerBatch int
+	// MaxCallDataBytesPerBatch is the maximum total size of the call data of
+	// the calls in a batch. It keeps aggregated requests below the maximum
+	// request size of the Ethereum RPC endpoint.
+	MaxCallDataBytesPerBatch int
+}
+
+// BatcherStats contains stats about the calls made through a Batcher.
+type BatcherStats struct {
+	// NumCalls is the number of calls which were made through the Batcher.
+	NumCalls int64
+	// NumBatches is the number of batches the calls were coalesced into.
+	NumBatches int64
+	// NumUnbatchedCalls is the number of calls which could not be batched,
+	// e.g. because they specified a sender, value or gas price.
+	NumUnbatchedCalls int64
+}
+
+// Batcher is a bind.ContractCaller which coalesces read-only calls made within
+// a short window into aggregated Multicall requests in order to reduce the
+// number of Ethereum RPC requests. Calls are grouped by block number. Calls
+// which specify a sender, send ether or specify gas parameters are passed
+// through unchanged, since the Multicall contract makes all calls itself.
+type Batcher struct {
+	contractCaller           bind.ContractCaller
+	caller                   *Caller
+	window                   time.Duration
+	maxCallsPerBatch         int
+	maxCallDataBytesPerBatch int
+	mu                       sync.Mutex
+	pendingBatches           map[string]*batch
+	numCalls                 int64
+	numBatches               int64
+	numUnbatchedCalls        int64
+}
+
+// batch is a set of calls at the same block number which are waiting to be
+// sent.
+type batch struct {
+	key           string
+	blockNumber   *big.Int
+	calls         []Call
+	waiters       []chan Result
+	callDataBytes int
+	timer         *time.Timer
+}
+
+// NewBatcher returns a new Batcher.
+func NewBatcher(config BatcherConfig) (*Batcher, error) {
+	if config.MulticallAddress == constants.NullAddress {
+		return nil, errors.New("the Multicall contract address is required for batching contract calls")
+	}
+	if config.MaxCallsPerBatch <= 0 || config.MaxCallsPerBatch > maxCallsPerAggregate {
+		config.MaxCallsPerBatch = maxCallsPerAggregate
+	}
+	caller, err := New(config.ContractCaller, config.MulticallAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &Batcher{
+		contractCaller:           config.ContractCaller,
+		caller:                   caller,
+		window:                   config.Window,
+		maxCallsPerBatch:         config.MaxCallsPerBatch,
+		maxCallDataBytesPerBatch: config.MaxCallDataBytesPerBatch,
+		pendingBatches:           map[string]*batch{},
+	}, nil
+}
+
+// CodeAt implements bind.ContractCaller. It is passed through to the
+// underlying contract caller.
+func (b *Batcher) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return b.contractCaller.CodeAt(ctx, contract, blockNumber)
+}
+
+// CallContext is passed through to the underlying contract caller so that the
+// Batcher can be used wherever raw JSON-RPC requests are needed.
+func (b *Batcher) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	client, ok := b.contractCaller.(rawCaller)
+	if !ok {
+		return ErrRawCallsNotSupported
+	}
+	return client.CallContext(ctx, result, method, args...)
+}
+
+// CallContract implements bind.ContractCaller. The call is added to the
+// pending batch for the given block number and the method blocks until the
+// batch has been sent or the context is done.
+func (b *Batcher) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	atomic.AddInt64(&b.numCalls, 1)
+	if call.To == nil || call.From != (common.Address{}) || call.Value != nil || call.GasPrice != nil || call.Gas != 0 {
+		atomic.AddInt64(&b.numUnbatchedCalls, 1)
+		return b.contractCaller.CallContract(ctx, call, blockNumber)
+	}
+
+	resultChan := b.enqueue(Call{Target: *call.To, CallData: call.Data}, blockNumber)
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-resultChan:
+		return result.ReturnData, result.Err
+	}
+}
+
+// Stats returns stats about the calls made through the Batcher.
+func (b *Batcher) Stats() BatcherStats {
+	return BatcherStats{
+		NumCalls:          atomic.LoadInt64(&b.numCalls),
+		NumBatches:        atomic.LoadInt64(&b.numBatches),
+		NumUnbatchedCalls: atomic.LoadInt64(&b.numUnbatchedCalls),
+	}
+}
+
+func (b *Batcher) enqueue(call Call, blockNumber *big.Int) chan Result {
+	// Buffered so that sending the result never blocks, even if the caller has
+	// stopped waiting.
+	resultChan := make(chan Result, 1)
+	key := "latest"
+	if blockNumber != nil {
+		key = blockNumber.String()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	pending, found := b.pendingBatches[key]
+	if found && b.maxCallDataBytesPerBatch > 0 && pending.callDataBytes+len(call.CallData) > b.maxCallDataBytesPerBatch {
+		// The call doesn't fit into the pending batch, so send it now and start
+		// a new one.
+		b.detach(pending)
+		go b.send(pending)
+		found = false
+	}
+	if !found {
+		pending = &batch{
+			key:         key,
+			blockNumber: blockNumber,
+		}
+		b.pendingBatches[key] = pending
+		pending.timer = time.AfterFunc(b.window, func() {
+			b.mu.Lock()
+			sendNow := b.pendingBatches[key] == pending
+			if sendNow {
+				b.detach(pending)
+			}
+			b.mu.Unlock()
+			if sendNow {
+				b.send(pending)
+			}
+		})
+	}
+	pending.calls = append(pending.calls, call)
+	pending.waiters = append(pending.waiters, resultChan)
+	pending.callDataBytes += len(call.CallData)
+	if len(pending.calls) >= b.maxCallsPerBatch {
+		b.detach(pending)
+		go b.send(pending)
+	}
+	return resultChan
+}
+
+// detach removes the batch from the pending batches so that no more calls
+// are added to it. b.mu must be held.
+func (b *Batcher) detach(pending *batch) {
+	pending.timer.Stop()
+	if b.pendingBatches[pending.key] == pending {
+		delete(b.pendingBatches, pending.key)
+	}
+}
+
+// send sends the calls of the batch and delivers the results. Since the calls
+// are shared by multiple callers, they are not bound to any caller's context.
+func (b *Batcher) send(pending *batch) {
+	atomic.AddInt64(&b.numBatches, 1)
+	results, err := b.caller.Call(context.Background(), pending.calls, pending.blockNumber)
+	for i, waiter := range pending.waiters {
+		if err != nil {
+			waiter <- Result{Err: err}
+			continue
+		}
+		waiter <- results[i]
+	}
+}
@@ -0,0 +1,109 @@
+package multicall
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatcherCoalescesCalls(t *testing.T) {
+	contractCaller := &echoContractCaller{}
+	batcher, err := NewBatcher(BatcherConfig{
+		ContractCaller:   contractCaller,
+		MulticallAddress: multicallAddress,
+		Window:           50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	numCalls := 3
+	wg := &sync.WaitGroup{}
+	for i := 0; i < numCalls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			target := targetAddress
+			returnData, err := batcher.CallContract(context.Background(), ethereum.CallMsg{
+				To:   &target,
+				Data: []byte{byte(i)},
+			}, nil)
+			require.NoError(t, err)
+			assert.Equal(t, []byte{byte(i)}, returnData)
+		}(i)
+	}
+	wg.Wait()
+
+	stats := batcher.Stats()
+	assert.Equal(t, int64(numCalls), stats.NumCalls)
+	assert.Equal(t, int64(1), stats.NumBatches)
+	assert.Equal(t, int64(0), stats.NumUnbatchedCalls)
+}
+
+func TestBatcherPassesThroughCallsWithValue(t *testing.T) {
+	contractCaller := &echoContractCaller{}
+	batcher, err := NewBatcher(BatcherConfig{
+		ContractCaller:   contractCaller,
+		MulticallAddress: multicallAddress,
+		Window:           time.Hour,
+	})
+	require.NoError(t, err)
+
+	target := targetAddress
+	returnData, err := batcher.CallContract(context.Background(), ethereum.CallMsg{
+		To:    &target,
+		Data:  []byte{0x1},
+		Value: big.NewInt(1),
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x1}, returnData)
+	assert.Equal(t, int64(1), batcher.Stats().NumUnbatchedCalls)
+	assert.Equal(t, 1, contractCaller.numCalls)
+}
+
+func TestBatcherPassesThroughCallsWithSender(t *testing.T) {
+	contractCaller := &echoContractCaller{}
+	batcher, err := NewBatcher(BatcherConfig{
+		ContractCaller:   contractCaller,
+		MulticallAddress: multicallAddress,
+		Window:           time.Hour,
+	})
+	require.NoError(t, err)
+
+	target := targetAddress
+	returnData, err := batcher.CallContract(context.Background(), ethereum.CallMsg{
+		From: common.HexToAddress("0x3"),
+		To:   &target,
+		Data: []byte{0x1},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x1}, returnData)
+	assert.Equal(t, int64(1), batcher.Stats().NumUnbatchedCalls)
+	assert.Equal(t, 1, contractCaller.numCalls)
+}
+
+func TestBatcherSendsFullBatchImmediately(t *testing.T) {
+	contractCaller := &echoContractCaller{}
+	batcher, err := NewBatcher(BatcherConfig{
+		ContractCaller:   contractCaller,
+		MulticallAddress: multicallAddress,
+		Window:           time.Hour,
+		MaxCallsPerBatch: 1,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	target := targetAddress
+	returnData, err := batcher.CallContract(ctx, ethereum.CallMsg{
+		To:   &target,
+		Data: []byte{0x1},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x1}, returnData)
+}
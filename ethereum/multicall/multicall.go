@@ -0,0 +1,158 @@
+// Package multicall batches read-only contract calls into a single eth_call
+// via the MakerDAO Multicall contract.
+package multicall
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/0xProject/0x-mesh/constants"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// multicallABI is the ABI of the aggregate method of the Multicall contract.
+const multicallABI = `[{"constant":false,"inputs":[{"components":[{"name":"target","type":"address"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate","outputs":[{"name":"blockNumber","type":"uint256"},{"name":"returnData","type":"bytes[]"}],"payable":false,"stateMutability":"nonpayable","type":"function"}]`
+
+// maxCallsPerAggregate is the maximum number of calls aggregated into a single
+// eth_call. It keeps requests well below the gas limit of eth_call and the
+// maximum request size of most Ethereum RPC endpoints.
+const maxCallsPerAggregate = 200
+
+// Call is a single read-only contract call.
+type Call struct {
+	Target   common.Address
+	CallData []byte
+}
+
+// Result is the result of a single Call. If the call failed (e.g. because it
+// reverted), Err is set.
+type Result struct {
+	ReturnData []byte
+	Err        error
+}
+
+// Caller makes batches of read-only contract calls. If a Multicall contract
+// address is configured, calls are aggregated into as few eth_call requests
+// as possible. If an aggregated call fails (e.g. because one of the calls
+// reverted), the calls are split in half and retried until the failing calls
+// are found. Without a Multicall contract address, the calls are made
+// individually.
+type Caller struct {
+	contractCaller bind.ContractCaller
+	address        common.Address
+	abi            abi.ABI
+}
+
+// New returns a new Caller. The address of the Multicall contract may be the
+// null address, in which case every call is made individually.
+func New(contractCaller bind.ContractCaller, address common.Address) (*Caller, error) {
+	parsed, err := abi.JSON(strings.NewReader(multicallABI))
+	if err != nil {
+		return nil, err
+	}
+	return &Caller{
+		contractCaller: contractCaller,
+		address:        address,
+		abi:            parsed,
+	}, nil
+}
+
+// Call makes the given calls at the given block number (or the latest block
+// if blockNumber is nil) and returns a result for each call, in order. An
+// error is only returned if the calls could not be made at all, e.g. because
+// the context was cancelled.
+func (c *Caller) Call(ctx context.Context, calls []Call, blockNumber *big.Int) ([]Result, error) {
+	results := make([]Result, 0, len(calls))
+	for len(calls) > 0 {
+		chunkSize := maxCallsPerAggregate
+		if len(calls) < chunkSize {
+			chunkSize = len(calls)
+		}
+		chunkResults, err := c.callChunk(ctx, calls[:chunkSize], blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, chunkResults...)
+		calls = calls[chunkSize:]
+	}
+	return results, nil
+}
+
+func (c *Caller) callChunk(ctx context.Context, calls []Call, blockNumber *big.Int) ([]Result, error) {
+	if c.address == constants.NullAddress || len(calls) == 1 {
+		return c.callIndividually(ctx, calls, blockNumber)
+	}
+	returnData, err := c.aggregate(ctx, calls, blockNumber)
+	if err == nil {
+		results := make([]Result, len(returnData))
+		for i, data := range returnData {
+			results[i] = Result{ReturnData: data}
+		}
+		return results, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	// The Multicall contract reverts if any of the calls reverts. Bisecting the
+	// calls finds a single reverting call with O(log n) additional requests
+	// instead of making all n calls individually.
+	log.WithFields(log.Fields{
+		"error":    err.Error(),
+		"numCalls": len(calls),
+	}).Debug("aggregated Multicall request failed; splitting the calls in half")
+	middle := len(calls) / 2
+	firstResults, err := c.callChunk(ctx, calls[:middle], blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	secondResults, err := c.callChunk(ctx, calls[middle:], blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return append(firstResults, secondResults...), nil
+}
+
+// callIndividually makes each of the given calls in its own eth_call request.
+func (c *Caller) callIndividually(ctx context.Context, calls []Call, blockNumber *big.Int) ([]Result, error) {
+	results := make([]Result, len(calls))
+	for i, call := range calls {
+		target := call.Target
+		returnData, err := c.contractCaller.CallContract(ctx, ethereum.CallMsg{
+			To:   &target,
+			Data: call.CallData,
+		}, blockNumber)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		results[i] = Result{ReturnData: returnData, Err: err}
+	}
+	return results, nil
+}
+
+func (c *Caller) aggregate(ctx context.Context, calls []Call, blockNumber *big.Int) ([][]byte, error) {
+	data, err := c.abi.Pack("aggregate", calls)
+	if err != nil {
+		return nil, err
+	}
+	address := c.address
+	output, err := c.contractCaller.CallContract(ctx, ethereum.CallMsg{
+		To:   &address,
+		Data: data,
+	}, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		BlockNumber *big.Int
+		ReturnData  [][]byte
+	}
+	if err := c.abi.Unpack(&result, "aggregate", output); err != nil {
+		return nil, err
+	}
+	return result.ReturnData, nil
+}
@@ -0,0 +1,131 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	multicallAddress = common.HexToAddress("0x1")
+	targetAddress    = common.HexToAddress("0x2")
+)
+
+// echoContractCaller is a bind.ContractCaller which echoes the call data of
+// calls to targetAddress and reverts for calls with the call data 0xff. Calls
+// to multicallAddress always revert.
+type echoContractCaller struct {
+	numCalls int
+}
+
+func (c *echoContractCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (c *echoContractCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.numCalls++
+	if *call.To == multicallAddress {
+		return nil, errors.New("execution reverted")
+	}
+	if len(call.Data) == 1 && call.Data[0] == 0xff {
+		return nil, errors.New("execution reverted")
+	}
+	return call.Data, nil
+}
+
+// multicallContractCaller is a bind.ContractCaller which behaves like
+// echoContractCaller for calls to targetAddress and implements the aggregate
+// method of the Multicall contract for calls to multicallAddress, which
+// reverts if any of the aggregated calls reverts.
+type multicallContractCaller struct {
+	echoContractCaller
+	abi abi.ABI
+}
+
+func (c *multicallContractCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if *call.To != multicallAddress {
+		return c.echoContractCaller.CallContract(ctx, call, blockNumber)
+	}
+	c.numCalls++
+	method := c.abi.Methods["aggregate"]
+	var input struct {
+		Calls []Call
+	}
+	if err := method.Inputs.Unpack(&input, call.Data[4:]); err != nil {
+		return nil, err
+	}
+	returnData := make([][]byte, len(input.Calls))
+	for i, aggregatedCall := range input.Calls {
+		if len(aggregatedCall.CallData) == 1 && aggregatedCall.CallData[0] == 0xff {
+			return nil, errors.New("execution reverted")
+		}
+		returnData[i] = aggregatedCall.CallData
+	}
+	return method.Outputs.Pack(big.NewInt(1), returnData)
+}
+
+func TestCallWithoutMulticall(t *testing.T) {
+	contractCaller := &echoContractCaller{}
+	caller, err := New(contractCaller, common.Address{})
+	require.NoError(t, err)
+	results, err := caller.Call(context.Background(), []Call{
+		{Target: targetAddress, CallData: []byte{0x1}},
+		{Target: targetAddress, CallData: []byte{0xff}},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, []byte{0x1}, results[0].ReturnData)
+	assert.NoError(t, results[0].Err)
+	assert.Error(t, results[1].Err)
+	assert.Equal(t, 2, contractCaller.numCalls)
+}
+
+func TestCallFallsBackToIndividualCallsIfAggregateReverts(t *testing.T) {
+	contractCaller := &echoContractCaller{}
+	caller, err := New(contractCaller, multicallAddress)
+	require.NoError(t, err)
+	results, err := caller.Call(context.Background(), []Call{
+		{Target: targetAddress, CallData: []byte{0x1}},
+		{Target: targetAddress, CallData: []byte{0xff}},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, []byte{0x1}, results[0].ReturnData)
+	assert.Error(t, results[1].Err)
+	// One aggregated call followed by two individual calls.
+	assert.Equal(t, 3, contractCaller.numCalls)
+}
+
+func TestCallBisectsCallsIfAggregateReverts(t *testing.T) {
+	caller, err := New(nil, multicallAddress)
+	require.NoError(t, err)
+	contractCaller := &multicallContractCaller{abi: caller.abi}
+	caller.contractCaller = contractCaller
+
+	calls := make([]Call, 8)
+	for i := range calls {
+		calls[i] = Call{Target: targetAddress, CallData: []byte{byte(i)}}
+	}
+	calls[5].CallData = []byte{0xff}
+	results, err := caller.Call(context.Background(), calls, nil)
+	require.NoError(t, err)
+	require.Len(t, results, len(calls))
+	for i, result := range results {
+		if i == 5 {
+			assert.Error(t, result.Err)
+			continue
+		}
+		assert.NoError(t, result.Err)
+		assert.Equal(t, []byte{byte(i)}, result.ReturnData)
+	}
+	// Calls 0-7 (reverts), 0-3, 4-7 (reverts), 4-5 (reverts), 4, 5 and 6-7
+	// instead of one aggregated call and eight individual calls.
+	assert.Equal(t, 7, contractCaller.numCalls)
+}
@@ -36,6 +36,12 @@ func (f *fakeLimiter) Wait(ctx context.Context) error {
 	return nil
 }
 
+// WaitForMethod blocks until the rateLimiter allows for another request to be
+// sent
+func (f *fakeLimiter) WaitForMethod(ctx context.Context, method string) error {
+	return f.Wait(ctx)
+}
+
 func (f *fakeLimiter) getGrantedInLast24hrsUTC() int {
 	return f.grantedInLast24hrsUTC
 }
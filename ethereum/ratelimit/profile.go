@@ -0,0 +1,180 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+)
+
+// Names of the built-in provider profiles.
+const (
+	// ProfileNameAuto selects a profile based on the Ethereum RPC URL.
+	ProfileNameAuto = "auto"
+	// ProfileNameGeneric uses the configured request limits as-is.
+	ProfileNameGeneric       = "generic"
+	ProfileNameInfura        = "infura"
+	ProfileNameAlchemy       = "alchemy"
+	ProfileNameAlchemyGrowth = "alchemy-growth"
+)
+
+// Units in which the limits of a Profile can be expressed.
+const (
+	UnitRequests     = "requests"
+	UnitComputeUnits = "computeUnits"
+)
+
+// defaultComputeUnitCost is the cost of methods which are missing from
+// alchemyComputeUnitCosts.
+const defaultComputeUnitCost = 26
+
+// alchemyComputeUnitCosts is the number of compute units Alchemy charges for
+// each JSON-RPC method used by Mesh.
+var alchemyComputeUnitCosts = map[string]int{
+	"eth_blockNumber":           10,
+	"eth_call":                  26,
+	"eth_chainId":               0,
+	"eth_estimateGas":           87,
+	"eth_gasPrice":              19,
+	"eth_getBalance":            19,
+	"eth_getBlockByHash":        16,
+	"eth_getBlockByNumber":      16,
+	"eth_getCode":               26,
+	"eth_getLogs":               75,
+	"eth_getStorageAt":          17,
+	"eth_getTransactionReceipt": 15,
+	"net_version":               0,
+}
+
+// Profile describes the rate limits of an Ethereum RPC provider. For most
+// providers limits are expressed in requests. Providers which bill by compute
+// units (e.g. Alchemy) set ComputeUnitCosts, in which case the limits are
+// expressed in compute units instead.
+type Profile struct {
+	Name string
+	// MaxUnitsPerSecond is the maximum number of requests (or compute units)
+	// per second. Bursts of up to half a second's worth are allowed.
+	MaxUnitsPerSecond float64
+	// MaxUnitsPer24HrUTC is the maximum number of requests (or compute units)
+	// per 24hr UTC time window.
+	MaxUnitsPer24HrUTC int
+	// ComputeUnitCosts maps JSON-RPC methods to their cost in compute units.
+	// If nil, every request costs 1 unit.
+	ComputeUnitCosts map[string]int
+}
+
+// GenericProfile returns a Profile which limits the number of requests as
+// given.
+func GenericProfile(maxRequestsPer24HrUTC int, maxRequestsPerSecond float64) Profile {
+	return Profile{
+		Name:               ProfileNameGeneric,
+		MaxUnitsPerSecond:  maxRequestsPerSecond,
+		MaxUnitsPer24HrUTC: maxRequestsPer24HrUTC,
+	}
+}
+
+// builtInProfiles are the default limits of well-known providers. The Alchemy
+// daily budgets are derived from their monthly compute unit allowances.
+var builtInProfiles = map[string]Profile{
+	ProfileNameInfura: {
+		Name:               ProfileNameInfura,
+		MaxUnitsPerSecond:  10,
+		MaxUnitsPer24HrUTC: 100000,
+	},
+	ProfileNameAlchemy: {
+		Name:               ProfileNameAlchemy,
+		MaxUnitsPerSecond:  330,
+		MaxUnitsPer24HrUTC: 300000000 / 31,
+		ComputeUnitCosts:   alchemyComputeUnitCosts,
+	},
+	ProfileNameAlchemyGrowth: {
+		Name:               ProfileNameAlchemyGrowth,
+		MaxUnitsPerSecond:  660,
+		MaxUnitsPer24HrUTC: 400000000 / 31,
+		ComputeUnitCosts:   alchemyComputeUnitCosts,
+	},
+}
+
+// Units returns the unit the limits of the profile are expressed in:
+// UnitRequests or UnitComputeUnits.
+func (p Profile) Units() string {
+	if p.ComputeUnitCosts == nil {
+		return UnitRequests
+	}
+	return UnitComputeUnits
+}
+
+// Cost returns the number of units a request with the given method uses up.
+func (p Profile) Cost(method string) int {
+	if p.ComputeUnitCosts == nil {
+		return 1
+	}
+	if cost, ok := p.ComputeUnitCosts[method]; ok {
+		return cost
+	}
+	return defaultComputeUnitCost
+}
+
+// burst returns the bucket size of the per-second limiter. It is large enough
+// for the most expensive request to be granted.
+func (p Profile) burst() int {
+	burst := int(math.Max(1, p.MaxUnitsPerSecond/2))
+	for _, cost := range p.ComputeUnitCosts {
+		if cost > burst {
+			burst = cost
+		}
+	}
+	return burst
+}
+
+// DetectProfileName returns the name of the built-in profile for the provider
+// of the given Ethereum RPC URL, or ProfileNameGeneric if the provider is not
+// recognized.
+func DetectProfileName(rpcURL string) string {
+	parsed, err := url.Parse(rpcURL)
+	if err != nil {
+		return ProfileNameGeneric
+	}
+	host := strings.ToLower(parsed.Hostname())
+	switch {
+	case hasDomain(host, "infura.io"):
+		return ProfileNameInfura
+	case hasDomain(host, "alchemyapi.io"), hasDomain(host, "alchemy.com"):
+		return ProfileNameAlchemy
+	default:
+		return ProfileNameGeneric
+	}
+}
+
+func hasDomain(host string, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// ResolveProfileName returns the name of the profile to use for the given
+// configured name. ProfileNameAuto is resolved by detecting the provider from
+// rpcURL. An empty name results in ProfileNameGeneric, so that the configured
+// request limits are used unless a provider profile was explicitly selected.
+func ResolveProfileName(name string, rpcURL string) string {
+	switch name {
+	case "":
+		return ProfileNameGeneric
+	case ProfileNameAuto:
+		return DetectProfileName(rpcURL)
+	default:
+		return name
+	}
+}
+
+// GetProfile returns the profile with the given name, which is resolved via
+// ResolveProfileName. The generic profile uses the given request limits.
+func GetProfile(name string, rpcURL string, maxRequestsPer24HrUTC int, maxRequestsPerSecond float64) (Profile, error) {
+	name = ResolveProfileName(name, rpcURL)
+	if name == ProfileNameGeneric {
+		return GenericProfile(maxRequestsPer24HrUTC, maxRequestsPerSecond), nil
+	}
+	profile, ok := builtInProfiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown Ethereum RPC provider profile: %q", name)
+	}
+	return profile, nil
+}
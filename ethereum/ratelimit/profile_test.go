@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectProfileName(t *testing.T) {
+	testCases := []struct {
+		rpcURL   string
+		expected string
+	}{
+		{"https://mainnet.infura.io/v3/abc", ProfileNameInfura},
+		{"wss://kovan.infura.io/ws/v3/abc", ProfileNameInfura},
+		{"https://eth-mainnet.alchemyapi.io/v2/abc", ProfileNameAlchemy},
+		{"https://eth-mainnet.g.alchemy.com/v2/abc", ProfileNameAlchemy},
+		{"http://localhost:8545", ProfileNameGeneric},
+		{"https://notinfura.io/v3/abc", ProfileNameGeneric},
+		{"https://example.com/?upstream=mainnet.infura.io", ProfileNameGeneric},
+		{"", ProfileNameGeneric},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, DetectProfileName(testCase.rpcURL), testCase.rpcURL)
+	}
+}
+
+func TestGetProfile(t *testing.T) {
+	profile, err := GetProfile(ProfileNameAuto, "http://localhost:8545", 200000, 30)
+	require.NoError(t, err)
+	assert.Equal(t, GenericProfile(200000, 30), profile)
+
+	profile, err = GetProfile(ProfileNameAuto, "https://eth-mainnet.alchemyapi.io/v2/abc", 200000, 30)
+	require.NoError(t, err)
+	assert.Equal(t, ProfileNameAlchemy, profile.Name)
+
+	// Explicitly selected profiles take precedence over auto-detection.
+	profile, err = GetProfile(ProfileNameGeneric, "https://mainnet.infura.io/v3/abc", 200000, 30)
+	require.NoError(t, err)
+	assert.Equal(t, GenericProfile(200000, 30), profile)
+
+	// Without an explicitly selected profile, the configured limits are used.
+	profile, err = GetProfile("", "https://mainnet.infura.io/v3/abc", 200000, 30)
+	require.NoError(t, err)
+	assert.Equal(t, GenericProfile(200000, 30), profile)
+
+	_, err = GetProfile("unknown", "", 200000, 30)
+	assert.Error(t, err)
+}
+
+func TestProfileUnits(t *testing.T) {
+	assert.Equal(t, UnitRequests, GenericProfile(200000, 30).Units())
+	assert.Equal(t, UnitRequests, builtInProfiles[ProfileNameInfura].Units())
+	assert.Equal(t, UnitComputeUnits, builtInProfiles[ProfileNameAlchemy].Units())
+}
+
+func TestProfileCost(t *testing.T) {
+	generic := GenericProfile(200000, 30)
+	assert.Equal(t, 1, generic.Cost("eth_getLogs"))
+	assert.Equal(t, 1, generic.Cost(""))
+
+	alchemy := builtInProfiles[ProfileNameAlchemy]
+	assert.Equal(t, 75, alchemy.Cost("eth_getLogs"))
+	assert.Equal(t, 0, alchemy.Cost("eth_chainId"))
+	assert.Equal(t, defaultComputeUnitCost, alchemy.Cost("eth_unknownMethod"))
+	// The per-second limiter must be able to grant the most expensive request.
+	assert.True(t, alchemy.burst() >= 75)
+}
@@ -3,7 +3,6 @@ package ratelimit
 import (
 	"context"
 	"errors"
-	"math"
 	"sync"
 	"time"
 
@@ -19,6 +18,7 @@ var ErrTooManyRequestsIn24Hours = errors.New("too many Ethereum RPC requests hav
 // RateLimiter is the interface one must satisfy to be considered a RateLimiter
 type RateLimiter interface {
 	Wait(ctx context.Context) error
+	WaitForMethod(ctx context.Context, method string) error
 	Start(ctx context.Context, checkpointInterval time.Duration) error
 	getCurrentUTCCheckpoint() time.Time
 	getGrantedInLast24hrsUTC() int
@@ -26,10 +26,10 @@ type RateLimiter interface {
 
 // rateLimiter is a rate-limiter for requests
 type rateLimiter struct {
-	maxRequestsPer24Hrs   int
+	profile               Profile
 	perSecondLimiter      *rate.Limiter
 	currentUTCCheckpoint  time.Time // Start of current UTC 24hr period
-	grantedInLast24hrsUTC int       // Number of granted requests issued in last 24hr UTC
+	grantedInLast24hrsUTC int       // Number of units (requests or compute units) granted in last 24hr UTC
 	meshDB                *meshdb.MeshDB
 	aClock                clock.Clock
 	wasStartedOnce        bool       // Whether the rate limiter has previously been started
@@ -39,6 +39,12 @@ type rateLimiter struct {
 
 // New instantiates a new RateLimiter
 func New(maxRequestsPer24Hrs int, maxRequestsPerSecond float64, meshDB *meshdb.MeshDB, aClock clock.Clock) (RateLimiter, error) {
+	return NewWithProfile(GenericProfile(maxRequestsPer24Hrs, maxRequestsPerSecond), meshDB, aClock)
+}
+
+// NewWithProfile instantiates a new RateLimiter which enforces the limits of
+// the given provider profile.
+func NewWithProfile(profile Profile, meshDB *meshdb.MeshDB, aClock clock.Clock) (RateLimiter, error) {
 	metadata, err := meshDB.GetMetadata()
 	if err != nil {
 		return nil, err
@@ -49,29 +55,38 @@ func New(maxRequestsPer24Hrs int, maxRequestsPerSecond float64, meshDB *meshdb.M
 	currentUTCCheckpoint := GetUTCMidnightOfDate(now)
 	storedUTCCheckpoint := metadata.StartOfCurrentUTCDay
 	storedGrantedInLast24HrsUTC := metadata.EthRPCRequestsSentInCurrentUTCDay
-	// Update DB if current values are from previous 24hr period and therefore no longer relevant
-	if currentUTCCheckpoint != storedUTCCheckpoint {
+	storedUnits := metadata.EthRPCRateLimitUnits
+	if storedUnits == "" {
+		storedUnits = UnitRequests
+	}
+	// Reset the stored values if they are from previous 24hr period or were
+	// counted in different units (e.g. requests instead of compute units) and
+	// are therefore no longer relevant
+	if currentUTCCheckpoint != storedUTCCheckpoint || storedUnits != profile.Units() {
 		storedUTCCheckpoint = currentUTCCheckpoint
 		storedGrantedInLast24HrsUTC = 0
+	}
+	if storedUTCCheckpoint != metadata.StartOfCurrentUTCDay || metadata.EthRPCRateLimitUnits != profile.Units() {
 		if err := meshDB.UpdateMetadata(func(metadata meshdb.Metadata) meshdb.Metadata {
 			metadata.StartOfCurrentUTCDay = storedUTCCheckpoint
 			metadata.EthRPCRequestsSentInCurrentUTCDay = storedGrantedInLast24HrsUTC
+			metadata.EthRPCRateLimitUnits = profile.Units()
 			return metadata
 		}); err != nil {
 			return nil, err
 		}
 	}
 
-	// Instantiate limiter with a bucketsize of maxRequestsPerSecond/2 and a limit
-	// of `maxRequestsPerSecond` requests per second. This does a pretty good job
+	// Instantiate limiter with a bucketsize of MaxUnitsPerSecond/2 and a limit
+	// of `MaxUnitsPerSecond` units per second. This does a pretty good job
 	// of limiting the number of requests we send per second while still allowing
 	// for some bursts.
-	limit := rate.Limit(maxRequestsPerSecond)
-	perSecondLimiter := rate.NewLimiter(limit, int(math.Max(1, maxRequestsPerSecond/2)))
+	limit := rate.Limit(profile.MaxUnitsPerSecond)
+	perSecondLimiter := rate.NewLimiter(limit, profile.burst())
 
 	return &rateLimiter{
 		aClock:                aClock,
-		maxRequestsPer24Hrs:   maxRequestsPer24Hrs,
+		profile:               profile,
 		perSecondLimiter:      perSecondLimiter,
 		meshDB:                meshDB,
 		currentUTCCheckpoint:  storedUTCCheckpoint,
@@ -150,17 +165,26 @@ func (r *rateLimiter) Start(ctx context.Context, checkpointInterval time.Duratio
 // would be granted. It also returns an error if too many requests have been
 // sent during this 24 hour period.
 func (r *rateLimiter) Wait(ctx context.Context) error {
+	return r.WaitForMethod(ctx, "")
+}
+
+// WaitForMethod is like Wait but accounts for the cost of the given JSON-RPC
+// method if the provider profile bills by compute units.
+func (r *rateLimiter) WaitForMethod(ctx context.Context, method string) error {
+	cost := r.profile.Cost(method)
 	r.mu.Lock()
-	if r.grantedInLast24hrsUTC >= r.maxRequestsPer24Hrs {
+	if r.grantedInLast24hrsUTC >= r.profile.MaxUnitsPer24HrUTC {
 		r.mu.Unlock()
 		return ErrTooManyRequestsIn24Hours
 	}
 	r.mu.Unlock()
-	if err := r.perSecondLimiter.Wait(ctx); err != nil {
-		return err
+	if cost > 0 {
+		if err := r.perSecondLimiter.WaitN(ctx, cost); err != nil {
+			return err
+		}
 	}
 	r.mu.Lock()
-	r.grantedInLast24hrsUTC++
+	r.grantedInLast24hrsUTC += cost
 	r.mu.Unlock()
 	return nil
 }
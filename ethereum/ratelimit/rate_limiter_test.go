@@ -188,6 +188,49 @@ func TestScenario3(t *testing.T) {
 	wg.Wait()
 }
 
+// Scenario 4: The units used up in the current day were counted in different
+// units than the ones of the profile (e.g. requests instead of compute
+// units). They get reset when RateLimiter is instantiated.
+func TestScenario4(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	now := time.Now()
+	startOfCurrentUTCDay := GetUTCMidnightOfDate(now)
+	// Metadata stored by older versions of Mesh has no units, which means the
+	// number of requests was counted.
+	metadata := &meshdb.Metadata{
+		EthereumChainID:                   1337,
+		MaxExpirationTime:                 constants.UnlimitedExpirationTime,
+		StartOfCurrentUTCDay:              startOfCurrentUTCDay,
+		EthRPCRequestsSentInCurrentUTCDay: 5000,
+	}
+	err = meshDB.SaveMetadata(metadata)
+	require.NoError(t, err)
+
+	aClock := clock.NewMock()
+	aClock.Set(now)
+
+	// The generic profile counts requests, so the count is kept.
+	rateLimiter, err := New(defaultMaxRequestsPer24Hrs, defaultMaxRequestsPerSecond, meshDB, aClock)
+	require.NoError(t, err)
+	assert.Equal(t, 5000, rateLimiter.getGrantedInLast24hrsUTC())
+	metadata, err = meshDB.GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, UnitRequests, metadata.EthRPCRateLimitUnits)
+
+	// The Alchemy profile counts compute units, so the count is reset.
+	rateLimiter, err = NewWithProfile(builtInProfiles[ProfileNameAlchemy], meshDB, aClock)
+	require.NoError(t, err)
+	assert.Equal(t, 0, rateLimiter.getGrantedInLast24hrsUTC())
+	assert.Equal(t, startOfCurrentUTCDay, rateLimiter.getCurrentUTCCheckpoint())
+	metadata, err = meshDB.GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, UnitComputeUnits, metadata.EthRPCRateLimitUnits)
+	assert.Equal(t, 0, metadata.EthRPCRequestsSentInCurrentUTCDay)
+}
+
 func initMetadata(t *testing.T, meshDB *meshdb.MeshDB) {
 	metadata := &meshdb.Metadata{
 		EthereumChainID:   1337,
@@ -0,0 +1,59 @@
+// +build !js
+
+// subscribe-with-replay is a short program that subscribes to order events via
+// RPC and resumes the subscription without missing any order events whenever
+// the connection to 0x Mesh is lost.
+package main
+
+import (
+	"context"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/rpc/meshclient"
+	"github.com/plaid/go-envvar/envvar"
+	log "github.com/sirupsen/logrus"
+)
+
+type clientEnvVars struct {
+	// RPCAddress is the address of the 0x Mesh node to communicate with.
+	WSRPCAddress string `envvar:"WS_RPC_ADDR"`
+}
+
+func main() {
+	log.SetFormatter(&log.JSONFormatter{})
+
+	env := clientEnvVars{}
+	if err := envvar.Parse(&env); err != nil {
+		panic(err)
+	}
+
+	ctx := context.Background()
+	client, err := meshclient.Connect(ctx, env.WSRPCAddress, meshclient.Options{})
+	if err != nil {
+		log.WithError(err).Fatal("could not connect to 0x Mesh")
+	}
+	defer client.Close()
+
+	notificationsChan := make(chan *types.OrderEventsNotification, 8000)
+	subscription, err := client.SubscribeWithReplay(ctx, notificationsChan)
+	if err != nil {
+		log.WithError(err).Fatal("Couldn't set up OrderStream subscription")
+	}
+	defer subscription.Unsubscribe()
+
+	for {
+		select {
+		case notification := <-notificationsChan:
+			if notification.ResyncRequired {
+				log.Warn("missed order events; the orderbook must be resynced via GetOrders")
+			}
+			for _, orderEvent := range notification.OrderEvents {
+				log.WithFields(log.Fields{
+					"event": orderEvent,
+				}).Printf("received order event")
+			}
+		case err := <-subscription.Err():
+			log.Fatal(err)
+		}
+	}
+}
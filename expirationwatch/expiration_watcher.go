@@ -1,11 +1,11 @@
 package expirationwatch
 
 import (
+	"container/heap"
+	"context"
 	"sync"
 	"time"
 
-	"github.com/albrow/stringset"
-	"github.com/ocdogan/rbt"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -15,45 +15,96 @@ type ExpiredItem struct {
 	ID                  string
 }
 
-// Watcher watches the expiration of items
+// itemKey uniquely identifies an item in the Watcher. Expiration timestamps
+// have a resolution of one second.
+type itemKey struct {
+	expirationTimeSeconds int64
+	id                    string
+}
+
+// item is an entry in the expiration heap.
+type item struct {
+	key itemKey
+	// index is the position of the item in the heap. It is maintained by the
+	// heap.Interface methods and allows items to be removed in O(log n) time.
+	index int
+}
+
+// expirationHeap is a min-heap of items ordered by expiration time. It
+// implements heap.Interface.
+type expirationHeap []*item
+
+func (h expirationHeap) Len() int { return len(h) }
+
+func (h expirationHeap) Less(i, j int) bool {
+	return h[i].key.expirationTimeSeconds < h[j].key.expirationTimeSeconds
+}
+
+func (h expirationHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expirationHeap) Push(x interface{}) {
+	item := x.(*item)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *expirationHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*h = old[:n-1]
+	return item
+}
+
+// Watcher watches the expiration of items. Items are kept in a min-heap keyed
+// by expiration time, so adding, removing and pruning an item all take
+// O(log n) time regardless of how many items are being watched.
 type Watcher struct {
-	expiredItems chan []ExpiredItem
-	rbTreeMu     sync.RWMutex
-	rbTree       *rbt.RbTree
+	mu    sync.Mutex
+	heap  expirationHeap
+	items map[itemKey]*item
+	// earliestChanged receives a value whenever the earliest expiration time
+	// changes. It is buffered so that sending to it never blocks.
+	earliestChanged chan struct{}
 }
 
 // New instantiates a new expiration watcher
 func New() *Watcher {
-	rbTree := rbt.NewRbTree()
 	return &Watcher{
-		expiredItems: make(chan []ExpiredItem, 10),
-		rbTree:       rbTree,
+		items:           map[itemKey]*item{},
+		earliestChanged: make(chan struct{}, 1),
 	}
 }
 
 // Add adds a new item identified by an ID to the expiration watcher
 func (w *Watcher) Add(expirationTimestamp time.Time, id string) {
-	key := rbt.Int64Key(expirationTimestamp.Unix())
-	w.rbTreeMu.Lock()
-	defer w.rbTreeMu.Unlock()
-	value, ok := w.rbTree.Get(&key)
-	var ids stringset.Set
-	if !ok {
-		ids = stringset.New()
-	} else {
-		ids = value.(stringset.Set)
+	key := itemKey{expirationTimeSeconds: expirationTimestamp.Unix(), id: id}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, found := w.items[key]; found {
+		return
+	}
+	item := &item{key: key}
+	heap.Push(&w.heap, item)
+	w.items[key] = item
+	if item.index == 0 {
+		w.notifyEarliestChanged()
 	}
-	ids.Add(id)
-	w.rbTree.Insert(&key, ids)
 }
 
 // Remove removes the item with a specified id from the expiration watcher
 func (w *Watcher) Remove(expirationTimestamp time.Time, id string) {
-	key := rbt.Int64Key(expirationTimestamp.Unix())
-	w.rbTreeMu.Lock()
-	defer w.rbTreeMu.Unlock()
-	value, ok := w.rbTree.Get(&key)
-	if !ok {
+	key := itemKey{expirationTimeSeconds: expirationTimestamp.Unix(), id: id}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	item, found := w.items[key]
+	if !found {
 		// Due to the asynchronous nature of the Watcher and OrderWatcher, there are
 		// race-conditions where we try to remove an item from the Watcher after it
 		// has already been removed.
@@ -61,43 +112,100 @@ func (w *Watcher) Remove(expirationTimestamp time.Time, id string) {
 			"id": id,
 		}).Trace("Attempted to remove item from Watcher that no longer exists")
 		return // Noop
-	} else {
-		ids := value.(stringset.Set)
-		ids.Remove(id)
-		if len(ids) == 0 {
-			w.rbTree.Delete(&key)
-		} else {
-			w.rbTree.Insert(&key, ids)
-		}
+	}
+	wasEarliest := item.index == 0
+	heap.Remove(&w.heap, item.index)
+	delete(w.items, key)
+	if wasEarliest {
+		w.notifyEarliestChanged()
 	}
 }
 
 // Prune checks for any expired items given a timestamp and removes any expired
-// items from the expiration watcher and returns them to the caller
+// items from the expiration watcher and returns them to the caller. Items are
+// returned in order of expiration.
 func (w *Watcher) Prune(timestamp time.Time) []ExpiredItem {
 	pruned := []ExpiredItem{}
-	for {
-		w.rbTreeMu.RLock()
-		key, value := w.rbTree.Min()
-		w.rbTreeMu.RUnlock()
-		if key == nil {
-			break
-		}
-		expirationTimeSeconds := int64(*key.(*rbt.Int64Key))
-		expirationTime := time.Unix(expirationTimeSeconds, 0)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for len(w.heap) > 0 {
+		expirationTime := time.Unix(w.heap[0].key.expirationTimeSeconds, 0)
 		if timestamp.Before(expirationTime) {
 			break
 		}
-		ids := value.(stringset.Set)
-		for id := range ids {
-			pruned = append(pruned, ExpiredItem{
-				ExpirationTimestamp: expirationTime,
-				ID:                  id,
-			})
-		}
-		w.rbTreeMu.Lock()
-		w.rbTree.Delete(key)
-		w.rbTreeMu.Unlock()
+		item := heap.Pop(&w.heap).(*item)
+		delete(w.items, item.key)
+		pruned = append(pruned, ExpiredItem{
+			ExpirationTimestamp: expirationTime,
+			ID:                  item.key.id,
+		})
+	}
+	if len(pruned) > 0 {
+		w.notifyEarliestChanged()
 	}
 	return pruned
 }
+
+// Len returns the number of items being watched.
+func (w *Watcher) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.heap)
+}
+
+// NextExpiration returns the earliest expiration time of any item being
+// watched. It returns false if there are no items.
+func (w *Watcher) NextExpiration() (time.Time, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.heap) == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(w.heap[0].key.expirationTimeSeconds, 0), true
+}
+
+// EarliestChanged returns a channel which receives a value whenever the
+// earliest expiration time (as returned by NextExpiration) might have changed.
+// It can be used to schedule pruning without polling. Only one receiver is
+// supported.
+func (w *Watcher) EarliestChanged() <-chan struct{} {
+	return w.earliestChanged
+}
+
+func (w *Watcher) notifyEarliestChanged() {
+	select {
+	case w.earliestChanged <- struct{}{}:
+	default:
+	}
+}
+
+// PruneOnExpiration calls Prune whenever an item expires according to the
+// local clock and passes the expired items to onExpired. It blocks until the
+// given context is canceled. It is useful for items whose expiration does not
+// depend on block timestamps.
+func (w *Watcher) PruneOnExpiration(ctx context.Context, onExpired func([]ExpiredItem)) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.EarliestChanged():
+		case now := <-timer.C:
+			if expired := w.Prune(now); len(expired) > 0 {
+				onExpired(expired)
+			}
+		}
+
+		// Reset the timer to fire at the next expiration time, if any.
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if next, ok := w.NextExpiration(); ok {
+			timer.Reset(time.Until(next))
+		}
+	}
+}
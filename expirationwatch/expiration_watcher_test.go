@@ -1,10 +1,13 @@
 package expirationwatch
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPrunesExpiredItems(t *testing.T) {
@@ -135,3 +138,52 @@ func TestRemoveItemWhichSharesExpirationTimeWithOtherItems(t *testing.T) {
 	assert.Len(t, pruned, 1, "two expired items should get pruned")
 	assert.Equal(t, expiryEntryOne, pruned[0])
 }
+
+func TestPrunesItemsInExpirationOrder(t *testing.T) {
+	watcher := New()
+
+	current := time.Now().Truncate(time.Second)
+	offsets := []int{5, 1, 4, 2, 3}
+	for _, offset := range offsets {
+		watcher.Add(current.Add(-time.Duration(offset)*time.Second), fmt.Sprintf("%d", offset))
+	}
+	watcher.Remove(current.Add(-4*time.Second), "4")
+	assert.Equal(t, 4, watcher.Len())
+
+	next, ok := watcher.NextExpiration()
+	require.True(t, ok)
+	assert.Equal(t, current.Add(-5*time.Second), next)
+
+	pruned := watcher.Prune(current.Add(-2 * time.Second))
+	require.Len(t, pruned, 3)
+	assert.Equal(t, "5", pruned[0].ID)
+	assert.Equal(t, "3", pruned[1].ID)
+	assert.Equal(t, "2", pruned[2].ID)
+	assert.Equal(t, 1, watcher.Len())
+}
+
+func TestPruneOnExpiration(t *testing.T) {
+	watcher := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expiredChan := make(chan []ExpiredItem, 1)
+	go watcher.PruneOnExpiration(ctx, func(expired []ExpiredItem) {
+		expiredChan <- expired
+	})
+
+	// Adding an item which expires before any other item should wake up the
+	// watcher without polling.
+	expiration := time.Now().Add(1 * time.Second).Truncate(time.Second)
+	watcher.Add(expiration.Add(1*time.Hour), "later")
+	watcher.Add(expiration, "soon")
+
+	select {
+	case expired := <-expiredChan:
+		require.Len(t, expired, 1)
+		assert.Equal(t, "soon", expired[0].ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for item to expire")
+	}
+	assert.Equal(t, 1, watcher.Len())
+}
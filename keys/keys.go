@@ -28,7 +28,7 @@ func GenerateAndSavePrivateKey(path string) (p2pcrypto.PrivKey, error) {
 	if err := mkdirAll(dir); err != nil {
 		return nil, err
 	}
-	privKey, _, err := p2pcrypto.GenerateSecp256k1Key(rand.Reader)
+	privKey, err := GeneratePrivateKey()
 	if err != nil {
 		return nil, err
 	}
@@ -42,3 +42,9 @@ func GenerateAndSavePrivateKey(path string) (p2pcrypto.PrivKey, error) {
 	}
 	return privKey, nil
 }
+
+// GeneratePrivateKey generates a new private key without saving it.
+func GeneratePrivateKey() (p2pcrypto.PrivKey, error) {
+	privKey, _, err := p2pcrypto.GenerateSecp256k1Key(rand.Reader)
+	return privKey, err
+}
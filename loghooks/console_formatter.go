@@ -0,0 +1,78 @@
+package loghooks
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// consoleTimestampFormat is the format used for timestamps by the
+// ConsoleFormatter.
+const consoleTimestampFormat = "2006-01-02 15:04:05.000"
+
+// ConsoleFormatter is a human-readable log formatter intended for local
+// development and for operators reading logs in a terminal. Each entry is
+// formatted on a single line as:
+//
+//	<timestamp> <LEVEL> [<subsystem>] <message> key1=value1 key2=value2
+//
+// Fields are sorted by key. The subsystem is only included if the entry has a
+// "subsystem" field (see SubsystemHook).
+type ConsoleFormatter struct{}
+
+// NewConsoleFormatter creates and returns a new ConsoleFormatter.
+func NewConsoleFormatter() *ConsoleFormatter {
+	return &ConsoleFormatter{}
+}
+
+// Ensure that ConsoleFormatter implements log.Formatter.
+var _ log.Formatter = &ConsoleFormatter{}
+
+func (f *ConsoleFormatter) Format(entry *log.Entry) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(entry.Time.UTC().Format(consoleTimestampFormat))
+	buf.WriteByte(' ')
+	fmt.Fprintf(buf, "%-7s", strings.ToUpper(entry.Level.String()))
+	if subsystem, found := entry.Data["subsystem"]; found {
+		fmt.Fprintf(buf, " [%v]", subsystem)
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(entry.Message)
+
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		if key == "subsystem" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		buf.WriteByte(' ')
+		buf.WriteString(key)
+		buf.WriteByte('=')
+		buf.WriteString(formatConsoleValue(entry.Data[key]))
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// formatConsoleValue formats a field value, quoting it if it contains spaces
+// or other characters which would make the line ambiguous.
+func formatConsoleValue(value interface{}) string {
+	var str string
+	if t, ok := value.(time.Time); ok {
+		str = t.UTC().Format(time.RFC3339Nano)
+	} else {
+		// Note that fmt uses the Error and String methods if value has them.
+		str = fmt.Sprintf("%v", value)
+	}
+	if str == "" || strings.ContainsAny(str, " =\"\t\n") {
+		return fmt.Sprintf("%q", str)
+	}
+	return str
+}
@@ -0,0 +1,29 @@
+package loghooks
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsoleFormatter(t *testing.T) {
+	entry := log.NewEntry(log.New())
+	entry.Time = time.Date(2020, time.April, 1, 12, 30, 15, 123000000, time.UTC)
+	entry.Level = log.WarnLevel
+	entry.Message = "could not validate orders"
+	entry.Data = log.Fields{
+		"subsystem": "orderwatch",
+		"numOrders": 3,
+		"error":     errors.New("request timed out"),
+		"orderHash": "0xa0fcb549",
+	}
+
+	output, err := NewConsoleFormatter().Format(entry)
+	require.NoError(t, err)
+	expected := `2020-04-01 12:30:15.123 WARNING [orderwatch] could not validate orders error="request timed out" numOrders=3 orderHash=0xa0fcb549` + "\n"
+	assert.Equal(t, expected, string(output))
+}
@@ -0,0 +1,144 @@
+package loghooks
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultSamplingInterval is the interval over which log entries are counted
+// for the purposes of sampling.
+const defaultSamplingInterval = time.Second
+
+// FilterConfig is a set of configuration options for a FilterFormatter.
+type FilterConfig struct {
+	// DefaultLevel is the level for subsystems without a level in
+	// SubsystemLevels.
+	DefaultLevel log.Level
+	// SubsystemLevels maps subsystem names (as returned by Subsystem) to the
+	// most verbose level that should be logged for that subsystem.
+	SubsystemLevels map[string]log.Level
+	// SamplingInitial is the number of entries with the same message that are
+	// logged each second before sampling kicks in. Only entries at Info level
+	// or more verbose are sampled. Zero disables sampling.
+	SamplingInitial int
+	// SamplingThereafter determines which entries are logged once
+	// SamplingInitial has been reached: every SamplingThereafter'th entry with
+	// the same message is logged and the rest are dropped. Zero drops all of
+	// them.
+	SamplingThereafter int
+}
+
+// FilterFormatter is a log formatter which wraps another formatter and drops
+// entries based on the level configured for the subsystem which logged them
+// and on sampling. Dropped entries are formatted as an empty byte slice. Hooks
+// added via AddHook are only fired for entries which are not dropped.
+//
+// Because logrus checks the level of the logger before entries reach the
+// formatter, the level of the logger must be set to MaxLevel.
+type FilterFormatter struct {
+	formatter          log.Formatter
+	defaultLevel       log.Level
+	subsystemLevels    map[string]log.Level
+	samplingInitial    int
+	samplingThereafter int
+	samplingInterval   time.Duration
+	mu                 sync.Mutex
+	windowStart        time.Time
+	messageCounts      map[string]int
+	hooksMu            sync.RWMutex
+	hooks              log.LevelHooks
+}
+
+// NewFilterFormatter creates and returns a new FilterFormatter which
+// delegates to the given formatter.
+func NewFilterFormatter(formatter log.Formatter, config FilterConfig) *FilterFormatter {
+	subsystemLevels := config.SubsystemLevels
+	if subsystemLevels == nil {
+		subsystemLevels = map[string]log.Level{}
+	}
+	return &FilterFormatter{
+		formatter:          formatter,
+		defaultLevel:       config.DefaultLevel,
+		subsystemLevels:    subsystemLevels,
+		samplingInitial:    config.SamplingInitial,
+		samplingThereafter: config.SamplingThereafter,
+		samplingInterval:   defaultSamplingInterval,
+		messageCounts:      map[string]int{},
+		hooks:              log.LevelHooks{},
+	}
+}
+
+// Ensure that FilterFormatter implements log.Formatter.
+var _ log.Formatter = &FilterFormatter{}
+
+// MaxLevel returns the most verbose level of any subsystem.
+func (f *FilterFormatter) MaxLevel() log.Level {
+	maxLevel := f.defaultLevel
+	for _, level := range f.subsystemLevels {
+		if level > maxLevel {
+			maxLevel = level
+		}
+	}
+	return maxLevel
+}
+
+// LevelEnabled returns true if the given entry should be logged according to
+// the level of the subsystem that logged it.
+func (f *FilterFormatter) LevelEnabled(entry *log.Entry) bool {
+	level, found := f.subsystemLevels[Subsystem(entry)]
+	if !found {
+		level = f.defaultLevel
+	}
+	return entry.Level <= level
+}
+
+// AddHook adds a hook which is fired for each entry that is not dropped, right
+// before it is formatted. Unlike hooks added to the logger, it is not fired for
+// entries that are dropped because of their subsystem's level or sampling.
+func (f *FilterFormatter) AddHook(hook log.Hook) {
+	f.hooksMu.Lock()
+	defer f.hooksMu.Unlock()
+	f.hooks.Add(hook)
+}
+
+func (f *FilterFormatter) Format(entry *log.Entry) ([]byte, error) {
+	if !f.LevelEnabled(entry) || !f.sample(entry) {
+		return nil, nil
+	}
+	f.hooksMu.RLock()
+	err := f.hooks.Fire(entry.Level, entry)
+	f.hooksMu.RUnlock()
+	if err != nil {
+		// This mirrors how logrus handles errors returned by hooks.
+		fmt.Fprintf(os.Stderr, "Failed to fire hook: %v\n", err)
+	}
+	// The caller is only needed to determine the subsystem. We don't want the
+	// underlying formatter to include it in the output.
+	entry.Caller = nil
+	return f.formatter.Format(entry)
+}
+
+// sample returns true if the given entry should be logged according to the
+// sampling configuration.
+func (f *FilterFormatter) sample(entry *log.Entry) bool {
+	if f.samplingInitial <= 0 || entry.Level < log.InfoLevel {
+		return true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if entry.Time.Sub(f.windowStart) >= f.samplingInterval || entry.Time.Before(f.windowStart) {
+		f.windowStart = entry.Time
+		f.messageCounts = map[string]int{}
+	}
+	f.messageCounts[entry.Message]++
+	count := f.messageCounts[entry.Message]
+	if count <= f.samplingInitial {
+		return true
+	}
+	return f.samplingThereafter > 0 && (count-f.samplingInitial)%f.samplingThereafter == 0
+}
@@ -0,0 +1,142 @@
+package loghooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ElasticsearchShipper ships log entries to Elasticsearch using the bulk API.
+type ElasticsearchShipper struct {
+	bulkURL string
+	index   string
+	client  *http.Client
+}
+
+// NewElasticsearchShipper returns a Shipper which indexes log entries in the
+// given index of the Elasticsearch cluster at baseURL.
+func NewElasticsearchShipper(baseURL string, index string) *ElasticsearchShipper {
+	return &ElasticsearchShipper{
+		bulkURL: strings.TrimSuffix(baseURL, "/") + "/_bulk",
+		index:   index,
+		client:  &http.Client{},
+	}
+}
+
+// Ship indexes the given entries.
+func (s *ElasticsearchShipper) Ship(ctx context.Context, entries []*ShippedEntry) error {
+	action, err := json.Marshal(map[string]interface{}{
+		"index": map[string]string{"_index": s.index},
+	})
+	if err != nil {
+		return err
+	}
+	body := &bytes.Buffer{}
+	for _, entry := range entries {
+		body.Write(action)
+		body.WriteByte('\n')
+		body.Write(entry.JSON)
+		body.WriteByte('\n')
+	}
+	// Note that errors for individual entries (e.g. mapping conflicts) are
+	// reported in the response body with a 200 status code. They won't go away
+	// if we retry, so we only check the status code.
+	return post(ctx, s.client, s.bulkURL, "application/x-ndjson", body)
+}
+
+// LokiShipper ships log entries to Grafana Loki using the push API. Entries
+// are labeled with the job, level and subsystem.
+type LokiShipper struct {
+	pushURL string
+	job     string
+	client  *http.Client
+}
+
+// NewLokiShipper returns a Shipper which pushes log entries to the Loki server
+// at baseURL, labeled with the given job.
+func NewLokiShipper(baseURL string, job string) *LokiShipper {
+	return &LokiShipper{
+		pushURL: strings.TrimSuffix(baseURL, "/") + "/loki/api/v1/push",
+		job:     job,
+		client:  &http.Client{},
+	}
+}
+
+type lokiPushRequest struct {
+	Streams []*lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// Ship pushes the given entries.
+func (s *LokiShipper) Ship(ctx context.Context, entries []*ShippedEntry) error {
+	// Loki requires entries to be grouped into streams with identical labels.
+	streams := map[string]*lokiStream{}
+	for _, entry := range entries {
+		key := entry.Level.String() + "|" + entry.Subsystem
+		stream, found := streams[key]
+		if !found {
+			stream = &lokiStream{
+				Stream: map[string]string{
+					"job":       s.job,
+					"level":     entry.Level.String(),
+					"subsystem": entry.Subsystem,
+				},
+				Values: [][2]string{},
+			}
+			streams[key] = stream
+		}
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(entry.Time.UnixNano(), 10),
+			string(entry.JSON),
+		})
+	}
+	keys := make([]string, 0, len(streams))
+	for key := range streams {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pushRequest := lokiPushRequest{Streams: make([]*lokiStream, 0, len(streams))}
+	for _, key := range keys {
+		pushRequest.Streams = append(pushRequest.Streams, streams[key])
+	}
+
+	body, err := json.Marshal(pushRequest)
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.client, s.pushURL, "application/json", bytes.NewReader(body))
+}
+
+// post sends a POST request and returns an error if the response has a non-2xx
+// status code.
+func post(ctx context.Context, client *http.Client, url string, contentType string, body io.Reader) error {
+	req, err := http.NewRequest("POST", url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("log store responded with status code %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package loghooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jpillora/backoff"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultShippingBufferSize is the default maximum number of log entries
+	// that are buffered in memory before new entries are dropped.
+	DefaultShippingBufferSize = 10000
+	// shippingBatchSize is the maximum number of entries shipped in a single
+	// request.
+	shippingBatchSize = 500
+	// defaultShippingFlushInterval is the default value for
+	// ShippingHookConfig.FlushInterval.
+	defaultShippingFlushInterval = 5 * time.Second
+	// shippingMaxAttempts is the maximum number of times we attempt to ship a
+	// batch before dropping it.
+	shippingMaxAttempts = 5
+	// shippingRequestTimeout is the timeout for a single shipping request.
+	shippingRequestTimeout = 30 * time.Second
+)
+
+// ShippedEntry is a log entry which has been serialized for shipping.
+type ShippedEntry struct {
+	Time      time.Time
+	Level     log.Level
+	Subsystem string
+	// JSON is the entry encoded as a JSON object, including the time, level
+	// and message.
+	JSON []byte
+}
+
+// Shipper ships batches of log entries to an external log store.
+type Shipper interface {
+	Ship(ctx context.Context, entries []*ShippedEntry) error
+}
+
+// ShippingHookConfig is a set of configuration options for a ShippingHook.
+type ShippingHookConfig struct {
+	// Shipper is used to ship entries to the external log store.
+	Shipper Shipper
+	// BufferSize is the maximum number of entries buffered in memory. When the
+	// buffer is full (e.g. because the log store is unreachable), new entries
+	// are dropped rather than blocking the caller. Defaults to
+	// DefaultShippingBufferSize.
+	BufferSize int
+	// Filter, if non-nil, is called for each entry and only entries for which
+	// it returns true are shipped.
+	Filter func(entry *log.Entry) bool
+	// FlushInterval is how often buffered entries are shipped if a full batch
+	// has not been reached. Defaults to 5 seconds.
+	FlushInterval time.Duration
+}
+
+// ShippingHook is a logger hook that asynchronously ships log entries to an
+// external log store such as Elasticsearch or Loki. Entries are buffered and
+// shipped in batches. Batches that fail to ship are retried with exponential
+// back-off. Logging never blocks on the log store: if the buffer fills up,
+// entries are dropped and counted.
+type ShippingHook struct {
+	shipper       Shipper
+	filter        func(entry *log.Entry) bool
+	entries       chan *ShippedEntry
+	flushInterval time.Duration
+	retryBackoff  *backoff.Backoff
+	numDropped    uint64
+	stopOnce      sync.Once
+	stop          chan struct{}
+	done          chan struct{}
+}
+
+// Ensure that ShippingHook implements log.Hook.
+var _ log.Hook = &ShippingHook{}
+
+// NewShippingHook creates and returns a new ShippingHook and starts shipping
+// entries in the background. Call Stop to flush remaining entries and stop.
+func NewShippingHook(config ShippingHookConfig) (*ShippingHook, error) {
+	if config.Shipper == nil {
+		return nil, errors.New("config.Shipper is required")
+	}
+	bufferSize := config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = DefaultShippingBufferSize
+	}
+	flushInterval := config.FlushInterval
+	if flushInterval <= 0 {
+		flushInterval = defaultShippingFlushInterval
+	}
+	h := &ShippingHook{
+		shipper:       config.Shipper,
+		filter:        config.Filter,
+		entries:       make(chan *ShippedEntry, bufferSize),
+		flushInterval: flushInterval,
+		retryBackoff: &backoff.Backoff{
+			Min:    250 * time.Millisecond,
+			Max:    10 * time.Second,
+			Factor: 2,
+			Jitter: true,
+		},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go h.run()
+	return h, nil
+}
+
+func (h *ShippingHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h *ShippingHook) Fire(entry *log.Entry) error {
+	if h.filter != nil && !h.filter(entry) {
+		return nil
+	}
+	shippedEntry, err := newShippedEntry(entry)
+	if err != nil {
+		return err
+	}
+	select {
+	case h.entries <- shippedEntry:
+	default:
+		atomic.AddUint64(&h.numDropped, 1)
+	}
+	return nil
+}
+
+// NumDropped returns the number of entries that were dropped, either because
+// the buffer was full or because they could not be shipped.
+func (h *ShippingHook) NumDropped() uint64 {
+	return atomic.LoadUint64(&h.numDropped)
+}
+
+// Stop ships any buffered entries and stops the hook. It blocks until the
+// entries have been shipped or the given context is done. Entries logged after
+// Stop is called are dropped.
+func (h *ShippingHook) Stop(ctx context.Context) error {
+	h.stopOnce.Do(func() {
+		close(h.stop)
+	})
+	select {
+	case <-h.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *ShippingHook) run() {
+	defer close(h.done)
+	ticker := time.NewTicker(h.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*ShippedEntry, 0, shippingBatchSize)
+	for {
+		select {
+		case entry := <-h.entries:
+			batch = append(batch, entry)
+			if len(batch) >= shippingBatchSize {
+				h.ship(batch)
+				batch = make([]*ShippedEntry, 0, shippingBatchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				h.ship(batch)
+				batch = make([]*ShippedEntry, 0, shippingBatchSize)
+			}
+		case <-h.stop:
+			// Drain whatever is left in the buffer and ship it.
+			for {
+				select {
+				case entry := <-h.entries:
+					batch = append(batch, entry)
+					if len(batch) >= shippingBatchSize {
+						h.ship(batch)
+						batch = make([]*ShippedEntry, 0, shippingBatchSize)
+					}
+				default:
+					if len(batch) > 0 {
+						h.ship(batch)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// ship ships the given batch, retrying with exponential back-off. While we
+// are retrying, new entries accumulate in the buffer. If the batch still can't
+// be shipped after shippingMaxAttempts, it is dropped.
+func (h *ShippingHook) ship(batch []*ShippedEntry) {
+	defer h.retryBackoff.Reset()
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), shippingRequestTimeout)
+		err := h.shipper.Ship(ctx, batch)
+		cancel()
+		if err == nil {
+			return
+		}
+		if attempt >= shippingMaxAttempts {
+			atomic.AddUint64(&h.numDropped, uint64(len(batch)))
+			// We can't use the logger here since that could result in an endless
+			// loop of failed shipping attempts.
+			fmt.Fprintf(os.Stderr, "could not ship %d log entries after %d attempts: %s\n", len(batch), attempt, err.Error())
+			return
+		}
+		select {
+		case <-time.After(h.retryBackoff.Duration()):
+		case <-h.stop:
+			// Don't keep retrying for long if we are stopping.
+			if attempt >= 2 {
+				atomic.AddUint64(&h.numDropped, uint64(len(batch)))
+				return
+			}
+		}
+	}
+}
+
+// newShippedEntry serializes the given entry. Field values are encoded in the
+// same way as by log.JSONFormatter.
+func newShippedEntry(entry *log.Entry) (*ShippedEntry, error) {
+	data := make(log.Fields, len(entry.Data)+3)
+	for key, value := range entry.Data {
+		if err, ok := value.(error); ok {
+			data[key] = err.Error()
+		} else {
+			data[key] = value
+		}
+	}
+	data["time"] = entry.Time.UTC().Format(time.RFC3339Nano)
+	data["level"] = entry.Level.String()
+	data["msg"] = entry.Message
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode log entry for shipping: %s", err.Error())
+	}
+	return &ShippedEntry{
+		Time:      entry.Time,
+		Level:     entry.Level,
+		Subsystem: Subsystem(entry),
+		JSON:      encoded,
+	}, nil
+}
@@ -0,0 +1,197 @@
+package loghooks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingShipper struct {
+	mu           sync.Mutex
+	numFailures  int
+	numAttempts  int
+	shippedLines []string
+}
+
+func (r *recordingShipper) Ship(ctx context.Context, entries []*ShippedEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.numAttempts++
+	if r.numAttempts <= r.numFailures {
+		return errors.New("log store unavailable")
+	}
+	for _, entry := range entries {
+		r.shippedLines = append(r.shippedLines, string(entry.JSON))
+	}
+	return nil
+}
+
+func newTestLogger(hook log.Hook) *log.Logger {
+	logger := log.New()
+	logger.SetOutput(ioutil.Discard)
+	logger.SetLevel(log.DebugLevel)
+	logger.AddHook(hook)
+	return logger
+}
+
+func TestShippingHook(t *testing.T) {
+	shipper := &recordingShipper{numFailures: 1}
+	hook, err := NewShippingHook(ShippingHookConfig{
+		Shipper: shipper,
+		Filter: func(entry *log.Entry) bool {
+			return entry.Level <= log.InfoLevel
+		},
+	})
+	require.NoError(t, err)
+	logger := newTestLogger(hook)
+
+	logger.WithField("numOrders", 3).Info("validated orders")
+	logger.Debug("filtered out")
+	logger.WithError(errors.New("timed out")).Warn("could not validate orders")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, hook.Stop(ctx))
+
+	// The first attempt fails and the batch is retried.
+	assert.Equal(t, 2, shipper.numAttempts)
+	require.Len(t, shipper.shippedLines, 2)
+	var first map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(shipper.shippedLines[0]), &first))
+	assert.Equal(t, "validated orders", first["msg"])
+	assert.Equal(t, "info", first["level"])
+	assert.Equal(t, float64(3), first["numOrders"])
+	var second map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(shipper.shippedLines[1]), &second))
+	assert.Equal(t, "timed out", second["error"])
+	assert.Equal(t, uint64(0), hook.NumDropped())
+}
+
+type blockingShipper struct {
+	shipping chan struct{}
+	unblock  chan struct{}
+}
+
+func (b *blockingShipper) Ship(ctx context.Context, entries []*ShippedEntry) error {
+	select {
+	case b.shipping <- struct{}{}:
+	default:
+	}
+	<-b.unblock
+	return nil
+}
+
+func TestShippingHookDropsEntriesWhenBufferIsFull(t *testing.T) {
+	shipper := &blockingShipper{
+		shipping: make(chan struct{}, 1),
+		unblock:  make(chan struct{}),
+	}
+	hook, err := NewShippingHook(ShippingHookConfig{
+		Shipper:       shipper,
+		BufferSize:    5,
+		FlushInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	logger := newTestLogger(hook)
+
+	// Wait for the hook to get stuck shipping the first entry.
+	logger.Info("message")
+	select {
+	case <-shipper.shipping:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for first entry to be shipped")
+	}
+
+	// Logging must never block, even though the shipper is blocked.
+	for i := 0; i < 20; i++ {
+		logger.Info("message")
+	}
+	assert.Equal(t, uint64(15), hook.NumDropped())
+
+	close(shipper.unblock)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, hook.Stop(ctx))
+}
+
+func TestElasticsearchShipper(t *testing.T) {
+	var requestPath string
+	var requestLines []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPath = r.URL.Path
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			requestLines = append(requestLines, scanner.Text())
+		}
+		_, _ = w.Write([]byte(`{"errors": false}`))
+	}))
+	defer server.Close()
+
+	shipper := NewElasticsearchShipper(server.URL+"/", "mesh-logs")
+	entries := []*ShippedEntry{
+		{Time: time.Now(), Level: log.InfoLevel, Subsystem: "p2p", JSON: []byte(`{"msg":"a"}`)},
+		{Time: time.Now(), Level: log.WarnLevel, Subsystem: "core", JSON: []byte(`{"msg":"b"}`)},
+	}
+	require.NoError(t, shipper.Ship(context.Background(), entries))
+
+	assert.Equal(t, "/_bulk", requestPath)
+	expectedLines := []string{
+		`{"index":{"_index":"mesh-logs"}}`,
+		`{"msg":"a"}`,
+		`{"index":{"_index":"mesh-logs"}}`,
+		`{"msg":"b"}`,
+	}
+	assert.Equal(t, expectedLines, requestLines)
+}
+
+func TestLokiShipper(t *testing.T) {
+	var requestPath string
+	var pushRequest lokiPushRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPath = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&pushRequest))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	timestamp := time.Unix(1585744215, 0)
+	shipper := NewLokiShipper(server.URL, "0x-mesh")
+	entries := []*ShippedEntry{
+		{Time: timestamp, Level: log.InfoLevel, Subsystem: "p2p", JSON: []byte(`{"msg":"a"}`)},
+		{Time: timestamp, Level: log.InfoLevel, Subsystem: "p2p", JSON: []byte(`{"msg":"b"}`)},
+		{Time: timestamp, Level: log.WarnLevel, Subsystem: "core", JSON: []byte(`{"msg":"c"}`)},
+	}
+	require.NoError(t, shipper.Ship(context.Background(), entries))
+
+	assert.Equal(t, "/loki/api/v1/push", requestPath)
+	require.Len(t, pushRequest.Streams, 2)
+	assert.Equal(t, map[string]string{"job": "0x-mesh", "level": "info", "subsystem": "p2p"}, pushRequest.Streams[0].Stream)
+	assert.Equal(t, [][2]string{{"1585744215000000000", `{"msg":"a"}`}, {"1585744215000000000", `{"msg":"b"}`}}, pushRequest.Streams[0].Values)
+	assert.Equal(t, map[string]string{"job": "0x-mesh", "level": "warning", "subsystem": "core"}, pushRequest.Streams[1].Stream)
+}
+
+func TestShipperReturnsErrorForFailedRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("overloaded"))
+	}))
+	defer server.Close()
+
+	err := NewLokiShipper(server.URL, "0x-mesh").Ship(context.Background(), []*ShippedEntry{
+		{Time: time.Now(), Level: log.InfoLevel, Subsystem: "p2p", JSON: []byte(`{}`)},
+	})
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "503"))
+}
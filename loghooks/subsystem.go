@@ -0,0 +1,109 @@
+package loghooks
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const meshPackagePrefix = "github.com/0xProject/0x-mesh/"
+
+// subsystemPackages maps package paths (relative to the 0x Mesh module) to the
+// name of the subsystem they belong to. Packages which are not listed here
+// belong to the subsystem named after the first element of their path.
+var subsystemPackages = map[string]string{
+	"core/ordersync":        "ordersync",
+	"ethereum/blockwatch":   "blockwatch",
+	"ethereum/ethrpcclient": "ethrpc",
+	"ethereum/ratelimit":    "ethrpc",
+	"zeroex/orderwatch":     "orderwatch",
+	"zeroex/ordervalidator": "ordervalidator",
+	"cmd/mesh":              "rpc",
+}
+
+// OtherSubsystem is the subsystem of log entries which were not logged by 0x
+// Mesh packages or whose caller is unknown.
+const OtherSubsystem = "other"
+
+// Subsystem returns the name of the subsystem (e.g. "p2p" or "orderwatch")
+// that logged the given entry. It relies on the caller of the entry, so it
+// returns OtherSubsystem unless caller reporting is enabled for the logger.
+func Subsystem(entry *log.Entry) string {
+	if entry.Caller == nil {
+		return OtherSubsystem
+	}
+	return subsystemForFunction(entry.Caller.Function)
+}
+
+// subsystemForFunction returns the subsystem for a fully qualified function
+// name such as "github.com/0xProject/0x-mesh/p2p.(*Node).Start".
+func subsystemForFunction(function string) string {
+	if !strings.HasPrefix(function, meshPackagePrefix) {
+		return OtherSubsystem
+	}
+	relativeName := strings.TrimPrefix(function, meshPackagePrefix)
+	// The package path ends at the first "." after the last "/".
+	lastSlash := strings.LastIndex(relativeName, "/")
+	packagePath := relativeName
+	if dot := strings.Index(relativeName[lastSlash+1:], "."); dot != -1 {
+		packagePath = relativeName[:lastSlash+1+dot]
+	}
+
+	// Find the most specific match in subsystemPackages.
+	for path := packagePath; path != ""; {
+		if subsystem, found := subsystemPackages[path]; found {
+			return subsystem
+		}
+		slash := strings.LastIndex(path, "/")
+		if slash == -1 {
+			break
+		}
+		path = path[:slash]
+	}
+	return strings.Split(packagePath, "/")[0]
+}
+
+// SubsystemHook is a logger hook that adds the subsystem which logged each
+// entry to its fields.
+type SubsystemHook struct{}
+
+// NewSubsystemHook creates and returns a new SubsystemHook.
+func NewSubsystemHook() *SubsystemHook {
+	return &SubsystemHook{}
+}
+
+// Ensure that SubsystemHook implements log.Hook.
+var _ log.Hook = &SubsystemHook{}
+
+func (h *SubsystemHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h *SubsystemHook) Fire(entry *log.Entry) error {
+	entry.Data["subsystem"] = Subsystem(entry)
+	return nil
+}
+
+// ParseSubsystemLevels parses a comma-separated list of subsystem=level pairs
+// (e.g. "p2p=warn,orderwatch=debug") as accepted by the LOG_LEVELS environment
+// variable.
+func ParseSubsystemLevels(levels string) (map[string]log.Level, error) {
+	result := map[string]log.Level{}
+	for _, pair := range strings.Split(levels, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.Split(pair, "=")
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid subsystem log level %q: expected subsystem=level", pair)
+		}
+		level, err := log.ParseLevel(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, err
+		}
+		result[strings.TrimSpace(parts[0])] = level
+	}
+	return result, nil
+}
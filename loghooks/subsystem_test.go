@@ -0,0 +1,174 @@
+package loghooks
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubsystemForFunction(t *testing.T) {
+	testCases := []struct {
+		function string
+		expected string
+	}{
+		{"github.com/0xProject/0x-mesh/p2p.(*Node).Start", "p2p"},
+		{"github.com/0xProject/0x-mesh/p2p/banner.(*Banner).ProtectIP", "p2p"},
+		{"github.com/0xProject/0x-mesh/core.(*App).Start.func1", "core"},
+		{"github.com/0xProject/0x-mesh/core/ordersync.(*Service).GetOrders", "ordersync"},
+		{"github.com/0xProject/0x-mesh/zeroex/orderwatch.(*Watcher).add", "orderwatch"},
+		{"github.com/0xProject/0x-mesh/zeroex/orderwatch/decoder.New", "orderwatch"},
+		{"github.com/0xProject/0x-mesh/zeroex.(*AssetDataDecoder).Decode", "zeroex"},
+		{"github.com/0xProject/0x-mesh/ethereum/blockwatch.(*Watcher).pollNextBlock", "blockwatch"},
+		{"github.com/0xProject/0x-mesh/cmd/mesh.(*rpcHandler).AddOrders", "rpc"},
+		{"github.com/sirupsen/logrus.(*Entry).Info", OtherSubsystem},
+		{"main.main", OtherSubsystem},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, subsystemForFunction(testCase.function), testCase.function)
+	}
+}
+
+func TestParseSubsystemLevels(t *testing.T) {
+	levels, err := ParseSubsystemLevels(" p2p=warn, orderwatch=debug,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]log.Level{
+		"p2p":        log.WarnLevel,
+		"orderwatch": log.DebugLevel,
+	}, levels)
+
+	levels, err = ParseSubsystemLevels("")
+	require.NoError(t, err)
+	assert.Empty(t, levels)
+
+	_, err = ParseSubsystemLevels("p2p")
+	assert.Error(t, err)
+	_, err = ParseSubsystemLevels("p2p=loud")
+	assert.Error(t, err)
+}
+
+type countingFormatter struct {
+	count int
+}
+
+func (c *countingFormatter) Format(entry *log.Entry) ([]byte, error) {
+	c.count++
+	return []byte(entry.Message), nil
+}
+
+func newTestEntry(function string, level log.Level, message string, timestamp time.Time) *log.Entry {
+	entry := log.NewEntry(log.New())
+	entry.Caller = &runtime.Frame{Function: function}
+	entry.Level = level
+	entry.Message = message
+	entry.Time = timestamp
+	return entry
+}
+
+func TestFilterFormatterSubsystemLevels(t *testing.T) {
+	inner := &countingFormatter{}
+	formatter := NewFilterFormatter(inner, FilterConfig{
+		DefaultLevel: log.InfoLevel,
+		SubsystemLevels: map[string]log.Level{
+			"p2p":        log.WarnLevel,
+			"orderwatch": log.DebugLevel,
+		},
+	})
+	assert.Equal(t, log.DebugLevel, formatter.MaxLevel())
+
+	now := time.Now()
+	testCases := []struct {
+		function string
+		level    log.Level
+		expected bool
+	}{
+		{"github.com/0xProject/0x-mesh/p2p.(*Node).Start", log.InfoLevel, false},
+		{"github.com/0xProject/0x-mesh/p2p.(*Node).Start", log.WarnLevel, true},
+		{"github.com/0xProject/0x-mesh/zeroex/orderwatch.(*Watcher).add", log.DebugLevel, true},
+		{"github.com/0xProject/0x-mesh/zeroex/orderwatch.(*Watcher).add", log.TraceLevel, false},
+		{"github.com/0xProject/0x-mesh/core.(*App).Start", log.InfoLevel, true},
+		{"github.com/0xProject/0x-mesh/core.(*App).Start", log.DebugLevel, false},
+	}
+	for _, testCase := range testCases {
+		entry := newTestEntry(testCase.function, testCase.level, "message", now)
+		output, err := formatter.Format(entry)
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, len(output) > 0, "%s at %s", testCase.function, testCase.level)
+	}
+}
+
+func TestFilterFormatterSampling(t *testing.T) {
+	inner := &countingFormatter{}
+	formatter := NewFilterFormatter(inner, FilterConfig{
+		DefaultLevel:       log.TraceLevel,
+		SamplingInitial:    3,
+		SamplingThereafter: 5,
+	})
+
+	function := "github.com/0xProject/0x-mesh/core.(*App).handleMessages"
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		_, err := formatter.Format(newTestEntry(function, log.InfoLevel, "received new valid order from peer", start))
+		require.NoError(t, err)
+	}
+	// The first 3 entries are logged, followed by every 5th entry of the
+	// remaining 17.
+	assert.Equal(t, 6, inner.count)
+
+	// Warnings and errors are never sampled.
+	for i := 0; i < 20; i++ {
+		_, err := formatter.Format(newTestEntry(function, log.WarnLevel, "received new valid order from peer", start))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 26, inner.count)
+
+	// Counts are reset every sampling interval.
+	_, err := formatter.Format(newTestEntry(function, log.InfoLevel, "received new valid order from peer", start.Add(defaultSamplingInterval)))
+	require.NoError(t, err)
+	assert.Equal(t, 27, inner.count)
+}
+
+type countingHook struct {
+	count int
+}
+
+func (h *countingHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h *countingHook) Fire(entry *log.Entry) error {
+	h.count++
+	return nil
+}
+
+func TestFilterFormatterHooks(t *testing.T) {
+	inner := &countingFormatter{}
+	formatter := NewFilterFormatter(inner, FilterConfig{
+		DefaultLevel:       log.InfoLevel,
+		SubsystemLevels:    map[string]log.Level{"p2p": log.WarnLevel},
+		SamplingInitial:    1,
+		SamplingThereafter: 0,
+	})
+	hook := &countingHook{}
+	formatter.AddHook(hook)
+
+	start := time.Now()
+	entries := []*log.Entry{
+		newTestEntry("github.com/0xProject/0x-mesh/core.(*App).Start", log.InfoLevel, "message", start),
+		// Dropped because of the level of the subsystem.
+		newTestEntry("github.com/0xProject/0x-mesh/p2p.(*Node).Start", log.InfoLevel, "other message", start),
+		// Dropped because of sampling.
+		newTestEntry("github.com/0xProject/0x-mesh/core.(*App).Start", log.InfoLevel, "message", start),
+		newTestEntry("github.com/0xProject/0x-mesh/p2p.(*Node).Start", log.WarnLevel, "message", start),
+	}
+	for _, entry := range entries {
+		_, err := formatter.Format(entry)
+		require.NoError(t, err)
+	}
+	// Hooks are only fired for entries which are not dropped.
+	assert.Equal(t, 2, inner.count)
+	assert.Equal(t, 2, hook.count)
+}
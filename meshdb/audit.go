@@ -0,0 +1,113 @@
+package meshdb
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/0xProject/0x-mesh/db"
+)
+
+// AuditRecord is the database representation of an entry in the audit log of
+// mutating RPC calls.
+type AuditRecord struct {
+	// Key uniquely identifies the record. Keys sort in the order the records
+	// were created.
+	Key string
+	// Time is when the call was made.
+	Time time.Time
+	// Method is the name of the RPC method that was called.
+	Method string
+	// Caller identifies the caller, if known.
+	Caller string
+	// ParamsDigest is the Keccak256 hash of the JSON-encoded parameters.
+	ParamsDigest string
+	// Success is true if the call did not return an error.
+	Success bool
+	// Error is the error returned by the call, if any.
+	Error string
+	// Result is a short summary of the outcome of a successful call.
+	Result string
+}
+
+// ID returns the AuditRecord's ID
+func (r AuditRecord) ID() []byte {
+	return []byte(r.Key)
+}
+
+// AuditRecordsCollection represents a DB collection of audit records
+type AuditRecordsCollection struct {
+	*db.Collection
+	timeIndex *db.Index
+}
+
+func setupAuditRecords(database *db.DB) (*AuditRecordsCollection, error) {
+	col, err := database.NewCollection("auditRecord", &AuditRecord{})
+	if err != nil {
+		return nil, err
+	}
+	timeIndex := col.AddIndex("time", func(m db.Model) []byte {
+		return auditTimeKey(m.(*AuditRecord).Time)
+	})
+	return &AuditRecordsCollection{
+		Collection: col,
+		timeIndex:  timeIndex,
+	}, nil
+}
+
+// auditTimeKey returns a representation of t which sorts in chronological
+// order. Unlike RFC3339Nano, it always has the same length.
+func auditTimeKey(t time.Time) []byte {
+	return []byte(fmt.Sprintf("%020d", t.UnixNano()))
+}
+
+// InsertAuditRecord sets the key of the given record and inserts it into the
+// database.
+func (m *MeshDB) InsertAuditRecord(record *AuditRecord) error {
+	// Multiple records might be created in the same nanosecond, so we add a
+	// random suffix to the key.
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	record.Key = fmt.Sprintf("%s|%s", auditTimeKey(record.Time), hex.EncodeToString(suffix))
+	return m.AuditRecords.Insert(record)
+}
+
+// FindAuditRecordsSince returns all audit records created at or after the given
+// time in chronological order.
+func (m *MeshDB) FindAuditRecordsSince(since time.Time) ([]*AuditRecord, error) {
+	start := auditTimeKey(since)
+	// Time keys always have 20 digits, so no key is greater than this one.
+	limit := []byte("99999999999999999999")
+	filter := m.AuditRecords.timeIndex.RangeFilter(start, limit)
+	records := []*AuditRecord{}
+	if err := m.AuditRecords.NewQuery(filter).Run(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// DeleteAuditRecordsBefore removes all audit records created before the given
+// time and returns the number of removed records.
+func (m *MeshDB) DeleteAuditRecordsBefore(before time.Time) (int, error) {
+	txn := m.AuditRecords.OpenTransaction()
+	defer func() {
+		_ = txn.Discard()
+	}()
+	filter := m.AuditRecords.timeIndex.RangeFilter(auditTimeKey(time.Unix(0, 0)), auditTimeKey(before))
+	records := []*AuditRecord{}
+	if err := m.AuditRecords.NewQuery(filter).Run(&records); err != nil {
+		return 0, err
+	}
+	for _, record := range records {
+		if err := txn.Delete(record.ID()); err != nil {
+			return 0, err
+		}
+	}
+	if err := txn.Commit(); err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
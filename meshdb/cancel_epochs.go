@@ -0,0 +1,89 @@
+package meshdb
+
+import (
+	"math/big"
+
+	"github.com/0xProject/0x-mesh/db"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CancelEpoch is the database representation of the order epoch set by a
+// maker for a sender address via the 0x Exchange `cancelOrdersUpTo` method.
+// All orders from the maker with that sender address and a salt less than the
+// epoch are cancelled.
+type CancelEpoch struct {
+	MakerAddress       common.Address
+	OrderSenderAddress common.Address
+	OrderEpoch         *big.Int
+}
+
+// ID returns the CancelEpoch's ID
+func (c CancelEpoch) ID() []byte {
+	return cancelEpochID(c.MakerAddress, c.OrderSenderAddress)
+}
+
+func cancelEpochID(makerAddress common.Address, orderSenderAddress common.Address) []byte {
+	return append(makerAddress.Bytes(), orderSenderAddress.Bytes()...)
+}
+
+// CancelEpochsCollection represents a DB collection of order epochs, indexed
+// by maker and sender address.
+type CancelEpochsCollection struct {
+	*db.Collection
+}
+
+func setupCancelEpochs(database *db.DB) (*CancelEpochsCollection, error) {
+	col, err := database.NewCollection("cancelEpoch", &CancelEpoch{})
+	if err != nil {
+		return nil, err
+	}
+	return &CancelEpochsCollection{
+		Collection: col,
+	}, nil
+}
+
+// SaveCancelEpoch stores the given order epoch for the given maker and sender
+// address. Since order epochs can only increase, it does nothing if a greater
+// epoch is already stored.
+func (m *MeshDB) SaveCancelEpoch(makerAddress common.Address, orderSenderAddress common.Address, orderEpoch *big.Int) error {
+	cancelEpoch := &CancelEpoch{
+		MakerAddress:       makerAddress,
+		OrderSenderAddress: orderSenderAddress,
+		OrderEpoch:         orderEpoch,
+	}
+	var existing CancelEpoch
+	if err := m.CancelEpochs.FindByID(cancelEpoch.ID(), &existing); err != nil {
+		if _, ok := err.(db.NotFoundError); !ok {
+			return err
+		}
+		return m.CancelEpochs.Insert(cancelEpoch)
+	}
+	if existing.OrderEpoch.Cmp(orderEpoch) >= 0 {
+		return nil
+	}
+	return m.CancelEpochs.Update(cancelEpoch)
+}
+
+// DeleteCancelEpoch deletes the order epoch stored for the given maker and
+// sender address, if any.
+func (m *MeshDB) DeleteCancelEpoch(makerAddress common.Address, orderSenderAddress common.Address) error {
+	if err := m.CancelEpochs.Delete(cancelEpochID(makerAddress, orderSenderAddress)); err != nil {
+		if _, ok := err.(db.NotFoundError); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindCancelEpoch returns the order epoch stored for the given maker and
+// sender address or nil if there is none.
+func (m *MeshDB) FindCancelEpoch(makerAddress common.Address, orderSenderAddress common.Address) (*big.Int, error) {
+	var cancelEpoch CancelEpoch
+	if err := m.CancelEpochs.FindByID(cancelEpochID(makerAddress, orderSenderAddress), &cancelEpoch); err != nil {
+		if _, ok := err.(db.NotFoundError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cancelEpoch.OrderEpoch, nil
+}
@@ -0,0 +1,116 @@
+package meshdb
+
+import (
+	"sort"
+	"time"
+
+	"github.com/0xProject/0x-mesh/db"
+)
+
+// KnownPeer is the database representation of a peer Mesh has been connected
+// to. Known peers are dialed right away when Mesh starts.
+type KnownPeer struct {
+	// PeerID is the base58-encoded ID of the peer.
+	PeerID string
+	// Addrs are the multiaddresses the peer can be dialed at.
+	Addrs []string
+	// Score is the score of the peer when Mesh was last connected to it.
+	Score int
+	// LastSeen is the last time Mesh was connected to the peer.
+	LastSeen time.Time
+}
+
+// ID returns the KnownPeer's ID
+func (p KnownPeer) ID() []byte {
+	return []byte(p.PeerID)
+}
+
+// KnownPeersCollection represents a DB collection of known peers
+type KnownPeersCollection struct {
+	*db.Collection
+	lastSeenIndex *db.Index
+}
+
+func setupKnownPeers(database *db.DB) (*KnownPeersCollection, error) {
+	col, err := database.NewCollection("knownPeer", &KnownPeer{})
+	if err != nil {
+		return nil, err
+	}
+	lastSeenIndex := col.AddIndex("lastSeen", func(m db.Model) []byte {
+		return auditTimeKey(m.(*KnownPeer).LastSeen)
+	})
+	return &KnownPeersCollection{
+		Collection:    col,
+		lastSeenIndex: lastSeenIndex,
+	}, nil
+}
+
+// SaveKnownPeers inserts the given peers or updates them if they are already
+// known.
+func (m *MeshDB) SaveKnownPeers(peers []*KnownPeer) error {
+	txn := m.KnownPeers.OpenTransaction()
+	defer func() {
+		_ = txn.Discard()
+	}()
+	for _, knownPeer := range peers {
+		var existing KnownPeer
+		if err := m.KnownPeers.FindByID(knownPeer.ID(), &existing); err != nil {
+			if _, ok := err.(db.NotFoundError); !ok {
+				return err
+			}
+			if err := txn.Insert(knownPeer); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := txn.Update(knownPeer); err != nil {
+			return err
+		}
+	}
+	return txn.Commit()
+}
+
+// FindBestKnownPeers returns up to max peers which were last seen at or after
+// the given time. Peers with a higher score come first and peers with the same
+// score are sorted by when they were last seen, most recent first.
+func (m *MeshDB) FindBestKnownPeers(since time.Time, max int) ([]*KnownPeer, error) {
+	// Time keys always have 20 digits, so no key is greater than this one.
+	filter := m.KnownPeers.lastSeenIndex.RangeFilter(auditTimeKey(since), []byte("99999999999999999999"))
+	knownPeers := []*KnownPeer{}
+	if err := m.KnownPeers.NewQuery(filter).Run(&knownPeers); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(knownPeers, func(i, j int) bool {
+		if knownPeers[i].Score != knownPeers[j].Score {
+			return knownPeers[i].Score > knownPeers[j].Score
+		}
+		return knownPeers[i].LastSeen.After(knownPeers[j].LastSeen)
+	})
+	if len(knownPeers) > max {
+		knownPeers = knownPeers[:max]
+	}
+	return knownPeers, nil
+}
+
+// DeleteKnownPeersLastSeenBefore removes all peers which were last seen before
+// the given time. It returns the number of peers removed.
+func (m *MeshDB) DeleteKnownPeersLastSeenBefore(before time.Time) (int, error) {
+	txn := m.KnownPeers.OpenTransaction()
+	defer func() {
+		_ = txn.Discard()
+	}()
+	filter := m.KnownPeers.lastSeenIndex.RangeFilter(auditTimeKey(time.Unix(0, 0)), auditTimeKey(before))
+	knownPeers := []*KnownPeer{}
+	if err := m.KnownPeers.NewQuery(filter).Run(&knownPeers); err != nil {
+		return 0, err
+	}
+	for _, knownPeer := range knownPeers {
+		if err := txn.Delete(knownPeer.ID()); err != nil {
+			return 0, err
+		}
+	}
+	if err := txn.Commit(); err != nil {
+		return 0, err
+	}
+	return len(knownPeers), nil
+}
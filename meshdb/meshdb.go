@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/0xProject/0x-mesh/constants"
@@ -41,6 +43,74 @@ type Order struct {
 	// IsPinned indicates whether or not the order is pinned. Pinned orders are
 	// not removed from the database unless they become unfillable.
 	IsPinned bool
+	// TransferSimulationFailed indicates that simulating the transfer of the
+	// maker's assets failed when the order was added. Such orders might not
+	// actually be fillable.
+	TransferSimulationFailed bool
+	// LastValidatedBlockNumber is the number of the block at which the order was
+	// last validated. It is nil for orders stored by older versions of Mesh.
+	LastValidatedBlockNumber *big.Int
+	// LastValidatedBlockHash is the hash of the block at which the order was last
+	// validated.
+	LastValidatedBlockHash common.Hash
+	// LastValidationResult is the result of the last validation.
+	// ValidationResultFillable indicates that the order was fillable. Otherwise
+	// it is the code of the RejectedOrderStatus.
+	LastValidationResult string
+	// Metadata is the opaque metadata which was attached to the order when it
+	// was added via AddOrders. It is never shared with peers.
+	Metadata []byte
+	// Source is how the order was received: "api", "gossipsub" or
+	// "ordersync". It is empty for orders stored by older versions of Mesh.
+	Source string
+	// SourcePeerID is the ID of the peer the order was received from. It is
+	// empty if the order was not received from a peer.
+	SourcePeerID string
+	// ReceivedAt is when the order was first stored.
+	ReceivedAt time.Time
+	// ReplacedBy is the hash of the order which replaced this order. Replaced
+	// orders are flagged for removal but kept until they expire, so that they
+	// can't be added again.
+	ReplacedBy common.Hash
+}
+
+// IsReplaced returns true if the order was replaced by a newer order.
+func (o Order) IsReplaced() bool {
+	return o.ReplacedBy != common.Hash{}
+}
+
+// ValidationResultFillable is the LastValidationResult of orders which were
+// fillable when they were last validated.
+const ValidationResultFillable = "FILLABLE"
+
+// Order statuses returned by Order.Status besides the codes of rejected order
+// statuses.
+const (
+	OrderStatusFillable = "FILLABLE"
+	OrderStatusRemoved  = "REMOVED"
+)
+
+// WasValidatedAt returns true if the order was last validated at the given
+// block. If so, there is no need to validate it again until a new block has
+// been mined.
+func (o Order) WasValidatedAt(block *miniheader.MiniHeader) bool {
+	return o.LastValidatedBlockNumber != nil &&
+		o.LastValidatedBlockNumber.Cmp(block.Number) == 0 &&
+		o.LastValidatedBlockHash == block.Hash
+}
+
+// Status returns OrderStatusFillable if the order has not been flagged for
+// removal. Otherwise it returns the code of the reason the order was rejected
+// when it was last validated or OrderStatusRemoved if it was removed for a
+// different reason (e.g. because the database was full).
+func (o Order) Status() string {
+	if !o.IsRemoved {
+		return OrderStatusFillable
+	}
+	if o.LastValidationResult != "" && o.LastValidationResult != ValidationResultFillable {
+		return o.LastValidationResult
+	}
+	return OrderStatusRemoved
 }
 
 // ID returns the Order's ID
@@ -54,6 +124,16 @@ type Metadata struct {
 	MaxExpirationTime                 *big.Int
 	EthRPCRequestsSentInCurrentUTCDay int
 	StartOfCurrentUTCDay              time.Time
+	// EthRPCRateLimitUnits is the unit EthRPCRequestsSentInCurrentUTCDay is
+	// counted in ("requests" or "computeUnits"). It is empty for databases
+	// created by older versions of Mesh, which always counted requests.
+	EthRPCRateLimitUnits string
+	// LastOrderEventSequenceNumber is the sequence number of the last order
+	// event that was emitted.
+	LastOrderEventSequenceNumber uint64
+	// BootstrapListTimestamp is the timestamp of the signed bootstrap list
+	// that was last fetched from BOOTSTRAP_LIST_URL.
+	BootstrapListTimestamp time.Time
 }
 
 // ID returns the id used for the metadata collection (one per DB)
@@ -67,6 +147,12 @@ type MeshDB struct {
 	metadata                 *MetadataCollection
 	MiniHeaders              *MiniHeadersCollection
 	Orders                   *OrdersCollection
+	AuditRecords             *AuditRecordsCollection
+	KnownPeers               *KnownPeersCollection
+	MetricsSamples           *MetricsSamplesCollection
+	CancelEpochs             *CancelEpochsCollection
+	PendingOrderEvents       *PendingOrderEventsCollection
+	schemaVersion            *db.Collection
 	MiniHeaderRetentionLimit int
 }
 
@@ -80,11 +166,17 @@ type MiniHeadersCollection struct {
 type OrdersCollection struct {
 	*db.Collection
 	MakerAddressAndSaltIndex                     *db.Index
+	MakerAddressSenderAddressAndSaltIndex        *db.Index
 	MakerAddressTokenAddressTokenIDIndex         *db.Index
 	MakerAddressMakerFeeAssetAddressTokenIDIndex *db.Index
 	LastUpdatedIndex                             *db.Index
 	IsRemovedIndex                               *db.Index
 	ExpirationTimeIndex                          *db.Index
+	// The following indexes are used to count orders by asset pair, maker and
+	// status without decoding them.
+	IsRemovedAndAssetPairIndex    *db.Index
+	IsRemovedAndMakerAddressIndex *db.Index
+	StatusIndex                   *db.Index
 }
 
 // MetadataCollection represents a DB collection used to store instance metadata
@@ -92,13 +184,27 @@ type MetadataCollection struct {
 	*db.Collection
 }
 
-// New instantiates a new MeshDB instance
+// New instantiates a new MeshDB instance. Migrate must be called before the
+// database is used.
 func New(path string, contractAddresses ethereum.ContractAddresses) (*MeshDB, error) {
 	database, err := db.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	return newWithDatabase(database, contractAddresses)
+}
 
+// NewInMemory instantiates a new MeshDB instance which is only stored in
+// memory. Migrate must be called before the database is used.
+func NewInMemory(contractAddresses ethereum.ContractAddresses) (*MeshDB, error) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		return nil, err
+	}
+	return newWithDatabase(database, contractAddresses)
+}
+
+func newWithDatabase(database *db.DB, contractAddresses ethereum.ContractAddresses) (*MeshDB, error) {
 	miniHeaders, err := setupMiniHeaders(database)
 	if err != nil {
 		return nil, err
@@ -114,11 +220,47 @@ func New(path string, contractAddresses ethereum.ContractAddresses) (*MeshDB, er
 		return nil, err
 	}
 
+	auditRecords, err := setupAuditRecords(database)
+	if err != nil {
+		return nil, err
+	}
+
+	knownPeers, err := setupKnownPeers(database)
+	if err != nil {
+		return nil, err
+	}
+
+	metricsSamples, err := setupMetricsSamples(database)
+	if err != nil {
+		return nil, err
+	}
+
+	cancelEpochs, err := setupCancelEpochs(database)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingOrderEvents, err := setupPendingOrderEvents(database)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaVersion, err := setupSchemaVersion(database)
+	if err != nil {
+		return nil, err
+	}
+
 	return &MeshDB{
 		database:                 database,
 		metadata:                 metadata,
 		MiniHeaders:              miniHeaders,
 		Orders:                   orders,
+		AuditRecords:             auditRecords,
+		KnownPeers:               knownPeers,
+		MetricsSamples:           metricsSamples,
+		CancelEpochs:             cancelEpochs,
+		PendingOrderEvents:       pendingOrderEvents,
+		schemaVersion:            schemaVersion,
 		MiniHeaderRetentionLimit: defaultMiniHeaderRetentionLimit,
 	}, nil
 }
@@ -141,6 +283,13 @@ func setupOrders(database *db.DB, contractAddresses ethereum.ContractAddresses)
 		index := []byte(fmt.Sprintf("%s|%s", signedOrder.MakerAddress.Hex(), uint256ToConstantLengthBytes(signedOrder.Salt)))
 		return index
 	})
+	// Order epochs set via `cancelOrdersUpTo` are scoped to a maker and sender
+	// address, so the orders they cancel are found with this index.
+	makerAddressSenderAddressAndSaltIndex := col.AddIndex("makerAddressSenderAddressAndSalt", func(m db.Model) []byte {
+		signedOrder := m.(*Order).SignedOrder
+		index := []byte(fmt.Sprintf("%s|%s|%s", signedOrder.MakerAddress.Hex(), signedOrder.SenderAddress.Hex(), uint256ToConstantLengthBytes(signedOrder.Salt)))
+		return index
+	})
 	// TODO(fabio): Optimize this index callback since it gets called many times under-the-hood.
 	// We might want to parse the assetData once and store it's components in the DB. The trade-off
 	// here is compute time for storage space.
@@ -211,17 +360,44 @@ func setupOrders(database *db.DB, contractAddresses ethereum.ContractAddresses)
 		return []byte(fmt.Sprintf("%s|%s", pinnedString, expTimeString))
 	})
 
+	isRemovedAndAssetPairIndex := col.AddIndex("isRemovedAndAssetPair", func(m db.Model) []byte {
+		order := m.(*Order)
+		return []byte(fmt.Sprintf("%s|%s|%s", isRemovedPrefix(order), common.ToHex(order.SignedOrder.MakerAssetData), common.ToHex(order.SignedOrder.TakerAssetData)))
+	})
+
+	isRemovedAndMakerAddressIndex := col.AddIndex("isRemovedAndMakerAddress", func(m db.Model) []byte {
+		order := m.(*Order)
+		return []byte(fmt.Sprintf("%s|%s", isRemovedPrefix(order), strings.ToLower(order.SignedOrder.MakerAddress.Hex())))
+	})
+
+	statusIndex := col.AddIndex("status", func(m db.Model) []byte {
+		return []byte(m.(*Order).Status())
+	})
+
 	return &OrdersCollection{
 		Collection:                                   col,
 		MakerAddressTokenAddressTokenIDIndex:         makerAddressTokenAddressTokenIDIndex,
 		MakerAddressMakerFeeAssetAddressTokenIDIndex: makerAddressMakerFeeAssetAddressTokenIDIndex,
 		MakerAddressAndSaltIndex:                     makerAddressAndSaltIndex,
+		MakerAddressSenderAddressAndSaltIndex:        makerAddressSenderAddressAndSaltIndex,
 		LastUpdatedIndex:                             lastUpdatedIndex,
 		IsRemovedIndex:                               isRemovedIndex,
 		ExpirationTimeIndex:                          expirationTimeIndex,
+		IsRemovedAndAssetPairIndex:                   isRemovedAndAssetPairIndex,
+		IsRemovedAndMakerAddressIndex:                isRemovedAndMakerAddressIndex,
+		StatusIndex:                                  statusIndex,
 	}, nil
 }
 
+// isRemovedPrefix returns the prefix used to separate orders which were
+// flagged for removal from the others in indexes.
+func isRemovedPrefix(order *Order) string {
+	if order.IsRemoved {
+		return "1"
+	}
+	return "0"
+}
+
 func setupMiniHeaders(database *db.DB) (*MiniHeadersCollection, error) {
 	col, err := database.NewCollection("miniHeader", &miniheader.MiniHeader{})
 	if err != nil {
@@ -342,6 +518,24 @@ func (m *MeshDB) ClearAllMiniHeaders() error {
 	return m.clearMiniHeadersWithFilter(m.MiniHeaders.numberIndex.All())
 }
 
+// ClearAllOrders removes all stored orders from the database.
+func (m *MeshDB) ClearAllOrders() error {
+	txn := m.Orders.OpenTransaction()
+	defer func() {
+		_ = txn.Discard()
+	}()
+	var orders []*Order
+	if err := m.Orders.FindAll(&orders); err != nil {
+		return err
+	}
+	for _, order := range orders {
+		if err := txn.Delete(order.ID()); err != nil {
+			return err
+		}
+	}
+	return txn.Commit()
+}
+
 // ClearOldMiniHeaders removes all stored MiniHeaders with a block number less then
 // the given minBlockNumber.
 func (m *MeshDB) ClearOldMiniHeaders(minBlockNumber *big.Int) error {
@@ -458,16 +652,57 @@ func (m *MeshDB) FindOrdersByMakerAddressAndMaxSalt(makerAddress common.Address,
 	return orders, nil
 }
 
-// FindOrdersLastUpdatedBefore finds all orders where the LastUpdated time is less
-// than X
-func (m *MeshDB) FindOrdersLastUpdatedBefore(lastUpdated time.Time) ([]*Order, error) {
+// FindOrdersCancelledByEpoch finds all orders which are cancelled by the given
+// order epoch, i.e. all orders with the given maker and sender address and a
+// salt less than the epoch. Unlike FindOrdersByMakerAddressAndMaxSalt, the
+// epoch is exclusive, since the epoch emitted in CancelUpTo events is one
+// greater than the value passed to `cancelOrdersUpTo`.
+func (m *MeshDB) FindOrdersCancelledByEpoch(makerAddress common.Address, senderAddress common.Address, orderEpoch *big.Int) ([]*Order, error) {
+	prefix := fmt.Sprintf("%s|%s|", makerAddress.Hex(), senderAddress.Hex())
+	start := []byte(fmt.Sprintf("%s%080s", prefix, "0"))
+	limit := []byte(fmt.Sprintf("%s%s", prefix, uint256ToConstantLengthBytes(orderEpoch)))
+	filter := m.Orders.MakerAddressSenderAddressAndSaltIndex.RangeFilter(start, limit)
+	orders := []*Order{}
+	if err := m.Orders.NewQuery(filter).Run(&orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// FindOrdersLastUpdatedBefore finds up to max orders where the LastUpdated time
+// is less than X, least recently updated first. If max is 0, all such orders
+// are returned.
+func (m *MeshDB) FindOrdersLastUpdatedBefore(lastUpdated time.Time, max int) ([]*Order, error) {
+	filter := m.lastUpdatedBeforeFilter(lastUpdated)
+	orders := []*Order{}
+	if err := m.Orders.NewQuery(filter).Max(max).Run(&orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// CountOrdersLastUpdatedBefore returns the number of orders where the
+// LastUpdated time is less than X
+func (m *MeshDB) CountOrdersLastUpdatedBefore(lastUpdated time.Time) (int, error) {
+	return m.Orders.NewQuery(m.lastUpdatedBeforeFilter(lastUpdated)).Count()
+}
+
+func (m *MeshDB) lastUpdatedBeforeFilter(lastUpdated time.Time) *db.Filter {
 	start := []byte(time.Unix(0, 0).Format(time.RFC3339Nano))
 	limit := []byte(lastUpdated.UTC().Format(time.RFC3339Nano))
-	filter := m.Orders.LastUpdatedIndex.RangeFilter(start, limit)
+	return m.Orders.LastUpdatedIndex.RangeFilter(start, limit)
+}
+
+// FindAllOrdersSortedByExpirationTime finds all orders (including removed
+// orders), soonest-expiring first.
+func (m *MeshDB) FindAllOrdersSortedByExpirationTime() ([]*Order, error) {
 	orders := []*Order{}
-	if err := m.Orders.NewQuery(filter).Run(&orders); err != nil {
+	if err := m.Orders.FindAll(&orders); err != nil {
 		return nil, err
 	}
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].SignedOrder.ExpirationTimeSeconds.Cmp(orders[j].SignedOrder.ExpirationTimeSeconds) == -1
+	})
 	return orders, nil
 }
 
@@ -2,6 +2,7 @@ package meshdb
 
 import (
 	"math/big"
+	"strings"
 	"testing"
 	"time"
 
@@ -79,13 +80,20 @@ func TestOrderCRUDOperations(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []*Order{order}, orders)
 
-	orders, err = meshDB.FindOrdersLastUpdatedBefore(fiveMinutesFromNow)
+	orders, err = meshDB.FindOrdersLastUpdatedBefore(fiveMinutesFromNow, 0)
 	require.NoError(t, err)
 	assert.Equal(t, []*Order{order}, orders)
 
+	numOrders, err := meshDB.CountOrdersLastUpdatedBefore(fiveMinutesFromNow)
+	require.NoError(t, err)
+	assert.Equal(t, 1, numOrders)
+
 	// Update
 	modifiedOrder := foundOrder
 	modifiedOrder.FillableTakerAssetAmount = big.NewInt(0)
+	modifiedOrder.LastValidatedBlockNumber = big.NewInt(5)
+	modifiedOrder.LastValidatedBlockHash = common.HexToHash("0x5")
+	modifiedOrder.LastValidationResult = "OrderFullyFilled"
 	require.NoError(t, meshDB.Orders.Update(modifiedOrder))
 	foundModifiedOrder := &Order{}
 	require.NoError(t, meshDB.Orders.FindByID(modifiedOrder.ID(), foundModifiedOrder))
@@ -98,6 +106,53 @@ func TestOrderCRUDOperations(t *testing.T) {
 	assert.IsType(t, db.NotFoundError{}, err)
 }
 
+func TestOrderWasValidatedAt(t *testing.T) {
+	block := &miniheader.MiniHeader{
+		Hash:   common.HexToHash("0x5"),
+		Number: big.NewInt(5),
+	}
+
+	testCases := []struct {
+		name     string
+		order    Order
+		expected bool
+	}{
+		{
+			name:     "never validated",
+			order:    Order{},
+			expected: false,
+		},
+		{
+			name: "validated at same block",
+			order: Order{
+				LastValidatedBlockNumber: big.NewInt(5),
+				LastValidatedBlockHash:   common.HexToHash("0x5"),
+			},
+			expected: true,
+		},
+		{
+			name: "validated at earlier block",
+			order: Order{
+				LastValidatedBlockNumber: big.NewInt(4),
+				LastValidatedBlockHash:   common.HexToHash("0x4"),
+			},
+			expected: false,
+		},
+		{
+			name: "validated at reorged block",
+			order: Order{
+				LastValidatedBlockNumber: big.NewInt(5),
+				LastValidatedBlockHash:   common.HexToHash("0x6"),
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, tc.order.WasValidatedAt(block), tc.name)
+	}
+}
+
 func TestParseContractAddressesAndTokenIdsFromAssetData(t *testing.T) {
 	// ERC20 AssetData
 	erc20AssetData := common.Hex2Bytes("f47261b000000000000000000000000038ae374ecf4db50b0ff37125b591a04997106a32")
@@ -499,3 +554,435 @@ func TestPruneMiniHeadersAboveRetentionLimit(t *testing.T) {
 	remainingMiniHeaders, err := meshDB.MiniHeaders.Count()
 	assert.Equal(t, defaultMiniHeaderRetentionLimit, remainingMiniHeaders, "wrong number of MiniHeaders remaining")
 }
+
+func TestFindAuditRecordsSince(t *testing.T) {
+	meshDB, err := New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	start := time.Now()
+	records := []*AuditRecord{}
+	for i := 0; i < 3; i++ {
+		record := &AuditRecord{
+			Time:         start.Add(time.Duration(i) * time.Minute),
+			Method:       "mesh_addPeer",
+			ParamsDigest: "0x01",
+			Success:      true,
+		}
+		require.NoError(t, meshDB.InsertAuditRecord(record))
+		records = append(records, record)
+	}
+	// A second record with the same time must not overwrite the first.
+	duplicate := &AuditRecord{
+		Time:         records[2].Time,
+		Method:       "mesh_setMakerDenylist",
+		ParamsDigest: "0x02",
+		Error:        "something went wrong",
+	}
+	require.NoError(t, meshDB.InsertAuditRecord(duplicate))
+
+	actual, err := meshDB.FindAuditRecordsSince(time.Unix(0, 0))
+	require.NoError(t, err)
+	assert.Len(t, actual, 4)
+
+	actual, err = meshDB.FindAuditRecordsSince(records[1].Time)
+	require.NoError(t, err)
+	require.Len(t, actual, 3)
+	assert.Equal(t, records[1].Key, actual[0].Key)
+	for i := 1; i < len(actual); i++ {
+		assert.False(t, actual[i].Time.Before(actual[i-1].Time), "records should be in chronological order")
+	}
+}
+
+func TestDeleteAuditRecordsBefore(t *testing.T) {
+	meshDB, err := New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	start := time.Now()
+	records := []*AuditRecord{}
+	for i := 0; i < 3; i++ {
+		record := &AuditRecord{
+			Time:         start.Add(time.Duration(i) * time.Minute),
+			Method:       "mesh_addPeer",
+			ParamsDigest: "0x01",
+			Success:      true,
+		}
+		require.NoError(t, meshDB.InsertAuditRecord(record))
+		records = append(records, record)
+	}
+
+	numDeleted, err := meshDB.DeleteAuditRecordsBefore(records[2].Time)
+	require.NoError(t, err)
+	assert.Equal(t, 2, numDeleted)
+	actual, err := meshDB.FindAuditRecordsSince(time.Unix(0, 0))
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.Equal(t, records[2].Key, actual[0].Key)
+}
+
+func TestKnownPeers(t *testing.T) {
+	meshDB, err := New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	now := time.Now()
+	knownPeers := []*KnownPeer{
+		{PeerID: "peer0", Addrs: []string{"/ip4/1.2.3.4/tcp/60558"}, Score: 10, LastSeen: now.Add(-2 * time.Hour)},
+		{PeerID: "peer1", Addrs: []string{"/ip4/1.2.3.5/tcp/60558"}, Score: 20, LastSeen: now.Add(-48 * time.Hour)},
+		{PeerID: "peer2", Addrs: []string{"/ip4/1.2.3.6/tcp/60558"}, Score: 10, LastSeen: now.Add(-1 * time.Hour)},
+		{PeerID: "peer3", Addrs: []string{"/ip4/1.2.3.7/tcp/60558"}, Score: 5, LastSeen: now},
+	}
+	require.NoError(t, meshDB.SaveKnownPeers(knownPeers))
+
+	// Saving a known peer again updates it.
+	updatedPeer := &KnownPeer{PeerID: "peer3", Addrs: []string{"/ip4/1.2.3.8/tcp/60558"}, Score: 30, LastSeen: now}
+	require.NoError(t, meshDB.SaveKnownPeers([]*KnownPeer{updatedPeer}))
+	count, err := meshDB.KnownPeers.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	actual, err := meshDB.FindBestKnownPeers(now.Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	actualIDs := []string{}
+	for _, knownPeer := range actual {
+		actualIDs = append(actualIDs, knownPeer.PeerID)
+	}
+	assert.Equal(t, []string{"peer3", "peer2", "peer0"}, actualIDs)
+	assert.Equal(t, updatedPeer.Addrs, actual[0].Addrs)
+
+	actual, err = meshDB.FindBestKnownPeers(time.Unix(0, 0), 2)
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	assert.Equal(t, "peer3", actual[0].PeerID)
+	assert.Equal(t, "peer1", actual[1].PeerID)
+
+	removed, err := meshDB.DeleteKnownPeersLastSeenBefore(now.Add(-24 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	count, err = meshDB.KnownPeers.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestMetricsSamples(t *testing.T) {
+	meshDB, err := New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	// Use a ring buffer with 3 slots of 1 minute each.
+	start := time.Unix(0, 0).Add(1000 * time.Hour)
+	for i := 0; i < 5; i++ {
+		sample := &MetricsSample{
+			Time:      start.Add(time.Duration(i) * time.Minute),
+			NumOrders: i,
+		}
+		require.NoError(t, meshDB.SaveMetricsSample(sample, time.Minute, 3))
+	}
+
+	// The first two samples were overwritten.
+	count, err := meshDB.MetricsSamples.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	samples, err := meshDB.FindMetricsSamplesSince(time.Unix(0, 0))
+	require.NoError(t, err)
+	numOrders := []int{}
+	for _, sample := range samples {
+		numOrders = append(numOrders, sample.NumOrders)
+	}
+	assert.Equal(t, []int{2, 3, 4}, numOrders)
+
+	samples, err = meshDB.FindMetricsSamplesSince(start.Add(4 * time.Minute))
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	assert.Equal(t, 4, samples[0].NumOrders)
+}
+
+func TestCancelEpochs(t *testing.T) {
+	t.Parallel()
+	meshDB, err := New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	makerAddress := constants.GanacheAccount0
+	senderAddress := constants.GanacheAccount1
+	epoch, err := meshDB.FindCancelEpoch(makerAddress, constants.NullAddress)
+	require.NoError(t, err)
+	assert.Nil(t, epoch)
+
+	require.NoError(t, meshDB.SaveCancelEpoch(makerAddress, constants.NullAddress, big.NewInt(10)))
+	require.NoError(t, meshDB.SaveCancelEpoch(makerAddress, senderAddress, big.NewInt(20)))
+	// Epochs can only increase, so lower epochs are ignored.
+	require.NoError(t, meshDB.SaveCancelEpoch(makerAddress, constants.NullAddress, big.NewInt(5)))
+	epoch, err = meshDB.FindCancelEpoch(makerAddress, constants.NullAddress)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), epoch)
+	require.NoError(t, meshDB.SaveCancelEpoch(makerAddress, constants.NullAddress, big.NewInt(15)))
+	epoch, err = meshDB.FindCancelEpoch(makerAddress, constants.NullAddress)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(15), epoch)
+	epoch, err = meshDB.FindCancelEpoch(makerAddress, senderAddress)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(20), epoch)
+
+	require.NoError(t, meshDB.DeleteCancelEpoch(makerAddress, senderAddress))
+	require.NoError(t, meshDB.DeleteCancelEpoch(makerAddress, senderAddress))
+	epoch, err = meshDB.FindCancelEpoch(makerAddress, senderAddress)
+	require.NoError(t, err)
+	assert.Nil(t, epoch)
+}
+
+func TestPendingOrderEvents(t *testing.T) {
+	t.Parallel()
+	meshDB, err := New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	newPendingOrderEvents := func(index uint64, orderHash common.Hash) *PendingOrderEvents {
+		return &PendingOrderEvents{
+			Index:       index,
+			BlockNumber: big.NewInt(10),
+			OrderEvents: []*zeroex.OrderEvent{
+				{
+					OrderHash:                orderHash,
+					EndState:                 zeroex.ESOrderExpired,
+					FillableTakerAssetAmount: big.NewInt(50),
+				},
+			},
+		}
+	}
+	// Pending order events are returned in the order of their indexes, not
+	// in the order in which they were inserted.
+	require.NoError(t, meshDB.PendingOrderEvents.Insert(newPendingOrderEvents(256, common.HexToHash("0x2"))))
+	require.NoError(t, meshDB.PendingOrderEvents.Insert(newPendingOrderEvents(1, common.HexToHash("0x1"))))
+	require.NoError(t, meshDB.PendingOrderEvents.Insert(newPendingOrderEvents(257, common.HexToHash("0x3"))))
+	pendingOrderEvents, err := meshDB.FindAllPendingOrderEvents()
+	require.NoError(t, err)
+	require.Len(t, pendingOrderEvents, 3)
+	for i, expectedIndex := range []uint64{1, 256, 257} {
+		assert.Equal(t, expectedIndex, pendingOrderEvents[i].Index)
+	}
+	require.Len(t, pendingOrderEvents[0].OrderEvents, 1)
+	assert.Equal(t, common.HexToHash("0x1"), pendingOrderEvents[0].OrderEvents[0].OrderHash)
+	assert.Equal(t, big.NewInt(50), pendingOrderEvents[0].OrderEvents[0].FillableTakerAssetAmount)
+
+	require.NoError(t, meshDB.DeletePendingOrderEvents(256))
+	require.NoError(t, meshDB.DeletePendingOrderEvents(256))
+	pendingOrderEvents, err = meshDB.FindAllPendingOrderEvents()
+	require.NoError(t, err)
+	require.Len(t, pendingOrderEvents, 2)
+
+	require.NoError(t, meshDB.ClearAllPendingOrderEvents())
+	pendingOrderEvents, err = meshDB.FindAllPendingOrderEvents()
+	require.NoError(t, err)
+	assert.Empty(t, pendingOrderEvents)
+}
+
+func TestFindOrdersCancelledByEpoch(t *testing.T) {
+	t.Parallel()
+	meshDB, err := New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	makerAddress := constants.GanacheAccount0
+	otherSenderAddress := constants.GanacheAccount1
+	newOrder := func(senderAddress common.Address, salt int64) *zeroex.Order {
+		return &zeroex.Order{
+			ChainID:               big.NewInt(constants.TestChainID),
+			ExchangeAddress:       contractAddresses.Exchange,
+			MakerAddress:          makerAddress,
+			TakerAddress:          constants.NullAddress,
+			SenderAddress:         senderAddress,
+			FeeRecipientAddress:   common.HexToAddress("0xa258b39954cef5cb142fd567a46cddb31a670124"),
+			TakerAssetData:        common.Hex2Bytes("f47261b000000000000000000000000034d402f14d58e001d8efbe6585051bf9706aa064"),
+			TakerFeeAssetData:     constants.NullBytes,
+			MakerAssetData:        common.Hex2Bytes("025717920000000000000000000000001dc4c1cefef38a777b15aa20260a54e584b16c480000000000000000000000000000000000000000000000000000000000000001"),
+			MakerFeeAssetData:     constants.NullBytes,
+			Salt:                  big.NewInt(salt),
+			MakerFee:              big.NewInt(0),
+			TakerFee:              big.NewInt(0),
+			MakerAssetAmount:      big.NewInt(3551808554499581700),
+			TakerAssetAmount:      big.NewInt(1),
+			ExpirationTimeSeconds: big.NewInt(1548619325),
+		}
+	}
+	orders := insertRawOrders(t, meshDB, []*zeroex.Order{
+		newOrder(constants.NullAddress, 1),
+		newOrder(constants.NullAddress, 10),
+		newOrder(otherSenderAddress, 1),
+		newOrder(otherSenderAddress, 10),
+	}, false)
+
+	// The epoch only applies to orders with the same sender address and a
+	// lower salt.
+	cancelledOrders, err := meshDB.FindOrdersCancelledByEpoch(makerAddress, constants.NullAddress, big.NewInt(10))
+	require.NoError(t, err)
+	assert.Equal(t, []*Order{orders[0]}, cancelledOrders)
+	cancelledOrders, err = meshDB.FindOrdersCancelledByEpoch(makerAddress, otherSenderAddress, big.NewInt(11))
+	require.NoError(t, err)
+	assert.Equal(t, []*Order{orders[2], orders[3]}, cancelledOrders)
+	cancelledOrders, err = meshDB.FindOrdersCancelledByEpoch(constants.GanacheAccount2, constants.NullAddress, big.NewInt(11))
+	require.NoError(t, err)
+	assert.Empty(t, cancelledOrders)
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	meshDB, err := New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	rawOrders := make([]*zeroex.Order, 2)
+	for i := range rawOrders {
+		rawOrders[i] = &zeroex.Order{
+			ChainID:               big.NewInt(constants.TestChainID),
+			ExchangeAddress:       contractAddresses.Exchange,
+			MakerAddress:          constants.GanacheAccount0,
+			TakerAddress:          constants.NullAddress,
+			SenderAddress:         constants.NullAddress,
+			FeeRecipientAddress:   constants.NullAddress,
+			MakerAssetData:        common.Hex2Bytes("025717920000000000000000000000001dc4c1cefef38a777b15aa20260a54e584b16c480000000000000000000000000000000000000000000000000000000000000001"),
+			MakerFeeAssetData:     constants.NullBytes,
+			TakerAssetData:        common.Hex2Bytes("f47261b000000000000000000000000034d402f14d58e001d8efbe6585051bf9706aa064"),
+			TakerFeeAssetData:     constants.NullBytes,
+			Salt:                  big.NewInt(int64(i)),
+			MakerFee:              big.NewInt(0),
+			TakerFee:              big.NewInt(0),
+			MakerAssetAmount:      big.NewInt(1),
+			TakerAssetAmount:      big.NewInt(1),
+			ExpirationTimeSeconds: big.NewInt(100),
+		}
+	}
+	orders := insertRawOrders(t, meshDB, rawOrders, false)
+
+	results, err := meshDB.Verify(false)
+	require.NoError(t, err)
+	for _, result := range results {
+		assert.True(t, result.OK(), "collection %s", result.Collection)
+	}
+
+	// Corrupt the second order so that its stored hash no longer matches.
+	corruptedOrder := orders[1]
+	corruptedOrder.SignedOrder.TakerAssetAmount = big.NewInt(2)
+	require.NoError(t, meshDB.Orders.Update(corruptedOrder))
+
+	results, err = meshDB.Verify(false)
+	require.NoError(t, err)
+	ordersResult := results[0]
+	assert.Equal(t, "order", ordersResult.Collection)
+	require.Len(t, ordersResult.InvalidModels, 1)
+	assert.Contains(t, ordersResult.InvalidModels[0].Reason, "does not match the actual order hash")
+	assert.False(t, ordersResult.Repaired)
+
+	results, err = meshDB.Verify(true)
+	require.NoError(t, err)
+	assert.True(t, results[0].Repaired)
+
+	results, err = meshDB.Verify(false)
+	require.NoError(t, err)
+	for _, result := range results {
+		assert.True(t, result.OK(), "collection %s", result.Collection)
+	}
+	var remainingOrders []*Order
+	require.NoError(t, meshDB.Orders.FindAll(&remainingOrders))
+	require.Len(t, remainingOrders, 1)
+	assert.Equal(t, orders[0].Hash, remainingOrders[0].Hash)
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+	meshDB, err := New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	// An empty database doesn't need to be migrated.
+	version, err := meshDB.SchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, version)
+	pendingMigrations, err := meshDB.PendingMigrations()
+	require.NoError(t, err)
+	assert.Empty(t, pendingMigrations)
+
+	// A database with metadata but without a schema version was created before
+	// the schema version was introduced.
+	require.NoError(t, meshDB.SaveMetadata(&Metadata{EthereumChainID: constants.TestChainID}))
+	version, err = meshDB.SchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, 0, version)
+	pendingMigrations, err = meshDB.PendingMigrations()
+	require.NoError(t, err)
+	assert.Equal(t, migrations, pendingMigrations)
+
+	require.NoError(t, meshDB.Migrate())
+	version, err = meshDB.SchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, version)
+	pendingMigrations, err = meshDB.PendingMigrations()
+	require.NoError(t, err)
+	assert.Empty(t, pendingMigrations)
+
+	// Databases created by newer versions of Mesh are rejected.
+	require.NoError(t, meshDB.setSchemaVersion(CurrentSchemaVersion+1))
+	_, err = meshDB.PendingMigrations()
+	assert.Error(t, err)
+	assert.Error(t, meshDB.Migrate())
+}
+
+func TestCountOrders(t *testing.T) {
+	t.Parallel()
+	meshDB, err := New("/tmp/meshdb_testing/"+uuid.New().String(), contractAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	wethAssetData := common.Hex2Bytes("f47261b00000000000000000000000000b1ba0af832d7c05fd64161e0db78e85978e8082")
+	zrxAssetData := common.Hex2Bytes("f47261b0000000000000000000000000871dd7c2b4b25e1aa18728e9d5f2af4c4e431f5c")
+	newOrder := func(makerAddress common.Address, makerAssetData, takerAssetData []byte, salt int64) *zeroex.Order {
+		return &zeroex.Order{
+			ChainID:               big.NewInt(constants.TestChainID),
+			ExchangeAddress:       contractAddresses.Exchange,
+			MakerAddress:          makerAddress,
+			TakerAddress:          constants.NullAddress,
+			SenderAddress:         constants.NullAddress,
+			FeeRecipientAddress:   constants.NullAddress,
+			MakerAssetData:        makerAssetData,
+			MakerFeeAssetData:     constants.NullBytes,
+			TakerAssetData:        takerAssetData,
+			TakerFeeAssetData:     constants.NullBytes,
+			Salt:                  big.NewInt(salt),
+			MakerFee:              big.NewInt(0),
+			TakerFee:              big.NewInt(0),
+			MakerAssetAmount:      big.NewInt(1),
+			TakerAssetAmount:      big.NewInt(1),
+			ExpirationTimeSeconds: big.NewInt(time.Now().Add(time.Hour).Unix()),
+		}
+	}
+	orders := insertRawOrders(t, meshDB, []*zeroex.Order{
+		newOrder(constants.GanacheAccount0, wethAssetData, zrxAssetData, 1),
+		newOrder(constants.GanacheAccount0, zrxAssetData, wethAssetData, 2),
+		newOrder(constants.GanacheAccount1, wethAssetData, zrxAssetData, 3),
+	}, false)
+	orders[0].IsRemoved = true
+	orders[0].LastValidationResult = "OrderFullyFilled"
+	require.NoError(t, meshDB.Orders.Update(orders[0]))
+
+	pairCounts, err := meshDB.CountOrdersByAssetPair()
+	require.NoError(t, err)
+	assert.Equal(t, map[AssetPair]int{
+		{MakerAssetData: common.ToHex(wethAssetData), TakerAssetData: common.ToHex(zrxAssetData)}: 1,
+		{MakerAssetData: common.ToHex(zrxAssetData), TakerAssetData: common.ToHex(wethAssetData)}: 1,
+	}, pairCounts)
+
+	makerCounts, err := meshDB.CountOrdersByMakerAddress()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		strings.ToLower(constants.GanacheAccount0.Hex()): 1,
+		strings.ToLower(constants.GanacheAccount1.Hex()): 1,
+	}, makerCounts)
+
+	statusCounts, err := meshDB.CountOrdersByStatus()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		OrderStatusFillable: 2,
+		"OrderFullyFilled":  1,
+	}, statusCounts)
+}
@@ -0,0 +1,92 @@
+package meshdb
+
+import (
+	"sort"
+	"time"
+
+	"github.com/0xProject/0x-mesh/db"
+)
+
+// MetricsSample is the database representation of a sample of key metrics
+// taken at a point in time.
+type MetricsSample struct {
+	// Slot is the position of the sample in the ring buffer of samples.
+	Slot int
+	// Time is when the sample was taken.
+	Time time.Time
+	// NumOrders is the number of orders which were stored and not flagged for
+	// removal.
+	NumOrders int
+	// NumPeers is the number of peers Mesh was connected to.
+	NumPeers int
+	// ValidationLatency is the average amount of time it took to validate and
+	// store new orders since the previous sample. It is 0 if no orders were
+	// validated.
+	ValidationLatency time.Duration
+	// NumValidations is the number of times new orders were validated since
+	// the previous sample.
+	NumValidations int
+	// EthRPCRequestsSentInCurrentUTCDay is the number of Ethereum JSON-RPC
+	// requests sent in the current UTC day.
+	EthRPCRequestsSentInCurrentUTCDay int
+	// EthRPCMaxRequestsPer24HrUTC is the maximum number of Ethereum JSON-RPC
+	// requests per UTC day. It is 0 if rate limiting is disabled.
+	EthRPCMaxRequestsPer24HrUTC int
+}
+
+// ID returns the MetricsSample's ID
+func (s MetricsSample) ID() []byte {
+	return []byte{byte(s.Slot >> 8), byte(s.Slot)}
+}
+
+// MetricsSamplesCollection represents a DB collection of metrics samples. It
+// is used as a ring buffer with a fixed number of slots, so that its size is
+// bounded without having to delete old samples.
+type MetricsSamplesCollection struct {
+	*db.Collection
+}
+
+func setupMetricsSamples(database *db.DB) (*MetricsSamplesCollection, error) {
+	col, err := database.NewCollection("metricsSample", &MetricsSample{})
+	if err != nil {
+		return nil, err
+	}
+	return &MetricsSamplesCollection{
+		Collection: col,
+	}, nil
+}
+
+// SaveMetricsSample stores the given sample in the slot of the ring buffer
+// which corresponds to its time. The ring buffer has numSlots slots of length
+// interval, so samples are overwritten after numSlots*interval. numSlots must
+// not exceed 65536.
+func (m *MeshDB) SaveMetricsSample(sample *MetricsSample, interval time.Duration, numSlots int) error {
+	sample.Slot = int((sample.Time.UnixNano() / int64(interval)) % int64(numSlots))
+	var existing MetricsSample
+	if err := m.MetricsSamples.FindByID(sample.ID(), &existing); err != nil {
+		if _, ok := err.(db.NotFoundError); !ok {
+			return err
+		}
+		return m.MetricsSamples.Insert(sample)
+	}
+	return m.MetricsSamples.Update(sample)
+}
+
+// FindMetricsSamplesSince returns all stored samples taken at or after the
+// given time in chronological order.
+func (m *MeshDB) FindMetricsSamplesSince(since time.Time) ([]*MetricsSample, error) {
+	allSamples := []*MetricsSample{}
+	if err := m.MetricsSamples.FindAll(&allSamples); err != nil {
+		return nil, err
+	}
+	samples := []*MetricsSample{}
+	for _, sample := range allSamples {
+		if !sample.Time.Before(since) {
+			samples = append(samples, sample)
+		}
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Time.Before(samples[j].Time)
+	})
+	return samples, nil
+}
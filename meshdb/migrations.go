@@ -0,0 +1,150 @@
+package meshdb
+
+import (
+	"fmt"
+
+	"github.com/0xProject/0x-mesh/db"
+	log "github.com/sirupsen/logrus"
+)
+
+// Migration upgrades the database from the previous schema version to
+// Version.
+type Migration struct {
+	// Version is the schema version of the database after the migration.
+	Version int
+	// Description is a short, human-readable description of the migration.
+	Description string
+	// migrate applies the migration. It must be safe to apply the migration
+	// again if it was interrupted.
+	migrate func(m *MeshDB) error
+}
+
+// migrations are all migrations in ascending order of their versions. The
+// version of each migration must be exactly one greater than the version of
+// the previous migration. Migrations must only ever be appended.
+var migrations = []*Migration{
+	{
+		Version:     1,
+		Description: "rebuild the indexes of all collections, which are missing entries for indexes added after the models were inserted",
+		migrate:     rebuildIndexes,
+	},
+	{
+		Version:     2,
+		Description: "build the indexes used to count orders by asset pair, maker and status",
+		migrate:     rebuildIndexes,
+	},
+	{
+		Version:     3,
+		Description: "build the index used to find the orders cancelled by an order epoch for a maker and sender address",
+		migrate:     rebuildIndexes,
+	},
+}
+
+// CurrentSchemaVersion is the schema version of databases created or migrated
+// by this version of Mesh.
+var CurrentSchemaVersion = migrations[len(migrations)-1].Version
+
+// schemaVersion is the database representation of the schema version. There
+// is at most one per database.
+type schemaVersion struct {
+	Version int
+}
+
+// ID returns the id used for the schema version collection (one per DB)
+func (s schemaVersion) ID() []byte {
+	return []byte{0}
+}
+
+func setupSchemaVersion(database *db.DB) (*db.Collection, error) {
+	return database.NewCollection("schemaVersion", &schemaVersion{})
+}
+
+// SchemaVersion returns the schema version of the database. Databases which
+// were created before the schema version was introduced have version 0. Empty
+// databases have the current schema version.
+func (m *MeshDB) SchemaVersion() (int, error) {
+	var version schemaVersion
+	if err := m.schemaVersion.FindByID(version.ID(), &version); err != nil {
+		if _, ok := err.(db.NotFoundError); !ok {
+			return 0, err
+		}
+		// Every database which was ever used by a node has metadata.
+		if _, err := m.GetMetadata(); err != nil {
+			if _, ok := err.(db.NotFoundError); ok {
+				return CurrentSchemaVersion, nil
+			}
+			return 0, err
+		}
+		return 0, nil
+	}
+	return version.Version, nil
+}
+
+// PendingMigrations returns the migrations which Migrate would apply, in the
+// order they would be applied. It returns an error if the database was
+// created by a newer version of Mesh, since it might not be able to read it
+// correctly.
+func (m *MeshDB) PendingMigrations() ([]*Migration, error) {
+	version, err := m.SchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentSchemaVersion {
+		return nil, fmt.Errorf("the database has schema version %d but this version of Mesh only supports schema versions up to %d; upgrade Mesh or remove the database", version, CurrentSchemaVersion)
+	}
+	return migrations[version:], nil
+}
+
+// Migrate applies all pending migrations and stores the new schema version
+// after each of them, so that an interrupted migration is resumed the next
+// time Migrate is called. It must be called before the database is used.
+func (m *MeshDB) Migrate() error {
+	pendingMigrations, err := m.PendingMigrations()
+	if err != nil {
+		return err
+	}
+	for _, migration := range pendingMigrations {
+		log.WithFields(log.Fields{
+			"version":     migration.Version,
+			"description": migration.Description,
+		}).Info("applying database migration")
+		if err := migration.migrate(m); err != nil {
+			return fmt.Errorf("database migration to schema version %d failed: %s", migration.Version, err.Error())
+		}
+		if err := m.setSchemaVersion(migration.Version); err != nil {
+			return err
+		}
+	}
+	// Empty databases are not migrated, so the version must be stored
+	// explicitly.
+	return m.setSchemaVersion(CurrentSchemaVersion)
+}
+
+func (m *MeshDB) setSchemaVersion(version int) error {
+	record := &schemaVersion{Version: version}
+	if err := m.schemaVersion.Update(record); err != nil {
+		if _, ok := err.(db.NotFoundError); ok {
+			return m.schemaVersion.Insert(record)
+		}
+		return err
+	}
+	return nil
+}
+
+// rebuildIndexes deletes models which cannot be unmarshaled and rebuilds the
+// indexes and counts of all collections.
+func rebuildIndexes(m *MeshDB) error {
+	for _, col := range []*db.Collection{m.Orders.Collection, m.MiniHeaders.Collection, m.metadata.Collection, m.AuditRecords.Collection} {
+		result, err := col.Verify(nil, true)
+		if err != nil {
+			return err
+		}
+		if len(result.InvalidModels) > 0 {
+			log.WithFields(log.Fields{
+				"collection":    result.Collection,
+				"invalidModels": result.InvalidModels,
+			}).Warn("deleted models which could not be unmarshaled")
+		}
+	}
+	return nil
+}
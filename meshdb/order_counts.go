@@ -0,0 +1,56 @@
+package meshdb
+
+import (
+	"strings"
+)
+
+// AssetPair identifies the assets of an order.
+type AssetPair struct {
+	MakerAssetData string
+	TakerAssetData string
+}
+
+// notRemovedPrefix is the prefix of the index values of orders which have not
+// been flagged for removal.
+const notRemovedPrefix = "0|"
+
+// CountOrdersByAssetPair returns the number of orders which have not been
+// flagged for removal for each asset pair. Asset data is hex-encoded.
+func (m *MeshDB) CountOrdersByAssetPair() (map[AssetPair]int, error) {
+	filter := m.Orders.IsRemovedAndAssetPairIndex.PrefixFilter([]byte(notRemovedPrefix))
+	counts, err := m.Orders.NewQuery(filter).CountByValue()
+	if err != nil {
+		return nil, err
+	}
+	pairCounts := make(map[AssetPair]int, len(counts))
+	for value, count := range counts {
+		split := strings.Split(strings.TrimPrefix(value, notRemovedPrefix), "|")
+		if len(split) != 2 {
+			continue
+		}
+		pairCounts[AssetPair{MakerAssetData: split[0], TakerAssetData: split[1]}] = count
+	}
+	return pairCounts, nil
+}
+
+// CountOrdersByMakerAddress returns the number of orders which have not been
+// flagged for removal for each maker address. Addresses are lowercase.
+func (m *MeshDB) CountOrdersByMakerAddress() (map[string]int, error) {
+	filter := m.Orders.IsRemovedAndMakerAddressIndex.PrefixFilter([]byte(notRemovedPrefix))
+	counts, err := m.Orders.NewQuery(filter).CountByValue()
+	if err != nil {
+		return nil, err
+	}
+	makerCounts := make(map[string]int, len(counts))
+	for value, count := range counts {
+		makerCounts[strings.TrimPrefix(value, notRemovedPrefix)] = count
+	}
+	return makerCounts, nil
+}
+
+// CountOrdersByStatus returns the number of orders for each status (see
+// Order.Status), including orders which have been flagged for removal but not
+// yet deleted.
+func (m *MeshDB) CountOrdersByStatus() (map[string]int, error) {
+	return m.Orders.NewQuery(m.Orders.StatusIndex.All()).CountByValue()
+}
@@ -0,0 +1,89 @@
+package meshdb
+
+import (
+	"encoding/binary"
+	"math/big"
+	"sort"
+
+	"github.com/0xProject/0x-mesh/db"
+	"github.com/0xProject/0x-mesh/zeroex"
+)
+
+// PendingOrderEvents is the database representation of order events which are
+// held back until the block that was the latest block when they were generated
+// has enough confirmations. They are stored so that they are still sent after
+// Mesh is restarted.
+type PendingOrderEvents struct {
+	// Index determines the order in which pending order events are sent.
+	Index uint64
+	// BlockNumber is the number of the latest block at the time the order
+	// events were generated.
+	BlockNumber *big.Int
+	OrderEvents []*zeroex.OrderEvent
+}
+
+// ID returns the PendingOrderEvents' ID
+func (p PendingOrderEvents) ID() []byte {
+	return pendingOrderEventsID(p.Index)
+}
+
+func pendingOrderEventsID(index uint64) []byte {
+	id := make([]byte, 8)
+	binary.BigEndian.PutUint64(id, index)
+	return id
+}
+
+// PendingOrderEventsCollection represents a DB collection of pending order
+// events, indexed by the order in which they are sent.
+type PendingOrderEventsCollection struct {
+	*db.Collection
+}
+
+func setupPendingOrderEvents(database *db.DB) (*PendingOrderEventsCollection, error) {
+	col, err := database.NewCollection("pendingOrderEvents", &PendingOrderEvents{})
+	if err != nil {
+		return nil, err
+	}
+	return &PendingOrderEventsCollection{
+		Collection: col,
+	}, nil
+}
+
+// FindAllPendingOrderEvents returns all pending order events in the order in
+// which they must be sent.
+func (m *MeshDB) FindAllPendingOrderEvents() ([]*PendingOrderEvents, error) {
+	pendingOrderEvents := []*PendingOrderEvents{}
+	if err := m.PendingOrderEvents.FindAll(&pendingOrderEvents); err != nil {
+		return nil, err
+	}
+	sort.Slice(pendingOrderEvents, func(i, j int) bool {
+		return pendingOrderEvents[i].Index < pendingOrderEvents[j].Index
+	})
+	return pendingOrderEvents, nil
+}
+
+// DeletePendingOrderEvents deletes the pending order events with the given
+// index, if any.
+func (m *MeshDB) DeletePendingOrderEvents(index uint64) error {
+	if err := m.PendingOrderEvents.Delete(pendingOrderEventsID(index)); err != nil {
+		if _, ok := err.(db.NotFoundError); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClearAllPendingOrderEvents removes all pending order events from the
+// database.
+func (m *MeshDB) ClearAllPendingOrderEvents() error {
+	pendingOrderEvents, err := m.FindAllPendingOrderEvents()
+	if err != nil {
+		return err
+	}
+	for _, pending := range pendingOrderEvents {
+		if err := m.DeletePendingOrderEvents(pending.Index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
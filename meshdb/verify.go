@@ -0,0 +1,67 @@
+package meshdb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xProject/0x-mesh/db"
+	"github.com/0xProject/0x-mesh/zeroex"
+)
+
+// Verify checks every collection of the database for corrupted models and
+// inconsistent indexes, e.g. after an unclean shutdown. In addition, the hash
+// of every order is recomputed and its signature is checked offline (see
+// zeroex.ValidateSignatureOffline). If repair is true, the problems are fixed
+// by deleting invalid models and rebuilding the indexes. It must only be
+// called while no other goroutine is using the database.
+func (m *MeshDB) Verify(repair bool) ([]*db.VerifyResult, error) {
+	collections := []struct {
+		collection *db.Collection
+		check      func(db.Model) error
+	}{
+		{collection: m.Orders.Collection, check: verifyOrder},
+		{collection: m.MiniHeaders.Collection},
+		{collection: m.metadata.Collection},
+		{collection: m.AuditRecords.Collection},
+		{collection: m.KnownPeers.Collection},
+		{collection: m.MetricsSamples.Collection},
+		{collection: m.CancelEpochs.Collection},
+		{collection: m.PendingOrderEvents.Collection},
+		{collection: m.schemaVersion},
+	}
+	results := make([]*db.VerifyResult, len(collections))
+	for i, col := range collections {
+		result, err := col.collection.Verify(col.check, repair)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// verifyOrder returns an error if the stored hash of the given order does not
+// match its actual hash or if its signature is invalid.
+func verifyOrder(model db.Model) (err error) {
+	order := model.(*Order)
+	if order.SignedOrder == nil {
+		return errors.New("order is missing")
+	}
+	defer func() {
+		// ComputeOrderHash panics for orders with missing fields.
+		if r := recover(); r != nil {
+			err = fmt.Errorf("could not compute order hash: %v", r)
+		}
+	}()
+	orderHash, err := order.SignedOrder.ComputeOrderHash()
+	if err != nil {
+		return fmt.Errorf("could not compute order hash: %s", err.Error())
+	}
+	if orderHash != order.Hash {
+		return fmt.Errorf("stored order hash %s does not match the actual order hash %s", order.Hash.Hex(), orderHash.Hex())
+	}
+	if status, err := zeroex.ValidateSignatureOffline(orderHash, order.SignedOrder.MakerAddress, order.SignedOrder.Signature); status == zeroex.SignatureInvalid {
+		return fmt.Errorf("invalid signature: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package orderbooksnapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultS3Endpoint is the endpoint of Amazon S3.
+	DefaultS3Endpoint = "https://s3.amazonaws.com"
+	// DefaultS3Region is the region used for signing requests if none is
+	// configured. Most S3-compatible services accept it.
+	DefaultS3Region = "us-east-1"
+	// s3RequestTimeout is the timeout for uploading a single snapshot.
+	s3RequestTimeout = 1 * time.Minute
+	// amzDateFormat is the format of the X-Amz-Date header.
+	amzDateFormat = "20060102T150405Z"
+)
+
+// S3Config configures access to S3-compatible storage.
+type S3Config struct {
+	// Endpoint is the base URL of the S3 API. Buckets are addressed with
+	// path-style URLs (<endpoint>/<bucket>/<key>), which are supported by
+	// most S3-compatible services. Defaults to DefaultS3Endpoint.
+	Endpoint string
+	// Region is the region used for signing requests. Defaults to
+	// DefaultS3Region.
+	Region string
+	// AccessKeyID and SecretAccessKey are the credentials used for signing
+	// requests.
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is only required for temporary credentials.
+	SessionToken string
+}
+
+// S3Store is a Store which uploads snapshots to a bucket in S3-compatible
+// storage. Requests are signed with AWS Signature Version 4.
+type S3Store struct {
+	config   S3Config
+	endpoint *url.URL
+	bucket   string
+	prefix   string
+	client   *http.Client
+	// now returns the current time. It is overridden in tests.
+	now func() time.Time
+}
+
+// NewS3Store returns an S3Store which uploads snapshots to the given bucket.
+// If prefix is not empty, the names of snapshots are prefixed with it,
+// followed by a slash.
+func NewS3Store(config S3Config, bucket string, prefix string) (*S3Store, error) {
+	if config.Endpoint == "" {
+		config.Endpoint = DefaultS3Endpoint
+	}
+	if config.Region == "" {
+		config.Region = DefaultS3Region
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("S3 credentials are required to store snapshots in S3")
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %s", err.Error())
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid S3 endpoint: %q (expected an http or https URL)", config.Endpoint)
+	}
+	return &S3Store{
+		config:   config,
+		endpoint: endpoint,
+		bucket:   bucket,
+		prefix:   prefix,
+		client:   &http.Client{Timeout: s3RequestTimeout},
+		now:      time.Now,
+	}, nil
+}
+
+// Put uploads data as the object with the given name.
+func (s *S3Store) Put(ctx context.Context, name string, data []byte) error {
+	key := name
+	if s.prefix != "" {
+		key = s.prefix + "/" + name
+	}
+	objectURL := *s.endpoint
+	objectURL.Path = strings.TrimSuffix(objectURL.Path, "/") + "/" + s.bucket + "/" + key
+	req, err := http.NewRequest(http.MethodPut, objectURL.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+	payloadHash := sha256.Sum256(data)
+	s.sign(req, hex.EncodeToString(payloadHash[:]))
+	res, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		message, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("unexpected status code while uploading snapshot to S3: %d: %s", res.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// sign adds the AWS Signature Version 4 Authorization header to req. All
+// headers of req are signed.
+func (s *S3Store) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	amzDate := now.Format(amzDateFormat)
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.config.SessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	headerNames := make([]string, 0, len(headers))
+	for name := range headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+	canonicalHeaders := &strings.Builder{}
+	for _, name := range headerNames {
+		fmt.Fprintf(canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+	canonicalQuery := strings.Replace(req.URL.Query().Encode(), "+", "%20", -1)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		canonicalQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, s.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
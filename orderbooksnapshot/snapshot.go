@@ -0,0 +1,134 @@
+// Package orderbooksnapshot builds compact snapshots of the orderbook of a
+// Mesh node and stores them on disk or in S3-compatible storage. A snapshot
+// contains the hash and the fillable taker asset amount of every order,
+// grouped by asset pair, which is enough to analyze the available liquidity
+// after the fact without running an indexer.
+package orderbooksnapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Snapshot is the orderbook of a Mesh node at a point in time.
+type Snapshot struct {
+	// Timestamp is when the snapshot was taken.
+	Timestamp time.Time `json:"timestamp"`
+	// ChainID is the chain ID of the node.
+	ChainID int `json:"chainId"`
+	// BlockNumber and BlockHash identify the latest block the node had
+	// processed when the snapshot was taken.
+	BlockNumber int64       `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+	// NumOrders is the total number of orders in the snapshot.
+	NumOrders int `json:"numOrders"`
+	// Pairs are the asset pairs with at least one order, sorted by maker and
+	// then taker asset data.
+	Pairs []*Pair `json:"pairs"`
+}
+
+// Pair contains the orders of a single asset pair.
+type Pair struct {
+	MakerAssetData hexutil.Bytes `json:"makerAssetData"`
+	TakerAssetData hexutil.Bytes `json:"takerAssetData"`
+	// Orders are sorted by order hash.
+	Orders []*Order `json:"orders"`
+}
+
+// Order is a single order in a snapshot.
+type Order struct {
+	OrderHash common.Hash `json:"orderHash"`
+	// FillableTakerAssetAmount is encoded as a decimal string so that it can
+	// be parsed without losing precision.
+	FillableTakerAssetAmount string `json:"fillableTakerAssetAmount"`
+}
+
+// Encode writes the gzip-compressed JSON encoding of the snapshot to w.
+func (s *Snapshot) Encode(w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	if err := json.NewEncoder(gzipWriter).Encode(s); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// FileName returns the name under which the snapshot is stored. Names sort in
+// chronological order.
+func (s *Snapshot) FileName() string {
+	return "orderbook-" + s.Timestamp.UTC().Format("20060102T150405Z") + ".json.gz"
+}
+
+// pairKey identifies an asset pair in a Builder.
+type pairKey struct {
+	makerAssetData string
+	takerAssetData string
+}
+
+// Builder collects orders and groups them by asset pair. The zero value is not
+// usable; use NewBuilder.
+type Builder struct {
+	pairs     map[pairKey]*Pair
+	numOrders int
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{
+		pairs: map[pairKey]*Pair{},
+	}
+}
+
+// Add adds an order to the snapshot.
+func (b *Builder) Add(makerAssetData, takerAssetData []byte, orderHash common.Hash, fillableTakerAssetAmount *big.Int) {
+	key := pairKey{
+		makerAssetData: string(makerAssetData),
+		takerAssetData: string(takerAssetData),
+	}
+	pair, found := b.pairs[key]
+	if !found {
+		pair = &Pair{
+			MakerAssetData: append([]byte{}, makerAssetData...),
+			TakerAssetData: append([]byte{}, takerAssetData...),
+			Orders:         []*Order{},
+		}
+		b.pairs[key] = pair
+	}
+	pair.Orders = append(pair.Orders, &Order{
+		OrderHash:                orderHash,
+		FillableTakerAssetAmount: fillableTakerAssetAmount.String(),
+	})
+	b.numOrders++
+}
+
+// Snapshot returns a snapshot of all orders added so far.
+func (b *Builder) Snapshot(timestamp time.Time, chainID int, blockNumber int64, blockHash common.Hash) *Snapshot {
+	pairs := make([]*Pair, 0, len(b.pairs))
+	for _, pair := range b.pairs {
+		sort.Slice(pair.Orders, func(i, j int) bool {
+			return bytes.Compare(pair.Orders[i].OrderHash.Bytes(), pair.Orders[j].OrderHash.Bytes()) < 0
+		})
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if c := bytes.Compare(pairs[i].MakerAssetData, pairs[j].MakerAssetData); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(pairs[i].TakerAssetData, pairs[j].TakerAssetData) < 0
+	})
+	return &Snapshot{
+		Timestamp:   timestamp,
+		ChainID:     chainID,
+		BlockNumber: blockNumber,
+		BlockHash:   blockHash,
+		NumOrders:   b.numOrders,
+		Pairs:       pairs,
+	}
+}
@@ -0,0 +1,71 @@
+package orderbooksnapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// s3Scheme is the prefix of destinations which refer to S3-compatible storage.
+const s3Scheme = "s3://"
+
+// Store stores encoded snapshots.
+type Store interface {
+	// Put stores data under the given name, replacing any data that was
+	// previously stored under it.
+	Put(ctx context.Context, name string, data []byte) error
+}
+
+// NewStore returns the Store for the given destination. Destinations of the
+// form s3://bucket/prefix refer to a bucket in S3-compatible storage, which is
+// accessed with the given config. Any other destination is the path of a
+// local directory, which is created if it does not exist.
+func NewStore(destination string, s3Config S3Config) (Store, error) {
+	if !strings.HasPrefix(destination, s3Scheme) {
+		return NewFileStore(destination)
+	}
+	bucketAndPrefix := strings.TrimPrefix(destination, s3Scheme)
+	bucket := bucketAndPrefix
+	prefix := ""
+	if i := strings.Index(bucketAndPrefix, "/"); i != -1 {
+		bucket = bucketAndPrefix[:i]
+		prefix = strings.Trim(bucketAndPrefix[i+1:], "/")
+	}
+	if bucket == "" {
+		return nil, fmt.Errorf("invalid snapshot destination: %q (expected s3://bucket/prefix)", destination)
+	}
+	return NewS3Store(s3Config, bucket, prefix)
+}
+
+// FileStore is a Store which writes snapshots to a local directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a FileStore which writes to dir, creating it if
+// necessary.
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, errors.New("snapshot directory must not be empty")
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Put writes data to the file with the given name in the directory of the
+// store. The data is written to a temporary file first, so readers never see
+// partially written snapshots.
+func (s *FileStore) Put(ctx context.Context, name string, data []byte) error {
+	path := filepath.Join(s.dir, name)
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
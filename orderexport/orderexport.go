@@ -0,0 +1,312 @@
+// Package orderexport encodes and decodes orders in the formats used to
+// export orders from and import orders into Mesh nodes. Orders can be
+// exported as JSON Lines, with one JSON-encoded order per line, or as CSV,
+// optionally compressed with gzip.
+package orderexport
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/zeroex"
+)
+
+// Format is the format of an export.
+type Format string
+
+const (
+	// FormatJSONL is the JSON Lines format. Each line is a JSON-encoded
+	// types.OrderInfo.
+	FormatJSONL Format = "jsonl"
+	// FormatCSV is the CSV format. The first row contains the column names
+	// (see Columns).
+	FormatCSV Format = "csv"
+)
+
+// maxLineSize is the maximum length of a line in the JSON Lines format.
+const maxLineSize = 1 << 20 // 1 MiB.
+
+// gzipMagic are the first bytes of any gzip-compressed data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Columns are the columns of the CSV format. The names of the signed order
+// columns are the same as the keys of the JSON encoding of a signed order.
+var Columns = []string{
+	"orderHash",
+	"fillableTakerAssetAmount",
+	"chainId",
+	"exchangeAddress",
+	"makerAddress",
+	"makerAssetData",
+	"makerFeeAssetData",
+	"makerAssetAmount",
+	"makerFee",
+	"takerAddress",
+	"takerAssetData",
+	"takerFeeAssetData",
+	"takerAssetAmount",
+	"takerFee",
+	"senderAddress",
+	"feeRecipientAddress",
+	"expirationTimeSeconds",
+	"salt",
+	"signature",
+}
+
+// ParseFormat returns the Format with the given name. An empty name means
+// FormatJSONL.
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case "", FormatJSONL:
+		return FormatJSONL, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unsupported export format: %q (expected %q or %q)", name, FormatJSONL, FormatCSV)
+	}
+}
+
+// FileExtension returns the extension of files in the given format, including
+// the .gz suffix if compressed is true.
+func FileExtension(format Format, compressed bool) string {
+	extension := "." + string(format)
+	if compressed {
+		extension += ".gz"
+	}
+	return extension
+}
+
+// Writer writes orders in one of the export formats.
+type Writer struct {
+	format      Format
+	gzipWriter  *gzip.Writer
+	jsonEncoder *json.Encoder
+	csvWriter   *csv.Writer
+	wroteHeader bool
+}
+
+// NewWriter returns a Writer which writes orders in the given format to w. If
+// compress is true, the output is compressed with gzip. Close must be called
+// after the last order has been written.
+func NewWriter(w io.Writer, format Format, compress bool) *Writer {
+	writer := &Writer{format: format}
+	if compress {
+		writer.gzipWriter = gzip.NewWriter(w)
+		w = writer.gzipWriter
+	}
+	switch format {
+	case FormatCSV:
+		writer.csvWriter = csv.NewWriter(w)
+	default:
+		writer.jsonEncoder = json.NewEncoder(w)
+	}
+	return writer
+}
+
+// Write writes a single order.
+func (w *Writer) Write(orderInfo *types.OrderInfo) error {
+	if w.csvWriter == nil {
+		return w.jsonEncoder.Encode(orderInfo)
+	}
+	if !w.wroteHeader {
+		if err := w.csvWriter.Write(Columns); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+	signedOrderJSON, err := toSignedOrderJSON(orderInfo.SignedOrder)
+	if err != nil {
+		return err
+	}
+	return w.csvWriter.Write([]string{
+		orderInfo.OrderHash.Hex(),
+		orderInfo.FillableTakerAssetAmount.String(),
+		strconv.FormatInt(signedOrderJSON.ChainID, 10),
+		signedOrderJSON.ExchangeAddress,
+		signedOrderJSON.MakerAddress,
+		signedOrderJSON.MakerAssetData,
+		signedOrderJSON.MakerFeeAssetData,
+		signedOrderJSON.MakerAssetAmount,
+		signedOrderJSON.MakerFee,
+		signedOrderJSON.TakerAddress,
+		signedOrderJSON.TakerAssetData,
+		signedOrderJSON.TakerFeeAssetData,
+		signedOrderJSON.TakerAssetAmount,
+		signedOrderJSON.TakerFee,
+		signedOrderJSON.SenderAddress,
+		signedOrderJSON.FeeRecipientAddress,
+		signedOrderJSON.ExpirationTimeSeconds,
+		signedOrderJSON.Salt,
+		signedOrderJSON.Signature,
+	})
+}
+
+// Close flushes any buffered data. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.csvWriter != nil {
+		if !w.wroteHeader {
+			// Always write the header so that empty exports are valid CSV files.
+			if err := w.csvWriter.Write(Columns); err != nil {
+				return err
+			}
+		}
+		w.csvWriter.Flush()
+		if err := w.csvWriter.Error(); err != nil {
+			return err
+		}
+	}
+	if w.gzipWriter != nil {
+		return w.gzipWriter.Close()
+	}
+	return nil
+}
+
+// toSignedOrderJSON returns the JSON representation of signedOrder, in which
+// addresses, asset data and amounts are encoded as strings.
+func toSignedOrderJSON(signedOrder *zeroex.SignedOrder) (*zeroex.SignedOrderJSON, error) {
+	encoded, err := json.Marshal(signedOrder)
+	if err != nil {
+		return nil, err
+	}
+	var signedOrderJSON zeroex.SignedOrderJSON
+	if err := json.Unmarshal(encoded, &signedOrderJSON); err != nil {
+		return nil, err
+	}
+	return &signedOrderJSON, nil
+}
+
+// Reader reads the signed orders of an export. In the JSON Lines format, each
+// line may either be an OrderInfo or a signed order. Other columns than the
+// signed order columns are ignored in the CSV format, so the CSV file only
+// needs to contain the signed order columns. Compressed input is detected
+// automatically.
+type Reader struct {
+	scanner   *bufio.Scanner
+	csvReader *csv.Reader
+	// csvColumns maps each column name to its index in the CSV rows.
+	csvColumns map[string]int
+}
+
+// NewReader returns a Reader which reads orders in the given format from r.
+func NewReader(r io.Reader, format Format) (*Reader, error) {
+	bufferedReader := bufio.NewReader(r)
+	if magic, err := bufferedReader.Peek(len(gzipMagic)); err == nil && bytes.Equal(magic, gzipMagic) {
+		gzipReader, err := gzip.NewReader(bufferedReader)
+		if err != nil {
+			return nil, err
+		}
+		r = gzipReader
+	} else {
+		r = bufferedReader
+	}
+	if format == FormatCSV {
+		return newCSVReader(r)
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &Reader{scanner: scanner}, nil
+}
+
+func newCSVReader(r io.Reader) (*Reader, error) {
+	csvReader := csv.NewReader(r)
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV input is empty (expected a header row)")
+	} else if err != nil {
+		return nil, err
+	}
+	csvColumns := make(map[string]int, len(header))
+	for i, column := range header {
+		csvColumns[column] = i
+	}
+	// All columns except the orderHash and fillableTakerAssetAmount columns
+	// are required to reconstruct the signed orders.
+	for _, column := range Columns[2:] {
+		if _, found := csvColumns[column]; !found {
+			return nil, fmt.Errorf("CSV input is missing the %q column", column)
+		}
+	}
+	return &Reader{
+		csvReader:  csvReader,
+		csvColumns: csvColumns,
+	}, nil
+}
+
+// Read returns the JSON encoding of the next signed order. It returns io.EOF
+// if there are no more orders.
+func (r *Reader) Read() (*json.RawMessage, error) {
+	if r.csvReader != nil {
+		return r.readCSV()
+	}
+	return r.readJSONL()
+}
+
+func (r *Reader) readJSONL() (*json.RawMessage, error) {
+	for r.scanner.Scan() {
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var holder struct {
+			SignedOrder *json.RawMessage `json:"signedOrder"`
+		}
+		if err := json.Unmarshal(line, &holder); err != nil {
+			return nil, fmt.Errorf("invalid JSON line: %s", err.Error())
+		}
+		if holder.SignedOrder != nil {
+			return holder.SignedOrder, nil
+		}
+		signedOrderRaw := json.RawMessage(append([]byte{}, line...))
+		return &signedOrderRaw, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (r *Reader) readCSV() (*json.RawMessage, error) {
+	row, err := r.csvReader.Read()
+	if err != nil {
+		return nil, err
+	}
+	column := func(name string) string {
+		return row[r.csvColumns[name]]
+	}
+	chainID, err := strconv.ParseInt(column("chainId"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chainId: %q", column("chainId"))
+	}
+	encoded, err := json.Marshal(zeroex.SignedOrderJSON{
+		ChainID:               chainID,
+		ExchangeAddress:       column("exchangeAddress"),
+		MakerAddress:          column("makerAddress"),
+		MakerAssetData:        column("makerAssetData"),
+		MakerFeeAssetData:     column("makerFeeAssetData"),
+		MakerAssetAmount:      column("makerAssetAmount"),
+		MakerFee:              column("makerFee"),
+		TakerAddress:          column("takerAddress"),
+		TakerAssetData:        column("takerAssetData"),
+		TakerFeeAssetData:     column("takerFeeAssetData"),
+		TakerAssetAmount:      column("takerAssetAmount"),
+		TakerFee:              column("takerFee"),
+		SenderAddress:         column("senderAddress"),
+		FeeRecipientAddress:   column("feeRecipientAddress"),
+		ExpirationTimeSeconds: column("expirationTimeSeconds"),
+		Salt:                  column("salt"),
+		Signature:             column("signature"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	signedOrderRaw := json.RawMessage(encoded)
+	return &signedOrderRaw, nil
+}
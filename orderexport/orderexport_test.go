@@ -0,0 +1,96 @@
+package orderexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/ethereum"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOrderInfo(t *testing.T, salt int64) *types.OrderInfo {
+	signedOrder, err := zeroex.SignTestOrder(&zeroex.Order{
+		ChainID:               big.NewInt(constants.TestChainID),
+		MakerAddress:          constants.GanacheAccount0,
+		TakerAddress:          constants.NullAddress,
+		SenderAddress:         constants.NullAddress,
+		FeeRecipientAddress:   constants.NullAddress,
+		MakerAssetData:        constants.NullAddress.Bytes(),
+		MakerFeeAssetData:     constants.NullBytes,
+		TakerAssetData:        constants.NullAddress.Bytes(),
+		TakerFeeAssetData:     constants.NullBytes,
+		Salt:                  big.NewInt(salt),
+		MakerFee:              big.NewInt(0),
+		TakerFee:              big.NewInt(0),
+		MakerAssetAmount:      big.NewInt(1000),
+		TakerAssetAmount:      big.NewInt(2000),
+		ExpirationTimeSeconds: big.NewInt(1893456000),
+		ExchangeAddress:       ethereum.GanacheAddresses.Exchange,
+	})
+	require.NoError(t, err)
+	orderHash, err := signedOrder.ComputeOrderHash()
+	require.NoError(t, err)
+	return &types.OrderInfo{
+		OrderHash:                orderHash,
+		SignedOrder:              signedOrder,
+		FillableTakerAssetAmount: big.NewInt(1500),
+	}
+}
+
+func TestWriterReaderRoundTrip(t *testing.T) {
+	orderInfos := []*types.OrderInfo{
+		newTestOrderInfo(t, 1),
+		newTestOrderInfo(t, 2),
+	}
+	for _, format := range []Format{FormatJSONL, FormatCSV} {
+		for _, compress := range []bool{false, true} {
+			buf := &bytes.Buffer{}
+			writer := NewWriter(buf, format, compress)
+			for _, orderInfo := range orderInfos {
+				require.NoError(t, writer.Write(orderInfo))
+			}
+			require.NoError(t, writer.Close())
+
+			reader, err := NewReader(buf, format)
+			require.NoError(t, err)
+			for _, orderInfo := range orderInfos {
+				signedOrderRaw, err := reader.Read()
+				require.NoError(t, err, "format: %s, compressed: %t", format, compress)
+				var signedOrder zeroex.SignedOrder
+				require.NoError(t, json.Unmarshal(*signedOrderRaw, &signedOrder))
+				orderHash, err := signedOrder.ComputeOrderHash()
+				require.NoError(t, err)
+				assert.Equal(t, orderInfo.OrderHash, orderHash, "format: %s, compressed: %t", format, compress)
+				assert.Equal(t, orderInfo.SignedOrder.Signature, signedOrder.Signature)
+			}
+			_, err = reader.Read()
+			assert.Equal(t, io.EOF, err, "format: %s, compressed: %t", format, compress)
+		}
+	}
+}
+
+func TestReaderAcceptsSignedOrderLines(t *testing.T) {
+	orderInfo := newTestOrderInfo(t, 1)
+	encoded, err := json.Marshal(orderInfo.SignedOrder)
+	require.NoError(t, err)
+
+	reader, err := NewReader(bytes.NewReader(append(encoded, '\n', '\n')), FormatJSONL)
+	require.NoError(t, err)
+	signedOrderRaw, err := reader.Read()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(encoded), string(*signedOrderRaw))
+	_, err = reader.Read()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReaderRequiresCSVColumns(t *testing.T) {
+	_, err := NewReader(bytes.NewReader([]byte("orderHash,makerAddress\n")), FormatCSV)
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package orderfilter
+
+import (
+	"github.com/0xProject/0x-mesh/zeroex"
+)
+
+// CheckResult is the result of checking an order against a Filter. Unlike the
+// results of ValidateOrder and ValidateOrderJSON, it does not depend on the
+// JSON Schema library used on the current platform, which makes it suitable
+// for use outside of Mesh. For example, relayers can use CheckOrderJSON to
+// check orders with exactly the same rules as their Mesh node before
+// submitting them. The Filter should be created with New (using the chain ID,
+// CUSTOM_ORDER_FILTER and contract addresses of the node) or with
+// NewFromTopic (using the pubSubTopic returned by mesh_getStats).
+type CheckResult struct {
+	// Valid is true if the order matches the filter.
+	Valid bool `json:"valid"`
+	// Errors describes why the order does not match the filter.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// CheckOrderJSON checks the given JSON encoded signed order against the
+// filter. It only returns an error if there was a problem with validation
+// (e.g. the input is not valid JSON).
+func (f *Filter) CheckOrderJSON(orderJSON []byte) (*CheckResult, error) {
+	result, err := f.ValidateOrderJSON(orderJSON)
+	if err != nil {
+		return nil, err
+	}
+	return newCheckResult(result), nil
+}
+
+// CheckOrder checks the given signed order against the filter. It only
+// returns an error if there was a problem with validation.
+func (f *Filter) CheckOrder(order *zeroex.SignedOrder) (*CheckResult, error) {
+	result, err := f.ValidateOrder(order)
+	if err != nil {
+		return nil, err
+	}
+	return newCheckResult(result), nil
+}
@@ -0,0 +1,94 @@
+package orderfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ParseFeeRecipientAllowlist parses a comma-separated list of fee recipient
+// addresses, e.g. the value of the FEE_RECIPIENT_ALLOWLIST environment
+// variable. An empty string results in an empty list.
+func ParseFeeRecipientAllowlist(list string) ([]common.Address, error) {
+	addresses := []common.Address{}
+	if strings.TrimSpace(list) == "" {
+		return addresses, nil
+	}
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if !common.IsHexAddress(entry) {
+			return nil, fmt.Errorf("invalid Ethereum address: %q", entry)
+		}
+		addresses = append(addresses, common.HexToAddress(entry))
+	}
+	return addresses, nil
+}
+
+// WithFeeRecipientAllowlist returns a custom order schema which only matches
+// orders that match customOrderSchema and whose feeRecipientAddress is one of
+// the given addresses. If feeRecipients is empty, customOrderSchema is
+// returned unchanged. The addresses are matched case-insensitively and the
+// result does not depend on their order, so that nodes configured with the
+// same allowlist always end up with the same topic.
+func WithFeeRecipientAllowlist(customOrderSchema string, feeRecipients []common.Address) (string, error) {
+	if len(feeRecipients) == 0 {
+		return customOrderSchema, nil
+	}
+	var customSchema map[string]interface{}
+	if err := json.Unmarshal([]byte(customOrderSchema), &customSchema); err != nil {
+		return "", fmt.Errorf("could not parse custom order schema: %s", err.Error())
+	}
+	feeRecipientSchema := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"feeRecipientAddress": map[string]interface{}{
+				"pattern": feeRecipientPattern(feeRecipients),
+			},
+		},
+	}
+	var combinedSchema map[string]interface{}
+	if len(customSchema) == 0 {
+		combinedSchema = feeRecipientSchema
+	} else {
+		combinedSchema = map[string]interface{}{
+			"allOf": []interface{}{customSchema, feeRecipientSchema},
+		}
+	}
+	encoded, err := json.Marshal(combinedSchema)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// feeRecipientPattern returns a regular expression which matches any of the
+// given addresses regardless of case. Inline flags such as (?i) are not
+// supported by all JSON Schema implementations, so each letter is matched
+// with a character class instead.
+func feeRecipientPattern(feeRecipients []common.Address) string {
+	seen := map[string]struct{}{}
+	alternatives := []string{}
+	for _, address := range feeRecipients {
+		hex := strings.ToLower(address.Hex()[2:])
+		if _, found := seen[hex]; found {
+			continue
+		}
+		seen[hex] = struct{}{}
+		alternatives = append(alternatives, hex)
+	}
+	sort.Strings(alternatives)
+	for i, hex := range alternatives {
+		var pattern strings.Builder
+		for _, char := range hex {
+			if char >= 'a' && char <= 'f' {
+				fmt.Fprintf(&pattern, "[%c%c]", char, char-'a'+'A')
+			} else {
+				pattern.WriteRune(char)
+			}
+		}
+		alternatives[i] = pattern.String()
+	}
+	return fmt.Sprintf("^0x(%s)$", strings.Join(alternatives, "|"))
+}
@@ -0,0 +1,66 @@
+package orderfilter
+
+import (
+	"testing"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFeeRecipientAllowlist(t *testing.T) {
+	addresses, err := ParseFeeRecipientAllowlist("")
+	require.NoError(t, err)
+	assert.Len(t, addresses, 0)
+
+	addresses, err = ParseFeeRecipientAllowlist("0x6ecbe1db9ef729cbe972c83fb886247691fb6beb, 0xE36Ea790bc9d7AB70C55260C66D52b1eca985f84")
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{constants.GanacheAccount1, constants.GanacheAccount2}, addresses)
+
+	_, err = ParseFeeRecipientAllowlist("0x6ecbe1db9ef729cbe972c83fb886247691fb6beb,relayer.eth")
+	assert.Error(t, err)
+}
+
+func TestWithFeeRecipientAllowlist(t *testing.T) {
+	// An empty allowlist doesn't change the schema.
+	schema, err := WithFeeRecipientAllowlist(DefaultCustomOrderSchema, nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCustomOrderSchema, schema)
+
+	// standardValidOrderJSON has a null feeRecipientAddress.
+	allowed, err := WithFeeRecipientAllowlist(DefaultCustomOrderSchema, []common.Address{constants.GanacheAccount1, constants.NullAddress})
+	require.NoError(t, err)
+	allowedFilter, err := New(constants.TestChainID, allowed, contractAddresses)
+	require.NoError(t, err)
+	result, err := allowedFilter.ValidateOrderJSON(standardValidOrderJSON)
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors())
+
+	notAllowed, err := WithFeeRecipientAllowlist(DefaultCustomOrderSchema, []common.Address{constants.GanacheAccount1})
+	require.NoError(t, err)
+	notAllowedFilter, err := New(constants.TestChainID, notAllowed, contractAddresses)
+	require.NoError(t, err)
+	result, err = notAllowedFilter.ValidateOrderJSON(standardValidOrderJSON)
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Errors())
+
+	// The topic doesn't depend on the order of the addresses.
+	reordered, err := WithFeeRecipientAllowlist(DefaultCustomOrderSchema, []common.Address{constants.NullAddress, constants.GanacheAccount1})
+	require.NoError(t, err)
+	reorderedFilter, err := New(constants.TestChainID, reordered, contractAddresses)
+	require.NoError(t, err)
+	assert.Equal(t, allowedFilter.Topic(), reorderedFilter.Topic())
+
+	// The allowlist is combined with the custom order schema.
+	combined, err := WithFeeRecipientAllowlist(`{"properties":{"senderAddress":{"type":"string","pattern":"0x00000000000000000000000000000000ba5eba11"}}}`, []common.Address{constants.NullAddress})
+	require.NoError(t, err)
+	combinedFilter, err := New(constants.TestChainID, combined, contractAddresses)
+	require.NoError(t, err)
+	result, err = combinedFilter.ValidateOrderJSON(orderWithSpecificSenderAddressJSON)
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors())
+	result, err = combinedFilter.ValidateOrderJSON(standardValidOrderJSON)
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Errors())
+}
@@ -256,6 +256,32 @@ func TestFilterValidateOrderJSON(t *testing.T) {
 	}
 }
 
+func TestFilterCheckOrderJSON(t *testing.T) {
+	t.Parallel()
+
+	filter, err := New(constants.TestChainID, `{"properties":{"senderAddress":{"pattern":"0x00000000000000000000000000000000ba5eba11","type":"string"}}}`, contractAddresses)
+	require.NoError(t, err)
+
+	result, err := filter.CheckOrderJSON(orderWithSpecificSenderAddressJSON)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Empty(t, result.Errors)
+
+	result, err = filter.CheckOrderJSON(standardValidOrderJSON)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], "senderAddress")
+
+	// The result should not depend on whether the order is checked as JSON
+	// or as a SignedOrder.
+	var signedOrder zeroex.SignedOrder
+	require.NoError(t, signedOrder.UnmarshalJSON(standardValidOrderJSON))
+	orderResult, err := filter.CheckOrder(&signedOrder)
+	require.NoError(t, err)
+	assert.Equal(t, result, orderResult)
+}
+
 func TestFilterMatchOrderMessageJSON(t *testing.T) {
 	t.Parallel()
 
@@ -22,3 +22,11 @@ func (f *Filter) MatchOrderMessageJSON(messageJSON []byte) (bool, error) {
 func (f *Filter) ValidateOrder(order *zeroex.SignedOrder) (*jsonschema.Result, error) {
 	return f.orderSchema.Validate(jsonschema.NewGoLoader(order))
 }
+
+func newCheckResult(result *jsonschema.Result) *CheckResult {
+	checkResult := &CheckResult{Valid: result.Valid()}
+	for _, resultErr := range result.Errors() {
+		checkResult.Errors = append(checkResult.Errors, resultErr.String())
+	}
+	return checkResult
+}
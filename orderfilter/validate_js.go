@@ -63,3 +63,11 @@ func (f *Filter) ValidateOrder(order *zeroex.SignedOrder) (*SchemaValidationResu
 	}
 	return f.ValidateOrderJSON(orderJSON)
 }
+
+func newCheckResult(result *SchemaValidationResult) *CheckResult {
+	checkResult := &CheckResult{Valid: result.Valid()}
+	for _, resultErr := range result.Errors() {
+		checkResult.Errors = append(checkResult.Errors, resultErr.String())
+	}
+	return checkResult
+}
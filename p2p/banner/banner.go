@@ -75,6 +75,20 @@ func (banner *Banner) ProtectIP(maddr ma.Multiaddr) error {
 	return nil
 }
 
+// UnprotectIP removes the IP address of the given Multiaddr from the list of
+// protected IP addresses, so that it can be banned again. If the IP address is
+// not protected this is a no-op.
+func (banner *Banner) UnprotectIP(maddr ma.Multiaddr) error {
+	banner.protectedIPsMut.Lock()
+	defer banner.protectedIPsMut.Unlock()
+	ipNet, err := ipNetFromMaddr(maddr)
+	if err != nil {
+		return err
+	}
+	delete(banner.protectedIPs, ipNet.IP.String())
+	return nil
+}
+
 // BanIP adds the IP address of the given Multiaddr to the blacklist. The
 // node will no longer dial or accept connections from this IP address. However,
 // if the IP address is protected, calling BanIP will not ban the IP address and
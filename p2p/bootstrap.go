@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
 	ma "github.com/multiformats/go-multiaddr"
+	madns "github.com/multiformats/go-multiaddr-dns"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -51,24 +53,90 @@ var DefaultBootstrapList = []string{
 	"/ip4/18.204.221.103/tcp/4001/ipfs/12D3KooWQS6Gsr2kLZvF7DVtoRFtj24aar5jvz88LvJePrawM3EM",
 }
 
-func BootstrapListToAddrInfos(bootstrapList []string) ([]peer.AddrInfo, error) {
-	maddrs := make([]ma.Multiaddr, len(bootstrapList))
-	for i, addrString := range bootstrapList {
+// maxDNSAddrDepth is the maximum number of nested dnsaddr records which are
+// followed when resolving a bootstrap list.
+const maxDNSAddrDepth = 4
+
+// ResolveBootstrapList parses the given multiaddress strings and groups them
+// by peer. Addresses starting with /dnsaddr are resolved via the TXT records
+// of the domain first, so that a bootstrap list can contain entries like
+// "/dnsaddr/bootstrap.example.org" which keep working when the addresses of
+// the bootstrap nodes change. Entries which can't be resolved are skipped.
+func ResolveBootstrapList(ctx context.Context, bootstrapList []string) ([]peer.AddrInfo, error) {
+	maddrs := []ma.Multiaddr{}
+	for _, addrString := range bootstrapList {
 		maddr, err := ma.NewMultiaddr(addrString)
 		if err != nil {
 			return nil, err
 		}
-		maddrs[i] = maddr
+		resolved, err := resolveDNSAddr(ctx, maddr, maxDNSAddrDepth)
+		if err != nil {
+			log.WithFields(map[string]interface{}{
+				"error":   err.Error(),
+				"address": addrString,
+			}).Warn("could not resolve bootstrap address")
+			continue
+		}
+		maddrs = append(maddrs, resolved...)
 	}
 	return peer.AddrInfosFromP2pAddrs(maddrs...)
 }
 
-func ConnectToBootstrapList(ctx context.Context, host host.Host, bootstrapList []string) error {
-	log.WithField("bootstrapList", bootstrapList).Info("connecting to bootstrap peers")
-	bootstrapAddrInfos, err := BootstrapListToAddrInfos(bootstrapList)
+// resolveBootstrapList is like ResolveBootstrapList, but if a proxy is
+// configured, DNS-based entries (e.g. /dns4 or /dnsaddr) are skipped, since
+// resolving them would require DNS lookups outside of the proxy.
+func (n *Node) resolveBootstrapList(ctx context.Context, bootstrapList []string) ([]peer.AddrInfo, error) {
+	if n.config.ProxyURL == nil {
+		return ResolveBootstrapList(ctx, bootstrapList)
+	}
+	filtered := []string{}
+	for _, addrString := range bootstrapList {
+		maddr, err := ma.NewMultiaddr(addrString)
+		if err != nil {
+			return nil, err
+		}
+		if madns.Matches(maddr) {
+			log.WithField("address", addrString).Debug("skipping DNS-based bootstrap address since a proxy is configured")
+			continue
+		}
+		filtered = append(filtered, addrString)
+	}
+	return ResolveBootstrapList(ctx, filtered)
+}
+
+// resolveDNSAddr resolves maddr if it starts with /dnsaddr, following at most
+// depth nested dnsaddr records. Other addresses are returned unchanged.
+func resolveDNSAddr(ctx context.Context, maddr ma.Multiaddr, depth int) ([]ma.Multiaddr, error) {
+	if _, err := maddr.ValueForProtocol(madns.DnsaddrProtocol.Code); err != nil {
+		return []ma.Multiaddr{maddr}, nil
+	}
+	if depth == 0 {
+		return nil, fmt.Errorf("too many nested dnsaddr records for %s", maddr)
+	}
+	resolveCtx, cancel := context.WithTimeout(ctx, defaultNetworkTimeout)
+	defer cancel()
+	resolved, err := madns.Resolve(resolveCtx, maddr)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("no dnsaddr records found for %s", maddr)
+	}
+	result := []ma.Multiaddr{}
+	for _, resolvedAddr := range resolved {
+		nested, err := resolveDNSAddr(ctx, resolvedAddr, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, nested...)
 	}
+	return result, nil
+}
+
+// ConnectToBootstrapPeers connects to the given bootstrap peers in parallel.
+// Peers which can't be reached are skipped.
+func ConnectToBootstrapPeers(ctx context.Context, host host.Host, bootstrapAddrInfos []peer.AddrInfo) {
+	log.WithField("bootstrapPeers", bootstrapAddrInfos).Info("connecting to bootstrap peers")
 	connectCtx, cancel := context.WithTimeout(ctx, defaultNetworkTimeout)
 	defer cancel()
 	wg := sync.WaitGroup{}
@@ -95,6 +163,4 @@ func ConnectToBootstrapList(ctx context.Context, host host.Host, bootstrapList [
 	// DHT to fully initialize.
 	// See: https://github.com/0xProject/0x-mesh/pull/69#discussion_r286849679
 	time.Sleep(2 * time.Second)
-
-	return nil
 }
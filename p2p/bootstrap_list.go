@@ -0,0 +1,183 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	canonicaljson "github.com/gibson042/canonicaljson-go"
+	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultBootstrapListRefreshInterval is the default value for
+	// BootstrapListRefreshInterval.
+	defaultBootstrapListRefreshInterval = 1 * time.Hour
+	// maxBootstrapListSize is the maximum size in bytes of a bootstrap list
+	// fetched from BootstrapListURL.
+	maxBootstrapListSize = 1 << 20 // 1 MiB.
+)
+
+var (
+	// ErrInvalidBootstrapListSignature is returned when a bootstrap list was
+	// not signed by the expected signer.
+	ErrInvalidBootstrapListSignature = errors.New("bootstrap list has an invalid signature")
+	// ErrOutdatedBootstrapList is returned when a bootstrap list is older than
+	// the list which was last fetched.
+	ErrOutdatedBootstrapList = errors.New("bootstrap list is older than the last fetched bootstrap list")
+)
+
+// SignedBootstrapList is a bootstrap list signed by a libp2p private key. It
+// is the format of the document served at BootstrapListURL.
+type SignedBootstrapList struct {
+	// Addrs are the multiaddresses of the bootstrap peers. They may start with
+	// /dnsaddr.
+	Addrs []string `json:"addrs"`
+	// Timestamp is when the list was signed. A list is only used if it is
+	// newer than the list that is currently used.
+	Timestamp time.Time `json:"timestamp"`
+	// Signature is the signature of the canonical JSON encoding of Addrs and
+	// Timestamp.
+	Signature []byte `json:"signature"`
+}
+
+// signedBytes returns the bytes which are signed by the signer of the list.
+func (l *SignedBootstrapList) signedBytes() ([]byte, error) {
+	return canonicaljson.Marshal(struct {
+		Addrs     []string  `json:"addrs"`
+		Timestamp time.Time `json:"timestamp"`
+	}{
+		Addrs:     l.Addrs,
+		Timestamp: l.Timestamp,
+	})
+}
+
+// Sign sets the signature of the list using the given private key.
+func (l *SignedBootstrapList) Sign(privKey p2pcrypto.PrivKey) error {
+	data, err := l.signedBytes()
+	if err != nil {
+		return err
+	}
+	signature, err := privKey.Sign(data)
+	if err != nil {
+		return err
+	}
+	l.Signature = signature
+	return nil
+}
+
+// Verify returns ErrInvalidBootstrapListSignature if the list was not signed
+// by the private key of the given peer.
+func (l *SignedBootstrapList) Verify(signer peer.ID) error {
+	pubKey, err := signer.ExtractPublicKey()
+	if err != nil {
+		return fmt.Errorf("could not get public key of bootstrap list signer: %s", err.Error())
+	}
+	data, err := l.signedBytes()
+	if err != nil {
+		return err
+	}
+	valid, err := pubKey.Verify(data, l.Signature)
+	if err != nil || !valid {
+		return ErrInvalidBootstrapListSignature
+	}
+	return nil
+}
+
+// fetchBootstrapList downloads the bootstrap list at the given URL with the
+// given HTTP client and checks that it was signed by signer.
+func fetchBootstrapList(ctx context.Context, httpClient *http.Client, url string, signer peer.ID) (*SignedBootstrapList, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, defaultNetworkTimeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := httpClient.Do(req.WithContext(fetchCtx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code while fetching bootstrap list: %d", res.StatusCode)
+	}
+	data, err := ioutil.ReadAll(http.MaxBytesReader(nil, res.Body, maxBootstrapListSize))
+	if err != nil {
+		return nil, err
+	}
+	var list SignedBootstrapList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	if err := list.Verify(signer); err != nil {
+		return nil, err
+	}
+	if len(list.Addrs) == 0 {
+		return nil, errors.New("fetched bootstrap list is empty")
+	}
+	return &list, nil
+}
+
+// updateBootstrapList fetches the bootstrap list from config.BootstrapListURL
+// and returns the resolved bootstrap peers if it is newer than the list which
+// is currently used. It returns nil if the list has not changed and
+// ErrOutdatedBootstrapList if it is older than the list which is currently
+// used or than config.LastBootstrapListTimestamp.
+func (n *Node) updateBootstrapList(ctx context.Context) ([]peer.AddrInfo, error) {
+	list, err := fetchBootstrapList(ctx, newHTTPClient(n.config.ProxyURL), n.config.BootstrapListURL, n.config.BootstrapListSigner)
+	if err != nil {
+		return nil, err
+	}
+	// The list last fetched before a restart is used again, so only lists
+	// which are strictly older than it are rejected.
+	if list.Timestamp.Before(n.bootstrapListTimestamp) || list.Timestamp.Before(n.config.LastBootstrapListTimestamp) {
+		return nil, ErrOutdatedBootstrapList
+	}
+	if list.Timestamp.Equal(n.bootstrapListTimestamp) {
+		return nil, nil
+	}
+	bootstrapAddrInfos, err := n.resolveBootstrapList(ctx, list.Addrs)
+	if err != nil {
+		return nil, err
+	}
+	n.bootstrapListTimestamp = list.Timestamp
+	if n.config.BootstrapListUpdated != nil {
+		n.config.BootstrapListUpdated(list.Timestamp)
+	}
+	log.WithFields(map[string]interface{}{
+		"timestamp": list.Timestamp,
+		"addrs":     list.Addrs,
+	}).Info("updated bootstrap list")
+	return bootstrapAddrInfos, nil
+}
+
+// refreshBootstrapList periodically fetches the bootstrap list from
+// config.BootstrapListURL and connects to the peers in it whenever it has
+// changed. It returns when the given context is canceled.
+func (n *Node) refreshBootstrapList(ctx context.Context) {
+	ticker := time.NewTicker(n.config.BootstrapListRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		bootstrapAddrInfos, err := n.updateBootstrapList(ctx)
+		if err != nil {
+			log.WithError(err).Warn("could not refresh bootstrap list")
+			continue
+		}
+		if bootstrapAddrInfos == nil {
+			continue
+		}
+		n.protectBootstrapPeers(bootstrapAddrInfos)
+		ConnectToBootstrapPeers(ctx, n.host, bootstrapAddrInfos)
+	}
+}
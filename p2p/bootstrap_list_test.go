@@ -0,0 +1,143 @@
+// +build !js
+
+package p2p
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xProject/0x-mesh/p2p/banner"
+	"github.com/google/uuid"
+	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBootstrapListSigner(t *testing.T) (p2pcrypto.PrivKey, peer.ID) {
+	privKey, _, err := p2pcrypto.GenerateSecp256k1Key(rand.Reader)
+	require.NoError(t, err)
+	signer, err := peer.IDFromPrivateKey(privKey)
+	require.NoError(t, err)
+	return privKey, signer
+}
+
+func TestSignedBootstrapListVerify(t *testing.T) {
+	privKey, signer := newBootstrapListSigner(t)
+	_, otherSigner := newBootstrapListSigner(t)
+
+	list := &SignedBootstrapList{
+		Addrs:     DefaultBootstrapList[:2],
+		Timestamp: time.Now().UTC(),
+	}
+	require.NoError(t, list.Sign(privKey))
+	assert.NoError(t, list.Verify(signer))
+	assert.Equal(t, ErrInvalidBootstrapListSignature, list.Verify(otherSigner))
+
+	// Changing the list invalidates the signature.
+	list.Addrs = DefaultBootstrapList[:3]
+	assert.Equal(t, ErrInvalidBootstrapListSignature, list.Verify(signer))
+}
+
+func TestFetchBootstrapList(t *testing.T) {
+	privKey, signer := newBootstrapListSigner(t)
+	_, otherSigner := newBootstrapListSigner(t)
+
+	list := &SignedBootstrapList{
+		Addrs:     DefaultBootstrapList[:2],
+		Timestamp: time.Now().UTC(),
+	}
+	require.NoError(t, list.Sign(privKey))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewEncoder(w).Encode(list))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fetched, err := fetchBootstrapList(ctx, http.DefaultClient, server.URL, signer)
+	require.NoError(t, err)
+	assert.Equal(t, list.Addrs, fetched.Addrs)
+	assert.True(t, list.Timestamp.Equal(fetched.Timestamp))
+
+	_, err = fetchBootstrapList(ctx, http.DefaultClient, server.URL, otherSigner)
+	assert.Equal(t, ErrInvalidBootstrapListSignature, err)
+}
+
+func TestUpdateBootstrapList(t *testing.T) {
+	privKey, signer := newBootstrapListSigner(t)
+	_, bootstrapPeerID := newBootstrapListSigner(t)
+	oldAddr := "/ip4/1.2.3.4/tcp/60558/ipfs/" + bootstrapPeerID.Pretty()
+	newAddr := "/ip4/5.6.7.8/tcp/60558/ipfs/" + bootstrapPeerID.Pretty()
+
+	start := time.Now().UTC()
+	var list *SignedBootstrapList
+	setList := func(addr string, timestamp time.Time) {
+		list = &SignedBootstrapList{
+			Addrs:     []string{addr},
+			Timestamp: timestamp,
+		}
+		require.NoError(t, list.Sign(privKey))
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewEncoder(w).Encode(list))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var updatedTimestamps []time.Time
+	node := newTestNodeWithConfig(t, ctx, nil, Config{
+		SubscribeTopic:             testTopic,
+		PublishTopics:              []string{testTopic},
+		MessageHandler:             &dummyMessageHandler{},
+		RendezvousPoints:           testRendezvousPoints,
+		BootstrapListURL:           server.URL,
+		BootstrapListSigner:        signer,
+		LastBootstrapListTimestamp: start,
+		BootstrapListUpdated: func(timestamp time.Time) {
+			updatedTimestamps = append(updatedTimestamps, timestamp)
+		},
+		DataDir: "/tmp/0x-mesh/p2p-testing/" + uuid.New().String(),
+	})
+
+	// Lists older than the list fetched before a restart are rejected.
+	setList(oldAddr, start.Add(-time.Minute))
+	_, err := node.updateBootstrapList(ctx)
+	assert.Equal(t, ErrOutdatedBootstrapList, err)
+
+	// The list fetched before a restart is used again.
+	setList(oldAddr, start)
+	bootstrapAddrInfos, err := node.updateBootstrapList(ctx)
+	require.NoError(t, err)
+	require.Len(t, bootstrapAddrInfos, 1)
+	node.protectBootstrapPeers(bootstrapAddrInfos)
+	oldMaddr := bootstrapAddrInfos[0].Addrs[0]
+	assert.Equal(t, banner.ErrProtectedIP, node.banner.BanIP(oldMaddr))
+
+	// Unchanged lists are ignored.
+	bootstrapAddrInfos, err = node.updateBootstrapList(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, bootstrapAddrInfos)
+
+	// Peers which are removed from the list are no longer protected.
+	setList(newAddr, start.Add(time.Minute))
+	bootstrapAddrInfos, err = node.updateBootstrapList(ctx)
+	require.NoError(t, err)
+	require.Len(t, bootstrapAddrInfos, 1)
+	node.protectBootstrapPeers(bootstrapAddrInfos)
+	assert.Equal(t, banner.ErrProtectedIP, node.banner.BanIP(bootstrapAddrInfos[0].Addrs[0]))
+	assert.NoError(t, node.banner.BanIP(oldMaddr))
+
+	// Replaying the previous list is rejected.
+	setList(oldAddr, start)
+	_, err = node.updateBootstrapList(ctx)
+	assert.Equal(t, ErrOutdatedBootstrapList, err)
+
+	assert.Equal(t, []time.Time{start, start.Add(time.Minute)}, updatedTimestamps)
+}
@@ -0,0 +1,116 @@
+package p2p
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	filter "github.com/libp2p/go-maddr-filter"
+)
+
+// ErrPeerNotAllowed is returned by Connect if the ConnectionGater doesn't
+// allow connections to the peer.
+var ErrPeerNotAllowed = errors.New("peer is not allowed by the connection gater")
+
+// ConnectionGater restricts which peers the node connects to and accepts
+// connections from. A connection is only allowed if both its IP address and
+// its peer ID are allowed.
+//
+// IP addresses are checked by the transports before the secure handshake
+// begins, both for incoming connections and when dialing. The peer ID of a
+// remote peer is only known once the handshake is complete, so connections
+// to and from peers whose IDs are not allowed are closed as soon as they are
+// established. Peers whose IDs are not allowed are never dialed on purpose.
+type ConnectionGater struct {
+	// AllowedCIDRs are the IP ranges (e.g. "10.0.0.0/8") that connections are
+	// allowed to and from. If empty, all IP addresses which are not in
+	// DeniedCIDRs are allowed.
+	AllowedCIDRs []string
+	// DeniedCIDRs are the IP ranges that connections are never allowed to or
+	// from. They take precedence over AllowedCIDRs.
+	DeniedCIDRs []string
+	// AllowedPeerIDs are the peers that connections are allowed to and from.
+	// If empty, all peers which are not in DeniedPeerIDs are allowed.
+	AllowedPeerIDs []peer.ID
+	// DeniedPeerIDs are the peers that connections are never allowed to or
+	// from. They take precedence over AllowedPeerIDs.
+	DeniedPeerIDs []peer.ID
+}
+
+// addFilters adds the CIDR lists of the gater to the given filters.
+func (g *ConnectionGater) addFilters(filters *filter.Filters) error {
+	allowed, err := parseCIDRs(g.AllowedCIDRs)
+	if err != nil {
+		return err
+	}
+	denied, err := parseCIDRs(g.DeniedCIDRs)
+	if err != nil {
+		return err
+	}
+	// The last matching filter wins, so the denied ranges are added after the
+	// allowed ranges. Addresses banned later on by the banner are also denied.
+	if len(allowed) > 0 {
+		filters.DefaultAction = filter.ActionDeny
+	}
+	for _, ipNet := range allowed {
+		filters.AddFilter(ipNet, filter.ActionAccept)
+	}
+	for _, ipNet := range denied {
+		filters.AddFilter(ipNet, filter.ActionDeny)
+	}
+	return nil
+}
+
+func parseCIDRs(cidrs []string) ([]net.IPNet, error) {
+	ipNets := make([]net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR in connection gater: %q", cidr)
+		}
+		ipNets[i] = *ipNet
+	}
+	return ipNets, nil
+}
+
+// peerIDGate checks the peer IDs of connections against the peer ID sets of
+// a ConnectionGater. A nil *peerIDGate allows all peers.
+type peerIDGate struct {
+	allowed map[peer.ID]struct{}
+	denied  map[peer.ID]struct{}
+}
+
+// newPeerIDGate returns a peerIDGate for the given ConnectionGater or nil if
+// it doesn't restrict peer IDs.
+func newPeerIDGate(g *ConnectionGater) *peerIDGate {
+	if g == nil || (len(g.AllowedPeerIDs) == 0 && len(g.DeniedPeerIDs) == 0) {
+		return nil
+	}
+	gate := &peerIDGate{
+		allowed: make(map[peer.ID]struct{}, len(g.AllowedPeerIDs)),
+		denied:  make(map[peer.ID]struct{}, len(g.DeniedPeerIDs)),
+	}
+	for _, id := range g.AllowedPeerIDs {
+		gate.allowed[id] = struct{}{}
+	}
+	for _, id := range g.DeniedPeerIDs {
+		gate.denied[id] = struct{}{}
+	}
+	return gate
+}
+
+// allows returns true if connections to and from the given peer are allowed.
+func (gate *peerIDGate) allows(id peer.ID) bool {
+	if gate == nil {
+		return true
+	}
+	if _, found := gate.denied[id]; found {
+		return false
+	}
+	if len(gate.allowed) == 0 {
+		return true
+	}
+	_, found := gate.allowed[id]
+	return found
+}
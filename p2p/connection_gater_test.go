@@ -0,0 +1,68 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	filter "github.com/libp2p/go-maddr-filter"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionGaterFilters(t *testing.T) {
+	gater := &ConnectionGater{
+		AllowedCIDRs: []string{"10.0.0.0/8", "3.214.190.67/32"},
+		DeniedCIDRs:  []string{"10.1.0.0/16"},
+	}
+	filters := filter.NewFilters()
+	require.NoError(t, gater.addFilters(filters))
+
+	testCases := []struct {
+		addr    string
+		blocked bool
+	}{
+		{"/ip4/10.0.0.1/tcp/60558", false},
+		{"/ip4/3.214.190.67/tcp/60558", false},
+		{"/ip4/10.1.2.3/tcp/60558", true},
+		{"/ip4/1.2.3.4/tcp/60558", true},
+	}
+	for _, testCase := range testCases {
+		maddr, err := ma.NewMultiaddr(testCase.addr)
+		require.NoError(t, err)
+		assert.Equal(t, testCase.blocked, filters.AddrBlocked(maddr), testCase.addr)
+	}
+
+	// Without an allowlist, only the denied ranges are blocked.
+	filters = filter.NewFilters()
+	require.NoError(t, (&ConnectionGater{DeniedCIDRs: []string{"10.1.0.0/16"}}).addFilters(filters))
+	assert.False(t, filters.AddrBlocked(ma.StringCast("/ip4/1.2.3.4/tcp/60558")))
+	assert.True(t, filters.AddrBlocked(ma.StringCast("/ip4/10.1.2.3/tcp/60558")))
+
+	invalidGater := &ConnectionGater{AllowedCIDRs: []string{"10.0.0.0"}}
+	assert.Error(t, invalidGater.addFilters(filter.NewFilters()))
+}
+
+func TestPeerIDGate(t *testing.T) {
+	allowedID := peer.ID("allowed")
+	deniedID := peer.ID("denied")
+	otherID := peer.ID("other")
+
+	assert.Nil(t, newPeerIDGate(nil))
+	assert.Nil(t, newPeerIDGate(&ConnectionGater{AllowedCIDRs: []string{"10.0.0.0/8"}}))
+	var nilGate *peerIDGate
+	assert.True(t, nilGate.allows(otherID))
+
+	gate := newPeerIDGate(&ConnectionGater{DeniedPeerIDs: []peer.ID{deniedID}})
+	assert.True(t, gate.allows(allowedID))
+	assert.True(t, gate.allows(otherID))
+	assert.False(t, gate.allows(deniedID))
+
+	gate = newPeerIDGate(&ConnectionGater{
+		AllowedPeerIDs: []peer.ID{allowedID, deniedID},
+		DeniedPeerIDs:  []peer.ID{deniedID},
+	})
+	assert.True(t, gate.allows(allowedID))
+	assert.False(t, gate.allows(otherID))
+	assert.False(t, gate.allows(deniedID))
+}
@@ -0,0 +1,250 @@
+package p2p
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// diversityCheckInterval is how often the peers which maintain the
+	// diversity of the peer set are chosen. It is shorter than
+	// peerGraceDuration so that new peers in a new group are protected before
+	// they become subject to pruning.
+	diversityCheckInterval = peerGraceDuration / 2
+	// diversityProtectionTag is the tag used to protect peers from being
+	// disconnected by the connection manager.
+	diversityProtectionTag = "peer-diversity"
+	// diversityDiscoveryLimit is the maximum number of new peers to look for
+	// when the node has enough peers but not enough peer groups.
+	diversityDiscoveryLimit = 5
+)
+
+// asnRange is a range of IP addresses announced by an autonomous system.
+type asnRange struct {
+	start net.IP
+	end   net.IP
+	asn   uint32
+}
+
+// peerGrouper assigns peers to groups, i.e. autonomous systems if an ASN
+// database has been loaded and IPv4 /16 or IPv6 /32 subnets otherwise.
+type peerGrouper struct {
+	// asnRanges are sorted by start address and don't overlap.
+	asnRanges []asnRange
+}
+
+// newPeerGrouper returns a peerGrouper which uses the ASN database at the given
+// path. If asnDatabasePath is empty, peers are grouped by subnet.
+func newPeerGrouper(asnDatabasePath string) (*peerGrouper, error) {
+	if asnDatabasePath == "" {
+		return &peerGrouper{}, nil
+	}
+	file, err := os.Open(asnDatabasePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	asnRanges, err := parseASNDatabase(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse ASN database %s: %s", asnDatabasePath, err.Error())
+	}
+	return &peerGrouper{asnRanges: asnRanges}, nil
+}
+
+// parseASNDatabase parses an IP to ASN database in the tab-separated format
+// used by https://iptoasn.com, i.e. one "range_start range_end AS_number ..."
+// line per range. Any columns after the AS number are ignored, as are ranges
+// with AS number 0, which are not routed.
+func parseASNDatabase(r io.Reader) ([]asnRange, error) {
+	asnRanges := []asnRange{}
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("line %d: expected at least 3 tab-separated columns", lineNumber)
+		}
+		start := net.ParseIP(fields[0])
+		end := net.ParseIP(fields[1])
+		if start == nil || end == nil {
+			return nil, fmt.Errorf("line %d: invalid IP address range", lineNumber)
+		}
+		asn, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid AS number: %s", lineNumber, err.Error())
+		}
+		if asn == 0 {
+			continue
+		}
+		asnRanges = append(asnRanges, asnRange{start: start.To16(), end: end.To16(), asn: uint32(asn)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(asnRanges, func(i, j int) bool {
+		return bytes.Compare(asnRanges[i].start, asnRanges[j].start) < 0
+	})
+	return asnRanges, nil
+}
+
+// groupForAddr returns the group of the given address. It returns false if
+// the address is a relayed address or doesn't contain an IP address.
+func (g *peerGrouper) groupForAddr(addr ma.Multiaddr) (string, bool) {
+	if _, err := addr.ValueForProtocol(ma.P_CIRCUIT); err == nil {
+		// Relayed addresses belong to the relay, not the peer.
+		return "", false
+	}
+	var ip net.IP
+	ma.ForEach(addr, func(c ma.Component) bool {
+		switch c.Protocol().Code {
+		case ma.P_IP4, ma.P_IP6:
+			ip = net.IP(c.RawValue())
+		}
+		return false
+	})
+	if ip == nil {
+		return "", false
+	}
+	if asn, found := g.asnForIP(ip); found {
+		return fmt.Sprintf("AS%d", asn), true
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return (&net.IPNet{IP: ip4.Mask(net.CIDRMask(16, 32)), Mask: net.CIDRMask(16, 32)}).String(), true
+	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(32, 128)), Mask: net.CIDRMask(32, 128)}).String(), true
+}
+
+// asnForIP returns the AS number which announces the given IP address. It
+// returns false if no ASN database has been loaded or the address is not in
+// it.
+func (g *peerGrouper) asnForIP(ip net.IP) (uint32, bool) {
+	ip = ip.To16()
+	// Find the last range which starts at or before ip.
+	i := sort.Search(len(g.asnRanges), func(i int) bool {
+		return bytes.Compare(g.asnRanges[i].start, ip) > 0
+	}) - 1
+	if i < 0 || bytes.Compare(ip, g.asnRanges[i].end) > 0 {
+		return 0, false
+	}
+	return g.asnRanges[i].asn, true
+}
+
+// peerGroups returns the connected peers by group.
+func (n *Node) peerGroups() map[string][]peer.ID {
+	groups := map[string][]peer.ID{}
+	for _, peerID := range n.host.Network().Peers() {
+		for _, conn := range n.host.Network().ConnsToPeer(peerID) {
+			if group, ok := n.peerGrouper.groupForAddr(conn.RemoteMultiaddr()); ok {
+				groups[group] = append(groups[group], peerID)
+				break
+			}
+		}
+	}
+	return groups
+}
+
+// isNewPeerGroup returns true if any of the given addresses belongs to a group
+// none of the connected peers belongs to.
+func (n *Node) isNewPeerGroup(groups map[string][]peer.ID, addrs []ma.Multiaddr) bool {
+	for _, addr := range addrs {
+		if group, ok := n.peerGrouper.groupForAddr(addr); ok {
+			if _, found := groups[group]; !found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// maintainPeerDiversity periodically protects one peer in each of
+// config.MinPeerGroups groups from being disconnected by the connection
+// manager, until the given context is canceled. This ensures that once the
+// node is connected to peers in enough different groups, it stays connected to
+// them.
+func (n *Node) maintainPeerDiversity(ctx context.Context) {
+	ticker := time.NewTicker(diversityCheckInterval)
+	defer ticker.Stop()
+	protected := map[peer.ID]struct{}{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		groups := n.peerGroups()
+		groupNames := make([]string, 0, len(groups))
+		for group := range groups {
+			groupNames = append(groupNames, group)
+		}
+		// Prefer to protect peers in the smallest groups since those are the
+		// groups the node is most likely to lose.
+		sort.Slice(groupNames, func(i, j int) bool {
+			if len(groups[groupNames[i]]) != len(groups[groupNames[j]]) {
+				return len(groups[groupNames[i]]) < len(groups[groupNames[j]])
+			}
+			return groupNames[i] < groupNames[j]
+		})
+		if len(groupNames) > n.config.MinPeerGroups {
+			groupNames = groupNames[:n.config.MinPeerGroups]
+		} else if len(groupNames) < n.config.MinPeerGroups {
+			log.WithFields(map[string]interface{}{
+				"numPeerGroups": len(groupNames),
+				"minPeerGroups": n.config.MinPeerGroups,
+			}).Debug("not connected to peers in enough different groups")
+		}
+
+		newProtected := map[peer.ID]struct{}{}
+		for _, group := range groupNames {
+			newProtected[n.highestScoringPeer(groups[group])] = struct{}{}
+		}
+		for peerID := range protected {
+			if _, found := newProtected[peerID]; !found {
+				n.connManager.Unprotect(peerID, diversityProtectionTag)
+			}
+		}
+		for peerID := range newProtected {
+			n.connManager.Protect(peerID, diversityProtectionTag)
+		}
+		protected = newProtected
+	}
+}
+
+// highestScoringPeer returns the peer with the highest peer score out of the
+// given peers.
+func (n *Node) highestScoringPeer(peerIDs []peer.ID) peer.ID {
+	best := peerIDs[0]
+	bestScore := n.peerScore(best)
+	for _, peerID := range peerIDs[1:] {
+		if score := n.peerScore(peerID); score > bestScore {
+			best = peerID
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// peerScore returns the total score of the given peer.
+func (n *Node) peerScore(id peer.ID) int {
+	tagInfo := n.connManager.GetTagInfo(id)
+	if tagInfo == nil {
+		return 0
+	}
+	return tagInfo.Value
+}
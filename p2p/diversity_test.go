@@ -0,0 +1,70 @@
+package p2p
+
+import (
+	"strings"
+	"testing"
+
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testASNDatabase = `1.0.0.0	1.0.0.255	13335	US	CLOUDFLARENET
+3.0.0.0	3.127.255.255	16509	US	AMAZON-02
+10.0.0.0	10.255.255.255	0	None	Not routed
+2600:1f00::	2600:1fff:ffff:ffff:ffff:ffff:ffff:ffff	16509	US	AMAZON-02
+`
+
+func TestPeerGrouperSubnets(t *testing.T) {
+	grouper, err := newPeerGrouper("")
+	require.NoError(t, err)
+	testCases := []struct {
+		addr          string
+		expectedGroup string
+		expectedOK    bool
+	}{
+		{"/ip4/3.214.190.67/tcp/60558", "3.214.0.0/16", true},
+		{"/ip4/3.214.1.2/tcp/60559/ws", "3.214.0.0/16", true},
+		{"/ip6/2600:1f18:1234::1/tcp/60558", "2600:1f18::/32", true},
+		{"/ip4/3.214.190.67/tcp/60558/p2p-circuit", "", false},
+		{"/unix/tmp/mesh.sock", "", false},
+	}
+	for _, testCase := range testCases {
+		addr, err := ma.NewMultiaddr(testCase.addr)
+		require.NoError(t, err)
+		group, ok := grouper.groupForAddr(addr)
+		assert.Equal(t, testCase.expectedOK, ok, testCase.addr)
+		assert.Equal(t, testCase.expectedGroup, group, testCase.addr)
+	}
+}
+
+func TestPeerGrouperASNs(t *testing.T) {
+	asnRanges, err := parseASNDatabase(strings.NewReader(testASNDatabase))
+	require.NoError(t, err)
+	grouper := &peerGrouper{asnRanges: asnRanges}
+	testCases := []struct {
+		addr          string
+		expectedGroup string
+	}{
+		{"/ip4/1.0.0.1/tcp/60558", "AS13335"},
+		{"/ip4/3.127.0.1/tcp/60558", "AS16509"},
+		{"/ip6/2600:1f18:1234::1/tcp/60558", "AS16509"},
+		// Addresses which are not in the database fall back to subnets.
+		{"/ip4/3.214.190.67/tcp/60558", "3.214.0.0/16"},
+		{"/ip4/10.0.0.1/tcp/60558", "10.0.0.0/16"},
+	}
+	for _, testCase := range testCases {
+		addr, err := ma.NewMultiaddr(testCase.addr)
+		require.NoError(t, err)
+		group, ok := grouper.groupForAddr(addr)
+		assert.True(t, ok, testCase.addr)
+		assert.Equal(t, testCase.expectedGroup, group, testCase.addr)
+	}
+}
+
+func TestParseASNDatabaseInvalid(t *testing.T) {
+	_, err := parseASNDatabase(strings.NewReader("1.0.0.0\t1.0.0.255\n"))
+	assert.Error(t, err)
+	_, err = parseASNDatabase(strings.NewReader("1.0.0.0\tinvalid\t13335\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,16 @@
+// +build !js
+
+package p2p
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/0xProject/0x-mesh/socksproxy"
+)
+
+// newHTTPClient returns an HTTP client which sends requests through the given
+// SOCKS5 proxy, or http.DefaultClient if proxyURL is nil.
+func newHTTPClient(proxyURL *url.URL) *http.Client {
+	return socksproxy.NewHTTPClient(proxyURL)
+}
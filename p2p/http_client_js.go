@@ -0,0 +1,14 @@
+// +build js,wasm
+
+package p2p
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// newHTTPClient always returns http.DefaultClient, since proxies are not
+// supported in the browser.
+func newHTTPClient(proxyURL *url.URL) *http.Client {
+	return http.DefaultClient
+}
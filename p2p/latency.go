@@ -0,0 +1,96 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// latencyMeasurementInterval is how often the latency of each connected
+	// peer is measured.
+	latencyMeasurementInterval = 30 * time.Second
+	// latencyScoreTag is the peer score tag which holds the latency score.
+	latencyScoreTag = "latency"
+)
+
+// latencyScore returns the peer score for a peer with the given round trip
+// time. Peers with a high latency get a negative score so that the connection
+// manager prefers to disconnect them when there are too many connections. The
+// score only affects the connection manager. GossipSub does not take it into
+// account when choosing the peers in its mesh.
+func latencyScore(rtt time.Duration) int {
+	switch {
+	case rtt < 100*time.Millisecond:
+		return 5
+	case rtt < 300*time.Millisecond:
+		return 0
+	case rtt < time.Second:
+		return -5
+	default:
+		return -10
+	}
+}
+
+// measurePeerLatencies pings all connected peers every interval and updates
+// their latency scores until the given context is canceled. The measured
+// round trip times are recorded in the peerstore.
+func (n *Node) measurePeerLatencies(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		wg := &sync.WaitGroup{}
+		for _, peerID := range n.host.Network().Peers() {
+			wg.Add(1)
+			go func(peerID peer.ID) {
+				defer wg.Done()
+				n.measurePeerLatency(ctx, peerID)
+			}(peerID)
+		}
+		wg.Wait()
+	}
+}
+
+// measurePeerLatency pings the given peer once and updates its latency score.
+func (n *Node) measurePeerLatency(ctx context.Context, peerID peer.ID) {
+	pingCtx, cancel := context.WithTimeout(ctx, defaultNetworkTimeout)
+	defer cancel()
+	// ping.Ping keeps pinging until the context is canceled and records the
+	// round trip time of each ping in the peerstore.
+	result, ok := <-ping.Ping(pingCtx, n.host, peerID)
+	if !ok {
+		return
+	}
+	if result.Error != nil {
+		log.WithFields(log.Fields{
+			"error":  result.Error.Error(),
+			"peerID": peerID.Pretty(),
+		}).Trace("could not measure peer latency")
+		return
+	}
+	// Use the moving average instead of the last round trip time so that a
+	// single slow ping doesn't change the score.
+	n.SetPeerScore(peerID, latencyScoreTag, latencyScore(n.host.Peerstore().LatencyEWMA(peerID)))
+}
+
+// PeerLatencies returns the moving average of the measured round trip time of
+// each connected peer. Peers whose latency has not been measured yet are
+// omitted.
+func (n *Node) PeerLatencies() map[peer.ID]time.Duration {
+	latencies := map[peer.ID]time.Duration{}
+	for _, peerID := range n.host.Network().Peers() {
+		if latency := n.host.Peerstore().LatencyEWMA(peerID); latency != 0 {
+			latencies[peerID] = latency
+		}
+	}
+	return latencies
+}
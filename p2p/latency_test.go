@@ -0,0 +1,77 @@
+// +build !js
+
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyScore(t *testing.T) {
+	testCases := []struct {
+		rtt           time.Duration
+		expectedScore int
+	}{
+		{10 * time.Millisecond, 5},
+		{100 * time.Millisecond, 0},
+		{299 * time.Millisecond, 0},
+		{300 * time.Millisecond, -5},
+		{time.Second, -10},
+		{5 * time.Second, -10},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expectedScore, latencyScore(testCase.rtt), testCase.rtt.String())
+	}
+}
+
+func TestMeasurePeerLatencies(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	node0 := newTestNode(t, ctx, nil)
+	node1 := newTestNode(t, ctx, nil)
+	connectTestNodes(t, node0, node1)
+
+	loopCtx, cancelLoop := context.WithCancel(ctx)
+	loopDone := make(chan struct{})
+	go func() {
+		defer close(loopDone)
+		node0.measurePeerLatencies(loopCtx, 10*time.Millisecond)
+	}()
+
+	// Wait for the loop to ping node1 and to tag it with its latency score.
+	deadline := time.Now().Add(5 * time.Second)
+	for !hasLatencyScore(node0, node1) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the latency score to be set")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The loop exits once its context is canceled.
+	cancelLoop()
+	select {
+	case <-loopDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("latency measurement loop did not exit after its context was canceled")
+	}
+
+	latencies := node0.PeerLatencies()
+	require.Contains(t, latencies, node1.ID())
+	latency := latencies[node1.ID()]
+	assert.True(t, latency > 0)
+	tagInfo := node0.connManager.GetTagInfo(node1.ID())
+	assert.Equal(t, latencyScore(latency), tagInfo.Tags[latencyScoreTag])
+}
+
+func hasLatencyScore(node *Node, other *Node) bool {
+	tagInfo := node.connManager.GetTagInfo(other.ID())
+	if tagInfo == nil {
+		return false
+	}
+	_, found := tagInfo.Tags[latencyScoreTag]
+	return found
+}
@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"context"
+	"time"
 
 	"github.com/libp2p/go-libp2p-core/peer"
 )
@@ -11,6 +12,9 @@ type Message struct {
 	From peer.ID
 	// Data is the underlying data for the message.
 	Data []byte
+	// ReceivedAt is when the message was received from GossipSub. It is the
+	// zero time if unknown.
+	ReceivedAt time.Time
 }
 
 // MessageHandler is an interface responsible for validating and storing
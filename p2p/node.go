@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	mathrand "math/rand"
+	"net/url"
 	"path/filepath"
 	"sync"
 	"time"
@@ -20,6 +21,8 @@ import (
 	"github.com/albrow/stringset"
 	lru "github.com/hashicorp/golang-lru"
 	libp2p "github.com/libp2p/go-libp2p"
+	autonat "github.com/libp2p/go-libp2p-autonat"
+	circuit "github.com/libp2p/go-libp2p-circuit"
 	connmgr "github.com/libp2p/go-libp2p-connmgr"
 	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/host"
@@ -85,8 +88,15 @@ const (
 	// defaultPerPeerPubSubMessageBurst is the default value for
 	// PerPeerPubSubMessageBurst.
 	defaultPerPeerPubSubMessageBurst = maxShareBatch * 5
+	// defaultPeerRateLimitBanDuration is the default value for
+	// PeerRateLimitBanDuration.
+	defaultPeerRateLimitBanDuration = 10 * time.Minute
 )
 
+// ErrUnknownPeer is returned by BanPeer if there are no known addresses for
+// the peer.
+var ErrUnknownPeer = errors.New("no known addresses for peer")
+
 // Node is the main type for the p2p package. It represents a particpant in the
 // 0x Mesh network who is capable of sending, receiving, validating, and storing
 // messages.
@@ -100,7 +110,25 @@ type Node struct {
 	routingDiscovery discovery.Discovery
 	pubsub           *pubsub.PubSub
 	sub              *pubsub.Subscription
+	validationQueue  *validationQueue
 	banner           *banner.Banner
+	rateValidator    *ratevalidator.Validator
+	// peerGrouper is nil if config.MinPeerGroups is 0.
+	peerGrouper *peerGrouper
+	// peerIDGate is nil if config.ConnectionGater doesn't restrict peer IDs.
+	peerIDGate *peerIDGate
+	// pubSubPeers receives newly found peers who speak our pubsub protocol.
+	// It is nil if config.EnablePeerExchange is false.
+	pubSubPeers       chan peer.ID
+	bandwidthCounter  *metrics.BandwidthCounter
+	peerMessageCounts *peerMessageCounts
+	autoNAT           autonat.AutoNAT
+	// bootstrapListTimestamp is the timestamp of the bootstrap list last
+	// fetched from config.BootstrapListURL.
+	bootstrapListTimestamp time.Time
+	// bootstrapPeers are the bootstrap peers which are currently protected
+	// from being banned.
+	bootstrapPeers []peer.AddrInfo
 }
 
 // Config contains configuration options for a Node.
@@ -117,9 +145,28 @@ type Config struct {
 	// WebSocketsPort is the port on which to listen for incoming WebSockets
 	// connections.
 	WebSocketsPort int
+	// ProxyURL is the URL of a SOCKS5 proxy (see socksproxy.ParseURL) through
+	// which peers are dialed. If set, the WebSocket transport is disabled, the
+	// public IP address of the node is neither looked up nor advertised, the
+	// bootstrap list is fetched through the proxy and DNS-based bootstrap
+	// addresses are skipped. It has no effect in the browser.
+	ProxyURL *url.URL
+	// ListenAddrs is a list of multiaddress strings to listen on for incoming
+	// connections (e.g. "/ip6/::/tcp/60558"). If empty, the node listens on
+	// all IPv4 and IPv6 interfaces at TCPPort and WebSocketsPort.
+	ListenAddrs []string
 	// Insecure controls whether or not messages should be encrypted. It should
 	// always be set to false in production.
 	Insecure bool
+	// SecurityTransports is the list of security transports ("tls" or
+	// "secio") to enable, in order of preference. If empty,
+	// DefaultSecurityTransports is used. It is ignored if Insecure is true.
+	SecurityTransports []string
+	// MinSecurityLevel is the minimum acceptable security level ("legacy" or
+	// "modern"). If set to "modern", secio is disabled even if it is included
+	// in SecurityTransports, so that only TLS 1.3 handshakes are accepted.
+	// Defaults to "legacy".
+	MinSecurityLevel string
 	// PrivateKey is the private key which will be used for signing messages and
 	// generating a peer ID.
 	PrivateKey p2pcrypto.PrivKey
@@ -134,8 +181,29 @@ type Config struct {
 	// peers to bootstrap the DHT for peer discovery.
 	UseBootstrapList bool
 	// BootstrapList is a list of multiaddress strings to use for bootstrapping
-	// the DHT. If empty, the default list will be used.
+	// the DHT. Addresses may start with /dnsaddr. If empty, the default list
+	// will be used.
 	BootstrapList []string
+	// BootstrapListURL is an optional HTTPS URL from which a
+	// SignedBootstrapList is fetched on startup and every
+	// BootstrapListRefreshInterval. If the list can be fetched, it is used
+	// instead of BootstrapList.
+	BootstrapListURL string
+	// BootstrapListSigner is the peer whose private key must have signed the
+	// list fetched from BootstrapListURL. It is required if BootstrapListURL is
+	// set.
+	BootstrapListSigner peer.ID
+	// BootstrapListRefreshInterval is how often the bootstrap list is fetched
+	// from BootstrapListURL. Defaults to 1 hour.
+	BootstrapListRefreshInterval time.Duration
+	// LastBootstrapListTimestamp is the timestamp of the bootstrap list which
+	// was last fetched from BootstrapListURL, e.g. before a restart. Older
+	// lists are rejected, so that an outdated list can't be replayed.
+	LastBootstrapListTimestamp time.Time
+	// BootstrapListUpdated, if non-nil, is called with the timestamp of each
+	// newer bootstrap list fetched from BootstrapListURL, so that it can be
+	// persisted and passed in as LastBootstrapListTimestamp after a restart.
+	BootstrapListUpdated func(timestamp time.Time)
 	// DataDir is the directory to use for storing data.
 	DataDir string
 	// GlobalPubSubMessageLimit is the maximum number of messages per second that
@@ -158,11 +226,75 @@ type Config struct {
 	// is allowed to send at once through the GossipSub network. Any additional
 	// messages will be dropped.
 	PerPeerPubSubMessageBurst int
+	// PeerRateLimitBanThreshold is the number of messages exceeding
+	// PerPeerPubSubMessageLimit within a minute after which a peer is
+	// temporarily banned. If 0, peers are never banned for exceeding the limit.
+	PeerRateLimitBanThreshold int
+	// PeerRateLimitBanDuration is how long the IP addresses of a peer are
+	// banned for exceeding PeerRateLimitBanThreshold. Defaults to 10 minutes.
+	PeerRateLimitBanDuration time.Duration
 	// CustomMessageValidator is a custom validator for GossipSub messages. All
 	// incoming and outgoing messages will be dropped unless they are valid
 	// according to this custom validator, which will be run in addition to the
 	// default validators.
 	CustomMessageValidator pubsub.Validator
+	// DisableForwarding prevents messages received from peers from being
+	// forwarded to other peers. Messages which are valid according to all
+	// validators are still handed to the MessageHandler.
+	DisableForwarding bool
+	// MaxPendingValidationMessages is the maximum number of messages received
+	// from peers which can be waiting to be handled by the MessageHandler. When
+	// the limit is reached, additional messages are ignored (i.e. neither
+	// handled nor forwarded) until the backlog has been processed.
+	MaxPendingValidationMessages int
+	// ValidationMemoryBudget is the maximum total size in bytes of the messages
+	// waiting to be handled by the MessageHandler. When the budget is exhausted,
+	// additional messages are ignored until the backlog has been processed.
+	ValidationMemoryBudget int
+	// MinPeerGroups is the minimum number of different groups the node tries
+	// to maintain peers in. Peers are grouped by autonomous system if
+	// ASNDatabasePath is set and by IPv4 /16 or IPv6 /32 subnet otherwise. One
+	// peer in each of MinPeerGroups groups is protected from being
+	// disconnected, and the node looks for peers in new groups until there are
+	// enough groups. If 0, the diversity of peers is not considered.
+	MinPeerGroups int
+	// ASNDatabasePath is the path of an offline IP to ASN database in the
+	// tab-separated format used by https://iptoasn.com. It is only used if
+	// MinPeerGroups is positive.
+	ASNDatabasePath string
+	// ConnectionGater restricts which IP addresses and peers the node connects
+	// to and accepts connections from. If nil, all peers are allowed.
+	ConnectionGater *ConnectionGater
+	// EnablePeerExchange determines whether the node asks newly connected
+	// peers who speak our pubsub protocol for other peers subscribed to
+	// SubscribeTopic and connects to them. This helps nodes on topics with few
+	// subscribers find each other much faster than via the DHT alone. Requests
+	// for peer exchange from other peers are always answered.
+	EnablePeerExchange bool
+	// UserAgent is the user agent advertised to peers via the identify
+	// protocol. If empty, the default user agent of libp2p is used.
+	UserAgent string
+	// InitialPeers are peers which are dialed right away when the node is
+	// started, e.g. the peers the node was connected to before it was last
+	// restarted. Peers which can't be reached are skipped.
+	InitialPeers []peer.AddrInfo
+	// EnableNATPortMap determines whether the node tries to open its ports on
+	// the router via UPnP or NAT-PMP so that it can be dialed by peers from
+	// outside of the local network. It has no effect in the browser.
+	EnableNATPortMap bool
+	// EnableRelayService determines whether the node also takes on the roles
+	// of a bootstrap node: it relays connections for peers which can't be
+	// dialed directly and advertises itself as a relay, answers AutoNAT
+	// dial-back requests and keeps its DHT routing table refreshed.
+	EnableRelayService bool
+	// MaxRelayStreams is the maximum number of open circuit relay streams
+	// when EnableRelayService is true. Each relayed connection uses two
+	// streams. If 0, the number of streams is not limited.
+	MaxRelayStreams int
+	// MaxRelayBytesPerSecond is the maximum bandwidth in bytes per second
+	// (sent and received) used for relaying connections when
+	// EnableRelayService is true. If 0, the bandwidth is not limited.
+	MaxRelayBytesPerSecond float64
 }
 
 func getPeerstoreDir(datadir string) string {
@@ -193,6 +325,21 @@ func New(ctx context.Context, config Config) (*Node, error) {
 	if config.PerPeerPubSubMessageBurst == 0 {
 		config.PerPeerPubSubMessageBurst = defaultPerPeerPubSubMessageBurst
 	}
+	if config.PeerRateLimitBanDuration == 0 {
+		config.PeerRateLimitBanDuration = defaultPeerRateLimitBanDuration
+	}
+	if config.BootstrapListURL != "" && config.BootstrapListSigner == "" {
+		return nil, errors.New("config.BootstrapListSigner is required if config.BootstrapListURL is set")
+	}
+	if config.BootstrapListRefreshInterval == 0 {
+		config.BootstrapListRefreshInterval = defaultBootstrapListRefreshInterval
+	}
+	if config.MaxPendingValidationMessages == 0 {
+		config.MaxPendingValidationMessages = defaultMaxPendingValidationMessages
+	}
+	if config.ValidationMemoryBudget == 0 {
+		config.ValidationMemoryBudget = defaultValidationMemoryBudget
+	}
 
 	// We need to declare the newDHT function ahead of time so we can use it in
 	// the libp2p.Routing option.
@@ -215,6 +362,12 @@ func New(ctx context.Context, config Config) (*Node, error) {
 
 	// Initialize filters.
 	filters := filter.NewFilters()
+	if config.ConnectionGater != nil {
+		if err := config.ConnectionGater.addFilters(filters); err != nil {
+			return nil, err
+		}
+	}
+	peerIDGate := newPeerIDGate(config.ConnectionGater)
 
 	// Set up and append environment agnostic host options.
 	bandwidthCounter := metrics.NewBandwidthCounter()
@@ -224,12 +377,23 @@ func New(ctx context.Context, config Config) (*Node, error) {
 		libp2p.ConnectionManager(connManager),
 		libp2p.Identity(config.PrivateKey),
 		libp2p.EnableAutoRelay(),
-		libp2p.EnableRelay(),
 		libp2p.BandwidthReporter(bandwidthCounter),
 		Filters(filters),
 	}...)
-	if config.Insecure {
-		opts = append(opts, libp2p.NoSecurity)
+	if config.EnableRelayService {
+		// With OptHop, AutoRelay advertises us as a relay instead of looking
+		// for relays.
+		opts = append(opts, libp2p.EnableRelay(circuit.OptHop))
+	} else {
+		opts = append(opts, libp2p.EnableRelay())
+	}
+	securityOpts, err := getSecurityOptions(config)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, securityOpts...)
+	if config.UserAgent != "" {
+		opts = append(opts, libp2p.UserAgent(config.UserAgent))
 	}
 
 	// Initialize the host.
@@ -245,54 +409,103 @@ func New(ctx context.Context, config Config) (*Node, error) {
 	}()
 
 	// Set up the notifee.
+	var pubSubPeers chan peer.ID
+	if config.EnablePeerExchange {
+		pubSubPeers = make(chan peer.ID, pubSubPeersBufferSize)
+	}
 	basicHost.Network().Notify(&notifee{
 		ctx:         ctx,
 		connManager: connManager,
+		peerIDGate:  peerIDGate,
+		pubSubPeers: pubSubPeers,
 	})
 
+	// Ask other peers to dial us back so that we know whether we are
+	// reachable from the public internet.
+	autoNAT := autonat.NewAutoNAT(ctx, basicHost, nil)
+
 	// Set up DHT for peer discovery.
 	routingDiscovery := discovery.NewRoutingDiscovery(kadDHT)
 
+	// Configure banner.
+	banner := banner.New(ctx, banner.Config{
+		Host:                   basicHost,
+		Filters:                filters,
+		BandwidthCounter:       bandwidthCounter,
+		MaxBytesPerSecond:      defaultMaxBytesPerSecond,
+		LogBandwidthUsageStats: true,
+	})
+
 	// Set up pubsub and custom validators.
 	pubsubOpts := getPubSubOptions()
 	ps, err := pubsub.NewGossipSub(ctx, basicHost, pubsubOpts...)
 	if err != nil {
 		return nil, err
 	}
-	if err := registerValidators(ctx, basicHost, config, ps); err != nil {
+	validationQueue := newValidationQueue(config.MaxPendingValidationMessages, config.ValidationMemoryBudget)
+	messageCounts := newPeerMessageCounts()
+	rateValidator, err := registerValidators(ctx, basicHost, config, ps, validationQueue, banner, messageCounts)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := ps.Subscribe(config.SubscribeTopic)
+	if err != nil {
 		return nil, err
 	}
 
-	// Configure banner.
-	banner := banner.New(ctx, banner.Config{
-		Host:                   basicHost,
-		Filters:                filters,
-		BandwidthCounter:       bandwidthCounter,
-		MaxBytesPerSecond:      defaultMaxBytesPerSecond,
-		LogBandwidthUsageStats: true,
-	})
+	var grouper *peerGrouper
+	if config.MinPeerGroups > 0 {
+		grouper, err = newPeerGrouper(config.ASNDatabasePath)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Create the Node.
 	node := &Node{
-		ctx:              ctx,
-		config:           config,
-		messageHandler:   config.MessageHandler,
-		host:             basicHost,
-		connManager:      connManager,
-		dht:              kadDHT,
-		routingDiscovery: routingDiscovery,
-		pubsub:           ps,
-		banner:           banner,
+		ctx:               ctx,
+		config:            config,
+		messageHandler:    config.MessageHandler,
+		host:              basicHost,
+		connManager:       connManager,
+		dht:               kadDHT,
+		routingDiscovery:  routingDiscovery,
+		pubsub:            ps,
+		sub:               sub,
+		validationQueue:   validationQueue,
+		banner:            banner,
+		rateValidator:     rateValidator,
+		peerGrouper:       grouper,
+		peerIDGate:        peerIDGate,
+		pubSubPeers:       pubSubPeers,
+		bandwidthCounter:  bandwidthCounter,
+		peerMessageCounts: messageCounts,
+		autoNAT:           autoNAT,
+	}
+	basicHost.SetStreamHandler(peerExchangeProtocolID, node.handlePeerExchangeStream)
+	if config.EnableRelayService {
+		if err := node.startBootstrapServices(); err != nil {
+			return nil, err
+		}
 	}
 
+	// Start moving incoming messages onto the validation queue right away so
+	// that they are not dropped by GossipSub while the node is starting up.
+	go node.receiveFromSubscription(ctx)
+
 	return node, nil
 }
 
 // registerValidators registers all the validators we use for incoming and
-// outgoing GossipSub messages.
-func registerValidators(ctx context.Context, basicHost host.Host, config Config, ps *pubsub.PubSub) error {
+// outgoing GossipSub messages. The messages received from each peer are
+// counted in messageCounts.
+func registerValidators(ctx context.Context, basicHost host.Host, config Config, ps *pubsub.PubSub, queue *validationQueue, banner *banner.Banner, messageCounts *peerMessageCounts) (*ratevalidator.Validator, error) {
 	validators := validatorset.New()
 
+	// Add the backpressure validator. It comes first so that we don't waste any
+	// resources on messages that we can't handle anyway.
+	validators.Add("validation backpressure", newBackpressureValidator(basicHost.ID(), queue))
+
 	// Add the rate limiting validator.
 	rateValidator, err := ratevalidator.New(ctx, ratevalidator.Config{
 		MyPeerID:       basicHost.ID(),
@@ -301,9 +514,13 @@ func registerValidators(ctx context.Context, basicHost host.Host, config Config,
 		PerPeerLimit:   config.PerPeerPubSubMessageLimit,
 		PerPeerBurst:   config.PerPeerPubSubMessageBurst,
 		MaxMessageSize: constants.MaxOrderSizeInBytes,
+		BanThreshold:   config.PeerRateLimitBanThreshold,
+		BanPeer: func(peerID peer.ID) {
+			banPeerTemporarily(ctx, basicHost, banner, peerID, config.PeerRateLimitBanDuration)
+		},
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	validators.Add("message rate limiting", rateValidator.Validate)
 
@@ -312,6 +529,12 @@ func registerValidators(ctx context.Context, basicHost host.Host, config Config,
 		validators.Add("custom", config.CustomMessageValidator)
 	}
 
+	// If forwarding is disabled, valid messages are handed to the
+	// MessageHandler by the last validator instead of the subscription.
+	if config.DisableForwarding {
+		validators.Add("no forwarding", newNoForwardingValidator(basicHost.ID(), queue))
+	}
+
 	// Register the set of validators for all topics that we publish and/or
 	// subscribe to.
 	//
@@ -327,12 +550,13 @@ func registerValidators(ctx context.Context, basicHost host.Host, config Config,
 	// subscribe topic will be one of the publish topics so it doesn't matter much
 	// in practice in the current implementation.
 	allTopics := stringset.NewFromSlice(append(config.PublishTopics, config.SubscribeTopic))
+	validate := messageCounts.wrapValidator(validators.Validate)
 	for topic := range allTopics {
-		if err := ps.RegisterTopicValidator(topic, validators.Validate, pubsub.WithValidatorInline(true)); err != nil {
-			return err
+		if err := ps.RegisterTopicValidator(topic, validate, pubsub.WithValidatorInline(true)); err != nil {
+			return nil, err
 		}
 	}
-	return nil
+	return rateValidator, nil
 }
 
 func getPrivateKey(path string) (p2pcrypto.PrivKey, error) {
@@ -379,21 +603,32 @@ func (n *Node) Start() error {
 		n.config.BootstrapList = DefaultBootstrapList
 	}
 
-	// If needed, connect to all peers in the bootstrap list.
+	// If needed, connect to all peers in the bootstrap list. A signed list
+	// fetched from config.BootstrapListURL takes precedence over
+	// config.BootstrapList, which is only used if the list can't be fetched.
 	if n.config.UseBootstrapList {
-		if err := ConnectToBootstrapList(n.ctx, n.host, n.config.BootstrapList); err != nil {
-			return err
-		}
-		// Protect the IP addresses for each bootstrap node.
-		bootstrapAddrInfos, err := BootstrapListToAddrInfos(n.config.BootstrapList)
-		if err != nil {
-			return err
+		var bootstrapAddrInfos []peer.AddrInfo
+		if n.config.BootstrapListURL != "" {
+			var err error
+			bootstrapAddrInfos, err = n.updateBootstrapList(n.ctx)
+			if err != nil {
+				log.WithError(err).Warn("could not fetch bootstrap list; falling back to the configured bootstrap list")
+			}
 		}
-		for _, addrInfo := range bootstrapAddrInfos {
-			for _, addr := range addrInfo.Addrs {
-				_ = n.banner.ProtectIP(addr)
+		if bootstrapAddrInfos == nil {
+			var err error
+			bootstrapAddrInfos, err = n.resolveBootstrapList(n.ctx, n.config.BootstrapList)
+			if err != nil {
+				return err
 			}
 		}
+		ConnectToBootstrapPeers(n.ctx, n.host, bootstrapAddrInfos)
+		n.protectBootstrapPeers(bootstrapAddrInfos)
+	}
+
+	// Immediately attempt to connect to the initial peers.
+	if len(n.config.InitialPeers) > 0 {
+		go n.connectToInitialPeers(n.ctx)
 	}
 
 	// Immediately attempt to connect to some peers at the rendezvous points.
@@ -439,6 +674,74 @@ func (n *Node) Start() error {
 		}
 	}()
 
+	// Start maintaining the diversity of peers.
+	if n.peerGrouper != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				log.Debug("closing p2p peer diversity loop")
+			}()
+			n.maintainPeerDiversity(innerCtx)
+		}()
+	}
+
+	// Start exchanging peers with newly found peers.
+	if n.pubSubPeers != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				log.Debug("closing p2p peer exchange loop")
+			}()
+			n.exchangePeers(innerCtx)
+		}()
+	}
+
+	// Start refreshing the bootstrap list.
+	if n.config.UseBootstrapList && n.config.BootstrapListURL != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				log.Debug("closing p2p bootstrap list refresh loop")
+			}()
+			n.refreshBootstrapList(innerCtx)
+		}()
+	}
+
+	// Start enforcing the resource limits of the relay service.
+	if n.config.EnableRelayService {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				log.Debug("closing p2p relay limits loop")
+			}()
+			n.enforceRelayLimits(innerCtx)
+		}()
+	}
+
+	// Start measuring the latency of peers.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			log.Debug("closing p2p latency measurement loop")
+		}()
+		n.measurePeerLatencies(innerCtx, latencyMeasurementInterval)
+	}()
+
+	// Start logging changes to whether the node can be dialed by other peers.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			log.Debug("closing p2p reachability loop")
+		}()
+		n.watchReachability(innerCtx)
+	}()
+
 	// Start message handler loop.
 	messageHandlerErrChan := make(chan error, 1)
 	wg.Add(1)
@@ -486,6 +789,23 @@ func (n *Node) Start() error {
 	return nil
 }
 
+// protectBootstrapPeers prevents the IP addresses of the given bootstrap peers
+// from being banned. The IP addresses of previous bootstrap peers are no
+// longer protected.
+func (n *Node) protectBootstrapPeers(bootstrapAddrInfos []peer.AddrInfo) {
+	for _, addrInfo := range n.bootstrapPeers {
+		for _, addr := range addrInfo.Addrs {
+			_ = n.banner.UnprotectIP(addr)
+		}
+	}
+	for _, addrInfo := range bootstrapAddrInfos {
+		for _, addr := range addrInfo.Addrs {
+			_ = n.banner.ProtectIP(addr)
+		}
+	}
+	n.bootstrapPeers = bootstrapAddrInfos
+}
+
 // AddPeerScore adds diff to the current score for a given peer. Tag is a unique
 // identifier for the score. A peer's total score is the sum of the scores
 // associated with each tag. Peers that end up with a low total score will
@@ -528,11 +848,121 @@ func (n *Node) Neighbors() []peer.ID {
 	return n.host.Network().Peers()
 }
 
+// TopicPeers returns the IDs of the connected peers which are subscribed to
+// the topic the node subscribes to.
+func (n *Node) TopicPeers() []peer.ID {
+	return n.pubsub.ListPeers(n.config.SubscribeTopic)
+}
+
+// ConnectedPeers returns the ID and the remote addresses of the open
+// connections of each peer that this node is currently connected to.
+func (n *Node) ConnectedPeers() []peer.AddrInfo {
+	peerIDs := n.host.Network().Peers()
+	peerInfos := make([]peer.AddrInfo, len(peerIDs))
+	for i, peerID := range peerIDs {
+		peerInfos[i] = peer.AddrInfo{ID: peerID}
+		for _, conn := range n.host.Network().ConnsToPeer(peerID) {
+			peerInfos[i].Addrs = append(peerInfos[i].Addrs, conn.RemoteMultiaddr())
+		}
+	}
+	return peerInfos
+}
+
+// PeerUserAgent returns the user agent the given peer advertised via the
+// identify protocol. It returns an empty string if the peer hasn't been
+// identified yet.
+func (n *Node) PeerUserAgent(id peer.ID) string {
+	agentVersion, err := n.host.Peerstore().Get(id, "AgentVersion")
+	if err != nil {
+		return ""
+	}
+	userAgent, _ := agentVersion.(string)
+	return userAgent
+}
+
+// BanPeer bans the IP addresses of the given peer and closes any open
+// connections to it. Both the addresses of open connections and the addresses
+// in the peerstore are banned. It returns banner.ErrProtectedIP if one of the
+// addresses is protected (e.g. belongs to a bootstrap node), in which case the
+// peer is not disconnected.
+func (n *Node) BanPeer(id peer.ID) error {
+	addrs := n.host.Peerstore().Addrs(id)
+	for _, conn := range n.host.Network().ConnsToPeer(id) {
+		addrs = append(addrs, conn.RemoteMultiaddr())
+	}
+	if len(addrs) == 0 {
+		return ErrUnknownPeer
+	}
+	for _, addr := range addrs {
+		if _, err := addr.ValueForProtocol(ma.P_CIRCUIT); err == nil {
+			// Relayed addresses belong to the relay, not the peer.
+			continue
+		}
+		if err := n.banner.BanIP(addr); err != nil {
+			return err
+		}
+	}
+	return n.host.Network().ClosePeer(id)
+}
+
+// banPeerTemporarily bans the IP addresses of the open connections to the
+// given peer for the given duration and closes the connections. Addresses
+// which are already banned are left alone, so that they stay banned after the
+// duration.
+func banPeerTemporarily(ctx context.Context, h host.Host, b *banner.Banner, id peer.ID, duration time.Duration) {
+	bannedAddrs := []ma.Multiaddr{}
+	for _, conn := range h.Network().ConnsToPeer(id) {
+		addr := conn.RemoteMultiaddr()
+		if _, err := addr.ValueForProtocol(ma.P_CIRCUIT); err == nil {
+			// Relayed addresses belong to the relay, not the peer.
+			continue
+		}
+		if b.IsAddrBanned(addr) {
+			continue
+		}
+		if err := b.BanIP(addr); err != nil {
+			if err != banner.ErrProtectedIP {
+				log.WithFields(log.Fields{
+					"remotePeerID":    id.String(),
+					"remoteMultiaddr": addr.String(),
+					"error":           err.Error(),
+				}).Error("could not ban peer")
+			}
+			continue
+		}
+		bannedAddrs = append(bannedAddrs, addr)
+	}
+	_ = h.Network().ClosePeer(id)
+	if len(bannedAddrs) == 0 {
+		return
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(duration):
+		}
+		for _, addr := range bannedAddrs {
+			_ = b.UnbanIP(addr)
+		}
+	}()
+}
+
+// RateLimitStats returns the counters of GossipSub messages dropped because of
+// the global or per-peer rate limits.
+func (n *Node) RateLimitStats() ratevalidator.Stats {
+	return n.rateValidator.Stats()
+}
+
 // Connect ensures there is a connection between this host and the peer with
 // given peerInfo. If there is not an active connection, Connect will dial the
 // peer, and block until a connection is open, timeout is exceeded, or an error
-// is returned.
+// is returned. It returns ErrPeerNotAllowed if the ConnectionGater doesn't
+// allow connections to the peer.
 func (n *Node) Connect(peerInfo peer.AddrInfo, timeout time.Duration) error {
+	if !n.peerIDGate.allows(peerInfo.ID) {
+		return ErrPeerNotAllowed
+	}
 	connectCtx, cancel := context.WithTimeout(n.ctx, timeout)
 	defer cancel()
 	err := n.host.Connect(connectCtx, peerInfo)
@@ -542,6 +972,36 @@ func (n *Node) Connect(peerInfo peer.AddrInfo, timeout time.Duration) error {
 	return nil
 }
 
+// connectToInitialPeers dials all of config.InitialPeers in parallel.
+func (n *Node) connectToInitialPeers(ctx context.Context) {
+	connectCtx, cancel := context.WithTimeout(ctx, defaultNetworkTimeout)
+	defer cancel()
+	wg := &sync.WaitGroup{}
+	numConnected := 0
+	mu := sync.Mutex{}
+	for _, peerInfo := range n.config.InitialPeers {
+		if peerInfo.ID == n.host.ID() || !n.peerIDGate.allows(peerInfo.ID) {
+			continue
+		}
+		wg.Add(1)
+		go func(peerInfo peer.AddrInfo) {
+			defer wg.Done()
+			if err := n.host.Connect(connectCtx, peerInfo); err != nil {
+				logPeerConnectionError(peerInfo, err)
+				return
+			}
+			mu.Lock()
+			numConnected++
+			mu.Unlock()
+		}(peerInfo)
+	}
+	wg.Wait()
+	log.WithFields(map[string]interface{}{
+		"numInitialPeers": len(n.config.InitialPeers),
+		"numConnected":    numConnected,
+	}).Info("connected to initial peers")
+}
+
 // startMessageHandler continuously receives and processes incoming messages
 // until there is an error or the context is canceled. It also checks bandwidth
 // usage on some iterations.
@@ -598,11 +1058,22 @@ func (n *Node) startPeerDiscovery(ctx context.Context) error {
 func (n *Node) findNewPeers(ctx context.Context) error {
 	for _, rendezvousPoint := range n.config.RendezvousPoints {
 		currentPeerCount := n.connManager.GetInfo().ConnCount
-		if currentPeerCount >= peerCountLow {
+		// If we have enough peers but they are not diverse enough, we only
+		// connect to peers in new groups.
+		var groups map[string][]peer.ID
+		onlyNewGroups := false
+		if n.peerGrouper != nil {
+			groups = n.peerGroups()
+			onlyNewGroups = currentPeerCount >= peerCountLow && len(groups) < n.config.MinPeerGroups
+		}
+		if currentPeerCount >= peerCountLow && !onlyNewGroups {
 			// We already have enough peers. Nothing to do.
 			return nil
 		}
 		maxNewPeers := peerCountLow - currentPeerCount
+		if onlyNewGroups {
+			maxNewPeers = diversityDiscoveryLimit
+		}
 		log.WithFields(map[string]interface{}{
 			"currentPeerCount": currentPeerCount,
 			"maxNewPeers":      maxNewPeers,
@@ -617,7 +1088,10 @@ func (n *Node) findNewPeers(ctx context.Context) error {
 		connectCtx, cancel := context.WithTimeout(ctx, defaultNetworkTimeout)
 		defer cancel()
 		for peer := range peerChan {
-			if peer.ID == n.host.ID() || len(peer.Addrs) == 0 {
+			if peer.ID == n.host.ID() || len(peer.Addrs) == 0 || !n.peerIDGate.allows(peer.ID) {
+				continue
+			}
+			if onlyNewGroups && !n.isNewPeerGroup(groups, peer.Addrs) {
 				continue
 			}
 			log.WithFields(map[string]interface{}{
@@ -714,16 +1188,35 @@ func (n *Node) Send(data []byte) error {
 // receive returns the next pending message. It blocks if no messages are
 // available. If the given context is canceled, it returns nil, ctx.Err().
 func (n *Node) receive(ctx context.Context) (*Message, error) {
-	if n.sub == nil {
-		var err error
-		n.sub, err = n.pubsub.Subscribe(n.config.SubscribeTopic)
+	return n.validationQueue.pop(ctx)
+}
+
+// receiveFromSubscription continuously receives messages from the GossipSub
+// subscription and pushes them onto the validation queue until the given
+// context is canceled. Receiving is decoupled from handling messages so that
+// the size of the backlog is bounded by the validation queue.
+func (n *Node) receiveFromSubscription(ctx context.Context) {
+	for {
+		msg, err := n.sub.Next(ctx)
 		if err != nil {
-			return nil, err
+			select {
+			case <-ctx.Done():
+			default:
+				log.WithError(err).Error("could not receive message from GossipSub subscription")
+			}
+			return
+		}
+		if msg.GetFrom() == n.host.ID() {
+			continue
+		}
+		if !n.validationQueue.push(&Message{From: msg.GetFrom(), Data: msg.Data, ReceivedAt: time.Now()}) {
+			log.WithField("from", msg.GetFrom().String()).Trace("dropping message because validation queue is saturated")
 		}
 	}
-	msg, err := n.sub.Next(ctx)
-	if err != nil {
-		return nil, err
-	}
-	return &Message{From: msg.GetFrom(), Data: msg.Data}, nil
+}
+
+// ValidationQueueStats returns information about the messages which have been
+// received from peers but not yet handled by the MessageHandler.
+func (n *Node) ValidationQueueStats() ValidationQueueStats {
+	return n.validationQueue.stats()
 }
@@ -16,6 +16,8 @@ import (
 	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
 	p2pnet "github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
 	ma "github.com/multiformats/go-multiaddr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -290,6 +292,89 @@ loop:
 	}
 }
 
+func TestRequestPeerExchange(t *testing.T) {
+	t.Parallel()
+	notifee := &testNotifee{
+		streams: make(chan p2pnet.Stream),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// node1 is connected to node0 and node2, both of which are subscribed to
+	// the same topic.
+	node0 := newTestNode(t, ctx, nil)
+	node1 := newTestNode(t, ctx, notifee)
+	node2 := newTestNode(t, ctx, nil)
+	connectTestNodes(t, node1, node2)
+	connectTestNodes(t, node0, node1)
+	// Each connection results in an inbound and an outbound pubsub stream for
+	// node1.
+	waitForGossipSubStreams(t, ctx, notifee, 4, testStreamTimeout)
+	topicPeersCtx, topicPeersCancel := context.WithTimeout(ctx, testStreamTimeout)
+	defer topicPeersCancel()
+	for len(node1.TopicPeers()) < 2 {
+		select {
+		case <-topicPeersCtx.Done():
+			t.Fatal("timed out waiting for node1 to learn the subscriptions of its peers")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	peerInfos, err := node0.requestPeerExchange(ctx, node1.ID())
+	require.NoError(t, err)
+	require.Len(t, peerInfos, 1)
+	assert.Equal(t, node2.ID(), peerInfos[0].ID)
+	assert.NotEmpty(t, peerInfos[0].Addrs)
+}
+
+func TestPeerDetails(t *testing.T) {
+	t.Parallel()
+	notifee := &testNotifee{
+		streams: make(chan p2pnet.Stream),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	node0 := newTestNode(t, ctx, notifee)
+	node1 := newTestNode(t, ctx, nil)
+	connectTestNodes(t, node0, node1)
+	waitForGossipSubStreams(t, ctx, notifee, 2, testStreamTimeout)
+	topicPeersCtx, topicPeersCancel := context.WithTimeout(ctx, testStreamTimeout)
+	defer topicPeersCancel()
+	for len(node0.TopicPeers()) < 1 {
+		select {
+		case <-topicPeersCtx.Done():
+			t.Fatal("timed out waiting for node0 to learn the subscriptions of node1")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	details := node0.PeerDetails()
+	require.Len(t, details, 1)
+	assert.Equal(t, node1.ID(), details[0].ID)
+	require.Len(t, details[0].Connections, 1)
+	assert.Equal(t, "outbound", details[0].Connections[0].Direction)
+	assert.Equal(t, []string{testTopic}, details[0].Topics)
+	assert.Contains(t, details[0].Protocols, string(pubsubProtocolID))
+}
+
+func TestPeerMessageCounts(t *testing.T) {
+	t.Parallel()
+	counts := newPeerMessageCounts()
+	peerID := peer.ID("peer")
+	validate := counts.wrapValidator(func(ctx context.Context, peerID peer.ID, msg *pubsub.Message) bool {
+		return len(msg.GetData()) > 0
+	})
+	ctx := context.Background()
+	assert.True(t, validate(ctx, peerID, &pubsub.Message{Message: &pb.Message{Data: []byte("valid")}}))
+	assert.True(t, validate(ctx, peerID, &pubsub.Message{Message: &pb.Message{Data: []byte("valid")}}))
+	assert.False(t, validate(ctx, peerID, &pubsub.Message{Message: &pb.Message{}}))
+	assert.Equal(t, messageCounts{received: 3, rejected: 1}, counts.get(peerID))
+	assert.Equal(t, messageCounts{}, counts.get(peer.ID("other")))
+}
+
 func TestBanIP(t *testing.T) {
 	t.Parallel()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -389,6 +474,66 @@ func TestProtectIP(t *testing.T) {
 	require.NoError(t, node1.Connect(node0AddrInfo, testConnectionTimeout))
 }
 
+func TestConnectedPeers(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	node0 := newTestNode(t, ctx, nil)
+	node1 := newTestNode(t, ctx, nil)
+	assert.Empty(t, node0.ConnectedPeers())
+
+	connectTestNodes(t, node0, node1)
+	connectedPeers := node0.ConnectedPeers()
+	require.Len(t, connectedPeers, 1)
+	assert.Equal(t, node1.ID(), connectedPeers[0].ID)
+	assert.NotEmpty(t, connectedPeers[0].Addrs)
+}
+
+func TestBanPeer(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	node0 := newTestNode(t, ctx, nil)
+	node1 := newTestNode(t, ctx, nil)
+	go startNodeAndCheckError(t, node0)
+	go startNodeAndCheckError(t, node1)
+
+	// Peers without known addresses can't be banned.
+	assert.Equal(t, ErrUnknownPeer, node0.BanPeer(node1.ID()))
+
+	connectTestNodes(t, node0, node1)
+	require.NoError(t, node0.BanPeer(node1.ID()))
+
+	// node1 is disconnected and its addresses are banned.
+	assert.Empty(t, node0.ConnectedPeers())
+	for _, maddr := range node1.Multiaddrs() {
+		assert.True(t, node0.banner.IsAddrBanned(maddr))
+	}
+	node0AddrInfo := peer.AddrInfo{
+		ID:    node0.ID(),
+		Addrs: node0.Multiaddrs(),
+	}
+	require.Error(t, node1.Connect(node0AddrInfo, testConnectionTimeout), "node1 should not be able to connect to node0")
+}
+
+func TestBanPeerProtected(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	node0 := newTestNode(t, ctx, nil)
+	node1 := newTestNode(t, ctx, nil)
+	connectTestNodes(t, node0, node1)
+
+	// Peers with protected addresses are not banned or disconnected.
+	for _, maddr := range node1.Multiaddrs() {
+		require.NoError(t, node0.banner.ProtectIP(maddr))
+	}
+	require.EqualError(t, node0.BanPeer(node1.ID()), banner.ErrProtectedIP.Error())
+	connectedPeers := node0.ConnectedPeers()
+	require.Len(t, connectedPeers, 1)
+	assert.Equal(t, node1.ID(), connectedPeers[0].ID)
+}
+
 func TestRateValidatorGlobal(t *testing.T) {
 	t.Parallel()
 
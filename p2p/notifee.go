@@ -6,6 +6,7 @@ import (
 
 	connmgr "github.com/libp2p/go-libp2p-connmgr"
 	p2pnet "github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
 	ma "github.com/multiformats/go-multiaddr"
 	log "github.com/sirupsen/logrus"
 )
@@ -22,6 +23,9 @@ const (
 type notifee struct {
 	ctx         context.Context
 	connManager *connmgr.BasicConnMgr
+	peerIDGate  *peerIDGate
+	// pubSubPeers is nil if peer exchange is disabled.
+	pubSubPeers chan<- peer.ID
 }
 
 var _ p2pnet.Notifiee = &notifee{}
@@ -34,6 +38,18 @@ func (n *notifee) ListenClose(p2pnet.Network, ma.Multiaddr) {}
 
 // Connected is called when a connection opened
 func (n *notifee) Connected(network p2pnet.Network, conn p2pnet.Conn) {
+	if !n.peerIDGate.allows(conn.RemotePeer()) {
+		log.WithFields(map[string]interface{}{
+			"remotePeerID":       conn.RemotePeer(),
+			"remoteMultiaddress": conn.RemoteMultiaddr(),
+		}).Debug("closing connection to peer not allowed by the connection gater")
+		// Closing the connection from within the notification could block the
+		// swarm, so it is closed in a separate goroutine.
+		go func() {
+			_ = conn.Close()
+		}()
+		return
+	}
 	log.WithFields(map[string]interface{}{
 		"remotePeerID":       conn.RemotePeer(),
 		"remoteMultiaddress": conn.RemoteMultiaddr(),
@@ -65,6 +81,13 @@ func (n *notifee) OpenedStream(network p2pnet.Network, stream p2pnet.Stream) {
 				"direction":    stream.Stat().Direction,
 			}).Debug("found peer who speaks our protocol")
 			n.connManager.TagPeer(stream.Conn().RemotePeer(), pubsubProtocolTag, pubsubProtocolScore)
+			if n.pubSubPeers != nil {
+				select {
+				case n.pubSubPeers <- stream.Conn().RemotePeer():
+				default:
+					// Skip peer exchange if too many peers are waiting for it.
+				}
+			}
 		}
 	}()
 }
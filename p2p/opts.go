@@ -7,8 +7,12 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/0xProject/0x-mesh/socksproxy"
 	leveldbStore "github.com/ipfs/go-ds-leveldb"
 	libp2p "github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p-core/host"
@@ -19,6 +23,7 @@ import (
 	tcp "github.com/libp2p/go-tcp-transport"
 	ws "github.com/libp2p/go-ws-transport"
 	ma "github.com/multiformats/go-multiaddr"
+	log "github.com/sirupsen/logrus"
 )
 
 const (
@@ -32,36 +37,59 @@ const (
 	// number of connections exceeds this number, we will prune connections until
 	// we reach peerCountLow.
 	peerCountHigh = 110
+	// publicIPRequestTimeout is the maximum amount of time to wait for the
+	// public IP address of the node to be looked up.
+	publicIPRequestTimeout = 10 * time.Second
 )
 
 func getHostOptions(ctx context.Context, config Config) ([]libp2p.Option, error) {
-	// Note: 0.0.0.0 will use all available addresses.
-	tcpBindAddr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/0.0.0.0/tcp/%d", config.TCPPort))
-	if err != nil {
-		return nil, err
-	}
-	wsBindAddr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/0.0.0.0/tcp/%d/ws", config.WebSocketsPort))
+	listenAddrs, err := getListenAddrs(config)
 	if err != nil {
 		return nil, err
 	}
 
-	// HACK(albrow): As a workaround for AutoNAT issues, ping ifconfig.me to
-	// determine our public IP address on boot. This will work for nodes that
-	// would be reachable via a public IP address but don't know what it is (e.g.
-	// because they are running in a Docker container).
-	publicIP, err := getPublicIP()
-	if err != nil {
-		return nil, fmt.Errorf("could not get public IP address: %s", err.Error())
-	}
-	tcpAdvertiseAddr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/%s/tcp/%d", publicIP, config.TCPPort))
-	if err != nil {
-		return nil, err
-	}
-	wsAdvertiseAddr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/%s/tcp/%d/ws", publicIP, config.WebSocketsPort))
-	if err != nil {
-		return nil, err
+	var transportOpts []libp2p.Option
+	advertiseAddrs := []ma.Multiaddr{}
+	if config.ProxyURL != nil {
+		// Peers are dialed through the proxy. The WebSocket transport doesn't
+		// support dialing through a proxy, so it is disabled. The public IP
+		// address isn't looked up either, since that would require a direct
+		// connection.
+		dialer, err := socksproxy.NewDialer(config.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		transportOpts = []libp2p.Option{
+			libp2p.Transport(newProxiedTCPTransport(dialer)),
+		}
+		listenAddrs = withoutWebSocketAddrs(listenAddrs)
+	} else {
+		// HACK(albrow): As a workaround for AutoNAT issues, ping ifconfig.me to
+		// determine our public IP address on boot. This will work for nodes that
+		// would be reachable via a public IP address but don't know what it is (e.g.
+		// because they are running in a Docker container). The public IP address
+		// is looked up separately for IPv4 and IPv6, so that both are advertised on
+		// dual-stack hosts.
+		publicIPs, err := getPublicIPs(listenAddrs)
+		if err != nil {
+			return nil, err
+		}
+		advertiseAddrs, err = getAdvertiseAddrs(listenAddrs, publicIPs)
+		if err != nil {
+			return nil, err
+		}
+
+		// Set up the WebSocket transport to ignore TLS verification. We use secio so
+		// it is not necessary.
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: true,
+		}
+		newWebsocketTransport := ws.NewWithOptions(ws.TLSClientConfig(tlsConfig))
+		transportOpts = []libp2p.Option{
+			libp2p.Transport(tcp.NewTCPTransport),
+			libp2p.Transport(newWebsocketTransport),
+		}
 	}
-	advertiseAddrs := []ma.Multiaddr{tcpAdvertiseAddr, wsAdvertiseAddr}
 
 	// Set up the peerstore to use LevelDB.
 	store, err := leveldbStore.NewDatastore(getPeerstoreDir(config.DataDir), nil)
@@ -73,20 +101,16 @@ func getHostOptions(ctx context.Context, config Config) ([]libp2p.Option, error)
 		return nil, err
 	}
 
-	// Set up the WebSocket transport to ignore TLS verification. We use secio so
-	// it is not necessary.
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: true,
-	}
-	newWebsocketTransport := ws.NewWithOptions(ws.TLSClientConfig(tlsConfig))
-
-	return []libp2p.Option{
-		libp2p.Transport(tcp.NewTCPTransport),
-		libp2p.Transport(newWebsocketTransport),
-		libp2p.ListenAddrs(tcpBindAddr, wsBindAddr),
+	opts := append(transportOpts, []libp2p.Option{
+		libp2p.ListenAddrs(listenAddrs...),
 		libp2p.AddrsFactory(newAddrsFactory(advertiseAddrs)),
 		libp2p.Peerstore(pstore),
-	}, nil
+	}...)
+	if config.EnableNATPortMap {
+		// Ports mapped on the router are added to the addresses we advertise.
+		opts = append(opts, libp2p.NATPortMap())
+	}
+	return opts, nil
 }
 
 func getPubSubOptions() []pubsub.Option {
@@ -103,17 +127,158 @@ func newAddrsFactory(advertiseAddrs []ma.Multiaddr) func([]ma.Multiaddr) []ma.Mu
 	}
 }
 
-func getPublicIP() (string, error) {
-	res, err := http.Get("https://ifconfig.me/ip")
+// getListenAddrs returns the addresses to listen on for incoming connections.
+// If config.ListenAddrs is empty, Mesh listens on all IPv4 and IPv6 interfaces
+// at config.TCPPort and config.WebSocketsPort.
+func getListenAddrs(config Config) ([]ma.Multiaddr, error) {
+	rawAddrs := config.ListenAddrs
+	if len(rawAddrs) == 0 {
+		// Note: 0.0.0.0 and :: will use all available addresses. Listening
+		// only fails if none of the addresses can be listened on, so this also
+		// works on hosts which only support one of IPv4 and IPv6.
+		rawAddrs = []string{
+			fmt.Sprintf("/ip4/0.0.0.0/tcp/%d", config.TCPPort),
+			fmt.Sprintf("/ip4/0.0.0.0/tcp/%d/ws", config.WebSocketsPort),
+			fmt.Sprintf("/ip6/::/tcp/%d", config.TCPPort),
+			fmt.Sprintf("/ip6/::/tcp/%d/ws", config.WebSocketsPort),
+		}
+	}
+	listenAddrs := make([]ma.Multiaddr, len(rawAddrs))
+	for i, rawAddr := range rawAddrs {
+		addr, err := ma.NewMultiaddr(strings.TrimSpace(rawAddr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid listen address %q: %s", rawAddr, err.Error())
+		}
+		listenAddrs[i] = addr
+	}
+	return listenAddrs, nil
+}
+
+// withoutWebSocketAddrs returns the given addresses except for WebSocket
+// addresses.
+func withoutWebSocketAddrs(addrs []ma.Multiaddr) []ma.Multiaddr {
+	result := []ma.Multiaddr{}
+	for _, addr := range addrs {
+		if _, err := addr.ValueForProtocol(ma.P_WS); err == nil {
+			continue
+		}
+		result = append(result, addr)
+	}
+	return result
+}
+
+// getPublicIPs returns the public IP address of this node for each IP
+// protocol (ma.P_IP4 or ma.P_IP6) with at least one listen address which is
+// bound to all interfaces. It returns an error only if none of the public IP
+// addresses could be determined.
+func getPublicIPs(listenAddrs []ma.Multiaddr) (map[int]net.IP, error) {
+	publicIPs := map[int]net.IP{}
+	var lastErr error
+	for _, protocol := range []int{ma.P_IP4, ma.P_IP6} {
+		if !hasUnspecifiedAddr(listenAddrs, protocol) {
+			continue
+		}
+		publicIP, err := getPublicIP(publicIPNetworks[protocol])
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":   err.Error(),
+				"network": publicIPNetworks[protocol],
+			}).Debug("could not get public IP address")
+			lastErr = err
+			continue
+		}
+		publicIPs[protocol] = publicIP
+	}
+	if len(publicIPs) == 0 && lastErr != nil {
+		return nil, fmt.Errorf("could not get public IP address: %s", lastErr.Error())
+	}
+	return publicIPs, nil
+}
+
+// publicIPNetworks maps IP protocols to the network used to look up the public
+// IP address for that protocol.
+var publicIPNetworks = map[int]string{
+	ma.P_IP4: "tcp4",
+	ma.P_IP6: "tcp6",
+}
+
+func hasUnspecifiedAddr(addrs []ma.Multiaddr, protocol int) bool {
+	for _, addr := range addrs {
+		if isUnspecifiedAddr(addr, protocol) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnspecifiedAddr returns true if the given address starts with the
+// unspecified IP address (0.0.0.0 or ::) of the given IP protocol.
+func isUnspecifiedAddr(addr ma.Multiaddr, protocol int) bool {
+	first, _ := ma.SplitFirst(addr)
+	if first == nil || first.Protocol().Code != protocol {
+		return false
+	}
+	ip := net.ParseIP(first.Value())
+	return ip != nil && ip.IsUnspecified()
+}
+
+// getAdvertiseAddrs returns the addresses to advertise in addition to the
+// addresses libp2p derives from the network interfaces. For each listen
+// address which is bound to all interfaces of an IP protocol for which the
+// public IP address is known, the same address with the public IP address is
+// advertised.
+func getAdvertiseAddrs(listenAddrs []ma.Multiaddr, publicIPs map[int]net.IP) ([]ma.Multiaddr, error) {
+	advertiseAddrs := []ma.Multiaddr{}
+	for _, protocol := range []int{ma.P_IP4, ma.P_IP6} {
+		publicIP, found := publicIPs[protocol]
+		if !found {
+			continue
+		}
+		for _, listenAddr := range listenAddrs {
+			if !isUnspecifiedAddr(listenAddr, protocol) {
+				continue
+			}
+			first, rest := ma.SplitFirst(listenAddr)
+			ipComponent, err := ma.NewComponent(first.Protocol().Name, publicIP.String())
+			if err != nil {
+				return nil, err
+			}
+			advertiseAddr := ma.Multiaddr(ipComponent)
+			if rest != nil {
+				advertiseAddr = advertiseAddr.Encapsulate(rest)
+			}
+			advertiseAddrs = append(advertiseAddrs, advertiseAddr)
+		}
+	}
+	return advertiseAddrs, nil
+}
+
+// getPublicIP looks up the public IP address of this node using the given
+// network ("tcp4" or "tcp6").
+func getPublicIP(network string) (net.IP, error) {
+	dialer := &net.Dialer{}
+	client := &http.Client{
+		Timeout: publicIPRequestTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+		},
+	}
+	res, err := client.Get("https://ifconfig.me/ip")
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer res.Body.Close()
 	ipBytes, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	ip := net.ParseIP(strings.TrimSpace(string(ipBytes)))
+	if ip == nil {
+		return nil, fmt.Errorf("unexpected response from ifconfig.me: %q", string(ipBytes))
 	}
-	return string(ipBytes), nil
+	return ip, nil
 }
 
 // NewDHT returns a new Kademlia DHT instance configured to work with 0x Mesh
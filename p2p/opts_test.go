@@ -0,0 +1,80 @@
+// +build !js
+
+package p2p
+
+import (
+	"net"
+	"testing"
+
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func multiaddrStrings(addrs []ma.Multiaddr) []string {
+	result := make([]string, len(addrs))
+	for i, addr := range addrs {
+		result[i] = addr.String()
+	}
+	return result
+}
+
+func TestGetListenAddrs(t *testing.T) {
+	listenAddrs, err := getListenAddrs(Config{TCPPort: 60558, WebSocketsPort: 60559})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/ip4/0.0.0.0/tcp/60558",
+		"/ip4/0.0.0.0/tcp/60559/ws",
+		"/ip6/::/tcp/60558",
+		"/ip6/::/tcp/60559/ws",
+	}, multiaddrStrings(listenAddrs))
+
+	listenAddrs, err = getListenAddrs(Config{
+		TCPPort:     60558,
+		ListenAddrs: []string{"/ip6/::/tcp/1234", " /ip6/::1/tcp/1235/ws"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/ip6/::/tcp/1234", "/ip6/::1/tcp/1235/ws"}, multiaddrStrings(listenAddrs))
+
+	_, err = getListenAddrs(Config{ListenAddrs: []string{"not a multiaddr"}})
+	assert.Error(t, err)
+}
+
+func TestGetAdvertiseAddrs(t *testing.T) {
+	listenAddrs := []ma.Multiaddr{
+		ma.StringCast("/ip4/0.0.0.0/tcp/60558"),
+		ma.StringCast("/ip4/0.0.0.0/tcp/60559/ws"),
+		ma.StringCast("/ip6/::/tcp/60558"),
+		ma.StringCast("/ip6/::1/tcp/60559/ws"),
+	}
+	assert.True(t, hasUnspecifiedAddr(listenAddrs, ma.P_IP4))
+	assert.True(t, hasUnspecifiedAddr(listenAddrs, ma.P_IP6))
+	assert.False(t, hasUnspecifiedAddr(listenAddrs[3:], ma.P_IP6))
+
+	publicIPs := map[int]net.IP{
+		ma.P_IP4: net.ParseIP("203.0.113.1"),
+		ma.P_IP6: net.ParseIP("2001:db8::1"),
+	}
+	advertiseAddrs, err := getAdvertiseAddrs(listenAddrs, publicIPs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/ip4/203.0.113.1/tcp/60558",
+		"/ip4/203.0.113.1/tcp/60559/ws",
+		"/ip6/2001:db8::1/tcp/60558",
+	}, multiaddrStrings(advertiseAddrs))
+
+	// Only the addresses of IP versions whose public IP address is known are
+	// advertised.
+	advertiseAddrs, err = getAdvertiseAddrs(listenAddrs, map[int]net.IP{ma.P_IP6: net.ParseIP("2001:db8::1")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/ip6/2001:db8::1/tcp/60558"}, multiaddrStrings(advertiseAddrs))
+}
+
+func TestWithoutWebSocketAddrs(t *testing.T) {
+	addrs := []ma.Multiaddr{
+		ma.StringCast("/ip4/0.0.0.0/tcp/60558"),
+		ma.StringCast("/ip4/0.0.0.0/tcp/60559/ws"),
+		ma.StringCast("/ip6/::/tcp/60558"),
+	}
+	assert.Equal(t, []string{"/ip4/0.0.0.0/tcp/60558", "/ip6/::/tcp/60558"}, multiaddrStrings(withoutWebSocketAddrs(addrs)))
+}
@@ -0,0 +1,171 @@
+package p2p
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+// PeerDetails contains everything the node knows about a connected peer.
+type PeerDetails struct {
+	ID peer.ID
+	// Connections are the open connections to the peer.
+	Connections []ConnectionDetails
+	// Addrs are the addresses of the peer in the peerstore. Unlike the remote
+	// addresses of inbound connections, they include the addresses the peer
+	// listens on.
+	Addrs []string
+	// Protocols are the protocols the peer supports.
+	Protocols []string
+	// Topics are the topics the node subscribes or publishes to which the
+	// peer is subscribed to.
+	Topics []string
+	// UserAgent is the user agent the peer advertised via the identify
+	// protocol.
+	UserAgent string
+	// Score is the current score of the peer in the connection manager. Peers
+	// with a lower score are disconnected first.
+	Score int
+	// MessagesReceived is the number of GossipSub messages received from the
+	// peer and MessagesRejected is the number of them which were dropped by
+	// the validators.
+	MessagesReceived uint64
+	MessagesRejected uint64
+	// BytesReceived and BytesSent are the total number of bytes received from
+	// and sent to the peer.
+	BytesReceived int64
+	BytesSent     int64
+}
+
+// ConnectionDetails contains information about an open connection.
+type ConnectionDetails struct {
+	RemoteMultiaddr string
+	// Direction is either "inbound" or "outbound".
+	Direction string
+	// OpenedAt is the time at which the connection was opened. It is zero if
+	// the time is not known.
+	OpenedAt time.Time
+}
+
+// peerMessageCounts counts the GossipSub messages received from each peer.
+// The counts of the least recently active peers are forgotten once more than
+// peerCountHigh * 2 peers have sent messages.
+type peerMessageCounts struct {
+	mu     sync.Mutex
+	counts *lru.Cache
+}
+
+type messageCounts struct {
+	received uint64
+	rejected uint64
+}
+
+func newPeerMessageCounts() *peerMessageCounts {
+	// lru.New only returns an error if size is <= 0, so we can safely ignore
+	// it.
+	counts, _ := lru.New(peerCountHigh * 2)
+	return &peerMessageCounts{counts: counts}
+}
+
+// wrapValidator returns a validator which counts the messages validated by
+// the given validator.
+func (c *peerMessageCounts) wrapValidator(validator pubsub.Validator) pubsub.Validator {
+	return func(ctx context.Context, peerID peer.ID, msg *pubsub.Message) bool {
+		isValid := validator(ctx, peerID, msg)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		counts := &messageCounts{}
+		if existing, found := c.counts.Get(peerID); found {
+			counts = existing.(*messageCounts)
+		} else {
+			c.counts.Add(peerID, counts)
+		}
+		counts.received++
+		if !isValid {
+			counts.rejected++
+		}
+		return isValid
+	}
+}
+
+func (c *peerMessageCounts) get(peerID peer.ID) messageCounts {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if counts, found := c.counts.Peek(peerID); found {
+		return *counts.(*messageCounts)
+	}
+	return messageCounts{}
+}
+
+func directionString(direction network.Direction) string {
+	switch direction {
+	case network.DirInbound:
+		return "inbound"
+	case network.DirOutbound:
+		return "outbound"
+	default:
+		return "unknown"
+	}
+}
+
+// PeerDetails returns details about each peer that this node is currently
+// connected to.
+func (n *Node) PeerDetails() []PeerDetails {
+	topics := append([]string{n.config.SubscribeTopic}, n.config.PublishTopics...)
+	peerIDs := n.host.Network().Peers()
+	details := make([]PeerDetails, len(peerIDs))
+	for i, peerID := range peerIDs {
+		tagInfo := n.connManager.GetTagInfo(peerID)
+		details[i] = PeerDetails{
+			ID:        peerID,
+			UserAgent: n.PeerUserAgent(peerID),
+		}
+		if tagInfo != nil {
+			details[i].Score = tagInfo.Value
+		}
+		for _, conn := range n.host.Network().ConnsToPeer(peerID) {
+			remoteMultiaddr := conn.RemoteMultiaddr().String()
+			connDetails := ConnectionDetails{
+				RemoteMultiaddr: remoteMultiaddr,
+				Direction:       directionString(conn.Stat().Direction),
+			}
+			if tagInfo != nil {
+				connDetails.OpenedAt = tagInfo.Conns[remoteMultiaddr]
+			}
+			details[i].Connections = append(details[i].Connections, connDetails)
+		}
+		for _, addr := range n.host.Peerstore().Addrs(peerID) {
+			details[i].Addrs = append(details[i].Addrs, addr.String())
+		}
+		if protocols, err := n.host.Peerstore().GetProtocols(peerID); err == nil {
+			sort.Strings(protocols)
+			details[i].Protocols = protocols
+		}
+		seenTopics := map[string]bool{}
+		for _, topic := range topics {
+			if seenTopics[topic] {
+				continue
+			}
+			seenTopics[topic] = true
+			for _, topicPeer := range n.pubsub.ListPeers(topic) {
+				if topicPeer == peerID {
+					details[i].Topics = append(details[i].Topics, topic)
+					break
+				}
+			}
+		}
+		counts := n.peerMessageCounts.get(peerID)
+		details[i].MessagesReceived = counts.received
+		details[i].MessagesRejected = counts.rejected
+		bandwidth := n.bandwidthCounter.GetBandwidthForPeer(peerID)
+		details[i].BytesReceived = bandwidth.TotalIn
+		details[i].BytesSent = bandwidth.TotalOut
+	}
+	return details
+}
@@ -0,0 +1,196 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	ma "github.com/multiformats/go-multiaddr"
+	log "github.com/sirupsen/logrus"
+)
+
+// peerExchangeProtocolID is the ID of the protocol used to ask peers for
+// other peers which are subscribed to the same topic.
+const peerExchangeProtocolID = protocol.ID("/0x-mesh/peer-exchange/version/0")
+
+const (
+	// maxPeerExchangePeers is the maximum number of peers included in a peer
+	// exchange response.
+	maxPeerExchangePeers = 16
+	// maxPeerExchangeAddrs is the maximum number of addresses per peer
+	// included in a peer exchange response.
+	maxPeerExchangeAddrs = 8
+	// maxPeerExchangeResponseSize is the maximum size in bytes of an encoded
+	// peer exchange response.
+	maxPeerExchangeResponseSize = 64 * 1024
+	// peerExchangeStreamTimeout is the maximum amount of time a peer exchange
+	// may take.
+	peerExchangeStreamTimeout = 10 * time.Second
+	// peerExchangeCacheSize is the number of peers remembered in order to
+	// only ask each peer for other peers once.
+	peerExchangeCacheSize = peerCountHigh * 2
+	// pubSubPeersBufferSize is the size of the buffer of newly found peers who
+	// speak our pubsub protocol. If the buffer is full, peer exchange with
+	// additional peers is skipped.
+	pubSubPeersBufferSize = 32
+)
+
+type peerExchangeRecord struct {
+	ID    string   `json:"id"`
+	Addrs []string `json:"addrs"`
+}
+
+type peerExchangeResponse struct {
+	Peers []peerExchangeRecord `json:"peers"`
+}
+
+// handlePeerExchangeStream responds to a peer exchange request with some of
+// the connected peers which are subscribed to our topic. The request itself
+// has no content.
+func (n *Node) handlePeerExchangeStream(stream network.Stream) {
+	defer func() {
+		_ = stream.Close()
+	}()
+	requesterID := stream.Conn().RemotePeer()
+	_ = stream.SetDeadline(time.Now().Add(peerExchangeStreamTimeout))
+
+	res := peerExchangeResponse{Peers: []peerExchangeRecord{}}
+	for _, peerID := range n.TopicPeers() {
+		if len(res.Peers) >= maxPeerExchangePeers {
+			break
+		}
+		if peerID == requesterID {
+			continue
+		}
+		record := peerExchangeRecord{ID: peerID.Pretty()}
+		for _, addr := range n.host.Peerstore().Addrs(peerID) {
+			if len(record.Addrs) >= maxPeerExchangeAddrs {
+				break
+			}
+			record.Addrs = append(record.Addrs, addr.String())
+		}
+		if len(record.Addrs) > 0 {
+			res.Peers = append(res.Peers, record)
+		}
+	}
+	if err := json.NewEncoder(stream).Encode(res); err != nil {
+		log.WithFields(log.Fields{
+			"error":     err.Error(),
+			"requester": requesterID.Pretty(),
+		}).Trace("could not encode peer exchange response")
+	}
+}
+
+// requestPeerExchange asks the given peer for other peers which are
+// subscribed to our topic. Invalid records in the response are skipped.
+func (n *Node) requestPeerExchange(ctx context.Context, peerID peer.ID) ([]peer.AddrInfo, error) {
+	stream, err := n.host.NewStream(ctx, peerID, peerExchangeProtocolID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+	_ = stream.SetDeadline(time.Now().Add(peerExchangeStreamTimeout))
+	var res peerExchangeResponse
+	if err := json.NewDecoder(io.LimitReader(stream, maxPeerExchangeResponseSize)).Decode(&res); err != nil {
+		return nil, err
+	}
+	if len(res.Peers) > maxPeerExchangePeers {
+		res.Peers = res.Peers[:maxPeerExchangePeers]
+	}
+	peerInfos := []peer.AddrInfo{}
+	for _, record := range res.Peers {
+		id, err := peer.IDB58Decode(record.ID)
+		if err != nil {
+			continue
+		}
+		peerInfo := peer.AddrInfo{ID: id}
+		for _, addrString := range record.Addrs {
+			addr, err := ma.NewMultiaddr(addrString)
+			if err != nil {
+				continue
+			}
+			peerInfo.Addrs = append(peerInfo.Addrs, addr)
+		}
+		if len(peerInfo.Addrs) > 0 {
+			peerInfos = append(peerInfos, peerInfo)
+		}
+	}
+	return peerInfos, nil
+}
+
+// exchangePeers asks every newly found peer who speaks our pubsub protocol
+// for other peers subscribed to our topic and connects to them, until the
+// given context is canceled. On topics with few subscribers this finds the
+// other subscribers much faster than looking for them on the DHT.
+//
+// The addresses in peer exchange responses are not signed by the peers they
+// belong to, so a peer could send us addresses of peers that don't exist. We
+// only ever dial a limited number of them per peer and each peer is only asked
+// once.
+func (n *Node) exchangePeers(ctx context.Context) {
+	// lru.New only returns an error if size is <= 0, so we can safely ignore
+	// it.
+	askedPeers, _ := lru.New(peerExchangeCacheSize)
+	for {
+		var peerID peer.ID
+		select {
+		case <-ctx.Done():
+			return
+		case peerID = <-n.pubSubPeers:
+		}
+		if alreadyAsked, _ := askedPeers.ContainsOrAdd(peerID, struct{}{}); alreadyAsked {
+			continue
+		}
+		maxNewPeers := peerCountLow - n.connManager.GetInfo().ConnCount
+		if maxNewPeers <= 0 {
+			continue
+		}
+		go n.connectToExchangedPeers(ctx, peerID, maxNewPeers)
+	}
+}
+
+// connectToExchangedPeers asks the given peer for other peers and connects
+// to up to maxNewPeers of them.
+func (n *Node) connectToExchangedPeers(ctx context.Context, peerID peer.ID, maxNewPeers int) {
+	requestCtx, cancel := context.WithTimeout(ctx, peerExchangeStreamTimeout)
+	defer cancel()
+	peerInfos, err := n.requestPeerExchange(requestCtx, peerID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error":  err.Error(),
+			"peerID": peerID.Pretty(),
+		}).Trace("could not exchange peers")
+		return
+	}
+	log.WithFields(log.Fields{
+		"peerID":   peerID.Pretty(),
+		"numPeers": len(peerInfos),
+	}).Trace("received peers via peer exchange")
+
+	connectCtx, cancel := context.WithTimeout(ctx, defaultNetworkTimeout)
+	defer cancel()
+	numNewPeers := 0
+	for _, peerInfo := range peerInfos {
+		if numNewPeers >= maxNewPeers {
+			return
+		}
+		if peerInfo.ID == n.host.ID() || !n.peerIDGate.allows(peerInfo.ID) {
+			continue
+		}
+		if n.host.Network().Connectedness(peerInfo.ID) == network.Connected {
+			continue
+		}
+		if err := n.host.Connect(connectCtx, peerInfo); err != nil {
+			logPeerConnectionError(peerInfo, err)
+			continue
+		}
+		numNewPeers++
+	}
+}
@@ -0,0 +1,84 @@
+// +build !js
+
+package p2p
+
+import (
+	"context"
+	"net"
+
+	"github.com/0xProject/0x-mesh/socksproxy"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/transport"
+	tptu "github.com/libp2p/go-libp2p-transport-upgrader"
+	tcp "github.com/libp2p/go-tcp-transport"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr-net"
+)
+
+// proxiedTCPTransport is a TCP transport which dials peers through a SOCKS5
+// proxy. Listening for incoming connections works like for the regular TCP
+// transport.
+type proxiedTCPTransport struct {
+	*tcp.TcpTransport
+	dialer *socksproxy.Dialer
+}
+
+// newProxiedTCPTransport returns a constructor for a proxiedTCPTransport which
+// can be passed to libp2p.Transport.
+func newProxiedTCPTransport(dialer *socksproxy.Dialer) func(upgrader *tptu.Upgrader) *proxiedTCPTransport {
+	return func(upgrader *tptu.Upgrader) *proxiedTCPTransport {
+		return &proxiedTCPTransport{
+			TcpTransport: tcp.NewTCPTransport(upgrader),
+			dialer:       dialer,
+		}
+	}
+}
+
+// Dial implements transport.Transport.
+func (t *proxiedTCPTransport) Dial(ctx context.Context, raddr ma.Multiaddr, p peer.ID) (transport.CapableConn, error) {
+	_, address, err := manet.DialArgs(raddr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := t.dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	maconn, err := newProxiedConn(conn, raddr)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return t.Upgrader.UpgradeOutbound(ctx, t, maconn, p)
+}
+
+// proxiedConn is a manet.Conn for a connection made through a proxy. The
+// remote address of the underlying connection is the address the proxy
+// reports, so the dialed address is used instead.
+type proxiedConn struct {
+	net.Conn
+	laddr ma.Multiaddr
+	raddr ma.Multiaddr
+}
+
+func newProxiedConn(conn net.Conn, raddr ma.Multiaddr) (*proxiedConn, error) {
+	laddr, err := manet.FromNetAddr(conn.LocalAddr())
+	if err != nil {
+		return nil, err
+	}
+	return &proxiedConn{
+		Conn:  conn,
+		laddr: laddr,
+		raddr: raddr,
+	}, nil
+}
+
+// LocalMultiaddr implements manet.Conn.
+func (c *proxiedConn) LocalMultiaddr() ma.Multiaddr {
+	return c.laddr
+}
+
+// RemoteMultiaddr implements manet.Conn.
+func (c *proxiedConn) RemoteMultiaddr() ma.Multiaddr {
+	return c.raddr
+}
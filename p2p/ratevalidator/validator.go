@@ -3,6 +3,8 @@ package ratevalidator
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/karlseguin/ccache"
@@ -23,6 +25,9 @@ const (
 	peerLimiterCacheTTL = 5 * time.Minute
 	// logStatsInterval is how often to log stats about rate limiting.
 	logStatsInterval = 1 * time.Hour
+	// banWindow is the period over which per-peer limit violations are counted
+	// to decide whether a peer should be banned.
+	banWindow = 1 * time.Minute
 )
 
 // Dummy declaration to ensure that Validate can be used as a pubsub.Validator
@@ -35,6 +40,34 @@ type Validator struct {
 	config        Config
 	globalLimiter *trackingRateLimiter
 	peerLimiters  *ccache.Cache
+	stats         Stats
+}
+
+// Stats are counters of the messages dropped by the validator since it was
+// created.
+type Stats struct {
+	// PerPeerLimitViolations is the number of messages dropped because a peer
+	// exceeded the per-peer limit or was banned.
+	PerPeerLimitViolations uint64
+	// GlobalLimitViolations is the number of messages dropped because the
+	// global limit was exceeded.
+	GlobalLimitViolations uint64
+	// OversizedMessages is the number of messages dropped because they
+	// exceeded the maximum message size.
+	OversizedMessages uint64
+	// NumBans is the number of times a peer was banned for exceeding the
+	// per-peer limit too often.
+	NumBans uint64
+}
+
+// peerLimiter is the rate limiter of a single peer. It also counts the
+// violations of the per-peer limit in the current ban window.
+type peerLimiter struct {
+	limiter     *rate.Limiter
+	mu          sync.Mutex
+	windowStart time.Time
+	violations  int
+	banned      bool
 }
 
 // Config is a set of configuration options for the validator.
@@ -56,6 +89,14 @@ type Config struct {
 	// MaxMessageSize is the maximum size (in bytes) for a message. Any messages
 	// that exceed this size will be considered invalid.
 	MaxMessageSize int
+	// BanThreshold is the number of messages exceeding the per-peer limit
+	// within a minute after which a peer is banned via BanPeer. If 0 or if
+	// BanPeer is nil, peers are never banned.
+	BanThreshold int
+	// BanPeer is called (in a separate goroutine) when a peer exceeds
+	// BanThreshold. Any further messages from the peer are dropped until its
+	// rate limiter expires from the cache.
+	BanPeer func(peerID peer.ID)
 }
 
 // New creates and returns a new rate limiting validator.
@@ -103,6 +144,7 @@ func (v *Validator) Validate(ctx context.Context, peerID peer.ID, msg *pubsub.Me
 	}
 
 	if data := msg.GetData(); data != nil && len(data) > v.config.MaxMessageSize {
+		atomic.AddUint64(&v.stats.OversizedMessages, 1)
 		return false
 	}
 
@@ -113,22 +155,80 @@ func (v *Validator) Validate(ctx context.Context, peerID peer.ID, msg *pubsub.Me
 		log.WithError(err).Error("unexpected error in getOrCreateLimiterForPeer")
 		return false
 	}
-	if !peerLimiter.Allow() {
+	if peerLimiter.isBanned() {
+		atomic.AddUint64(&v.stats.PerPeerLimitViolations, 1)
+		return false
+	}
+	if !peerLimiter.limiter.Allow() {
+		atomic.AddUint64(&v.stats.PerPeerLimitViolations, 1)
+		v.addViolation(peerID, peerLimiter)
 		return false
 	}
 
-	return v.globalLimiter.allow()
+	if !v.globalLimiter.allow() {
+		atomic.AddUint64(&v.stats.GlobalLimitViolations, 1)
+		return false
+	}
+	return true
 }
 
-func (v *Validator) getOrCreateLimiterForPeer(peerID peer.ID) (*rate.Limiter, error) {
+// Stats returns the counters of dropped messages.
+func (v *Validator) Stats() Stats {
+	return Stats{
+		PerPeerLimitViolations: atomic.LoadUint64(&v.stats.PerPeerLimitViolations),
+		GlobalLimitViolations:  atomic.LoadUint64(&v.stats.GlobalLimitViolations),
+		OversizedMessages:      atomic.LoadUint64(&v.stats.OversizedMessages),
+		NumBans:                atomic.LoadUint64(&v.stats.NumBans),
+	}
+}
+
+// addViolation counts a violation of the per-peer limit by the given peer and
+// bans the peer if it exceeded config.BanThreshold in the current ban window.
+func (v *Validator) addViolation(peerID peer.ID, peerLimiter *peerLimiter) {
+	if v.config.BanThreshold == 0 || v.config.BanPeer == nil {
+		return
+	}
+	peerLimiter.mu.Lock()
+	defer peerLimiter.mu.Unlock()
+	now := time.Now()
+	if now.Sub(peerLimiter.windowStart) > banWindow {
+		peerLimiter.windowStart = now
+		peerLimiter.violations = 0
+	}
+	peerLimiter.violations++
+	if peerLimiter.violations != v.config.BanThreshold {
+		return
+	}
+	// Drop all further messages from the peer until its limiter expires from
+	// the cache, i.e. for at most peerLimiterCacheTTL.
+	peerLimiter.banned = true
+	atomic.AddUint64(&v.stats.NumBans, 1)
+	log.WithFields(log.Fields{
+		"peerID":       peerID.String(),
+		"banThreshold": v.config.BanThreshold,
+	}).Warn("banning peer for exceeding the message rate limit")
+	go v.config.BanPeer(peerID)
+}
+
+// isBanned returns true if the peer has been banned.
+func (l *peerLimiter) isBanned() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.banned
+}
+
+func (v *Validator) getOrCreateLimiterForPeer(peerID peer.ID) (*peerLimiter, error) {
 	item, err := v.peerLimiters.Fetch(peerID.String(), peerLimiterCacheTTL, func() (interface{}, error) {
-		limiter := rate.NewLimiter(v.config.PerPeerLimit, v.config.PerPeerBurst)
+		limiter := &peerLimiter{
+			limiter:     rate.NewLimiter(v.config.PerPeerLimit, v.config.PerPeerBurst),
+			windowStart: time.Now(),
+		}
 		return limiter, nil
 	})
 	if err != nil {
 		return nil, err
 	}
-	return item.Value().(*rate.Limiter), nil
+	return item.Value().(*peerLimiter), nil
 }
 
 // isClosed returns true if the context is done and false otherwise.
@@ -126,3 +126,50 @@ func TestValidatorMaxMessageSize(t *testing.T) {
 		assert.False(t, isValid, "message should be invalid")
 	}
 }
+
+func TestValidatorBanThreshold(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	bannedPeers := make(chan peer.ID, 1)
+	validator, err := New(ctx, Config{
+		MyPeerID:       peerIDs[0],
+		GlobalLimit:    rate.Inf,
+		PerPeerLimit:   1,
+		PerPeerBurst:   1,
+		MaxMessageSize: 1024,
+		BanThreshold:   3,
+		BanPeer: func(peerID peer.ID) {
+			bannedPeers <- peerID
+		},
+	})
+	require.NoError(t, err)
+
+	// The first message is allowed and the next BanThreshold messages exceed
+	// the per-peer limit.
+	for i := 0; i < validator.config.PerPeerBurst+validator.config.BanThreshold; i++ {
+		validator.Validate(ctx, peerIDs[1], &pubsub.Message{})
+	}
+	select {
+	case bannedPeer := <-bannedPeers:
+		assert.Equal(t, peerIDs[1], bannedPeer)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for peer to be banned")
+	}
+
+	// Messages from the banned peer should be invalid even once the limiter
+	// would allow them again.
+	time.Sleep(1 * time.Second)
+	valid := validator.Validate(ctx, peerIDs[1], &pubsub.Message{})
+	assert.False(t, valid, "message from banned peer should be invalid")
+
+	// Other peers should not be affected.
+	valid = validator.Validate(ctx, peerIDs[2], &pubsub.Message{})
+	assert.True(t, valid, "message should be valid")
+
+	stats := validator.Stats()
+	assert.Equal(t, uint64(1), stats.NumBans)
+	assert.Equal(t, uint64(validator.config.BanThreshold+1), stats.PerPeerLimitViolations)
+}
@@ -0,0 +1,97 @@
+package p2p
+
+import (
+	"context"
+	"time"
+
+	autonat "github.com/libp2p/go-libp2p-autonat"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr-net"
+	log "github.com/sirupsen/logrus"
+)
+
+// reachabilityCheckInterval is how often the result of AutoNAT is checked
+// for changes.
+const reachabilityCheckInterval = 30 * time.Second
+
+const (
+	// NATStatusUnknown means that AutoNAT has not determined yet whether the
+	// node can be dialed from the public internet.
+	NATStatusUnknown = "unknown"
+	// NATStatusPublic means that other peers were able to dial the node at a
+	// public address.
+	NATStatusPublic = "public"
+	// NATStatusPrivate means that other peers were not able to dial the node.
+	NATStatusPrivate = "private"
+)
+
+// Reachability describes whether the node can be dialed by other peers.
+type Reachability struct {
+	// NATStatus is one of NATStatusUnknown, NATStatusPublic or
+	// NATStatusPrivate.
+	NATStatus string
+	// ConfirmedPublicAddrs are the public addresses at which other peers
+	// successfully dialed the node. It is empty unless NATStatus is
+	// NATStatusPublic.
+	ConfirmedPublicAddrs []ma.Multiaddr
+	// AdvertisedPublicAddrs are the public addresses the node advertises to
+	// other peers, including any ports mapped via UPnP or NAT-PMP. They are not
+	// necessarily dialable.
+	AdvertisedPublicAddrs []ma.Multiaddr
+}
+
+// Reachability returns whether the node can currently be dialed by other
+// peers according to AutoNAT.
+func (n *Node) Reachability() Reachability {
+	reachability := Reachability{
+		NATStatus:             NATStatusUnknown,
+		ConfirmedPublicAddrs:  []ma.Multiaddr{},
+		AdvertisedPublicAddrs: []ma.Multiaddr{},
+	}
+	for _, addr := range n.host.Addrs() {
+		if manet.IsPublicAddr(addr) {
+			reachability.AdvertisedPublicAddrs = append(reachability.AdvertisedPublicAddrs, addr)
+		}
+	}
+	switch n.autoNAT.Status() {
+	case autonat.NATStatusPublic:
+		reachability.NATStatus = NATStatusPublic
+		if addr, err := n.autoNAT.PublicAddr(); err == nil {
+			reachability.ConfirmedPublicAddrs = append(reachability.ConfirmedPublicAddrs, addr)
+		}
+	case autonat.NATStatusPrivate:
+		reachability.NATStatus = NATStatusPrivate
+	}
+	return reachability
+}
+
+// watchReachability periodically checks the result of AutoNAT and logs
+// whenever it changes, so that operators can tell whether inbound
+// connectivity works. It returns when the given context is canceled.
+func (n *Node) watchReachability(ctx context.Context) {
+	ticker := time.NewTicker(reachabilityCheckInterval)
+	defer ticker.Stop()
+	lastStatus := NATStatusUnknown
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		reachability := n.Reachability()
+		if reachability.NATStatus == lastStatus {
+			continue
+		}
+		lastStatus = reachability.NATStatus
+		logger := log.WithFields(log.Fields{
+			"natStatus":             reachability.NATStatus,
+			"confirmedPublicAddrs":  reachability.ConfirmedPublicAddrs,
+			"advertisedPublicAddrs": reachability.AdvertisedPublicAddrs,
+		})
+		if reachability.NATStatus == NATStatusPrivate {
+			logger.Warn("node is not dialable from the public internet")
+		} else {
+			logger.Info("node reachability changed")
+		}
+	}
+}
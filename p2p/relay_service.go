@@ -0,0 +1,85 @@
+// +build !js
+
+package p2p
+
+import (
+	"context"
+	"time"
+
+	autonatsvc "github.com/libp2p/go-libp2p-autonat-svc"
+	circuit "github.com/libp2p/go-libp2p-circuit"
+	"github.com/libp2p/go-libp2p-core/network"
+	log "github.com/sirupsen/logrus"
+)
+
+// relayLimitsCheckInterval is how often the resource usage of the relay
+// service is compared to the configured limits.
+const relayLimitsCheckInterval = 5 * time.Second
+
+// startBootstrapServices starts the services which bootstrap nodes provide to
+// other peers in addition to relaying: answering AutoNAT dial-back requests
+// and keeping the DHT routing table refreshed so that other peers can find
+// each other through this node.
+func (n *Node) startBootstrapServices() error {
+	if _, err := autonatsvc.NewAutoNATService(n.ctx, n.host); err != nil {
+		return err
+	}
+	return n.dht.Bootstrap(n.ctx)
+}
+
+// relayStreams returns all open circuit relay streams. Each connection that
+// is relayed through this node uses two streams, one to each peer.
+func (n *Node) relayStreams() []network.Stream {
+	streams := []network.Stream{}
+	for _, conn := range n.host.Network().Conns() {
+		for _, stream := range conn.GetStreams() {
+			if stream.Protocol() == circuit.ProtoID {
+				streams = append(streams, stream)
+			}
+		}
+	}
+	return streams
+}
+
+// enforceRelayLimits periodically checks the number of relay streams and the
+// bandwidth used for relaying until the given context is canceled. Streams
+// above config.MaxRelayStreams are reset, and all relay streams are reset if
+// the bandwidth exceeds config.MaxRelayBytesPerSecond. Peers whose relayed
+// connections were reset fall back to other relays.
+func (n *Node) enforceRelayLimits(ctx context.Context) {
+	ticker := time.NewTicker(relayLimitsCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		streams := n.relayStreams()
+		if n.config.MaxRelayBytesPerSecond > 0 {
+			stats := n.bandwidthCounter.GetBandwidthForProtocol(circuit.ProtoID)
+			if rate := stats.RateIn + stats.RateOut; rate > n.config.MaxRelayBytesPerSecond {
+				log.WithFields(log.Fields{
+					"bytesPerSecond":    rate,
+					"maxBytesPerSecond": n.config.MaxRelayBytesPerSecond,
+					"numStreams":        len(streams),
+				}).Warn("relay bandwidth limit exceeded; resetting all relay streams")
+				resetStreams(streams)
+				continue
+			}
+		}
+		if n.config.MaxRelayStreams > 0 && len(streams) > n.config.MaxRelayStreams {
+			log.WithFields(log.Fields{
+				"numStreams": len(streams),
+				"maxStreams": n.config.MaxRelayStreams,
+			}).Warn("relay stream limit exceeded; resetting excess relay streams")
+			resetStreams(streams[n.config.MaxRelayStreams:])
+		}
+	}
+}
+
+func resetStreams(streams []network.Stream) {
+	for _, stream := range streams {
+		_ = stream.Reset()
+	}
+}
@@ -0,0 +1,18 @@
+// +build js,wasm
+
+package p2p
+
+import (
+	"context"
+	"errors"
+)
+
+// startBootstrapServices returns an error, since browser nodes can't accept
+// incoming connections and therefore can't serve as bootstrap nodes.
+func (n *Node) startBootstrapServices() error {
+	return errors.New("the relay service is not supported in the browser")
+}
+
+// enforceRelayLimits is never called in the browser, since
+// startBootstrapServices always fails.
+func (n *Node) enforceRelayLimits(ctx context.Context) {}
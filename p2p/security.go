@@ -0,0 +1,104 @@
+package p2p
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	libp2p "github.com/libp2p/go-libp2p"
+	secio "github.com/libp2p/go-libp2p-secio"
+	libp2ptls "github.com/libp2p/go-libp2p-tls"
+	log "github.com/sirupsen/logrus"
+)
+
+// Names of the supported security transports, which encrypt and authenticate
+// connections to peers.
+const (
+	SecurityTransportTLS   = "tls"
+	SecurityTransportSecio = "secio"
+	// SecurityTransportNoise is not supported yet. Every release of
+	// go-libp2p-noise requires go-libp2p >= v0.8.1 and go-libp2p-core >=
+	// v0.5.1, so adding it would upgrade the whole libp2p stack. It is
+	// recognized so that configuring it fails with a clear error instead of
+	// being mistaken for a typo.
+	SecurityTransportNoise = "noise"
+)
+
+// errNoiseNotSupported is returned if the Noise security transport is
+// configured.
+var errNoiseNotSupported = fmt.Errorf("the %q security transport is not supported until go-libp2p is upgraded to v0.8.1 or later (use %q for modern handshakes)", SecurityTransportNoise, SecurityTransportTLS)
+
+// Security levels, which determine the minimum acceptable security transport.
+const (
+	// SecurityLevelLegacy accepts all supported security transports, including
+	// secio.
+	SecurityLevelLegacy = "legacy"
+	// SecurityLevelModern only accepts security transports with a modern
+	// handshake (TLS 1.3).
+	SecurityLevelModern = "modern"
+)
+
+// DefaultSecurityTransports is the default list of security transports in
+// order of preference. secio is preferred so that connections to peers which
+// only support secio don't require an extra round trip.
+var DefaultSecurityTransports = []string{
+	SecurityTransportSecio,
+	SecurityTransportTLS,
+}
+
+type securityTransport struct {
+	id          string
+	constructor interface{}
+	level       string
+}
+
+var securityTransports = map[string]securityTransport{
+	SecurityTransportTLS:   {id: libp2ptls.ID, constructor: libp2ptls.New, level: SecurityLevelModern},
+	SecurityTransportSecio: {id: secio.ID, constructor: secio.New, level: SecurityLevelLegacy},
+}
+
+// getSecurityOptions returns the host options which enable the configured
+// security transports in order of preference. Transports below the minimum
+// security level are left out. An error is returned if an unknown transport or
+// level is configured or if no transports remain.
+func getSecurityOptions(config Config) ([]libp2p.Option, error) {
+	if config.Insecure {
+		return []libp2p.Option{libp2p.NoSecurity}, nil
+	}
+	names := config.SecurityTransports
+	if len(names) == 0 {
+		names = DefaultSecurityTransports
+	}
+	minLevel := config.MinSecurityLevel
+	switch minLevel {
+	case "", SecurityLevelLegacy, SecurityLevelModern:
+	default:
+		return nil, fmt.Errorf("invalid minimum security level: %q (must be one of %q or %q)", minLevel, SecurityLevelLegacy, SecurityLevelModern)
+	}
+
+	opts := []libp2p.Option{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == SecurityTransportNoise {
+			return nil, errNoiseNotSupported
+		}
+		transport, found := securityTransports[name]
+		if !found {
+			return nil, fmt.Errorf("unsupported security transport: %q (must be one of %q or %q)", name, SecurityTransportTLS, SecurityTransportSecio)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if minLevel == SecurityLevelModern && transport.level != SecurityLevelModern {
+			log.WithField("securityTransport", name).Debug("disabling security transport below the minimum security level")
+			continue
+		}
+		opts = append(opts, libp2p.Security(transport.id, transport.constructor))
+	}
+	if len(opts) == 0 {
+		return nil, errors.New("no security transports are enabled which meet the minimum security level")
+	}
+	return opts, nil
+}
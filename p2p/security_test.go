@@ -0,0 +1,38 @@
+// +build !js
+
+package p2p
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSecurityOptions(t *testing.T) {
+	opts, err := getSecurityOptions(Config{})
+	require.NoError(t, err)
+	assert.Len(t, opts, len(DefaultSecurityTransports))
+
+	opts, err = getSecurityOptions(Config{SecurityTransports: []string{"tls", " TLS", "secio"}})
+	require.NoError(t, err)
+	assert.Len(t, opts, 2)
+
+	// secio is below the minimum security level.
+	opts, err = getSecurityOptions(Config{MinSecurityLevel: SecurityLevelModern})
+	require.NoError(t, err)
+	assert.Len(t, opts, 1)
+
+	opts, err = getSecurityOptions(Config{Insecure: true, MinSecurityLevel: SecurityLevelModern})
+	require.NoError(t, err)
+	assert.Len(t, opts, 1)
+
+	_, err = getSecurityOptions(Config{SecurityTransports: []string{"secio"}, MinSecurityLevel: SecurityLevelModern})
+	assert.Error(t, err)
+	_, err = getSecurityOptions(Config{SecurityTransports: []string{"tls", "noise"}})
+	assert.Equal(t, errNoiseNotSupported, err)
+	_, err = getSecurityOptions(Config{SecurityTransports: []string{"plaintext"}})
+	assert.Error(t, err)
+	_, err = getSecurityOptions(Config{MinSecurityLevel: "paranoid"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,158 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultMaxPendingValidationMessages is the default value for
+	// Config.MaxPendingValidationMessages.
+	defaultMaxPendingValidationMessages = 20 * maxReceiveBatch
+	// defaultValidationMemoryBudget is the default value for
+	// Config.ValidationMemoryBudget.
+	defaultValidationMemoryBudget = 64 * 1024 * 1024 // 64 MiB.
+)
+
+// ValidationQueueStats contains information about the messages which have been
+// received from peers but not yet handled by the MessageHandler.
+type ValidationQueueStats struct {
+	// NumPending is the number of messages waiting to be handled.
+	NumPending int
+	// NumPendingBytes is the total size of the messages waiting to be handled.
+	NumPendingBytes int
+	// NumDropped is the number of messages that were dropped because the queue
+	// was saturated.
+	NumDropped uint64
+}
+
+// validationQueue is a bounded queue of messages which have been received from
+// peers but not yet handled by the MessageHandler. Both the number of messages
+// and their total size are bounded. When the queue is saturated, new messages
+// are dropped instead of being buffered indefinitely.
+type validationQueue struct {
+	mu          sync.Mutex
+	messages    []*Message
+	numBytes    int
+	maxMessages int
+	maxBytes    int
+	numDropped  uint64
+	// notEmpty receives a value whenever messages are pushed. It is buffered so
+	// that push never blocks.
+	notEmpty chan struct{}
+}
+
+func newValidationQueue(maxMessages int, maxBytes int) *validationQueue {
+	return &validationQueue{
+		maxMessages: maxMessages,
+		maxBytes:    maxBytes,
+		notEmpty:    make(chan struct{}, 1),
+	}
+}
+
+// isSaturated returns true if no more messages can be pushed.
+func (q *validationQueue) isSaturated() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.isSaturatedLocked()
+}
+
+func (q *validationQueue) isSaturatedLocked() bool {
+	return len(q.messages) >= q.maxMessages || q.numBytes >= q.maxBytes
+}
+
+// push adds the message to the end of the queue. If the queue is saturated,
+// the message is dropped and push returns false.
+func (q *validationQueue) push(msg *Message) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.isSaturatedLocked() {
+		atomic.AddUint64(&q.numDropped, 1)
+		return false
+	}
+	q.messages = append(q.messages, msg)
+	q.numBytes += len(msg.Data)
+	select {
+	case q.notEmpty <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// pop removes and returns the message at the front of the queue. It blocks
+// until a message is available or the given context is done, in which case it
+// returns nil, ctx.Err().
+func (q *validationQueue) pop(ctx context.Context) (*Message, error) {
+	for {
+		q.mu.Lock()
+		if len(q.messages) > 0 {
+			msg := q.messages[0]
+			q.messages[0] = nil
+			q.messages = q.messages[1:]
+			q.numBytes -= len(msg.Data)
+			q.mu.Unlock()
+			return msg, nil
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-q.notEmpty:
+		}
+	}
+}
+
+func (q *validationQueue) stats() ValidationQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return ValidationQueueStats{
+		NumPending:      len(q.messages),
+		NumPendingBytes: q.numBytes,
+		NumDropped:      atomic.LoadUint64(&q.numDropped),
+	}
+}
+
+// newNoForwardingValidator returns a GossipSub validator which pushes messages
+// from other peers onto the queue itself and then ignores them, so that they
+// are handled by the MessageHandler but neither delivered via the
+// subscription nor forwarded to other peers. It must be the last validator.
+func newNoForwardingValidator(myPeerID peer.ID, queue *validationQueue) pubsub.Validator {
+	return func(ctx context.Context, sender peer.ID, msg *pubsub.Message) bool {
+		if msg.GetFrom() == myPeerID {
+			return true
+		}
+		if !queue.push(&Message{From: msg.GetFrom(), Data: msg.Data, ReceivedAt: time.Now()}) {
+			log.WithField("from", msg.GetFrom().String()).Trace("dropping message because validation queue is saturated")
+		}
+		return false
+	}
+}
+
+// newBackpressureValidator returns a GossipSub validator which applies
+// backpressure when the queue is saturated: messages from other peers are
+// neither delivered nor forwarded until the backlog has been handled.
+//
+// Note that the version of GossipSub we use does not distinguish between
+// rejecting and ignoring messages and does not penalize peers for invalid
+// messages, so returning false here is equivalent to ignoring the message.
+func newBackpressureValidator(myPeerID peer.ID, queue *validationQueue) pubsub.Validator {
+	return func(ctx context.Context, sender peer.ID, msg *pubsub.Message) bool {
+		if msg.GetFrom() == myPeerID {
+			// Always allow our own messages.
+			return true
+		}
+		if queue.isSaturated() {
+			atomic.AddUint64(&queue.numDropped, 1)
+			log.WithField("from", msg.GetFrom().String()).Trace("ignoring message because validation queue is saturated")
+			return false
+		}
+		return true
+	}
+}
@@ -0,0 +1,63 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationQueueMaxMessages(t *testing.T) {
+	queue := newValidationQueue(2, 1024)
+	assert.True(t, queue.push(&Message{Data: []byte("a")}))
+	assert.False(t, queue.isSaturated())
+	assert.True(t, queue.push(&Message{Data: []byte("b")}))
+	assert.True(t, queue.isSaturated())
+	assert.False(t, queue.push(&Message{Data: []byte("c")}))
+	assert.Equal(t, ValidationQueueStats{NumPending: 2, NumPendingBytes: 2, NumDropped: 1}, queue.stats())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	msg, err := queue.pop(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("a"), msg.Data)
+	assert.False(t, queue.isSaturated())
+	msg, err = queue.pop(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("b"), msg.Data)
+	assert.Equal(t, ValidationQueueStats{NumPending: 0, NumPendingBytes: 0, NumDropped: 1}, queue.stats())
+}
+
+func TestValidationQueueMemoryBudget(t *testing.T) {
+	queue := newValidationQueue(100, 10)
+	assert.True(t, queue.push(&Message{Data: make([]byte, 6)}))
+	assert.False(t, queue.isSaturated())
+	// The budget is only checked before pushing, so a single message may
+	// exceed it.
+	assert.True(t, queue.push(&Message{Data: make([]byte, 6)}))
+	assert.True(t, queue.isSaturated())
+	assert.False(t, queue.push(&Message{Data: make([]byte, 1)}))
+	assert.Equal(t, 12, queue.stats().NumPendingBytes)
+}
+
+func TestValidationQueuePopBlocksUntilPush(t *testing.T) {
+	queue := newValidationQueue(10, 1024)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		queue.push(&Message{Data: []byte("a")})
+	}()
+	msg, err := queue.pop(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("a"), msg.Data)
+
+	// pop returns an error once the context is done.
+	shortCtx, shortCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer shortCancel()
+	_, err = queue.pop(shortCtx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
@@ -10,9 +10,13 @@ export { SupportedProvider } from 'ethereum-types';
 
 import {
     AcceptedOrderInfo,
+    BlockProcessingStats,
+    ChainStats,
+    CleanupStats,
     Config,
     ContractAddresses,
     ContractEvent,
+    CustomEvent,
     ERC1155ApprovalForAllEvent,
     ERC1155TransferBatchEvent,
     ERC1155TransferSingleEvent,
@@ -21,20 +25,36 @@ import {
     ERC721ApprovalEvent,
     ERC721ApprovalForAllEvent,
     ERC721TransferEvent,
+    ExchangeAssetProxyRegisteredEvent,
     ExchangeCancelEvent,
     ExchangeCancelUpToEvent,
     ExchangeFillEvent,
+    ExchangeProtocolFeeCollectorAddressEvent,
+    ExchangeProtocolFeeMultiplierEvent,
+    ExchangeSignatureValidatorApprovalEvent,
+    ExchangeTransactionExecutionEvent,
     GetOrdersResponse,
+    IngestionLatencyStats,
     JsonSchema,
+    LatencyStats,
     LatestBlock,
     MeshWrapper,
     OrderEvent,
     OrderEventEndState,
     OrderInfo,
+    RateLimitStats,
+    ReachabilityStats,
+    RejectedOrderCategory,
     RejectedOrderInfo,
     RejectedOrderKind,
     RejectedOrderStatus,
+    StartupPhase,
+    StartupProgress,
+    StartupRevalidationStats,
     Stats,
+    StorageBackend,
+    SubscriptionStats,
+    SupersededOrderEvent,
     ValidationResults,
     Verbosity,
     WethDepositEvent,
@@ -53,9 +73,13 @@ import {
 
 export {
     AcceptedOrderInfo,
+    BlockProcessingStats,
+    ChainStats,
+    CleanupStats,
     Config,
     ContractAddresses,
     ContractEvent,
+    CustomEvent,
     ERC1155ApprovalForAllEvent,
     ERC1155TransferSingleEvent,
     ERC1155TransferBatchEvent,
@@ -64,19 +88,34 @@ export {
     ERC721ApprovalEvent,
     ERC721ApprovalForAllEvent,
     ERC721TransferEvent,
+    ExchangeAssetProxyRegisteredEvent,
     ExchangeCancelEvent,
     ExchangeCancelUpToEvent,
     ExchangeFillEvent,
+    ExchangeProtocolFeeCollectorAddressEvent,
+    ExchangeProtocolFeeMultiplierEvent,
+    ExchangeSignatureValidatorApprovalEvent,
+    ExchangeTransactionExecutionEvent,
     GetOrdersResponse,
+    IngestionLatencyStats,
+    LatencyStats,
     LatestBlock,
     JsonSchema,
     OrderEvent,
     OrderEventEndState,
     OrderInfo,
+    RateLimitStats,
+    ReachabilityStats,
+    RejectedOrderCategory,
     RejectedOrderInfo,
     RejectedOrderKind,
     RejectedOrderStatus,
+    StartupPhase,
+    StartupProgress,
+    StartupRevalidationStats,
     Stats,
+    StorageBackend,
+    SubscriptionStats,
     ValidationResults,
     Verbosity,
     WethDepositEvent,
@@ -100,23 +139,34 @@ declare global {
 // initialize BrowserFS.
 (window as any).willLoadBrowserFS = true;
 
-BrowserFS.configure(
-    {
-        fs: 'IndexedDB',
-        options: {
-            storeName: '0x-mesh-db',
+// BrowserFS is only configured once a Mesh node which persists its data is
+// started, so that IndexedDB isn't touched by nodes which use in-memory
+// storage.
+let isBrowserFSConfigured = false;
+
+function configureBrowserFS(): void {
+    if (isBrowserFSConfigured) {
+        return;
+    }
+    isBrowserFSConfigured = true;
+    BrowserFS.configure(
+        {
+            fs: 'IndexedDB',
+            options: {
+                storeName: '0x-mesh-db',
+            },
         },
-    },
-    e => {
-        if (e) {
-            throw e;
-        }
-        // We use the global browserFS variable as a handle for Go/Wasm code to
-        // call into the BrowserFS API. Setting this variable also indicates
-        // that BrowserFS has finished loading.
-        (window as any).browserFS = BrowserFS.BFSRequire('fs');
-    },
-);
+        e => {
+            if (e) {
+                throw e;
+            }
+            // We use the global browserFS variable as a handle for Go/Wasm code to
+            // call into the BrowserFS API. Setting this variable also indicates
+            // that BrowserFS has finished loading.
+            (window as any).browserFS = BrowserFS.BFSRequire('fs');
+        },
+    );
+}
 
 // The interval (in milliseconds) to check whether Wasm is done loading.
 const wasmLoadCheckIntervalMs = 100;
@@ -140,6 +190,7 @@ export class Mesh {
     private _wrapper?: MeshWrapper;
     private _errHandler?: (err: Error) => void;
     private _orderEventsHandler?: (events: WrapperOrderEvent[]) => void;
+    private _startupProgressHandler?: (progress: StartupProgress) => void;
 
     /**
      * Instantiates a new Mesh instance.
@@ -181,12 +232,34 @@ export class Mesh {
         }
     }
 
+    /**
+     * Registers a handler which will be called as Mesh starts up, e.g. in
+     * order to render a loading indicator. Progress is reported from the
+     * moment startAsync is called until Mesh has finished getting orders from
+     * its peers for the first time (StartupPhase.Ready), which can be well
+     * after the promise returned by startAsync has resolved. In order to
+     * ensure no progress is missed, this should be called before startAsync.
+     *
+     * @param   handler                The handler to be called.
+     */
+    public onStartupProgress(handler: (progress: StartupProgress) => void): void {
+        this._startupProgressHandler = handler;
+        if (this._wrapper !== undefined) {
+            this._wrapper.onStartupProgress(this._startupProgressHandler);
+        }
+    }
+
     /**
      * Starts the Mesh node in the background. Mesh will automatically find
      * peers in the network and begin receiving orders from them.
      */
     public async startAsync(): Promise<void> {
+        this._reportStartupProgress({ phase: StartupPhase.LoadingWasm, percent: 0 });
         await waitForLoadAsync();
+        this._reportStartupProgress({ phase: StartupPhase.Initializing, percent: 10 });
+        if (this._config.storageBackend !== StorageBackend.Memory) {
+            configureBrowserFS();
+        }
         this._wrapper = await zeroExMesh.newWrapperAsync(configToWrapperConfig(this._config));
         if (this._orderEventsHandler !== undefined) {
             this._wrapper.onOrderEvents(this._orderEventsHandler);
@@ -194,6 +267,9 @@ export class Mesh {
         if (this._errHandler !== undefined) {
             this._wrapper.onError(this._errHandler);
         }
+        if (this._startupProgressHandler !== undefined) {
+            this._wrapper.onStartupProgress(this._startupProgressHandler);
+        }
         return this._wrapper.startAsync();
     }
 
@@ -301,6 +377,12 @@ export class Mesh {
         const meshResults = await this._wrapper.addOrdersAsync(meshOrders, pinned);
         return wrapperValidationResultsToValidationResults(meshResults);
     }
+
+    private _reportStartupProgress(progress: StartupProgress): void {
+        if (this._startupProgressHandler !== undefined) {
+            this._startupProgressHandler(progress);
+        }
+    }
 }
 
 async function waitForLoadAsync(): Promise<void> {
@@ -0,0 +1,143 @@
+import { Order, SignedOrder } from '@0x/order-utils';
+
+import { WrapperSignedOrder } from './types';
+import './wasm_exec';
+import { signedOrderToWrapperSignedOrder } from './wrapper_conversion';
+
+/**
+ * The type of signature that is produced by `signOrderAsync`.
+ */
+export enum OrderSignatureType {
+    EIP712 = 'EIP712',
+    EthSign = 'EthSign',
+}
+
+/**
+ * Signs an order hash and returns the hex-encoded ECDSA signature in the
+ * [R || S || V] format, e.g. by asking a wallet to sign it. For EthSign
+ * signatures the hash must be signed with the Ethereum message prefix (as
+ * `eth_sign` does); for EIP712 signatures the hash must be signed as is.
+ */
+export type OrderHashSigner = (orderHash: string) => Promise<string>;
+
+/**
+ * The result of validating a signature without making any calls to Ethereum.
+ * Signatures of types other than EIP712 and EthSign (e.g. Wallet or Validator
+ * signatures) can only be validated by a Mesh node or the Exchange contract and
+ * have a status of "requires on-chain validation".
+ */
+export interface SignatureValidationResult {
+    status: 'valid' | 'invalid' | 'requires on-chain validation';
+    reason?: string;
+}
+
+interface ZeroExOrderUtils {
+    computeOrderHashAsync(order: WrapperSignedOrder): Promise<string>;
+    signOrderAsync(order: WrapperSignedOrder, signer: OrderHashSigner, signatureType: string): Promise<string>;
+    validateSignatureAsync(signedOrder: WrapperSignedOrder): Promise<SignatureValidationResult>;
+    recoverSignerAsync(orderHash: string, signature: string): Promise<string>;
+}
+
+// The Go code sets the zeroExOrderUtils global and this is our only way of
+// interacting with it.
+declare global {
+    // Defined in ../../browser/go/order-utils/main.go
+    const zeroExOrderUtils: ZeroExOrderUtils;
+}
+
+// The interval (in milliseconds) to check whether Wasm is done loading.
+const wasmLoadCheckIntervalMs = 100;
+
+// We use a global variable to track whether the Wasm code has finished loading.
+let isWasmLoaded = false;
+const loadEventName = '0xorderutilsload';
+window.addEventListener(loadEventName, () => {
+    isWasmLoaded = true;
+});
+
+/**
+ * Loads the order utilities Wasm module by fetching a url. The module can be
+ * used to hash, sign and verify orders without running Mesh.
+ * @param url The URL to query for the order utilities Wasm binary.
+ */
+export async function loadOrderUtilsStreamingWithURLAsync(url: string): Promise<void> {
+    return loadOrderUtilsStreamingAsync(fetch(url));
+}
+
+/**
+ * Loads the order utilities Wasm module that is provided by a response.
+ * @param response The Wasm response that supplies the order utilities Wasm binary.
+ */
+export async function loadOrderUtilsStreamingAsync(response: Response | Promise<Response>): Promise<void> {
+    const go = new Go();
+    const module = await WebAssembly.instantiateStreaming(response, go.importObject);
+    setImmediate(() => {
+        go.run(module.instance);
+    });
+    await waitForLoadAsync();
+}
+
+/**
+ * Computes the EIP712 hash of an order.
+ * @param order The order to hash.
+ * @returns The order hash as a hex string.
+ */
+export async function computeOrderHashAsync(order: Order): Promise<string> {
+    await waitForLoadAsync();
+    return zeroExOrderUtils.computeOrderHashAsync(orderToWrapperSignedOrder(order));
+}
+
+/**
+ * Signs an order. The order hash is computed by the Wasm module and passed to
+ * the given signer, so private keys never have to be handed to the module.
+ * @param order The order to sign.
+ * @param signer Signs the order hash, e.g. using the maker's wallet.
+ * @param signatureType The type of signature to produce.
+ * @returns The signed order.
+ */
+export async function signOrderAsync(
+    order: Order,
+    signer: OrderHashSigner,
+    signatureType: OrderSignatureType = OrderSignatureType.EIP712,
+): Promise<SignedOrder> {
+    await waitForLoadAsync();
+    const signature = await zeroExOrderUtils.signOrderAsync(orderToWrapperSignedOrder(order), signer, signatureType);
+    return { ...order, signature };
+}
+
+/**
+ * Validates the signature of a signed order without making any calls to
+ * Ethereum.
+ * @param signedOrder The signed order to validate.
+ */
+export async function validateSignatureAsync(signedOrder: SignedOrder): Promise<SignatureValidationResult> {
+    await waitForLoadAsync();
+    return zeroExOrderUtils.validateSignatureAsync(signedOrderToWrapperSignedOrder(signedOrder));
+}
+
+/**
+ * Recovers the address which produced an EIP712 or EthSign signature.
+ * @param orderHash The hash of the signed order.
+ * @param signature The hex-encoded signature.
+ * @returns The address of the signer.
+ */
+export async function recoverSignerAsync(orderHash: string, signature: string): Promise<string> {
+    await waitForLoadAsync();
+    return zeroExOrderUtils.recoverSignerAsync(orderHash, signature);
+}
+
+function orderToWrapperSignedOrder(order: Order): WrapperSignedOrder {
+    return signedOrderToWrapperSignedOrder({ ...order, signature: '0x' });
+}
+
+async function waitForLoadAsync(): Promise<void> {
+    // Note: this approach is not CPU efficient but it is only used while the
+    // Wasm is loading.
+    while (!isWasmLoaded) {
+        await sleepAsync(wasmLoadCheckIntervalMs);
+    }
+}
+
+async function sleepAsync(ms: number): Promise<void> {
+    return new Promise<void>(resolve => setTimeout(resolve, ms));
+}
@@ -188,6 +188,18 @@ export interface Config {
     // Offers the ability to use your own web3 provider for all Ethereum RPC
     // requests instead of the default.
     web3Provider?: SupportedProvider;
+    // Where Mesh stores orders and its private key. StorageBackend.IndexedDB
+    // persists them across page loads. StorageBackend.Memory doesn't persist
+    // anything, so the node starts from scratch (with a new peer ID) on every
+    // page load, but it starts faster and doesn't touch IndexedDB, which can
+    // be unavailable or prompt for permission in privacy modes. Defaults to
+    // StorageBackend.IndexedDB.
+    storageBackend?: StorageBackend;
+}
+
+export enum StorageBackend {
+    IndexedDB = 'indexeddb',
+    Memory = 'memory',
 }
 
 export interface ContractAddresses {
@@ -212,6 +224,39 @@ export enum Verbosity {
     Trace = 6,
 }
 
+/**
+ * The phases Mesh goes through while starting up, in the order in which they
+ * occur.
+ */
+export enum StartupPhase {
+    // Waiting for the WebAssembly to be compiled and loaded.
+    LoadingWasm = 'LOADING_WASM',
+    // Initializing the database and loading or generating the private key.
+    Initializing = 'INITIALIZING',
+    ConnectingToEthereum = 'CONNECTING_TO_ETHEREUM',
+    // Catching up with the blocks that were mined while Mesh was offline.
+    SyncingBlocks = 'SYNCING_BLOCKS',
+    // Re-validating all stored orders. Skipped unless Mesh was offline for
+    // too long.
+    RevalidatingOrders = 'REVALIDATING_ORDERS',
+    StartingP2P = 'STARTING_P2P',
+    // Looking for the first peer.
+    DiscoveringPeers = 'DISCOVERING_PEERS',
+    // Getting the orders of peers for the first time.
+    SyncingOrders = 'SYNCING_ORDERS',
+    Ready = 'READY',
+}
+
+/**
+ * Reported to the handler registered with Mesh.onStartupProgress as Mesh
+ * starts up.
+ */
+export interface StartupProgress {
+    phase: StartupPhase;
+    // An estimate of the overall startup progress between 0 and 100.
+    percent: number;
+}
+
 /**
  * The global entrypoint for creating a new MeshWrapper.
  * @ignore
@@ -230,6 +275,7 @@ export interface MeshWrapper {
     startAsync(): Promise<void>;
     onError(handler: (err: Error) => void): void;
     onOrderEvents(handler: (events: WrapperOrderEvent[]) => void): void;
+    onStartupProgress(handler: (progress: StartupProgress) => void): void;
     getStatsAsync(): Promise<WrapperStats>;
     getOrdersForPageAsync(page: number, perPage: number, snapshotID?: string): Promise<WrapperGetOrdersResponse>;
     addOrdersAsync(orders: WrapperSignedOrder[], pinned: boolean): Promise<WrapperValidationResults>;
@@ -254,6 +300,7 @@ export interface WrapperConfig {
     maxOrdersInStorage?: number;
     customOrderFilter?: string; // json-encoded string instead of Object
     web3Provider?: ZeroExProvider; // Standardized ZeroExProvider instead the more permissive SupportedProvider interface
+    storageBackend?: string;
 }
 
 /**
@@ -437,6 +484,42 @@ export interface WrapperExchangeCancelUpToEvent {
     orderEpoch: string;
 }
 
+export interface ExchangeTransactionExecutionEvent {
+    transactionHash: string;
+}
+
+export interface ExchangeSignatureValidatorApprovalEvent {
+    signerAddress: string;
+    validatorAddress: string;
+    isApproved: boolean;
+}
+
+export interface ExchangeAssetProxyRegisteredEvent {
+    id: string;
+    assetProxy: string;
+}
+
+export interface ExchangeProtocolFeeMultiplierEvent {
+    oldProtocolFeeMultiplier: BigNumber;
+    updatedProtocolFeeMultiplier: BigNumber;
+}
+
+/** @ignore */
+export interface WrapperExchangeProtocolFeeMultiplierEvent {
+    oldProtocolFeeMultiplier: string;
+    updatedProtocolFeeMultiplier: string;
+}
+
+export interface ExchangeProtocolFeeCollectorAddressEvent {
+    oldProtocolFeeCollector: string;
+    updatedProtocolFeeCollector: string;
+}
+
+export interface CustomEvent {
+    name: string;
+    parameters: { [name: string]: any };
+}
+
 export interface WethWithdrawalEvent {
     owner: string;
     value: BigNumber;
@@ -471,6 +554,12 @@ export enum ContractEventKind {
     ExchangeFillEvent = 'ExchangeFillEvent',
     ExchangeCancelEvent = 'ExchangeCancelEvent',
     ExchangeCancelUpToEvent = 'ExchangeCancelUpToEvent',
+    ExchangeTransactionExecutionEvent = 'ExchangeTransactionExecutionEvent',
+    ExchangeSignatureValidatorApprovalEvent = 'ExchangeSignatureValidatorApprovalEvent',
+    ExchangeAssetProxyRegisteredEvent = 'ExchangeAssetProxyRegisteredEvent',
+    ExchangeProtocolFeeMultiplierEvent = 'ExchangeProtocolFeeMultiplierEvent',
+    ExchangeProtocolFeeCollectorAddressEvent = 'ExchangeProtocolFeeCollectorAddressEvent',
+    CustomEvent = 'CustomEvent',
     WethDepositEvent = 'WethDepositEvent',
     WethWithdrawalEvent = 'WethWithdrawalEvent',
 }
@@ -489,7 +578,13 @@ export type WrapperContractEventParameters =
     | ExchangeCancelEvent
     | WrapperERC1155TransferSingleEvent
     | WrapperERC1155TransferBatchEvent
-    | ERC1155ApprovalForAllEvent;
+    | ERC1155ApprovalForAllEvent
+    | ExchangeTransactionExecutionEvent
+    | ExchangeSignatureValidatorApprovalEvent
+    | ExchangeAssetProxyRegisteredEvent
+    | WrapperExchangeProtocolFeeMultiplierEvent
+    | ExchangeProtocolFeeCollectorAddressEvent
+    | CustomEvent;
 
 /** @ignore */
 export type ContractEventParameters =
@@ -505,7 +600,13 @@ export type ContractEventParameters =
     | ExchangeCancelEvent
     | ERC1155TransferSingleEvent
     | ERC1155TransferBatchEvent
-    | ERC1155ApprovalForAllEvent;
+    | ERC1155ApprovalForAllEvent
+    | ExchangeTransactionExecutionEvent
+    | ExchangeSignatureValidatorApprovalEvent
+    | ExchangeAssetProxyRegisteredEvent
+    | ExchangeProtocolFeeMultiplierEvent
+    | ExchangeProtocolFeeCollectorAddressEvent
+    | CustomEvent;
 
 export interface ContractEvent {
     blockHash: string;
@@ -544,6 +645,8 @@ export enum OrderEventEndState {
     Unfunded = 'UNFUNDED',
     FillabilityIncreased = 'FILLABILITY_INCREASED',
     StoppedWatching = 'STOPPED_WATCHING',
+    Replaced = 'REPLACED',
+    EventsDropped = 'EVENTS_DROPPED',
 }
 
 /** @ignore */
@@ -554,6 +657,19 @@ export interface WrapperOrderEvent {
     endState: OrderEventEndState;
     fillableTakerAssetAmount: string;
     contractEvents: WrapperContractEvent[];
+    supersedes: WrapperSupersededOrderEvent[];
+    blockNumber?: number;
+    blockHash?: string;
+    blockTimestamp?: string;
+    sequenceNumber?: number;
+}
+
+/** @ignore */
+export interface WrapperSupersededOrderEvent {
+    blockHash: string;
+    timestamp: string;
+    endState: OrderEventEndState;
+    fillableTakerAssetAmount: string;
 }
 
 /**
@@ -567,6 +683,29 @@ export interface OrderEvent {
     endState: OrderEventEndState;
     fillableTakerAssetAmount: BigNumber;
     contractEvents: ContractEvent[];
+    supersedes: SupersededOrderEvent[];
+    // The latest block Mesh had processed when the order event was generated,
+    // i.e. the block at which the new state of the order was observed. These
+    // are undefined if Mesh had not processed any blocks yet.
+    blockNumber?: number;
+    blockHash?: string;
+    blockTimestampMs?: number;
+    // Sequence numbers increase by one for each order event emitted over the
+    // lifetime of the node's database. Gaps indicate missed order events.
+    sequenceNumber?: number;
+}
+
+/**
+ * A superseded order event is an earlier order event for the same order which
+ * was caused by a block that has since been removed by a block re-org.
+ * Order events which correct the state of an order after a re-org reference
+ * the order events they supersede so that they can be unwound precisely.
+ */
+export interface SupersededOrderEvent {
+    blockHash: string;
+    timestampMs: number;
+    endState: OrderEventEndState;
+    fillableTakerAssetAmount: BigNumber;
 }
 
 /** @ignore */
@@ -635,9 +774,23 @@ export enum RejectedOrderKind {
  */
 export interface RejectedOrderStatus {
     code: string;
+    numericCode: number;
+    category: RejectedOrderCategory;
     message: string;
 }
 
+/**
+ * Groups the reasons for an order's rejection by how they should be handled.
+ * Numeric codes 1xx are validation errors, 2xx are Mesh policy errors, 3xx are
+ * network errors and 9xx are internal errors.
+ */
+export enum RejectedOrderCategory {
+    Validation = 'VALIDATION',
+    MeshPolicy = 'MESH_POLICY',
+    Network = 'NETWORK',
+    Internal = 'INTERNAL',
+}
+
 export interface LatestBlock {
     number: number;
     hash: string;
@@ -651,8 +804,10 @@ export interface WrapperStats {
     secondaryRendezvous: string[];
     peerID: string;
     ethereumChainID: number;
+    relayOnly: boolean;
     latestBlock: LatestBlock;
     numPeers: number;
+    peerLatencies: { [peerID: string]: number }; // nanoseconds
     numOrders: number;
     numOrdersIncludingRemoved: number;
     numPinnedOrders: number;
@@ -660,6 +815,46 @@ export interface WrapperStats {
     startOfCurrentUTCDay: string; // string instead of Date
     ethRPCRequestsSentInCurrentUTCDay: number;
     ethRPCRateLimitExpiredRequests: number;
+    numPendingValidation: number;
+    rateLimiting: RateLimitStats;
+    signatureCacheHitRate: number;
+    lastCleanup: WrapperCleanupStats;
+    startupRevalidation: WrapperStartupRevalidationStats;
+    reachability: ReachabilityStats;
+    subscriptions: SubscriptionStats[];
+    chain: ChainStats;
+    ingestionLatency: IngestionLatencyStats;
+    shadowFilter?: WrapperShadowFilterStats;
+}
+
+/** @ignore */
+export interface WrapperShadowFilterStats {
+    pubSubTopic: string;
+    since: string; // string instead of Date
+    numEvaluated: number;
+    numAcceptedByBoth: number;
+    numAcceptedOnlyByShadow: number;
+    numRejectedOnlyByShadow: number;
+    numRejectedByBoth: number;
+}
+
+/** @ignore */
+export interface WrapperCleanupStats {
+    startTime: string; // string instead of Date
+    duration: number;
+    numOrdersChecked: number;
+    numOrdersDeferred: number;
+    numOrderEvents: number;
+    error?: string;
+}
+
+/** @ignore */
+export interface WrapperStartupRevalidationStats {
+    startTime: string; // string instead of Date
+    endTime: string; // string instead of Date
+    numOrdersTotal: number;
+    numOrdersRevalidated: number;
+    error?: string;
 }
 
 export interface Stats {
@@ -669,8 +864,10 @@ export interface Stats {
     secondaryRendezvous: string[];
     peerID: string;
     ethereumChainID: number;
+    relayOnly: boolean;
     latestBlock: LatestBlock;
     numPeers: number;
+    peerLatencies: { [peerID: string]: number }; // nanoseconds
     numOrders: number;
     numOrdersIncludingRemoved: number;
     numPinnedOrders: number;
@@ -678,5 +875,95 @@ export interface Stats {
     startOfCurrentUTCDay: Date;
     ethRPCRequestsSentInCurrentUTCDay: number;
     ethRPCRateLimitExpiredRequests: number;
+    numPendingValidation: number;
+    rateLimiting: RateLimitStats;
+    signatureCacheHitRate: number;
+    lastCleanup: CleanupStats;
+    startupRevalidation: StartupRevalidationStats;
+    reachability: ReachabilityStats;
+    subscriptions: SubscriptionStats[];
+    chain: ChainStats;
+    ingestionLatency: IngestionLatencyStats;
+    shadowFilter?: ShadowFilterStats;
+}
+
+export interface ShadowFilterStats {
+    pubSubTopic: string;
+    since: Date;
+    numEvaluated: number;
+    numAcceptedByBoth: number;
+    numAcceptedOnlyByShadow: number;
+    numRejectedOnlyByShadow: number;
+    numRejectedByBoth: number;
+}
+
+export interface RateLimitStats {
+    perPeerLimitViolations: number;
+    globalLimitViolations: number;
+    oversizedMessages: number;
+    numBans: number;
+}
+
+export interface CleanupStats {
+    startTime: Date;
+    duration: number;
+    numOrdersChecked: number;
+    numOrdersDeferred: number;
+    numOrderEvents: number;
+    error?: string;
+}
+
+export interface StartupRevalidationStats {
+    startTime: Date;
+    endTime: Date;
+    numOrdersTotal: number;
+    numOrdersRevalidated: number;
+    error?: string;
+}
+
+export interface ReachabilityStats {
+    natStatus: 'unknown' | 'public' | 'private';
+    confirmedPublicAddrs: string[];
+    advertisedPublicAddrs: string[];
+}
+
+export interface SubscriptionStats {
+    id: number;
+    topic: string;
+    queueDepth: number;
+    queueSize: number;
+    numDropped: number;
+}
+
+export interface ChainStats {
+    numBlocksProcessed: number;
+    numReorgs: number;
+    reorgsByDepth: { [depth: string]: number };
+    numLogsDecoded: { [kind: string]: number };
+    numOrdersRevalidated: number;
+    numValidationCalls: number;
+    lastBlock: BlockProcessingStats;
+}
+
+export interface BlockProcessingStats {
+    number: number;
+    numLogsDecoded: number;
+    numOrdersRevalidated: number;
+    numValidationCalls: number;
+}
+
+export interface IngestionLatencyStats {
+    queue: LatencyStats;
+    decoding: LatencyStats;
+    validation: LatencyStats;
+    emission: LatencyStats;
+    total: LatencyStats;
+}
+
+export interface LatencyStats {
+    count: number;
+    p50: number; // nanoseconds
+    p95: number; // nanoseconds
+    p99: number; // nanoseconds
 }
 // tslint:disable-next-line:max-file-line-count
@@ -23,6 +23,7 @@ import {
     OrderInfo,
     RejectedOrderInfo,
     Stats,
+    SupersededOrderEvent,
     ValidationResults,
     WrapperAcceptedOrderInfo,
     WrapperConfig,
@@ -35,12 +36,14 @@ import {
     WrapperERC721TransferEvent,
     WrapperExchangeCancelUpToEvent,
     WrapperExchangeFillEvent,
+    WrapperExchangeProtocolFeeMultiplierEvent,
     WrapperGetOrdersResponse,
     WrapperOrderEvent,
     WrapperOrderInfo,
     WrapperRejectedOrderInfo,
     WrapperSignedOrder,
     WrapperStats,
+    WrapperSupersededOrderEvent,
     WrapperValidationResults,
     WrapperWethDepositEvent,
     WrapperWethWithdrawalEvent,
@@ -180,6 +183,22 @@ export function wrapperContractEventsToContractEvents(wrapperContractEvents: Wra
                     orderEpoch: new BigNumber(exchangeCancelUpToEvent.orderEpoch),
                 };
                 break;
+            case ContractEventKind.ExchangeTransactionExecutionEvent:
+            case ContractEventKind.ExchangeSignatureValidatorApprovalEvent:
+            case ContractEventKind.ExchangeAssetProxyRegisteredEvent:
+            case ContractEventKind.ExchangeProtocolFeeCollectorAddressEvent:
+            case ContractEventKind.CustomEvent:
+                parameters = rawParameters as ContractEventParameters;
+                break;
+            case ContractEventKind.ExchangeProtocolFeeMultiplierEvent:
+                const exchangeProtocolFeeMultiplierEvent = rawParameters as WrapperExchangeProtocolFeeMultiplierEvent;
+                parameters = {
+                    oldProtocolFeeMultiplier: new BigNumber(exchangeProtocolFeeMultiplierEvent.oldProtocolFeeMultiplier),
+                    updatedProtocolFeeMultiplier: new BigNumber(
+                        exchangeProtocolFeeMultiplierEvent.updatedProtocolFeeMultiplier,
+                    ),
+                };
+                break;
             case ContractEventKind.WethDepositEvent:
                 const wethDepositEvent = rawParameters as WrapperWethDepositEvent;
                 parameters = {
@@ -225,15 +244,29 @@ export function signedOrderToWrapperSignedOrder(signedOrder: SignedOrder): Wrapp
 }
 
 export function wrapperOrderEventToOrderEvent(wrapperOrderEvent: WrapperOrderEvent): OrderEvent {
+    const { blockTimestamp, ...rest } = wrapperOrderEvent;
     return {
-        ...wrapperOrderEvent,
+        ...rest,
+        blockTimestampMs: blockTimestamp === undefined ? undefined : new Date(blockTimestamp).getTime(),
         timestampMs: new Date(wrapperOrderEvent.timestamp).getTime(),
         signedOrder: wrapperSignedOrderToSignedOrder(wrapperOrderEvent.signedOrder),
         fillableTakerAssetAmount: new BigNumber(wrapperOrderEvent.fillableTakerAssetAmount),
         contractEvents: wrapperContractEventsToContractEvents(wrapperOrderEvent.contractEvents),
+        supersedes: wrapperSupersededOrderEventsToSupersededOrderEvents(wrapperOrderEvent.supersedes),
     };
 }
 
+export function wrapperSupersededOrderEventsToSupersededOrderEvents(
+    wrapperSupersededOrderEvents: WrapperSupersededOrderEvent[],
+): SupersededOrderEvent[] {
+    return wrapperSupersededOrderEvents.map(wrapperSupersededOrderEvent => ({
+        blockHash: wrapperSupersededOrderEvent.blockHash,
+        timestampMs: new Date(wrapperSupersededOrderEvent.timestamp).getTime(),
+        endState: wrapperSupersededOrderEvent.endState,
+        fillableTakerAssetAmount: new BigNumber(wrapperSupersededOrderEvent.fillableTakerAssetAmount),
+    }));
+}
+
 export function orderEventsHandlerToWrapperOrderEventsHandler(
     orderEventsHandler: (events: OrderEvent[]) => void,
 ): (events: WrapperOrderEvent[]) => void {
@@ -276,6 +309,22 @@ export function wrapperStatsToStats(wrapperStats: WrapperStats): Stats {
         ...wrapperStats,
         startOfCurrentUTCDay: new Date(wrapperStats.startOfCurrentUTCDay),
         maxExpirationTime: new BigNumber(wrapperStats.maxExpirationTime),
+        lastCleanup: {
+            ...wrapperStats.lastCleanup,
+            startTime: new Date(wrapperStats.lastCleanup.startTime),
+        },
+        startupRevalidation: {
+            ...wrapperStats.startupRevalidation,
+            startTime: new Date(wrapperStats.startupRevalidation.startTime),
+            endTime: new Date(wrapperStats.startupRevalidation.endTime),
+        },
+        shadowFilter:
+            wrapperStats.shadowFilter === undefined
+                ? undefined
+                : {
+                      ...wrapperStats.shadowFilter,
+                      since: new Date(wrapperStats.shadowFilter.since),
+                  },
     };
 }
 
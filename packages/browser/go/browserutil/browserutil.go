@@ -7,6 +7,7 @@ package browserutil
 
 import (
 	"errors"
+	"fmt"
 	"syscall/js"
 	"time"
 
@@ -16,6 +17,12 @@ import (
 	"github.com/0xProject/0x-mesh/packages/browser/go/providerwrapper"
 )
 
+// Values of the storageBackend config option.
+const (
+	storageBackendIndexedDB = "indexeddb"
+	storageBackendMemory    = "memory"
+)
+
 // ConvertConfig converts a JavaScript config object into a core.Config. It also
 // adds default values for any that are missing in the JavaScript object.
 func ConvertConfig(jsConfig js.Value) (core.Config, error) {
@@ -86,6 +93,15 @@ func ConvertConfig(jsConfig js.Value) (core.Config, error) {
 	if web3Provider := jsConfig.Get("web3Provider"); !jsutil.IsNullOrUndefined(web3Provider) {
 		config.EthereumRPCClient = providerwrapper.NewRPCClient(web3Provider)
 	}
+	if storageBackend := jsConfig.Get("storageBackend"); !jsutil.IsNullOrUndefined(storageBackend) {
+		switch storageBackend.String() {
+		case storageBackendIndexedDB:
+		case storageBackendMemory:
+			config.InMemoryStorage = true
+		default:
+			return core.Config{}, fmt.Errorf("invalid storageBackend: %q (must be one of %q or %q)", storageBackend.String(), storageBackendIndexedDB, storageBackendMemory)
+		}
+	}
 
 	return config, nil
 }
@@ -7,6 +7,7 @@ package jsutil
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"syscall/js"
 )
@@ -76,3 +77,42 @@ func InefficientlyConvertFromJS(jsValue js.Value, value interface{}) (err error)
 	jsonString := js.Global().Get("JSON").Call("stringify", jsValue)
 	return json.Unmarshal([]byte(jsonString.String()), value)
 }
+
+// AwaitPromise blocks until the given JavaScript Promise settles. It returns
+// the value the promise resolved with, or an error if it was rejected. Values
+// which are not promises are returned as is. AwaitPromise must not be called on
+// the JavaScript event loop (e.g. directly inside a js.Func), since the promise
+// can't settle while the event loop is blocked. It is safe to call inside a
+// function passed to WrapInPromise.
+func AwaitPromise(promise js.Value) (js.Value, error) {
+	if promise.Type() != js.TypeObject || promise.Get("then").Type() != js.TypeFunction {
+		return promise, nil
+	}
+	resultChan := make(chan js.Value, 1)
+	errChan := make(chan error, 1)
+	onFulfilled := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) == 0 {
+			resultChan <- js.Undefined()
+		} else {
+			resultChan <- args[0]
+		}
+		return nil
+	})
+	defer onFulfilled.Release()
+	onRejected := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) == 0 {
+			errChan <- errors.New("promise was rejected")
+		} else {
+			errChan <- js.Error{Value: args[0]}
+		}
+		return nil
+	})
+	defer onRejected.Release()
+	promise.Call("then", onFulfilled, onRejected)
+	select {
+	case result := <-resultChan:
+		return result, nil
+	case err := <-errChan:
+		return js.Undefined(), err
+	}
+}
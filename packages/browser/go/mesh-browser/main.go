@@ -8,6 +8,7 @@ import (
 	"syscall/js"
 	"time"
 
+	"github.com/0xProject/0x-mesh/common/types"
 	"github.com/0xProject/0x-mesh/core"
 	"github.com/0xProject/0x-mesh/packages/browser/go/browserutil"
 	"github.com/0xProject/0x-mesh/packages/browser/go/jsutil"
@@ -22,6 +23,9 @@ const (
 	// orderEventsBufferSize is the buffer size for the orderEvents channel. If
 	// the buffer is full, any additional events won't be processed.
 	orderEventsBufferSize = 100
+	// startupProgressBufferSize is the buffer size for the startupProgress
+	// channel.
+	startupProgressBufferSize = 10
 )
 
 func main() {
@@ -70,6 +74,7 @@ type MeshWrapper struct {
 	orderEvents             chan []*zeroex.OrderEvent
 	orderEventsSubscription event.Subscription
 	orderEventsHandler      js.Value
+	startupProgressHandler  js.Value
 }
 
 // NewMeshWrapper creates a new wrapper from the given config.
@@ -94,6 +99,30 @@ func (cw *MeshWrapper) Start() error {
 	cw.orderEventsSubscription = cw.app.SubscribeToOrderEvents(cw.orderEvents)
 	cw.errChan = make(chan error, 1)
 
+	// Startup progress is reported while cw.app.Start is running, so we need
+	// to start listening for it before starting the app.
+	startupProgress := make(chan core.StartupProgress, startupProgressBufferSize)
+	startupProgressSubscription := cw.app.SubscribeToStartupProgress(startupProgress)
+	go func() {
+		defer startupProgressSubscription.Unsubscribe()
+		for {
+			select {
+			case <-cw.ctx.Done():
+				return
+			case progress := <-startupProgress:
+				if !jsutil.IsNullOrUndefined(cw.startupProgressHandler) {
+					cw.startupProgressHandler.Invoke(map[string]interface{}{
+						"phase":   string(progress.Phase),
+						"percent": progress.Percent,
+					})
+				}
+				if progress.Phase == core.StartupPhaseReady {
+					return
+				}
+			}
+		}
+	}()
+
 	// cw.app.Start blocks until there is an error or the app is closed, so we
 	// need to start it in a goroutine.
 	go func() {
@@ -145,7 +174,7 @@ func (cw *MeshWrapper) AddOrders(rawOrders js.Value, pinned bool) (js.Value, err
 	if err := jsutil.InefficientlyConvertFromJS(rawOrders, &rawMessages); err != nil {
 		return js.Undefined(), err
 	}
-	results, err := cw.app.AddOrders(cw.ctx, rawMessages, pinned)
+	results, err := cw.app.AddOrders(cw.ctx, rawMessages, types.AddOrdersOpts{Pinned: pinned})
 	if err != nil {
 		return js.Undefined(), err
 	}
@@ -192,6 +221,12 @@ func (cw *MeshWrapper) JSValue() js.Value {
 			cw.errHandler = handler
 			return nil
 		}),
+		// onStartupProgress(handler: (progress: StartupProgress) => void): void;
+		"onStartupProgress": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			handler := args[0]
+			cw.startupProgressHandler = handler
+			return nil
+		}),
 		// onOrderEvents(handler: (events: Array<OrderEvent>) => void): void;
 		"onOrderEvents": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 			handler := args[0]
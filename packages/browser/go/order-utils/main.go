@@ -0,0 +1,200 @@
+// +build js,wasm
+
+// Command order-utils is a small WebAssembly module which exposes order
+// hashing, signing and signature verification without the rest of Mesh, so it
+// can be loaded by web applications which need to create or verify Mesh orders
+// but don't run a Mesh node. It only depends on the zeroex/orderhash package,
+// which doesn't import the contract wrappers or the event decoder. Orders are
+// signed through a callback (e.g. backed by a wallet), so private keys never
+// enter the module.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"syscall/js"
+
+	"github.com/0xProject/0x-mesh/packages/browser/go/jsutil"
+	"github.com/0xProject/0x-mesh/zeroex/orderhash"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// loadEventName is the name of a global event that will be fired after the
+// WebAssembly is done loading.
+const loadEventName = "0xorderutilsload"
+
+var signatureTypes = map[string]orderhash.SignatureType{
+	"EIP712":  orderhash.EIP712Signature,
+	"EthSign": orderhash.EthSignSignature,
+}
+
+func main() {
+	setGlobals()
+	triggerLoadEvent()
+
+	// In order for callback functions to work, we can't allow main to exit.
+	// Simply use select to block forever.
+	select {}
+}
+
+// setGlobals sets the global identifiers that are needed to use the order
+// utilities from the JavaScript world.
+func setGlobals() {
+	zeroExOrderUtils := map[string]interface{}{
+		// computeOrderHashAsync(order: WrapperSignedOrder): Promise<string>;
+		"computeOrderHashAsync": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return jsutil.WrapInPromise(func() (interface{}, error) {
+				order, _, err := convertOrder(args[0])
+				if err != nil {
+					return nil, err
+				}
+				orderHash, err := orderhash.Compute(order)
+				if err != nil {
+					return nil, err
+				}
+				return orderHash.Hex(), nil
+			})
+		}),
+		// signOrderAsync(order: WrapperSignedOrder, signer: (orderHash: string) => Promise<string>, signatureType: string): Promise<string>;
+		"signOrderAsync": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return jsutil.WrapInPromise(func() (interface{}, error) {
+				order, _, err := convertOrder(args[0])
+				if err != nil {
+					return nil, err
+				}
+				signer := args[1]
+				if signer.Type() != js.TypeFunction {
+					return nil, errors.New("signer must be a function")
+				}
+				signatureType, found := signatureTypes[args[2].String()]
+				if !found {
+					return nil, fmt.Errorf("unsupported signature type: %q", args[2].String())
+				}
+				orderHash, err := orderhash.Compute(order)
+				if err != nil {
+					return nil, err
+				}
+				ecSignature, err := jsutil.AwaitPromise(signer.Invoke(orderHash.Hex()))
+				if err != nil {
+					return nil, err
+				}
+				if ecSignature.Type() != js.TypeString {
+					return nil, errors.New("signer must return a hex-encoded signature")
+				}
+				signature, err := orderhash.FromECSignature(orderHash, order.MakerAddress, common.FromHex(ecSignature.String()), signatureType)
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("0x%s", common.Bytes2Hex(signature)), nil
+			})
+		}),
+		// validateSignatureAsync(signedOrder: WrapperSignedOrder): Promise<WrapperSignatureValidationResult>;
+		"validateSignatureAsync": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return jsutil.WrapInPromise(func() (interface{}, error) {
+				order, signature, err := convertOrder(args[0])
+				if err != nil {
+					return nil, err
+				}
+				orderHash, err := orderhash.Compute(order)
+				if err != nil {
+					return nil, err
+				}
+				status, err := orderhash.ValidateSignatureOffline(orderHash, order.MakerAddress, signature)
+				result := map[string]interface{}{
+					"status": status.String(),
+				}
+				if err != nil {
+					result["reason"] = err.Error()
+				}
+				return result, nil
+			})
+		}),
+		// recoverSignerAsync(orderHash: string, signature: string): Promise<string>;
+		"recoverSignerAsync": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return jsutil.WrapInPromise(func() (interface{}, error) {
+				signer, err := orderhash.RecoverSigner(common.HexToHash(args[0].String()), common.FromHex(args[1].String()))
+				if err != nil {
+					return nil, err
+				}
+				return signer.Hex(), nil
+			})
+		}),
+	}
+	js.Global().Set("zeroExOrderUtils", zeroExOrderUtils)
+}
+
+// wrapperSignedOrder is the format of WrapperSignedOrder in the TypeScript
+// code.
+type wrapperSignedOrder struct {
+	ChainID               int64  `json:"chainId"`
+	ExchangeAddress       string `json:"exchangeAddress"`
+	MakerAddress          string `json:"makerAddress"`
+	MakerAssetData        string `json:"makerAssetData"`
+	MakerFeeAssetData     string `json:"makerFeeAssetData"`
+	MakerAssetAmount      string `json:"makerAssetAmount"`
+	MakerFee              string `json:"makerFee"`
+	TakerAddress          string `json:"takerAddress"`
+	TakerAssetData        string `json:"takerAssetData"`
+	TakerFeeAssetData     string `json:"takerFeeAssetData"`
+	TakerAssetAmount      string `json:"takerAssetAmount"`
+	TakerFee              string `json:"takerFee"`
+	SenderAddress         string `json:"senderAddress"`
+	FeeRecipientAddress   string `json:"feeRecipientAddress"`
+	ExpirationTimeSeconds string `json:"expirationTimeSeconds"`
+	Salt                  string `json:"salt"`
+	Signature             string `json:"signature"`
+}
+
+// convertOrder converts a JavaScript order to an orderhash.Order and its
+// signature. The signature is optional.
+func convertOrder(jsOrder js.Value) (*orderhash.Order, []byte, error) {
+	if jsutil.IsNullOrUndefined(jsOrder) {
+		return nil, nil, errors.New("order is required")
+	}
+	var wrapperOrder wrapperSignedOrder
+	if err := jsutil.InefficientlyConvertFromJS(jsOrder, &wrapperOrder); err != nil {
+		return nil, nil, err
+	}
+	order := &orderhash.Order{
+		ChainID:             big.NewInt(wrapperOrder.ChainID),
+		ExchangeAddress:     common.HexToAddress(wrapperOrder.ExchangeAddress),
+		MakerAddress:        common.HexToAddress(wrapperOrder.MakerAddress),
+		MakerAssetData:      common.FromHex(wrapperOrder.MakerAssetData),
+		MakerFeeAssetData:   common.FromHex(wrapperOrder.MakerFeeAssetData),
+		TakerAddress:        common.HexToAddress(wrapperOrder.TakerAddress),
+		TakerAssetData:      common.FromHex(wrapperOrder.TakerAssetData),
+		TakerFeeAssetData:   common.FromHex(wrapperOrder.TakerFeeAssetData),
+		SenderAddress:       common.HexToAddress(wrapperOrder.SenderAddress),
+		FeeRecipientAddress: common.HexToAddress(wrapperOrder.FeeRecipientAddress),
+	}
+	amounts := []struct {
+		name  string
+		value string
+		dst   **big.Int
+	}{
+		{"makerAssetAmount", wrapperOrder.MakerAssetAmount, &order.MakerAssetAmount},
+		{"makerFee", wrapperOrder.MakerFee, &order.MakerFee},
+		{"takerAssetAmount", wrapperOrder.TakerAssetAmount, &order.TakerAssetAmount},
+		{"takerFee", wrapperOrder.TakerFee, &order.TakerFee},
+		{"expirationTimeSeconds", wrapperOrder.ExpirationTimeSeconds, &order.ExpirationTimeSeconds},
+		{"salt", wrapperOrder.Salt, &order.Salt},
+	}
+	for _, amount := range amounts {
+		value, ok := math.ParseBig256(amount.value)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid %s: %q", amount.name, amount.value)
+		}
+		*amount.dst = value
+	}
+	return order, common.FromHex(wrapperOrder.Signature), nil
+}
+
+// triggerLoadEvent triggers the global load event to indicate that the Wasm is
+// done loading.
+func triggerLoadEvent() {
+	event := js.Global().Get("document").Call("createEvent", "Event")
+	event.Call("initEvent", loadEventName, true, true)
+	js.Global().Call("dispatchEvent", event)
+}
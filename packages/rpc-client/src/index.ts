@@ -6,9 +6,11 @@ export {
     OrderEventPayload,
     OrderEvent,
     OrderInfo,
+    SupersededOrderEvent,
     AcceptedOrderInfo,
     RejectedKind,
     RejectedCode,
+    RejectedCategory,
     RejectedStatus,
     RejectedOrderInfo,
     ValidationResults,
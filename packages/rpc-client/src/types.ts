@@ -193,6 +193,41 @@ export interface StringifiedExchangeCancelUpToEvent {
     orderEpoch: string;
 }
 
+export interface ExchangeTransactionExecutionEvent {
+    transactionHash: string;
+}
+
+export interface ExchangeSignatureValidatorApprovalEvent {
+    signerAddress: string;
+    validatorAddress: string;
+    isApproved: boolean;
+}
+
+export interface ExchangeAssetProxyRegisteredEvent {
+    id: string;
+    assetProxy: string;
+}
+
+export interface ExchangeProtocolFeeMultiplierEvent {
+    oldProtocolFeeMultiplier: BigNumber;
+    updatedProtocolFeeMultiplier: BigNumber;
+}
+
+export interface StringifiedExchangeProtocolFeeMultiplierEvent {
+    oldProtocolFeeMultiplier: string;
+    updatedProtocolFeeMultiplier: string;
+}
+
+export interface ExchangeProtocolFeeCollectorAddressEvent {
+    oldProtocolFeeCollector: string;
+    updatedProtocolFeeCollector: string;
+}
+
+export interface CustomEvent {
+    name: string;
+    parameters: { [name: string]: any };
+}
+
 export interface WethWithdrawalEvent {
     owner: string;
     value: BigNumber;
@@ -225,6 +260,12 @@ export enum ContractEventKind {
     ExchangeFillEvent = 'ExchangeFillEvent',
     ExchangeCancelEvent = 'ExchangeCancelEvent',
     ExchangeCancelUpToEvent = 'ExchangeCancelUpToEvent',
+    ExchangeTransactionExecutionEvent = 'ExchangeTransactionExecutionEvent',
+    ExchangeSignatureValidatorApprovalEvent = 'ExchangeSignatureValidatorApprovalEvent',
+    ExchangeAssetProxyRegisteredEvent = 'ExchangeAssetProxyRegisteredEvent',
+    ExchangeProtocolFeeMultiplierEvent = 'ExchangeProtocolFeeMultiplierEvent',
+    ExchangeProtocolFeeCollectorAddressEvent = 'ExchangeProtocolFeeCollectorAddressEvent',
+    CustomEvent = 'CustomEvent',
     WethDepositEvent = 'WethDepositEvent',
     WethWithdrawalEvent = 'WethWithdrawalEvent',
 }
@@ -242,7 +283,13 @@ export type StringifiedContractEventParameters =
     | ERC1155ApprovalForAllEvent
     | StringifiedERC1155TransferSingleEvent
     | StringifiedERC1155TransferBatchEvent
-    | ExchangeCancelEvent;
+    | ExchangeCancelEvent
+    | ExchangeTransactionExecutionEvent
+    | ExchangeSignatureValidatorApprovalEvent
+    | ExchangeAssetProxyRegisteredEvent
+    | StringifiedExchangeProtocolFeeMultiplierEvent
+    | ExchangeProtocolFeeCollectorAddressEvent
+    | CustomEvent;
 
 export interface StringifiedContractEvent {
     blockHash: string;
@@ -268,7 +315,13 @@ export type ContractEventParameters =
     | ExchangeCancelEvent
     | ERC1155ApprovalForAllEvent
     | ERC1155TransferSingleEvent
-    | ERC1155TransferBatchEvent;
+    | ERC1155TransferBatchEvent
+    | ExchangeTransactionExecutionEvent
+    | ExchangeSignatureValidatorApprovalEvent
+    | ExchangeAssetProxyRegisteredEvent
+    | ExchangeProtocolFeeMultiplierEvent
+    | ExchangeProtocolFeeCollectorAddressEvent
+    | CustomEvent;
 
 export interface ContractEvent {
     blockHash: string;
@@ -292,6 +345,8 @@ export enum OrderEventEndState {
     StoppedWatching = 'STOPPED_WATCHING',
     Unfunded = 'UNFUNDED',
     FillabilityIncreased = 'FILLABILITY_INCREASED',
+    Replaced = 'REPLACED',
+    EventsDropped = 'EVENTS_DROPPED',
 }
 
 export interface OrderEventPayload {
@@ -307,19 +362,49 @@ export interface HeartbeatEventPayload {
 export interface RawOrderEvent {
     timestamp: string;
     orderHash: string;
-    signedOrder: StringifiedSignedOrder;
+    signedOrder: StringifiedSignedOrder | null;
     endState: OrderEventEndState;
     fillableTakerAssetAmount: string;
     contractEvents: StringifiedContractEvent[];
+    supersedes: RawSupersededOrderEvent[];
+    blockNumber?: number;
+    blockHash?: string;
+    blockTimestamp?: string;
+    sequenceNumber?: number;
+}
+
+export interface RawSupersededOrderEvent {
+    blockHash: string;
+    timestamp: string;
+    endState: OrderEventEndState;
+    fillableTakerAssetAmount: string;
 }
 
 export interface OrderEvent {
     timestampMs: number;
+    // The orderHash is the zero hash and the signedOrder is null for
+    // EVENTS_DROPPED order events, which are not emitted for a specific order.
     orderHash: string;
-    signedOrder: SignedOrder;
+    signedOrder: SignedOrder | null;
     endState: OrderEventEndState;
     fillableTakerAssetAmount: BigNumber;
     contractEvents: ContractEvent[];
+    supersedes: SupersededOrderEvent[];
+    // The latest block Mesh had processed when the order event was generated.
+    // These are undefined if Mesh had not processed any blocks yet.
+    blockNumber?: number;
+    blockHash?: string;
+    blockTimestampMs?: number;
+    // Sequence numbers increase by one for each order event emitted over the
+    // lifetime of the node's database. Gaps indicate missed order events.
+    sequenceNumber?: number;
+}
+
+export interface SupersededOrderEvent {
+    blockHash: string;
+    timestampMs: number;
+    endState: OrderEventEndState;
+    fillableTakerAssetAmount: BigNumber;
 }
 
 export interface RawAcceptedOrderInfo {
@@ -357,6 +442,8 @@ export enum RejectedKind {
 export enum RejectedCode {
     InternalError = 'InternalError',
     MaxOrderSizeExceeded = 'MaxOrderSizeExceeded',
+    AssetDataTooLarge = 'AssetDataTooLarge',
+    MultiAssetNestingTooDeep = 'MultiAssetNestingTooDeep',
     OrderAlreadyStored = 'OrderAlreadyStored',
     OrderForIncorrectChain = 'OrderForIncorrectChain',
     NetworkRequestFailed = 'NetworkRequestFailed',
@@ -371,8 +458,17 @@ export enum RejectedCode {
     OrderHasInvalidSignature = 'OrderHasInvalidSignature',
 }
 
+export enum RejectedCategory {
+    Validation = 'VALIDATION',
+    MeshPolicy = 'MESH_POLICY',
+    Network = 'NETWORK',
+    Internal = 'INTERNAL',
+}
+
 export interface RejectedStatus {
     code: RejectedCode;
+    numericCode: number;
+    category: RejectedCategory;
     message: string;
 }
 
@@ -431,8 +527,10 @@ export interface GetStatsResponse {
     rendezvous: string;
     peerID: string;
     ethereumChainID: number;
+    relayOnly: boolean;
     latestBlock: LatestBlock;
     numPeers: number;
+    peerLatencies: { [peerID: string]: number }; // nanoseconds
     numOrders: number;
     numOrdersIncludingRemoved: number;
     numPinnedOrders: number;
@@ -440,4 +538,94 @@ export interface GetStatsResponse {
     startOfCurrentUTCDay: string;
     ethRPCRequestsSentInCurrentUTCDay: number;
     ethRPCRateLimitExpiredRequests: number;
+    numPendingValidation: number;
+    rateLimiting: RateLimitStats;
+    signatureCacheHitRate: number;
+    lastCleanup: CleanupStats;
+    startupRevalidation: StartupRevalidationStats;
+    reachability: ReachabilityStats;
+    subscriptions: SubscriptionStats[];
+    chain: ChainStats;
+    ingestionLatency: IngestionLatencyStats;
+    shadowFilter?: ShadowFilterStats;
+}
+
+export interface ShadowFilterStats {
+    pubSubTopic: string;
+    since: string;
+    numEvaluated: number;
+    numAcceptedByBoth: number;
+    numAcceptedOnlyByShadow: number;
+    numRejectedOnlyByShadow: number;
+    numRejectedByBoth: number;
+}
+
+export interface RateLimitStats {
+    perPeerLimitViolations: number;
+    globalLimitViolations: number;
+    oversizedMessages: number;
+    numBans: number;
+}
+
+export interface CleanupStats {
+    startTime: string;
+    duration: number;
+    numOrdersChecked: number;
+    numOrdersDeferred: number;
+    numOrderEvents: number;
+    error?: string;
+}
+
+export interface StartupRevalidationStats {
+    startTime: string;
+    endTime: string;
+    numOrdersTotal: number;
+    numOrdersRevalidated: number;
+    error?: string;
+}
+
+export interface ReachabilityStats {
+    natStatus: 'unknown' | 'public' | 'private';
+    confirmedPublicAddrs: string[];
+    advertisedPublicAddrs: string[];
+}
+
+export interface SubscriptionStats {
+    id: number;
+    topic: string;
+    queueDepth: number;
+    queueSize: number;
+    numDropped: number;
+}
+
+export interface ChainStats {
+    numBlocksProcessed: number;
+    numReorgs: number;
+    reorgsByDepth: { [depth: string]: number };
+    numLogsDecoded: { [kind: string]: number };
+    numOrdersRevalidated: number;
+    numValidationCalls: number;
+    lastBlock: BlockProcessingStats;
+}
+
+export interface BlockProcessingStats {
+    number: number;
+    numLogsDecoded: number;
+    numOrdersRevalidated: number;
+    numValidationCalls: number;
+}
+
+export interface IngestionLatencyStats {
+    queue: LatencyStats;
+    decoding: LatencyStats;
+    validation: LatencyStats;
+    emission: LatencyStats;
+    total: LatencyStats;
+}
+
+export interface LatencyStats {
+    count: number;
+    p50: number; // nanoseconds
+    p95: number; // nanoseconds
+    p99: number; // nanoseconds
 }
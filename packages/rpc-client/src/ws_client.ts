@@ -23,6 +23,7 @@ import {
     RawGetOrdersResponse,
     RawOrderEvent,
     RawOrderInfo,
+    RawSupersededOrderEvent,
     RawValidationResults,
     RejectedOrderInfo,
     StringifiedContractEvent,
@@ -34,8 +35,10 @@ import {
     StringifiedERC721TransferEvent,
     StringifiedExchangeCancelUpToEvent,
     StringifiedExchangeFillEvent,
+    StringifiedExchangeProtocolFeeMultiplierEvent,
     StringifiedWethDepositEvent,
     StringifiedWethWithdrawalEvent,
+    SupersededOrderEvent,
     ValidationResults,
     WSOpts,
 } from './types';
@@ -116,6 +119,19 @@ export class WSClient {
             ordersInfos: WSClient._convertRawOrderInfos(rawGetOrdersResponse.ordersInfos),
         };
     }
+    private static _convertRawSupersededOrderEvents(
+        rawSupersededOrderEvents: RawSupersededOrderEvent[],
+    ): SupersededOrderEvent[] {
+        if (rawSupersededOrderEvents === null || rawSupersededOrderEvents === undefined) {
+            return [];
+        }
+        return rawSupersededOrderEvents.map(rawSupersededOrderEvent => ({
+            blockHash: rawSupersededOrderEvent.blockHash,
+            timestampMs: new Date(rawSupersededOrderEvent.timestamp).getTime(),
+            endState: rawSupersededOrderEvent.endState,
+            fillableTakerAssetAmount: new BigNumber(rawSupersededOrderEvent.fillableTakerAssetAmount),
+        }));
+    }
     private static _convertStringifiedContractEvents(rawContractEvents: StringifiedContractEvent[]): ContractEvent[] {
         const contractEvents: ContractEvent[] = [];
         if (rawContractEvents === null) {
@@ -219,6 +235,22 @@ export class WSClient {
                         orderEpoch: new BigNumber(exchangeCancelUpToEvent.orderEpoch),
                     };
                     break;
+                case ContractEventKind.ExchangeTransactionExecutionEvent:
+                case ContractEventKind.ExchangeSignatureValidatorApprovalEvent:
+                case ContractEventKind.ExchangeAssetProxyRegisteredEvent:
+                case ContractEventKind.ExchangeProtocolFeeCollectorAddressEvent:
+                case ContractEventKind.CustomEvent:
+                    parameters = rawParameters as ContractEventParameters;
+                    break;
+                case ContractEventKind.ExchangeProtocolFeeMultiplierEvent:
+                    const protocolFeeMultiplierEvent = rawParameters as StringifiedExchangeProtocolFeeMultiplierEvent;
+                    parameters = {
+                        oldProtocolFeeMultiplier: new BigNumber(protocolFeeMultiplierEvent.oldProtocolFeeMultiplier),
+                        updatedProtocolFeeMultiplier: new BigNumber(
+                            protocolFeeMultiplierEvent.updatedProtocolFeeMultiplier,
+                        ),
+                    };
+                    break;
                 case ContractEventKind.WethDepositEvent:
                     const wethDepositEvent = rawParameters as StringifiedWethDepositEvent;
                     parameters = {
@@ -374,10 +406,21 @@ export class WSClient {
                 const orderEvent = {
                     timestampMs: new Date(rawOrderEvent.timestamp).getTime(),
                     orderHash: rawOrderEvent.orderHash,
-                    signedOrder: WSClient._convertOrderStringFieldsToBigNumber(rawOrderEvent.signedOrder),
+                    signedOrder:
+                        rawOrderEvent.signedOrder === null
+                            ? null
+                            : WSClient._convertOrderStringFieldsToBigNumber(rawOrderEvent.signedOrder),
                     endState: rawOrderEvent.endState,
                     fillableTakerAssetAmount: new BigNumber(rawOrderEvent.fillableTakerAssetAmount),
                     contractEvents: WSClient._convertStringifiedContractEvents(rawOrderEvent.contractEvents),
+                    supersedes: WSClient._convertRawSupersededOrderEvents(rawOrderEvent.supersedes),
+                    blockNumber: rawOrderEvent.blockNumber,
+                    blockHash: rawOrderEvent.blockHash,
+                    blockTimestampMs:
+                        rawOrderEvent.blockTimestamp === undefined
+                            ? undefined
+                            : new Date(rawOrderEvent.blockTimestamp).getTime(),
+                    sequenceNumber: rawOrderEvent.sequenceNumber,
                 };
                 orderEvents.push(orderEvent);
             });
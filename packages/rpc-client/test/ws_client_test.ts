@@ -138,6 +138,9 @@ blockchainTests.resets('WSClient', env => {
                     number: 0,
                     hash: '',
                 };
+                // The advertised addresses depend on the public IP address of
+                // the machine running the test.
+                stats.reachability.advertisedPublicAddrs = [];
 
                 const now = new Date(Date.now());
                 const expectedStartOfCurrentUTCDay = `${now.getUTCFullYear()}-${leftPad(
@@ -162,6 +165,26 @@ blockchainTests.resets('WSClient', env => {
                     startOfCurrentUTCDay: expectedStartOfCurrentUTCDay,
                     ethRPCRequestsSentInCurrentUTCDay: 0,
                     ethRPCRateLimitExpiredRequests: 0,
+                    numPendingValidation: 0,
+                    signatureCacheHitRate: 0,
+                    lastCleanup: {
+                        startTime: '0001-01-01T00:00:00Z',
+                        duration: 0,
+                        numOrdersChecked: 0,
+                        numOrdersDeferred: 0,
+                        numOrderEvents: 0,
+                    },
+                    startupRevalidation: {
+                        startTime: '0001-01-01T00:00:00Z',
+                        endTime: '0001-01-01T00:00:00Z',
+                        numOrdersTotal: 0,
+                        numOrdersRevalidated: 0,
+                    },
+                    reachability: {
+                        natStatus: 'unknown',
+                        confirmedPublicAddrs: [],
+                        advertisedPublicAddrs: [],
+                    },
                 };
                 expect(stats).to.be.deep.eq(expectedStats);
             });
@@ -438,6 +461,72 @@ blockchainTests.resets('WSClient', env => {
                 })().catch(done);
             });
         });
+        describe('#subscribeToOrdersAsync', async () => {
+            it('should handle EVENTS_DROPPED order events without a signed order', (done: DoneCallback) => {
+                // tslint:disable-next-line:no-floating-promises
+                (async () => {
+                    const wsServer = await setupServerAsync();
+                    const ordersSubscriptionID = '0xab1a3e8af590364c09d0fa6a12103ada';
+                    wsServer.on('connect', (connection: WebSocket.connection) => {
+                        connection.on('message', async (message: WebSocket.IMessage) => {
+                            const wsMessage = message as WSMessage;
+                            const jsonRpcRequest = JSON.parse(wsMessage.utf8Data);
+                            if (jsonRpcRequest.method !== 'mesh_subscribe') {
+                                return;
+                            }
+                            const isOrdersSubscription = jsonRpcRequest.params[0] === 'orders';
+                            const response = `
+                                {
+                                    "id": "${jsonRpcRequest.id}",
+                                    "jsonrpc": "2.0",
+                                    "result": "${isOrdersSubscription ? ordersSubscriptionID : '0x1'}"
+                                }
+                            `;
+                            connection.sendUTF(response);
+                            if (!isOrdersSubscription) {
+                                return;
+                            }
+                            // tslint:disable-next-line:custom-no-magic-numbers
+                            await sleepAsync(100);
+                            const notification = `
+                                {
+                                    "jsonrpc": "2.0",
+                                    "method": "mesh_subscription",
+                                    "params": {
+                                        "subscription": "${ordersSubscriptionID}",
+                                        "result": [
+                                            {
+                                                "timestamp": "2020-06-01T12:00:00Z",
+                                                "orderHash": "${constants.NULL_BYTES32}",
+                                                "signedOrder": null,
+                                                "endState": "EVENTS_DROPPED",
+                                                "fillableTakerAssetAmount": "0",
+                                                "contractEvents": null,
+                                                "supersedes": []
+                                            }
+                                        ]
+                                    }
+                                }
+                            `;
+                            connection.sendUTF(notification);
+                        });
+                    });
+
+                    const client = new WSClient(`ws://localhost:${SERVER_PORT}`);
+                    await client.subscribeToOrdersAsync((orderEvents: OrderEvent[]) => {
+                        expect(orderEvents.length).to.be.eq(1);
+                        expect(orderEvents[0].endState).to.be.eq(OrderEventEndState.EventsDropped);
+                        expect(orderEvents[0].orderHash).to.be.eq(constants.NULL_BYTES32);
+                        expect(orderEvents[0].signedOrder).to.be.null();
+                        expect(orderEvents[0].fillableTakerAssetAmount).to.be.bignumber.eq(0);
+                        expect(orderEvents[0].contractEvents).to.be.deep.eq([]);
+                        client.destroy();
+                        stopServer();
+                        done();
+                    });
+                })().catch(done);
+            });
+        });
         describe('#destroy', async () => {
             it('should unsubscribe and trigger onClose when close() is called', (done: DoneCallback) => {
                 // tslint:disable-next-line:no-floating-promises
@@ -0,0 +1,59 @@
+// +build !js
+
+package priceoracle
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/karlseguin/ccache"
+)
+
+const (
+	// cacheSize is the maximum number of token prices to cache.
+	cacheSize = 1000
+	// DefaultCacheTTL is the default amount of time that a token price is cached
+	// for.
+	DefaultCacheTTL = 5 * time.Minute
+)
+
+// Cached wraps a PriceOracle and caches the prices it returns.
+type Cached struct {
+	oracle PriceOracle
+	ttl    time.Duration
+	cache  *ccache.Cache
+}
+
+// NewCached returns a PriceOracle which caches the prices returned by oracle
+// for the given amount of time. Tokens whose price is unavailable are cached
+// too, so that they don't result in a request every time.
+func NewCached(oracle PriceOracle, ttl time.Duration) *Cached {
+	return &Cached{
+		oracle: oracle,
+		ttl:    ttl,
+		cache:  ccache.New(ccache.Configure().MaxSize(cacheSize)),
+	}
+}
+
+// PriceUSD returns the cached price of the given token if there is one and
+// otherwise asks the underlying PriceOracle.
+func (c *Cached) PriceUSD(ctx context.Context, token common.Address) (*big.Float, error) {
+	key := token.Hex()
+	if item := c.cache.Get(key); item != nil && !item.Expired() {
+		if price, ok := item.Value().(*big.Float); ok {
+			return new(big.Float).Set(price), nil
+		}
+		return nil, ErrPriceUnavailable
+	}
+	price, err := c.oracle.PriceUSD(ctx, token)
+	if err == ErrPriceUnavailable {
+		c.cache.Set(key, struct{}{}, c.ttl)
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+	c.cache.Set(key, new(big.Float).Set(price), c.ttl)
+	return price, nil
+}
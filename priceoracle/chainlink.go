@@ -0,0 +1,105 @@
+// +build !js
+
+package priceoracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// aggregatorAbi is the subset of the Chainlink aggregator ABI used to look up
+// prices. The answer of a USD aggregator is the price of one whole token.
+const aggregatorAbi = `[{"constant":true,"inputs":[],"name":"latestAnswer","outputs":[{"name":"","type":"int256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// erc20DecimalsAbi is the subset of the ERC20 ABI used to look up the number
+// of decimals of a token.
+const erc20DecimalsAbi = `[{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// Chainlink is a PriceOracle which reads prices from on-chain Chainlink USD
+// price feeds.
+type Chainlink struct {
+	contractCaller bind.ContractCaller
+	feeds          map[common.Address]common.Address
+	aggregatorABI  abi.ABI
+	erc20ABI       abi.ABI
+	// decimalsMu protects decimals, which caches the number of decimals of
+	// tokens and aggregators since they never change.
+	decimalsMu sync.Mutex
+	decimals   map[common.Address]uint8
+}
+
+// NewChainlink returns a Chainlink price oracle which uses the given map of
+// token address to Chainlink USD aggregator address.
+func NewChainlink(contractCaller bind.ContractCaller, feeds map[common.Address]common.Address) (*Chainlink, error) {
+	aggregatorABI, err := abi.JSON(strings.NewReader(aggregatorAbi))
+	if err != nil {
+		return nil, err
+	}
+	erc20ABI, err := abi.JSON(strings.NewReader(erc20DecimalsAbi))
+	if err != nil {
+		return nil, err
+	}
+	return &Chainlink{
+		contractCaller: contractCaller,
+		feeds:          feeds,
+		aggregatorABI:  aggregatorABI,
+		erc20ABI:       erc20ABI,
+		decimals:       map[common.Address]uint8{},
+	}, nil
+}
+
+// PriceUSD returns the USD price of a single base unit of the given token
+// according to its Chainlink aggregator.
+func (c *Chainlink) PriceUSD(ctx context.Context, token common.Address) (*big.Float, error) {
+	aggregatorAddress, found := c.feeds[token]
+	if !found {
+		return nil, ErrPriceUnavailable
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	aggregator := bind.NewBoundContract(aggregatorAddress, c.aggregatorABI, c.contractCaller, nil, nil)
+	answer := new(big.Int)
+	if err := aggregator.Call(opts, &answer, "latestAnswer"); err != nil {
+		return nil, err
+	}
+	if answer.Sign() <= 0 {
+		return nil, errors.New("Chainlink aggregator returned a non-positive answer")
+	}
+	aggregatorDecimals, err := c.getDecimals(opts, aggregatorAddress, c.aggregatorABI)
+	if err != nil {
+		return nil, err
+	}
+	tokenDecimals, err := c.getDecimals(opts, token, c.erc20ABI)
+	if err != nil {
+		return nil, err
+	}
+
+	price := new(big.Float).SetInt(answer)
+	price.Quo(price, pow10(aggregatorDecimals))
+	price.Quo(price, pow10(tokenDecimals))
+	return price, nil
+}
+
+func (c *Chainlink) getDecimals(opts *bind.CallOpts, address common.Address, contractABI abi.ABI) (uint8, error) {
+	c.decimalsMu.Lock()
+	decimals, found := c.decimals[address]
+	c.decimalsMu.Unlock()
+	if found {
+		return decimals, nil
+	}
+
+	contract := bind.NewBoundContract(address, contractABI, c.contractCaller, nil, nil)
+	if err := contract.Call(opts, &decimals, "decimals"); err != nil {
+		return 0, err
+	}
+	c.decimalsMu.Lock()
+	c.decimals[address] = decimals
+	c.decimalsMu.Unlock()
+	return decimals, nil
+}
@@ -0,0 +1,89 @@
+// +build !js
+
+package priceoracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// httpRequestTimeout is the maximum amount of time to wait for a response from
+// an HTTP price oracle.
+const httpRequestTimeout = 10 * time.Second
+
+// HTTP is a PriceOracle which fetches prices from an external HTTP endpoint.
+// For each token, it sends a GET request to the endpoint with a "token" query
+// parameter set to the token address, e.g. GET <url>?token=0x.... The endpoint
+// must respond with a JSON object of the form:
+//
+//	{"priceUSD": "1.0012", "decimals": 18}
+//
+// where priceUSD is the price of one whole token in USD and decimals is the
+// number of decimals of the token. The endpoint should respond with status
+// code 404 if it does not know the price of a token.
+type HTTP struct {
+	endpoint *url.URL
+	client   *http.Client
+}
+
+type httpPriceResponse struct {
+	PriceUSD json.Number `json:"priceUSD"`
+	Decimals uint8       `json:"decimals"`
+}
+
+// NewHTTP returns an HTTP price oracle which uses the given endpoint.
+func NewHTTP(endpoint string) (*HTTP, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if endpointURL.Scheme != "http" && endpointURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid price oracle URL %q: scheme must be http or https", endpoint)
+	}
+	return &HTTP{
+		endpoint: endpointURL,
+		client:   &http.Client{Timeout: httpRequestTimeout},
+	}, nil
+}
+
+// PriceUSD returns the USD price of a single base unit of the given token
+// according to the HTTP endpoint.
+func (h *HTTP) PriceUSD(ctx context.Context, token common.Address) (*big.Float, error) {
+	requestURL := *h.endpoint
+	query := requestURL.Query()
+	query.Set("token", token.Hex())
+	requestURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", requestURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrPriceUnavailable
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("price oracle responded with unexpected status code: %d", resp.StatusCode)
+	}
+	var priceResponse httpPriceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&priceResponse); err != nil {
+		return nil, err
+	}
+	price, ok := new(big.Float).SetString(priceResponse.PriceUSD.String())
+	if !ok || price.Sign() <= 0 {
+		return nil, fmt.Errorf("price oracle responded with invalid price: %q", priceResponse.PriceUSD)
+	}
+	return price.Quo(price, pow10(priceResponse.Decimals)), nil
+}
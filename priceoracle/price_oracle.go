@@ -0,0 +1,88 @@
+// Package priceoracle provides approximate USD prices for ERC20 tokens. Prices
+// are used to annotate orders with an approximate USD notional value, which in
+// turn can be used to filter out and evict low-value orders. The Chainlink and
+// HTTP price oracles and the cache are not available in the browser.
+package priceoracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrPriceUnavailable is returned when the price of a token is not known to a
+// PriceOracle.
+var ErrPriceUnavailable = errors.New("price unavailable for token")
+
+// PriceOracle returns approximate USD prices for ERC20 tokens.
+type PriceOracle interface {
+	// PriceUSD returns the USD price of a single base unit of the given token
+	// (i.e. the price of one whole token divided by 10^decimals). It returns
+	// ErrPriceUnavailable if the oracle does not know the price of the token.
+	PriceUSD(ctx context.Context, token common.Address) (*big.Float, error)
+}
+
+// Kind is the kind of a PriceOracle, as configured via the PRICE_ORACLE
+// environment variable.
+type Kind string
+
+// Kind values
+const (
+	KindNone      Kind = "none"
+	KindChainlink Kind = "chainlink"
+	KindHTTP      Kind = "http"
+)
+
+// ValueUSD returns the USD value of amount base units of the given token,
+// according to the given oracle.
+func ValueUSD(ctx context.Context, oracle PriceOracle, token common.Address, amount *big.Int) (float64, error) {
+	price, err := oracle.PriceUSD(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	value, _ := new(big.Float).Mul(price, new(big.Float).SetInt(amount)).Float64()
+	return value, nil
+}
+
+// None is a PriceOracle which does not know the price of any token.
+type None struct{}
+
+// PriceUSD always returns ErrPriceUnavailable.
+func (None) PriceUSD(ctx context.Context, token common.Address) (*big.Float, error) {
+	return nil, ErrPriceUnavailable
+}
+
+// ParseChainlinkFeeds parses a comma-separated list of token:aggregator address
+// pairs, as accepted by the PRICE_ORACLE_CHAINLINK_FEEDS environment variable.
+func ParseChainlinkFeeds(feeds string) (map[common.Address]common.Address, error) {
+	result := map[common.Address]common.Address{}
+	for _, pair := range strings.Split(feeds, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.Split(pair, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid Chainlink feed %q: expected token:aggregator", pair)
+		}
+		token := strings.TrimSpace(parts[0])
+		aggregator := strings.TrimSpace(parts[1])
+		if !common.IsHexAddress(token) {
+			return nil, fmt.Errorf("invalid token address in Chainlink feed: %q", token)
+		}
+		if !common.IsHexAddress(aggregator) {
+			return nil, fmt.Errorf("invalid aggregator address in Chainlink feed: %q", aggregator)
+		}
+		result[common.HexToAddress(token)] = common.HexToAddress(aggregator)
+	}
+	return result, nil
+}
+
+// pow10 returns 10^n as a big.Float.
+func pow10(n uint8) *big.Float {
+	return new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil))
+}
@@ -0,0 +1,117 @@
+// +build !js
+
+package priceoracle
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	tokenA = common.HexToAddress("0x871dd7c2b4b25e1aa18728e9d5f2af4c4e431f5c")
+	tokenB = common.HexToAddress("0x0b1ba0af832d7c05fd64161e0db78e85978e8082")
+)
+
+type countingOracle struct {
+	prices   map[common.Address]*big.Float
+	numCalls int
+}
+
+func (c *countingOracle) PriceUSD(ctx context.Context, token common.Address) (*big.Float, error) {
+	c.numCalls++
+	price, found := c.prices[token]
+	if !found {
+		return nil, ErrPriceUnavailable
+	}
+	return price, nil
+}
+
+func TestValueUSD(t *testing.T) {
+	oracle := &countingOracle{
+		prices: map[common.Address]*big.Float{
+			// $2 per whole token with 18 decimals.
+			tokenA: new(big.Float).Quo(big.NewFloat(2), pow10(18)),
+		},
+	}
+	amount, _ := new(big.Int).SetString("1500000000000000000", 10)
+	value, err := ValueUSD(context.Background(), oracle, tokenA, amount)
+	require.NoError(t, err)
+	assert.InDelta(t, 3.0, value, 1e-9)
+
+	_, err = ValueUSD(context.Background(), oracle, tokenB, amount)
+	assert.Equal(t, ErrPriceUnavailable, err)
+}
+
+func TestCached(t *testing.T) {
+	oracle := &countingOracle{
+		prices: map[common.Address]*big.Float{
+			tokenA: big.NewFloat(1),
+		},
+	}
+	cached := NewCached(oracle, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		price, err := cached.PriceUSD(ctx, tokenA)
+		require.NoError(t, err)
+		assert.Equal(t, 0, price.Cmp(big.NewFloat(1)))
+		_, err = cached.PriceUSD(ctx, tokenB)
+		assert.Equal(t, ErrPriceUnavailable, err)
+	}
+	assert.Equal(t, 2, oracle.numCalls, "expected prices (and unavailable prices) to be cached")
+}
+
+func TestParseChainlinkFeeds(t *testing.T) {
+	aggregatorA := common.HexToAddress("0x773616e4d11a78f511299002da57a0a94577f1f4")
+	aggregatorB := common.HexToAddress("0xee9f2375b4bdf6387aa8265dd4fb8f16512a1d46")
+	feeds, err := ParseChainlinkFeeds(" " + tokenA.Hex() + ":" + aggregatorA.Hex() + ", " + tokenB.Hex() + ":" + aggregatorB.Hex() + ",")
+	require.NoError(t, err)
+	assert.Equal(t, map[common.Address]common.Address{
+		tokenA: aggregatorA,
+		tokenB: aggregatorB,
+	}, feeds)
+
+	feeds, err = ParseChainlinkFeeds("")
+	require.NoError(t, err)
+	assert.Empty(t, feeds)
+
+	_, err = ParseChainlinkFeeds(tokenA.Hex())
+	assert.Error(t, err)
+	_, err = ParseChainlinkFeeds(tokenA.Hex() + ":0xinvalid")
+	assert.Error(t, err)
+}
+
+func TestHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("token") != tokenA.Hex() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"priceUSD": "250.5", "decimals": 6}`))
+	}))
+	defer server.Close()
+
+	oracle, err := NewHTTP(server.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	value, err := ValueUSD(ctx, oracle, tokenA, big.NewInt(2000000))
+	require.NoError(t, err)
+	assert.InDelta(t, 501.0, value, 1e-9)
+
+	_, err = oracle.PriceUSD(ctx, tokenB)
+	assert.Equal(t, ErrPriceUnavailable, err)
+}
+
+func TestNewHTTPInvalidURL(t *testing.T) {
+	_, err := NewHTTP("ftp://example.com/prices")
+	assert.Error(t, err)
+}
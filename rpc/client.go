@@ -5,10 +5,12 @@ package rpc
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/0xProject/0x-mesh/common/types"
 	"github.com/0xProject/0x-mesh/zeroex"
 	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rpc"
 	peer "github.com/libp2p/go-libp2p-core/peer"
 	peerstore "github.com/libp2p/go-libp2p-peerstore"
@@ -32,6 +34,12 @@ func NewClient(addr string) (*Client, error) {
 	}, nil
 }
 
+// Close closes the connection to the 0x Mesh node. Any subscriptions are
+// terminated.
+func (c *Client) Close() {
+	c.rpcClient.Close()
+}
+
 // AddOrders adds orders to the 0x Mesh node and broadcasts them throughout the
 // 0x Mesh network.
 func (c *Client) AddOrders(orders []*zeroex.SignedOrder, opts ...types.AddOrdersOpts) (*ordervalidator.ValidationResults, error) {
@@ -43,6 +51,7 @@ func (c *Client) AddOrders(orders []*zeroex.SignedOrder, opts ...types.AddOrders
 		if err := c.rpcClient.Call(&validationResults, "mesh_addOrders", orders, opts[0]); err != nil {
 			return nil, err
 		}
+		return &validationResults, nil
 	}
 	if err := c.rpcClient.Call(&validationResults, "mesh_addOrders", orders); err != nil {
 		return nil, err
@@ -50,6 +59,40 @@ func (c *Client) AddOrders(orders []*zeroex.SignedOrder, opts ...types.AddOrders
 	return &validationResults, nil
 }
 
+// ValidateOrders validates orders without adding them to the 0x Mesh node. The
+// opts can be used to validate the orders against the pending state or with
+// state overrides applied.
+func (c *Client) ValidateOrders(orders []*zeroex.SignedOrder, opts types.ValidateOrdersOpts) (*ordervalidator.ValidationResults, error) {
+	var validationResults ordervalidator.ValidationResults
+	if err := c.rpcClient.Call(&validationResults, "mesh_validateOrders", orders, opts); err != nil {
+		return nil, err
+	}
+	return &validationResults, nil
+}
+
+// GetPropagationReceipts estimates how far the orders with the given hashes
+// have propagated through the network by asking a sample of the Mesh node's
+// peers whether they have stored them. It is typically called a few seconds
+// after AddOrders.
+func (c *Client) GetPropagationReceipts(orderHashes []common.Hash, opts types.GetPropagationReceiptsOpts) (*types.PropagationReceipts, error) {
+	var receipts types.PropagationReceipts
+	if err := c.rpcClient.Call(&receipts, "mesh_getPropagationReceipts", orderHashes, opts); err != nil {
+		return nil, err
+	}
+	return &receipts, nil
+}
+
+// SimulateFill simulates filling the order with the given hash, which must be
+// stored on the Mesh node, from opts.TakerAddress. A reverted fill is not an
+// error; instead the revert reason is included in the result.
+func (c *Client) SimulateFill(orderHash common.Hash, opts types.SimulateFillOpts) (*types.SimulateFillResult, error) {
+	var result types.SimulateFillResult
+	if err := c.rpcClient.Call(&result, "mesh_simulateFill", orderHash, opts); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // GetOrders gets all orders stored on the Mesh node at a particular point in time in a paginated fashion
 func (c *Client) GetOrders(page, perPage int, snapshotID string) (*types.GetOrdersResponse, error) {
 	var getOrdersResponse types.GetOrdersResponse
@@ -59,6 +102,39 @@ func (c *Client) GetOrders(page, perPage int, snapshotID string) (*types.GetOrde
 	return &getOrdersResponse, nil
 }
 
+// GetOrderDiff gets the orders which were added, changed or removed since the
+// snapshot with the given ID. Pass an empty sinceSnapshotID to get all orders.
+// The SnapshotID of the response should be passed to the next call.
+func (c *Client) GetOrderDiff(sinceSnapshotID string) (*types.GetOrderDiffResponse, error) {
+	var response types.GetOrderDiffResponse
+	if err := c.rpcClient.Call(&response, "mesh_getOrderDiff", sinceSnapshotID); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetOrderByHash gets the order with the given hash along with its provenance
+// and recent state history. It returns nil if the order is not stored on the
+// Mesh node.
+func (c *Client) GetOrderByHash(orderHash common.Hash) (*types.GetOrderByHashResponse, error) {
+	var response *types.GetOrderByHashResponse
+	if err := c.rpcClient.Call(&response, "mesh_getOrderByHash", orderHash); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetOrderCounts gets the number of orders stored on the Mesh node for each
+// asset pair, maker or status, depending on groupBy ("pair", "maker" or
+// "status").
+func (c *Client) GetOrderCounts(groupBy string) (*types.GetOrderCountsResponse, error) {
+	var response types.GetOrderCountsResponse
+	if err := c.rpcClient.Call(&response, "mesh_getOrderCounts", groupBy); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
 // AddPeer adds the peer to the node's list of peers. The node will attempt to
 // connect to this new peer and return an error if it cannot.
 func (c *Client) AddPeer(peerInfo peerstore.PeerInfo) error {
@@ -82,6 +158,178 @@ func (c *Client) GetStats() (*types.Stats, error) {
 	return getStatsResponse, nil
 }
 
+// GetRuntimeStats retrieves statistics about the Go runtime of the Mesh node,
+// such as the number of goroutines, heap usage and GC pauses.
+func (c *Client) GetRuntimeStats() (*types.RuntimeStats, error) {
+	var runtimeStats types.RuntimeStats
+	if err := c.rpcClient.Call(&runtimeStats, "mesh_getRuntimeStats"); err != nil {
+		return nil, err
+	}
+	return &runtimeStats, nil
+}
+
+// GetRecentRejections retrieves the number of orders rejected by the Mesh node
+// by status code and the most recent rejections. If opts.MakerAddress is set,
+// only the rejections of orders from that maker are returned.
+func (c *Client) GetRecentRejections(opts types.GetRecentRejectionsOpts) (*types.RecentRejections, error) {
+	var recentRejections types.RecentRejections
+	if err := c.rpcClient.Call(&recentRejections, "mesh_getRecentRejections", opts); err != nil {
+		return nil, err
+	}
+	return &recentRejections, nil
+}
+
+// GetPeers retrieves the peers the Mesh node is currently connected to.
+func (c *Client) GetPeers() ([]*types.PeerInfo, error) {
+	var peers []*types.PeerInfo
+	if err := c.rpcClient.Call(&peers, "mesh_getPeers"); err != nil {
+		return nil, err
+	}
+	return peers, nil
+}
+
+// BanPeer bans the IP addresses of the peer with the given ID and disconnects
+// from it.
+func (c *Client) BanPeer(peerID peer.ID) error {
+	if err := c.rpcClient.Call(nil, "mesh_banPeer", peer.IDB58Encode(peerID)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetMakerLists retrieves the maker allowlist and denylist enforced by the
+// Mesh node.
+func (c *Client) GetMakerLists() (*types.MakerLists, error) {
+	var makerLists types.MakerLists
+	if err := c.rpcClient.Call(&makerLists, "mesh_getMakerLists"); err != nil {
+		return nil, err
+	}
+	return &makerLists, nil
+}
+
+// SetMakerAllowlist replaces the maker allowlist enforced by the Mesh node. An
+// empty allowlist allows orders from all makers not on the denylist. Any stored
+// orders from makers that are no longer allowed are removed.
+func (c *Client) SetMakerAllowlist(addresses []common.Address) (*types.SetMakerListResponse, error) {
+	var response types.SetMakerListResponse
+	if err := c.rpcClient.Call(&response, "mesh_setMakerAllowlist", addresses); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SetMakerAllowlistNames is like SetMakerAllowlist but entries may be
+// addresses or ENS names.
+func (c *Client) SetMakerAllowlistNames(addressesOrNames []string) (*types.SetMakerListResponse, error) {
+	var response types.SetMakerListResponse
+	if err := c.rpcClient.Call(&response, "mesh_setMakerAllowlistNames", addressesOrNames); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SetMakerDenylist replaces the maker denylist enforced by the Mesh node. Any
+// stored orders from makers on the new denylist are removed.
+func (c *Client) SetMakerDenylist(addresses []common.Address) (*types.SetMakerListResponse, error) {
+	var response types.SetMakerListResponse
+	if err := c.rpcClient.Call(&response, "mesh_setMakerDenylist", addresses); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SetMakerDenylistNames is like SetMakerDenylist but entries may be addresses
+// or ENS names.
+func (c *Client) SetMakerDenylistNames(addressesOrNames []string) (*types.SetMakerListResponse, error) {
+	var response types.SetMakerListResponse
+	if err := c.rpcClient.Call(&response, "mesh_setMakerDenylistNames", addressesOrNames); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetAssetDenylist retrieves the token addresses on the asset denylist enforced
+// by the Mesh node.
+func (c *Client) GetAssetDenylist() ([]common.Address, error) {
+	var addresses []common.Address
+	if err := c.rpcClient.Call(&addresses, "mesh_getAssetDenylist"); err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// SetAssetDenylist replaces the asset denylist enforced by the Mesh node. Any
+// stored orders involving a token on the new denylist are removed.
+func (c *Client) SetAssetDenylist(addresses []common.Address) (*types.SetAssetDenylistResponse, error) {
+	var response types.SetAssetDenylistResponse
+	if err := c.rpcClient.Call(&response, "mesh_setAssetDenylist", addresses); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetAuditLog retrieves the records in the Mesh node's audit log of mutating
+// RPC calls that were created at or after the given time. If since is the zero
+// time, all records are retrieved.
+func (c *Client) GetAuditLog(since time.Time) ([]*types.AuditRecord, error) {
+	var records []*types.AuditRecord
+	var sinceParam *time.Time
+	if !since.IsZero() {
+		sinceParam = &since
+	}
+	if err := c.rpcClient.Call(&records, "mesh_getAuditLog", sinceParam); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetMetricsHistory retrieves the samples of key metrics taken by the Mesh
+// node at or after the given time. Samples are taken every minute and kept for
+// 24 hours. If since is the zero time, all samples are retrieved.
+func (c *Client) GetMetricsHistory(since time.Time) ([]*types.MetricsSample, error) {
+	var samples []*types.MetricsSample
+	var sinceParam *time.Time
+	if !since.IsZero() {
+		sinceParam = &since
+	}
+	if err := c.rpcClient.Call(&samples, "mesh_getMetricsHistory", sinceParam); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// ExportAuditLog causes the Mesh node to write its audit log of mutating RPC
+// calls to the file configured via AUDIT_LOG_EXPORT_PATH.
+func (c *Client) ExportAuditLog() (*types.ExportAuditLogResponse, error) {
+	var response types.ExportAuditLogResponse
+	if err := c.rpcClient.Call(&response, "mesh_exportAuditLog"); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ExportOrders causes the Mesh node to write all orders matching the given
+// options to a file in its data directory. Use the /orders/export endpoint of
+// the HTTP RPC server to download the orders instead.
+func (c *Client) ExportOrders(opts types.ExportOrdersOpts) (*types.ExportOrdersResponse, error) {
+	var response types.ExportOrdersResponse
+	if err := c.rpcClient.Call(&response, "mesh_exportOrders", opts); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// BackfillOrderEvents causes the Mesh node to scan the Exchange logs of the
+// given makers in the given block range for fills and cancellations it may
+// have missed and to emit corrective order events for the affected orders.
+func (c *Client) BackfillOrderEvents(opts types.BackfillOrderEventsOpts) (*types.BackfillOrderEventsResponse, error) {
+	var response types.BackfillOrderEventsResponse
+	if err := c.rpcClient.Call(&response, "mesh_backfillOrderEvents", opts); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
 // SubscribeToOrders subscribes a stream of order events
 // Note copied from `go-ethereum` codebase: Slow subscribers will be dropped eventually. Client
 // buffers up to 8000 notifications before considering the subscriber dead. The subscription Err
@@ -91,6 +339,40 @@ func (c *Client) SubscribeToOrders(ctx context.Context, ch chan<- []*zeroex.Orde
 	return c.rpcClient.Subscribe(ctx, "mesh", ch, "orders")
 }
 
+// SubscribeToResumableOrders subscribes a stream of order events which are sent
+// along with resume tokens. If the subscription is interrupted, the resume
+// token of the last received notification can be passed via opts.ResumeToken
+// to a new subscription in order to receive the order events that were
+// missed in the meantime.
+// Note copied from `go-ethereum` codebase: Slow subscribers will be dropped eventually. Client
+// buffers up to 8000 notifications before considering the subscriber dead. The subscription Err
+// channel will receive ErrSubscriptionQueueOverflow. Use a sufficiently large buffer on the channel
+// or ensure that the channel usually has at least one reader to prevent this issue.
+func (c *Client) SubscribeToResumableOrders(ctx context.Context, ch chan<- *types.OrderEventsNotification, opts types.OrderSubscriptionOpts) (*rpc.ClientSubscription, error) {
+	opts.Resumable = true
+	return c.rpcClient.Subscribe(ctx, "mesh", ch, "orders", opts)
+}
+
+// SubscribeToBlocks subscribes a stream of block events, i.e. blocks being
+// added to or removed from the chain as seen by the Mesh node.
+// Note copied from `go-ethereum` codebase: Slow subscribers will be dropped eventually. Client
+// buffers up to 8000 notifications before considering the subscriber dead. The subscription Err
+// channel will receive ErrSubscriptionQueueOverflow. Use a sufficiently large buffer on the channel
+// or ensure that the channel usually has at least one reader to prevent this issue.
+func (c *Client) SubscribeToBlocks(ctx context.Context, ch chan<- []*types.BlockEvent) (*rpc.ClientSubscription, error) {
+	return c.rpcClient.Subscribe(ctx, "mesh", ch, "blocks")
+}
+
+// SubscribeToContractEvents subscribes a stream of the contract events decoded
+// by the Mesh node which pass the given filter.
+// Note copied from `go-ethereum` codebase: Slow subscribers will be dropped eventually. Client
+// buffers up to 8000 notifications before considering the subscriber dead. The subscription Err
+// channel will receive ErrSubscriptionQueueOverflow. Use a sufficiently large buffer on the channel
+// or ensure that the channel usually has at least one reader to prevent this issue.
+func (c *Client) SubscribeToContractEvents(ctx context.Context, ch chan<- []*zeroex.ContractEvent, filter types.ContractEventFilter) (*rpc.ClientSubscription, error) {
+	return c.rpcClient.Subscribe(ctx, "mesh", ch, "contractEvents", filter)
+}
+
 // SubscribeToHeartbeat subscribes a stream of heartbeats in order to have certainty that the WS
 // connection is still alive.
 // Note copied from `go-ethereum` codebase: Slow subscribers will be dropped eventually. Client
@@ -0,0 +1,308 @@
+// +build !js
+
+// Package meshclient provides a higher-level client for the JSON-RPC API of a
+// 0x Mesh node. Unlike rpc.Client, it reconnects automatically with an
+// exponential backoff whenever the connection is lost, resubscribes to order
+// events and backfills the order events that were missed in the meantime.
+package meshclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0xProject/0x-mesh/common/types"
+	"github.com/0xProject/0x-mesh/rpc"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/jpillora/backoff"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMinBackoff       = 250 * time.Millisecond
+	defaultMaxBackoff       = 30 * time.Second
+	defaultHeartbeatTimeout = 90 * time.Second
+	// maxAddOrdersAttempts is the maximum number of times AddOrders is sent
+	// before giving up. Adding the same orders more than once is harmless.
+	maxAddOrdersAttempts = 3
+	// notificationsBufferSize is the buffer size of the channel on which
+	// notifications are received from the Mesh node.
+	notificationsBufferSize = 100
+)
+
+// Clock tells the time and waits. It can be replaced in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Options configures a Client. All fields are optional.
+type Options struct {
+	// MinBackoff is the amount of time to wait before the first reconnection
+	// attempt. Defaults to 250ms.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum amount of time to wait between reconnection
+	// attempts. Defaults to 30s.
+	MaxBackoff time.Duration
+	// HeartbeatTimeout is the amount of time after which a subscription
+	// without any notifications is considered broken and re-established. The
+	// Mesh node sends a notification at least every 30 seconds. Defaults to
+	// 90s.
+	HeartbeatTimeout time.Duration
+	// Clock is used for all timing. Defaults to the system clock.
+	Clock Clock
+	// Dial connects to the Mesh node at the given address. Defaults to
+	// rpc.NewClient.
+	Dial func(addr string) (*rpc.Client, error)
+}
+
+func (opts Options) withDefaults() Options {
+	if opts.MinBackoff == 0 {
+		opts.MinBackoff = defaultMinBackoff
+	}
+	if opts.MaxBackoff == 0 {
+		opts.MaxBackoff = defaultMaxBackoff
+	}
+	if opts.HeartbeatTimeout == 0 {
+		opts.HeartbeatTimeout = defaultHeartbeatTimeout
+	}
+	if opts.Clock == nil {
+		opts.Clock = realClock{}
+	}
+	if opts.Dial == nil {
+		opts.Dial = rpc.NewClient
+	}
+	return opts
+}
+
+// Client is a client for the JSON-RPC API of a 0x Mesh node which reconnects
+// automatically. It is safe for concurrent use.
+type Client struct {
+	addr      string
+	opts      Options
+	mu        sync.Mutex
+	rpcClient *rpc.Client
+}
+
+// Connect connects to the Mesh node at addr (e.g. "ws://localhost:60557"). It
+// keeps retrying with an exponential backoff until it succeeds or ctx is
+// canceled.
+func Connect(ctx context.Context, addr string, opts Options) (*Client, error) {
+	c := &Client{
+		addr: addr,
+		opts: opts.withDefaults(),
+	}
+	if _, err := c.reconnect(ctx, nil); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// RPCClient returns the current underlying rpc.Client. It can be used to call
+// methods which this client does not wrap. Note that it is replaced whenever
+// the client reconnects.
+func (c *Client) RPCClient() *rpc.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rpcClient
+}
+
+// Close closes the connection to the Mesh node.
+func (c *Client) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rpcClient != nil {
+		c.rpcClient.Close()
+	}
+}
+
+// AddOrders adds orders to the Mesh node. If the request fails because the
+// connection was lost, it reconnects and sends the request again.
+func (c *Client) AddOrders(ctx context.Context, orders []*zeroex.SignedOrder, opts types.AddOrdersOpts) (*ordervalidator.ValidationResults, error) {
+	var err error
+	for attempt := 0; attempt < maxAddOrdersAttempts; attempt++ {
+		rpcClient := c.RPCClient()
+		var results *ordervalidator.ValidationResults
+		results, err = rpcClient.AddOrders(orders, opts)
+		if err == nil {
+			return results, nil
+		}
+		if _, ok := err.(ethrpc.Error); ok {
+			// The Mesh node responded with an error. Retrying won't help.
+			return nil, err
+		}
+		log.WithError(err).Debug("AddOrders failed; reconnecting")
+		if _, reconnectErr := c.reconnect(ctx, rpcClient); reconnectErr != nil {
+			return nil, reconnectErr
+		}
+	}
+	return nil, err
+}
+
+// reconnect replaces the broken rpc.Client with a new one. If another caller
+// has already replaced it, the current client is returned instead. It keeps
+// retrying with an exponential backoff until it succeeds or ctx is canceled.
+func (c *Client) reconnect(ctx context.Context, broken *rpc.Client) (*rpc.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rpcClient != broken {
+		return c.rpcClient, nil
+	}
+	if broken != nil {
+		broken.Close()
+	}
+	retryBackoff := c.newBackoff()
+	for {
+		rpcClient, err := c.opts.Dial(c.addr)
+		if err == nil {
+			c.rpcClient = rpcClient
+			return rpcClient, nil
+		}
+		log.WithError(err).WithField("addr", c.addr).Debug("could not connect to Mesh node")
+		if err := c.wait(ctx, retryBackoff.Duration()); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) newBackoff() *backoff.Backoff {
+	return &backoff.Backoff{
+		Min:    c.opts.MinBackoff,
+		Max:    c.opts.MaxBackoff,
+		Factor: 2,
+	}
+}
+
+// wait waits for d or until ctx is canceled.
+func (c *Client) wait(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.opts.Clock.After(d):
+		return nil
+	}
+}
+
+// Subscription is a subscription to order events created by
+// SubscribeWithReplay.
+type Subscription struct {
+	cancel context.CancelFunc
+	err    chan error
+	once   sync.Once
+}
+
+// Unsubscribe ends the subscription. The Err channel is closed afterwards.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(s.cancel)
+}
+
+// Err returns a channel which receives an error if the subscription could not
+// be re-established and which is closed when the subscription ends.
+func (s *Subscription) Err() <-chan error {
+	return s.err
+}
+
+// SubscribeWithReplay subscribes to order events and sends them to ch. If the
+// connection is lost or no notification is received within the heartbeat
+// timeout, it reconnects and resumes the subscription, so that the order
+// events emitted in the meantime are sent to ch before any new ones. If they
+// are no longer available on the Mesh node, a notification with
+// ResyncRequired set is sent instead and the caller should resync its
+// orderbook, e.g. via GetOrders. Other notifications without order events
+// are heartbeats and can be ignored.
+func (c *Client) SubscribeWithReplay(ctx context.Context, ch chan<- *types.OrderEventsNotification) (*Subscription, error) {
+	rpcClient := c.RPCClient()
+	notifications := make(chan *types.OrderEventsNotification, notificationsBufferSize)
+	rpcSub, err := rpcClient.SubscribeToResumableOrders(ctx, notifications, types.OrderSubscriptionOpts{})
+	if err != nil {
+		return nil, err
+	}
+	innerCtx, cancel := context.WithCancel(ctx)
+	sub := &Subscription{
+		cancel: cancel,
+		err:    make(chan error, 1),
+	}
+	go c.forwardNotifications(innerCtx, sub, ch, rpcClient, rpcSub, notifications)
+	return sub, nil
+}
+
+// forwardNotifications sends the notifications received on notifications to
+// ch and re-establishes the subscription when needed.
+func (c *Client) forwardNotifications(ctx context.Context, sub *Subscription, ch chan<- *types.OrderEventsNotification, rpcClient *rpc.Client, rpcSub *ethrpc.ClientSubscription, notifications chan *types.OrderEventsNotification) {
+	defer close(sub.err)
+	resumeToken := ""
+	lastNotification := c.opts.Clock.Now()
+	heartbeatTimeout := c.opts.Clock.After(c.opts.HeartbeatTimeout)
+	for {
+		select {
+		case <-ctx.Done():
+			rpcSub.Unsubscribe()
+			return
+		case notification := <-notifications:
+			lastNotification = c.opts.Clock.Now()
+			resumeToken = notification.ResumeToken
+			select {
+			case ch <- notification:
+			case <-ctx.Done():
+				rpcSub.Unsubscribe()
+				return
+			}
+			continue
+		case err := <-rpcSub.Err():
+			log.WithError(err).Debug("order event subscription was interrupted; resubscribing")
+		case <-heartbeatTimeout:
+			elapsed := c.opts.Clock.Now().Sub(lastNotification)
+			if elapsed < c.opts.HeartbeatTimeout {
+				heartbeatTimeout = c.opts.Clock.After(c.opts.HeartbeatTimeout - elapsed)
+				continue
+			}
+			log.WithField("elapsed", elapsed).Debug("missed order event subscription heartbeat; resubscribing")
+		}
+
+		rpcSub.Unsubscribe()
+		var err error
+		rpcClient, rpcSub, notifications, err = c.resubscribe(ctx, rpcClient, resumeToken)
+		if err != nil {
+			if ctx.Err() == nil {
+				sub.err <- err
+			}
+			return
+		}
+		lastNotification = c.opts.Clock.Now()
+		heartbeatTimeout = c.opts.Clock.After(c.opts.HeartbeatTimeout)
+	}
+}
+
+// resubscribe reconnects and resumes the order event subscription from
+// resumeToken. It keeps retrying with an exponential backoff until it
+// succeeds or ctx is canceled.
+func (c *Client) resubscribe(ctx context.Context, broken *rpc.Client, resumeToken string) (*rpc.Client, *ethrpc.ClientSubscription, chan *types.OrderEventsNotification, error) {
+	retryBackoff := c.newBackoff()
+	for {
+		rpcClient, err := c.reconnect(ctx, broken)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		notifications := make(chan *types.OrderEventsNotification, notificationsBufferSize)
+		opts := types.OrderSubscriptionOpts{ResumeToken: resumeToken}
+		rpcSub, err := rpcClient.SubscribeToResumableOrders(ctx, notifications, opts)
+		if err == nil {
+			return rpcClient, rpcSub, notifications, nil
+		}
+		log.WithError(err).Debug("could not resume order event subscription")
+		broken = rpcClient
+		if err := c.wait(ctx, retryBackoff.Duration()); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+}
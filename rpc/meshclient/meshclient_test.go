@@ -0,0 +1,68 @@
+// +build !js
+
+package meshclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xProject/0x-mesh/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock records the durations it was asked to wait for and returns
+// immediately.
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestConnectBacksOffExponentially(t *testing.T) {
+	clock := &fakeClock{}
+	dialErr := errors.New("connection refused")
+	numDials := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := Connect(ctx, "ws://localhost:60557", Options{
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: time.Second,
+		Clock:      clock,
+		Dial: func(addr string) (*rpc.Client, error) {
+			numDials++
+			if numDials == 6 {
+				cancel()
+			}
+			return nil, dialErr
+		},
+	})
+	require.Equal(t, context.Canceled, err)
+	assert.Equal(t, 6, numDials)
+	assert.Equal(t, []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+	}, clock.waits)
+}
@@ -0,0 +1,205 @@
+// +build !js
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/0xProject/0x-mesh/common/addressformat"
+)
+
+// BigNumberFormat determines how uint256 values (e.g. order amounts) are
+// rendered in the JSON sent over a connection. Clients choose it with the
+// bigNumberFormat query parameter of the URL they connect to.
+type BigNumberFormat string
+
+// BigNumberFormat values
+const (
+	// BigNumberFormatString renders uint256 values as decimal strings, e.g.
+	// "1000000000000000000". It is the default.
+	BigNumberFormatString BigNumberFormat = "string"
+	// BigNumberFormatNumber renders uint256 values as JSON numbers, e.g.
+	// 1000000000000000000. Clients must parse them without loss of precision
+	// (e.g. into a BigInt).
+	BigNumberFormatNumber BigNumberFormat = "number"
+	// BigNumberFormatHex renders uint256 values as 0x-prefixed hex strings,
+	// e.g. "0xde0b6b3a7640000".
+	BigNumberFormatHex BigNumberFormat = "hex"
+)
+
+// bigNumberFormatQueryParam is the name of the query parameter with which
+// clients choose the BigNumberFormat.
+const bigNumberFormatQueryParam = "bigNumberFormat"
+
+// bigNumberFields are the names of the JSON fields which hold uint256 values
+// (or arrays of them) as decimal strings.
+var bigNumberFields = map[string]bool{
+	"makerAssetAmount":             true,
+	"takerAssetAmount":             true,
+	"makerFee":                     true,
+	"takerFee":                     true,
+	"expirationTimeSeconds":        true,
+	"salt":                         true,
+	"fillableTakerAssetAmount":     true,
+	"makerAssetFilledAmount":       true,
+	"takerAssetFilledAmount":       true,
+	"makerFeePaid":                 true,
+	"takerFeePaid":                 true,
+	"protocolFeePaid":              true,
+	"orderEpoch":                   true,
+	"oldProtocolFeeMultiplier":     true,
+	"updatedProtocolFeeMultiplier": true,
+	"value":                        true,
+	"values":                       true,
+	"id":                           true,
+	"ids":                          true,
+	"tokenId":                      true,
+	"maxExpirationTime":            true,
+	"baseFee":                      true,
+}
+
+// parseBigNumberFormat returns the BigNumberFormat requested by the given
+// request.
+func parseBigNumberFormat(r *http.Request) (BigNumberFormat, error) {
+	format := BigNumberFormat(r.URL.Query().Get(bigNumberFormatQueryParam))
+	switch format {
+	case "":
+		return BigNumberFormatString, nil
+	case BigNumberFormatString, BigNumberFormatNumber, BigNumberFormatHex:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid %s: %q (must be one of %q, %q or %q)", bigNumberFormatQueryParam, format, BigNumberFormatString, BigNumberFormatNumber, BigNumberFormatHex)
+	}
+}
+
+// needsFormatting returns true if messages have to be re-encoded by
+// formatMessage. Addresses only need to be re-encoded if they are checksummed
+// because addresses that aren't emitted by zeroex (i.e. common.Address values)
+// are always lowercase.
+func needsFormatting(format BigNumberFormat) bool {
+	return format != BigNumberFormatString || addressformat.Get() == addressformat.Checksum
+}
+
+// formatMessage re-encodes the uint256 values in the given JSON RPC message
+// (or batch of messages) in the given format and the addresses in it in the
+// current addressformat.Format. Only the result of responses and the params of
+// notifications are changed, so that e.g. request IDs are left alone.
+func formatMessage(message []byte, format BigNumberFormat) ([]byte, error) {
+	if !needsFormatting(format) {
+		return message, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(message))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	switch decoded := decoded.(type) {
+	case []interface{}:
+		for _, element := range decoded {
+			formatEnvelope(element, format)
+		}
+	default:
+		formatEnvelope(decoded, format)
+	}
+	return json.Marshal(decoded)
+}
+
+func formatEnvelope(envelope interface{}, format BigNumberFormat) {
+	fields, ok := envelope.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, key := range []string{"result", "params"} {
+		if value, found := fields[key]; found {
+			fields[key] = formatValue(value, format, false)
+		}
+	}
+}
+
+// formatValue walks the given decoded JSON value and formats the decimal
+// strings in bigNumberFields and all addresses. isBigNumber is true if value
+// is (part of) such a field.
+func formatValue(value interface{}, format BigNumberFormat, isBigNumber bool) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, fieldValue := range value {
+			value[key] = formatValue(fieldValue, format, bigNumberFields[key])
+		}
+		return value
+	case []interface{}:
+		for i, element := range value {
+			value[i] = formatValue(element, format, isBigNumber)
+		}
+		return value
+	case string:
+		if !isBigNumber {
+			if addressformat.IsHexAddress(value) {
+				return addressformat.Reformat(value)
+			}
+			return value
+		}
+		number, ok := new(big.Int).SetString(value, 10)
+		if !ok || number.Sign() < 0 {
+			return value
+		}
+		switch format {
+		case BigNumberFormatNumber:
+			return json.Number(number.String())
+		case BigNumberFormatHex:
+			return "0x" + number.Text(16)
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// formatHandler wraps the given JSON RPC over HTTP handler so that the uint256
+// values in its responses are rendered in the format requested by each
+// request and addresses in the current addressformat.Format.
+func formatHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format, err := parseBigNumberFormat(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !needsFormatting(format) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		recorder := &responseRecorder{header: w.Header(), statusCode: http.StatusOK}
+		handler.ServeHTTP(recorder, r)
+		body := recorder.body.Bytes()
+		if formatted, err := formatMessage(body, format); err == nil {
+			body = formatted
+		}
+		w.Header().Del("Content-Length")
+		w.WriteHeader(recorder.statusCode)
+		_, _ = w.Write(body)
+	})
+}
+
+// responseRecorder is an http.ResponseWriter which buffers the response body.
+type responseRecorder struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	return r.body.Write(data)
+}
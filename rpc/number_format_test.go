@@ -0,0 +1,59 @@
+// +build !js
+
+package rpc
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/common/addressformat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatMessage(t *testing.T) {
+	message := []byte(`{"jsonrpc":"2.0","id":"7","result":{"makerAssetAmount":"1000000000000000000","salt":"12","ids":["1","2"],"orderHash":"0x1234","chainId":1337,"peerID":"42"}}`)
+
+	formatted, err := formatMessage(message, BigNumberFormatString)
+	require.NoError(t, err)
+	assert.Equal(t, message, formatted)
+
+	formatted, err = formatMessage(message, BigNumberFormatNumber)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":"7","result":{"makerAssetAmount":1000000000000000000,"salt":12,"ids":[1,2],"orderHash":"0x1234","chainId":1337,"peerID":"42"}}`, string(formatted))
+
+	formatted, err = formatMessage(message, BigNumberFormatHex)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":"7","result":{"makerAssetAmount":"0xde0b6b3a7640000","salt":"0xc","ids":["0x1","0x2"],"orderHash":"0x1234","chainId":1337,"peerID":"42"}}`, string(formatted))
+
+	// Notifications and batches are supported as well.
+	notification := []byte(`[{"jsonrpc":"2.0","method":"mesh_subscription","params":{"subscription":"0x1","result":[{"fillableTakerAssetAmount":"100"}]}}]`)
+	formatted, err = formatMessage(notification, BigNumberFormatNumber)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"jsonrpc":"2.0","method":"mesh_subscription","params":{"subscription":"0x1","result":[{"fillableTakerAssetAmount":100}]}}]`, string(formatted))
+}
+
+func TestFormatMessageChecksumAddresses(t *testing.T) {
+	require.NoError(t, addressformat.Set(addressformat.Checksum))
+	defer func() {
+		require.NoError(t, addressformat.Set(addressformat.Lowercase))
+	}()
+	message := []byte(`{"jsonrpc":"2.0","id":"0xe36ea790bc9d7ab70c55260c66d52b1eca985f84","result":{"allowlist":["0xe36ea790bc9d7ab70c55260c66d52b1eca985f84"],"makerAssetAmount":"10","orderHash":"0xddb8be9f6fed5209693ecce4eb127252827c1c331d661ae7a2491c80355f3fdd"}}`)
+
+	formatted, err := formatMessage(message, BigNumberFormatString)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":"0xe36ea790bc9d7ab70c55260c66d52b1eca985f84","result":{"allowlist":["0xE36Ea790bc9d7AB70C55260C66D52b1eca985f84"],"makerAssetAmount":"10","orderHash":"0xddb8be9f6fed5209693ecce4eb127252827c1c331d661ae7a2491c80355f3fdd"}}`, string(formatted))
+}
+
+func TestParseBigNumberFormat(t *testing.T) {
+	format, err := parseBigNumberFormat(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, BigNumberFormatString, format)
+
+	format, err = parseBigNumberFormat(httptest.NewRequest("GET", "/?bigNumberFormat=hex", nil))
+	require.NoError(t, err)
+	assert.Equal(t, BigNumberFormatHex, format)
+
+	_, err = parseBigNumberFormat(httptest.NewRequest("GET", "/?bigNumberFormat=float", nil))
+	assert.Error(t, err)
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/rpc"
 	log "github.com/sirupsen/logrus"
@@ -23,6 +24,9 @@ type Server struct {
 	rpcHandler   RPCHandler
 	listener     net.Listener
 	rpcServer    *rpc.Server
+	extraRoutes  map[string]http.Handler
+	pingInterval time.Duration
+	idleTimeout  time.Duration
 }
 
 // NewServer creates and returns a new server which will listen for new
@@ -35,6 +39,29 @@ func NewServer(addr string, rpcHandler RPCHandler) (*Server, error) {
 	}, nil
 }
 
+// Handle registers an additional HTTP handler for the given pattern (e.g. a
+// health check endpoint). All other requests are handled by the JSON RPC
+// handler. It must be called before Listen.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if s.extraRoutes == nil {
+		s.extraRoutes = map[string]http.Handler{}
+	}
+	s.extraRoutes[pattern] = handler
+}
+
+// SetWebsocketKeepalive configures the interval at which ping frames are sent
+// over WebSocket connections and the amount of time after which connections
+// on which nothing was received are closed. A zero value disables the
+// respective feature. It must be called before Listen.
+func (s *Server) SetWebsocketKeepalive(pingInterval time.Duration, idleTimeout time.Duration) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.pingInterval = pingInterval
+	s.idleTimeout = idleTimeout
+}
+
 // HandlerType represents the type of handler to attach to the server
 type HandlerType uint8
 
@@ -50,14 +77,13 @@ const (
 func (s *Server) Listen(ctx context.Context, handlerType HandlerType) error {
 	s.mut.Lock()
 
-	rpcService := &rpcService{
-		rpcHandler: s.rpcHandler,
-	}
-	s.rpcServer = rpc.NewServer()
-	if err := s.rpcServer.RegisterName("mesh", rpcService); err != nil {
+	rpcServer, err := s.newRPCServer("")
+	if err != nil {
+		s.mut.Unlock()
 		log.WithField("error", err.Error()).Error("could not register RPC service")
 		return err
 	}
+	s.rpcServer = rpcServer
 	listener, err := net.Listen("tcp4", s.addr)
 	if err != nil {
 		s.mut.Unlock()
@@ -65,6 +91,9 @@ func (s *Server) Listen(ctx context.Context, handlerType HandlerType) error {
 		return err
 	}
 	s.listener = listener
+	extraRoutes := s.extraRoutes
+	pingInterval := s.pingInterval
+	idleTimeout := s.idleTimeout
 	s.mut.Unlock()
 
 	// Close the server when the context is canceled.
@@ -77,12 +106,20 @@ func (s *Server) Listen(ctx context.Context, handlerType HandlerType) error {
 	var handler http.Handler
 	switch handlerType {
 	case HTTPHandler:
-		handler = s.rpcServer
+		handler = formatHandler(s.rpcServer)
 	case WSHandler:
-		handler = s.rpcServer.WebsocketHandler([]string{"*"})
+		handler = websocketHandler(ctx, s.newRPCServer, pingInterval, idleTimeout)
 	default:
 		return fmt.Errorf("Unrecognized HandlerType: %d", handlerType)
 	}
+	if len(extraRoutes) > 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/", handler)
+		for pattern, extraHandler := range extraRoutes {
+			mux.Handle(pattern, extraHandler)
+		}
+		handler = mux
+	}
 
 	if err := http.Serve(s.listener, handler); err != nil {
 		// HACK(albrow): http.Serve doesn't accept a context. This means that
@@ -107,6 +144,21 @@ func (s *Server) Listen(ctx context.Context, handlerType HandlerType) error {
 	return nil
 }
 
+// newRPCServer returns a JSON RPC server which serves the mesh namespace.
+// caller identifies the client the server is used for, if it is only used for
+// a single WebSocket connection. It is recorded in the audit log.
+func (s *Server) newRPCServer(caller string) (*rpc.Server, error) {
+	rpcService := &rpcService{
+		rpcHandler: s.rpcHandler,
+		caller:     caller,
+	}
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("mesh", rpcService); err != nil {
+		return nil, err
+	}
+	return rpcServer, nil
+}
+
 func isClosedNetworkConnectionErr(err error) bool {
 	if opErr, ok := err.(*net.OpError); ok {
 		if strings.Contains(opErr.Error(), "use of closed network connection") {
@@ -5,6 +5,7 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net"
 	"strings"
 	"time"
@@ -12,6 +13,8 @@ import (
 	"github.com/0xProject/0x-mesh/common/types"
 	"github.com/0xProject/0x-mesh/constants"
 	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
 	ethrpc "github.com/ethereum/go-ethereum/rpc"
 	peer "github.com/libp2p/go-libp2p-core/peer"
@@ -26,25 +29,99 @@ var minHeartbeatInterval = 5 * time.Second
 // rpcService is an /ethereum/go-ethereum/rpc compatible service.
 type rpcService struct {
 	rpcHandler RPCHandler
+	// caller is the remote address of the WebSocket connection the service is
+	// used for. It is empty for the service used for HTTP requests.
+	caller string
 }
 
 // RPCHandler is used to respond to incoming requests from the client.
 type RPCHandler interface {
 	// AddOrders is called when the client sends an AddOrders request.
 	AddOrders(signedOrdersRaw []*json.RawMessage, opts types.AddOrdersOpts) (*ordervalidator.ValidationResults, error)
+	// ValidateOrders is called when the client sends a ValidateOrders request.
+	ValidateOrders(signedOrdersRaw []*json.RawMessage, opts types.ValidateOrdersOpts) (*ordervalidator.ValidationResults, error)
+	// GetPropagationReceipts is called when the client sends a GetPropagationReceipts request.
+	GetPropagationReceipts(orderHashes []common.Hash, opts types.GetPropagationReceiptsOpts) (*types.PropagationReceipts, error)
+	// SimulateFill is called when the client sends a SimulateFill request.
+	SimulateFill(orderHash common.Hash, opts types.SimulateFillOpts) (*types.SimulateFillResult, error)
 	// GetOrders is called when the clients sends a GetOrders request
 	GetOrders(page, perPage int, snapshotID string) (*types.GetOrdersResponse, error)
+	// GetOrderDiff is called when the client sends a GetOrderDiff request.
+	GetOrderDiff(sinceSnapshotID string) (*types.GetOrderDiffResponse, error)
+	// GetOrderByHash is called when the client sends a GetOrderByHash request.
+	GetOrderByHash(orderHash common.Hash) (*types.GetOrderByHashResponse, error)
+	// GetOrderCounts is called when the client sends a GetOrderCounts request.
+	GetOrderCounts(groupBy string) (*types.GetOrderCountsResponse, error)
 	// AddPeer is called when the client sends an AddPeer request.
 	AddPeer(peerInfo peerstore.PeerInfo) error
 	// GetStats is called when the client sends an GetStats request.
 	GetStats() (*types.Stats, error)
+	// GetRuntimeStats is called when the client sends a GetRuntimeStats request.
+	GetRuntimeStats() (*types.RuntimeStats, error)
+	// GetRecentRejections is called when the client sends a GetRecentRejections request.
+	GetRecentRejections(opts types.GetRecentRejectionsOpts) (*types.RecentRejections, error)
+	// GetPeers is called when the client sends a GetPeers request.
+	GetPeers() ([]*types.PeerInfo, error)
+	// BanPeer is called when the client sends a BanPeer request.
+	BanPeer(peerID peer.ID) error
 	// SubscribeToOrders is called when a client sends a Subscribe to `orders` request
-	SubscribeToOrders(ctx context.Context) (*rpc.Subscription, error)
+	SubscribeToOrders(ctx context.Context, opts types.OrderSubscriptionOpts) (*rpc.Subscription, error)
+	// SubscribeToBlocks is called when a client sends a Subscribe to `blocks` request
+	SubscribeToBlocks(ctx context.Context) (*rpc.Subscription, error)
+	// SubscribeToContractEvents is called when a client sends a Subscribe to `contractEvents` request
+	SubscribeToContractEvents(ctx context.Context, filter types.ContractEventFilter) (*rpc.Subscription, error)
+	// GetMakerLists is called when the client sends a GetMakerLists request.
+	GetMakerLists() (*types.MakerLists, error)
+	// SetMakerAllowlist is called when the client sends a SetMakerAllowlist request.
+	SetMakerAllowlist(addresses []common.Address) (*types.SetMakerListResponse, error)
+	// SetMakerAllowlistNames is called when the client sends a SetMakerAllowlistNames request.
+	SetMakerAllowlistNames(addressesOrNames []string) (*types.SetMakerListResponse, error)
+	// SetMakerDenylist is called when the client sends a SetMakerDenylist request.
+	SetMakerDenylist(addresses []common.Address) (*types.SetMakerListResponse, error)
+	// SetMakerDenylistNames is called when the client sends a SetMakerDenylistNames request.
+	SetMakerDenylistNames(addressesOrNames []string) (*types.SetMakerListResponse, error)
+	// GetAssetDenylist is called when the client sends a GetAssetDenylist request.
+	GetAssetDenylist() ([]common.Address, error)
+	// SetAssetDenylist is called when the client sends a SetAssetDenylist request.
+	SetAssetDenylist(addresses []common.Address) (*types.SetAssetDenylistResponse, error)
+	// RecordAudit is called after each mutating request with a record of the
+	// call.
+	RecordAudit(record *types.AuditRecord)
+	// GetAuditLog is called when the client sends a GetAuditLog request.
+	GetAuditLog(since time.Time) ([]*types.AuditRecord, error)
+	// GetMetricsHistory is called when the client sends a GetMetricsHistory
+	// request.
+	GetMetricsHistory(since time.Time) ([]*types.MetricsSample, error)
+	// ExportAuditLog is called when the client sends an ExportAuditLog request.
+	ExportAuditLog() (*types.ExportAuditLogResponse, error)
+	// ExportOrders is called when the client sends an ExportOrders request.
+	ExportOrders(opts types.ExportOrdersOpts) (*types.ExportOrdersResponse, error)
+	// BackfillOrderEvents is called when the client sends a
+	// BackfillOrderEvents request.
+	BackfillOrderEvents(opts types.BackfillOrderEventsOpts) (*types.BackfillOrderEventsResponse, error)
 }
 
 // Orders calls rpcHandler.SubscribeToOrders and returns the rpc subscription.
-func (s *rpcService) Orders(ctx context.Context) (*rpc.Subscription, error) {
-	return s.rpcHandler.SubscribeToOrders(ctx)
+// The opts are optional.
+func (s *rpcService) Orders(ctx context.Context, opts *types.OrderSubscriptionOpts) (*rpc.Subscription, error) {
+	if opts == nil {
+		opts = &types.OrderSubscriptionOpts{}
+	}
+	return s.rpcHandler.SubscribeToOrders(ctx, *opts)
+}
+
+// Blocks calls rpcHandler.SubscribeToBlocks and returns the rpc subscription.
+func (s *rpcService) Blocks(ctx context.Context) (*rpc.Subscription, error) {
+	return s.rpcHandler.SubscribeToBlocks(ctx)
+}
+
+// ContractEvents calls rpcHandler.SubscribeToContractEvents and returns the rpc
+// subscription. The filter is optional.
+func (s *rpcService) ContractEvents(ctx context.Context, filter *types.ContractEventFilter) (*rpc.Subscription, error) {
+	if filter == nil {
+		filter = &types.ContractEventFilter{}
+	}
+	return s.rpcHandler.SubscribeToContractEvents(ctx, *filter)
 }
 
 // Heartbeat calls rpcHandler.SubscribeToHeartbeat and returns the rpc subscription.
@@ -128,11 +205,41 @@ var defaultAddOrdersOpts = types.AddOrdersOpts{
 }
 
 // AddOrders calls rpcHandler.AddOrders and returns the validation results.
-func (s *rpcService) AddOrders(signedOrdersRaw []*json.RawMessage, opts *types.AddOrdersOpts) (*ordervalidator.ValidationResults, error) {
+func (s *rpcService) AddOrders(ctx context.Context, signedOrdersRaw []*json.RawMessage, opts *types.AddOrdersOpts) (*ordervalidator.ValidationResults, error) {
 	if opts == nil {
 		opts = &defaultAddOrdersOpts
 	}
-	return s.rpcHandler.AddOrders(signedOrdersRaw, *opts)
+	results, err := s.rpcHandler.AddOrders(signedOrdersRaw, *opts)
+	result := ""
+	if results != nil {
+		result = fmt.Sprintf("accepted=%d rejected=%d", len(results.Accepted), len(results.Rejected))
+	}
+	s.audit(ctx, "mesh_addOrders", []interface{}{signedOrdersRaw, opts}, result, err)
+	return results, err
+}
+
+// ValidateOrders calls rpcHandler.ValidateOrders and returns the validation
+// results.
+func (s *rpcService) ValidateOrders(signedOrdersRaw []*json.RawMessage, opts *types.ValidateOrdersOpts) (*ordervalidator.ValidationResults, error) {
+	if opts == nil {
+		opts = &types.ValidateOrdersOpts{}
+	}
+	return s.rpcHandler.ValidateOrders(signedOrdersRaw, *opts)
+}
+
+// GetPropagationReceipts calls rpcHandler.GetPropagationReceipts and returns
+// the propagation receipts.
+func (s *rpcService) GetPropagationReceipts(orderHashes []common.Hash, opts *types.GetPropagationReceiptsOpts) (*types.PropagationReceipts, error) {
+	if opts == nil {
+		opts = &types.GetPropagationReceiptsOpts{}
+	}
+	return s.rpcHandler.GetPropagationReceipts(orderHashes, *opts)
+}
+
+// SimulateFill calls rpcHandler.SimulateFill and returns the simulated fill
+// results.
+func (s *rpcService) SimulateFill(orderHash common.Hash, opts types.SimulateFillOpts) (*types.SimulateFillResult, error) {
+	return s.rpcHandler.SimulateFill(orderHash, opts)
 }
 
 // GetOrders calls rpcHandler.GetOrders and returns the validation results.
@@ -140,9 +247,31 @@ func (s *rpcService) GetOrders(page, perPage int, snapshotID string) (*types.Get
 	return s.rpcHandler.GetOrders(page, perPage, snapshotID)
 }
 
+// GetOrderDiff calls rpcHandler.GetOrderDiff and returns the orders which
+// changed since the given snapshot.
+func (s *rpcService) GetOrderDiff(sinceSnapshotID string) (*types.GetOrderDiffResponse, error) {
+	return s.rpcHandler.GetOrderDiff(sinceSnapshotID)
+}
+
+// GetOrderByHash calls rpcHandler.GetOrderByHash and returns the order with
+// the given hash.
+func (s *rpcService) GetOrderByHash(orderHash common.Hash) (*types.GetOrderByHashResponse, error) {
+	return s.rpcHandler.GetOrderByHash(orderHash)
+}
+
+// GetOrderCounts calls rpcHandler.GetOrderCounts and returns the number of
+// orders in each group.
+func (s *rpcService) GetOrderCounts(groupBy string) (*types.GetOrderCountsResponse, error) {
+	return s.rpcHandler.GetOrderCounts(groupBy)
+}
+
 // AddPeer builds PeerInfo out of the given peer ID and multiaddresses and
 // calls rpcHandler.AddPeer. If there is an error, it returns it.
-func (s *rpcService) AddPeer(peerID string, multiaddrs []string) error {
+func (s *rpcService) AddPeer(ctx context.Context, peerID string, multiaddrs []string) (err error) {
+	defer func() {
+		s.audit(ctx, "mesh_addPeer", []interface{}{peerID, multiaddrs}, "", err)
+	}()
+
 	// Parse peer ID.
 	parsedPeerID, err := peer.IDB58Decode(peerID)
 	if err != nil {
@@ -170,3 +299,182 @@ func (s *rpcService) AddPeer(peerID string, multiaddrs []string) error {
 func (s *rpcService) GetStats() (*types.Stats, error) {
 	return s.rpcHandler.GetStats()
 }
+
+// GetRuntimeStats calls rpcHandler.GetRuntimeStats. If there is an error, it returns it.
+func (s *rpcService) GetRuntimeStats() (*types.RuntimeStats, error) {
+	return s.rpcHandler.GetRuntimeStats()
+}
+
+// GetRecentRejections calls rpcHandler.GetRecentRejections. If there is an
+// error, it returns it.
+func (s *rpcService) GetRecentRejections(opts *types.GetRecentRejectionsOpts) (*types.RecentRejections, error) {
+	if opts == nil {
+		opts = &types.GetRecentRejectionsOpts{}
+	}
+	return s.rpcHandler.GetRecentRejections(*opts)
+}
+
+// GetPeers calls rpcHandler.GetPeers. If there is an error, it returns it.
+func (s *rpcService) GetPeers() ([]*types.PeerInfo, error) {
+	return s.rpcHandler.GetPeers()
+}
+
+// BanPeer parses the given peer ID and calls rpcHandler.BanPeer. If there is
+// an error, it returns it.
+func (s *rpcService) BanPeer(ctx context.Context, peerID string) (err error) {
+	defer func() {
+		s.audit(ctx, "mesh_banPeer", []interface{}{peerID}, "", err)
+	}()
+
+	parsedPeerID, err := peer.IDB58Decode(peerID)
+	if err != nil {
+		return err
+	}
+	return s.rpcHandler.BanPeer(parsedPeerID)
+}
+
+// GetMakerLists calls rpcHandler.GetMakerLists. If there is an error, it returns it.
+func (s *rpcService) GetMakerLists() (*types.MakerLists, error) {
+	return s.rpcHandler.GetMakerLists()
+}
+
+// SetMakerAllowlist calls rpcHandler.SetMakerAllowlist. If there is an error, it returns it.
+func (s *rpcService) SetMakerAllowlist(ctx context.Context, addresses []common.Address) (*types.SetMakerListResponse, error) {
+	response, err := s.rpcHandler.SetMakerAllowlist(addresses)
+	s.audit(ctx, "mesh_setMakerAllowlist", []interface{}{addresses}, setMakerListResult(response), err)
+	return response, err
+}
+
+// SetMakerAllowlistNames calls rpcHandler.SetMakerAllowlistNames. If there is an error, it returns it.
+func (s *rpcService) SetMakerAllowlistNames(ctx context.Context, addressesOrNames []string) (*types.SetMakerListResponse, error) {
+	response, err := s.rpcHandler.SetMakerAllowlistNames(addressesOrNames)
+	s.audit(ctx, "mesh_setMakerAllowlistNames", []interface{}{addressesOrNames}, setMakerListResult(response), err)
+	return response, err
+}
+
+// SetMakerDenylist calls rpcHandler.SetMakerDenylist. If there is an error, it returns it.
+func (s *rpcService) SetMakerDenylist(ctx context.Context, addresses []common.Address) (*types.SetMakerListResponse, error) {
+	response, err := s.rpcHandler.SetMakerDenylist(addresses)
+	s.audit(ctx, "mesh_setMakerDenylist", []interface{}{addresses}, setMakerListResult(response), err)
+	return response, err
+}
+
+// SetMakerDenylistNames calls rpcHandler.SetMakerDenylistNames. If there is an error, it returns it.
+func (s *rpcService) SetMakerDenylistNames(ctx context.Context, addressesOrNames []string) (*types.SetMakerListResponse, error) {
+	response, err := s.rpcHandler.SetMakerDenylistNames(addressesOrNames)
+	s.audit(ctx, "mesh_setMakerDenylistNames", []interface{}{addressesOrNames}, setMakerListResult(response), err)
+	return response, err
+}
+
+// GetAssetDenylist calls rpcHandler.GetAssetDenylist. If there is an error, it returns it.
+func (s *rpcService) GetAssetDenylist() ([]common.Address, error) {
+	return s.rpcHandler.GetAssetDenylist()
+}
+
+// SetAssetDenylist calls rpcHandler.SetAssetDenylist. If there is an error, it returns it.
+func (s *rpcService) SetAssetDenylist(ctx context.Context, addresses []common.Address) (*types.SetAssetDenylistResponse, error) {
+	response, err := s.rpcHandler.SetAssetDenylist(addresses)
+	s.audit(ctx, "mesh_setAssetDenylist", []interface{}{addresses}, setAssetDenylistResult(response), err)
+	return response, err
+}
+
+// GetAuditLog calls rpcHandler.GetAuditLog. If since is nil, all records are
+// returned. If there is an error, it returns it.
+func (s *rpcService) GetAuditLog(since *time.Time) ([]*types.AuditRecord, error) {
+	if since == nil {
+		return s.rpcHandler.GetAuditLog(time.Time{})
+	}
+	return s.rpcHandler.GetAuditLog(*since)
+}
+
+// GetMetricsHistory calls rpcHandler.GetMetricsHistory. If since is nil, all
+// samples from the last 24 hours are returned.
+func (s *rpcService) GetMetricsHistory(since *time.Time) ([]*types.MetricsSample, error) {
+	if since == nil {
+		return s.rpcHandler.GetMetricsHistory(time.Time{})
+	}
+	return s.rpcHandler.GetMetricsHistory(*since)
+}
+
+// ExportAuditLog calls rpcHandler.ExportAuditLog. If there is an error, it returns it.
+func (s *rpcService) ExportAuditLog() (*types.ExportAuditLogResponse, error) {
+	return s.rpcHandler.ExportAuditLog()
+}
+
+// ExportOrders calls rpcHandler.ExportOrders. If opts is nil, all fillable
+// orders are exported as JSON Lines. If there is an error, it returns it.
+func (s *rpcService) ExportOrders(opts *types.ExportOrdersOpts) (*types.ExportOrdersResponse, error) {
+	if opts == nil {
+		return s.rpcHandler.ExportOrders(types.ExportOrdersOpts{})
+	}
+	return s.rpcHandler.ExportOrders(*opts)
+}
+
+// BackfillOrderEvents calls rpcHandler.BackfillOrderEvents. If there is an
+// error, it returns it.
+func (s *rpcService) BackfillOrderEvents(ctx context.Context, opts types.BackfillOrderEventsOpts) (*types.BackfillOrderEventsResponse, error) {
+	response, err := s.rpcHandler.BackfillOrderEvents(opts)
+	s.audit(ctx, "mesh_backfillOrderEvents", []interface{}{opts}, backfillOrderEventsResult(response), err)
+	return response, err
+}
+
+// audit records a call to a mutating RPC method via rpcHandler.RecordAudit.
+func (s *rpcService) audit(ctx context.Context, method string, params []interface{}, result string, err error) {
+	record := &types.AuditRecord{
+		Time:         time.Now().UTC(),
+		Method:       method,
+		Caller:       s.callerFromContext(ctx),
+		ParamsDigest: paramsDigest(params),
+		Success:      err == nil,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	} else {
+		record.Result = result
+	}
+	s.rpcHandler.RecordAudit(record)
+}
+
+// callerFromContext returns an identifier for the caller of an RPC method, or
+// an empty string if the caller is unknown. For HTTP requests, go-ethereum
+// stores the remote address in the context. WebSocket connections are served
+// by their own rpcService, which knows the remote address of the connection.
+func (s *rpcService) callerFromContext(ctx context.Context) string {
+	if remote, ok := ctx.Value("remote").(string); ok {
+		return remote
+	}
+	return s.caller
+}
+
+// paramsDigest returns the Keccak256 hash of the JSON-encoded params. The
+// digest can be used to check whether a call was made with specific
+// parameters without storing the parameters themselves.
+func paramsDigest(params []interface{}) string {
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		// This should never happen since the params were just decoded from JSON.
+		return ""
+	}
+	return crypto.Keccak256Hash(encoded).Hex()
+}
+
+func setMakerListResult(response *types.SetMakerListResponse) string {
+	if response == nil {
+		return ""
+	}
+	return fmt.Sprintf("numOrdersRemoved=%d", response.NumOrdersRemoved)
+}
+
+func setAssetDenylistResult(response *types.SetAssetDenylistResponse) string {
+	if response == nil {
+		return ""
+	}
+	return fmt.Sprintf("numOrdersRemoved=%d", response.NumOrdersRemoved)
+}
+
+func backfillOrderEventsResult(response *types.BackfillOrderEventsResponse) string {
+	if response == nil {
+		return ""
+	}
+	return fmt.Sprintf("numOrderEvents=%d", response.NumOrderEvents)
+}
@@ -0,0 +1,131 @@
+// +build !js
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// wsReadLimit is the maximum size of a message received over WebSockets.
+	wsReadLimit = 5 * 1024 * 1024
+	// wsPingWriteTimeout is the maximum amount of time it may take to write a
+	// ping frame.
+	wsPingWriteTimeout = 10 * time.Second
+)
+
+// websocketHandler returns an HTTP handler which serves the JSON RPC server
+// over WebSockets like rpc.Server.WebsocketHandler does. Additionally, it sends
+// a ping frame every pingInterval and closes connections on which nothing
+// (including pong frames) was received for idleTimeout. Load balancers and
+// proxies tend to silently drop idle connections, so the pings keep them alive
+// and the idle timeout ensures that half-open connections are closed. A zero
+// pingInterval or idleTimeout disables the respective feature. uint256 values
+// are rendered in the BigNumberFormat requested when connecting and addresses
+// in the current addressformat.Format. Each connection is served by its own
+// JSON RPC server created by newRPCServer, so that calls can be attributed to
+// the remote address of the connection. The servers are stopped when ctx is
+// canceled.
+func websocketHandler(ctx context.Context, newRPCServer func(caller string) (*rpc.Server, error), pingInterval time.Duration, idleTimeout time.Duration) http.Handler {
+	upgrader := websocket.Upgrader{
+		// Any origin is allowed, just like in rpc.Server.WebsocketHandler.
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format, err := parseBigNumberFormat(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rpcServer, err := newRPCServer(r.RemoteAddr)
+		if err != nil {
+			log.WithError(err).Error("could not create RPC server for WebSocket connection")
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		defer rpcServer.Stop()
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.WithError(err).Debug("could not upgrade WebSocket connection")
+			return
+		}
+		conn.SetReadLimit(wsReadLimit)
+
+		extendReadDeadline := func() {
+			if idleTimeout > 0 {
+				_ = conn.SetReadDeadline(time.Now().Add(idleTimeout))
+			}
+		}
+		extendReadDeadline()
+		conn.SetPongHandler(func(string) error {
+			extendReadDeadline()
+			return nil
+		})
+		readJSON := func(v interface{}) error {
+			if err := conn.ReadJSON(v); err != nil {
+				return err
+			}
+			extendReadDeadline()
+			return nil
+		}
+
+		done := make(chan struct{})
+		defer close(done)
+		if pingInterval > 0 {
+			go pingPeriodically(conn, pingInterval, done)
+		}
+		// Stopping the server closes the connection.
+		go func() {
+			select {
+			case <-ctx.Done():
+				rpcServer.Stop()
+			case <-done:
+			}
+		}()
+
+		writeJSON := conn.WriteJSON
+		if needsFormatting(format) {
+			writeJSON = func(v interface{}) error {
+				message, err := json.Marshal(v)
+				if err != nil {
+					return err
+				}
+				message, err = formatMessage(message, format)
+				if err != nil {
+					return err
+				}
+				return conn.WriteMessage(websocket.TextMessage, message)
+			}
+		}
+
+		codec := rpc.NewFuncCodec(conn, writeJSON, readJSON)
+		rpcServer.ServeCodec(codec, rpc.OptionMethodInvocation|rpc.OptionSubscriptions)
+	})
+}
+
+// pingPeriodically sends a ping frame over conn every pingInterval until done
+// is closed or sending a ping fails.
+func pingPeriodically(conn *websocket.Conn, pingInterval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			// WriteControl can be called concurrently with the writes done by
+			// the codec.
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsPingWriteTimeout)); err != nil {
+				log.WithError(err).Trace("could not send WebSocket ping")
+				return
+			}
+		}
+	}
+}
@@ -64,6 +64,13 @@ func TakerAssetAmount(amount *big.Int) Option {
 	}
 }
 
+func Salt(salt *big.Int) Option {
+	return func(cfg *Config) error {
+		cfg.Order.Salt = salt
+		return nil
+	}
+}
+
 func ExpirationTimeSeconds(expirationTimeSeconds *big.Int) Option {
 	return func(cfg *Config) error {
 		cfg.Order.ExpirationTimeSeconds = expirationTimeSeconds
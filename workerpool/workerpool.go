@@ -0,0 +1,103 @@
+// Package workerpool offers a fixed-size pool of goroutines for CPU-bound work
+// such as computing order hashes and recovering signatures. Using a pool
+// bounds the number of such tasks which run concurrently while still making
+// use of all available cores.
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ErrClosed is returned by Do if the pool has been closed.
+var ErrClosed = errors.New("worker pool is closed")
+
+type task struct {
+	ctx  context.Context
+	fn   func()
+	done chan struct{}
+}
+
+// Pool is a fixed-size pool of worker goroutines.
+type Pool struct {
+	size      int
+	tasks     chan *task
+	closeOnce sync.Once
+	closed    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// New creates a new pool with the given number of workers and starts them. If
+// size is 0 or negative, runtime.GOMAXPROCS(0) workers are started.
+func New(size int) *Pool {
+	if size <= 0 {
+		size = runtime.GOMAXPROCS(0)
+	}
+	p := &Pool{
+		size:   size,
+		tasks:  make(chan *task),
+		closed: make(chan struct{}),
+	}
+	p.wg.Add(size)
+	for i := 0; i < size; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Size returns the number of workers in the pool.
+func (p *Pool) Size() int {
+	return p.size
+}
+
+// Do runs fn on one of the workers and blocks until it returns. If the given
+// context is done before fn returns, Do returns ctx.Err() without waiting. In
+// that case fn might still be running or might not run at all, so any results
+// written by fn must not be used.
+func (p *Pool) Do(ctx context.Context, fn func()) error {
+	t := &task{
+		ctx:  ctx,
+		fn:   fn,
+		done: make(chan struct{}),
+	}
+	select {
+	case p.tasks <- t:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.closed:
+		return ErrClosed
+	}
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops all workers after they have finished their current tasks.
+// Subsequent calls to Do return ErrClosed.
+func (p *Pool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+	})
+	p.wg.Wait()
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.closed:
+			return
+		case t := <-p.tasks:
+			// Don't bother running the task if the caller already gave up.
+			if t.ctx.Err() == nil {
+				t.fn()
+			}
+			close(t.done)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package workerpool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolRunsTasksConcurrently(t *testing.T) {
+	pool := New(4)
+	defer pool.Close()
+	assert.Equal(t, 4, pool.Size())
+
+	var running, maxRunning int32
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := pool.Do(context.Background(), func() {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					max := atomic.LoadInt32(&maxRunning)
+					if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+			})
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.True(t, atomic.LoadInt32(&maxRunning) <= 4, "no more than 4 tasks should run at once")
+	assert.True(t, atomic.LoadInt32(&maxRunning) > 1, "tasks should run concurrently")
+}
+
+func TestPoolDoRespectsDeadline(t *testing.T) {
+	pool := New(1)
+	defer pool.Close()
+
+	// Occupy the only worker.
+	unblock := make(chan struct{})
+	go func() {
+		_ = pool.Do(context.Background(), func() { <-unblock })
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	ran := false
+	err := pool.Do(ctx, func() { ran = true })
+	assert.Equal(t, context.DeadlineExceeded, err)
+	close(unblock)
+	assert.False(t, ran)
+}
+
+func TestPoolDoAfterClose(t *testing.T) {
+	pool := New(2)
+	pool.Close()
+	err := pool.Do(context.Background(), func() {})
+	assert.Equal(t, ErrClosed, err)
+}
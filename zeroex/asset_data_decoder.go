@@ -185,8 +185,10 @@ func (a *AssetDataDecoder) GetName(assetData []byte) (string, error) {
 	return info.name, nil
 }
 
-// Decode decodes an encoded asset data into it's sub-components
-func (a *AssetDataDecoder) Decode(assetData []byte, decodedAssetData interface{}) error {
+// Decode decodes an encoded asset data into it's sub-components. Asset data is
+// received from untrusted peers, so any panic in the ABI decoder caused by
+// malformed input is returned as an error.
+func (a *AssetDataDecoder) Decode(assetData []byte, decodedAssetData interface{}) (err error) {
 	if len(assetData) < 4 {
 		return errors.New("assetData must be at least 4 bytes long")
 	}
@@ -201,10 +203,70 @@ func (a *AssetDataDecoder) Decode(assetData []byte, decodedAssetData interface{}
 	if info.abi.Methods[info.name].Inputs.LengthNonIndexed() == 0 {
 		return nil
 	}
-	err := info.abi.Methods[info.name].Inputs.Unpack(decodedAssetData, assetData[4:])
-	if err != nil {
-		return err
+	// ABI-encoded arguments always consist of 32 byte words, so anything else
+	// can be rejected before it reaches the ABI decoder.
+	if (len(assetData)-4)%32 != 0 {
+		return fmt.Errorf("assetData with prefix %s is not a multiple of 32 bytes long", idHex)
 	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("could not decode assetData with prefix %s: %v", idHex, r)
+		}
+	}()
+	return info.abi.Methods[info.name].Inputs.Unpack(decodedAssetData, assetData[4:])
+}
+
+var (
+	// ErrAssetDataTooLarge is returned by CheckLimits if the asset data or any
+	// nested asset data exceeds the maximum size.
+	ErrAssetDataTooLarge = errors.New("assetData exceeds the maximum size")
+	// ErrMultiAssetNestingTooDeep is returned by CheckLimits if MultiAsset
+	// asset data is nested deeper than the maximum nesting depth.
+	ErrMultiAssetNestingTooDeep = errors.New("MultiAsset assetData exceeds the maximum nesting depth")
+)
+
+// AssetDataLimits are limits on the size and structure of asset data which are
+// checked before it is decoded, so that maliciously crafted asset data can't
+// be used to exhaust CPU or memory.
+type AssetDataLimits struct {
+	// MaxSizeInBytes is the maximum length of encoded asset data. If 0, the
+	// size is not limited.
+	MaxSizeInBytes int
+	// MaxMultiAssetNestingDepth is the maximum number of MultiAsset asset data
+	// nested in each other. A MultiAsset which only contains other types of
+	// asset data has a nesting depth of 1. If 0, the nesting depth is not
+	// limited.
+	MaxMultiAssetNestingDepth int
+}
+
+// CheckLimits returns ErrAssetDataTooLarge or ErrMultiAssetNestingTooDeep if the
+// given asset data exceeds the given limits. The size is checked before
+// anything is decoded and nested asset data is only decoded while the nesting
+// depth is within the limit. Any other decoding errors are ignored since they
+// are reported when the asset data is validated.
+func (a *AssetDataDecoder) CheckLimits(assetData []byte, limits AssetDataLimits) error {
+	return a.checkLimits(assetData, limits, 0)
+}
 
+func (a *AssetDataDecoder) checkLimits(assetData []byte, limits AssetDataLimits, depth int) error {
+	if limits.MaxSizeInBytes != 0 && len(assetData) > limits.MaxSizeInBytes {
+		return ErrAssetDataTooLarge
+	}
+	if len(assetData) < 4 || common.Bytes2Hex(assetData[:4]) != MultiAssetDataID {
+		return nil
+	}
+	depth++
+	if limits.MaxMultiAssetNestingDepth != 0 && depth > limits.MaxMultiAssetNestingDepth {
+		return ErrMultiAssetNestingTooDeep
+	}
+	var decodedAssetData MultiAssetData
+	if err := a.Decode(assetData, &decodedAssetData); err != nil {
+		return nil
+	}
+	for _, nestedAssetData := range decodedAssetData.NestedAssetData {
+		if err := a.checkLimits(nestedAssetData, limits, depth); err != nil {
+			return err
+		}
+	}
 	return nil
 }
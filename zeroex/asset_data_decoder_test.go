@@ -2,8 +2,10 @@ package zeroex
 
 import (
 	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -161,3 +163,91 @@ func TestDecodeERC20BridgeEth2DaiAssetData(t *testing.T) {
 	}
 	assert.Equal(t, expectedDecodedAssetData, actualDecodedAssetData, "ERC20Bridge Asset Data properly decoded")
 }
+
+func TestCheckLimits(t *testing.T) {
+	multiAssetDataABI, err := abi.JSON(strings.NewReader(multiAssetDataAbi))
+	require.NoError(t, err)
+	wrapInMultiAsset := func(nestedAssetData []byte) []byte {
+		assetData, err := multiAssetDataABI.Pack("MultiAsset", []*big.Int{big.NewInt(1)}, [][]byte{nestedAssetData})
+		require.NoError(t, err)
+		return assetData
+	}
+	erc20AssetData := common.Hex2Bytes("f47261b000000000000000000000000038ae374ecf4db50b0ff37125b591a04997106a32")
+	nestedOnce := wrapInMultiAsset(erc20AssetData)
+	nestedTwice := wrapInMultiAsset(nestedOnce)
+
+	d := NewAssetDataDecoder()
+
+	testCases := []struct {
+		name        string
+		assetData   []byte
+		limits      AssetDataLimits
+		expectedErr error
+	}{
+		{
+			name:      "no limits",
+			assetData: nestedTwice,
+			limits:    AssetDataLimits{},
+		},
+		{
+			name:      "ERC20 within size limit",
+			assetData: erc20AssetData,
+			limits:    AssetDataLimits{MaxSizeInBytes: len(erc20AssetData), MaxMultiAssetNestingDepth: 1},
+		},
+		{
+			name:        "ERC20 exceeding size limit",
+			assetData:   erc20AssetData,
+			limits:      AssetDataLimits{MaxSizeInBytes: len(erc20AssetData) - 1},
+			expectedErr: ErrAssetDataTooLarge,
+		},
+		{
+			name:      "MultiAsset within nesting limit",
+			assetData: nestedTwice,
+			limits:    AssetDataLimits{MaxMultiAssetNestingDepth: 2},
+		},
+		{
+			name:        "MultiAsset exceeding nesting limit",
+			assetData:   nestedTwice,
+			limits:      AssetDataLimits{MaxMultiAssetNestingDepth: 1},
+			expectedErr: ErrMultiAssetNestingTooDeep,
+		},
+		{
+			name:      "invalid MultiAsset is ignored",
+			assetData: common.Hex2Bytes("94cfcdd7"),
+			limits:    AssetDataLimits{MaxSizeInBytes: 100, MaxMultiAssetNestingDepth: 1},
+		},
+	}
+	for _, testCase := range testCases {
+		err := d.CheckLimits(testCase.assetData, testCase.limits)
+		assert.Equal(t, testCase.expectedErr, err, testCase.name)
+	}
+}
+
+func TestDecodeMalformedAssetData(t *testing.T) {
+	d := NewAssetDataDecoder()
+
+	testCases := []struct {
+		name      string
+		assetData []byte
+	}{
+		{
+			name:      "truncated ERC20 asset data",
+			assetData: common.Hex2Bytes("f47261b000000000000000000000000038ae374ecf4db50b0ff37125b591a049971"),
+		},
+		{
+			name:      "MultiAsset with offset beyond the end of the data",
+			assetData: common.Hex2Bytes("94cfcdd7" + "00000000000000000000000000000000000000000000000000000000000fffff" + "0000000000000000000000000000000000000000000000000000000000000040"),
+		},
+		{
+			name:      "MultiAsset with huge array length",
+			assetData: common.Hex2Bytes("94cfcdd7" + "0000000000000000000000000000000000000000000000000000000000000040" + "0000000000000000000000000000000000000000000000000000000000000060" + "4000000000000000000000000000000000000000000000000000000000000000"),
+		},
+	}
+	for _, testCase := range testCases {
+		var decodedAssetData MultiAssetData
+		assert.NotPanics(t, func() {
+			err := d.Decode(testCase.assetData, &decodedAssetData)
+			assert.Error(t, err, testCase.name)
+		}, testCase.name)
+	}
+}
@@ -0,0 +1,101 @@
+package zeroex
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/0xProject/0x-mesh/common/uint256"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	sharedAssetDataDecoderOnce sync.Once
+	sharedAssetDataDecoder     *AssetDataDecoder
+)
+
+// getSharedAssetDataDecoder returns an AssetDataDecoder which is shared by the
+// asset data encoders and OrderBuilder. It is created lazily because parsing
+// the ABIs is expensive.
+func getSharedAssetDataDecoder() *AssetDataDecoder {
+	sharedAssetDataDecoderOnce.Do(func() {
+		sharedAssetDataDecoder = NewAssetDataDecoder()
+	})
+	return sharedAssetDataDecoder
+}
+
+// EncodeERC20AssetData encodes the asset data for the ERC20 token at the given
+// address.
+func EncodeERC20AssetData(tokenAddress common.Address) ([]byte, error) {
+	return encodeAssetData(ERC20AssetDataID, tokenAddress)
+}
+
+// EncodeERC721AssetData encodes the asset data for the token with the given ID
+// of the ERC721 contract at the given address.
+func EncodeERC721AssetData(tokenAddress common.Address, tokenID *big.Int) ([]byte, error) {
+	if err := checkUint256("tokenID", tokenID); err != nil {
+		return nil, err
+	}
+	return encodeAssetData(ERC721AssetDataID, tokenAddress, tokenID)
+}
+
+// EncodeERC1155AssetData encodes the asset data for the given tokens of the
+// ERC1155 contract at the given address. values[i] is the amount of ids[i]
+// which is transferred per unit of the order's asset amount. callbackData is
+// passed to the receiver of the tokens and may be empty.
+func EncodeERC1155AssetData(tokenAddress common.Address, ids []*big.Int, values []*big.Int, callbackData []byte) ([]byte, error) {
+	if len(ids) != len(values) {
+		return nil, fmt.Errorf("ids and values must have the same length (got %d and %d)", len(ids), len(values))
+	}
+	for i := range ids {
+		if err := checkUint256(fmt.Sprintf("ids[%d]", i), ids[i]); err != nil {
+			return nil, err
+		}
+		if err := checkUint256(fmt.Sprintf("values[%d]", i), values[i]); err != nil {
+			return nil, err
+		}
+	}
+	if callbackData == nil {
+		callbackData = []byte{}
+	}
+	return encodeAssetData(ERC1155AssetDataID, tokenAddress, ids, values, callbackData)
+}
+
+// EncodeMultiAssetData encodes MultiAsset asset data, which transfers
+// amounts[i] times the order's asset amount of nestedAssetData[i].
+func EncodeMultiAssetData(amounts []*big.Int, nestedAssetData [][]byte) ([]byte, error) {
+	if len(amounts) != len(nestedAssetData) {
+		return nil, fmt.Errorf("amounts and nestedAssetData must have the same length (got %d and %d)", len(amounts), len(nestedAssetData))
+	}
+	if len(amounts) == 0 {
+		return nil, errors.New("MultiAsset asset data must contain at least one nested asset data")
+	}
+	decoder := getSharedAssetDataDecoder()
+	for i := range amounts {
+		if err := checkUint256(fmt.Sprintf("amounts[%d]", i), amounts[i]); err != nil {
+			return nil, err
+		}
+		if _, err := decoder.GetName(nestedAssetData[i]); err != nil {
+			return nil, fmt.Errorf("invalid nestedAssetData[%d]: %s", i, err.Error())
+		}
+	}
+	return encodeAssetData(MultiAssetDataID, amounts, nestedAssetData)
+}
+
+// encodeAssetData ABI-encodes args and prepends the asset data ID.
+func encodeAssetData(id string, args ...interface{}) ([]byte, error) {
+	info := getSharedAssetDataDecoder().idToAssetDataInfo[id]
+	encodedArgs, err := info.abi.Methods[info.name].Inputs.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	return append(common.Hex2Bytes(id), encodedArgs...), nil
+}
+
+func checkUint256(name string, value *big.Int) error {
+	if _, ok := uint256.FromBig(value); !ok {
+		return fmt.Errorf("%s must be a uint256", name)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package zeroex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeERC20AssetData(t *testing.T) {
+	expectedAssetData := common.Hex2Bytes("f47261b000000000000000000000000038ae374ecf4db50b0ff37125b591a04997106a32")
+	tokenAddress := common.HexToAddress("0x38ae374ecf4db50b0ff37125b591a04997106a32")
+
+	assetData, err := EncodeERC20AssetData(tokenAddress)
+	require.NoError(t, err)
+	assert.Equal(t, expectedAssetData, assetData)
+
+	var decodedAssetData ERC20AssetData
+	require.NoError(t, NewAssetDataDecoder().Decode(assetData, &decodedAssetData))
+	assert.Equal(t, tokenAddress, decodedAssetData.Address)
+}
+
+func TestEncodeERC721AssetData(t *testing.T) {
+	expectedAssetData := common.Hex2Bytes("025717920000000000000000000000001dc4c1cefef38a777b15aa20260a54e584b16c480000000000000000000000000000000000000000000000000000000000000001")
+
+	var decodedAssetData ERC721AssetData
+	require.NoError(t, NewAssetDataDecoder().Decode(expectedAssetData, &decodedAssetData))
+	assetData, err := EncodeERC721AssetData(decodedAssetData.Address, decodedAssetData.TokenId)
+	require.NoError(t, err)
+	assert.Equal(t, expectedAssetData, assetData)
+
+	_, err = EncodeERC721AssetData(decodedAssetData.Address, big.NewInt(-1))
+	assert.Error(t, err)
+}
+
+func TestEncodeERC1155AssetData(t *testing.T) {
+	expectedAssetData := common.Hex2Bytes("a7cb5fb70000000000000000000000001dc4c1cefef38a777b15aa20260a54e584b16c480000000000000000000000000000000000000000000000000000000000000080000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000001800000000000000000000000000000000000000000000000000000000000000003000000000000000000000000000000000000000000000000000000000000006400000000000000000000000000000000000000000000000000000000000003e90000000000000000000000000000000000000000000000000000000000002711000000000000000000000000000000000000000000000000000000000000000300000000000000000000000000000000000000000000000000000000000000c800000000000000000000000000000000000000000000000000000000000007d10000000000000000000000000000000000000000000000000000000000004e210000000000000000000000000000000000000000000000000000000000000044025717920000000000000000000000001dc4c1cefef38a777b15aa20260a54e584b16c48000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000")
+
+	var decodedAssetData ERC1155AssetData
+	require.NoError(t, NewAssetDataDecoder().Decode(expectedAssetData, &decodedAssetData))
+	assetData, err := EncodeERC1155AssetData(decodedAssetData.Address, decodedAssetData.Ids, decodedAssetData.Values, decodedAssetData.CallbackData)
+	require.NoError(t, err)
+	assert.Equal(t, expectedAssetData, assetData)
+
+	_, err = EncodeERC1155AssetData(decodedAssetData.Address, decodedAssetData.Ids, decodedAssetData.Values[1:], nil)
+	assert.Error(t, err)
+}
+
+func TestEncodeMultiAssetData(t *testing.T) {
+	expectedAssetData := common.Hex2Bytes("94cfcdd7000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c000000000000000000000000000000000000000000000000000000000000000030000000000000000000000000000000000000000000000000000000000000046000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000120000000000000000000000000000000000000000000000000000000000000003000000000000000000000000000000000000000000000000000000000000006000000000000000000000000000000000000000000000000000000000000000c000000000000000000000000000000000000000000000000000000000000001400000000000000000000000000000000000000000000000000000000000000024f47261b00000000000000000000000001dc4c1cefef38a777b15aa20260a54e584b16c48000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000044025717920000000000000000000000001dc4c1cefef38a777b15aa20260a54e584b16c480000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000204a7cb5fb70000000000000000000000001dc4c1cefef38a777b15aa20260a54e584b16c480000000000000000000000000000000000000000000000000000000000000080000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000001800000000000000000000000000000000000000000000000000000000000000003000000000000000000000000000000000000000000000000000000000000006400000000000000000000000000000000000000000000000000000000000003e90000000000000000000000000000000000000000000000000000000000002711000000000000000000000000000000000000000000000000000000000000000300000000000000000000000000000000000000000000000000000000000000c800000000000000000000000000000000000000000000000000000000000007d10000000000000000000000000000000000000000000000000000000000004e210000000000000000000000000000000000000000000000000000000000000044025717920000000000000000000000001dc4c1cefef38a777b15aa20260a54e584b16c4800000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000")
+
+	var decodedAssetData MultiAssetData
+	require.NoError(t, NewAssetDataDecoder().Decode(expectedAssetData, &decodedAssetData))
+	assetData, err := EncodeMultiAssetData(decodedAssetData.Amounts, decodedAssetData.NestedAssetData)
+	require.NoError(t, err)
+	assert.Equal(t, expectedAssetData, assetData)
+
+	// Nested asset data can be built with the other encoders.
+	erc20AssetData, err := EncodeERC20AssetData(common.HexToAddress("0x1dc4c1cefef38a777b15aa20260a54e584b16c48"))
+	require.NoError(t, err)
+	assert.Equal(t, decodedAssetData.NestedAssetData[0], erc20AssetData)
+	erc721AssetData, err := EncodeERC721AssetData(common.HexToAddress("0x1dc4c1cefef38a777b15aa20260a54e584b16c48"), big.NewInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, decodedAssetData.NestedAssetData[1], erc721AssetData)
+
+	_, err = EncodeMultiAssetData([]*big.Int{big.NewInt(1)}, [][]byte{common.Hex2Bytes("deadbeef")})
+	assert.Error(t, err)
+	_, err = EncodeMultiAssetData([]*big.Int{}, [][]byte{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package zeroex
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// String returns a human-readable representation of the asset data, e.g.
+// "ERC20(0x1dC4c1cEFEF38a777b15aA20260a54E584b16C48)".
+func (d ERC20AssetData) String() string {
+	return fmt.Sprintf("ERC20(%s)", d.Address.Hex())
+}
+
+// String returns a human-readable representation of the asset data, e.g.
+// "ERC721(0x1dC4c1cEFEF38a777b15aA20260a54E584b16C48, tokenId=1)".
+func (d ERC721AssetData) String() string {
+	return fmt.Sprintf("ERC721(%s, tokenId=%s)", d.Address.Hex(), bigIntString(d.TokenId))
+}
+
+// String returns a human-readable representation of the asset data.
+func (d ERC1155AssetData) String() string {
+	return fmt.Sprintf("ERC1155(%s, ids=%s, values=%s, callbackData=%s)", d.Address.Hex(), bigIntsString(d.Ids), bigIntsString(d.Values), common.ToHex(d.CallbackData))
+}
+
+// String returns a human-readable representation of the asset data.
+func (d ERC20BridgeAssetData) String() string {
+	return fmt.Sprintf("ERC20Bridge(%s, bridge=%s, bridgeData=%s)", d.TokenAddress.Hex(), d.BridgeAddress.Hex(), common.ToHex(d.BridgeData))
+}
+
+// String returns a human-readable representation of the asset data.
+func (d StaticCallAssetData) String() string {
+	return fmt.Sprintf("StaticCall(%s, data=%s, expectedReturnHash=%s)", d.StaticCallTargetAddress.Hex(), common.ToHex(d.StaticCallData), common.ToHex(d.ExpectedReturnHashData[:]))
+}
+
+// String returns a human-readable representation of the asset data. The
+// nested asset data is shown as hex. Use AssetDataDecoder.Describe to show it
+// in a human-readable form as well.
+func (d MultiAssetData) String() string {
+	nested := make([]string, len(d.NestedAssetData))
+	for i, nestedAssetData := range d.NestedAssetData {
+		nested[i] = common.ToHex(nestedAssetData)
+	}
+	return fmt.Sprintf("MultiAsset(amounts=%s, nestedAssetData=[%s])", bigIntsString(d.Amounts), strings.Join(nested, " "))
+}
+
+// AssetDataString returns a human-readable representation of the given asset
+// data, e.g. for logs and error messages. If the asset data cannot be decoded,
+// it is returned as hex.
+func AssetDataString(assetData []byte) string {
+	description, err := getSharedAssetDataDecoder().Describe(assetData)
+	if err != nil {
+		return common.ToHex(assetData)
+	}
+	return description
+}
+
+// maxDescribeNestingDepth is the maximum MultiAsset nesting depth which
+// AssetDataDecoder.Describe describes, so that maliciously nested asset data
+// can't be used to exhaust the stack.
+const maxDescribeNestingDepth = 8
+
+// Describe decodes the given asset data and returns a human-readable
+// representation of it. Nested MultiAsset asset data is described recursively,
+// e.g. "MultiAsset(70 x ERC20(0x...), 1 x ERC721(0x..., tokenId=1))".
+func (a *AssetDataDecoder) Describe(assetData []byte) (string, error) {
+	return a.describe(assetData, 0)
+}
+
+func (a *AssetDataDecoder) describe(assetData []byte, depth int) (string, error) {
+	name, err := a.GetName(assetData)
+	if err != nil {
+		return "", err
+	}
+	switch common.Bytes2Hex(assetData[:4]) {
+	case ERC20AssetDataID:
+		var decoded ERC20AssetData
+		if err := a.Decode(assetData, &decoded); err != nil {
+			return "", err
+		}
+		return decoded.String(), nil
+	case ERC721AssetDataID:
+		var decoded ERC721AssetData
+		if err := a.Decode(assetData, &decoded); err != nil {
+			return "", err
+		}
+		return decoded.String(), nil
+	case ERC1155AssetDataID:
+		var decoded ERC1155AssetData
+		if err := a.Decode(assetData, &decoded); err != nil {
+			return "", err
+		}
+		return decoded.String(), nil
+	case ERC20BridgeAssetDataID:
+		var decoded ERC20BridgeAssetData
+		if err := a.Decode(assetData, &decoded); err != nil {
+			return "", err
+		}
+		return decoded.String(), nil
+	case StaticCallAssetDataID:
+		var decoded StaticCallAssetData
+		if err := a.Decode(assetData, &decoded); err != nil {
+			return "", err
+		}
+		return decoded.String(), nil
+	case CheckGasPriceID:
+		var decoded CheckGasPriceStaticCallData
+		if err := a.Decode(assetData, &decoded); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("checkGasPrice(maxGasPrice=%s)", bigIntString(decoded.MaxGasPrice)), nil
+	case MultiAssetDataID:
+		if depth >= maxDescribeNestingDepth {
+			return "", ErrMultiAssetNestingTooDeep
+		}
+		var decoded MultiAssetData
+		if err := a.Decode(assetData, &decoded); err != nil {
+			return "", err
+		}
+		if len(decoded.Amounts) != len(decoded.NestedAssetData) {
+			return "", fmt.Errorf("MultiAsset has %d amounts but %d nested asset data", len(decoded.Amounts), len(decoded.NestedAssetData))
+		}
+		nested := make([]string, len(decoded.NestedAssetData))
+		for i, nestedAssetData := range decoded.NestedAssetData {
+			description, err := a.describe(nestedAssetData, depth+1)
+			if err != nil {
+				return "", fmt.Errorf("invalid nested asset data at index %d: %s", i, err.Error())
+			}
+			nested[i] = fmt.Sprintf("%s x %s", bigIntString(decoded.Amounts[i]), description)
+		}
+		return fmt.Sprintf("MultiAsset(%s)", strings.Join(nested, ", ")), nil
+	default:
+		return name + "()", nil
+	}
+}
+
+func bigIntString(x *big.Int) string {
+	if x == nil {
+		return "<nil>"
+	}
+	return x.String()
+}
+
+func bigIntsString(xs []*big.Int) string {
+	strs := make([]string, len(xs))
+	for i, x := range xs {
+		strs[i] = bigIntString(x)
+	}
+	return "[" + strings.Join(strs, " ") + "]"
+}
@@ -0,0 +1,41 @@
+package zeroex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetDataString(t *testing.T) {
+	tokenAddress := common.HexToAddress("0x1dc4c1cefef38a777b15aa20260a54e584b16c48")
+	erc20AssetData, err := EncodeERC20AssetData(tokenAddress)
+	require.NoError(t, err)
+	erc721AssetData, err := EncodeERC721AssetData(tokenAddress, big.NewInt(1))
+	require.NoError(t, err)
+	multiAssetData, err := EncodeMultiAssetData([]*big.Int{big.NewInt(70), big.NewInt(1)}, [][]byte{erc20AssetData, erc721AssetData})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ERC20(0x1dC4c1cEFEF38a777b15aA20260a54E584b16C48)", AssetDataString(erc20AssetData))
+	assert.Equal(t, "ERC721(0x1dC4c1cEFEF38a777b15aA20260a54E584b16C48, tokenId=1)", AssetDataString(erc721AssetData))
+	assert.Equal(t, "MultiAsset(70 x ERC20(0x1dC4c1cEFEF38a777b15aA20260a54E584b16C48), 1 x ERC721(0x1dC4c1cEFEF38a777b15aA20260a54E584b16C48, tokenId=1))", AssetDataString(multiAssetData))
+	assert.Equal(t, "checkGasPrice()", AssetDataString(common.Hex2Bytes(CheckGasPriceDefaultID)))
+
+	// Asset data which can't be decoded is shown as hex.
+	assert.Equal(t, "0xdeadbeef", AssetDataString(common.Hex2Bytes("deadbeef")))
+	_, err = NewAssetDataDecoder().Describe(common.Hex2Bytes("deadbeef"))
+	assert.Error(t, err)
+}
+
+func TestDescribeNestingLimit(t *testing.T) {
+	assetData, err := EncodeERC20AssetData(common.HexToAddress("0x1dc4c1cefef38a777b15aa20260a54e584b16c48"))
+	require.NoError(t, err)
+	for i := 0; i < maxDescribeNestingDepth+1; i++ {
+		assetData, err = EncodeMultiAssetData([]*big.Int{big.NewInt(1)}, [][]byte{assetData})
+		require.NoError(t, err)
+	}
+	_, err = NewAssetDataDecoder().Describe(assetData)
+	assert.Error(t, err)
+}
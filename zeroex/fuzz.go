@@ -0,0 +1,85 @@
+// +build gofuzz
+
+package zeroex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// This file contains harnesses for go-fuzz
+// (https://github.com/dvyukov/go-fuzz) for the decoders which parse data
+// received from peers. To run one of them:
+//
+//     go-fuzz-build -func FuzzDecodeAssetData github.com/0xProject/0x-mesh/zeroex
+//     go-fuzz -bin zeroex-fuzz.zip -workdir fuzz/assetdata
+//
+// To build a libFuzzer binary instead, pass -libfuzzer to go-fuzz-build and
+// link the resulting archive with clang -fsanitize=fuzzer.
+
+var fuzzAssetDataDecoder = NewAssetDataDecoder()
+
+// FuzzDecodeAssetData decodes the given data as asset data of the type
+// indicated by its prefix and checks it against the asset data limits.
+func FuzzDecodeAssetData(data []byte) int {
+	_ = fuzzAssetDataDecoder.CheckLimits(data, AssetDataLimits{MaxSizeInBytes: 4096, MaxMultiAssetNestingDepth: 2})
+	name, err := fuzzAssetDataDecoder.GetName(data)
+	if err != nil {
+		return 0
+	}
+	var decodedAssetData interface{}
+	switch name {
+	case "ERC20Token":
+		decodedAssetData = &ERC20AssetData{}
+	case "ERC721Token":
+		decodedAssetData = &ERC721AssetData{}
+	case "ERC1155Assets":
+		decodedAssetData = &ERC1155AssetData{}
+	case "StaticCall":
+		decodedAssetData = &StaticCallAssetData{}
+	case "checkGasPrice":
+		decodedAssetData = &CheckGasPriceStaticCallData{}
+	case "MultiAsset":
+		decodedAssetData = &MultiAssetData{}
+	case "ERC20Bridge":
+		decodedAssetData = &ERC20BridgeAssetData{}
+	default:
+		panic(fmt.Sprintf("no decoded type for asset data %q", name))
+	}
+	if err := fuzzAssetDataDecoder.Decode(data, decodedAssetData); err != nil {
+		return 0
+	}
+	return 1
+}
+
+// FuzzUnmarshalSignedOrderJSON decodes the given data as a signed order and
+// checks that the fast decoder agrees with encoding/json.
+func FuzzUnmarshalSignedOrderJSON(data []byte) int {
+	var standard SignedOrder
+	standardErr := standard.unmarshalJSONStandard(data)
+	var fast SignedOrder
+	if fast.unmarshalJSONFast(data) {
+		if standardErr != nil {
+			panic(fmt.Sprintf("fast decoder accepted input rejected by encoding/json: %s", standardErr))
+		}
+		standardJSON, err := json.Marshal(&standard)
+		if err != nil {
+			panic(err)
+		}
+		fastJSON, err := json.Marshal(&fast)
+		if err != nil {
+			panic(err)
+		}
+		if !bytes.Equal(standardJSON, fastJSON) {
+			panic(fmt.Sprintf("fast decoder result %s differs from encoding/json result %s", fastJSON, standardJSON))
+		}
+	}
+	if standardErr != nil {
+		return 0
+	}
+	// The order hash is computed for every order received from peers, so it
+	// must not panic for any decoded order.
+	_, _ = standard.ComputeOrderHash()
+	return 1
+}
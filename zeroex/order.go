@@ -5,16 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"strings"
 	"time"
 
+	"github.com/0xProject/0x-mesh/common/addressformat"
 	"github.com/0xProject/0x-mesh/ethereum/signer"
 	"github.com/0xProject/0x-mesh/ethereum/wrappers"
+	"github.com/0xProject/0x-mesh/zeroex/orderhash"
 	"github.com/0xProject/0x-mesh/zeroex/orderwatch/decoder"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
-	gethsigner "github.com/ethereum/go-ethereum/signer/core"
-	"golang.org/x/crypto/sha3"
 )
 
 // Order represents an unsigned 0x order
@@ -47,19 +46,19 @@ type SignedOrder struct {
 }
 
 // SignatureType represents the type of 0x signature encountered
-type SignatureType uint8
+type SignatureType = orderhash.SignatureType
 
 // SignatureType values
 const (
-	IllegalSignature SignatureType = iota
-	InvalidSignature
-	EIP712Signature
-	EthSignSignature
-	WalletSignature
-	ValidatorSignature
-	PreSignedSignature
-	EIP1271WalletSignature
-	NSignatureTypesSignature
+	IllegalSignature         = orderhash.IllegalSignature
+	InvalidSignature         = orderhash.InvalidSignature
+	EIP712Signature          = orderhash.EIP712Signature
+	EthSignSignature         = orderhash.EthSignSignature
+	WalletSignature          = orderhash.WalletSignature
+	ValidatorSignature       = orderhash.ValidatorSignature
+	PreSignedSignature       = orderhash.PreSignedSignature
+	EIP1271WalletSignature   = orderhash.EIP1271WalletSignature
+	NSignatureTypesSignature = orderhash.NSignatureTypesSignature
 )
 
 // OrderStatus represents the status of an order as returned from the 0x smart contracts
@@ -92,6 +91,30 @@ type ContractEvent struct {
 	Parameters interface{}
 }
 
+// ContractEventKinds are the kinds of contract events which are decoded by
+// Mesh.
+var ContractEventKinds = []string{
+	"ERC20TransferEvent",
+	"ERC20ApprovalEvent",
+	"ERC721TransferEvent",
+	"ERC721ApprovalEvent",
+	"ERC721ApprovalForAllEvent",
+	"ERC1155TransferSingleEvent",
+	"ERC1155TransferBatchEvent",
+	"ERC1155ApprovalForAllEvent",
+	"WethWithdrawalEvent",
+	"WethDepositEvent",
+	"ExchangeFillEvent",
+	"ExchangeCancelEvent",
+	"ExchangeCancelUpToEvent",
+	"ExchangeTransactionExecutionEvent",
+	"ExchangeSignatureValidatorApprovalEvent",
+	"ExchangeAssetProxyRegisteredEvent",
+	"ExchangeProtocolFeeMultiplierEvent",
+	"ExchangeProtocolFeeCollectorAddressEvent",
+	"CustomEvent",
+}
+
 type contractEventJSON struct {
 	BlockHash  common.Hash
 	TxHash     common.Hash
@@ -111,7 +134,7 @@ func (c ContractEvent) MarshalJSON() ([]byte, error) {
 		"txIndex":    c.TxIndex,
 		"logIndex":   c.LogIndex,
 		"isRemoved":  c.IsRemoved,
-		"address":    c.Address,
+		"address":    addressformat.String(c.Address),
 		"kind":       c.Kind,
 		"parameters": c.Parameters,
 	}
@@ -139,27 +162,80 @@ type OrderEvent struct {
 	// They did not all necessarily cause the orders state change itself, only it's re-evaluation.
 	// Since it's state _did_ change, at least one of them did cause the actual state change.
 	ContractEvents []*ContractEvent `json:"contractEvents"`
+	// Supersedes is set if this order event was caused by a block re-org which
+	// removed the blocks that caused earlier order events for the same order
+	// (e.g. a fill which was reverted). It contains those earlier order events,
+	// so that consumers can precisely unwind them.
+	Supersedes []*SupersededOrderEvent `json:"supersedes"`
+	// ReplacedBy is the hash of the order which replaced this order. It is
+	// only set if EndState is ESOrderReplaced.
+	ReplacedBy common.Hash `json:"replacedBy,omitempty"`
+	// Metadata is the opaque metadata which was attached to the order when it
+	// was added via AddOrders, e.g. a client order ID. It is only stored
+	// locally and never shared with peers.
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+	// BlockNumber, BlockHash and BlockTimestamp identify the latest block that
+	// Mesh had processed when the order event was generated, i.e. the block at
+	// which the new state of the order was observed. They are not set if Mesh
+	// had not processed any blocks yet.
+	BlockNumber    *big.Int    `json:"blockNumber,omitempty"`
+	BlockHash      common.Hash `json:"blockHash,omitempty"`
+	BlockTimestamp time.Time   `json:"blockTimestamp,omitempty"`
+	// SequenceNumber is assigned by the node when the order event is emitted.
+	// Sequence numbers start at 1 and increase by one for each order event
+	// over the lifetime of the node's database, so consumers can use them to
+	// detect missed order events and to process each order event exactly
+	// once. It is 0 for order events which were not emitted by the order
+	// watcher (e.g. EVENTS_DROPPED).
+	SequenceNumber uint64 `json:"sequenceNumber,omitempty"`
 }
 
 type orderEventJSON struct {
-	Timestamp                time.Time            `json:"timestamp"`
-	OrderHash                string               `json:"orderHash"`
-	SignedOrder              *SignedOrder         `json:"signedOrder"`
-	EndState                 string               `json:"endState"`
-	FillableTakerAssetAmount string               `json:"fillableTakerAssetAmount"`
-	ContractEvents           []*contractEventJSON `json:"contractEvents"`
+	Timestamp                time.Time                   `json:"timestamp"`
+	OrderHash                string                      `json:"orderHash"`
+	SignedOrder              *SignedOrder                `json:"signedOrder"`
+	EndState                 string                      `json:"endState"`
+	FillableTakerAssetAmount string                      `json:"fillableTakerAssetAmount"`
+	ContractEvents           []*contractEventJSON        `json:"contractEvents"`
+	Supersedes               []*supersededOrderEventJSON `json:"supersedes"`
+	ReplacedBy               string                      `json:"replacedBy"`
+	Metadata                 json.RawMessage             `json:"metadata"`
+	BlockNumber              *big.Int                    `json:"blockNumber"`
+	BlockHash                string                      `json:"blockHash"`
+	BlockTimestamp           time.Time                   `json:"blockTimestamp"`
+	SequenceNumber           uint64                      `json:"sequenceNumber"`
 }
 
 // MarshalJSON implements a custom JSON marshaller for the OrderEvent type
 func (o OrderEvent) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]interface{}{
+	supersedes := o.Supersedes
+	if supersedes == nil {
+		supersedes = []*SupersededOrderEvent{}
+	}
+	orderEventJSON := map[string]interface{}{
 		"timestamp":                o.Timestamp,
 		"orderHash":                o.OrderHash.Hex(),
 		"signedOrder":              o.SignedOrder,
 		"endState":                 o.EndState,
 		"fillableTakerAssetAmount": o.FillableTakerAssetAmount.String(),
 		"contractEvents":           o.ContractEvents,
-	})
+		"supersedes":               supersedes,
+	}
+	if o.ReplacedBy != (common.Hash{}) {
+		orderEventJSON["replacedBy"] = o.ReplacedBy.Hex()
+	}
+	if len(o.Metadata) != 0 {
+		orderEventJSON["metadata"] = o.Metadata
+	}
+	if o.BlockNumber != nil {
+		orderEventJSON["blockNumber"] = o.BlockNumber
+		orderEventJSON["blockHash"] = o.BlockHash.Hex()
+		orderEventJSON["blockTimestamp"] = o.BlockTimestamp
+	}
+	if o.SequenceNumber != 0 {
+		orderEventJSON["sequenceNumber"] = o.SequenceNumber
+	}
+	return json.Marshal(orderEventJSON)
 }
 
 // UnmarshalJSON implements a custom JSON unmarshaller for the OrderEvent type
@@ -177,6 +253,16 @@ func (o *OrderEvent) fromOrderEventJSON(orderEventJSON orderEventJSON) error {
 	o.OrderHash = common.HexToHash(orderEventJSON.OrderHash)
 	o.SignedOrder = orderEventJSON.SignedOrder
 	o.EndState = OrderEventEndState(orderEventJSON.EndState)
+	if orderEventJSON.ReplacedBy != "" {
+		o.ReplacedBy = common.HexToHash(orderEventJSON.ReplacedBy)
+	}
+	o.Metadata = orderEventJSON.Metadata
+	o.SequenceNumber = orderEventJSON.SequenceNumber
+	if orderEventJSON.BlockNumber != nil {
+		o.BlockNumber = orderEventJSON.BlockNumber
+		o.BlockHash = common.HexToHash(orderEventJSON.BlockHash)
+		o.BlockTimestamp = orderEventJSON.BlockTimestamp
+	}
 	var ok bool
 	o.FillableTakerAssetAmount, ok = math.ParseBig256(orderEventJSON.FillableTakerAssetAmount)
 	if !ok {
@@ -190,6 +276,70 @@ func (o *OrderEvent) fromOrderEventJSON(orderEventJSON orderEventJSON) error {
 		}
 		o.ContractEvents[i] = contractEvent
 	}
+	if len(orderEventJSON.Supersedes) > 0 {
+		o.Supersedes = make([]*SupersededOrderEvent, len(orderEventJSON.Supersedes))
+		for i, supersededJSON := range orderEventJSON.Supersedes {
+			superseded := &SupersededOrderEvent{}
+			if err := superseded.fromSupersededOrderEventJSON(supersededJSON); err != nil {
+				return err
+			}
+			o.Supersedes[i] = superseded
+		}
+	}
+	return nil
+}
+
+// SupersededOrderEvent is an order event which was caused by contract events
+// in a block that has since been removed by a block re-org.
+type SupersededOrderEvent struct {
+	// BlockHash is the hash of the removed block.
+	BlockHash common.Hash `json:"blockHash"`
+	// Timestamp is the timestamp of the superseded order event.
+	Timestamp time.Time `json:"timestamp"`
+	// EndState is the end state of the superseded order event.
+	EndState OrderEventEndState `json:"endState"`
+	// FillableTakerAssetAmount is the fillable taker asset amount of the
+	// superseded order event.
+	FillableTakerAssetAmount *big.Int `json:"fillableTakerAssetAmount"`
+}
+
+type supersededOrderEventJSON struct {
+	BlockHash                common.Hash `json:"blockHash"`
+	Timestamp                time.Time   `json:"timestamp"`
+	EndState                 string      `json:"endState"`
+	FillableTakerAssetAmount string      `json:"fillableTakerAssetAmount"`
+}
+
+// MarshalJSON implements a custom JSON marshaller for the SupersededOrderEvent
+// type
+func (s SupersededOrderEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"blockHash":                s.BlockHash.Hex(),
+		"timestamp":                s.Timestamp,
+		"endState":                 s.EndState,
+		"fillableTakerAssetAmount": s.FillableTakerAssetAmount.String(),
+	})
+}
+
+// UnmarshalJSON implements a custom JSON unmarshaller for the
+// SupersededOrderEvent type
+func (s *SupersededOrderEvent) UnmarshalJSON(data []byte) error {
+	var supersededJSON supersededOrderEventJSON
+	if err := json.Unmarshal(data, &supersededJSON); err != nil {
+		return err
+	}
+	return s.fromSupersededOrderEventJSON(&supersededJSON)
+}
+
+func (s *SupersededOrderEvent) fromSupersededOrderEventJSON(supersededJSON *supersededOrderEventJSON) error {
+	s.BlockHash = supersededJSON.BlockHash
+	s.Timestamp = supersededJSON.Timestamp
+	s.EndState = OrderEventEndState(supersededJSON.EndState)
+	var ok bool
+	s.FillableTakerAssetAmount, ok = math.ParseBig256(supersededJSON.FillableTakerAssetAmount)
+	if !ok {
+		return errors.New("Invalid uint256 number encountered for FillableTakerAssetAmount")
+	}
 	return nil
 }
 
@@ -296,6 +446,48 @@ func unmarshalContractEvent(eventJSON *contractEventJSON) (*ContractEvent, error
 		}
 		event.Parameters = parameters
 
+	case "ExchangeTransactionExecutionEvent":
+		var parameters decoder.ExchangeTransactionExecutionEvent
+		if err := json.Unmarshal(eventJSON.Parameters, &parameters); err != nil {
+			return nil, err
+		}
+		event.Parameters = parameters
+
+	case "ExchangeSignatureValidatorApprovalEvent":
+		var parameters decoder.ExchangeSignatureValidatorApprovalEvent
+		if err := json.Unmarshal(eventJSON.Parameters, &parameters); err != nil {
+			return nil, err
+		}
+		event.Parameters = parameters
+
+	case "ExchangeAssetProxyRegisteredEvent":
+		var parameters decoder.ExchangeAssetProxyRegisteredEvent
+		if err := json.Unmarshal(eventJSON.Parameters, &parameters); err != nil {
+			return nil, err
+		}
+		event.Parameters = parameters
+
+	case "ExchangeProtocolFeeMultiplierEvent":
+		var parameters decoder.ExchangeProtocolFeeMultiplierEvent
+		if err := json.Unmarshal(eventJSON.Parameters, &parameters); err != nil {
+			return nil, err
+		}
+		event.Parameters = parameters
+
+	case "ExchangeProtocolFeeCollectorAddressEvent":
+		var parameters decoder.ExchangeProtocolFeeCollectorAddressEvent
+		if err := json.Unmarshal(eventJSON.Parameters, &parameters); err != nil {
+			return nil, err
+		}
+		event.Parameters = parameters
+
+	case "CustomEvent":
+		var parameters decoder.CustomEvent
+		if err := json.Unmarshal(eventJSON.Parameters, &parameters); err != nil {
+			return nil, err
+		}
+		event.Parameters = parameters
+
 	default:
 		return nil, fmt.Errorf("unknown event kind: %s", eventJSON.Kind)
 	}
@@ -336,141 +528,62 @@ const (
 	// and no further events for this order will be emitted. In some cases, the order may be re-added in the
 	// future.
 	ESStoppedWatching = OrderEventEndState("STOPPED_WATCHING")
+	// ESOrderReplaced means an order was removed because the maker replaced it
+	// with a newer order for the same asset pair (see ReplacedBy). The order
+	// may still be fillable on-chain, but it will no longer be watched.
+	ESOrderReplaced = OrderEventEndState("REPLACED")
+	// ESEventsDropped is not emitted for a specific order. It is sent to an
+	// order event subscriber whose subscription queue overflowed, and means
+	// that some order events were dropped and never sent to the subscriber.
+	// The subscriber should resync its orders, e.g. via GetOrders.
+	ESEventsDropped = OrderEventEndState("EVENTS_DROPPED")
 )
 
-var eip712OrderTypes = gethsigner.Types{
-	"EIP712Domain": {
-		{
-			Name: "name",
-			Type: "string",
-		},
-		{
-			Name: "version",
-			Type: "string",
-		},
-		{
-			Name: "chainId",
-			Type: "uint256",
-		},
-		{
-			Name: "verifyingContract",
-			Type: "address",
-		},
-	},
-	"Order": {
-		{
-			Name: "makerAddress",
-			Type: "address",
-		},
-		{
-			Name: "takerAddress",
-			Type: "address",
-		},
-		{
-			Name: "feeRecipientAddress",
-			Type: "address",
-		},
-		{
-			Name: "senderAddress",
-			Type: "address",
-		},
-		{
-			Name: "makerAssetAmount",
-			Type: "uint256",
-		},
-		{
-			Name: "takerAssetAmount",
-			Type: "uint256",
-		},
-		{
-			Name: "makerFee",
-			Type: "uint256",
-		},
-		{
-			Name: "takerFee",
-			Type: "uint256",
-		},
-		{
-			Name: "expirationTimeSeconds",
-			Type: "uint256",
-		},
-		{
-			Name: "salt",
-			Type: "uint256",
-		},
-		{
-			Name: "makerAssetData",
-			Type: "bytes",
-		},
-		{
-			Name: "takerAssetData",
-			Type: "bytes",
-		},
-		{
-			Name: "makerFeeAssetData",
-			Type: "bytes",
-		},
-		{
-			Name: "takerFeeAssetData",
-			Type: "bytes",
-		},
-	},
-}
-
 // ResetHash resets the cached order hash. Usually only required for testing.
 func (o *Order) ResetHash() {
 	o.hash = nil
 }
 
+// IsTakerRestricted returns true if the order can only be filled by a specific
+// taker, i.e. if its TakerAddress is not the null address.
+func (o *Order) IsTakerRestricted() bool {
+	return o.TakerAddress != common.Address{}
+}
+
+// IsSenderRestricted returns true if the order can only be submitted to the
+// Exchange contract by a specific sender (e.g. a Coordinator contract), i.e.
+// if its SenderAddress is not the null address.
+func (o *Order) IsSenderRestricted() bool {
+	return o.SenderAddress != common.Address{}
+}
+
 // ComputeOrderHash computes a 0x order hash
 func (o *Order) ComputeOrderHash() (common.Hash, error) {
 	if o.hash != nil {
 		return *o.hash, nil
 	}
 
-	chainID := math.NewHexOrDecimal256(o.ChainID.Int64())
-	var domain = gethsigner.TypedDataDomain{
-		Name:              "0x Protocol",
-		Version:           "3.0.0",
-		ChainId:           chainID,
-		VerifyingContract: o.ExchangeAddress.Hex(),
-	}
-
-	var message = map[string]interface{}{
-		"makerAddress":          o.MakerAddress.Hex(),
-		"takerAddress":          o.TakerAddress.Hex(),
-		"senderAddress":         o.SenderAddress.Hex(),
-		"feeRecipientAddress":   o.FeeRecipientAddress.Hex(),
-		"makerAssetData":        o.MakerAssetData,
-		"makerFeeAssetData":     o.MakerFeeAssetData,
-		"takerAssetData":        o.TakerAssetData,
-		"takerFeeAssetData":     o.TakerFeeAssetData,
-		"salt":                  o.Salt.String(),
-		"makerFee":              o.MakerFee.String(),
-		"takerFee":              o.TakerFee.String(),
-		"makerAssetAmount":      o.MakerAssetAmount.String(),
-		"takerAssetAmount":      o.TakerAssetAmount.String(),
-		"expirationTimeSeconds": o.ExpirationTimeSeconds.String(),
-	}
-
-	var typedData = gethsigner.TypedData{
-		Types:       eip712OrderTypes,
-		PrimaryType: "Order",
-		Domain:      domain,
-		Message:     message,
-	}
-
-	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
-	if err != nil {
-		return common.Hash{}, err
-	}
-	typedDataHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	hash, err := orderhash.Compute(&orderhash.Order{
+		ChainID:               o.ChainID,
+		ExchangeAddress:       o.ExchangeAddress,
+		MakerAddress:          o.MakerAddress,
+		MakerAssetData:        o.MakerAssetData,
+		MakerFeeAssetData:     o.MakerFeeAssetData,
+		MakerAssetAmount:      o.MakerAssetAmount,
+		MakerFee:              o.MakerFee,
+		TakerAddress:          o.TakerAddress,
+		TakerAssetData:        o.TakerAssetData,
+		TakerFeeAssetData:     o.TakerFeeAssetData,
+		TakerAssetAmount:      o.TakerAssetAmount,
+		TakerFee:              o.TakerFee,
+		SenderAddress:         o.SenderAddress,
+		FeeRecipientAddress:   o.FeeRecipientAddress,
+		ExpirationTimeSeconds: o.ExpirationTimeSeconds,
+		Salt:                  o.Salt,
+	})
 	if err != nil {
 		return common.Hash{}, err
 	}
-	rawData := []byte(fmt.Sprintf("\x19\x01%s%s", string(domainSeparator), string(typedDataHash)))
-	hashBytes := keccak256(rawData)
-	hash := common.BytesToHash(hashBytes)
 	o.hash = &hash
 	return hash, nil
 }
@@ -583,19 +696,19 @@ func (s SignedOrder) MarshalJSON() ([]byte, error) {
 
 	signedOrderBytes, err := json.Marshal(SignedOrderJSON{
 		ChainID:               s.ChainID.Int64(),
-		ExchangeAddress:       strings.ToLower(s.ExchangeAddress.Hex()),
-		MakerAddress:          strings.ToLower(s.MakerAddress.Hex()),
+		ExchangeAddress:       addressformat.String(s.ExchangeAddress),
+		MakerAddress:          addressformat.String(s.MakerAddress),
 		MakerAssetData:        makerAssetData,
 		MakerFeeAssetData:     makerFeeAssetData,
 		MakerAssetAmount:      s.MakerAssetAmount.String(),
 		MakerFee:              s.MakerFee.String(),
-		TakerAddress:          strings.ToLower(s.TakerAddress.Hex()),
+		TakerAddress:          addressformat.String(s.TakerAddress),
 		TakerAssetData:        takerAssetData,
 		TakerFeeAssetData:     takerFeeAssetData,
 		TakerAssetAmount:      s.TakerAssetAmount.String(),
 		TakerFee:              s.TakerFee.String(),
-		SenderAddress:         strings.ToLower(s.SenderAddress.Hex()),
-		FeeRecipientAddress:   strings.ToLower(s.FeeRecipientAddress.Hex()),
+		SenderAddress:         addressformat.String(s.SenderAddress),
+		FeeRecipientAddress:   addressformat.String(s.FeeRecipientAddress),
 		ExpirationTimeSeconds: s.ExpirationTimeSeconds.String(),
 		Salt:                  s.Salt.String(),
 		Signature:             signature,
@@ -607,6 +720,18 @@ const addressHexLength = 42
 
 // UnmarshalJSON implements a custom JSON unmarshaller for the SignedOrder type
 func (s *SignedOrder) UnmarshalJSON(data []byte) error {
+	// Orders are decoded whenever they are received from peers, so we try a
+	// faster decoder first and only fall back to encoding/json if the input
+	// is not supported by it.
+	if s.unmarshalJSONFast(data) {
+		return nil
+	}
+	return s.unmarshalJSONStandard(data)
+}
+
+// unmarshalJSONStandard decodes the JSON representation of a SignedOrder using
+// encoding/json.
+func (s *SignedOrder) unmarshalJSONStandard(data []byte) error {
 	var signedOrderJSON SignedOrderJSON
 	err := json.Unmarshal(data, &signedOrderJSON)
 	if err != nil {
@@ -662,12 +787,3 @@ func (s *SignedOrder) UnmarshalJSON(data []byte) error {
 	s.Signature = common.FromHex(signedOrderJSON.Signature)
 	return nil
 }
-
-// keccak256 calculates and returns the Keccak256 hash of the input data.
-func keccak256(data ...[]byte) []byte {
-	d := sha3.NewLegacyKeccak256()
-	for _, b := range data {
-		_, _ = d.Write(b)
-	}
-	return d.Sum(nil)
-}
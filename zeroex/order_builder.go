@@ -0,0 +1,307 @@
+package zeroex
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/0xProject/0x-mesh/common/uint256"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// maxRememberedSalts is the number of random salts a SaltGenerator remembers
+// in order to avoid collisions. Once it is reached, the remembered salts are
+// forgotten so that memory usage doesn't grow without bound.
+const maxRememberedSalts = 1 << 16
+
+// maxSalt is the exclusive upper bound for salts, which are uint256 values.
+var maxSalt = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// SaltGenerator generates order salts which are unique among the salts it has
+// generated. It is safe for concurrent use.
+type SaltGenerator struct {
+	mu        sync.Mutex
+	monotonic bool
+	last      *big.Int
+	used      map[string]struct{}
+}
+
+// NewRandomSaltGenerator returns a SaltGenerator which generates random 256-bit
+// salts. The most recently generated salts are never returned again.
+func NewRandomSaltGenerator() *SaltGenerator {
+	return &SaltGenerator{
+		used: map[string]struct{}{},
+	}
+}
+
+// NewMonotonicSaltGenerator returns a SaltGenerator which generates strictly
+// increasing salts. Salts are the current Unix time in milliseconds unless
+// that would not be greater than the last salt, so orders created later always
+// have higher salts. This makes it possible to cancel all orders created
+// before a certain time with a single call to cancelOrdersUpTo.
+func NewMonotonicSaltGenerator() *SaltGenerator {
+	return &SaltGenerator{
+		monotonic: true,
+		last:      big.NewInt(0),
+	}
+}
+
+// Next returns a new salt.
+func (g *SaltGenerator) Next() (*big.Int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.monotonic {
+		salt := big.NewInt(time.Now().UnixNano() / int64(time.Millisecond))
+		if salt.Cmp(g.last) <= 0 {
+			salt.Add(g.last, big.NewInt(1))
+		}
+		g.last = salt
+		return new(big.Int).Set(salt), nil
+	}
+	for {
+		salt, err := rand.Int(rand.Reader, maxSalt)
+		if err != nil {
+			return nil, err
+		}
+		key := salt.String()
+		if _, found := g.used[key]; found {
+			continue
+		}
+		if len(g.used) >= maxRememberedSalts {
+			g.used = map[string]struct{}{}
+		}
+		g.used[key] = struct{}{}
+		return salt, nil
+	}
+}
+
+// defaultSaltGenerator is used by OrderBuilder unless a salt or another
+// SaltGenerator is set.
+var defaultSaltGenerator = NewRandomSaltGenerator()
+
+// OrderBuilder constructs orders and checks them for the mistakes which most
+// commonly cause hand-constructed orders to be rejected. The zero values of
+// the taker, sender and fee recipient addresses and of the fees are used
+// unless they are set. Create one with NewOrder.
+type OrderBuilder struct {
+	order         Order
+	saltGenerator *SaltGenerator
+	chainTime     time.Time
+	expiresIn     time.Duration
+}
+
+// NewOrder returns an OrderBuilder for an order on the given chain and
+// Exchange contract.
+func NewOrder(chainID int, exchangeAddress common.Address) *OrderBuilder {
+	return &OrderBuilder{
+		order: Order{
+			ChainID:           big.NewInt(int64(chainID)),
+			ExchangeAddress:   exchangeAddress,
+			MakerFeeAssetData: []byte{},
+			MakerFee:          big.NewInt(0),
+			TakerFeeAssetData: []byte{},
+			TakerFee:          big.NewInt(0),
+		},
+		saltGenerator: defaultSaltGenerator,
+	}
+}
+
+// Maker sets the maker address.
+func (b *OrderBuilder) Maker(makerAddress common.Address) *OrderBuilder {
+	b.order.MakerAddress = makerAddress
+	return b
+}
+
+// MakerAsset sets the asset data and amount of the maker asset.
+func (b *OrderBuilder) MakerAsset(assetData []byte, amount *big.Int) *OrderBuilder {
+	b.order.MakerAssetData = assetData
+	b.order.MakerAssetAmount = amount
+	return b
+}
+
+// TakerAsset sets the asset data and amount of the taker asset.
+func (b *OrderBuilder) TakerAsset(assetData []byte, amount *big.Int) *OrderBuilder {
+	b.order.TakerAssetData = assetData
+	b.order.TakerAssetAmount = amount
+	return b
+}
+
+// MakerFee sets the asset data and amount of the fee paid by the maker.
+func (b *OrderBuilder) MakerFee(assetData []byte, amount *big.Int) *OrderBuilder {
+	b.order.MakerFeeAssetData = assetData
+	b.order.MakerFee = amount
+	return b
+}
+
+// TakerFee sets the asset data and amount of the fee paid by the taker.
+func (b *OrderBuilder) TakerFee(assetData []byte, amount *big.Int) *OrderBuilder {
+	b.order.TakerFeeAssetData = assetData
+	b.order.TakerFee = amount
+	return b
+}
+
+// Taker restricts the order to be filled by the given taker.
+func (b *OrderBuilder) Taker(takerAddress common.Address) *OrderBuilder {
+	b.order.TakerAddress = takerAddress
+	return b
+}
+
+// Sender restricts the order to be submitted by the given sender.
+func (b *OrderBuilder) Sender(senderAddress common.Address) *OrderBuilder {
+	b.order.SenderAddress = senderAddress
+	return b
+}
+
+// FeeRecipient sets the fee recipient address.
+func (b *OrderBuilder) FeeRecipient(feeRecipientAddress common.Address) *OrderBuilder {
+	b.order.FeeRecipientAddress = feeRecipientAddress
+	return b
+}
+
+// Salt sets the salt. If it isn't set, a salt is generated by the
+// SaltGenerator when the order is built.
+func (b *OrderBuilder) Salt(salt *big.Int) *OrderBuilder {
+	b.order.Salt = salt
+	return b
+}
+
+// SaltGenerator sets the SaltGenerator which is used if no salt is set. It
+// defaults to a shared random SaltGenerator.
+func (b *OrderBuilder) SaltGenerator(saltGenerator *SaltGenerator) *OrderBuilder {
+	b.saltGenerator = saltGenerator
+	return b
+}
+
+// ChainTime sets the current time on the chain, i.e. the timestamp of the
+// latest block. Orders are expired based on block timestamps, which can differ
+// from the local clock, so the expiration time is checked against it and
+// ExpiresIn is relative to it.
+func (b *OrderBuilder) ChainTime(chainTime time.Time) *OrderBuilder {
+	b.chainTime = chainTime
+	return b
+}
+
+// ExpiresAt sets the expiration time.
+func (b *OrderBuilder) ExpiresAt(expirationTime time.Time) *OrderBuilder {
+	b.order.ExpirationTimeSeconds = big.NewInt(expirationTime.Unix())
+	b.expiresIn = 0
+	return b
+}
+
+// ExpiresIn sets the expiration time to d after the chain time. ChainTime must
+// be set before the order is built.
+func (b *OrderBuilder) ExpiresIn(d time.Duration) *OrderBuilder {
+	b.order.ExpirationTimeSeconds = nil
+	b.expiresIn = d
+	return b
+}
+
+// Build checks the order and returns it. The returned order can be signed with
+// SignOrder. Build generates a new salt on each call unless a salt is set.
+func (b *OrderBuilder) Build() (*Order, error) {
+	order := b.order
+	if b.expiresIn != 0 {
+		if b.chainTime.IsZero() {
+			return nil, errors.New("chain time must be set to use ExpiresIn")
+		}
+		order.ExpirationTimeSeconds = big.NewInt(b.chainTime.Add(b.expiresIn).Unix())
+	}
+	if order.Salt == nil {
+		if b.saltGenerator == nil {
+			return nil, errors.New("salt or salt generator must be set")
+		}
+		salt, err := b.saltGenerator.Next()
+		if err != nil {
+			return nil, err
+		}
+		order.Salt = salt
+	}
+	if err := b.validate(&order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// validate checks the constraints on the fields of the order which can be
+// checked without making any calls to Ethereum.
+func (b *OrderBuilder) validate(order *Order) error {
+	if order.ChainID.Sign() <= 0 {
+		return errors.New("chain ID must be positive")
+	}
+	if order.ExchangeAddress == (common.Address{}) {
+		return errors.New("exchange address must be set")
+	}
+	if order.MakerAddress == (common.Address{}) {
+		return errors.New("maker address must be set")
+	}
+	decoder := getSharedAssetDataDecoder()
+	if err := validateBuilderAssetData(decoder, "maker asset data", order.MakerAssetData, false); err != nil {
+		return err
+	}
+	if err := validateBuilderAssetData(decoder, "taker asset data", order.TakerAssetData, false); err != nil {
+		return err
+	}
+	if err := validateBuilderAssetData(decoder, "maker fee asset data", order.MakerFeeAssetData, true); err != nil {
+		return err
+	}
+	if err := validateBuilderAssetData(decoder, "taker fee asset data", order.TakerFeeAssetData, true); err != nil {
+		return err
+	}
+	if err := validateBuilderAmount("maker asset amount", order.MakerAssetAmount, true); err != nil {
+		return err
+	}
+	if err := validateBuilderAmount("taker asset amount", order.TakerAssetAmount, true); err != nil {
+		return err
+	}
+	if err := validateBuilderAmount("maker fee", order.MakerFee, false); err != nil {
+		return err
+	}
+	if err := validateBuilderAmount("taker fee", order.TakerFee, false); err != nil {
+		return err
+	}
+	if order.MakerFee.Sign() > 0 && len(order.MakerFeeAssetData) == 0 {
+		return errors.New("maker fee asset data must be set if the maker fee is not zero")
+	}
+	if order.TakerFee.Sign() > 0 && len(order.TakerFeeAssetData) == 0 {
+		return errors.New("taker fee asset data must be set if the taker fee is not zero")
+	}
+	if err := validateBuilderAmount("salt", order.Salt, false); err != nil {
+		return err
+	}
+	if order.ExpirationTimeSeconds == nil {
+		return errors.New("expiration time must be set")
+	}
+	if !b.chainTime.IsZero() && order.ExpirationTimeSeconds.Cmp(big.NewInt(b.chainTime.Unix())) <= 0 {
+		return fmt.Errorf("expiration time (%s) must be after the chain time (%d)", order.ExpirationTimeSeconds, b.chainTime.Unix())
+	}
+	return nil
+}
+
+func validateBuilderAssetData(decoder *AssetDataDecoder, name string, assetData []byte, optional bool) error {
+	if len(assetData) == 0 {
+		if optional {
+			return nil
+		}
+		return fmt.Errorf("%s must be set", name)
+	}
+	if _, err := decoder.GetName(assetData); err != nil {
+		return fmt.Errorf("invalid %s: %s", name, err.Error())
+	}
+	return nil
+}
+
+func validateBuilderAmount(name string, amount *big.Int, mustBePositive bool) error {
+	if amount == nil {
+		return fmt.Errorf("%s must be set", name)
+	}
+	if _, ok := uint256.FromBig(amount); !ok {
+		return fmt.Errorf("%s must be a uint256", name)
+	}
+	if mustBePositive && amount.Sign() == 0 {
+		return fmt.Errorf("%s must not be zero", name)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package zeroex
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var builderTestAssetData = common.Hex2Bytes("f47261b000000000000000000000000038ae374ecf4db50b0ff37125b591a04997106a32")
+
+func newTestOrderBuilder(chainTime time.Time) *OrderBuilder {
+	return NewOrder(constants.TestChainID, contractAddresses.Exchange).
+		Maker(constants.GanacheAccount0).
+		MakerAsset(builderTestAssetData, big.NewInt(100)).
+		TakerAsset(builderTestAssetData, big.NewInt(200)).
+		ChainTime(chainTime).
+		ExpiresIn(time.Hour)
+}
+
+func TestOrderBuilderBuild(t *testing.T) {
+	chainTime := time.Unix(1600000000, 0)
+	order, err := newTestOrderBuilder(chainTime).Build()
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(constants.TestChainID), order.ChainID)
+	assert.Equal(t, big.NewInt(chainTime.Add(time.Hour).Unix()), order.ExpirationTimeSeconds)
+	assert.Equal(t, big.NewInt(0), order.MakerFee)
+	assert.Equal(t, big.NewInt(0), order.TakerFee)
+	require.NotNil(t, order.Salt)
+
+	// The order can be signed and passes the offline signature check.
+	signedOrder, err := SignTestOrder(order)
+	require.NoError(t, err)
+	status, err := signedOrder.ValidateSignatureOffline()
+	require.NoError(t, err)
+	assert.Equal(t, SignatureValid, status)
+}
+
+func TestOrderBuilderValidation(t *testing.T) {
+	chainTime := time.Unix(1600000000, 0)
+	testCases := []struct {
+		name    string
+		builder *OrderBuilder
+	}{
+		{
+			name:    "missing maker",
+			builder: newTestOrderBuilder(chainTime).Maker(common.Address{}),
+		},
+		{
+			name:    "zero maker asset amount",
+			builder: newTestOrderBuilder(chainTime).MakerAsset(builderTestAssetData, big.NewInt(0)),
+		},
+		{
+			name:    "missing taker asset data",
+			builder: newTestOrderBuilder(chainTime).TakerAsset(nil, big.NewInt(1)),
+		},
+		{
+			name:    "unsupported asset data",
+			builder: newTestOrderBuilder(chainTime).TakerAsset(common.Hex2Bytes("deadbeef"), big.NewInt(1)),
+		},
+		{
+			name:    "fee without fee asset data",
+			builder: newTestOrderBuilder(chainTime).MakerFee(nil, big.NewInt(1)),
+		},
+		{
+			name:    "negative fee",
+			builder: newTestOrderBuilder(chainTime).TakerFee(builderTestAssetData, big.NewInt(-1)),
+		},
+		{
+			name:    "amount larger than uint256",
+			builder: newTestOrderBuilder(chainTime).MakerAsset(builderTestAssetData, new(big.Int).Lsh(big.NewInt(1), 256)),
+		},
+		{
+			name:    "already expired",
+			builder: newTestOrderBuilder(chainTime).ExpiresAt(chainTime.Add(-time.Second)),
+		},
+		{
+			name:    "ExpiresIn without chain time",
+			builder: newTestOrderBuilder(time.Time{}),
+		},
+	}
+	for _, testCase := range testCases {
+		_, err := testCase.builder.Build()
+		assert.Error(t, err, testCase.name)
+	}
+}
+
+func TestRandomSaltGenerator(t *testing.T) {
+	generator := NewRandomSaltGenerator()
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		salt, err := generator.Next()
+		require.NoError(t, err)
+		assert.False(t, seen[salt.String()], "salt was generated twice")
+		seen[salt.String()] = true
+		assert.True(t, salt.Cmp(maxSalt) < 0)
+	}
+}
+
+func TestMonotonicSaltGenerator(t *testing.T) {
+	generator := NewMonotonicSaltGenerator()
+	previous := big.NewInt(0)
+	for i := 0; i < 100; i++ {
+		salt, err := generator.Next()
+		require.NoError(t, err)
+		assert.True(t, salt.Cmp(previous) > 0, "salts should be strictly increasing")
+		previous = salt
+	}
+
+	// Two orders built with the same monotonic generator get different salts.
+	builder := newTestOrderBuilder(time.Unix(1600000000, 0)).SaltGenerator(generator)
+	first, err := builder.Build()
+	require.NoError(t, err)
+	second, err := builder.Build()
+	require.NoError(t, err)
+	assert.True(t, second.Salt.Cmp(first.Salt) > 0)
+}
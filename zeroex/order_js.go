@@ -4,10 +4,10 @@ package zeroex
 
 import (
 	"fmt"
-	"strings"
 	"syscall/js"
 	"time"
 
+	"github.com/0xProject/0x-mesh/common/addressformat"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -16,13 +16,39 @@ func (o OrderEvent) JSValue() js.Value {
 	for i, contractEvent := range o.ContractEvents {
 		contractEventsJS[i] = contractEvent.JSValue()
 	}
-	return js.ValueOf(map[string]interface{}{
+	supersedesJS := make([]interface{}, len(o.Supersedes))
+	for i, superseded := range o.Supersedes {
+		supersedesJS[i] = superseded.JSValue()
+	}
+	orderEventJS := map[string]interface{}{
 		"timestamp":                o.Timestamp.Format(time.RFC3339),
 		"orderHash":                o.OrderHash.Hex(),
 		"signedOrder":              o.SignedOrder.JSValue(),
 		"endState":                 string(o.EndState),
 		"fillableTakerAssetAmount": o.FillableTakerAssetAmount.String(),
 		"contractEvents":           contractEventsJS,
+		"supersedes":               supersedesJS,
+	}
+	if o.ReplacedBy != (common.Hash{}) {
+		orderEventJS["replacedBy"] = o.ReplacedBy.Hex()
+	}
+	if o.BlockNumber != nil {
+		orderEventJS["blockNumber"] = o.BlockNumber.Int64()
+		orderEventJS["blockHash"] = o.BlockHash.Hex()
+		orderEventJS["blockTimestamp"] = o.BlockTimestamp.Format(time.RFC3339)
+	}
+	if o.SequenceNumber != 0 {
+		orderEventJS["sequenceNumber"] = o.SequenceNumber
+	}
+	return js.ValueOf(orderEventJS)
+}
+
+func (s SupersededOrderEvent) JSValue() js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"blockHash":                s.BlockHash.Hex(),
+		"timestamp":                s.Timestamp.Format(time.RFC3339),
+		"endState":                 string(s.EndState),
+		"fillableTakerAssetAmount": s.FillableTakerAssetAmount.String(),
 	})
 }
 
@@ -53,19 +79,19 @@ func (s SignedOrder) JSValue() js.Value {
 
 	return js.ValueOf(map[string]interface{}{
 		"chainId":               s.ChainID.Int64(),
-		"exchangeAddress":       strings.ToLower(s.ExchangeAddress.Hex()),
-		"makerAddress":          strings.ToLower(s.MakerAddress.Hex()),
+		"exchangeAddress":       addressformat.String(s.ExchangeAddress),
+		"makerAddress":          addressformat.String(s.MakerAddress),
 		"makerAssetData":        makerAssetData,
 		"makerFeeAssetData":     makerFeeAssetData,
 		"makerAssetAmount":      s.MakerAssetAmount.String(),
 		"makerFee":              s.MakerFee.String(),
-		"takerAddress":          strings.ToLower(s.TakerAddress.Hex()),
+		"takerAddress":          addressformat.String(s.TakerAddress),
 		"takerAssetData":        takerAssetData,
 		"takerFeeAssetData":     takerFeeAssetData,
 		"takerAssetAmount":      s.TakerAssetAmount.String(),
 		"takerFee":              s.TakerFee.String(),
-		"senderAddress":         strings.ToLower(s.SenderAddress.Hex()),
-		"feeRecipientAddress":   strings.ToLower(s.FeeRecipientAddress.Hex()),
+		"senderAddress":         addressformat.String(s.SenderAddress),
+		"feeRecipientAddress":   addressformat.String(s.FeeRecipientAddress),
 		"expirationTimeSeconds": s.ExpirationTimeSeconds.String(),
 		"salt":                  s.Salt.String(),
 		"signature":             signature,
@@ -74,7 +100,7 @@ func (s SignedOrder) JSValue() js.Value {
 
 func (c ContractEvent) JSValue() js.Value {
 	m := map[string]interface{}{
-		"address":    c.Address.Hex(),
+		"address":    addressformat.String(c.Address),
 		"blockHash":  c.BlockHash.Hex(),
 		"txHash":     c.TxHash.Hex(),
 		"txIndex":    c.TxIndex,
@@ -0,0 +1,243 @@
+package zeroex
+
+import (
+	"encoding/hex"
+	"math/big"
+	"sync"
+
+	"github.com/0xProject/0x-mesh/common/uint256"
+	"github.com/0xProject/0x-mesh/encoding/jsonscan"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// Indexes of the string fields of SignedOrderJSON in signedOrderFields.values.
+const (
+	fieldExchangeAddress = iota
+	fieldMakerAddress
+	fieldMakerAssetData
+	fieldMakerFeeAssetData
+	fieldMakerAssetAmount
+	fieldMakerFee
+	fieldTakerAddress
+	fieldTakerAssetData
+	fieldTakerFeeAssetData
+	fieldTakerAssetAmount
+	fieldTakerFee
+	fieldSenderAddress
+	fieldFeeRecipientAddress
+	fieldExpirationTimeSeconds
+	fieldSalt
+	fieldSignature
+	numSignedOrderStringFields
+)
+
+// signedOrderFields holds the raw values of the fields of a SignedOrderJSON.
+// The values reference the input data and are only valid while decoding.
+type signedOrderFields struct {
+	scanner jsonscan.Scanner
+	chainID []byte
+	values  [numSignedOrderStringFields][]byte
+}
+
+func (f *signedOrderFields) reset() {
+	f.scanner.Reset(nil)
+	f.chainID = nil
+	for i := range f.values {
+		f.values[i] = nil
+	}
+}
+
+var signedOrderFieldsPool = sync.Pool{
+	New: func() interface{} {
+		return &signedOrderFields{}
+	},
+}
+
+// unmarshalJSONFast decodes the JSON representation of a SignedOrder without
+// going through encoding/json. It returns false without modifying s if the
+// input is invalid or uses any JSON features that it doesn't support (e.g.
+// escape sequences, null values or keys which differ from the canonical ones),
+// in which case the caller must fall back to encoding/json. Any order which
+// is decoded successfully is identical to the one decoded by encoding/json.
+func (s *SignedOrder) unmarshalJSONFast(data []byte) bool {
+	fields := signedOrderFieldsPool.Get().(*signedOrderFields)
+	defer func() {
+		fields.reset()
+		signedOrderFieldsPool.Put(fields)
+	}()
+
+	scanner := &fields.scanner
+	scanner.Reset(data)
+	err := scanner.ReadObject(func(key []byte) error {
+		kind, err := scanner.Peek()
+		if err != nil {
+			return err
+		}
+		if string(key) == "chainId" {
+			if kind != jsonscan.KindNumber {
+				return jsonscan.ErrUnsupported
+			}
+			fields.chainID, err = scanner.ReadInteger()
+			return err
+		}
+		index := signedOrderFieldIndex(key)
+		if index == -1 || kind != jsonscan.KindString {
+			return jsonscan.ErrUnsupported
+		}
+		fields.values[index], err = scanner.ReadString()
+		return err
+	})
+	if err != nil {
+		return false
+	}
+	if err := scanner.End(); err != nil {
+		return false
+	}
+	chainID, ok := parseSmallInt(fields.chainID)
+	if !ok {
+		return false
+	}
+
+	s.ChainID = big.NewInt(chainID)
+	s.ExchangeAddress = hexToAddress(fields.values[fieldExchangeAddress])
+	s.MakerAddress = hexToAddress(fields.values[fieldMakerAddress])
+	s.MakerAssetData = fromHex(fields.values[fieldMakerAssetData])
+	s.MakerFeeAssetData = fromHex(fields.values[fieldMakerFeeAssetData])
+	setBig256(&s.MakerAssetAmount, fields.values[fieldMakerAssetAmount])
+	setBig256(&s.MakerFee, fields.values[fieldMakerFee])
+	s.TakerAddress = hexToAddress(fields.values[fieldTakerAddress])
+	s.TakerAssetData = fromHex(fields.values[fieldTakerAssetData])
+	s.TakerFeeAssetData = fromHex(fields.values[fieldTakerFeeAssetData])
+	setBig256(&s.TakerAssetAmount, fields.values[fieldTakerAssetAmount])
+	setBig256(&s.TakerFee, fields.values[fieldTakerFee])
+	s.SenderAddress = hexToAddress(fields.values[fieldSenderAddress])
+	s.FeeRecipientAddress = hexToAddress(fields.values[fieldFeeRecipientAddress])
+	setBig256(&s.ExpirationTimeSeconds, fields.values[fieldExpirationTimeSeconds])
+	setBig256(&s.Salt, fields.values[fieldSalt])
+	s.Signature = fromHex(fields.values[fieldSignature])
+	return true
+}
+
+// signedOrderFieldIndex returns the index of the string field with the given
+// JSON key or -1 if there is no such field.
+func signedOrderFieldIndex(key []byte) int {
+	switch string(key) {
+	case "exchangeAddress":
+		return fieldExchangeAddress
+	case "makerAddress":
+		return fieldMakerAddress
+	case "makerAssetData":
+		return fieldMakerAssetData
+	case "makerFeeAssetData":
+		return fieldMakerFeeAssetData
+	case "makerAssetAmount":
+		return fieldMakerAssetAmount
+	case "makerFee":
+		return fieldMakerFee
+	case "takerAddress":
+		return fieldTakerAddress
+	case "takerAssetData":
+		return fieldTakerAssetData
+	case "takerFeeAssetData":
+		return fieldTakerFeeAssetData
+	case "takerAssetAmount":
+		return fieldTakerAssetAmount
+	case "takerFee":
+		return fieldTakerFee
+	case "senderAddress":
+		return fieldSenderAddress
+	case "feeRecipientAddress":
+		return fieldFeeRecipientAddress
+	case "expirationTimeSeconds":
+		return fieldExpirationTimeSeconds
+	case "salt":
+		return fieldSalt
+	case "signature":
+		return fieldSignature
+	default:
+		return -1
+	}
+}
+
+// maxSmallIntDigits is the maximum number of decimal digits that can always be
+// parsed into an int64 or uint64 without overflowing.
+const maxSmallIntDigits = 18
+
+// parseSmallInt parses a (possibly negative) decimal integer. A missing value
+// is parsed as 0. It returns false if the value has too many digits.
+func parseSmallInt(b []byte) (int64, bool) {
+	negative := len(b) > 0 && b[0] == '-'
+	if negative {
+		b = b[1:]
+	}
+	value, ok := parseSmallUint(b)
+	if !ok {
+		return 0, false
+	}
+	if negative {
+		return -int64(value), true
+	}
+	return int64(value), true
+}
+
+func parseSmallUint(b []byte) (uint64, bool) {
+	if len(b) > maxSmallIntDigits {
+		return 0, false
+	}
+	var value uint64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		value = value*10 + uint64(c-'0')
+	}
+	return value, true
+}
+
+// hexToAddress is equivalent to common.HexToAddress but avoids allocating in
+// the common case.
+func hexToAddress(b []byte) common.Address {
+	var address common.Address
+	if len(b) == addressHexLength && b[0] == '0' && b[1] == 'x' {
+		if _, err := hex.Decode(address[:], b[2:]); err == nil {
+			return address
+		}
+	}
+	return common.HexToAddress(string(b))
+}
+
+// fromHex is equivalent to common.FromHex but avoids intermediate allocations
+// in the common case.
+func fromHex(b []byte) []byte {
+	if len(b) >= 2 && len(b)%2 == 0 && b[0] == '0' && b[1] == 'x' {
+		decoded := make([]byte, (len(b)-2)/2)
+		if _, err := hex.Decode(decoded, b[2:]); err == nil {
+			return decoded
+		}
+	}
+	return common.FromHex(string(b))
+}
+
+// setBig256 sets *dst to the number represented by b in the same way as
+// SignedOrder.UnmarshalJSON does: *dst is left unchanged if b is empty and set
+// to nil if b is not a valid 256-bit number.
+func setBig256(dst **big.Int, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	if value, ok := uint256.ParseDecimal(b); ok {
+		if value.IsUint64() {
+			*dst = new(big.Int).SetUint64(value.Uint64())
+			return
+		}
+		*dst = value.ToBig()
+		return
+	}
+	value, ok := math.ParseBig256(string(b))
+	if !ok {
+		*dst = nil
+		return
+	}
+	*dst = value
+}
@@ -0,0 +1,186 @@
+package zeroex
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalJSONFastMatchesStandard(t *testing.T) {
+	signedOrder, err := SignTestOrder(testOrder)
+	require.NoError(t, err)
+	canonical, err := json.Marshal(signedOrder)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		data           string
+		expectFastPath bool
+	}{
+		{
+			name:           "canonical order",
+			data:           string(canonical),
+			expectFastPath: true,
+		},
+		{
+			name:           "empty object",
+			data:           `{}`,
+			expectFastPath: true,
+		},
+		{
+			name:           "whitespace",
+			data:           " {\n\t\"chainId\" : 1337 ,\r\n \"salt\": \"12\" } ",
+			expectFastPath: true,
+		},
+		{
+			name:           "invalid numbers and hex",
+			data:           `{"makerAssetAmount":"abc","makerFee":"0x10","takerFee":"-1","salt":"123456789012345678901234567890","makerAddress":"0x12","makerAssetData":"0x1","signature":"zz"}`,
+			expectFastPath: true,
+		},
+		{
+			name: "uppercase hex prefix",
+			data: `{"makerAddress":"0X6ECBE1DB9EF729CBE972C83FB886247691FB6BEB","makerAssetData":"0XABCD"}`,
+			// Decoded by the fast path using the go-ethereum functions.
+			expectFastPath: true,
+		},
+		{
+			name:           "number too large for uint64",
+			data:           `{"salt":"115792089237316195423570985008687907853269984665640564039457584007913129639936"}`,
+			expectFastPath: true,
+		},
+		{
+			name:           "escape sequences",
+			data:           `{"salt":"\u0031"}`,
+			expectFastPath: false,
+		},
+		{
+			name:           "null value",
+			data:           `{"salt":null}`,
+			expectFastPath: false,
+		},
+		{
+			name:           "non-canonical key",
+			data:           `{"Salt":"1"}`,
+			expectFastPath: false,
+		},
+		{
+			name:           "fractional chain ID",
+			data:           `{"chainId":1.5}`,
+			expectFastPath: false,
+		},
+		{
+			name:           "chain ID too large",
+			data:           `{"chainId":12345678901234567890}`,
+			expectFastPath: false,
+		},
+		{
+			name:           "trailing data",
+			data:           `{} {}`,
+			expectFastPath: false,
+		},
+		{
+			name:           "truncated",
+			data:           `{"salt":"1"`,
+			expectFastPath: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var fast SignedOrder
+			usedFastPath := fast.unmarshalJSONFast([]byte(tc.data))
+			assert.Equal(t, tc.expectFastPath, usedFastPath)
+
+			var standard SignedOrder
+			standardErr := standard.unmarshalJSONStandard([]byte(tc.data))
+			if usedFastPath {
+				require.NoError(t, standardErr)
+				assert.Equal(t, standard, fast)
+			}
+
+			var actual SignedOrder
+			actualErr := actual.UnmarshalJSON([]byte(tc.data))
+			assert.Equal(t, standardErr, actualErr)
+			assert.Equal(t, standard, actual)
+		})
+	}
+}
+
+func TestUnmarshalJSONFastLeavesOrderUnchangedOnFallback(t *testing.T) {
+	signedOrder, err := SignTestOrder(testOrder)
+	require.NoError(t, err)
+	original := *signedOrder
+
+	assert.False(t, signedOrder.unmarshalJSONFast([]byte(`{"salt":"1","makerFee":null}`)))
+	assert.Equal(t, original, *signedOrder)
+}
+
+func BenchmarkSignedOrderUnmarshalJSON(b *testing.B) {
+	signedOrder, err := SignTestOrder(testOrder)
+	require.NoError(b, err)
+	data, err := json.Marshal(signedOrder)
+	require.NoError(b, err)
+	var decoded SignedOrder
+	require.True(b, decoded.unmarshalJSONFast(data), "canonical order not supported by fast decoder")
+
+	b.Run("fast", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var decoded SignedOrder
+			if err := decoded.UnmarshalJSON(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("standard", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var decoded SignedOrder
+			if err := decoded.unmarshalJSONStandard(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestSetBig256(t *testing.T) {
+	for _, input := range []string{"0", "0042", "18446744073709551615", "18446744073709551616", math.MaxBig256.String(), "0x2a"} {
+		expected, ok := math.ParseBig256(input)
+		require.True(t, ok, input)
+		var actual *big.Int
+		setBig256(&actual, []byte(input))
+		assert.Equal(t, expected, actual, input)
+	}
+
+	actual := big.NewInt(1)
+	setBig256(&actual, []byte(new(big.Int).Add(math.MaxBig256, big.NewInt(1)).String()))
+	assert.Nil(t, actual)
+	actual = big.NewInt(1)
+	setBig256(&actual, nil)
+	assert.Equal(t, big.NewInt(1), actual)
+}
+
+func BenchmarkSetBig256(b *testing.B) {
+	// Salts are typically large random numbers which don't fit in a uint64.
+	salt := []byte("58600101225676680041453168589125977076540694791976419610199695339725548478315")
+
+	b.Run("uint256", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var value *big.Int
+			setBig256(&value, salt)
+		}
+	})
+	b.Run("ParseBig256", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, ok := math.ParseBig256(string(salt)); !ok {
+				b.Fatal("invalid salt")
+			}
+		}
+	})
+}
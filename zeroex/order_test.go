@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/0xProject/0x-mesh/common/addressformat"
 	"github.com/0xProject/0x-mesh/constants"
 	"github.com/0xProject/0x-mesh/ethereum"
 	"github.com/0xProject/0x-mesh/zeroex/orderwatch/decoder"
@@ -100,6 +102,18 @@ func TestMarshalUnmarshalOrderEvent(t *testing.T) {
 				},
 			},
 		},
+		Supersedes: []*SupersededOrderEvent{
+			{
+				BlockHash:                common.HexToHash("0x3fcd58a6613265e2b0deba902d7ff693f330a0af6e5b04805b44bbffd8a415d4"),
+				Timestamp:                time.Now().UTC(),
+				EndState:                 ESOrderFilled,
+				FillableTakerAssetAmount: big.NewInt(1000),
+			},
+		},
+		BlockNumber:    big.NewInt(12965000),
+		BlockHash:      common.HexToHash("0x9b83c12c69edb74f6c8dd5d052765c1adf940e320bd1291696e6fa07829eee71"),
+		BlockTimestamp: time.Now().UTC(),
+		SequenceNumber: 1337,
 	}
 
 	buf := &bytes.Buffer{}
@@ -113,3 +127,30 @@ func TestMarshalUnmarshalOrderEvent(t *testing.T) {
 	require.NoError(t, json.NewDecoder(buf).Decode(&decoded))
 	assert.Equal(t, orderEvent, decoded)
 }
+
+func TestMarshalSignedOrderAddressFormat(t *testing.T) {
+	defer func() {
+		require.NoError(t, addressformat.Set(addressformat.Lowercase))
+	}()
+	signedOrder, err := SignTestOrder(testOrder)
+	require.NoError(t, err)
+
+	for _, format := range []addressformat.Format{addressformat.Lowercase, addressformat.Checksum} {
+		require.NoError(t, addressformat.Set(format))
+		encoded, err := json.Marshal(signedOrder)
+		require.NoError(t, err)
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal(encoded, &fields))
+		expectedMakerAddress := strings.ToLower(signedOrder.MakerAddress.Hex())
+		if format == addressformat.Checksum {
+			expectedMakerAddress = signedOrder.MakerAddress.Hex()
+		}
+		assert.Equal(t, expectedMakerAddress, fields["makerAddress"], "format: %s", format)
+
+		// Addresses are parsed regardless of their case.
+		var decoded SignedOrder
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		assert.Equal(t, signedOrder.MakerAddress, decoded.MakerAddress)
+		assert.Equal(t, signedOrder.ExchangeAddress, decoded.ExchangeAddress)
+	}
+}
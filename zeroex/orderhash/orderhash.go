@@ -0,0 +1,125 @@
+// Package orderhash computes 0x order hashes and creates and verifies order
+// signatures. Unlike the zeroex package, it doesn't depend on the contract
+// wrappers, the event decoder or go-ethereum's typed data signer, so it can be
+// used by small binaries such as the order-utils WebAssembly module.
+package orderhash
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/crypto/sha3"
+)
+
+// Order holds the fields of a 0x order which determine its hash.
+type Order struct {
+	ChainID               *big.Int
+	ExchangeAddress       common.Address
+	MakerAddress          common.Address
+	MakerAssetData        []byte
+	MakerFeeAssetData     []byte
+	MakerAssetAmount      *big.Int
+	MakerFee              *big.Int
+	TakerAddress          common.Address
+	TakerAssetData        []byte
+	TakerFeeAssetData     []byte
+	TakerAssetAmount      *big.Int
+	TakerFee              *big.Int
+	SenderAddress         common.Address
+	FeeRecipientAddress   common.Address
+	ExpirationTimeSeconds *big.Int
+	Salt                  *big.Int
+}
+
+var (
+	// eip712DomainTypeHash is the hash of the EIP712Domain type used by the 0x
+	// v3 Exchange contract.
+	eip712DomainTypeHash = keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	// eip712OrderTypeHash is the hash of the EIP712 Order type.
+	eip712OrderTypeHash = keccak256([]byte("Order(address makerAddress,address takerAddress,address feeRecipientAddress,address senderAddress,uint256 makerAssetAmount,uint256 takerAssetAmount,uint256 makerFee,uint256 takerFee,uint256 expirationTimeSeconds,uint256 salt,bytes makerAssetData,bytes takerAssetData,bytes makerFeeAssetData,bytes takerFeeAssetData)"))
+	// eip712DomainNameHash and eip712DomainVersionHash are the hashes of the
+	// name and version of the 0x v3 EIP712 domain.
+	eip712DomainNameHash    = keccak256([]byte("0x Protocol"))
+	eip712DomainVersionHash = keccak256([]byte("3.0.0"))
+)
+
+// maxUint256 is the largest value of a uint256.
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// Compute computes the EIP712 hash of the given order, as defined by the 0x
+// v3 Exchange contract.
+func Compute(order *Order) (common.Hash, error) {
+	if order == nil {
+		return common.Hash{}, errors.New("cannot hash nil order")
+	}
+	if order.ChainID == nil {
+		return common.Hash{}, errors.New("chainId is required")
+	}
+
+	domainSeparator := keccak256(
+		eip712DomainTypeHash,
+		eip712DomainNameHash,
+		eip712DomainVersionHash,
+		encodeUint256(big.NewInt(order.ChainID.Int64())),
+		encodeAddress(order.ExchangeAddress),
+	)
+
+	amounts := []struct {
+		name  string
+		value *big.Int
+	}{
+		{"makerAssetAmount", order.MakerAssetAmount},
+		{"takerAssetAmount", order.TakerAssetAmount},
+		{"makerFee", order.MakerFee},
+		{"takerFee", order.TakerFee},
+		{"expirationTimeSeconds", order.ExpirationTimeSeconds},
+		{"salt", order.Salt},
+	}
+	encodedOrder := [][]byte{
+		eip712OrderTypeHash,
+		encodeAddress(order.MakerAddress),
+		encodeAddress(order.TakerAddress),
+		encodeAddress(order.FeeRecipientAddress),
+		encodeAddress(order.SenderAddress),
+	}
+	for _, amount := range amounts {
+		if amount.value == nil {
+			return common.Hash{}, fmt.Errorf("%s is required", amount.name)
+		}
+		if amount.value.BitLen() > 256 {
+			return common.Hash{}, fmt.Errorf("%s does not fit in a uint256", amount.name)
+		}
+		encodedOrder = append(encodedOrder, encodeUint256(amount.value))
+	}
+	encodedOrder = append(encodedOrder,
+		keccak256(order.MakerAssetData),
+		keccak256(order.TakerAssetData),
+		keccak256(order.MakerFeeAssetData),
+		keccak256(order.TakerFeeAssetData),
+	)
+	orderStructHash := keccak256(encodedOrder...)
+
+	return common.BytesToHash(keccak256([]byte("\x19\x01"), domainSeparator, orderStructHash)), nil
+}
+
+// encodeAddress encodes an address as an EIP712 address value.
+func encodeAddress(address common.Address) []byte {
+	return common.LeftPadBytes(address.Bytes(), 32)
+}
+
+// encodeUint256 encodes x as an EIP712 uint256 value. Negative values are
+// encoded in two's complement, like go-ethereum's typed data signer does.
+func encodeUint256(x *big.Int) []byte {
+	return common.LeftPadBytes(new(big.Int).And(x, maxUint256).Bytes(), 32)
+}
+
+// keccak256 calculates and returns the Keccak256 hash of the input data.
+func keccak256(data ...[]byte) []byte {
+	d := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		_, _ = d.Write(b)
+	}
+	return d.Sum(nil)
+}
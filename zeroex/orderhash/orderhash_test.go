@@ -0,0 +1,52 @@
+package orderhash
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newZeroOrder() *Order {
+	return &Order{
+		ChainID:               big.NewInt(1337),
+		ExchangeAddress:       common.HexToAddress("0x1dc4c1cefef38a777b15aa20260a54e584b16c48"),
+		MakerAssetData:        common.Address{}.Bytes(),
+		MakerFeeAssetData:     common.Address{}.Bytes(),
+		TakerAssetData:        common.Address{}.Bytes(),
+		TakerFeeAssetData:     common.Address{}.Bytes(),
+		Salt:                  big.NewInt(0),
+		MakerFee:              big.NewInt(0),
+		TakerFee:              big.NewInt(0),
+		MakerAssetAmount:      big.NewInt(0),
+		TakerAssetAmount:      big.NewInt(0),
+		ExpirationTimeSeconds: big.NewInt(0),
+	}
+}
+
+func TestCompute(t *testing.T) {
+	// expectedOrderHash copied over from canonical order hashing test in Typescript library
+	expectedOrderHash := common.HexToHash("0xcb36e4fedb36508fb707e2c05e21bffc7a72766ccae93f8ff096693fff7f1714")
+	actualOrderHash, err := Compute(newZeroOrder())
+	require.NoError(t, err)
+	assert.Equal(t, expectedOrderHash, actualOrderHash)
+}
+
+func TestComputeInvalidOrder(t *testing.T) {
+	order := newZeroOrder()
+	order.Salt = nil
+	_, err := Compute(order)
+	assert.EqualError(t, err, "salt is required")
+
+	order = newZeroOrder()
+	order.MakerAssetAmount = new(big.Int).Lsh(big.NewInt(1), 256)
+	_, err = Compute(order)
+	assert.EqualError(t, err, "makerAssetAmount does not fit in a uint256")
+
+	order = newZeroOrder()
+	order.ChainID = nil
+	_, err = Compute(order)
+	assert.Error(t, err)
+}
@@ -0,0 +1,196 @@
+package orderhash
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SignatureType represents the type of 0x signature encountered
+type SignatureType uint8
+
+// SignatureType values
+const (
+	IllegalSignature SignatureType = iota
+	InvalidSignature
+	EIP712Signature
+	EthSignSignature
+	WalletSignature
+	ValidatorSignature
+	PreSignedSignature
+	EIP1271WalletSignature
+	NSignatureTypesSignature
+)
+
+// ErrSignatureNotRecoverable is returned by RecoverSigner for signature types
+// which don't consist of an ECDSA signature (e.g. Wallet or Validator
+// signatures). Such signatures can only be verified on-chain.
+var ErrSignatureNotRecoverable = errors.New("signature type does not support ECDSA recovery")
+
+// RecoverSigner returns the address of the account that produced the given
+// EIP712 or EthSign signature for the order with the given hash. It returns
+// ErrSignatureNotRecoverable for all other signature types.
+func RecoverSigner(orderHash common.Hash, signature []byte) (common.Address, error) {
+	if len(signature) == 0 {
+		return common.Address{}, errors.New("signature is empty")
+	}
+	var hash []byte
+	switch SignatureType(signature[len(signature)-1]) {
+	case EIP712Signature:
+		hash = orderHash.Bytes()
+	case EthSignSignature:
+		hash = keccak256([]byte("\x19Ethereum Signed Message:\n32"), orderHash.Bytes())
+	default:
+		return common.Address{}, ErrSignatureNotRecoverable
+	}
+	if len(signature) != 66 {
+		return common.Address{}, errors.New("signature must be 66 bytes long")
+	}
+
+	// 0x signatures are in the [V || R || S || type] format where V is 27 or 28.
+	// crypto.SigToPub expects the [R || S || V] format where V is 0 or 1.
+	v := signature[0]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return common.Address{}, errors.New("signature has invalid V value")
+	}
+	ecSignature := make([]byte, 65)
+	copy(ecSignature[0:64], signature[1:65])
+	ecSignature[64] = v
+	publicKey, err := crypto.SigToPub(hash, ecSignature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// OfflineSignatureStatus is the result of validating a signature without
+// making any calls to Ethereum.
+type OfflineSignatureStatus uint8
+
+// OfflineSignatureStatus values
+const (
+	// SignatureInvalid means that the signature is malformed, has an
+	// unsupported type or was not produced by the maker.
+	SignatureInvalid OfflineSignatureStatus = iota
+	// SignatureValid means that the signature is an EIP712 or EthSign
+	// signature produced by the maker.
+	SignatureValid
+	// SignatureRequiresOnChainValidation means that the signature is well
+	// formed, but its type (Wallet, Validator, PreSigned or EIP1271Wallet) can
+	// only be validated by calling the Exchange contract or DevUtils.
+	SignatureRequiresOnChainValidation
+)
+
+// String returns a human-readable representation of the status.
+func (s OfflineSignatureStatus) String() string {
+	switch s {
+	case SignatureInvalid:
+		return "invalid"
+	case SignatureValid:
+		return "valid"
+	case SignatureRequiresOnChainValidation:
+		return "requires on-chain validation"
+	default:
+		return "unknown"
+	}
+}
+
+// ValidateSignatureOffline checks the signature of the order with the given
+// hash and maker without making any calls to Ethereum. EIP712 and EthSign
+// signatures are fully validated by recovering the signer and comparing it to
+// the maker. For all other signature types, only the format of the signature
+// is checked and SignatureRequiresOnChainValidation is returned if it is well
+// formed. If the signature is invalid, the returned error describes why.
+func ValidateSignatureOffline(orderHash common.Hash, makerAddress common.Address, signature []byte) (OfflineSignatureStatus, error) {
+	if len(signature) == 0 {
+		return SignatureInvalid, errors.New("signature is empty")
+	}
+	switch signatureType := SignatureType(signature[len(signature)-1]); signatureType {
+	case EIP712Signature, EthSignSignature:
+		signer, err := RecoverSigner(orderHash, signature)
+		if err != nil {
+			return SignatureInvalid, err
+		}
+		if signer != makerAddress {
+			return SignatureInvalid, fmt.Errorf("signature was produced by %s instead of the maker", signer.Hex())
+		}
+		return SignatureValid, nil
+	case ValidatorSignature:
+		// Validator signatures end with the 20 byte address of the validator,
+		// followed by the signature type.
+		if len(signature) < 21 {
+			return SignatureInvalid, errors.New("validator signature must be at least 21 bytes long")
+		}
+		return SignatureRequiresOnChainValidation, nil
+	case WalletSignature, PreSignedSignature, EIP1271WalletSignature:
+		return SignatureRequiresOnChainValidation, nil
+	case IllegalSignature, InvalidSignature:
+		return SignatureInvalid, fmt.Errorf("signature type %d is never valid", signatureType)
+	default:
+		return SignatureInvalid, fmt.Errorf("unsupported signature type %d", signatureType)
+	}
+}
+
+// SignOrderHash produces an EIP712 or EthSign signature in the 0x format for
+// the order with the given hash. It returns ErrSignatureNotRecoverable for all
+// other signature types. Signatures are deterministic (RFC 6979), so the same
+// private key and order hash always result in the same signature.
+func SignOrderHash(privateKey *ecdsa.PrivateKey, orderHash common.Hash, signatureType SignatureType) ([]byte, error) {
+	var hash []byte
+	switch signatureType {
+	case EIP712Signature:
+		hash = orderHash.Bytes()
+	case EthSignSignature:
+		hash = keccak256([]byte("\x19Ethereum Signed Message:\n32"), orderHash.Bytes())
+	default:
+		return nil, ErrSignatureNotRecoverable
+	}
+	// crypto.Sign produces signatures in the [R || S || V] format where V is 0
+	// or 1. 0x signatures are in the [V || R || S || type] format where V is 27
+	// or 28.
+	ecSignature, err := crypto.Sign(hash, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	signature := make([]byte, 66)
+	signature[0] = ecSignature[64] + 27
+	copy(signature[1:65], ecSignature[0:64])
+	signature[65] = byte(signatureType)
+	return signature, nil
+}
+
+// FromECSignature converts an ECDSA signature in the [R || S || V] format used
+// by Ethereum wallets (e.g. the result of eth_sign or eth_signTypedData) into a
+// 0x signature of the given type for the order with the given hash. V may be 0,
+// 1, 27 or 28. An EthSign signature must be over the order hash prefixed with
+// "\x19Ethereum Signed Message:\n32" and an EIP712 signature over the order
+// hash itself. An error is returned if the signature was not produced by the
+// maker, which usually means that the wallet signed with a different account
+// or used a different signature scheme. It returns ErrSignatureNotRecoverable
+// for signature types other than EIP712 and EthSign.
+func FromECSignature(orderHash common.Hash, makerAddress common.Address, ecSignature []byte, signatureType SignatureType) ([]byte, error) {
+	if signatureType != EIP712Signature && signatureType != EthSignSignature {
+		return nil, ErrSignatureNotRecoverable
+	}
+	if len(ecSignature) != 65 {
+		return nil, errors.New("ECDSA signature must be 65 bytes long")
+	}
+	signature := make([]byte, 66)
+	signature[0] = ecSignature[64]
+	if signature[0] < 27 {
+		signature[0] += 27
+	}
+	copy(signature[1:65], ecSignature[0:64])
+	signature[65] = byte(signatureType)
+	status, err := ValidateSignatureOffline(orderHash, makerAddress, signature)
+	if status != SignatureValid {
+		return nil, err
+	}
+	return signature, nil
+}
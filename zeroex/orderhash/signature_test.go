@@ -0,0 +1,48 @@
+package orderhash
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromECSignature(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	makerAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
+	orderHash := common.HexToHash("0x1")
+
+	// Wallets return signatures in the [R || S || V] format, with V being
+	// either 0 or 1 or 27 or 28 depending on the wallet.
+	eip712ECSignature, err := crypto.Sign(orderHash.Bytes(), privateKey)
+	require.NoError(t, err)
+	ethSignECSignature, err := crypto.Sign(keccak256([]byte("\x19Ethereum Signed Message:\n32"), orderHash.Bytes()), privateKey)
+	require.NoError(t, err)
+	ethSignECSignature[64] += 27
+
+	for signatureType, ecSignature := range map[SignatureType][]byte{
+		EIP712Signature:  eip712ECSignature,
+		EthSignSignature: ethSignECSignature,
+	} {
+		signature, err := FromECSignature(orderHash, makerAddress, ecSignature, signatureType)
+		require.NoError(t, err)
+		expectedSignature, err := SignOrderHash(privateKey, orderHash, signatureType)
+		require.NoError(t, err)
+		assert.Equal(t, expectedSignature, signature)
+	}
+
+	// Signatures which weren't produced by the maker are rejected, e.g. if the
+	// wallet used a different account or signature scheme.
+	_, err = FromECSignature(orderHash, common.HexToAddress("0x2"), eip712ECSignature, EIP712Signature)
+	assert.Error(t, err)
+	_, err = FromECSignature(orderHash, makerAddress, eip712ECSignature, EthSignSignature)
+	assert.Error(t, err)
+
+	_, err = FromECSignature(orderHash, makerAddress, eip712ECSignature[:64], EIP712Signature)
+	assert.Error(t, err)
+	_, err = FromECSignature(orderHash, makerAddress, eip712ECSignature, WalletSignature)
+	assert.Equal(t, ErrSignatureNotRecoverable, err)
+}
@@ -0,0 +1,51 @@
+package ordervalidator
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+type callCounterKey struct{}
+
+// CallCounter counts the eth_call requests the OrderValidator makes on behalf
+// of a context. It is safe for concurrent use.
+type CallCounter struct {
+	count uint64
+}
+
+// WithCallCounter returns a copy of ctx and a CallCounter which counts the
+// eth_call requests made by the OrderValidator with the returned context,
+// including retries.
+func WithCallCounter(ctx context.Context) (context.Context, *CallCounter) {
+	counter := &CallCounter{}
+	return context.WithValue(ctx, callCounterKey{}, counter), counter
+}
+
+// Count returns the number of eth_call requests counted so far.
+func (c *CallCounter) Count() uint64 {
+	return atomic.LoadUint64(&c.count)
+}
+
+// countingCaller is a bind.ContractCaller which counts the calls made with the
+// underlying caller in the CallCounter of their context, if any.
+type countingCaller struct {
+	bind.ContractCaller
+}
+
+// countCalls returns a bind.ContractCaller which makes calls with the given
+// caller and counts them (see WithCallCounter).
+func countCalls(contractCaller bind.ContractCaller) bind.ContractCaller {
+	return &countingCaller{ContractCaller: contractCaller}
+}
+
+// CallContract implements bind.ContractCaller.
+func (c *countingCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if counter, ok := ctx.Value(callCounterKey{}).(*CallCounter); ok {
+		atomic.AddUint64(&counter.count, 1)
+	}
+	return c.ContractCaller.CallContract(ctx, call, blockNumber)
+}
@@ -0,0 +1,37 @@
+// +build !js
+
+package ordervalidator
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountCalls(t *testing.T) {
+	to := common.HexToAddress("0x1")
+	caller := withCallOptions(countCalls(&recordingContractCaller{}), CallOptions{Timeout: time.Minute})
+
+	// Calls without a CallCounter are not counted.
+	_, err := caller.CallContract(context.Background(), ethereum.CallMsg{To: &to}, big.NewInt(42))
+	require.NoError(t, err)
+
+	ctx, counter := WithCallCounter(context.Background())
+	for i := 0; i < 3; i++ {
+		_, err := caller.CallContract(ctx, ethereum.CallMsg{To: &to}, big.NewInt(42))
+		require.NoError(t, err)
+	}
+	_, err = caller.CodeAt(ctx, to, big.NewInt(42))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), counter.Count())
+
+	// Counters of different contexts are independent.
+	_, otherCounter := WithCallCounter(context.Background())
+	assert.Equal(t, uint64(0), otherCounter.Count())
+}
@@ -0,0 +1,83 @@
+package ordervalidator
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CallOptions configures the eth_call requests the OrderValidator makes to
+// read the on-chain state of orders. The zero value leaves the gas limit and
+// timeout up to the Ethereum node and validates orders at the requested block.
+type CallOptions struct {
+	// GasLimit is the gas limit of each eth_call. If 0, the Ethereum node uses
+	// its own default (typically its RPC gas cap). Some providers reject calls
+	// which exceed their gas cap, in which case this should be set to at most
+	// that cap.
+	GasLimit uint64
+	// Timeout is the maximum amount of time to wait for the response to each
+	// eth_call. If 0, calls are only limited by their context.
+	Timeout time.Duration
+	// UseLatestBlock determines whether calls are made at the latest block
+	// instead of the block requested by the caller. This avoids errors from
+	// load-balanced providers whose nodes have not all processed the requested
+	// block yet, at the expense of validation results which may correspond to
+	// a slightly newer block than the one they are recorded for.
+	UseLatestBlock bool
+}
+
+// callOptionsCaller is a bind.ContractCaller which applies CallOptions to
+// every call made with the underlying caller.
+type callOptionsCaller struct {
+	bind.ContractCaller
+	opts CallOptions
+}
+
+// withCallOptions returns a bind.ContractCaller which makes calls with the
+// given caller and applies the given CallOptions. If the options are the zero
+// value, the caller is returned as-is.
+func withCallOptions(contractCaller bind.ContractCaller, opts CallOptions) bind.ContractCaller {
+	if opts == (CallOptions{}) {
+		return contractCaller
+	}
+	return &callOptionsCaller{
+		ContractCaller: contractCaller,
+		opts:           opts,
+	}
+}
+
+// CodeAt implements bind.ContractCaller.
+func (c *callOptionsCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	return c.ContractCaller.CodeAt(ctx, contract, c.blockNumber(blockNumber))
+}
+
+// CallContract implements bind.ContractCaller. The gas limit of the call is
+// only set if the call doesn't specify one already.
+func (c *callOptionsCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if call.Gas == 0 {
+		call.Gas = c.opts.GasLimit
+	}
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	return c.ContractCaller.CallContract(ctx, call, c.blockNumber(blockNumber))
+}
+
+func (c *callOptionsCaller) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.opts.Timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.opts.Timeout)
+}
+
+func (c *callOptionsCaller) blockNumber(blockNumber *big.Int) *big.Int {
+	if c.opts.UseLatestBlock {
+		return nil
+	}
+	return blockNumber
+}
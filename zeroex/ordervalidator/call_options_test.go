@@ -0,0 +1,72 @@
+// +build !js
+
+package ordervalidator
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingContractCaller is a bind.ContractCaller which records the calls it
+// receives.
+type recordingContractCaller struct {
+	call        ethereum.CallMsg
+	blockNumber *big.Int
+	hasDeadline bool
+}
+
+func (c *recordingContractCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	c.blockNumber = blockNumber
+	_, c.hasDeadline = ctx.Deadline()
+	return []byte{0x1}, nil
+}
+
+func (c *recordingContractCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.call = call
+	c.blockNumber = blockNumber
+	_, c.hasDeadline = ctx.Deadline()
+	return []byte{0x1}, nil
+}
+
+func TestWithCallOptions(t *testing.T) {
+	to := common.HexToAddress("0x1")
+	blockNumber := big.NewInt(42)
+
+	// The zero value leaves the caller unchanged.
+	recorder := &recordingContractCaller{}
+	assert.Equal(t, recorder, withCallOptions(recorder, CallOptions{}))
+
+	recorder = &recordingContractCaller{}
+	caller := withCallOptions(recorder, CallOptions{
+		GasLimit: 50000000,
+		Timeout:  time.Minute,
+	})
+	_, err := caller.CallContract(context.Background(), ethereum.CallMsg{To: &to}, blockNumber)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(50000000), recorder.call.Gas)
+	assert.Equal(t, blockNumber, recorder.blockNumber)
+	assert.True(t, recorder.hasDeadline)
+
+	// An explicit gas limit is not overridden.
+	_, err = caller.CallContract(context.Background(), ethereum.CallMsg{To: &to, Gas: 100000}, blockNumber)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100000), recorder.call.Gas)
+
+	recorder = &recordingContractCaller{}
+	caller = withCallOptions(recorder, CallOptions{UseLatestBlock: true})
+	_, err = caller.CallContract(context.Background(), ethereum.CallMsg{To: &to}, blockNumber)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), recorder.call.Gas)
+	assert.Nil(t, recorder.blockNumber)
+	assert.False(t, recorder.hasDeadline)
+	_, err = caller.CodeAt(context.Background(), to, blockNumber)
+	require.NoError(t, err)
+	assert.Nil(t, recorder.blockNumber)
+}
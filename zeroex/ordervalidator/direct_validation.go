@@ -0,0 +1,327 @@
+package ordervalidator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/0xProject/0x-mesh/ethereum/multicall"
+	"github.com/0xProject/0x-mesh/ethereum/wrappers"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// ValidationStrategy determines how the on-chain state of orders is read.
+type ValidationStrategy string
+
+// ValidationStrategy values
+const (
+	// ValidationStrategyDevUtils reads the state of each batch of orders with a
+	// single call to the getOrderRelevantStates method of DevUtils.
+	ValidationStrategyDevUtils = ValidationStrategy("devutils")
+	// ValidationStrategyDirect reads the state of orders by calling the
+	// Exchange and ERC20 token contracts directly. The calls are batched via
+	// Multicall if its address is configured. It is meant for chains on which
+	// DevUtils is not deployed and only supports orders whose maker asset (and
+	// maker fee asset, if the maker fee is non-zero) is an ERC20 token.
+	ValidationStrategyDirect = ValidationStrategy("direct")
+)
+
+// erc20ABI contains the subset of the ERC20 ABI used to determine the
+// transferable amount of a token.
+const erc20ABI = `[{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"name":"allowance","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// orderRelevantStates is the return type of the getOrderRelevantStates method
+// of DevUtils.
+type orderRelevantStates = struct {
+	OrdersInfo                []wrappers.OrderInfo
+	FillableTakerAssetAmounts []*big.Int
+	IsValidSignature          []bool
+}
+
+// orderStateReader reads the on-chain state of orders. It is implemented by
+// the DevUtils contract wrapper and by directStateReader.
+type orderStateReader interface {
+	GetOrderRelevantStates(opts *bind.CallOpts, orders []wrappers.TrimmedOrder, signatures [][]byte) (orderRelevantStates, error)
+}
+
+// newOrderStateReader returns the orderStateReader for the configured
+// validation strategy which makes calls with the given contract caller and
+// the configured call options.
+func (o *OrderValidator) newOrderStateReader(contractCaller bind.ContractCaller) (orderStateReader, error) {
+	contractCaller = withCallOptions(countCalls(contractCaller), o.callOptions)
+	switch o.validationStrategy {
+	case ValidationStrategyDirect:
+		return newDirectStateReader(contractCaller, o.contractAddresses.Multicall, o.contractAddresses.Exchange, o.contractAddresses.ERC20Proxy, o.exchangeABI)
+	default:
+		return wrappers.NewDevUtilsCaller(o.contractAddresses.DevUtils, contractCaller)
+	}
+}
+
+// directStateReader is an orderStateReader which computes the same results as
+// DevUtils from individual calls to the Exchange and ERC20 token contracts.
+type directStateReader struct {
+	multicall         *multicall.Caller
+	exchangeAddress   common.Address
+	erc20ProxyAddress common.Address
+	exchangeABI       abi.ABI
+	erc20ABI          abi.ABI
+	assetDataDecoder  *zeroex.AssetDataDecoder
+}
+
+func newDirectStateReader(contractCaller bind.ContractCaller, multicallAddress, exchangeAddress, erc20ProxyAddress common.Address, exchangeABI abi.ABI) (*directStateReader, error) {
+	multicallCaller, err := multicall.New(contractCaller, multicallAddress)
+	if err != nil {
+		return nil, err
+	}
+	parsedERC20ABI, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return nil, err
+	}
+	return &directStateReader{
+		multicall:         multicallCaller,
+		exchangeAddress:   exchangeAddress,
+		erc20ProxyAddress: erc20ProxyAddress,
+		exchangeABI:       exchangeABI,
+		erc20ABI:          parsedERC20ABI,
+		assetDataDecoder:  zeroex.NewAssetDataDecoder(),
+	}, nil
+}
+
+// tokenOwner identifies the balance and allowance of a token owner.
+type tokenOwner struct {
+	token common.Address
+	owner common.Address
+}
+
+// GetOrderRelevantStates implements orderStateReader. For each order it reads
+// the order info and signature validity from the Exchange contract and the
+// balances and allowances of the maker from the relevant ERC20 tokens, and
+// then computes the fillable taker asset amount in the same way DevUtils
+// does. Orders whose maker asset or maker fee asset is not an ERC20 token
+// have a fillable taker asset amount of 0.
+func (r *directStateReader) GetOrderRelevantStates(opts *bind.CallOpts, orders []wrappers.TrimmedOrder, signatures [][]byte) (orderRelevantStates, error) {
+	states := orderRelevantStates{
+		OrdersInfo:                make([]wrappers.OrderInfo, len(orders)),
+		FillableTakerAssetAmounts: make([]*big.Int, len(orders)),
+		IsValidSignature:          make([]bool, len(orders)),
+	}
+	if len(orders) != len(signatures) {
+		return states, errors.New("number of orders and signatures must be equal")
+	}
+
+	// The first two calls for each order are getOrderInfo and
+	// isValidOrderSignature. They are followed by calls to balanceOf and
+	// allowance for each unique token owner.
+	calls := make([]multicall.Call, 0, 4*len(orders))
+	for i, order := range orders {
+		orderInfoCallData, err := r.exchangeABI.Pack("getOrderInfo", order)
+		if err != nil {
+			return states, err
+		}
+		signatureCallData, err := r.exchangeABI.Pack("isValidOrderSignature", order, signatures[i])
+		if err != nil {
+			return states, err
+		}
+		calls = append(calls,
+			multicall.Call{Target: r.exchangeAddress, CallData: orderInfoCallData},
+			multicall.Call{Target: r.exchangeAddress, CallData: signatureCallData},
+		)
+	}
+	tokenOwnerToCallIndex := map[tokenOwner]int{}
+	for _, order := range orders {
+		for _, assetData := range [][]byte{order.MakerAssetData, order.MakerFeeAssetData} {
+			token, ok := r.decodeERC20Token(assetData)
+			if !ok {
+				continue
+			}
+			key := tokenOwner{token: token, owner: order.MakerAddress}
+			if _, found := tokenOwnerToCallIndex[key]; found {
+				continue
+			}
+			balanceCallData, err := r.erc20ABI.Pack("balanceOf", order.MakerAddress)
+			if err != nil {
+				return states, err
+			}
+			allowanceCallData, err := r.erc20ABI.Pack("allowance", order.MakerAddress, r.erc20ProxyAddress)
+			if err != nil {
+				return states, err
+			}
+			tokenOwnerToCallIndex[key] = len(calls)
+			calls = append(calls,
+				multicall.Call{Target: token, CallData: balanceCallData},
+				multicall.Call{Target: token, CallData: allowanceCallData},
+			)
+		}
+	}
+
+	results, err := r.multicall.Call(opts.Context, calls, opts.BlockNumber)
+	if err != nil {
+		return states, err
+	}
+
+	for i, order := range orders {
+		orderInfoResult := results[2*i]
+		if err := r.checkResult(orderInfoResult); err != nil {
+			return states, err
+		}
+		if err := r.exchangeABI.Unpack(&states.OrdersInfo[i], "getOrderInfo", orderInfoResult.ReturnData); err != nil {
+			return states, err
+		}
+
+		// Like DevUtils, we consider the signature invalid if checking it
+		// reverts.
+		signatureResult := results[2*i+1]
+		if err := r.checkResult(signatureResult); err != nil && err != errCallReverted {
+			return states, err
+		} else if err == nil {
+			if err := r.exchangeABI.Unpack(&states.IsValidSignature[i], "isValidOrderSignature", signatureResult.ReturnData); err != nil {
+				states.IsValidSignature[i] = false
+			}
+		}
+
+		transferableMakerAssetAmount, err := r.transferableAmount(order.MakerAssetData, order.MakerAddress, tokenOwnerToCallIndex, results)
+		if err != nil {
+			return states, err
+		}
+		var transferableTakerAssetAmount *big.Int
+		if bytes.Equal(order.MakerAssetData, order.MakerFeeAssetData) {
+			transferableTakerAssetAmount = getPartialAmountFloor(transferableMakerAssetAmount, new(big.Int).Add(order.MakerAssetAmount, order.MakerFee), order.TakerAssetAmount)
+		} else if order.MakerFee.Sign() == 0 {
+			transferableTakerAssetAmount = getPartialAmountFloor(transferableMakerAssetAmount, order.MakerAssetAmount, order.TakerAssetAmount)
+		} else {
+			transferableMakerFeeAssetAmount, err := r.transferableAmount(order.MakerFeeAssetData, order.MakerAddress, tokenOwnerToCallIndex, results)
+			if err != nil {
+				return states, err
+			}
+			transferableTakerAssetAmount = math.BigMin(
+				getPartialAmountFloor(transferableMakerAssetAmount, order.MakerAssetAmount, order.TakerAssetAmount),
+				getPartialAmountFloor(transferableMakerFeeAssetAmount, order.MakerFee, order.TakerAssetAmount),
+			)
+		}
+
+		fillableTakerAssetAmount := big.NewInt(0)
+		orderInfo := states.OrdersInfo[i]
+		if zeroex.OrderStatus(orderInfo.OrderStatus) == zeroex.OSFillable {
+			remainingTakerAssetAmount := new(big.Int).Sub(order.TakerAssetAmount, orderInfo.OrderTakerAssetFilledAmount)
+			fillableTakerAssetAmount = math.BigMin(remainingTakerAssetAmount, transferableTakerAssetAmount)
+		}
+		states.FillableTakerAssetAmounts[i] = fillableTakerAssetAmount
+	}
+	return states, nil
+}
+
+// errCallReverted is returned by checkResult if the call reverted.
+var errCallReverted = errors.New("call reverted")
+
+// checkResult returns errCallReverted if the call reverted and the original
+// error if it failed for any other reason (e.g. a network error).
+func (r *directStateReader) checkResult(result multicall.Result) error {
+	if result.Err == nil {
+		return nil
+	}
+	if _, ok := revertReasonFromError(result.Err); ok {
+		return errCallReverted
+	}
+	return result.Err
+}
+
+// transferableAmount returns the amount of the ERC20 token encoded in the
+// given asset data that the owner can transfer, i.e. the minimum of its
+// balance and its allowance for the ERC20Proxy. Like DevUtils, it returns 0 if
+// reading either of them reverts or if the asset data does not encode an ERC20
+// token.
+func (r *directStateReader) transferableAmount(assetData []byte, owner common.Address, tokenOwnerToCallIndex map[tokenOwner]int, results []multicall.Result) (*big.Int, error) {
+	token, ok := r.decodeERC20Token(assetData)
+	if !ok {
+		return big.NewInt(0), nil
+	}
+	callIndex, found := tokenOwnerToCallIndex[tokenOwner{token: token, owner: owner}]
+	if !found {
+		return nil, fmt.Errorf("unexpectedly missing balance of %s for token %s", owner.Hex(), token.Hex())
+	}
+	amounts := make([]*big.Int, 2)
+	for j, method := range []string{"balanceOf", "allowance"} {
+		result := results[callIndex+j]
+		if err := r.checkResult(result); err == errCallReverted {
+			return big.NewInt(0), nil
+		} else if err != nil {
+			return nil, err
+		}
+		if err := r.erc20ABI.Unpack(&amounts[j], method, result.ReturnData); err != nil {
+			// The token address is not a contract or does not implement ERC20.
+			return big.NewInt(0), nil
+		}
+	}
+	return math.BigMin(amounts[0], amounts[1]), nil
+}
+
+// decodeERC20Token returns the token address encoded in the given asset data.
+// It returns false if the asset data does not encode an ERC20 token.
+func (r *directStateReader) decodeERC20Token(assetData []byte) (common.Address, bool) {
+	assetDataName, err := r.assetDataDecoder.GetName(assetData)
+	if err != nil || assetDataName != "ERC20Token" {
+		return common.Address{}, false
+	}
+	var decodedAssetData zeroex.ERC20AssetData
+	if err := r.assetDataDecoder.Decode(assetData, &decodedAssetData); err != nil {
+		return common.Address{}, false
+	}
+	return decodedAssetData.Address, true
+}
+
+// getPartialAmountFloor computes numerator * target / denominator, rounded
+// down, like the function of the same name in LibMath. It returns 0 if the
+// denominator is 0.
+func getPartialAmountFloor(numerator, denominator, target *big.Int) *big.Int {
+	if denominator.Sign() == 0 {
+		return big.NewInt(0)
+	}
+	result := new(big.Int).Mul(numerator, target)
+	return result.Div(result, denominator)
+}
+
+// filterOrdersUnsupportedByStrategy rejects orders which cannot be validated
+// using the configured validation strategy. With the direct strategy, only
+// orders whose maker asset (and maker fee asset, if the maker fee is
+// non-zero) is an ERC20 token are supported.
+func (o *OrderValidator) filterOrdersUnsupportedByStrategy(signedOrders []*zeroex.SignedOrder) ([]*zeroex.SignedOrder, []*RejectedOrderInfo) {
+	if o.validationStrategy != ValidationStrategyDirect {
+		return signedOrders, nil
+	}
+	supportedOrders := []*zeroex.SignedOrder{}
+	rejectedOrderInfos := []*RejectedOrderInfo{}
+	for _, signedOrder := range signedOrders {
+		var status *RejectedOrderStatus
+		if !o.isERC20AssetData(signedOrder.MakerAssetData) {
+			status = &ROInvalidMakerAssetData
+		} else if signedOrder.MakerFee.Sign() > 0 && !o.isERC20AssetData(signedOrder.MakerFeeAssetData) {
+			status = &ROInvalidMakerFeeAssetData
+		}
+		if status == nil {
+			supportedOrders = append(supportedOrders, signedOrder)
+			continue
+		}
+		orderHash, err := signedOrder.ComputeOrderHash()
+		if err != nil {
+			continue
+		}
+		rejectedOrderInfos = append(rejectedOrderInfos, &RejectedOrderInfo{
+			OrderHash:   orderHash,
+			SignedOrder: signedOrder,
+			Kind:        MeshValidation,
+			Status:      *status,
+		})
+	}
+	return supportedOrders, rejectedOrderInfos
+}
+
+func (o *OrderValidator) isERC20AssetData(assetData []byte) bool {
+	assetDataName, err := o.assetDataDecoder.GetName(assetData)
+	return err == nil && assetDataName == "ERC20Token"
+}
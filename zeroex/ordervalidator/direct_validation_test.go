@@ -0,0 +1,124 @@
+// +build !js
+
+package ordervalidator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/ethereum/wrappers"
+	"github.com/0xProject/0x-mesh/zeroex"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeContractCaller is a bind.ContractCaller which responds to calls with
+// fixed return data, keyed by the target address and call data.
+type fakeContractCaller struct {
+	responses map[string][]byte
+	numCalls  int
+}
+
+func (c *fakeContractCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (c *fakeContractCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.numCalls++
+	response, found := c.responses[call.To.Hex()+hexutil.Encode(call.Data)]
+	if !found {
+		return nil, errors.New("execution reverted")
+	}
+	return response, nil
+}
+
+func (c *fakeContractCaller) respond(t *testing.T, to common.Address, contractABI abi.ABI, method string, output interface{}, inputs ...interface{}) {
+	callData, err := contractABI.Pack(method, inputs...)
+	require.NoError(t, err)
+	returnData, err := contractABI.Methods[method].Outputs.Pack(output)
+	require.NoError(t, err)
+	c.responses[to.Hex()+hexutil.Encode(callData)] = returnData
+}
+
+func TestDirectStateReaderGetOrderRelevantStates(t *testing.T) {
+	exchangeABI, err := abi.JSON(strings.NewReader(wrappers.ExchangeABI))
+	require.NoError(t, err)
+	erc20ABI, err := abi.JSON(strings.NewReader(erc20ABI))
+	require.NoError(t, err)
+
+	exchangeAddress := common.HexToAddress("0x48bacb9266a570d521063ef5dd96e61686dbe788")
+	erc20ProxyAddress := common.HexToAddress("0x1dc4c1cefef38a777b15aa20260a54e584b16c48")
+	tokenAddress := common.HexToAddress("0x871dd7c2b4b25e1aa18728e9d5f2af4c4e431f5c")
+	makerAddress := common.HexToAddress("0x6ecbe1db9ef729cbe972c83fb886247691fb6beb")
+	tokenAssetData := common.FromHex("0xf47261b0000000000000000000000000871dd7c2b4b25e1aa18728e9d5f2af4c4e431f5c")
+
+	// The maker asset is funded for 50 units.
+	orderWithoutFee := wrappers.TrimmedOrder{
+		MakerAddress:          makerAddress,
+		MakerAssetAmount:      big.NewInt(100),
+		TakerAssetAmount:      big.NewInt(200),
+		MakerFee:              big.NewInt(0),
+		TakerFee:              big.NewInt(0),
+		ExpirationTimeSeconds: big.NewInt(0),
+		Salt:                  big.NewInt(1),
+		MakerAssetData:        tokenAssetData,
+		TakerAssetData:        tokenAssetData,
+		MakerFeeAssetData:     []byte{},
+		TakerFeeAssetData:     []byte{},
+	}
+	// The maker fee is paid in the maker asset and the order is partially
+	// filled.
+	orderWithFee := orderWithoutFee
+	orderWithFee.MakerAssetAmount = big.NewInt(40)
+	orderWithFee.MakerFee = big.NewInt(10)
+	orderWithFee.TakerAssetAmount = big.NewInt(10)
+	orderWithFee.MakerFeeAssetData = tokenAssetData
+	orderWithFee.Salt = big.NewInt(2)
+	orders := []wrappers.TrimmedOrder{orderWithoutFee, orderWithFee}
+	signatures := [][]byte{{0x1}, {0x2}}
+
+	caller := &fakeContractCaller{responses: map[string][]byte{}}
+	caller.respond(t, exchangeAddress, exchangeABI, "getOrderInfo", wrappers.OrderInfo{
+		OrderStatus:                 uint8(zeroex.OSFillable),
+		OrderHash:                   common.HexToHash("0x1"),
+		OrderTakerAssetFilledAmount: big.NewInt(0),
+	}, orderWithoutFee)
+	caller.respond(t, exchangeAddress, exchangeABI, "getOrderInfo", wrappers.OrderInfo{
+		OrderStatus:                 uint8(zeroex.OSFillable),
+		OrderHash:                   common.HexToHash("0x2"),
+		OrderTakerAssetFilledAmount: big.NewInt(4),
+	}, orderWithFee)
+	caller.respond(t, exchangeAddress, exchangeABI, "isValidOrderSignature", true, orderWithoutFee, signatures[0])
+	// isValidOrderSignature reverts for the second order.
+	caller.respond(t, tokenAddress, erc20ABI, "balanceOf", big.NewInt(100), makerAddress)
+	caller.respond(t, tokenAddress, erc20ABI, "allowance", big.NewInt(50), makerAddress, erc20ProxyAddress)
+
+	reader, err := newDirectStateReader(caller, constants.NullAddress, exchangeAddress, erc20ProxyAddress, exchangeABI)
+	require.NoError(t, err)
+	states, err := reader.GetOrderRelevantStates(&bind.CallOpts{Context: context.Background()}, orders, signatures)
+	require.NoError(t, err)
+
+	assert.Equal(t, []bool{true, false}, states.IsValidSignature)
+	assert.Equal(t, common.HexToHash("0x1"), common.Hash(states.OrdersInfo[0].OrderHash))
+	assert.Equal(t, common.HexToHash("0x2"), common.Hash(states.OrdersInfo[1].OrderHash))
+	// 50 / 100 * 200
+	assert.Equal(t, big.NewInt(100), states.FillableTakerAssetAmounts[0])
+	// min(50 / (40 + 10) * 10, 10 - 4)
+	assert.Equal(t, big.NewInt(6), states.FillableTakerAssetAmounts[1])
+	// The balance and allowance are only read once for both orders.
+	assert.Equal(t, 6, caller.numCalls)
+}
+
+func TestGetPartialAmountFloor(t *testing.T) {
+	assert.Equal(t, big.NewInt(3), getPartialAmountFloor(big.NewInt(1), big.NewInt(3), big.NewInt(10)))
+	assert.Equal(t, big.NewInt(0), getPartialAmountFloor(big.NewInt(1), big.NewInt(0), big.NewInt(10)))
+}
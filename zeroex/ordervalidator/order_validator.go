@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/0xProject/0x-mesh/common/uint256"
 	"github.com/0xProject/0x-mesh/constants"
 	"github.com/0xProject/0x-mesh/ethereum"
 	"github.com/0xProject/0x-mesh/ethereum/wrappers"
@@ -49,6 +50,11 @@ type AcceptedOrderInfo struct {
 	SignedOrder              *zeroex.SignedOrder `json:"signedOrder"`
 	FillableTakerAssetAmount *big.Int            `json:"fillableTakerAssetAmount"`
 	IsNew                    bool                `json:"isNew"`
+	// TransferSimulationFailed is true if simulating the transfer of the maker's
+	// assets reverted or delivered a different amount than was transferred,
+	// which indicates that the order might not actually be fillable (e.g.
+	// because the maker token is paused or charges a fee on transfer).
+	TransferSimulationFailed bool `json:"transferSimulationFailed,omitempty"`
 }
 
 type acceptedOrderInfoJSON struct {
@@ -56,16 +62,21 @@ type acceptedOrderInfoJSON struct {
 	SignedOrder              *zeroex.SignedOrder `json:"signedOrder"`
 	FillableTakerAssetAmount string              `json:"fillableTakerAssetAmount"`
 	IsNew                    bool                `json:"isNew"`
+	TransferSimulationFailed bool                `json:"transferSimulationFailed"`
 }
 
 // MarshalJSON is a custom Marshaler for AcceptedOrderInfo
 func (a AcceptedOrderInfo) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]interface{}{
+	acceptedOrderInfoJSON := map[string]interface{}{
 		"orderHash":                a.OrderHash.Hex(),
 		"signedOrder":              a.SignedOrder,
 		"fillableTakerAssetAmount": a.FillableTakerAssetAmount.String(),
 		"isNew":                    a.IsNew,
-	})
+	}
+	if a.TransferSimulationFailed {
+		acceptedOrderInfoJSON["transferSimulationFailed"] = true
+	}
+	return json.Marshal(acceptedOrderInfoJSON)
 }
 
 // UnmarshalJSON implements a custom JSON unmarshaller for the OrderEvent type
@@ -79,6 +90,7 @@ func (a *AcceptedOrderInfo) UnmarshalJSON(data []byte) error {
 	a.OrderHash = common.HexToHash(acceptedOrderInfoJSON.OrderHash)
 	a.SignedOrder = acceptedOrderInfoJSON.SignedOrder
 	a.IsNew = acceptedOrderInfoJSON.IsNew
+	a.TransferSimulationFailed = acceptedOrderInfoJSON.TransferSimulationFailed
 	var ok bool
 	a.FillableTakerAssetAmount, ok = math.ParseBig256(acceptedOrderInfoJSON.FillableTakerAssetAmount)
 	if !ok {
@@ -89,109 +101,325 @@ func (a *AcceptedOrderInfo) UnmarshalJSON(data []byte) error {
 
 // RejectedOrderStatus enumerates all the unique reasons for an orders rejection
 type RejectedOrderStatus struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	// Code is a stable, human-readable identifier for the reason.
+	Code string `json:"code"`
+	// NumericCode is a stable numeric identifier for the reason. The hundreds
+	// digit identifies the Category: 1xx for validation, 2xx for Mesh policy,
+	// 3xx for network and 9xx for internal errors.
+	NumericCode int `json:"numericCode"`
+	// Category groups reasons which should be handled in the same way.
+	Category RejectedOrderCategory `json:"category"`
+	Message  string                `json:"message"`
+}
+
+// WithMessage returns a copy of the status with the given message. It is used
+// for statuses which include details about the specific order in the message.
+func (s RejectedOrderStatus) WithMessage(message string) RejectedOrderStatus {
+	s.Message = message
+	return s
 }
 
+// RejectedOrderCategory groups the reasons for an order's rejection by how
+// they should be handled.
+type RejectedOrderCategory string
+
+// RejectedOrderCategory values
+const (
+	// ValidationCategory means that the order is invalid or unfillable. It
+	// should not be submitted again.
+	ValidationCategory = RejectedOrderCategory("VALIDATION")
+	// MeshPolicyCategory means that the order is not accepted because of the
+	// configuration or the state of the Mesh node. It may be accepted by other
+	// nodes.
+	MeshPolicyCategory = RejectedOrderCategory("MESH_POLICY")
+	// NetworkCategory means that a network request needed to validate the
+	// order failed. The order may be accepted if it is submitted again later.
+	NetworkCategory = RejectedOrderCategory("NETWORK")
+	// InternalCategory means that an unexpected error occurred in Mesh.
+	InternalCategory = RejectedOrderCategory("INTERNAL")
+)
+
 // RejectedOrderStatus values
 var (
+	ROInvalidSchema = RejectedOrderStatus{
+		Code:        ROInvalidSchemaCode,
+		NumericCode: 100,
+		Category:    ValidationCategory,
+		Message:     "order did not pass JSON-schema validation",
+	}
 	ROEthRPCRequestFailed = RejectedOrderStatus{
-		Code:    "EthRPCRequestFailed",
-		Message: "network request to Ethereum RPC endpoint failed",
+		Code:        "EthRPCRequestFailed",
+		NumericCode: 300,
+		Category:    NetworkCategory,
+		Message:     "network request to Ethereum RPC endpoint failed",
 	}
 	ROCoordinatorRequestFailed = RejectedOrderStatus{
-		Code:    "CoordinatorRequestFailed",
-		Message: "network request to coordinator server endpoint failed",
+		Code:        "CoordinatorRequestFailed",
+		NumericCode: 301,
+		Category:    NetworkCategory,
+		Message:     "network request to coordinator server endpoint failed",
 	}
 	ROCoordinatorSoftCancelled = RejectedOrderStatus{
-		Code:    "CoordinatorSoftCancelled",
-		Message: "order was soft-cancelled via the coordinator server",
+		Code:        "CoordinatorSoftCancelled",
+		NumericCode: 114,
+		Category:    ValidationCategory,
+		Message:     "order was soft-cancelled via the coordinator server",
 	}
 	ROCoordinatorEndpointNotFound = RejectedOrderStatus{
-		Code:    "CoordinatorEndpointNotFound",
-		Message: "corresponding coordinator endpoint not found in CoordinatorRegistry contract",
+		Code:        "CoordinatorEndpointNotFound",
+		NumericCode: 302,
+		Category:    NetworkCategory,
+		Message:     "corresponding coordinator endpoint not found in CoordinatorRegistry contract",
 	}
 	ROInvalidMakerAssetAmount = RejectedOrderStatus{
-		Code:    "OrderHasInvalidMakerAssetAmount",
-		Message: "order makerAssetAmount cannot be 0",
+		Code:        "OrderHasInvalidMakerAssetAmount",
+		NumericCode: 101,
+		Category:    ValidationCategory,
+		Message:     "order makerAssetAmount cannot be 0",
 	}
 	ROInvalidTakerAssetAmount = RejectedOrderStatus{
-		Code:    "OrderHasInvalidTakerAssetAmount",
-		Message: "order takerAssetAmount cannot be 0",
+		Code:        "OrderHasInvalidTakerAssetAmount",
+		NumericCode: 102,
+		Category:    ValidationCategory,
+		Message:     "order takerAssetAmount cannot be 0",
 	}
 	ROExpired = RejectedOrderStatus{
-		Code:    "OrderExpired",
-		Message: "order expired according to latest block timestamp",
+		Code:        "OrderExpired",
+		NumericCode: 103,
+		Category:    ValidationCategory,
+		Message:     "order expired according to latest block timestamp",
 	}
 	ROFullyFilled = RejectedOrderStatus{
-		Code:    "OrderFullyFilled",
-		Message: "order already fully filled",
+		Code:        "OrderFullyFilled",
+		NumericCode: 104,
+		Category:    ValidationCategory,
+		Message:     "order already fully filled",
 	}
 	ROCancelled = RejectedOrderStatus{
-		Code:    "OrderCancelled",
-		Message: "order cancelled",
+		Code:        "OrderCancelled",
+		NumericCode: 105,
+		Category:    ValidationCategory,
+		Message:     "order cancelled",
 	}
 	ROUnfunded = RejectedOrderStatus{
-		Code:    "OrderUnfunded",
-		Message: "maker has insufficient balance or allowance for this order to be filled",
+		Code:        "OrderUnfunded",
+		NumericCode: 106,
+		Category:    ValidationCategory,
+		Message:     "maker has insufficient balance or allowance for this order to be filled",
 	}
 	ROInvalidMakerAssetData = RejectedOrderStatus{
-		Code:    "OrderHasInvalidMakerAssetData",
-		Message: "order makerAssetData must encode a supported assetData type",
+		Code:        "OrderHasInvalidMakerAssetData",
+		NumericCode: 107,
+		Category:    ValidationCategory,
+		Message:     "order makerAssetData must encode a supported assetData type",
 	}
 	ROInvalidMakerFeeAssetData = RejectedOrderStatus{
-		Code:    "OrderHasInvalidMakerFeeAssetData",
-		Message: "order makerFeeAssetData must encode a supported assetData type",
+		Code:        "OrderHasInvalidMakerFeeAssetData",
+		NumericCode: 108,
+		Category:    ValidationCategory,
+		Message:     "order makerFeeAssetData must encode a supported assetData type",
 	}
 	ROInvalidTakerAssetData = RejectedOrderStatus{
-		Code:    "OrderHasInvalidTakerAssetData",
-		Message: "order takerAssetData must encode a supported assetData type",
+		Code:        "OrderHasInvalidTakerAssetData",
+		NumericCode: 109,
+		Category:    ValidationCategory,
+		Message:     "order takerAssetData must encode a supported assetData type",
 	}
 	ROInvalidTakerFeeAssetData = RejectedOrderStatus{
-		Code:    "OrderHasInvalidTakerFeeAssetData",
-		Message: "order takerFeeAssetData must encode a supported assetData type",
+		Code:        "OrderHasInvalidTakerFeeAssetData",
+		NumericCode: 110,
+		Category:    ValidationCategory,
+		Message:     "order takerFeeAssetData must encode a supported assetData type",
 	}
 	ROInvalidSignature = RejectedOrderStatus{
-		Code:    "OrderHasInvalidSignature",
-		Message: "order signature must be valid",
+		Code:        "OrderHasInvalidSignature",
+		NumericCode: 111,
+		Category:    ValidationCategory,
+		Message:     "order signature must be valid",
 	}
 	ROMaxExpirationExceeded = RejectedOrderStatus{
-		Code:    "OrderMaxExpirationExceeded",
-		Message: "order expiration too far in the future",
+		Code:        "OrderMaxExpirationExceeded",
+		NumericCode: 200,
+		Category:    MeshPolicyCategory,
+		Message:     "order expiration too far in the future",
 	}
 	ROInternalError = RejectedOrderStatus{
-		Code:    "InternalError",
-		Message: "an unexpected internal error has occurred",
+		Code:        "InternalError",
+		NumericCode: 900,
+		Category:    InternalCategory,
+		Message:     "an unexpected internal error has occurred",
 	}
 	ROMaxOrderSizeExceeded = RejectedOrderStatus{
-		Code:    "MaxOrderSizeExceeded",
-		Message: fmt.Sprintf("order exceeds the maximum encoded size of %d bytes", constants.MaxOrderSizeInBytes),
+		Code:        "MaxOrderSizeExceeded",
+		NumericCode: 201,
+		Category:    MeshPolicyCategory,
+		Message:     fmt.Sprintf("order exceeds the maximum encoded size of %d bytes", constants.MaxOrderSizeInBytes),
 	}
 	ROOrderAlreadyStoredAndUnfillable = RejectedOrderStatus{
-		Code:    "OrderAlreadyStoredAndUnfillable",
-		Message: "order is already stored and is unfillable. Mesh keeps unfillable orders in storage for a little while incase a block re-org makes them fillable again",
+		Code:        "OrderAlreadyStoredAndUnfillable",
+		NumericCode: 202,
+		Category:    MeshPolicyCategory,
+		Message:     "order is already stored and is unfillable. Mesh keeps unfillable orders in storage for a little while incase a block re-org makes them fillable again",
 	}
 	ROIncorrectChain = RejectedOrderStatus{
-		Code:    "OrderForIncorrectChain",
-		Message: "order was created for a different chain than the one this Mesh node is configured to support",
+		Code:        "OrderForIncorrectChain",
+		NumericCode: 112,
+		Category:    ValidationCategory,
+		Message:     "order was created for a different chain than the one this Mesh node is configured to support",
 	}
 	ROIncorrectExchangeAddress = RejectedOrderStatus{
-		Code:    "IncorrectExchangeAddress",
-		Message: "the exchange address for the order does not match the chain ID/network ID",
+		Code:        "IncorrectExchangeAddress",
+		NumericCode: 113,
+		Category:    ValidationCategory,
+		Message:     "the exchange address for the order does not match the chain ID/network ID",
 	}
 	ROSenderAddressNotAllowed = RejectedOrderStatus{
-		Code:    "SenderAddressNotAllowed",
-		Message: "orders with a senderAddress are not currently supported",
+		Code:        "SenderAddressNotAllowed",
+		NumericCode: 203,
+		Category:    MeshPolicyCategory,
+		Message:     "orders with a senderAddress are not currently supported",
+	}
+	ROMakerNotAllowed = RejectedOrderStatus{
+		Code:        "MakerNotAllowed",
+		NumericCode: 204,
+		Category:    MeshPolicyCategory,
+		Message:     "orders from this maker address are not accepted by this Mesh node",
+	}
+	ROAssetNotAllowed = RejectedOrderStatus{
+		Code:        "AssetNotAllowed",
+		NumericCode: 205,
+		Category:    MeshPolicyCategory,
+		Message:     "order involves a token that is not accepted by this Mesh node",
+	}
+	ROTransferSimulationFailed = RejectedOrderStatus{
+		Code:        "TransferSimulationFailed",
+		NumericCode: 115,
+		Category:    ValidationCategory,
+		Message:     "simulating a transfer of the maker's assets failed (e.g. because the token charges a fee on transfer), so the order is unlikely to be fillable",
+	}
+	ROOrderNotionalTooLow = RejectedOrderStatus{
+		Code:        "OrderNotionalTooLow",
+		NumericCode: 206,
+		Category:    MeshPolicyCategory,
+		Message:     "order's approximate USD value is below the minimum accepted by this node",
 	}
 	RODatabaseFullOfOrders = RejectedOrderStatus{
-		Code:    "DatabaseFullOfOrders",
-		Message: "database is full of pinned orders and no orders can be deleted to make space (consider increasing MAX_ORDERS_IN_STORAGE)",
+		Code:        "DatabaseFullOfOrders",
+		NumericCode: 207,
+		Category:    MeshPolicyCategory,
+		Message:     "database is full of pinned orders and no orders can be deleted to make space (consider increasing MAX_ORDERS_IN_STORAGE)",
+	}
+	ROAssetDataTooLarge = RejectedOrderStatus{
+		Code:        "AssetDataTooLarge",
+		NumericCode: 208,
+		Category:    MeshPolicyCategory,
+		Message:     "order asset data exceeds the maximum size accepted by this node",
+	}
+	ROMultiAssetNestingTooDeep = RejectedOrderStatus{
+		Code:        "MultiAssetNestingTooDeep",
+		NumericCode: 209,
+		Category:    MeshPolicyCategory,
+		Message:     "order MultiAsset asset data is nested deeper than accepted by this node",
+	}
+	ROTakerAddressNotAllowed = RejectedOrderStatus{
+		Code:        "TakerAddressNotAllowed",
+		NumericCode: 210,
+		Category:    MeshPolicyCategory,
+		Message:     "orders with a takerAddress are not accepted by this Mesh node",
+	}
+	ROOrderSuperseded = RejectedOrderStatus{
+		Code:        "OrderSuperseded",
+		NumericCode: 211,
+		Category:    MeshPolicyCategory,
+		Message:     "a newer order from the same maker for the same asset pair (i.e. with a higher salt) is already stored",
+	}
+	ROSignatureRequiresOnChainValidation = RejectedOrderStatus{
+		Code:        "SignatureRequiresOnChainValidation",
+		NumericCode: 212,
+		Category:    MeshPolicyCategory,
+		Message:     "order signature can only be validated on-chain, which this node does not do because it runs in relay-only mode",
+	}
+	ROOrderReplaced = RejectedOrderStatus{
+		Code:        "OrderReplaced",
+		NumericCode: 213,
+		Category:    MeshPolicyCategory,
+		Message:     "order was replaced by a newer order from the same maker and can't be added again",
+	}
+	RODoesNotMatchFilter = RejectedOrderStatus{
+		Code:        "OrderDoesNotMatchFilter",
+		NumericCode: 214,
+		Category:    MeshPolicyCategory,
+		Message:     "order received from a peer does not match the custom order filter of this node",
 	}
 )
 
-// ROInvalidSchemaCode is the RejectedOrderStatus emitted if an order doesn't conform to the order schema
+// ROInvalidSchemaCode is the Code of ROInvalidSchema, the RejectedOrderStatus
+// emitted if an order doesn't conform to the order schema
 const ROInvalidSchemaCode = "InvalidSchema"
 
+// RejectedOrderStatuses is the catalogue of all RejectedOrderStatus values,
+// sorted by NumericCode. Codes are never re-used, so the catalogue only ever
+// grows.
+var RejectedOrderStatuses = []RejectedOrderStatus{
+	ROInvalidSchema,
+	ROInvalidMakerAssetAmount,
+	ROInvalidTakerAssetAmount,
+	ROExpired,
+	ROFullyFilled,
+	ROCancelled,
+	ROUnfunded,
+	ROInvalidMakerAssetData,
+	ROInvalidMakerFeeAssetData,
+	ROInvalidTakerAssetData,
+	ROInvalidTakerFeeAssetData,
+	ROInvalidSignature,
+	ROIncorrectChain,
+	ROIncorrectExchangeAddress,
+	ROCoordinatorSoftCancelled,
+	ROTransferSimulationFailed,
+	ROMaxExpirationExceeded,
+	ROMaxOrderSizeExceeded,
+	ROOrderAlreadyStoredAndUnfillable,
+	ROSenderAddressNotAllowed,
+	ROMakerNotAllowed,
+	ROAssetNotAllowed,
+	ROOrderNotionalTooLow,
+	RODatabaseFullOfOrders,
+	ROAssetDataTooLarge,
+	ROMultiAssetNestingTooDeep,
+	ROTakerAddressNotAllowed,
+	ROOrderSuperseded,
+	ROSignatureRequiresOnChainValidation,
+	ROOrderReplaced,
+	RODoesNotMatchFilter,
+	ROEthRPCRequestFailed,
+	ROCoordinatorRequestFailed,
+	ROCoordinatorEndpointNotFound,
+	ROInternalError,
+}
+
+// FindRejectedOrderStatusByCode returns the RejectedOrderStatus with the given
+// Code. It returns false if there is no such status.
+func FindRejectedOrderStatusByCode(code string) (RejectedOrderStatus, bool) {
+	for _, status := range RejectedOrderStatuses {
+		if status.Code == code {
+			return status, true
+		}
+	}
+	return RejectedOrderStatus{}, false
+}
+
+// FindRejectedOrderStatusByNumericCode returns the RejectedOrderStatus with
+// the given NumericCode. It returns false if there is no such status.
+func FindRejectedOrderStatusByNumericCode(numericCode int) (RejectedOrderStatus, bool) {
+	for _, status := range RejectedOrderStatuses {
+		if status.NumericCode == numericCode {
+			return status, true
+		}
+	}
+	return RejectedOrderStatus{}, false
+}
+
 // ConvertRejectOrderCodeToOrderEventEndState converts an RejectOrderCode to an OrderEventEndState type
 func ConvertRejectOrderCodeToOrderEventEndState(rejectedOrderStatus RejectedOrderStatus) (zeroex.OrderEventEndState, bool) {
 	switch rejectedOrderStatus {
@@ -233,8 +461,14 @@ type ValidationResults struct {
 // OrderValidator validates 0x orders
 type OrderValidator struct {
 	maxRequestContentLength      int
+	contractCaller               bind.ContractCaller
 	devUtilsABI                  abi.ABI
+	exchangeABI                  abi.ABI
 	devUtils                     *wrappers.DevUtilsCaller
+	devUtilsRaw                  *wrappers.DevUtilsCallerRaw
+	validationStrategy           ValidationStrategy
+	callOptions                  CallOptions
+	orderStateReader             orderStateReader
 	coordinatorRegistry          *wrappers.CoordinatorRegistryCaller
 	assetDataDecoder             *zeroex.AssetDataDecoder
 	chainID                      int
@@ -242,32 +476,57 @@ type OrderValidator struct {
 	contractAddresses            ethereum.ContractAddresses
 }
 
-// New instantiates a new order validator
-func New(contractCaller bind.ContractCaller, chainID int, maxRequestContentLength int, contractAddresses ethereum.ContractAddresses) (*OrderValidator, error) {
+// New instantiates a new order validator. The validation strategy determines
+// how the on-chain state of orders is read and the call options configure the
+// eth_call requests used to read it.
+func New(contractCaller bind.ContractCaller, chainID int, maxRequestContentLength int, contractAddresses ethereum.ContractAddresses, validationStrategy ValidationStrategy, callOptions CallOptions) (*OrderValidator, error) {
+	switch validationStrategy {
+	case ValidationStrategyDevUtils:
+		if contractAddresses.DevUtils == constants.NullAddress {
+			return nil, errors.New("the devutils validation strategy requires the DevUtils contract address to be configured")
+		}
+	case ValidationStrategyDirect:
+	default:
+		return nil, fmt.Errorf("invalid validation strategy: %q", validationStrategy)
+	}
 	devUtilsABI, err := abi.JSON(strings.NewReader(wrappers.DevUtilsABI))
 	if err != nil {
 		return nil, err
 	}
-	devUtils, err := wrappers.NewDevUtilsCaller(contractAddresses.DevUtils, contractCaller)
+	exchangeABI, err := abi.JSON(strings.NewReader(wrappers.ExchangeABI))
+	if err != nil {
+		return nil, err
+	}
+	devUtils, err := wrappers.NewDevUtilsCaller(contractAddresses.DevUtils, withCallOptions(countCalls(contractCaller), callOptions))
 	if err != nil {
 		return nil, err
 	}
-	coordinatorRegistry, err := wrappers.NewCoordinatorRegistryCaller(contractAddresses.CoordinatorRegistry, contractCaller)
+	coordinatorRegistry, err := wrappers.NewCoordinatorRegistryCaller(contractAddresses.CoordinatorRegistry, withCallOptions(countCalls(contractCaller), callOptions))
 	if err != nil {
 		return nil, err
 	}
 	assetDataDecoder := zeroex.NewAssetDataDecoder()
 
-	return &OrderValidator{
+	orderValidator := &OrderValidator{
 		maxRequestContentLength:      maxRequestContentLength,
+		contractCaller:               contractCaller,
 		devUtilsABI:                  devUtilsABI,
+		exchangeABI:                  exchangeABI,
 		devUtils:                     devUtils,
+		devUtilsRaw:                  &wrappers.DevUtilsCallerRaw{Contract: devUtils},
 		coordinatorRegistry:          coordinatorRegistry,
 		assetDataDecoder:             assetDataDecoder,
 		chainID:                      chainID,
 		cachedFeeRecipientToEndpoint: map[common.Address]string{},
 		contractAddresses:            contractAddresses,
-	}, nil
+		validationStrategy:           validationStrategy,
+		callOptions:                  callOptions,
+	}
+	orderValidator.orderStateReader, err = orderValidator.newOrderStateReader(contractCaller)
+	if err != nil {
+		return nil, err
+	}
+	return orderValidator, nil
 }
 
 // BatchValidate retrieves all the information needed to validate the supplied orders.
@@ -278,6 +537,12 @@ func New(contractCaller bind.ContractCaller, chainID int, maxRequestContentLengt
 // The `blockNumber` parameter lets the caller specify a specific block height at which to validate
 // the orders. This can be set to the `latest` block or any other historical block number.
 func (o *OrderValidator) BatchValidate(ctx context.Context, rawSignedOrders []*zeroex.SignedOrder, areNewOrders bool, blockNumber *big.Int) *ValidationResults {
+	return o.batchValidate(ctx, rawSignedOrders, areNewOrders, o.orderStateReader, blockNumber)
+}
+
+// batchValidate implements BatchValidate using the given orderStateReader,
+// which determines the state that orders are validated against.
+func (o *OrderValidator) batchValidate(ctx context.Context, rawSignedOrders []*zeroex.SignedOrder, areNewOrders bool, stateReader orderStateReader, blockNumber *big.Int) *ValidationResults {
 	if len(rawSignedOrders) == 0 {
 		return &ValidationResults{}
 	}
@@ -293,6 +558,9 @@ func (o *OrderValidator) BatchValidate(ctx context.Context, rawSignedOrders []*z
 		validationResults.Rejected = append(validationResults.Rejected, rejectedOrderInfo)
 	}
 
+	signedOrders, unsupportedRejectedOrderInfos := o.filterOrdersUnsupportedByStrategy(signedOrders)
+	validationResults.Rejected = append(validationResults.Rejected, unsupportedRejectedOrderInfos...)
+
 	signedOrderChunks := [][]*zeroex.SignedOrder{}
 	chunkSizes := o.computeOptimalChunkSizes(signedOrders)
 	for _, chunkSize := range chunkSizes {
@@ -341,7 +609,7 @@ func (o *OrderValidator) BatchValidate(ctx context.Context, rawSignedOrders []*z
 				}
 				opts.BlockNumber = blockNumber
 
-				results, err := o.devUtils.GetOrderRelevantStates(opts, trimmedOrders, signatures)
+				results, err := stateReader.GetOrderRelevantStates(opts, trimmedOrders, signatures)
 				if err != nil {
 					log.WithFields(log.Fields{
 						"error":     err.Error(),
@@ -418,10 +686,9 @@ func (o *OrderValidator) BatchValidate(ctx context.Context, rawSignedOrders []*z
 						})
 						continue
 					case zeroex.OSFillable:
-						remainingTakerAssetAmount := big.NewInt(0).Sub(signedOrder.TakerAssetAmount, orderInfo.OrderTakerAssetFilledAmount)
 						// If `fillableTakerAssetAmount` != `remainingTakerAssetAmount`, the order is partially fillable. We consider
 						// partially fillable orders as invalid
-						if fillableTakerAssetAmount.Cmp(remainingTakerAssetAmount) != 0 {
+						if !isFullyFillable(signedOrder.TakerAssetAmount, orderInfo.OrderTakerAssetFilledAmount, fillableTakerAssetAmount) {
 							validationResults.Rejected = append(validationResults.Rejected, &RejectedOrderInfo{
 								OrderHash:   orderHash,
 								SignedOrder: signedOrder,
@@ -625,6 +892,26 @@ func (o *OrderValidator) batchValidateSoftCancelled(ctx context.Context, signedO
 	return validSignedOrders, rejectedOrderInfos
 }
 
+// isFullyFillable returns true if fillableTakerAssetAmount is equal to the
+// remaining taker asset amount of the order. Since this is checked for every
+// order that is validated, the arithmetic is done with fixed-width integers
+// instead of allocating a new big.Int.
+func isFullyFillable(takerAssetAmount, takerAssetFilledAmount, fillableTakerAssetAmount *big.Int) bool {
+	amount, ok1 := uint256.FromBig(takerAssetAmount)
+	filled, ok2 := uint256.FromBig(takerAssetFilledAmount)
+	fillable, ok3 := uint256.FromBig(fillableTakerAssetAmount)
+	if !ok1 || !ok2 || !ok3 {
+		remainingTakerAssetAmount := big.NewInt(0).Sub(takerAssetAmount, takerAssetFilledAmount)
+		return fillableTakerAssetAmount.Cmp(remainingTakerAssetAmount) == 0
+	}
+	remaining, underflow := amount.Sub(filled)
+	if underflow {
+		// The fillable amount can't be negative.
+		return false
+	}
+	return fillable.Cmp(remaining) == 0
+}
+
 // BatchOffchainValidation performs all off-chain validation checks on a batch of 0x orders.
 // These checks include:
 // - `MakerAssetAmount` and `TakerAssetAmount` cannot be 0
@@ -39,7 +39,9 @@ func (r RejectedOrderInfo) JSValue() js.Value {
 
 func (s RejectedOrderStatus) JSValue() js.Value {
 	return js.ValueOf(map[string]interface{}{
-		"code":    s.Code,
-		"message": s.Message,
+		"code":        s.Code,
+		"numericCode": s.NumericCode,
+		"category":    string(s.Category),
+		"message":     s.Message,
 	})
 }
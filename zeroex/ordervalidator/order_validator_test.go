@@ -143,7 +143,7 @@ func TestBatchValidateOffChainCases(t *testing.T) {
 		signedOrders := []*zeroex.SignedOrder{
 			testCase.SignedOrder,
 		}
-		orderValidator, err := New(ethClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses)
+		orderValidator, err := New(ethClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses, ValidationStrategyDevUtils, CallOptions{})
 		require.NoError(t, err)
 
 		offchainValidOrders, rejectedOrderInfos := orderValidator.BatchOffchainValidation(signedOrders)
@@ -168,7 +168,7 @@ func TestBatchValidateAValidOrder(t *testing.T) {
 		signedOrder,
 	}
 
-	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses)
+	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses, ValidationStrategyDevUtils, CallOptions{})
 	require.NoError(t, err)
 
 	ctx := context.Background()
@@ -202,7 +202,7 @@ func TestBatchOffchainValidateUnsupportedStaticCall(t *testing.T) {
 	ethRPCClient, err := ethrpcclient.New(rpcClient, defaultEthRPCTimeout, rateLimiter)
 	require.NoError(t, err)
 
-	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses)
+	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses, ValidationStrategyDevUtils, CallOptions{})
 	require.NoError(t, err)
 
 	accepted, rejected := orderValidator.BatchOffchainValidation(signedOrders)
@@ -224,7 +224,7 @@ func TestBatchOffchainValidateMaxGasPriceOrder(t *testing.T) {
 		t.Skip("Serial tests (tests which cannot run in parallel) are disabled. You can enable them with the --serial flag")
 	}
 
-	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses)
+	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses, ValidationStrategyDevUtils, CallOptions{})
 	require.NoError(t, err)
 
 	for _, staticCallAssetData := range [][]byte{
@@ -259,7 +259,7 @@ func TestBatchValidateMaxGasPriceOrder(t *testing.T) {
 		t.Skip("Serial tests (tests which cannot run in parallel) are disabled. You can enable them with the --serial flag")
 	}
 
-	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses)
+	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses, ValidationStrategyDevUtils, CallOptions{})
 	require.NoError(t, err)
 
 	for _, staticCallAssetData := range [][]byte{
@@ -298,7 +298,7 @@ func TestBatchValidateSignatureInvalid(t *testing.T) {
 	orderHash, err := signedOrder.ComputeOrderHash()
 	require.NoError(t, err)
 
-	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses)
+	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses, ValidationStrategyDevUtils, CallOptions{})
 	require.NoError(t, err)
 
 	ctx := context.Background()
@@ -321,7 +321,7 @@ func TestBatchValidateUnregisteredCoordinator(t *testing.T) {
 		signedOrder,
 	}
 
-	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses)
+	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses, ValidationStrategyDevUtils, CallOptions{})
 	require.NoError(t, err)
 
 	ctx := context.Background()
@@ -353,7 +353,7 @@ func TestBatchValidateCoordinatorSoftCancels(t *testing.T) {
 		signedOrder,
 	}
 
-	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses)
+	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses, ValidationStrategyDevUtils, CallOptions{})
 	require.NoError(t, err)
 
 	// generate a test server so we can capture and inspect the request
@@ -395,12 +395,47 @@ func TestBatchValidateCoordinatorSoftCancels(t *testing.T) {
 	assert.Equal(t, orderHash, validationResults.Rejected[0].OrderHash)
 }
 
+func TestSimulateMakerTransfers(t *testing.T) {
+	if !serialTestsEnabled {
+		t.Skip("Serial tests (tests which cannot run in parallel) are disabled. You can enable them with the --serial flag")
+	}
+
+	teardownSubTest := setupSubTest(t)
+	defer teardownSubTest(t)
+
+	signedOrder := scenario.NewSignedTestOrder(t, orderopts.SetupMakerState(true))
+	orderHash, err := signedOrder.ComputeOrderHash()
+	require.NoError(t, err)
+
+	orderValidator, err := New(ethRPCClient, constants.TestChainID, constants.TestMaxContentLength, ganacheAddresses, ValidationStrategyDevUtils, CallOptions{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	latestBlock, err := ethRPCClient.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	orderInfos := []*AcceptedOrderInfo{
+		{
+			OrderHash:                orderHash,
+			SignedOrder:              signedOrder,
+			FillableTakerAssetAmount: signedOrder.TakerAssetAmount,
+			IsNew:                    true,
+		},
+	}
+	failedOrderHashes, err := orderValidator.SimulateMakerTransfers(ctx, orderInfos, latestBlock.Number)
+	require.NoError(t, err)
+	assert.Empty(t, failedOrderHashes, "transfer simulation should succeed for a standard ERC20 maker asset")
+}
+
+func TestEncodeNoopStaticCallAssetData(t *testing.T) {
+	assert.Equal(t, checkGasPriceDefaultStaticCallData, encodeNoopStaticCallAssetData(ganacheAddresses.MaximumGasPrice))
+}
+
 const singleOrderPayloadSize = 2236
 
 func TestComputeOptimalChunkSizesMaxContentLengthTooLow(t *testing.T) {
 	signedOrder := scenario.NewSignedTestOrder(t)
 	maxContentLength := singleOrderPayloadSize - 10
-	orderValidator, err := New(ethRPCClient, constants.TestChainID, maxContentLength, ganacheAddresses)
+	orderValidator, err := New(ethRPCClient, constants.TestChainID, maxContentLength, ganacheAddresses, ValidationStrategyDevUtils, CallOptions{})
 	require.NoError(t, err)
 
 	signedOrders := []*zeroex.SignedOrder{signedOrder}
@@ -412,7 +447,7 @@ func TestComputeOptimalChunkSizesMaxContentLengthTooLow(t *testing.T) {
 func TestComputeOptimalChunkSizes(t *testing.T) {
 	signedOrder := scenario.NewSignedTestOrder(t)
 	maxContentLength := singleOrderPayloadSize * 3
-	orderValidator, err := New(ethRPCClient, constants.TestChainID, maxContentLength, ganacheAddresses)
+	orderValidator, err := New(ethRPCClient, constants.TestChainID, maxContentLength, ganacheAddresses, ValidationStrategyDevUtils, CallOptions{})
 	require.NoError(t, err)
 
 	signedOrders := []*zeroex.SignedOrder{signedOrder, signedOrder, signedOrder, signedOrder}
@@ -426,7 +461,7 @@ func TestComputeOptimalChunkSizesMultiAssetOrder(t *testing.T) {
 	signedMultiAssetOrder := scenario.NewSignedTestOrder(t, orderopts.MakerAssetData(multiAssetAssetData))
 
 	maxContentLength := singleOrderPayloadSize * 3
-	orderValidator, err := New(ethRPCClient, constants.TestChainID, maxContentLength, ganacheAddresses)
+	orderValidator, err := New(ethRPCClient, constants.TestChainID, maxContentLength, ganacheAddresses, ValidationStrategyDevUtils, CallOptions{})
 	require.NoError(t, err)
 
 	signedOrders := []*zeroex.SignedOrder{signedMultiAssetOrder, signedOrder, signedOrder, signedOrder, signedOrder}
@@ -451,3 +486,74 @@ func signedOrderWithCustomSignature(t *testing.T, signature []byte) *zeroex.Sign
 func copyOrder(order zeroex.Order) zeroex.Order {
 	return order
 }
+
+func TestRejectedOrderStatusesAreUnique(t *testing.T) {
+	codes := map[string]bool{}
+	numericCodes := map[int]bool{}
+	categoryByHundreds := map[int]RejectedOrderCategory{
+		1: ValidationCategory,
+		2: MeshPolicyCategory,
+		3: NetworkCategory,
+		9: InternalCategory,
+	}
+	for _, status := range RejectedOrderStatuses {
+		assert.False(t, codes[status.Code], "duplicate code %s", status.Code)
+		assert.False(t, numericCodes[status.NumericCode], "duplicate numeric code %d", status.NumericCode)
+		codes[status.Code] = true
+		numericCodes[status.NumericCode] = true
+		assert.Equal(t, categoryByHundreds[status.NumericCode/100], status.Category, "wrong category for %s", status.Code)
+
+		found, ok := FindRejectedOrderStatusByCode(status.Code)
+		require.True(t, ok)
+		assert.Equal(t, status, found)
+		found, ok = FindRejectedOrderStatusByNumericCode(status.NumericCode)
+		require.True(t, ok)
+		assert.Equal(t, status, found)
+	}
+	_, ok := FindRejectedOrderStatusByCode("NotARejectedOrderStatus")
+	assert.False(t, ok)
+}
+
+func TestIsFullyFillable(t *testing.T) {
+	tooLarge := new(big.Int).Lsh(big.NewInt(1), 256)
+	testCases := []struct {
+		takerAssetAmount         *big.Int
+		takerAssetFilledAmount   *big.Int
+		fillableTakerAssetAmount *big.Int
+		expected                 bool
+	}{
+		{big.NewInt(100), big.NewInt(0), big.NewInt(100), true},
+		{big.NewInt(100), big.NewInt(40), big.NewInt(60), true},
+		{big.NewInt(100), big.NewInt(40), big.NewInt(50), false},
+		{big.NewInt(100), big.NewInt(101), big.NewInt(0), false},
+		{new(big.Int).Lsh(big.NewInt(1), 200), big.NewInt(1), new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 200), big.NewInt(1)), true},
+		{tooLarge, big.NewInt(1), new(big.Int).Sub(tooLarge, big.NewInt(1)), true},
+	}
+	for i, tc := range testCases {
+		assert.Equal(t, tc.expected, isFullyFillable(tc.takerAssetAmount, tc.takerAssetFilledAmount, tc.fillableTakerAssetAmount), "test case %d", i)
+	}
+}
+
+func BenchmarkIsFullyFillable(b *testing.B) {
+	takerAssetAmount := new(big.Int).Lsh(big.NewInt(1), 100)
+	takerAssetFilledAmount := big.NewInt(12345)
+	fillableTakerAssetAmount := new(big.Int).Sub(takerAssetAmount, takerAssetFilledAmount)
+
+	b.Run("uint256", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if !isFullyFillable(takerAssetAmount, takerAssetFilledAmount, fillableTakerAssetAmount) {
+				b.Fatal("expected order to be fully fillable")
+			}
+		}
+	})
+	b.Run("big.Int", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			remainingTakerAssetAmount := big.NewInt(0).Sub(takerAssetAmount, takerAssetFilledAmount)
+			if fillableTakerAssetAmount.Cmp(remainingTakerAssetAmount) != 0 {
+				b.Fatal("expected order to be fully fillable")
+			}
+		}
+	})
+}
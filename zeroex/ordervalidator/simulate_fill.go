@@ -0,0 +1,163 @@
+package ordervalidator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/0xProject/0x-mesh/ethereum/wrappers"
+	"github.com/0xProject/0x-mesh/zeroex"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrSimulationNotSupported is returned by SimulateFill if the Ethereum RPC
+// client used by the OrderValidator does not support making raw JSON-RPC
+// requests.
+var ErrSimulationNotSupported = errors.New("simulating fills is not supported by this Ethereum RPC client")
+
+// errorStringSelector is the selector of Error(string), which is used by
+// Solidity to encode revert reasons.
+var errorStringSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// errorStringABI is used to decode revert reasons encoded as Error(string).
+var errorStringABI = mustParseABI(`[{"name":"Error","type":"function","inputs":[{"name":"message","type":"string"}],"outputs":[{"name":"message","type":"string"}]}]`)
+
+func mustParseABI(json string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(json))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// SimulateFillOpts are the parameters of a simulated fill.
+type SimulateFillOpts struct {
+	// TakerAddress is the address the fill is simulated from.
+	TakerAddress common.Address
+	// TakerAssetFillAmount is the amount of the taker asset to fill.
+	TakerAssetFillAmount *big.Int
+	// GasPrice is the gas price of the simulated transaction. It determines the
+	// protocol fee, which is sent along with the transaction in ether. If nil
+	// or 0, no protocol fee is paid, so fills can succeed even if the taker
+	// couldn't afford the protocol fee.
+	GasPrice *big.Int
+}
+
+// FillSimulation is the result of simulating a call to the fillOrder method
+// of the Exchange contract.
+type FillSimulation struct {
+	// Success is true if the simulated fill did not revert.
+	Success bool
+	// FillResults are the amounts filled and fees paid by the simulated fill.
+	// They are only set if Success is true.
+	FillResults wrappers.Struct5
+	// RevertReason explains why the simulated fill reverted. It is only set if
+	// Success is false. If the revert reason cannot be decoded, it contains the
+	// error returned by the Ethereum RPC endpoint.
+	RevertReason string
+	// EstimatedGas is the amount of gas the fill is expected to use. It is only
+	// set if Success is true.
+	EstimatedGas uint64
+}
+
+// SimulateFill simulates filling the given order via eth_call at the latest
+// block and estimates the gas required to do so. An error is only returned if
+// the simulation could not be run. If the simulated fill reverts, the reason is
+// returned as part of the FillSimulation instead.
+func (o *OrderValidator) SimulateFill(ctx context.Context, signedOrder *zeroex.SignedOrder, opts SimulateFillOpts) (*FillSimulation, error) {
+	client, ok := o.contractCaller.(rawCaller)
+	if !ok {
+		return nil, ErrSimulationNotSupported
+	}
+	if opts.TakerAssetFillAmount == nil || opts.TakerAssetFillAmount.Sign() <= 0 {
+		return nil, errors.New("takerAssetFillAmount must be positive")
+	}
+	data, err := o.exchangeABI.Pack("fillOrder", signedOrder.Trim(), opts.TakerAssetFillAmount, signedOrder.Signature)
+	if err != nil {
+		return nil, err
+	}
+	exchangeAddress := o.contractAddresses.Exchange
+	msg := ethereum.CallMsg{
+		From: opts.TakerAddress,
+		To:   &exchangeAddress,
+		Data: data,
+	}
+	if opts.GasPrice != nil && opts.GasPrice.Sign() > 0 {
+		exchange, err := wrappers.NewExchangeCaller(exchangeAddress, o.contractCaller)
+		if err != nil {
+			return nil, err
+		}
+		protocolFeeMultiplier, err := exchange.ProtocolFeeMultiplier(&bind.CallOpts{Context: ctx})
+		if err != nil {
+			return nil, err
+		}
+		msg.GasPrice = opts.GasPrice
+		msg.Value = new(big.Int).Mul(opts.GasPrice, protocolFeeMultiplier)
+	}
+
+	var output hexutil.Bytes
+	if err := client.CallContext(ctx, &output, "eth_call", toCallArg(msg), "latest"); err != nil {
+		if revertReason, ok := revertReasonFromError(err); ok {
+			return &FillSimulation{RevertReason: revertReason}, nil
+		}
+		return nil, err
+	}
+	// Some Ethereum nodes return the revert data instead of an error.
+	if bytes.HasPrefix(output, errorStringSelector) {
+		return &FillSimulation{RevertReason: decodeRevertReason(output)}, nil
+	}
+	simulation := &FillSimulation{Success: true}
+	if err := o.exchangeABI.Unpack(&simulation.FillResults, "fillOrder", output); err != nil {
+		return nil, err
+	}
+
+	var estimatedGas hexutil.Uint64
+	if err := client.CallContext(ctx, &estimatedGas, "eth_estimateGas", toCallArg(msg)); err != nil {
+		return nil, err
+	}
+	simulation.EstimatedGas = uint64(estimatedGas)
+	return simulation, nil
+}
+
+// dataError is implemented by JSON-RPC errors which include additional data,
+// such as the revert data of a failed eth_call.
+type dataError interface {
+	ErrorData() interface{}
+}
+
+// revertReasonFromError returns the revert reason of a failed eth_call. It
+// returns false if err does not indicate that the call reverted (e.g. because
+// of a network error).
+func revertReasonFromError(err error) (string, bool) {
+	if dataErr, ok := err.(dataError); ok {
+		if hexData, ok := dataErr.ErrorData().(string); ok {
+			if data, err := hexutil.Decode(hexData); err == nil && len(data) > 0 {
+				return decodeRevertReason(data), true
+			}
+		}
+	}
+	message := err.Error()
+	if strings.Contains(message, "revert") || strings.Contains(message, "VM Exception") || strings.Contains(message, "out of gas") || strings.Contains(message, "insufficient funds") {
+		return message, true
+	}
+	return "", false
+}
+
+// decodeRevertReason decodes revert data encoded as Error(string). Other
+// revert data (e.g. the rich revert errors of the Exchange contract) is
+// returned hex-encoded.
+func decodeRevertReason(data []byte) string {
+	if bytes.HasPrefix(data, errorStringSelector) {
+		var reason string
+		if err := errorStringABI.Unpack(&reason, "Error", data[len(errorStringSelector):]); err == nil {
+			return reason
+		}
+	}
+	return hexutil.Encode(data)
+}
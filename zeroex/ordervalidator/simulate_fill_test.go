@@ -0,0 +1,46 @@
+// +build !js
+
+package ordervalidator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type revertError struct {
+	data string
+}
+
+func (e revertError) Error() string {
+	return "execution reverted"
+}
+
+func (e revertError) ErrorData() interface{} {
+	return e.data
+}
+
+func TestDecodeRevertReason(t *testing.T) {
+	// Error("ORDER_EXPIRED")
+	data := hexutil.MustDecode("0x08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000d4f524445525f4558504952454400000000000000000000000000000000000000")
+	assert.Equal(t, "ORDER_EXPIRED", decodeRevertReason(data))
+
+	// Rich revert errors are returned hex-encoded.
+	richRevert := []byte{0xf5, 0x98, 0x51, 0xb2, 0x01}
+	assert.Equal(t, "0xf59851b201", decodeRevertReason(richRevert))
+}
+
+func TestRevertReasonFromError(t *testing.T) {
+	reason, ok := revertReasonFromError(revertError{data: "0x08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000d4f524445525f4558504952454400000000000000000000000000000000000000"})
+	assert.True(t, ok)
+	assert.Equal(t, "ORDER_EXPIRED", reason)
+
+	reason, ok = revertReasonFromError(errors.New("VM Exception while processing transaction: revert"))
+	assert.True(t, ok)
+	assert.Equal(t, "VM Exception while processing transaction: revert", reason)
+
+	_, ok = revertReasonFromError(errors.New("connection refused"))
+	assert.False(t, ok)
+}
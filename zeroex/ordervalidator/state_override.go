@@ -0,0 +1,137 @@
+package ordervalidator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/0xProject/0x-mesh/zeroex"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrStateOptionsNotSupported is returned by BatchValidateAtState if the
+// Ethereum RPC client used by the OrderValidator does not support making raw
+// JSON-RPC requests.
+var ErrStateOptionsNotSupported = errors.New("validating orders against pending state or state overrides is not supported by this Ethereum RPC client")
+
+// StateOverride overrides the state of a single account for the duration of
+// an eth_call. It uses the same format as the optional third parameter of
+// eth_call supported by Geth and many other Ethereum nodes. Any nil or empty
+// fields are left unchanged.
+type StateOverride struct {
+	// Nonce overrides the nonce of the account.
+	Nonce *hexutil.Uint64 `json:"nonce,omitempty"`
+	// Code overrides the code of the account.
+	Code *hexutil.Bytes `json:"code,omitempty"`
+	// Balance overrides the ether balance of the account.
+	Balance *hexutil.Big `json:"balance,omitempty"`
+	// State replaces the entire storage of the account.
+	State map[common.Hash]common.Hash `json:"state,omitempty"`
+	// StateDiff replaces individual storage slots of the account.
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+}
+
+// StateOverrides is a set of account state overrides, keyed by address.
+type StateOverrides map[common.Address]StateOverride
+
+// StateOptions determines the state of the blockchain that orders are
+// validated against by BatchValidateAtState.
+type StateOptions struct {
+	// Pending determines whether orders are validated against the pending
+	// state (i.e. including transactions which have not been mined yet)
+	// instead of the latest block. Note that the pending state depends on the
+	// transaction pool of the Ethereum node Mesh is connected to.
+	Pending bool
+	// StateOverrides are applied on top of the latest or pending state. They
+	// can be used to answer "what if" questions, e.g. whether an order would
+	// still be fillable after a transaction which changes the maker's balance.
+	StateOverrides StateOverrides
+}
+
+// rawCaller is implemented by Ethereum RPC clients which can make raw
+// JSON-RPC requests, such as ethrpcclient.Client.
+type rawCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// stateCaller is a bind.ContractCaller which makes every eth_call at the given
+// block tag and with the given state overrides.
+type stateCaller struct {
+	client    rawCaller
+	blockTag  string
+	overrides StateOverrides
+}
+
+// CodeAt implements bind.ContractCaller. The blockNumber is ignored in favor of
+// the block tag of the stateCaller.
+func (c *stateCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	var result hexutil.Bytes
+	if err := c.client.CallContext(ctx, &result, "eth_getCode", contract, c.blockTag); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CallContract implements bind.ContractCaller. The blockNumber is ignored in
+// favor of the block tag of the stateCaller.
+func (c *stateCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	args := []interface{}{toCallArg(call), c.blockTag}
+	if len(c.overrides) > 0 {
+		args = append(args, c.overrides)
+	}
+	var result hexutil.Bytes
+	if err := c.client.CallContext(ctx, &result, "eth_call", args...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// toCallArg converts a CallMsg into the format expected by eth_call. It is
+// equivalent to the unexported function of the same name in ethclient.
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	return arg
+}
+
+// BatchValidateAtState works like BatchValidate but validates the orders
+// against the pending state and/or with the given state overrides instead of
+// at a specific block. Since the results don't correspond to a specific block,
+// they should not be used to update stored orders.
+func (o *OrderValidator) BatchValidateAtState(ctx context.Context, rawSignedOrders []*zeroex.SignedOrder, stateOpts StateOptions) (*ValidationResults, error) {
+	client, ok := o.contractCaller.(rawCaller)
+	if !ok {
+		return nil, ErrStateOptionsNotSupported
+	}
+	blockTag := "latest"
+	if stateOpts.Pending {
+		blockTag = "pending"
+	}
+	caller := &stateCaller{
+		client:    client,
+		blockTag:  blockTag,
+		overrides: stateOpts.StateOverrides,
+	}
+	stateReader, err := o.newOrderStateReader(caller)
+	if err != nil {
+		return nil, err
+	}
+	areNewOrders := false
+	return o.batchValidate(ctx, rawSignedOrders, areNewOrders, stateReader, nil), nil
+}
@@ -0,0 +1,80 @@
+// +build !js
+
+package ordervalidator
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingCaller is a rawCaller which records the requests it receives and
+// responds with a fixed result.
+type recordingCaller struct {
+	method string
+	args   []interface{}
+	result string
+}
+
+func (c *recordingCaller) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	c.method = method
+	c.args = args
+	return json.Unmarshal([]byte(c.result), result)
+}
+
+func TestStateCallerCallContract(t *testing.T) {
+	to := common.HexToAddress("0x1")
+	msg := ethereum.CallMsg{
+		From: common.HexToAddress("0x2"),
+		To:   &to,
+		Data: []byte{0xab, 0xcd},
+	}
+	balance := hexutil.Big(*big.NewInt(100))
+	overrides := StateOverrides{
+		common.HexToAddress("0x3"): StateOverride{
+			Balance: &balance,
+			StateDiff: map[common.Hash]common.Hash{
+				common.HexToHash("0x4"): common.HexToHash("0x5"),
+			},
+		},
+	}
+
+	client := &recordingCaller{result: `"0x1234"`}
+	caller := &stateCaller{client: client, blockTag: "pending", overrides: overrides}
+	result, err := caller.CallContract(context.Background(), msg, big.NewInt(42))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x12, 0x34}, result)
+	assert.Equal(t, "eth_call", client.method)
+	require.Len(t, client.args, 3)
+	assert.Equal(t, "pending", client.args[1], "block number should be ignored in favor of the block tag")
+
+	// Check that the request is encoded in the format expected by eth_call.
+	encoded, err := json.Marshal(client.args)
+	require.NoError(t, err)
+	expected := `[{"data":"0xabcd","from":"0x0000000000000000000000000000000000000002","to":"0x0000000000000000000000000000000000000001"},"pending",{"0x0000000000000000000000000000000000000003":{"balance":"0x64","stateDiff":{"0x0000000000000000000000000000000000000000000000000000000000000004":"0x0000000000000000000000000000000000000000000000000000000000000005"}}}]`
+	assert.JSONEq(t, expected, string(encoded))
+
+	// State overrides are omitted entirely if there are none.
+	caller = &stateCaller{client: client, blockTag: "latest"}
+	_, err = caller.CallContract(context.Background(), msg, nil)
+	require.NoError(t, err)
+	require.Len(t, client.args, 2)
+	assert.Equal(t, "latest", client.args[1])
+}
+
+func TestStateCallerCodeAt(t *testing.T) {
+	client := &recordingCaller{result: `"0x6080"`}
+	caller := &stateCaller{client: client, blockTag: "pending"}
+	code, err := caller.CodeAt(context.Background(), common.HexToAddress("0x1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x60, 0x80}, code)
+	assert.Equal(t, "eth_getCode", client.method)
+	assert.Equal(t, []interface{}{common.HexToAddress("0x1"), "pending"}, client.args)
+}
@@ -0,0 +1,244 @@
+package ordervalidator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/ethereum/wrappers"
+	"github.com/0xProject/0x-mesh/zeroex"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	log "github.com/sirupsen/logrus"
+)
+
+// transferSimulationTakerAddress is the taker address used when simulating
+// transfers. It only needs to be an address which is able to receive tokens.
+var transferSimulationTakerAddress = common.HexToAddress("0x000000000000000000000000000000000000dead")
+
+// orderTransferResult enumerates the possible return values of the DevUtils
+// getSimulatedOrderTransferResults method.
+type orderTransferResult uint8
+
+// orderTransferResult values
+const (
+	otrTakerAssetDataFailed orderTransferResult = iota
+	otrMakerAssetDataFailed
+	otrTakerFeeAssetDataFailed
+	otrMakerFeeAssetDataFailed
+	otrTransfersSuccessful
+)
+
+// ErrTransferSimulationUnsupported is returned by SimulateMakerTransfers when
+// the MaximumGasPrice contract is not deployed on the configured chain.
+var ErrTransferSimulationUnsupported = errors.New("transfer simulation requires the MaximumGasPrice contract to be deployed")
+
+// balanceDeltaProbeCode is the runtime code of a contract which transfers
+// ERC20 tokens and returns the amount that the recipient actually received.
+// It is placed at the maker's address via a state override, so that it
+// transfers the maker's tokens without needing an allowance. The calldata
+// consists of three words: the token address, the recipient address and the
+// amount to transfer. It reverts if a call to the token fails or if transfer
+// returns false, and otherwise returns balanceOf(recipient) after the
+// transfer minus balanceOf(recipient) before it. Disassembled:
+//
+//	mstore(0x00, 0x70a08231)                       // balanceOf(address)
+//	mstore(0x20, calldataload(0x20))               // recipient
+//	staticcall(gas, calldataload(0x00), 0x1c, 0x24, 0x80, 0x20) or revert
+//	before := mload(0x80)
+//	mstore(0x00, 0xa9059cbb)                       // transfer(address,uint256)
+//	mstore(0x40, calldataload(0x40))               // amount
+//	call(gas, calldataload(0x00), 0, 0x1c, 0x44, 0x80, 0x20) or revert
+//	if returndatasize != 0 && mload(0x80) == 0 { revert }
+//	mstore(0x00, 0x70a08231)
+//	staticcall(gas, calldataload(0x00), 0x1c, 0x24, 0x80, 0x20) or revert
+//	return(mload(0x80) - before)
+var balanceDeltaProbeCode = common.FromHex("0x6370a08231600052602035602052602060806024601c6000355afa1560755760805163a9059cbb600052604035604052602060806044601c60006000355af1156075573d15604f57608051156075575b6370a08231600052602060806024601c6000355afa156075576080510360005260206000f35b600080fd")
+
+// SimulateMakerTransfers simulates the transfers of the maker's assets (and
+// maker fee) that would occur when filling each of the given orders for their
+// fillable amount. It is a heuristic for detecting orders that pass validation
+// but fail when filled, e.g. because the maker token is paused or uses
+// transfer hooks which revert. If the maker asset is an ERC20 token, it also
+// compares the taker's balance before and after a simulated transfer of the
+// maker asset, so that tokens which charge a fee on transfer or otherwise
+// deliver less (or more) than the transferred amount are detected too. The
+// balance comparison relies on eth_call state overrides and is skipped if the
+// Ethereum RPC client or node doesn't support them. It returns the hashes of
+// all orders for which the simulation failed.
+func (o *OrderValidator) SimulateMakerTransfers(ctx context.Context, orderInfos []*AcceptedOrderInfo, blockNumber *big.Int) (map[common.Hash]struct{}, error) {
+	if o.contractAddresses.MaximumGasPrice == constants.NullAddress {
+		return nil, ErrTransferSimulationUnsupported
+	}
+	takerAssetData := encodeNoopStaticCallAssetData(o.contractAddresses.MaximumGasPrice)
+
+	failedOrderHashes := map[common.Hash]struct{}{}
+	mu := sync.Mutex{}
+	semaphoreChan := make(chan struct{}, concurrencyLimit)
+	wg := &sync.WaitGroup{}
+	var firstErr error
+	for _, orderInfo := range orderInfos {
+		wg.Add(1)
+		go func(orderInfo *AcceptedOrderInfo) {
+			defer wg.Done()
+			semaphoreChan <- struct{}{}
+			defer func() { <-semaphoreChan }()
+
+			result, err := o.simulateMakerTransfers(ctx, orderInfo, takerAssetData, blockNumber)
+			receivedExpectedAmount := true
+			if err == nil && result == otrTransfersSuccessful {
+				receivedExpectedAmount, err = o.checkReceivedMakerAssetAmount(ctx, orderInfo, blockNumber)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			if result != otrTransfersSuccessful {
+				log.WithFields(log.Fields{
+					"orderHash": orderInfo.OrderHash.Hex(),
+					"result":    result,
+				}).Debug("simulated maker transfers failed")
+				failedOrderHashes[orderInfo.OrderHash] = struct{}{}
+			} else if !receivedExpectedAmount {
+				log.WithFields(log.Fields{
+					"orderHash": orderInfo.OrderHash.Hex(),
+				}).Debug("simulated maker transfer delivered a different amount than was transferred")
+				failedOrderHashes[orderInfo.OrderHash] = struct{}{}
+			}
+		}(orderInfo)
+	}
+	wg.Wait()
+
+	return failedOrderHashes, firstErr
+}
+
+func (o *OrderValidator) simulateMakerTransfers(ctx context.Context, orderInfo *AcceptedOrderInfo, takerAssetData []byte, blockNumber *big.Int) (orderTransferResult, error) {
+	// In order to only simulate the maker side of the fill, the taker asset is
+	// replaced with a StaticCall asset which always succeeds and the taker fee is
+	// removed. The remaining amounts are unchanged so that the simulated fill
+	// transfers exactly what a real fill for the fillable amount would.
+	signedOrder := orderInfo.SignedOrder
+	probeOrder := wrappers.TrimmedOrder{
+		MakerAddress:          signedOrder.MakerAddress,
+		TakerAddress:          constants.NullAddress,
+		FeeRecipientAddress:   signedOrder.FeeRecipientAddress,
+		SenderAddress:         constants.NullAddress,
+		MakerAssetAmount:      signedOrder.MakerAssetAmount,
+		TakerAssetAmount:      signedOrder.TakerAssetAmount,
+		MakerFee:              signedOrder.MakerFee,
+		TakerFee:              big.NewInt(0),
+		ExpirationTimeSeconds: signedOrder.ExpirationTimeSeconds,
+		Salt:                  signedOrder.Salt,
+		MakerAssetData:        signedOrder.MakerAssetData,
+		TakerAssetData:        takerAssetData,
+		MakerFeeAssetData:     signedOrder.MakerFeeAssetData,
+		TakerFeeAssetData:     []byte{},
+	}
+	opts := &bind.CallOpts{
+		// HACK(albrow): From field should not be required for eth_call but
+		// including it here is a workaround for a bug in Ganache. Removing
+		// this line causes Ganache to crash.
+		From:        constants.GanacheDummyERC721TokenAddress,
+		Pending:     false,
+		Context:     ctx,
+		BlockNumber: blockNumber,
+	}
+	var result uint8
+	err := o.devUtilsRaw.Call(opts, &result, "getSimulatedOrderTransferResults", probeOrder, transferSimulationTakerAddress, orderInfo.FillableTakerAssetAmount)
+	if err != nil {
+		return 0, err
+	}
+	return orderTransferResult(result), nil
+}
+
+// checkReceivedMakerAssetAmount simulates transferring the maker asset amount
+// that filling the order for its fillable amount would transfer from the maker
+// to the taker, and returns false if the taker's balance changes by a
+// different amount. It returns true without checking anything if the maker
+// asset is not an ERC20 token or if state overrides are not supported.
+func (o *OrderValidator) checkReceivedMakerAssetAmount(ctx context.Context, orderInfo *AcceptedOrderInfo, blockNumber *big.Int) (bool, error) {
+	client, ok := o.contractCaller.(rawCaller)
+	if !ok {
+		return true, nil
+	}
+	signedOrder := orderInfo.SignedOrder
+	assetDataName, err := o.assetDataDecoder.GetName(signedOrder.MakerAssetData)
+	if err != nil || assetDataName != "ERC20Token" {
+		return true, nil
+	}
+	var makerAssetData zeroex.ERC20AssetData
+	if err := o.assetDataDecoder.Decode(signedOrder.MakerAssetData, &makerAssetData); err != nil {
+		return true, nil
+	}
+	if signedOrder.TakerAssetAmount.Sign() == 0 {
+		return true, nil
+	}
+	// The Exchange contract rounds the maker amount down when filling an order
+	// partially.
+	amount := new(big.Int).Mul(signedOrder.MakerAssetAmount, orderInfo.FillableTakerAssetAmount)
+	amount.Quo(amount, signedOrder.TakerAssetAmount)
+
+	blockTag := "latest"
+	if blockNumber != nil {
+		blockTag = hexutil.EncodeBig(blockNumber)
+	}
+	code := hexutil.Bytes(balanceDeltaProbeCode)
+	caller := &stateCaller{
+		client:   client,
+		blockTag: blockTag,
+		overrides: StateOverrides{
+			signedOrder.MakerAddress: StateOverride{Code: &code},
+		},
+	}
+	data := common.LeftPadBytes(makerAssetData.Address.Bytes(), 32)
+	data = append(data, common.LeftPadBytes(transferSimulationTakerAddress.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(amount.Bytes(), 32)...)
+	makerAddress := signedOrder.MakerAddress
+	result, err := caller.CallContract(ctx, ethereum.CallMsg{
+		// See the HACK comment in simulateMakerTransfers.
+		From: constants.GanacheDummyERC721TokenAddress,
+		To:   &makerAddress,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return false, err
+	}
+	if len(result) == 0 {
+		// Nodes which don't support state overrides (e.g. Ganache) ignore them,
+		// in which case the call to the maker's address returns nothing.
+		return true, nil
+	}
+	if len(result) != 32 {
+		return false, fmt.Errorf("unexpected balance delta probe result length: %d", len(result))
+	}
+	received := new(big.Int).SetBytes(result)
+	return received.Cmp(amount) == 0, nil
+}
+
+// encodeNoopStaticCallAssetData returns StaticCall asset data which calls the
+// no-argument checkGasPrice method of the given MaximumGasPrice contract. The
+// call returns no data and only fails if the gas price exceeds the default
+// maximum, so it always succeeds within an eth_call.
+func encodeNoopStaticCallAssetData(maximumGasPrice common.Address) []byte {
+	staticCallData := common.Hex2Bytes(zeroex.CheckGasPriceDefaultID)
+	emptyReturnDataHash := crypto.Keccak256Hash([]byte{})
+
+	assetData := common.Hex2Bytes(zeroex.StaticCallAssetDataID)
+	assetData = append(assetData, common.LeftPadBytes(maximumGasPrice.Bytes(), 32)...)
+	// Offset of the staticCallData bytes, relative to the start of the arguments.
+	assetData = append(assetData, common.LeftPadBytes(big.NewInt(3*32).Bytes(), 32)...)
+	assetData = append(assetData, emptyReturnDataHash.Bytes()...)
+	assetData = append(assetData, common.LeftPadBytes(big.NewInt(int64(len(staticCallData))).Bytes(), 32)...)
+	assetData = append(assetData, common.RightPadBytes(staticCallData, 32)...)
+	return assetData
+}
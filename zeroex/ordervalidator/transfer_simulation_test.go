@@ -0,0 +1,116 @@
+// +build !js
+
+package ordervalidator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/zeroex"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTokenCaller emulates the eth_call made by checkReceivedMakerAssetAmount
+// for an ERC20 token which keeps feeBasisPoints of every transfer as a fee.
+type mockTokenCaller struct {
+	maker          common.Address
+	token          common.Address
+	feeBasisPoints int64
+	// ignoresStateOverrides emulates a node which doesn't support state
+	// overrides, in which case the call to the maker's address returns
+	// nothing.
+	ignoresStateOverrides bool
+}
+
+func (c *mockTokenCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, errors.New("unexpected call to CodeAt")
+}
+
+func (c *mockTokenCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, errors.New("unexpected call to CallContract")
+}
+
+func (c *mockTokenCaller) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method != "eth_call" || len(args) != 3 {
+		return errors.New("unexpected request")
+	}
+	if c.ignoresStateOverrides {
+		*result.(*hexutil.Bytes) = hexutil.Bytes{}
+		return nil
+	}
+	overrides := args[2].(StateOverrides)
+	if code := overrides[c.maker].Code; code == nil || !bytes.Equal(balanceDeltaProbeCode, *code) {
+		return errors.New("expected the probe code to be placed at the maker's address")
+	}
+	callArg := args[0].(map[string]interface{})
+	if to := callArg["to"].(*common.Address); *to != c.maker {
+		return errors.New("expected the maker's address to be called")
+	}
+	data := callArg["data"].(hexutil.Bytes)
+	if len(data) != 96 || common.BytesToAddress(data[:32]) != c.token || common.BytesToAddress(data[32:64]) != transferSimulationTakerAddress {
+		return errors.New("unexpected calldata")
+	}
+	amount := new(big.Int).SetBytes(data[64:])
+	fee := new(big.Int).Div(new(big.Int).Mul(amount, big.NewInt(c.feeBasisPoints)), big.NewInt(10000))
+	received := new(big.Int).Sub(amount, fee)
+	*result.(*hexutil.Bytes) = common.LeftPadBytes(received.Bytes(), 32)
+	return nil
+}
+
+func TestCheckReceivedMakerAssetAmount(t *testing.T) {
+	maker := common.HexToAddress("0x6ecbe1db9ef729cbe972c83fb886247691fb6beb")
+	token := common.HexToAddress("0x871dd7c2b4b25e1aa18728e9d5f2af4c4e431f5c")
+	makerAssetData, err := zeroex.EncodeERC20AssetData(token)
+	require.NoError(t, err)
+	orderInfo := &AcceptedOrderInfo{
+		SignedOrder: &zeroex.SignedOrder{
+			Order: zeroex.Order{
+				MakerAddress:     maker,
+				MakerAssetData:   makerAssetData,
+				MakerAssetAmount: big.NewInt(1000),
+				TakerAssetAmount: big.NewInt(100),
+			},
+		},
+		FillableTakerAssetAmount: big.NewInt(50),
+	}
+
+	testCases := []struct {
+		name           string
+		caller         *mockTokenCaller
+		expectReceived bool
+	}{
+		{
+			name:           "standard token",
+			caller:         &mockTokenCaller{maker: maker, token: token},
+			expectReceived: true,
+		},
+		{
+			name:           "fee on transfer token",
+			caller:         &mockTokenCaller{maker: maker, token: token, feeBasisPoints: 100},
+			expectReceived: false,
+		},
+		{
+			name:           "state overrides not supported",
+			caller:         &mockTokenCaller{maker: maker, token: token, feeBasisPoints: 100, ignoresStateOverrides: true},
+			expectReceived: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			orderValidator := &OrderValidator{
+				contractCaller:   tc.caller,
+				assetDataDecoder: zeroex.NewAssetDataDecoder(),
+			}
+			receivedExpectedAmount, err := orderValidator.checkReceivedMakerAssetAmount(context.Background(), orderInfo, big.NewInt(1))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectReceived, receivedExpectedAmount)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package orderwatch
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/orderwatch/decoder"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	logger "github.com/sirupsen/logrus"
+)
+
+// backfillValidationTimeout limits how long BackfillOrderEvents blocks at the
+// Ethereum RPC rate limiter while re-validating the affected orders.
+const backfillValidationTimeout = 10 * time.Minute
+
+// exchangeOrderEventSignatures are the signatures of the Exchange events which
+// change the state of an order and have the maker address as their first
+// indexed parameter.
+var exchangeOrderEventSignatures = []string{
+	"Fill(address,address,bytes,bytes,bytes,bytes,bytes32,address,address,uint256,uint256,uint256,uint256,uint256)",
+	"Cancel(address,address,bytes,bytes,address,bytes32)",
+	"CancelUpTo(address,address,uint256)",
+}
+
+// BackfillResult describes the outcome of BackfillOrderEvents.
+type BackfillResult struct {
+	// NumLogs is the number of Exchange logs that were found.
+	NumLogs int
+	// NumOrdersChecked is the number of stored orders affected by the logs
+	// which were re-validated.
+	NumOrdersChecked int
+	// NumOrderEvents is the number of order events that were emitted.
+	NumOrderEvents int
+}
+
+// BackfillOrderEvents scans the Exchange Fill, Cancel and CancelUpTo logs of
+// the given makers between the from and to blocks (inclusive) and re-validates
+// the stored orders they affect at the latest block, unless they were already
+// validated at that block. This corrects the state of
+// orders whose fills or cancellations were missed, e.g. while Mesh was
+// offline. Order events, which include the logs as contract events, are
+// emitted for all orders whose state changed. The logs are not sent to
+// contract event subscribers again.
+func (w *Watcher) BackfillOrderEvents(ctx context.Context, makerAddresses []common.Address, from, to int) (*BackfillResult, error) {
+	eventTopics := make([]common.Hash, len(exchangeOrderEventSignatures))
+	for i, signature := range exchangeOrderEventSignatures {
+		eventTopics[i] = common.BytesToHash(crypto.Keccak256([]byte(signature)))
+	}
+	makerTopics := make([]common.Hash, len(makerAddresses))
+	for i, makerAddress := range makerAddresses {
+		makerTopics[i] = common.BytesToHash(makerAddress.Bytes())
+	}
+	logs, err := w.blockWatcher.FilterLogs(ctx, w.contractAddresses.Exchange, from, to, [][]common.Hash{eventTopics, makerTopics})
+	if err != nil {
+		return nil, err
+	}
+
+	// Pause block event processing until we finished re-validating at current
+	// block height
+	w.handleBlockEventsMu.RLock()
+	defer w.handleBlockEventsMu.RUnlock()
+
+	ordersColTxn := w.meshDB.Orders.OpenTransaction()
+	defer func() {
+		_ = ordersColTxn.Discard()
+	}()
+	latestBlock, err := w.meshDB.FindLatestMiniHeader()
+	if err != nil {
+		return nil, err
+	}
+	orderHashToDBOrder := map[common.Hash]*meshdb.Order{}
+	orderHashToEvents := map[common.Hash][]*zeroex.ContractEvent{}
+	for _, log := range logs {
+		contractEvent, orders, err := w.decodeExchangeOrderLog(log)
+		if err != nil {
+			return nil, err
+		}
+		if contractEvent == nil {
+			continue
+		}
+		for _, order := range orders {
+			if order.WasValidatedAt(latestBlock) {
+				// The order was already validated at the latest block, so
+				// re-validating it would not change anything.
+				continue
+			}
+			orderHashToDBOrder[order.Hash] = order
+			orderHashToEvents[order.Hash] = append(orderHashToEvents[order.Hash], contractEvent)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backfillValidationTimeout)
+	defer cancel()
+	orderEvents, err := w.generateOrderEventsIfChanged(ctx, ordersColTxn, orderHashToDBOrder, orderHashToEvents, latestBlock)
+	if err != nil {
+		return nil, err
+	}
+	if err := ordersColTxn.Commit(); err != nil {
+		logger.WithFields(logger.Fields{
+			"error": err.Error(),
+		}).Error("Failed to commit orders collection transaction")
+		return nil, err
+	}
+	w.sendOrderEvents(orderEvents)
+
+	return &BackfillResult{
+		NumLogs:          len(logs),
+		NumOrdersChecked: len(orderHashToDBOrder),
+		NumOrderEvents:   len(orderEvents),
+	}, nil
+}
+
+// decodeExchangeOrderLog decodes the given Exchange Fill, Cancel or CancelUpTo
+// log and returns it as a contract event along with the stored orders it
+// affects. If the log cannot be decoded, it returns a nil contract event.
+func (w *Watcher) decodeExchangeOrderLog(log types.Log) (*zeroex.ContractEvent, []*meshdb.Order, error) {
+	eventType, err := w.eventDecoder.FindEventType(log)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"error":  err.Error(),
+			"txHash": log.TxHash.Hex(),
+		}).Warn("could not find type of Exchange log while backfilling order events")
+		return nil, nil, nil
+	}
+	contractEvent := &zeroex.ContractEvent{
+		BlockHash: log.BlockHash,
+		TxHash:    log.TxHash,
+		TxIndex:   log.TxIndex,
+		LogIndex:  log.Index,
+		IsRemoved: log.Removed,
+		Address:   log.Address,
+		Kind:      eventType,
+	}
+	orders := []*meshdb.Order{}
+	switch eventType {
+	case "ExchangeFillEvent":
+		var exchangeFillEvent decoder.ExchangeFillEvent
+		if err := w.eventDecoder.Decode(log, &exchangeFillEvent); err != nil {
+			if isNonCritical := w.checkDecodeErr(err, eventType); isNonCritical {
+				return nil, nil, nil
+			}
+			return nil, nil, err
+		}
+		contractEvent.Parameters = exchangeFillEvent
+		if order := w.findOrder(exchangeFillEvent.OrderHash); order != nil {
+			orders = append(orders, order)
+		}
+	case "ExchangeCancelEvent":
+		var exchangeCancelEvent decoder.ExchangeCancelEvent
+		if err := w.eventDecoder.Decode(log, &exchangeCancelEvent); err != nil {
+			if isNonCritical := w.checkDecodeErr(err, eventType); isNonCritical {
+				return nil, nil, nil
+			}
+			return nil, nil, err
+		}
+		contractEvent.Parameters = exchangeCancelEvent
+		if order := w.findOrder(exchangeCancelEvent.OrderHash); order != nil {
+			orders = append(orders, order)
+		}
+	case "ExchangeCancelUpToEvent":
+		var exchangeCancelUpToEvent decoder.ExchangeCancelUpToEvent
+		if err := w.eventDecoder.Decode(log, &exchangeCancelUpToEvent); err != nil {
+			if isNonCritical := w.checkDecodeErr(err, eventType); isNonCritical {
+				return nil, nil, nil
+			}
+			return nil, nil, err
+		}
+		contractEvent.Parameters = exchangeCancelUpToEvent
+		// Lower epochs than the stored one are ignored, so this only updates
+		// the index if the event was missed.
+		w.updateCancelEpoch(exchangeCancelUpToEvent, log.Removed)
+		cancelledOrders, err := w.meshDB.FindOrdersCancelledByEpoch(exchangeCancelUpToEvent.MakerAddress, exchangeCancelUpToEvent.OrderSenderAddress, exchangeCancelUpToEvent.OrderEpoch)
+		if err != nil {
+			return nil, nil, err
+		}
+		orders = append(orders, cancelledOrders...)
+	default:
+		return nil, nil, nil
+	}
+	return contractEvent, orders, nil
+}
@@ -0,0 +1,117 @@
+package orderwatch
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/0xProject/0x-mesh/ethereum/blockwatch"
+)
+
+// ChainStats contains cumulative counters about the blocks and contract events
+// processed by the Watcher since it was created. They help to correlate
+// anomalies in order events with the behavior of the chain.
+type ChainStats struct {
+	// NumBlocksProcessed is the number of blocks added to the chain that were
+	// processed.
+	NumBlocksProcessed uint64
+	// NumReorgs is the number of chain re-orgs observed.
+	NumReorgs uint64
+	// ReorgsByDepth is the number of re-orgs observed for each depth, i.e.
+	// number of blocks removed from the chain.
+	ReorgsByDepth map[int]uint64
+	// NumLogsDecoded is the number of logs decoded into contract events for
+	// each kind of contract event (e.g. "ERC20TransferEvent").
+	NumLogsDecoded map[string]uint64
+	// NumOrdersRevalidated is the number of orders re-validated because of
+	// contract events or expirations.
+	NumOrdersRevalidated uint64
+	// NumValidationCalls is the number of eth_call requests made to
+	// re-validate them.
+	NumValidationCalls uint64
+	// LastBlock contains the counters for the most recently processed blocks.
+	LastBlock BlockProcessingStats
+}
+
+// BlockProcessingStats contains counters about the processing of the blocks
+// received from the block watcher at once. Usually that is a single block, but
+// it can be several if the Watcher fell behind or there was a re-org.
+type BlockProcessingStats struct {
+	// Number is the number of the latest block. It is nil if no blocks have
+	// been processed yet.
+	Number *big.Int
+	// NumLogsDecoded is the number of logs decoded into contract events.
+	NumLogsDecoded int
+	// NumOrdersRevalidated is the number of orders re-validated.
+	NumOrdersRevalidated int
+	// NumValidationCalls is the number of eth_call requests made to
+	// re-validate them.
+	NumValidationCalls uint64
+}
+
+// chainStatsRecorder keeps track of the ChainStats of a Watcher. It is safe
+// for concurrent use.
+type chainStatsRecorder struct {
+	mu    sync.RWMutex
+	stats ChainStats
+}
+
+// recordBlockEvents records the given block events, which were received from
+// the block watcher at once, and the counters for processing them.
+func (r *chainStatsRecorder) recordBlockEvents(events []*blockwatch.Event, latestBlockNumber *big.Int, numLogsDecodedByKind map[string]int, numOrdersRevalidated int, numValidationCalls uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stats.ReorgsByDepth == nil {
+		r.stats.ReorgsByDepth = map[int]uint64{}
+		r.stats.NumLogsDecoded = map[string]uint64{}
+	}
+
+	reorgDepth := 0
+	for _, event := range events {
+		switch event.Type {
+		case blockwatch.Added:
+			r.stats.NumBlocksProcessed++
+		case blockwatch.Removed:
+			reorgDepth++
+		}
+	}
+	if reorgDepth > 0 {
+		r.stats.NumReorgs++
+		r.stats.ReorgsByDepth[reorgDepth]++
+	}
+
+	numLogsDecoded := 0
+	for kind, count := range numLogsDecodedByKind {
+		r.stats.NumLogsDecoded[kind] += uint64(count)
+		numLogsDecoded += count
+	}
+	r.stats.NumOrdersRevalidated += uint64(numOrdersRevalidated)
+	r.stats.NumValidationCalls += numValidationCalls
+	r.stats.LastBlock = BlockProcessingStats{
+		Number:               new(big.Int).Set(latestBlockNumber),
+		NumLogsDecoded:       numLogsDecoded,
+		NumOrdersRevalidated: numOrdersRevalidated,
+		NumValidationCalls:   numValidationCalls,
+	}
+}
+
+// get returns a copy of the ChainStats.
+func (r *chainStatsRecorder) get() ChainStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	stats := r.stats
+	stats.ReorgsByDepth = make(map[int]uint64, len(r.stats.ReorgsByDepth))
+	for depth, count := range r.stats.ReorgsByDepth {
+		stats.ReorgsByDepth[depth] = count
+	}
+	stats.NumLogsDecoded = make(map[string]uint64, len(r.stats.NumLogsDecoded))
+	for kind, count := range r.stats.NumLogsDecoded {
+		stats.NumLogsDecoded[kind] = count
+	}
+	return stats
+}
+
+// ChainStats returns counters about the blocks and contract events processed
+// by the Watcher.
+func (w *Watcher) ChainStats() ChainStats {
+	return w.chainStats.get()
+}
@@ -0,0 +1,62 @@
+// +build !js
+
+package orderwatch
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/ethereum/blockwatch"
+	"github.com/0xProject/0x-mesh/ethereum/miniheader"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainStatsRecorder(t *testing.T) {
+	var recorder chainStatsRecorder
+	stats := recorder.get()
+	assert.Equal(t, uint64(0), stats.NumBlocksProcessed)
+	assert.Nil(t, stats.LastBlock.Number)
+
+	block10 := &miniheader.MiniHeader{Hash: common.HexToHash("0x10"), Number: big.NewInt(10)}
+	block11 := &miniheader.MiniHeader{Hash: common.HexToHash("0x11"), Number: big.NewInt(11)}
+	recorder.recordBlockEvents(
+		[]*blockwatch.Event{{Type: blockwatch.Added, BlockHeader: block10}, {Type: blockwatch.Added, BlockHeader: block11}},
+		block11.Number,
+		map[string]int{"ERC20TransferEvent": 3, "ExchangeFillEvent": 1},
+		5,
+		2,
+	)
+
+	// A re-org of depth 2.
+	replacementBlock10 := &miniheader.MiniHeader{Hash: common.HexToHash("0x1010"), Number: big.NewInt(10)}
+	recorder.recordBlockEvents(
+		[]*blockwatch.Event{
+			{Type: blockwatch.Removed, BlockHeader: block11},
+			{Type: blockwatch.Removed, BlockHeader: block10},
+			{Type: blockwatch.Added, BlockHeader: replacementBlock10},
+		},
+		replacementBlock10.Number,
+		map[string]int{"ERC20TransferEvent": 1},
+		1,
+		1,
+	)
+
+	stats = recorder.get()
+	assert.Equal(t, uint64(3), stats.NumBlocksProcessed)
+	assert.Equal(t, uint64(1), stats.NumReorgs)
+	assert.Equal(t, map[int]uint64{2: 1}, stats.ReorgsByDepth)
+	assert.Equal(t, map[string]uint64{"ERC20TransferEvent": 4, "ExchangeFillEvent": 1}, stats.NumLogsDecoded)
+	assert.Equal(t, uint64(6), stats.NumOrdersRevalidated)
+	assert.Equal(t, uint64(3), stats.NumValidationCalls)
+	assert.Equal(t, BlockProcessingStats{
+		Number:               big.NewInt(10),
+		NumLogsDecoded:       1,
+		NumOrdersRevalidated: 1,
+		NumValidationCalls:   1,
+	}, stats.LastBlock)
+
+	// The returned stats are a copy.
+	stats.NumLogsDecoded["ERC20TransferEvent"] = 100
+	assert.Equal(t, uint64(4), recorder.get().NumLogsDecoded["ERC20TransferEvent"])
+}
@@ -0,0 +1,226 @@
+package orderwatch
+
+import (
+	"math/big"
+
+	"github.com/0xProject/0x-mesh/ethereum/blockwatch"
+	"github.com/0xProject/0x-mesh/ethereum/miniheader"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	logger "github.com/sirupsen/logrus"
+)
+
+// loadPendingOrderEvents loads the order events which were still held back
+// when Mesh was last stopped. It is called once when the Watcher is created.
+// If orderEventConfirmationDepth has been set to 0 since then, they are sent
+// right away. Pending order events caused by blocks which were removed by a
+// re-org while Mesh was stopped are dropped once the BlockWatcher reports the
+// removed blocks.
+func (w *Watcher) loadPendingOrderEvents() error {
+	pendingOrderEvents, err := w.meshDB.FindAllPendingOrderEvents()
+	if err != nil {
+		return err
+	}
+	if len(pendingOrderEvents) == 0 {
+		return nil
+	}
+	if w.orderEventConfirmationDepth == 0 {
+		orderEvents := []*zeroex.OrderEvent{}
+		for _, pending := range pendingOrderEvents {
+			orderEvents = append(orderEvents, pending.OrderEvents...)
+		}
+		if err := w.publishOrderEvents(orderEvents); err != nil {
+			return err
+		}
+		return w.meshDB.ClearAllPendingOrderEvents()
+	}
+	w.pendingOrderEventsMu.Lock()
+	defer w.pendingOrderEventsMu.Unlock()
+	w.pendingOrderEvents = pendingOrderEvents
+	w.nextPendingOrderEventsIndex = pendingOrderEvents[len(pendingOrderEvents)-1].Index + 1
+	return nil
+}
+
+// sendOrderEvents sends the given order events to subscribers. The order
+// events are annotated with the latest block, at which their new state was
+// observed. If orderEventConfirmationDepth is greater than 0, the order events
+// which were caused by contract events are held back until the current latest
+// block has that many confirmations. Since order events which were not caused
+// by contract events (e.g. ADDED) can't be undone by a re-org, they are only
+// held back behind pending order events for the same order, so that
+// subscribers receive the order events for each order in order. Pending order
+// events are stored in the database, so they are still sent if Mesh is
+// restarted. The order state in the database is not affected.
+func (w *Watcher) sendOrderEvents(orderEvents []*zeroex.OrderEvent) {
+	if len(orderEvents) == 0 {
+		return
+	}
+	latestBlock, err := w.meshDB.FindLatestMiniHeader()
+	if err != nil {
+		if _, ok := err.(meshdb.MiniHeaderCollectionEmptyError); !ok {
+			logger.WithError(err).Error("could not find latest block for order events")
+		}
+		// Without a latest block, there is nothing to wait for.
+		w.publishOrderEventsOrLog(orderEvents)
+		return
+	}
+	setOrderEventBlocks(orderEvents, latestBlock)
+	if w.orderEventConfirmationDepth == 0 {
+		w.publishOrderEventsOrLog(orderEvents)
+		return
+	}
+
+	w.pendingOrderEventsMu.Lock()
+	defer w.pendingOrderEventsMu.Unlock()
+	pendingOrderHashes := map[common.Hash]struct{}{}
+	for _, pending := range w.pendingOrderEvents {
+		for _, orderEvent := range pending.OrderEvents {
+			pendingOrderHashes[orderEvent.OrderHash] = struct{}{}
+		}
+	}
+	immediateOrderEvents := []*zeroex.OrderEvent{}
+	heldBackOrderEvents := []*zeroex.OrderEvent{}
+	for _, orderEvent := range orderEvents {
+		_, hasPendingOrderEvents := pendingOrderHashes[orderEvent.OrderHash]
+		if len(orderEvent.ContractEvents) == 0 && !hasPendingOrderEvents {
+			immediateOrderEvents = append(immediateOrderEvents, orderEvent)
+			continue
+		}
+		pendingOrderHashes[orderEvent.OrderHash] = struct{}{}
+		heldBackOrderEvents = append(heldBackOrderEvents, orderEvent)
+	}
+	if len(immediateOrderEvents) > 0 {
+		w.publishOrderEventsOrLog(immediateOrderEvents)
+	}
+	if len(heldBackOrderEvents) == 0 {
+		return
+	}
+	pending := &meshdb.PendingOrderEvents{
+		Index:       w.nextPendingOrderEventsIndex,
+		BlockNumber: latestBlock.Number,
+		OrderEvents: heldBackOrderEvents,
+	}
+	w.nextPendingOrderEventsIndex++
+	if err := w.meshDB.PendingOrderEvents.Insert(pending); err != nil {
+		logger.WithError(err).Error("could not store pending order events, they will not be sent if Mesh is restarted before they are confirmed")
+	}
+	w.pendingOrderEvents = append(w.pendingOrderEvents, pending)
+}
+
+// publishOrderEventsOrLog publishes the given order events and logs an error
+// if that fails. The order events are then published with the next order
+// events.
+func (w *Watcher) publishOrderEventsOrLog(orderEvents []*zeroex.OrderEvent) {
+	if err := w.publishOrderEvents(orderEvents); err != nil {
+		logger.WithError(err).Error("could not publish order events, they will be published with the next order events")
+	}
+}
+
+// setOrderEventBlocks sets the block of the given order events which don't
+// have one yet to the given block.
+func setOrderEventBlocks(orderEvents []*zeroex.OrderEvent, block *miniheader.MiniHeader) {
+	for _, orderEvent := range orderEvents {
+		if orderEvent.BlockNumber != nil {
+			continue
+		}
+		orderEvent.BlockNumber = new(big.Int).Set(block.Number)
+		orderEvent.BlockHash = block.Hash
+		orderEvent.BlockTimestamp = block.Timestamp
+	}
+}
+
+// confirmOrderEvents is called after the given block events have been
+// processed and before the resulting order events are passed to
+// sendOrderEvents. Pending order events which were entirely caused by blocks
+// that have now been removed are dropped, since subscribers never saw them.
+// The order events generated by reverting those blocks are dropped for the
+// same reason, so that subscribers do not see short-lived re-orgs at all.
+// Finally, all pending order events which have enough confirmations are sent.
+// It returns the given order events without the dropped ones.
+func (w *Watcher) confirmOrderEvents(events []*blockwatch.Event, latestBlock *miniheader.MiniHeader, orderEvents []*zeroex.OrderEvent) []*zeroex.OrderEvent {
+	if w.orderEventConfirmationDepth == 0 {
+		return orderEvents
+	}
+	removedBlocks := map[common.Hash]struct{}{}
+	for _, event := range events {
+		if event.Type == blockwatch.Removed {
+			removedBlocks[event.BlockHeader.Hash] = struct{}{}
+		}
+	}
+
+	w.pendingOrderEventsMu.Lock()
+	defer w.pendingOrderEventsMu.Unlock()
+
+	// droppedOrderHashes are the hashes of orders whose pending order events
+	// were dropped.
+	droppedOrderHashes := map[common.Hash]struct{}{}
+	if len(removedBlocks) > 0 {
+		for _, pending := range w.pendingOrderEvents {
+			remaining := []*zeroex.OrderEvent{}
+			for _, orderEvent := range pending.OrderEvents {
+				if isCausedByBlocks(orderEvent, removedBlocks, false) {
+					droppedOrderHashes[orderEvent.OrderHash] = struct{}{}
+					continue
+				}
+				remaining = append(remaining, orderEvent)
+			}
+			if len(remaining) == len(pending.OrderEvents) {
+				continue
+			}
+			pending.OrderEvents = remaining
+			if err := w.meshDB.PendingOrderEvents.Update(pending); err != nil {
+				logger.WithError(err).Error("could not update pending order events")
+			}
+		}
+	}
+	remainingOrderEvents := []*zeroex.OrderEvent{}
+	for _, orderEvent := range orderEvents {
+		if _, found := droppedOrderHashes[orderEvent.OrderHash]; found && isCausedByBlocks(orderEvent, removedBlocks, true) {
+			continue
+		}
+		remainingOrderEvents = append(remainingOrderEvents, orderEvent)
+	}
+
+	// Order events are sent in the order in which they were generated, so we
+	// stop at the first pending order events without enough confirmations.
+	confirmedOrderEvents := []*zeroex.OrderEvent{}
+	numConfirmed := 0
+	for _, pending := range w.pendingOrderEvents {
+		confirmations := new(big.Int).Sub(latestBlock.Number, pending.BlockNumber)
+		if confirmations.Cmp(big.NewInt(int64(w.orderEventConfirmationDepth))) < 0 {
+			break
+		}
+		confirmedOrderEvents = append(confirmedOrderEvents, pending.OrderEvents...)
+		numConfirmed++
+	}
+	confirmed := w.pendingOrderEvents[:numConfirmed]
+	w.pendingOrderEvents = w.pendingOrderEvents[numConfirmed:]
+	if len(confirmedOrderEvents) > 0 {
+		w.publishOrderEventsOrLog(confirmedOrderEvents)
+	}
+	// The confirmed order events are deleted after they have been published,
+	// so they are sent again rather than lost if Mesh is stopped in between.
+	for _, pending := range confirmed {
+		if err := w.meshDB.DeletePendingOrderEvents(pending.Index); err != nil {
+			logger.WithError(err).Error("could not delete confirmed pending order events")
+		}
+	}
+	return remainingOrderEvents
+}
+
+// isCausedByBlocks returns true if the given order event was caused only by
+// contract events in the given blocks. If isRemoved is true, the contract
+// events must have been removed by a re-org, otherwise they must not have
+// been.
+func isCausedByBlocks(orderEvent *zeroex.OrderEvent, blocks map[common.Hash]struct{}, isRemoved bool) bool {
+	if len(orderEvent.ContractEvents) == 0 {
+		return false
+	}
+	for _, contractEvent := range orderEvent.ContractEvents {
+		if _, found := blocks[contractEvent.BlockHash]; !found || contractEvent.IsRemoved != isRemoved {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,206 @@
+// +build !js
+
+package orderwatch
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xProject/0x-mesh/ethereum/blockwatch"
+	"github.com/0xProject/0x-mesh/ethereum/miniheader"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/orderwatch/decoder"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderEventConfirmationDepth(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+	require.NoError(t, meshDB.SaveMetadata(&meshdb.Metadata{}))
+	w := &Watcher{
+		meshDB:                      meshDB,
+		orderEventConfirmationDepth: 2,
+	}
+	sink := make(chan []*zeroex.OrderEvent, 10)
+	subscription := w.orderFeed.Subscribe(sink)
+	defer subscription.Unsubscribe()
+
+	block10 := &miniheader.MiniHeader{Hash: common.HexToHash("0x10"), Number: big.NewInt(10)}
+	require.NoError(t, meshDB.MiniHeaders.Insert(block10))
+
+	// filledEvent is caused by block 10 and addedEvent is not caused by any
+	// block.
+	filledEvent := &zeroex.OrderEvent{
+		OrderHash:      common.HexToHash("0x1"),
+		EndState:       zeroex.ESOrderFilled,
+		ContractEvents: []*zeroex.ContractEvent{{BlockHash: block10.Hash}},
+	}
+	addedEvent := &zeroex.OrderEvent{
+		OrderHash: common.HexToHash("0x2"),
+		EndState:  zeroex.ESOrderAdded,
+	}
+	w.sendOrderEvents([]*zeroex.OrderEvent{filledEvent, addedEvent})
+
+	// The added event was not caused by a block, so it is sent right away.
+	// The order events are annotated with the latest block when they are
+	// generated, not when they are sent.
+	require.Len(t, sink, 1)
+	assert.Equal(t, []*zeroex.OrderEvent{addedEvent}, <-sink)
+	assert.Equal(t, block10.Number, addedEvent.BlockNumber)
+	assert.Equal(t, block10.Hash, addedEvent.BlockHash)
+	assert.Equal(t, block10.Number, filledEvent.BlockNumber)
+
+	// One confirmation is not enough.
+	block11 := &miniheader.MiniHeader{Hash: common.HexToHash("0x11"), Number: big.NewInt(11)}
+	remaining := w.confirmOrderEvents([]*blockwatch.Event{{Type: blockwatch.Added, BlockHeader: block11}}, block11, nil)
+	assert.Empty(t, remaining)
+	assert.Len(t, sink, 0)
+
+	// Block 10 is removed by a re-org. The filled event and its correction are
+	// dropped.
+	correctionEvent := &zeroex.OrderEvent{
+		OrderHash:      filledEvent.OrderHash,
+		EndState:       zeroex.ESOrderFillabilityIncreased,
+		ContractEvents: []*zeroex.ContractEvent{{BlockHash: block10.Hash, IsRemoved: true}},
+	}
+	otherEvent := &zeroex.OrderEvent{
+		OrderHash: common.HexToHash("0x3"),
+		EndState:  zeroex.ESOrderExpired,
+	}
+	replacementBlock11 := &miniheader.MiniHeader{Hash: common.HexToHash("0x1111"), Number: big.NewInt(11)}
+	blockEvents := []*blockwatch.Event{
+		{Type: blockwatch.Removed, BlockHeader: block11},
+		{Type: blockwatch.Removed, BlockHeader: block10},
+		{Type: blockwatch.Added, BlockHeader: replacementBlock11},
+	}
+	remaining = w.confirmOrderEvents(blockEvents, replacementBlock11, []*zeroex.OrderEvent{correctionEvent, otherEvent})
+	assert.Equal(t, []*zeroex.OrderEvent{otherEvent}, remaining)
+	assert.Len(t, sink, 0)
+
+	// Nothing is left to send once block 10 has two confirmations.
+	block12 := &miniheader.MiniHeader{Hash: common.HexToHash("0x12"), Number: big.NewInt(12)}
+	remaining = w.confirmOrderEvents([]*blockwatch.Event{{Type: blockwatch.Added, BlockHeader: block12}}, block12, nil)
+	assert.Empty(t, remaining)
+	assert.Len(t, sink, 0)
+	pendingOrderEvents, err := meshDB.FindAllPendingOrderEvents()
+	require.NoError(t, err)
+	assert.Empty(t, pendingOrderEvents)
+}
+
+func TestOrderEventsHeldBackBehindPendingOrderEvents(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+	require.NoError(t, meshDB.SaveMetadata(&meshdb.Metadata{}))
+	w := &Watcher{
+		meshDB:                      meshDB,
+		orderEventConfirmationDepth: 2,
+	}
+	sink := make(chan []*zeroex.OrderEvent, 10)
+	subscription := w.orderFeed.Subscribe(sink)
+	defer subscription.Unsubscribe()
+
+	block10 := &miniheader.MiniHeader{Hash: common.HexToHash("0x10"), Number: big.NewInt(10)}
+	require.NoError(t, meshDB.MiniHeaders.Insert(block10))
+	filledEvent := newTestBlockOrderEvent(common.HexToHash("0x1"), zeroex.ESOrderFilled, block10.Hash)
+	w.sendOrderEvents([]*zeroex.OrderEvent{filledEvent})
+
+	// An order event which was not caused by a block is still held back if
+	// there are pending order events for the same order, so that subscribers
+	// receive the order events for the order in order.
+	stoppedWatchingEvent := &zeroex.OrderEvent{
+		OrderHash:                filledEvent.OrderHash,
+		EndState:                 zeroex.ESStoppedWatching,
+		FillableTakerAssetAmount: big.NewInt(0),
+	}
+	w.sendOrderEvents([]*zeroex.OrderEvent{stoppedWatchingEvent})
+	assert.Len(t, sink, 0)
+
+	block12 := &miniheader.MiniHeader{Hash: common.HexToHash("0x12"), Number: big.NewInt(12)}
+	w.confirmOrderEvents([]*blockwatch.Event{{Type: blockwatch.Added, BlockHeader: block12}}, block12, nil)
+	require.Len(t, sink, 1)
+	assert.Equal(t, []*zeroex.OrderEvent{filledEvent, stoppedWatchingEvent}, <-sink)
+}
+
+func TestPendingOrderEventsAreSentAfterRestart(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+	require.NoError(t, meshDB.SaveMetadata(&meshdb.Metadata{}))
+	w := &Watcher{
+		meshDB:                      meshDB,
+		orderEventConfirmationDepth: 2,
+	}
+	require.NoError(t, w.loadLastSequenceNumber())
+	require.NoError(t, w.loadPendingOrderEvents())
+
+	block10 := &miniheader.MiniHeader{Hash: common.HexToHash("0x10"), Number: big.NewInt(10)}
+	require.NoError(t, meshDB.MiniHeaders.Insert(block10))
+	filledEvent := newTestBlockOrderEvent(common.HexToHash("0x1"), zeroex.ESOrderFilled, block10.Hash)
+	w.sendOrderEvents([]*zeroex.OrderEvent{filledEvent})
+
+	// Mesh is restarted before block 10 has enough confirmations.
+	restarted := &Watcher{
+		meshDB:                      meshDB,
+		orderEventConfirmationDepth: 2,
+	}
+	require.NoError(t, restarted.loadLastSequenceNumber())
+	require.NoError(t, restarted.loadPendingOrderEvents())
+	sink := make(chan []*zeroex.OrderEvent, 10)
+	subscription := restarted.orderFeed.Subscribe(sink)
+	defer subscription.Unsubscribe()
+
+	// Order events held back by the restarted watcher are sent after the
+	// ones held back before the restart.
+	block11 := &miniheader.MiniHeader{Hash: common.HexToHash("0x11"), Number: big.NewInt(11)}
+	require.NoError(t, meshDB.MiniHeaders.Insert(block11))
+	cancelledEvent := newTestBlockOrderEvent(common.HexToHash("0x2"), zeroex.ESOrderCancelled, block11.Hash)
+	restarted.sendOrderEvents([]*zeroex.OrderEvent{cancelledEvent})
+
+	block12 := &miniheader.MiniHeader{Hash: common.HexToHash("0x12"), Number: big.NewInt(12)}
+	restarted.confirmOrderEvents([]*blockwatch.Event{{Type: blockwatch.Added, BlockHeader: block12}}, block12, nil)
+	require.Len(t, sink, 1)
+	sentOrderEvents := <-sink
+	require.Len(t, sentOrderEvents, 1)
+	assert.Equal(t, filledEvent.OrderHash, sentOrderEvents[0].OrderHash)
+	assert.Equal(t, filledEvent.EndState, sentOrderEvents[0].EndState)
+	assert.Equal(t, filledEvent.FillableTakerAssetAmount, sentOrderEvents[0].FillableTakerAssetAmount)
+	assert.Equal(t, block10.Number, sentOrderEvents[0].BlockNumber)
+	require.Len(t, sentOrderEvents[0].ContractEvents, 1)
+	assert.Equal(t, block10.Hash, sentOrderEvents[0].ContractEvents[0].BlockHash)
+
+	block13 := &miniheader.MiniHeader{Hash: common.HexToHash("0x13"), Number: big.NewInt(13)}
+	restarted.confirmOrderEvents([]*blockwatch.Event{{Type: blockwatch.Added, BlockHeader: block13}}, block13, nil)
+	require.Len(t, sink, 1)
+	assert.Equal(t, []*zeroex.OrderEvent{cancelledEvent}, <-sink)
+	pendingOrderEvents, err := meshDB.FindAllPendingOrderEvents()
+	require.NoError(t, err)
+	assert.Empty(t, pendingOrderEvents)
+}
+
+// newTestBlockOrderEvent returns an order event which was caused by a contract
+// event in the given block and can be stored in the database.
+func newTestBlockOrderEvent(orderHash common.Hash, endState zeroex.OrderEventEndState, blockHash common.Hash) *zeroex.OrderEvent {
+	return &zeroex.OrderEvent{
+		Timestamp:                time.Now().UTC(),
+		OrderHash:                orderHash,
+		EndState:                 endState,
+		FillableTakerAssetAmount: big.NewInt(50),
+		ContractEvents: []*zeroex.ContractEvent{
+			{
+				BlockHash: blockHash,
+				Kind:      "WethDepositEvent",
+				Parameters: decoder.WethDepositEvent{
+					Owner: common.HexToAddress("0x1"),
+					Value: big.NewInt(1),
+				},
+			},
+		},
+	}
+}
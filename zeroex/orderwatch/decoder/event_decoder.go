@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/0xProject/0x-mesh/common/addressformat"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -25,7 +26,12 @@ var EVENT_SIGNATURES = [...]string{
 	"Withdrawal(address,uint256)",                                // WETH9
 	"Fill(address,address,bytes,bytes,bytes,bytes,bytes32,address,address,uint256,uint256,uint256,uint256,uint256)", // Exchange
 	"Cancel(address,address,bytes,bytes,address,bytes32)",                                                           // Exchange
-	"CancelUpTo(address,address,uint256)",
+	"CancelUpTo(address,address,uint256)",                                                                           // Exchange
+	"TransactionExecution(bytes32)",                                                                                 // Exchange
+	"SignatureValidatorApproval(address,address,bool)",                                                              // Exchange
+	"AssetProxyRegistered(bytes4,address)",                                                                          // Exchange
+	"ProtocolFeeMultiplier(uint256,uint256)",                                                                        // Exchange
+	"ProtocolFeeCollectorAddress(address,address)",                                                                  // Exchange
 }
 
 // Includes ERC20 `Transfer` & `Approval` events as well as WETH `Deposit` & `Withdraw` events
@@ -40,7 +46,9 @@ const erc721EventsAbiWithoutTokenIDIndexStr = "[{\"anonymous\":false,\"inputs\":
 // Includes ERC1155 `TransferSingle`, `TransferBatch` & `ApprovalForAll` events
 const erc1155EventsAbi = "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"approved\",\"type\":\"bool\"}],\"name\":\"ApprovalForAll\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256[]\",\"name\":\"ids\",\"type\":\"uint256[]\"},{\"indexed\":false,\"internalType\":\"uint256[]\",\"name\":\"values\",\"type\":\"uint256[]\"}],\"name\":\"TransferBatch\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"TransferSingle\",\"type\":\"event\"}]"
 
-// Includes Exchange `Fill`, `Cancel`, `CancelUpTo` events
+// Includes Exchange `Fill`, `Cancel`, `CancelUpTo`, `TransactionExecution`,
+// `SignatureValidatorApproval`, `AssetProxyRegistered`, `ProtocolFeeMultiplier`
+// and `ProtocolFeeCollectorAddress` events
 const exchangeEventsAbi = "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"transactionHash\",\"type\":\"bytes32\"}],\"name\":\"TransactionExecution\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"signerAddress\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"validatorAddress\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"isApproved\",\"type\":\"bool\"}],\"name\":\"SignatureValidatorApproval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bytes4\",\"name\":\"id\",\"type\":\"bytes4\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"assetProxy\",\"type\":\"address\"}],\"name\":\"AssetProxyRegistered\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"oldProtocolFeeMultiplier\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"updatedProtocolFeeMultiplier\",\"type\":\"uint256\"}],\"name\":\"ProtocolFeeMultiplier\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"oldProtocolFeeCollector\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"updatedProtocolFeeCollector\",\"type\":\"address\"}],\"name\":\"ProtocolFeeCollectorAddress\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"makerAddress\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"feeRecipientAddress\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"makerAssetData\",\"type\":\"bytes\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"takerAssetData\",\"type\":\"bytes\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"makerFeeAssetData\",\"type\":\"bytes\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"takerFeeAssetData\",\"type\":\"bytes\"},{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"orderHash\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"takerAddress\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"senderAddress\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"makerAssetFilledAmount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"takerAssetFilledAmount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"makerFeePaid\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"takerFeePaid\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"protocolFeePaid\",\"type\":\"uint256\"}],\"name\":\"Fill\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"makerAddress\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"feeRecipientAddress\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"makerAssetData\",\"type\":\"bytes\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"takerAssetData\",\"type\":\"bytes\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"senderAddress\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"orderHash\",\"type\":\"bytes32\"}],\"name\":\"Cancel\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"makerAddress\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"orderSenderAddress\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"orderEpoch\",\"type\":\"uint256\"}],\"name\":\"CancelUpTo\",\"type\":\"event\"}]"
 
 // ERC20TransferEvent represents an ERC20 Transfer event
@@ -59,8 +67,8 @@ type erc20TransferEventJSON struct {
 // MarshalJSON implements a custom JSON marshaller for the ERC20TransferEvent type
 func (e ERC20TransferEvent) MarshalJSON() ([]byte, error) {
 	return json.Marshal(erc20TransferEventJSON{
-		From:  e.From.Hex(),
-		To:    e.To.Hex(),
+		From:  addressformat.String(e.From),
+		To:    addressformat.String(e.To),
 		Value: e.Value.String(),
 	})
 }
@@ -96,8 +104,8 @@ type erc20ApprovalEventJSON struct {
 // MarshalJSON implements a custom JSON marshaller for the ERC20ApprovalEvent type
 func (e ERC20ApprovalEvent) MarshalJSON() ([]byte, error) {
 	return json.Marshal(erc20ApprovalEventJSON{
-		Owner:   e.Owner.Hex(),
-		Spender: e.Spender.Hex(),
+		Owner:   addressformat.String(e.Owner),
+		Spender: addressformat.String(e.Spender),
 		Value:   e.Value.String(),
 	})
 }
@@ -133,8 +141,8 @@ type erc721TransferEventJSON struct {
 // MarshalJSON implements a custom JSON marshaller for the ERC721TransferEvent type
 func (e ERC721TransferEvent) MarshalJSON() ([]byte, error) {
 	return json.Marshal(erc721TransferEventJSON{
-		From:    e.From.Hex(),
-		To:      e.To.Hex(),
+		From:    addressformat.String(e.From),
+		To:      addressformat.String(e.To),
 		TokenId: e.TokenId.String(),
 	})
 }
@@ -170,8 +178,8 @@ type erc721ApprovalEventJSON struct {
 // MarshalJSON implements a custom JSON marshaller for the ERC721ApprovalEvent type
 func (e ERC721ApprovalEvent) MarshalJSON() ([]byte, error) {
 	return json.Marshal(erc721ApprovalEventJSON{
-		Owner:    e.Owner.Hex(),
-		Approved: e.Approved.Hex(),
+		Owner:    addressformat.String(e.Owner),
+		Approved: addressformat.String(e.Approved),
 		TokenId:  e.TokenId.String(),
 	})
 }
@@ -225,9 +233,9 @@ type erc1155TransferSingleEventJSON struct {
 // MarshalJSON implements a custom JSON marshaller for the ERC1155TransferSingleEvent type
 func (e ERC1155TransferSingleEvent) MarshalJSON() ([]byte, error) {
 	return json.Marshal(erc1155TransferSingleEventJSON{
-		Operator: e.Operator.Hex(),
-		From:     e.From.Hex(),
-		To:       e.To.Hex(),
+		Operator: addressformat.String(e.Operator),
+		From:     addressformat.String(e.From),
+		To:       addressformat.String(e.To),
 		Id:       e.Id.String(),
 		Value:    e.Value.String(),
 	})
@@ -281,9 +289,9 @@ func (e ERC1155TransferBatchEvent) MarshalJSON() ([]byte, error) {
 		values[i] = value.String()
 	}
 	return json.Marshal(erc1155TransferBatchEventJSON{
-		Operator: e.Operator.Hex(),
-		From:     e.From.Hex(),
-		To:       e.To.Hex(),
+		Operator: addressformat.String(e.Operator),
+		From:     addressformat.String(e.From),
+		To:       addressformat.String(e.To),
 		Ids:      ids,
 		Values:   values,
 	})
@@ -370,10 +378,10 @@ func (e ExchangeFillEvent) MarshalJSON() ([]byte, error) {
 		takerFeeAssetData = fmt.Sprintf("0x%s", common.Bytes2Hex(e.TakerFeeAssetData))
 	}
 	return json.Marshal(exchangeFillEventJSON{
-		MakerAddress:           e.MakerAddress.Hex(),
-		TakerAddress:           e.TakerAddress.Hex(),
-		SenderAddress:          e.SenderAddress.Hex(),
-		FeeRecipientAddress:    e.FeeRecipientAddress.Hex(),
+		MakerAddress:           addressformat.String(e.MakerAddress),
+		TakerAddress:           addressformat.String(e.TakerAddress),
+		SenderAddress:          addressformat.String(e.SenderAddress),
+		FeeRecipientAddress:    addressformat.String(e.FeeRecipientAddress),
 		MakerAssetFilledAmount: e.MakerAssetFilledAmount.String(),
 		TakerAssetFilledAmount: e.TakerAssetFilledAmount.String(),
 		MakerFeePaid:           e.MakerFeePaid.String(),
@@ -456,9 +464,9 @@ func (e ExchangeCancelEvent) MarshalJSON() ([]byte, error) {
 		takerAssetData = fmt.Sprintf("0x%s", common.Bytes2Hex(e.TakerAssetData))
 	}
 	return json.Marshal(exchangeCancelEventJSON{
-		MakerAddress:        e.MakerAddress.Hex(),
-		SenderAddress:       e.SenderAddress.Hex(),
-		FeeRecipientAddress: e.FeeRecipientAddress.Hex(),
+		MakerAddress:        addressformat.String(e.MakerAddress),
+		SenderAddress:       addressformat.String(e.SenderAddress),
+		FeeRecipientAddress: addressformat.String(e.FeeRecipientAddress),
 		OrderHash:           e.OrderHash.Hex(),
 		MakerAssetData:      makerAssetData,
 		TakerAssetData:      takerAssetData,
@@ -496,8 +504,8 @@ type exchangeCancelUpToEventJSON struct {
 // MarshalJSON implements a custom JSON marshaller for the ExchangeCancelUpToEvent type
 func (e ExchangeCancelUpToEvent) MarshalJSON() ([]byte, error) {
 	return json.Marshal(exchangeCancelUpToEventJSON{
-		MakerAddress:       e.MakerAddress.Hex(),
-		OrderSenderAddress: e.OrderSenderAddress.Hex(),
+		MakerAddress:       addressformat.String(e.MakerAddress),
+		OrderSenderAddress: addressformat.String(e.OrderSenderAddress),
 		OrderEpoch:         e.OrderEpoch.String(),
 	})
 }
@@ -518,6 +526,246 @@ func (e *ExchangeCancelUpToEvent) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ExchangeTransactionExecutionEvent represents a 0x Exchange TransactionExecution
+// event, which is emitted when a 0x meta-transaction is executed.
+type ExchangeTransactionExecutionEvent struct {
+	TransactionHash common.Hash
+}
+
+type exchangeTransactionExecutionEventJSON struct {
+	TransactionHash string `json:"transactionHash"`
+}
+
+// MarshalJSON implements a custom JSON marshaller for the ExchangeTransactionExecutionEvent type
+func (e ExchangeTransactionExecutionEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(exchangeTransactionExecutionEventJSON{
+		TransactionHash: e.TransactionHash.Hex(),
+	})
+}
+
+func (e *ExchangeTransactionExecutionEvent) UnmarshalJSON(data []byte) error {
+	var eventJSON exchangeTransactionExecutionEventJSON
+	if err := json.Unmarshal(data, &eventJSON); err != nil {
+		return err
+	}
+	e.TransactionHash = common.HexToHash(eventJSON.TransactionHash)
+
+	return nil
+}
+
+// ExchangeSignatureValidatorApprovalEvent represents a 0x Exchange
+// SignatureValidatorApproval event
+type ExchangeSignatureValidatorApprovalEvent struct {
+	SignerAddress    common.Address
+	ValidatorAddress common.Address
+	IsApproved       bool
+}
+
+type exchangeSignatureValidatorApprovalEventJSON struct {
+	SignerAddress    string `json:"signerAddress"`
+	ValidatorAddress string `json:"validatorAddress"`
+	IsApproved       bool   `json:"isApproved"`
+}
+
+// MarshalJSON implements a custom JSON marshaller for the ExchangeSignatureValidatorApprovalEvent type
+func (e ExchangeSignatureValidatorApprovalEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(exchangeSignatureValidatorApprovalEventJSON{
+		SignerAddress:    addressformat.String(e.SignerAddress),
+		ValidatorAddress: addressformat.String(e.ValidatorAddress),
+		IsApproved:       e.IsApproved,
+	})
+}
+
+func (e *ExchangeSignatureValidatorApprovalEvent) UnmarshalJSON(data []byte) error {
+	var eventJSON exchangeSignatureValidatorApprovalEventJSON
+	if err := json.Unmarshal(data, &eventJSON); err != nil {
+		return err
+	}
+	e.SignerAddress = common.HexToAddress(eventJSON.SignerAddress)
+	e.ValidatorAddress = common.HexToAddress(eventJSON.ValidatorAddress)
+	e.IsApproved = eventJSON.IsApproved
+
+	return nil
+}
+
+// ExchangeAssetProxyRegisteredEvent represents a 0x Exchange
+// AssetProxyRegistered event
+type ExchangeAssetProxyRegisteredEvent struct {
+	Id         [4]byte
+	AssetProxy common.Address
+}
+
+type exchangeAssetProxyRegisteredEventJSON struct {
+	Id         string `json:"id"`
+	AssetProxy string `json:"assetProxy"`
+}
+
+// MarshalJSON implements a custom JSON marshaller for the ExchangeAssetProxyRegisteredEvent type
+func (e ExchangeAssetProxyRegisteredEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(exchangeAssetProxyRegisteredEventJSON{
+		Id:         fmt.Sprintf("0x%s", common.Bytes2Hex(e.Id[:])),
+		AssetProxy: addressformat.String(e.AssetProxy),
+	})
+}
+
+func (e *ExchangeAssetProxyRegisteredEvent) UnmarshalJSON(data []byte) error {
+	var eventJSON exchangeAssetProxyRegisteredEventJSON
+	if err := json.Unmarshal(data, &eventJSON); err != nil {
+		return err
+	}
+	id := common.FromHex(eventJSON.Id)
+	if len(id) != len(e.Id) {
+		return fmt.Errorf("Invalid bytes4 value for ExchangeAssetProxyRegisteredEvent.Id: %q", eventJSON.Id)
+	}
+	copy(e.Id[:], id)
+	e.AssetProxy = common.HexToAddress(eventJSON.AssetProxy)
+
+	return nil
+}
+
+// ExchangeProtocolFeeMultiplierEvent represents a 0x Exchange
+// ProtocolFeeMultiplier event
+type ExchangeProtocolFeeMultiplierEvent struct {
+	OldProtocolFeeMultiplier     *big.Int
+	UpdatedProtocolFeeMultiplier *big.Int
+}
+
+type exchangeProtocolFeeMultiplierEventJSON struct {
+	OldProtocolFeeMultiplier     string `json:"oldProtocolFeeMultiplier"`
+	UpdatedProtocolFeeMultiplier string `json:"updatedProtocolFeeMultiplier"`
+}
+
+// MarshalJSON implements a custom JSON marshaller for the ExchangeProtocolFeeMultiplierEvent type
+func (e ExchangeProtocolFeeMultiplierEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(exchangeProtocolFeeMultiplierEventJSON{
+		OldProtocolFeeMultiplier:     e.OldProtocolFeeMultiplier.String(),
+		UpdatedProtocolFeeMultiplier: e.UpdatedProtocolFeeMultiplier.String(),
+	})
+}
+
+func (e *ExchangeProtocolFeeMultiplierEvent) UnmarshalJSON(data []byte) error {
+	var eventJSON exchangeProtocolFeeMultiplierEventJSON
+	if err := json.Unmarshal(data, &eventJSON); err != nil {
+		return err
+	}
+	var ok bool
+	e.OldProtocolFeeMultiplier, ok = math.ParseBig256(eventJSON.OldProtocolFeeMultiplier)
+	if !ok {
+		return fmt.Errorf("Invalid uint256 number for ExchangeProtocolFeeMultiplierEvent.OldProtocolFeeMultiplier: %q", eventJSON.OldProtocolFeeMultiplier)
+	}
+	e.UpdatedProtocolFeeMultiplier, ok = math.ParseBig256(eventJSON.UpdatedProtocolFeeMultiplier)
+	if !ok {
+		return fmt.Errorf("Invalid uint256 number for ExchangeProtocolFeeMultiplierEvent.UpdatedProtocolFeeMultiplier: %q", eventJSON.UpdatedProtocolFeeMultiplier)
+	}
+
+	return nil
+}
+
+// ExchangeProtocolFeeCollectorAddressEvent represents a 0x Exchange
+// ProtocolFeeCollectorAddress event
+type ExchangeProtocolFeeCollectorAddressEvent struct {
+	OldProtocolFeeCollector     common.Address
+	UpdatedProtocolFeeCollector common.Address
+}
+
+type exchangeProtocolFeeCollectorAddressEventJSON struct {
+	OldProtocolFeeCollector     string `json:"oldProtocolFeeCollector"`
+	UpdatedProtocolFeeCollector string `json:"updatedProtocolFeeCollector"`
+}
+
+// MarshalJSON implements a custom JSON marshaller for the ExchangeProtocolFeeCollectorAddressEvent type
+func (e ExchangeProtocolFeeCollectorAddressEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(exchangeProtocolFeeCollectorAddressEventJSON{
+		OldProtocolFeeCollector:     addressformat.String(e.OldProtocolFeeCollector),
+		UpdatedProtocolFeeCollector: addressformat.String(e.UpdatedProtocolFeeCollector),
+	})
+}
+
+func (e *ExchangeProtocolFeeCollectorAddressEvent) UnmarshalJSON(data []byte) error {
+	var eventJSON exchangeProtocolFeeCollectorAddressEventJSON
+	if err := json.Unmarshal(data, &eventJSON); err != nil {
+		return err
+	}
+	e.OldProtocolFeeCollector = common.HexToAddress(eventJSON.OldProtocolFeeCollector)
+	e.UpdatedProtocolFeeCollector = common.HexToAddress(eventJSON.UpdatedProtocolFeeCollector)
+
+	return nil
+}
+
+// CustomEvent represents an event emitted by a contract registered with
+// AddKnownCustomContract. Since the decoder does not know the meaning of
+// custom events, their parameters are decoded into a map keyed by the
+// parameter names in the contract's ABI.
+type CustomEvent struct {
+	// Name is the name of the event in the contract's ABI.
+	Name       string
+	Parameters map[string]interface{}
+}
+
+type customEventJSON struct {
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// MarshalJSON implements a custom JSON marshaller for the CustomEvent type.
+// Numbers are encoded as decimal strings and byte arrays as hex strings.
+func (e CustomEvent) MarshalJSON() ([]byte, error) {
+	parameters := make(map[string]interface{}, len(e.Parameters))
+	for name, value := range e.Parameters {
+		parameters[name] = customEventValueToJSON(value)
+	}
+	return json.Marshal(customEventJSON{
+		Name:       e.Name,
+		Parameters: parameters,
+	})
+}
+
+// UnmarshalJSON implements a custom JSON unmarshaller for the CustomEvent
+// type. Since the ABI is not known, parameters keep their JSON encoding
+// (e.g. numbers remain decimal strings).
+func (e *CustomEvent) UnmarshalJSON(data []byte) error {
+	var eventJSON customEventJSON
+	err := json.Unmarshal(data, &eventJSON)
+	if err != nil {
+		return err
+	}
+	e.Name = eventJSON.Name
+	e.Parameters = eventJSON.Parameters
+
+	return nil
+}
+
+// customEventValueToJSON converts a value decoded from a custom event into a
+// JSON-friendly representation.
+func customEventValueToJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return addressformat.String(v)
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return common.ToHex(v)
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Array, reflect.Slice:
+		if reflectValue.Type().Elem().Kind() == reflect.Uint8 {
+			bytes := make([]byte, reflectValue.Len())
+			reflect.Copy(reflect.ValueOf(bytes), reflectValue)
+			return common.ToHex(bytes)
+		}
+		values := make([]interface{}, reflectValue.Len())
+		for i := range values {
+			values[i] = customEventValueToJSON(reflectValue.Index(i).Interface())
+		}
+		return values
+	default:
+		return value
+	}
+}
+
 // WethWithdrawalEvent represents a wrapped Ether Withdraw event
 type WethWithdrawalEvent struct {
 	Owner common.Address
@@ -532,7 +780,7 @@ type wethWithdrawalEventJSON struct {
 // MarshalJSON implements a custom JSON marshaller for the WethWithdrawalEvent type
 func (w WethWithdrawalEvent) MarshalJSON() ([]byte, error) {
 	return json.Marshal(wethWithdrawalEventJSON{
-		Owner: w.Owner.Hex(),
+		Owner: addressformat.String(w.Owner),
 		Value: w.Value.String(),
 	})
 }
@@ -566,7 +814,7 @@ type wethDepositEventJSON struct {
 // MarshalJSON implements a custom JSON marshaller for the WethDepositEvent type
 func (w WethDepositEvent) MarshalJSON() ([]byte, error) {
 	return json.Marshal(wethDepositEventJSON{
-		Owner: w.Owner.Hex(),
+		Owner: addressformat.String(w.Owner),
 		Value: w.Value.String(),
 	})
 }
@@ -623,6 +871,8 @@ type Decoder struct {
 	knownERC721Addresses               map[common.Address]bool
 	knownERC1155Addresses              map[common.Address]bool
 	knownExchangeAddresses             map[common.Address]bool
+	knownCustomContractsMu             sync.RWMutex
+	knownCustomContracts               map[common.Address]*customContract
 	erc20ABI                           abi.ABI
 	erc721ABI                          abi.ABI
 	erc721EventsAbiWithoutTokenIDIndex abi.ABI
@@ -683,6 +933,7 @@ func New() (*Decoder, error) {
 		knownERC721Addresses:               make(map[common.Address]bool),
 		knownERC1155Addresses:              make(map[common.Address]bool),
 		knownExchangeAddresses:             make(map[common.Address]bool),
+		knownCustomContracts:               make(map[common.Address]*customContract),
 		erc20ABI:                           erc20ABI,
 		erc721ABI:                          erc721ABI,
 		erc721EventsAbiWithoutTokenIDIndex: erc721EventsAbiWithoutTokenIDIndex,
@@ -795,6 +1046,51 @@ func (d *Decoder) isKnownExchange(address common.Address) bool {
 	return exists
 }
 
+// customContract holds the parsed ABI of a contract registered with
+// AddKnownCustomContract.
+type customContract struct {
+	abi              abi.ABI
+	topicToEventName map[common.Hash]string
+}
+
+// AddKnownCustomContract registers the supplied contract address as a custom contract with the
+// given JSON ABI. This allows embedders to track events of contracts other than the ones supported
+// out of the box (e.g. custom settlement contracts or forked Exchange contracts). Events found from
+// this contract address are decoded into a CustomEvent.
+func (d *Decoder) AddKnownCustomContract(address common.Address, abiJSON string) error {
+	contractABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return err
+	}
+	topicToEventName := map[common.Hash]string{}
+	for _, event := range contractABI.Events {
+		topicToEventName[event.ID()] = event.Name
+	}
+	d.knownCustomContractsMu.Lock()
+	defer d.knownCustomContractsMu.Unlock()
+	d.knownCustomContracts[address] = &customContract{
+		abi:              contractABI,
+		topicToEventName: topicToEventName,
+	}
+	return nil
+}
+
+// RemoveKnownCustomContract removes a custom contract address from the list of known addresses. We
+// will no longer decode events for this contract.
+func (d *Decoder) RemoveKnownCustomContract(address common.Address) {
+	d.knownCustomContractsMu.Lock()
+	defer d.knownCustomContractsMu.Unlock()
+	delete(d.knownCustomContracts, address)
+}
+
+// getKnownCustomContract returns the custom contract registered with the supplied address or nil
+// if there is none
+func (d *Decoder) getKnownCustomContract(address common.Address) *customContract {
+	d.knownCustomContractsMu.RLock()
+	defer d.knownCustomContractsMu.RUnlock()
+	return d.knownCustomContracts[address]
+}
+
 // FindEventType returns to event type contained in the supplied log. It looks both at the registered
 // contract addresses and the log topic.
 func (d *Decoder) FindEventType(log types.Log) (string, error) {
@@ -830,6 +1126,12 @@ func (d *Decoder) FindEventType(log types.Log) (string, error) {
 		}
 		return fmt.Sprintf("Exchange%sEvent", eventName), nil
 	}
+	if contract := d.getKnownCustomContract(log.Address); contract != nil {
+		if _, ok := contract.topicToEventName[firstTopic]; !ok {
+			return "", UnsupportedEventError{Topics: log.Topics, ContractAddress: log.Address}
+		}
+		return "CustomEvent", nil
+	}
 
 	return "", UntrackedTokenError{Topic: firstTopic, TokenAddress: log.Address}
 }
@@ -849,6 +1151,13 @@ func (d *Decoder) Decode(log types.Log, decodedLog interface{}) error {
 	if isKnown := d.isKnownExchange(log.Address); isKnown {
 		return d.decodeExchange(log, decodedLog)
 	}
+	if contract := d.getKnownCustomContract(log.Address); contract != nil {
+		customEvent, ok := decodedLog.(*CustomEvent)
+		if !ok {
+			return fmt.Errorf("events of custom contracts must be decoded into a *CustomEvent, got %T", decodedLog)
+		}
+		return decodeCustom(log, customEvent, contract)
+	}
 
 	return UntrackedTokenError{Topic: log.Topics[0], TokenAddress: log.Address}
 }
@@ -912,6 +1221,35 @@ func (d *Decoder) decodeExchange(log types.Log, decodedLog interface{}) error {
 	return nil
 }
 
+func decodeCustom(log types.Log, decodedLog *CustomEvent, contract *customContract) error {
+	eventName, ok := contract.topicToEventName[log.Topics[0]]
+	if !ok {
+		return UnsupportedEventError{Topics: log.Topics, ContractAddress: log.Address}
+	}
+
+	parameters := map[string]interface{}{}
+	if len(log.Data) > 0 {
+		if err := contract.abi.UnpackIntoMap(parameters, eventName, log.Data); err != nil {
+			return err
+		}
+	}
+	var indexed abi.Arguments
+	for _, arg := range contract.abi.Events[eventName].Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(indexed) != len(log.Topics[1:]) {
+		return UnsupportedEventError{Topics: log.Topics, ContractAddress: log.Address}
+	}
+	if err := parseTopicsIntoMap(parameters, indexed, log.Topics[1:]); err != nil {
+		return err
+	}
+	decodedLog.Name = eventName
+	decodedLog.Parameters = parameters
+	return nil
+}
+
 // unpackLog unpacks a retrieved log into the provided output structure.
 func unpackLog(decodedEvent interface{}, event string, log types.Log, _abi abi.ABI) error {
 	if len(log.Data) > 0 {
@@ -1029,3 +1367,34 @@ func parseTopics(out interface{}, fields abi.Arguments, topics []common.Hash) er
 	}
 	return nil
 }
+
+// parseTopicsIntoMap converts the indexed topic fields into actual log field values and stores
+// them in out, keyed by the argument names. Dynamic types are stored as the Keccak256 hash found
+// in the topic since their value cannot be reconstructed.
+func parseTopicsIntoMap(out map[string]interface{}, fields abi.Arguments, topics []common.Hash) error {
+	// Sanity check that the fields and topics match up
+	if len(fields) != len(topics) {
+		return errors.New("topic/field count mismatch")
+	}
+	for i, arg := range fields {
+		if !arg.Indexed {
+			return errors.New("non-indexed field in topic reconstruction")
+		}
+		topic := topics[i]
+		switch arg.Type.T {
+		case abi.BoolTy:
+			out[arg.Name] = topic[common.HashLength-1] == 1
+		case abi.AddressTy:
+			out[arg.Name] = common.BytesToAddress(topic[common.HashLength-common.AddressLength:])
+		case abi.UintTy:
+			out[arg.Name] = new(big.Int).SetBytes(topic[:])
+		case abi.IntTy:
+			out[arg.Name] = math.S256(new(big.Int).SetBytes(topic[:]))
+		case abi.FixedBytesTy:
+			out[arg.Name] = common.CopyBytes(topic[:arg.Type.Size])
+		default:
+			out[arg.Name] = topic
+		}
+	}
+	return nil
+}
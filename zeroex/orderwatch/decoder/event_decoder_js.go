@@ -6,62 +6,63 @@ import (
 	"fmt"
 	"syscall/js"
 
+	"github.com/0xProject/0x-mesh/common/addressformat"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 func (e ERC20TransferEvent) JSValue() js.Value {
 	return js.ValueOf(map[string]interface{}{
-		"from":  e.From.Hex(),
-		"to":    e.To.Hex(),
+		"from":  addressformat.String(e.From),
+		"to":    addressformat.String(e.To),
 		"value": e.Value.String(),
 	})
 }
 
 func (e ERC20ApprovalEvent) JSValue() js.Value {
 	return js.ValueOf(map[string]interface{}{
-		"owner":   e.Owner.Hex(),
-		"spender": e.Spender.Hex(),
+		"owner":   addressformat.String(e.Owner),
+		"spender": addressformat.String(e.Spender),
 		"value":   e.Value.String(),
 	})
 }
 
 func (e ERC721TransferEvent) JSValue() js.Value {
 	return js.ValueOf(map[string]interface{}{
-		"from":    e.From.Hex(),
-		"to":      e.To.Hex(),
+		"from":    addressformat.String(e.From),
+		"to":      addressformat.String(e.To),
 		"tokenId": e.TokenId.String(),
 	})
 }
 
 func (e ERC721ApprovalEvent) JSValue() js.Value {
 	return js.ValueOf(map[string]interface{}{
-		"owner":    e.Owner.Hex(),
-		"approved": e.Approved.Hex(),
+		"owner":    addressformat.String(e.Owner),
+		"approved": addressformat.String(e.Approved),
 		"tokenId":  e.TokenId.String(),
 	})
 }
 
 func (e ERC721ApprovalForAllEvent) JSValue() js.Value {
 	return js.ValueOf(map[string]interface{}{
-		"owner":    e.Owner.Hex(),
-		"operator": e.Operator.Hex(),
+		"owner":    addressformat.String(e.Owner),
+		"operator": addressformat.String(e.Operator),
 		"approved": e.Approved,
 	})
 }
 
 func (e ERC1155ApprovalForAllEvent) JSValue() js.Value {
 	return js.ValueOf(map[string]interface{}{
-		"owner":    e.Owner.Hex(),
-		"operator": e.Operator.Hex(),
+		"owner":    addressformat.String(e.Owner),
+		"operator": addressformat.String(e.Operator),
 		"approved": e.Approved,
 	})
 }
 
 func (e ERC1155TransferSingleEvent) JSValue() js.Value {
 	return js.ValueOf(map[string]interface{}{
-		"operator": e.Operator.Hex(),
-		"from":     e.From.Hex(),
-		"to":       e.To.Hex(),
+		"operator": addressformat.String(e.Operator),
+		"from":     addressformat.String(e.From),
+		"to":       addressformat.String(e.To),
 		"id":       e.Id.String(),
 		"value":    e.Value.String(),
 	})
@@ -79,9 +80,9 @@ func (e ERC1155TransferBatchEvent) JSValue() js.Value {
 		values = append(values, value.String())
 	}
 	return js.ValueOf(map[string]interface{}{
-		"operator": e.Operator.Hex(),
-		"from":     e.From.Hex(),
-		"to":       e.To.Hex(),
+		"operator": addressformat.String(e.Operator),
+		"from":     addressformat.String(e.From),
+		"to":       addressformat.String(e.To),
 		"ids":      ids,
 		"values":   values,
 	})
@@ -105,10 +106,10 @@ func (e ExchangeFillEvent) JSValue() js.Value {
 		takerFeeAssetData = fmt.Sprintf("0x%s", common.Bytes2Hex(e.TakerFeeAssetData))
 	}
 	return js.ValueOf(map[string]interface{}{
-		"makerAddress":           e.MakerAddress.Hex(),
-		"takerAddress":           e.TakerAddress.Hex(),
-		"senderAddress":          e.SenderAddress.Hex(),
-		"feeRecipientAddress":    e.FeeRecipientAddress.Hex(),
+		"makerAddress":           addressformat.String(e.MakerAddress),
+		"takerAddress":           addressformat.String(e.TakerAddress),
+		"senderAddress":          addressformat.String(e.SenderAddress),
+		"feeRecipientAddress":    addressformat.String(e.FeeRecipientAddress),
 		"makerAssetFilledAmount": e.MakerAssetFilledAmount.String(),
 		"takerAssetFilledAmount": e.TakerAssetFilledAmount.String(),
 		"makerFeePaid":           e.MakerFeePaid.String(),
@@ -132,9 +133,9 @@ func (e ExchangeCancelEvent) JSValue() js.Value {
 		takerAssetData = fmt.Sprintf("0x%s", common.Bytes2Hex(e.TakerAssetData))
 	}
 	return js.ValueOf(map[string]interface{}{
-		"makerAddress":        e.MakerAddress.Hex(),
-		"senderAddress":       e.SenderAddress.Hex(),
-		"feeRecipientAddress": e.FeeRecipientAddress.Hex(),
+		"makerAddress":        addressformat.String(e.MakerAddress),
+		"senderAddress":       addressformat.String(e.SenderAddress),
+		"feeRecipientAddress": addressformat.String(e.FeeRecipientAddress),
 		"orderHash":           e.OrderHash.Hex(),
 		"makerAssetData":      makerAssetData,
 		"takerAssetData":      takerAssetData,
@@ -143,22 +144,68 @@ func (e ExchangeCancelEvent) JSValue() js.Value {
 
 func (e ExchangeCancelUpToEvent) JSValue() js.Value {
 	return js.ValueOf(map[string]interface{}{
-		"makerAddress":       e.MakerAddress.Hex(),
-		"orderSenderAddress": e.OrderSenderAddress.Hex(),
+		"makerAddress":       addressformat.String(e.MakerAddress),
+		"orderSenderAddress": addressformat.String(e.OrderSenderAddress),
 		"orderEpoch":         e.OrderEpoch.String(),
 	})
 }
 
+func (e ExchangeTransactionExecutionEvent) JSValue() js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"transactionHash": e.TransactionHash.Hex(),
+	})
+}
+
+func (e ExchangeSignatureValidatorApprovalEvent) JSValue() js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"signerAddress":    addressformat.String(e.SignerAddress),
+		"validatorAddress": addressformat.String(e.ValidatorAddress),
+		"isApproved":       e.IsApproved,
+	})
+}
+
+func (e ExchangeAssetProxyRegisteredEvent) JSValue() js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"id":         fmt.Sprintf("0x%s", common.Bytes2Hex(e.Id[:])),
+		"assetProxy": addressformat.String(e.AssetProxy),
+	})
+}
+
+func (e ExchangeProtocolFeeMultiplierEvent) JSValue() js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"oldProtocolFeeMultiplier":     e.OldProtocolFeeMultiplier.String(),
+		"updatedProtocolFeeMultiplier": e.UpdatedProtocolFeeMultiplier.String(),
+	})
+}
+
+func (e ExchangeProtocolFeeCollectorAddressEvent) JSValue() js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"oldProtocolFeeCollector":     addressformat.String(e.OldProtocolFeeCollector),
+		"updatedProtocolFeeCollector": addressformat.String(e.UpdatedProtocolFeeCollector),
+	})
+}
+
 func (w WethWithdrawalEvent) JSValue() js.Value {
 	return js.ValueOf(map[string]interface{}{
-		"owner": w.Owner.Hex(),
+		"owner": addressformat.String(w.Owner),
 		"value": w.Value.String(),
 	})
 }
 
 func (w WethDepositEvent) JSValue() js.Value {
 	return js.ValueOf(map[string]interface{}{
-		"owner": w.Owner.Hex(),
+		"owner": addressformat.String(w.Owner),
 		"value": w.Value.String(),
 	})
 }
+
+func (e CustomEvent) JSValue() js.Value {
+	parameters := make(map[string]interface{}, len(e.Parameters))
+	for name, value := range e.Parameters {
+		parameters[name] = customEventValueToJSON(value)
+	}
+	return js.ValueOf(map[string]interface{}{
+		"name":       e.Name,
+		"parameters": parameters,
+	})
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -480,6 +482,56 @@ func assertBigIntEqual(t *testing.T, expected *big.Int, actual *big.Int, msgAndA
 	assert.Equal(t, expected.String(), actual.String(), msgAndArgs...)
 }
 
+var customContractAddress common.Address = common.HexToAddress("0x6ecbe1db9ef729cbe972c83fb886247691fb6beb")
+
+const customContractABI string = "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"orderHash\",\"type\":\"bytes32\"},{\"indexed\":true,\"name\":\"maker\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"Settled\",\"type\":\"event\"}]"
+
+func TestDecodeCustomEvent(t *testing.T) {
+	parsedABI, err := abi.JSON(strings.NewReader(customContractABI))
+	require.NoError(t, err)
+	orderHash := common.HexToHash("0xddb8be9f6fed5209693ecce4eb127252827c1c331d661ae7a2491c80355f3fdd")
+	maker := common.HexToAddress("0xa258b39954cef5cb142fd567a46cddb31a670124")
+	settledLog := types.Log{
+		Address: customContractAddress,
+		Topics: []common.Hash{
+			parsedABI.Events["Settled"].ID(),
+			orderHash,
+			common.BytesToHash(maker.Bytes()),
+		},
+		Data: common.BigToHash(big.NewInt(1000)).Bytes(),
+	}
+
+	decoder, err := New()
+	require.NoError(t, err)
+	require.NoError(t, decoder.AddKnownCustomContract(customContractAddress, customContractABI))
+
+	eventType, err := decoder.FindEventType(settledLog)
+	require.NoError(t, err)
+	assert.Equal(t, "CustomEvent", eventType)
+
+	var actualEvent CustomEvent
+	require.NoError(t, decoder.Decode(settledLog, &actualEvent))
+	expectedEvent := CustomEvent{
+		Name: "Settled",
+		Parameters: map[string]interface{}{
+			"orderHash": orderHash,
+			"maker":     maker,
+			"amount":    big.NewInt(1000),
+		},
+	}
+	assert.Equal(t, expectedEvent, actualEvent, "Custom event decode")
+
+	decoder.RemoveKnownCustomContract(customContractAddress)
+	_, err = decoder.FindEventType(settledLog)
+	assert.IsType(t, UntrackedTokenError{}, err)
+}
+
+func TestAddKnownCustomContractInvalidABI(t *testing.T) {
+	decoder, err := New()
+	require.NoError(t, err)
+	assert.Error(t, decoder.AddKnownCustomContract(customContractAddress, "not an ABI"))
+}
+
 func TestJSONMarshalUnmarshalERC20Transfer(t *testing.T) {
 	expectedEvent := ERC20TransferEvent{
 		From:  common.HexToAddress("0x90CF64CbB199523C893A1D519243E214b8e0b472"),
@@ -652,6 +704,103 @@ func TestJSONMarshalUnmarshalExchangeCancelUpTo(t *testing.T) {
 	assert.Equal(t, expectedEvent, unmarshaledEvent)
 }
 
+func TestJSONMarshalUnmarshalExchangeTransactionExecution(t *testing.T) {
+	expectedEvent := ExchangeTransactionExecutionEvent{
+		TransactionHash: common.HexToHash("0x6c53a519cf31c3bf86162f3a46037979e2a2f6d1ab917275e5f64e5a7e2a0671"),
+	}
+
+	buf := bytes.Buffer{}
+	require.NoError(t, json.NewEncoder(&buf).Encode(expectedEvent))
+	var unmarshaledEvent ExchangeTransactionExecutionEvent
+	require.NoError(t, json.NewDecoder(&buf).Decode(&unmarshaledEvent))
+	assert.Equal(t, expectedEvent, unmarshaledEvent)
+}
+
+func TestJSONMarshalUnmarshalExchangeSignatureValidatorApproval(t *testing.T) {
+	expectedEvent := ExchangeSignatureValidatorApprovalEvent{
+		SignerAddress:    common.HexToAddress("0x638C1eF824ACD48E63E6ACC84948f8eAD46f08De"),
+		ValidatorAddress: common.HexToAddress("0x6ecbe1db9ef729cbe972c83fb886247691fb6beb"),
+		IsApproved:       true,
+	}
+
+	buf := bytes.Buffer{}
+	require.NoError(t, json.NewEncoder(&buf).Encode(expectedEvent))
+	var unmarshaledEvent ExchangeSignatureValidatorApprovalEvent
+	require.NoError(t, json.NewDecoder(&buf).Decode(&unmarshaledEvent))
+	assert.Equal(t, expectedEvent, unmarshaledEvent)
+}
+
+func TestJSONMarshalUnmarshalExchangeAssetProxyRegistered(t *testing.T) {
+	expectedEvent := ExchangeAssetProxyRegisteredEvent{
+		Id:         [4]byte{0xf4, 0x72, 0x61, 0xb0},
+		AssetProxy: common.HexToAddress("0x95e6f48254609a6ee006f7d493c8e5fb97094cef"),
+	}
+
+	buf := bytes.Buffer{}
+	require.NoError(t, json.NewEncoder(&buf).Encode(expectedEvent))
+	var unmarshaledEvent ExchangeAssetProxyRegisteredEvent
+	require.NoError(t, json.NewDecoder(&buf).Decode(&unmarshaledEvent))
+	assert.Equal(t, expectedEvent, unmarshaledEvent)
+}
+
+func TestJSONMarshalUnmarshalExchangeProtocolFeeMultiplier(t *testing.T) {
+	expectedEvent := ExchangeProtocolFeeMultiplierEvent{
+		OldProtocolFeeMultiplier:     big.NewInt(150000),
+		UpdatedProtocolFeeMultiplier: big.NewInt(70000),
+	}
+
+	buf := bytes.Buffer{}
+	require.NoError(t, json.NewEncoder(&buf).Encode(expectedEvent))
+	var unmarshaledEvent ExchangeProtocolFeeMultiplierEvent
+	require.NoError(t, json.NewDecoder(&buf).Decode(&unmarshaledEvent))
+	assert.Equal(t, expectedEvent, unmarshaledEvent)
+}
+
+func TestJSONMarshalUnmarshalExchangeProtocolFeeCollectorAddress(t *testing.T) {
+	expectedEvent := ExchangeProtocolFeeCollectorAddressEvent{
+		OldProtocolFeeCollector:     common.HexToAddress("0xa26e80e7dea86279c6d778d702cc413e6cffa777"),
+		UpdatedProtocolFeeCollector: common.HexToAddress("0x0000000000000000000000000000000000000000"),
+	}
+
+	buf := bytes.Buffer{}
+	require.NoError(t, json.NewEncoder(&buf).Encode(expectedEvent))
+	var unmarshaledEvent ExchangeProtocolFeeCollectorAddressEvent
+	require.NoError(t, json.NewDecoder(&buf).Decode(&unmarshaledEvent))
+	assert.Equal(t, expectedEvent, unmarshaledEvent)
+}
+
+func TestJSONMarshalCustomEvent(t *testing.T) {
+	maker := common.HexToAddress("0xa258b39954cef5cb142fd567a46cddb31a670124")
+	event := CustomEvent{
+		Name: "Settled",
+		Parameters: map[string]interface{}{
+			"orderHash": common.HexToHash("0xddb8be9f6fed5209693ecce4eb127252827c1c331d661ae7a2491c80355f3fdd"),
+			"maker":     maker,
+			"amount":    big.NewInt(1000),
+			"ids":       []*big.Int{big.NewInt(1), big.NewInt(2)},
+			"selector":  [4]byte{0xf4, 0x72, 0x61, 0xb0},
+			"approved":  true,
+		},
+	}
+
+	buf := bytes.Buffer{}
+	require.NoError(t, json.NewEncoder(&buf).Encode(event))
+	var unmarshaledEvent CustomEvent
+	require.NoError(t, json.NewDecoder(&buf).Decode(&unmarshaledEvent))
+	expectedEvent := CustomEvent{
+		Name: "Settled",
+		Parameters: map[string]interface{}{
+			"orderHash": "0xddb8be9f6fed5209693ecce4eb127252827c1c331d661ae7a2491c80355f3fdd",
+			"maker":     "0xa258b39954cef5cb142fd567a46cddb31a670124",
+			"amount":    "1000",
+			"ids":       []interface{}{"1", "2"},
+			"selector":  "0xf47261b0",
+			"approved":  true,
+		},
+	}
+	assert.Equal(t, expectedEvent, unmarshaledEvent)
+}
+
 func TestJSONMarshalUnmarshalWethDeposit(t *testing.T) {
 	expectedEvent := WethDepositEvent{
 		Owner: common.HexToAddress("0x81228eA33D680B0F51271aBAb1105886eCd01C2c"),
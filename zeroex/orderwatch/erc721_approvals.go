@@ -0,0 +1,120 @@
+package orderwatch
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// erc721Owner identifies the tokens of an owner within an ERC721 contract.
+type erc721Owner struct {
+	tokenAddress common.Address
+	owner        common.Address
+}
+
+// erc721Token identifies a single ERC721 token.
+type erc721Token struct {
+	tokenAddress common.Address
+	// tokenID is the decimal representation of the token ID.
+	tokenID string
+}
+
+// erc721ApprovalTracker keeps track of whether the ERC721Proxy may transfer
+// ERC721 tokens. Approvals given for all of an owner's tokens (ApprovalForAll)
+// and approvals given for individual tokens (Approval) are tracked separately
+// so that an approval event only causes the orders whose fillability it can
+// actually change to be re-validated.
+//
+// The tracker only knows about approvals it has seen in events. Unknown
+// approvals are treated as if they might have changed, so the tracker never
+// causes a necessary re-validation to be skipped.
+type erc721ApprovalTracker struct {
+	mu sync.Mutex
+	// approvedForAll maps an owner to whether the ERC721Proxy is an approved
+	// operator for all of their tokens.
+	approvedForAll map[erc721Owner]bool
+	// tokenApprovals maps a token to whether the ERC721Proxy is the approved
+	// address for it.
+	tokenApprovals map[erc721Token]bool
+}
+
+func newERC721ApprovalTracker() *erc721ApprovalTracker {
+	return &erc721ApprovalTracker{
+		approvedForAll: map[erc721Owner]bool{},
+		tokenApprovals: map[erc721Token]bool{},
+	}
+}
+
+// setApprovalForAll records an ApprovalForAll event for the ERC721Proxy. It
+// returns true if the owner's orders need to be re-validated. Since the event
+// may be undone by a block re-org, the approval is forgotten if isRemoved is
+// true.
+func (t *erc721ApprovalTracker) setApprovalForAll(tokenAddress, owner common.Address, approved bool, isRemoved bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := erc721Owner{tokenAddress: tokenAddress, owner: owner}
+	if isRemoved {
+		delete(t.approvedForAll, key)
+		return true
+	}
+	wasApproved, known := t.approvedForAll[key]
+	t.approvedForAll[key] = approved
+	return !known || wasApproved != approved
+}
+
+// setTokenApproval records an Approval event for a single token.
+// proxyApproved is whether the approved address is the ERC721Proxy. It
+// returns true if the orders involving the token need to be re-validated,
+// which is not the case if the ERC721Proxy is known to be approved for all of
+// the owner's tokens. Since the event may be undone by a block re-org, the
+// approval is forgotten if isRemoved is true.
+func (t *erc721ApprovalTracker) setTokenApproval(tokenAddress, owner common.Address, tokenID *big.Int, proxyApproved bool, isRemoved bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := erc721Token{tokenAddress: tokenAddress, tokenID: tokenID.String()}
+	if isRemoved {
+		delete(t.tokenApprovals, key)
+		return true
+	}
+	wasApproved, known := t.tokenApprovals[key]
+	t.tokenApprovals[key] = proxyApproved
+	if known && wasApproved == proxyApproved {
+		return false
+	}
+	if approvedForAll := t.approvedForAll[erc721Owner{tokenAddress: tokenAddress, owner: owner}]; approvedForAll {
+		// The per-token approval has no effect on fillability.
+		return false
+	}
+	return true
+}
+
+// clearTokenApproval records that a token has been transferred, which clears
+// its per-token approval.
+func (t *erc721ApprovalTracker) clearTokenApproval(tokenAddress common.Address, tokenID *big.Int, isRemoved bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := erc721Token{tokenAddress: tokenAddress, tokenID: tokenID.String()}
+	if isRemoved {
+		delete(t.tokenApprovals, key)
+		return
+	}
+	t.tokenApprovals[key] = false
+}
+
+// removeToken forgets all approvals for the given ERC721 contract. It is
+// called once no stored orders involve the contract anymore.
+func (t *erc721ApprovalTracker) removeToken(tokenAddress common.Address) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key := range t.approvedForAll {
+		if key.tokenAddress == tokenAddress {
+			delete(t.approvedForAll, key)
+		}
+	}
+	for key := range t.tokenApprovals {
+		if key.tokenAddress == tokenAddress {
+			delete(t.tokenApprovals, key)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// +build !js
+
+package orderwatch
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestERC721ApprovalTrackerApprovalForAll(t *testing.T) {
+	tracker := newERC721ApprovalTracker()
+	tokenAddress := common.HexToAddress("0x5d00d312e171be5342067c09bae883f9bcb2003b")
+	owner := common.HexToAddress("0x6ecbe1db9ef729cbe972c83fb886247691fb6beb")
+
+	assert.True(t, tracker.setApprovalForAll(tokenAddress, owner, true, false), "unknown approval should require re-validation")
+	assert.False(t, tracker.setApprovalForAll(tokenAddress, owner, true, false), "unchanged approval should not require re-validation")
+	assert.True(t, tracker.setApprovalForAll(tokenAddress, owner, false, false), "revoked approval should require re-validation")
+	assert.True(t, tracker.setApprovalForAll(tokenAddress, owner, false, true), "removed event should require re-validation")
+	assert.True(t, tracker.setApprovalForAll(tokenAddress, owner, false, false), "approval should be unknown after a removed event")
+}
+
+func TestERC721ApprovalTrackerTokenApproval(t *testing.T) {
+	tracker := newERC721ApprovalTracker()
+	tokenAddress := common.HexToAddress("0x5d00d312e171be5342067c09bae883f9bcb2003b")
+	owner := common.HexToAddress("0x6ecbe1db9ef729cbe972c83fb886247691fb6beb")
+	tokenID := big.NewInt(50609)
+	otherTokenID := big.NewInt(1)
+
+	assert.True(t, tracker.setTokenApproval(tokenAddress, owner, tokenID, true, false), "unknown approval should require re-validation")
+	assert.False(t, tracker.setTokenApproval(tokenAddress, owner, tokenID, true, false), "unchanged approval should not require re-validation")
+	assert.True(t, tracker.setTokenApproval(tokenAddress, owner, otherTokenID, false, false), "approvals should be tracked per token")
+	assert.False(t, tracker.setTokenApproval(tokenAddress, owner, otherTokenID, false, false), "unchanged approval should not require re-validation")
+
+	// Transfers reset the approval of a token.
+	tracker.clearTokenApproval(tokenAddress, tokenID, false)
+	assert.False(t, tracker.setTokenApproval(tokenAddress, owner, tokenID, false, false), "approval should be cleared by a transfer")
+
+	// Per-token approvals don't matter while the owner has approved all tokens.
+	tracker.setApprovalForAll(tokenAddress, owner, true, false)
+	assert.False(t, tracker.setTokenApproval(tokenAddress, owner, tokenID, true, false), "approval should not matter when approved for all")
+}
+
+func TestERC721ApprovalTrackerRemoveToken(t *testing.T) {
+	tracker := newERC721ApprovalTracker()
+	tokenAddress := common.HexToAddress("0x5d00d312e171be5342067c09bae883f9bcb2003b")
+	owner := common.HexToAddress("0x6ecbe1db9ef729cbe972c83fb886247691fb6beb")
+	tokenID := big.NewInt(50609)
+
+	tracker.setApprovalForAll(tokenAddress, owner, false, false)
+	tracker.setTokenApproval(tokenAddress, owner, tokenID, true, false)
+	tracker.removeToken(tokenAddress)
+	assert.Empty(t, tracker.approvedForAll)
+	assert.Empty(t, tracker.tokenApprovals)
+}
@@ -0,0 +1,248 @@
+package orderwatch
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	logger "github.com/sirupsen/logrus"
+)
+
+// addressSet is a set of Ethereum addresses.
+type addressSet map[common.Address]struct{}
+
+func newAddressSet(addresses []common.Address) addressSet {
+	set := addressSet{}
+	for _, address := range addresses {
+		set[address] = struct{}{}
+	}
+	return set
+}
+
+func (s addressSet) contains(address common.Address) bool {
+	_, found := s[address]
+	return found
+}
+
+// sorted returns the addresses in the set in ascending order.
+func (s addressSet) sorted() []common.Address {
+	addresses := make([]common.Address, 0, len(s))
+	for address := range s {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i].Bytes(), addresses[j].Bytes()) == -1
+	})
+	return addresses
+}
+
+// MakerAllowlist returns the maker addresses on the allowlist. An empty
+// allowlist means orders from any maker are allowed unless the maker is on the
+// denylist.
+func (w *Watcher) MakerAllowlist() []common.Address {
+	w.makerListsMu.RLock()
+	defer w.makerListsMu.RUnlock()
+	return w.makerAllowlist.sorted()
+}
+
+// MakerDenylist returns the maker addresses on the denylist.
+func (w *Watcher) MakerDenylist() []common.Address {
+	w.makerListsMu.RLock()
+	defer w.makerListsMu.RUnlock()
+	return w.makerDenylist.sorted()
+}
+
+// SetMakerAllowlist replaces the maker allowlist. Any stored orders whose maker
+// is no longer allowed are removed and a STOPPED_WATCHING event is emitted for
+// each of them. It returns the number of orders that were removed.
+func (w *Watcher) SetMakerAllowlist(addresses []common.Address) (int, error) {
+	w.makerListsMu.Lock()
+	w.makerAllowlist = newAddressSet(addresses)
+	w.makerListsMu.Unlock()
+	return w.removeDisallowedOrders()
+}
+
+// SetMakerDenylist replaces the maker denylist. Any stored orders whose maker
+// is now denied are removed and a STOPPED_WATCHING event is emitted for each of
+// them. It returns the number of orders that were removed.
+func (w *Watcher) SetMakerDenylist(addresses []common.Address) (int, error) {
+	w.makerListsMu.Lock()
+	w.makerDenylist = newAddressSet(addresses)
+	w.makerListsMu.Unlock()
+	return w.removeDisallowedOrders()
+}
+
+// isMakerAllowed returns whether orders from the given maker may be stored.
+// The denylist takes precedence over the allowlist.
+func (w *Watcher) isMakerAllowed(makerAddress common.Address) bool {
+	w.makerListsMu.RLock()
+	defer w.makerListsMu.RUnlock()
+	if w.makerDenylist.contains(makerAddress) {
+		return false
+	}
+	if len(w.makerAllowlist) > 0 && !w.makerAllowlist.contains(makerAddress) {
+		return false
+	}
+	return true
+}
+
+// AssetDenylist returns the token addresses on the asset denylist.
+func (w *Watcher) AssetDenylist() []common.Address {
+	w.assetDenylistMu.RLock()
+	defer w.assetDenylistMu.RUnlock()
+	return w.assetDenylist.sorted()
+}
+
+// SetAssetDenylist replaces the asset denylist. Any stored orders involving a
+// denied token are removed and a STOPPED_WATCHING event is emitted for each of
+// them. It returns the number of orders that were removed.
+func (w *Watcher) SetAssetDenylist(addresses []common.Address) (int, error) {
+	w.assetDenylistMu.Lock()
+	w.assetDenylist = newAddressSet(addresses)
+	w.assetDenylistMu.Unlock()
+	return w.removeDisallowedOrders()
+}
+
+// areAssetsAllowed returns false if any of the asset data of the given order
+// involves a token on the asset denylist.
+func (w *Watcher) areAssetsAllowed(order *zeroex.SignedOrder) bool {
+	w.assetDenylistMu.RLock()
+	defer w.assetDenylistMu.RUnlock()
+	if len(w.assetDenylist) == 0 {
+		return true
+	}
+	for _, assetData := range [][]byte{order.MakerAssetData, order.TakerAssetData, order.MakerFeeAssetData, order.TakerFeeAssetData} {
+		if len(assetData) == 0 {
+			continue
+		}
+		addresses, err := w.contractAddressesFromAssetData(assetData)
+		if err != nil {
+			// Orders with invalid asset data will be rejected by the order
+			// validator.
+			continue
+		}
+		for _, address := range addresses {
+			if w.assetDenylist.contains(address) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// contractAddressesFromAssetData returns all contract addresses involved in a
+// transfer of the given asset data, including those of nested assets, bridges
+// and staticcall targets.
+func (w *Watcher) contractAddressesFromAssetData(assetData []byte) ([]common.Address, error) {
+	assetDataName, err := w.assetDataDecoder.GetName(assetData)
+	if err != nil {
+		return nil, err
+	}
+	switch assetDataName {
+	case "ERC20Token":
+		var decodedAssetData zeroex.ERC20AssetData
+		if err := w.assetDataDecoder.Decode(assetData, &decodedAssetData); err != nil {
+			return nil, err
+		}
+		return []common.Address{decodedAssetData.Address}, nil
+	case "ERC721Token":
+		var decodedAssetData zeroex.ERC721AssetData
+		if err := w.assetDataDecoder.Decode(assetData, &decodedAssetData); err != nil {
+			return nil, err
+		}
+		return []common.Address{decodedAssetData.Address}, nil
+	case "ERC1155Assets":
+		var decodedAssetData zeroex.ERC1155AssetData
+		if err := w.assetDataDecoder.Decode(assetData, &decodedAssetData); err != nil {
+			return nil, err
+		}
+		return []common.Address{decodedAssetData.Address}, nil
+	case "StaticCall":
+		var decodedAssetData zeroex.StaticCallAssetData
+		if err := w.assetDataDecoder.Decode(assetData, &decodedAssetData); err != nil {
+			return nil, err
+		}
+		return []common.Address{decodedAssetData.StaticCallTargetAddress}, nil
+	case "ERC20Bridge":
+		var decodedAssetData zeroex.ERC20BridgeAssetData
+		if err := w.assetDataDecoder.Decode(assetData, &decodedAssetData); err != nil {
+			return nil, err
+		}
+		return []common.Address{decodedAssetData.TokenAddress, decodedAssetData.BridgeAddress}, nil
+	case "MultiAsset":
+		var decodedAssetData zeroex.MultiAssetData
+		if err := w.assetDataDecoder.Decode(assetData, &decodedAssetData); err != nil {
+			return nil, err
+		}
+		addresses := []common.Address{}
+		for _, nestedAssetData := range decodedAssetData.NestedAssetData {
+			nestedAddresses, err := w.contractAddressesFromAssetData(nestedAssetData)
+			if err != nil {
+				return nil, err
+			}
+			addresses = append(addresses, nestedAddresses...)
+		}
+		return addresses, nil
+	default:
+		return nil, fmt.Errorf("unrecognized assetData type name found: %s", assetDataName)
+	}
+}
+
+// removeDisallowedOrders permanently deletes all stored orders that are not
+// allowed by the current maker lists or asset denylist and emits
+// STOPPED_WATCHING events for any of them that were still being watched.
+func (w *Watcher) removeDisallowedOrders() (int, error) {
+	// Pause block event processing and order additions while we remove orders.
+	w.handleBlockEventsMu.Lock()
+	defer w.handleBlockEventsMu.Unlock()
+
+	orders := []*meshdb.Order{}
+	if err := w.meshDB.Orders.FindAll(&orders); err != nil {
+		return 0, err
+	}
+
+	ordersColTxn := w.meshDB.Orders.OpenTransaction()
+	defer func() {
+		_ = ordersColTxn.Discard()
+	}()
+	now := time.Now().UTC()
+	numOrdersRemoved := 0
+	orderEvents := []*zeroex.OrderEvent{}
+	for _, order := range orders {
+		if w.isMakerAllowed(order.SignedOrder.MakerAddress) && w.areAssetsAllowed(order.SignedOrder) {
+			continue
+		}
+		if err := w.permanentlyDeleteOrder(ordersColTxn, order); err != nil {
+			return 0, err
+		}
+		numOrdersRemoved++
+		if order.IsRemoved {
+			// Removed orders are no longer being watched and subscribers have
+			// already received an event for them.
+			continue
+		}
+		expirationTimestamp := time.Unix(order.SignedOrder.ExpirationTimeSeconds.Int64(), 0)
+		w.expirationWatcher.Remove(expirationTimestamp, order.Hash.Hex())
+		orderEvents = append(orderEvents, &zeroex.OrderEvent{
+			Timestamp:                now,
+			OrderHash:                order.Hash,
+			SignedOrder:              order.SignedOrder,
+			FillableTakerAssetAmount: order.FillableTakerAssetAmount,
+			EndState:                 zeroex.ESStoppedWatching,
+			Metadata:                 order.Metadata,
+		})
+	}
+	if err := ordersColTxn.Commit(); err != nil {
+		return 0, err
+	}
+
+	if numOrdersRemoved > 0 {
+		logger.WithField("numOrdersRemoved", numOrdersRemoved).Info("removed orders that are no longer allowed")
+	}
+	w.sendOrderEvents(orderEvents)
+	return numOrdersRemoved, nil
+}
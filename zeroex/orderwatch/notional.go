@@ -0,0 +1,246 @@
+package orderwatch
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/priceoracle"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/ethereum/go-ethereum/common"
+	logger "github.com/sirupsen/logrus"
+)
+
+// notionalLookupTimeout is the maximum amount of time to spend looking up token
+// prices when computing the notional values of a batch of orders.
+const notionalLookupTimeout = 30 * time.Second
+
+// OrderNotionalUSD returns the approximate USD value of the remaining fillable
+// portion of the given order. The value is based on the maker asset if its
+// price is known and otherwise on the taker asset. The second return value is
+// false if no PriceOracle is configured or if neither asset is an ERC20 token
+// with a known price.
+func (w *Watcher) OrderNotionalUSD(ctx context.Context, signedOrder *zeroex.SignedOrder, fillableTakerAssetAmount *big.Int) (float64, bool) {
+	if w.priceOracle == nil {
+		return 0, false
+	}
+	return w.orderNotionalUSD(ctx, w.priceOracle, signedOrder, fillableTakerAssetAmount)
+}
+
+// orderNotionalUSD is like OrderNotionalUSD but looks up token prices with the
+// given oracle. Batch operations pass a tokenPriceCache, so that the price of
+// each token is only looked up once.
+func (w *Watcher) orderNotionalUSD(ctx context.Context, oracle priceoracle.PriceOracle, signedOrder *zeroex.SignedOrder, fillableTakerAssetAmount *big.Int) (float64, bool) {
+	if fillableTakerAssetAmount == nil || signedOrder.TakerAssetAmount.Sign() == 0 {
+		return 0, false
+	}
+
+	if token, ok := w.erc20TokenAddress(signedOrder.MakerAssetData); ok {
+		fillableMakerAssetAmount := new(big.Int).Mul(signedOrder.MakerAssetAmount, fillableTakerAssetAmount)
+		fillableMakerAssetAmount.Div(fillableMakerAssetAmount, signedOrder.TakerAssetAmount)
+		if value, err := priceoracle.ValueUSD(ctx, oracle, token, fillableMakerAssetAmount); err == nil {
+			return value, true
+		} else if err != priceoracle.ErrPriceUnavailable {
+			logger.WithError(err).WithField("token", token.Hex()).Debug("could not get token price")
+		}
+	}
+	if token, ok := w.erc20TokenAddress(signedOrder.TakerAssetData); ok {
+		if value, err := priceoracle.ValueUSD(ctx, oracle, token, fillableTakerAssetAmount); err == nil {
+			return value, true
+		} else if err != priceoracle.ErrPriceUnavailable {
+			logger.WithError(err).WithField("token", token.Hex()).Debug("could not get token price")
+		}
+	}
+	return 0, false
+}
+
+// tokenPriceCache is a PriceOracle which remembers the prices and errors
+// returned by another PriceOracle. It is used for the duration of a single
+// batch operation, so that the number of price lookups is bounded by the
+// number of distinct tokens rather than the number of orders. It is not safe
+// for concurrent use.
+type tokenPriceCache struct {
+	oracle priceoracle.PriceOracle
+	prices map[common.Address]tokenPriceResult
+}
+
+type tokenPriceResult struct {
+	price *big.Float
+	err   error
+}
+
+func newTokenPriceCache(oracle priceoracle.PriceOracle) *tokenPriceCache {
+	return &tokenPriceCache{
+		oracle: oracle,
+		prices: map[common.Address]tokenPriceResult{},
+	}
+}
+
+// PriceUSD returns the price of the given token, looking it up only the first
+// time it is requested.
+func (c *tokenPriceCache) PriceUSD(ctx context.Context, token common.Address) (*big.Float, error) {
+	if result, found := c.prices[token]; found {
+		return result.price, result.err
+	}
+	price, err := c.oracle.PriceUSD(ctx, token)
+	c.prices[token] = tokenPriceResult{price: price, err: err}
+	return price, err
+}
+
+// erc20TokenAddress returns the token address of the given asset data if it
+// is ERC20 asset data.
+func (w *Watcher) erc20TokenAddress(assetData []byte) (common.Address, bool) {
+	assetDataName, err := w.assetDataDecoder.GetName(assetData)
+	if err != nil || assetDataName != "ERC20Token" {
+		return common.Address{}, false
+	}
+	var decodedAssetData zeroex.ERC20AssetData
+	if err := w.assetDataDecoder.Decode(assetData, &decodedAssetData); err != nil {
+		return common.Address{}, false
+	}
+	return decodedAssetData.Address, true
+}
+
+// minNotionalUSD returns the minimum USD notional value that new orders must
+// have. It is the greater of the configured minimum and the notional value of
+// the most valuable order that was evicted to make space, if any.
+func (w *Watcher) minNotionalUSD() float64 {
+	w.notionalMu.RLock()
+	defer w.notionalMu.RUnlock()
+	if w.evictedNotionalUSD > w.minOrderNotionalUSD {
+		return w.evictedNotionalUSD
+	}
+	return w.minOrderNotionalUSD
+}
+
+// rejectLowNotionalOrders moves new orders whose USD notional value is known
+// and below the minimum from results.Accepted to results.Rejected. It returns
+// the new orders that should still be added. Orders whose value is unknown are
+// never rejected.
+func (w *Watcher) rejectLowNotionalOrders(ctx context.Context, results *ordervalidator.ValidationResults, newOrderInfos []*ordervalidator.AcceptedOrderInfo) []*ordervalidator.AcceptedOrderInfo {
+	minNotionalUSD := w.minNotionalUSD()
+	if w.priceOracle == nil || minNotionalUSD <= 0 || len(newOrderInfos) == 0 {
+		return newOrderInfos
+	}
+	ctx, cancel := context.WithTimeout(ctx, notionalLookupTimeout)
+	defer cancel()
+	prices := newTokenPriceCache(w.priceOracle)
+
+	rejectedOrderHashes := map[common.Hash]struct{}{}
+	remainingOrderInfos := []*ordervalidator.AcceptedOrderInfo{}
+	for _, orderInfo := range newOrderInfos {
+		notionalUSD, ok := w.orderNotionalUSD(ctx, prices, orderInfo.SignedOrder, orderInfo.FillableTakerAssetAmount)
+		if ok && notionalUSD < minNotionalUSD {
+			rejectedOrderHashes[orderInfo.OrderHash] = struct{}{}
+			continue
+		}
+		remainingOrderInfos = append(remainingOrderInfos, orderInfo)
+	}
+	if len(rejectedOrderHashes) == 0 {
+		return newOrderInfos
+	}
+
+	accepted := []*ordervalidator.AcceptedOrderInfo{}
+	for _, orderInfo := range results.Accepted {
+		if _, found := rejectedOrderHashes[orderInfo.OrderHash]; found {
+			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
+				OrderHash:   orderInfo.OrderHash,
+				SignedOrder: orderInfo.SignedOrder,
+				Kind:        ordervalidator.MeshValidation,
+				Status:      ordervalidator.ROOrderNotionalTooLow,
+			})
+			continue
+		}
+		accepted = append(accepted, orderInfo)
+	}
+	results.Accepted = accepted
+	return remainingOrderInfos
+}
+
+// trimLowValueOrders removes the non-pinned orders with the lowest known USD
+// notional value until there are at most targetMaxOrders orders in the
+// database. Orders whose value is unknown are left to be trimmed by expiration
+// time. In order to avoid immediately re-adding the removed orders, the
+// notional value of the most valuable removed order becomes the minimum
+// notional value for new orders until there is space in the database again.
+// The price of each token is only looked up once per trim.
+func (w *Watcher) trimLowValueOrders(targetMaxOrders int) ([]*meshdb.Order, error) {
+	numOrders, err := w.meshDB.Orders.Count()
+	if err != nil {
+		return nil, err
+	}
+	if numOrders <= targetMaxOrders {
+		return nil, nil
+	}
+
+	// We use a prefix filter of "0|" so that we only consider non-pinned
+	// orders.
+	orders := []*meshdb.Order{}
+	filter := w.meshDB.Orders.ExpirationTimeIndex.PrefixFilter([]byte("0|"))
+	if err := w.meshDB.Orders.NewQuery(filter).Run(&orders); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notionalLookupTimeout)
+	defer cancel()
+	prices := newTokenPriceCache(w.priceOracle)
+	type valuedOrder struct {
+		order       *meshdb.Order
+		notionalUSD float64
+	}
+	valuedOrders := []valuedOrder{}
+	for _, order := range orders {
+		if notionalUSD, ok := w.orderNotionalUSD(ctx, prices, order.SignedOrder, order.FillableTakerAssetAmount); ok {
+			valuedOrders = append(valuedOrders, valuedOrder{order: order, notionalUSD: notionalUSD})
+		}
+	}
+	sort.SliceStable(valuedOrders, func(i, j int) bool {
+		return valuedOrders[i].notionalUSD < valuedOrders[j].notionalUSD
+	})
+	numOrdersToRemove := numOrders - targetMaxOrders
+	if len(valuedOrders) > numOrdersToRemove {
+		valuedOrders = valuedOrders[:numOrdersToRemove]
+	}
+	if len(valuedOrders) == 0 {
+		return nil, nil
+	}
+
+	txn := w.meshDB.Orders.OpenTransaction()
+	defer func() {
+		_ = txn.Discard()
+	}()
+	removedOrders := make([]*meshdb.Order, len(valuedOrders))
+	for i, valuedOrder := range valuedOrders {
+		if err := txn.Delete(valuedOrder.order.Hash.Bytes()); err != nil {
+			return nil, err
+		}
+		removedOrders[i] = valuedOrder.order
+	}
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
+
+	evictedNotionalUSD := valuedOrders[len(valuedOrders)-1].notionalUSD
+	w.notionalMu.Lock()
+	if evictedNotionalUSD > w.evictedNotionalUSD {
+		logger.WithFields(logger.Fields{
+			"oldEvictedNotionalUSD": w.evictedNotionalUSD,
+			"newEvictedNotionalUSD": evictedNotionalUSD,
+		}).Debug("increasing minimum notional value for new orders")
+		w.evictedNotionalUSD = evictedNotionalUSD
+	}
+	w.notionalMu.Unlock()
+	return removedOrders, nil
+}
+
+// resetEvictedNotional removes the minimum notional value that was set when
+// low-value orders were evicted. It is called once there is space for new
+// orders again.
+func (w *Watcher) resetEvictedNotional() {
+	w.notionalMu.Lock()
+	defer w.notionalMu.Unlock()
+	w.evictedNotionalUSD = 0
+}
@@ -0,0 +1,140 @@
+// +build !js
+
+package orderwatch
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/priceoracle"
+	"github.com/0xProject/0x-mesh/scenario"
+	"github.com/0xProject/0x-mesh/scenario/orderopts"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticPriceOracle map[common.Address]*big.Float
+
+func (s staticPriceOracle) PriceUSD(ctx context.Context, token common.Address) (*big.Float, error) {
+	price, found := s[token]
+	if !found {
+		return nil, priceoracle.ErrPriceUnavailable
+	}
+	return price, nil
+}
+
+// countingPriceOracle counts the number of prices looked up via oracle.
+type countingPriceOracle struct {
+	oracle   priceoracle.PriceOracle
+	numCalls int
+}
+
+func (c *countingPriceOracle) PriceUSD(ctx context.Context, token common.Address) (*big.Float, error) {
+	c.numCalls++
+	return c.oracle.PriceUSD(ctx, token)
+}
+
+func TestOrderNotionalUSD(t *testing.T) {
+	zrxAddress := ganacheAddresses.ZRXToken
+	wethAddress := ganacheAddresses.WETH9
+	signedOrder := scenario.NewSignedTestOrder(t,
+		orderopts.MakerAssetData(scenario.ZRXAssetData),
+		orderopts.MakerAssetAmount(big.NewInt(1000)),
+		orderopts.TakerAssetData(scenario.WETHAssetData),
+		orderopts.TakerAssetAmount(big.NewInt(100)),
+	)
+	fillableTakerAssetAmount := big.NewInt(50)
+
+	testCases := []struct {
+		description    string
+		oracle         priceoracle.PriceOracle
+		expectedValue  float64
+		expectedExists bool
+	}{
+		{
+			description:    "no price oracle",
+			oracle:         nil,
+			expectedExists: false,
+		},
+		{
+			description: "maker asset price known",
+			oracle: staticPriceOracle{
+				zrxAddress:  big.NewFloat(0.5),
+				wethAddress: big.NewFloat(100),
+			},
+			// 500 remaining maker asset base units at $0.5 each.
+			expectedValue:  250,
+			expectedExists: true,
+		},
+		{
+			description: "only taker asset price known",
+			oracle: staticPriceOracle{
+				wethAddress: big.NewFloat(2),
+			},
+			// 50 remaining taker asset base units at $2 each.
+			expectedValue:  100,
+			expectedExists: true,
+		},
+		{
+			description:    "no prices known",
+			oracle:         staticPriceOracle{},
+			expectedExists: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		w := &Watcher{
+			assetDataDecoder: zeroex.NewAssetDataDecoder(),
+			priceOracle:      testCase.oracle,
+		}
+		value, exists := w.OrderNotionalUSD(context.Background(), signedOrder, fillableTakerAssetAmount)
+		require.Equal(t, testCase.expectedExists, exists, testCase.description)
+		assert.InDelta(t, testCase.expectedValue, value, 1e-9, testCase.description)
+	}
+}
+
+func TestTrimLowValueOrdersLooksUpEachTokenPriceOnce(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	// Only the price of ZRX is known, so the orders selling WETH also require
+	// a lookup of the WETH price.
+	oracle := &countingPriceOracle{
+		oracle: staticPriceOracle{
+			ganacheAddresses.ZRXToken: big.NewFloat(1),
+		},
+	}
+	w := &Watcher{
+		meshDB:           meshDB,
+		assetDataDecoder: zeroex.NewAssetDataDecoder(),
+		priceOracle:      oracle,
+	}
+	numOrders := 10
+	for i := 1; i <= numOrders; i++ {
+		makerAssetData, takerAssetData := scenario.ZRXAssetData, scenario.WETHAssetData
+		if i%2 == 0 {
+			makerAssetData, takerAssetData = takerAssetData, makerAssetData
+		}
+		storeTestOrder(t, meshDB, scenario.NewSignedTestOrder(t,
+			orderopts.MakerAssetData(makerAssetData),
+			orderopts.MakerAssetAmount(big.NewInt(int64(i*100))),
+			orderopts.TakerAssetData(takerAssetData),
+			orderopts.TakerAssetAmount(big.NewInt(int64(i*100))),
+		))
+	}
+
+	removedOrders, err := w.trimLowValueOrders(numOrders / 2)
+	require.NoError(t, err)
+	require.Len(t, removedOrders, numOrders/2)
+	for i, removedOrder := range removedOrders {
+		assert.Equal(t, big.NewInt(int64((i+1)*100)), removedOrder.SignedOrder.MakerAssetAmount)
+	}
+	assert.Equal(t, 2, oracle.numCalls)
+	assert.Equal(t, float64(500), w.minNotionalUSD())
+}
@@ -0,0 +1,39 @@
+package orderwatch
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// updateOrderMetadata updates the metadata of the accepted orders in results
+// which were already stored. The metadata of new orders is stored when they
+// are added.
+func (w *Watcher) updateOrderMetadata(results *ordervalidator.ValidationResults, metadata map[common.Hash]json.RawMessage) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+	for _, acceptedOrderInfo := range results.Accepted {
+		if acceptedOrderInfo.IsNew {
+			continue
+		}
+		orderMetadata, found := metadata[acceptedOrderInfo.OrderHash]
+		if !found {
+			continue
+		}
+		order, err := w.findStoredOrder(acceptedOrderInfo.OrderHash)
+		if err != nil {
+			return err
+		}
+		if order == nil || bytes.Equal(order.Metadata, orderMetadata) {
+			continue
+		}
+		order.Metadata = orderMetadata
+		if err := w.meshDB.Orders.Update(order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/0xProject/0x-mesh/ethereum/miniheader"
 	"github.com/0xProject/0x-mesh/expirationwatch"
 	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/priceoracle"
 	"github.com/0xProject/0x-mesh/zeroex"
 	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
 	"github.com/0xProject/0x-mesh/zeroex/orderwatch/decoder"
@@ -27,10 +29,10 @@ import (
 )
 
 const (
-	// minCleanupInterval specified the minimum amount of time between orderbook
-	// cleanups. These cleanups are meant to catch any stale orders that somehow
-	// were not caught by the event watcher process.
-	minCleanupInterval = 1 * time.Hour
+	// defaultCleanupInterval specifies the default minimum amount of time
+	// between orderbook cleanups. These cleanups are meant to catch any stale
+	// orders that somehow were not caught by the event watcher process.
+	defaultCleanupInterval = 1 * time.Hour
 
 	// minRemovedCheckInterval specifies the minimum amount of time between checks
 	// on whether to remove orders flaggged for removal from the DB
@@ -45,10 +47,6 @@ const (
 	// corresponds to a block depth of ~25.
 	permanentlyDeleteAfter = 5 * time.Minute
 
-	// expirationPollingInterval specifies the interval in which the order watcher should check for expired
-	// orders
-	expirationPollingInterval = 50 * time.Millisecond
-
 	// maxOrdersTrimRatio affects how many orders are trimmed whenever we reach the
 	// maximum number of orders. When order storage is full, Watcher will remove
 	// orders until the total number of remaining orders is equal to
@@ -88,6 +86,10 @@ type Watcher struct {
 	expirationWatcher          *expirationwatch.Watcher
 	orderFeed                  event.Feed
 	orderScope                 event.SubscriptionScope // Subscription scope tracking current live listeners
+	contractEventFeed          event.Feed
+	contractEventScope         event.SubscriptionScope
+	customEventHandlers        map[common.Address]CustomEventHandler
+	erc721Approvals            *erc721ApprovalTracker
 	contractAddressToSeenCount map[common.Address]uint
 	orderValidator             *ordervalidator.OrderValidator
 	wasStartedOnce             bool
@@ -95,12 +97,50 @@ type Watcher struct {
 	maxExpirationTime          *big.Int
 	maxExpirationCounter       *slowcounter.SlowCounter
 	maxOrders                  int
+	makerListsMu               sync.RWMutex
+	makerAllowlist             addressSet
+	makerDenylist              addressSet
+	assetDenylistMu            sync.RWMutex
+	assetDenylist              addressSet
+	transferSimulationMode     TransferSimulationMode
+	takerRestrictedPolicy      RestrictedOrderPolicy
+	senderRestrictedPolicy     RestrictedOrderPolicy
+	replaceSupersededOrders    bool
+	dryRun                     bool
+	priceOracle                priceoracle.PriceOracle
+	minOrderNotionalUSD        float64
+	notionalMu                 sync.RWMutex
+	evictedNotionalUSD         float64
 	handleBlockEventsMu        sync.RWMutex
+	validationLanes            *laneScheduler
 	// atLeastOneBlockProcessed is closed to signal that the BlockWatcher has processed at least one
 	// block. Validation of orders should block until this has completed
-	atLeastOneBlockProcessed   chan struct{}
-	atLeastOneBlockProcessedMu sync.Mutex
-	didProcessABlock           bool
+	atLeastOneBlockProcessed    chan struct{}
+	atLeastOneBlockProcessedMu  sync.Mutex
+	didProcessABlock            bool
+	cleanupInterval             time.Duration
+	cleanupJitter               time.Duration
+	cleanupRand                 *rand.Rand
+	cleanupMaxOrdersPerRun      int
+	cleanupLastUpdatedBuffer    time.Duration
+	lastCleanupStatsMu          sync.RWMutex
+	lastCleanupStats            CleanupStats
+	chainStats                  chainStatsRecorder
+	revalidationProgressMu      sync.RWMutex
+	revalidationProgress        RevalidationProgress
+	validationStatsMu           sync.Mutex
+	validationStats             ValidationStats
+	maxOrderSizeInBytes         int
+	assetDataLimits             zeroex.AssetDataLimits
+	orderEventConfirmationDepth int
+	relayOnly                   bool
+	pendingOrderEventsMu        sync.Mutex
+	pendingOrderEvents          []*meshdb.PendingOrderEvents
+	nextPendingOrderEventsIndex uint64
+	recentBlockOrderEvents      map[common.Hash]*blockOrderEvents
+	sequenceNumberMu            sync.Mutex
+	lastSequenceNumber          uint64
+	unpublishedOrderEvents      []*zeroex.OrderEvent
 }
 
 type Config struct {
@@ -111,8 +151,200 @@ type Config struct {
 	ContractAddresses ethereum.ContractAddresses
 	MaxOrders         int
 	MaxExpirationTime *big.Int
+	// MakerAllowlist, if non-empty, is the set of maker addresses whose orders
+	// may be stored. Orders from any other maker will be rejected.
+	MakerAllowlist []common.Address
+	// MakerDenylist is a set of maker addresses whose orders will be rejected.
+	// It takes precedence over MakerAllowlist.
+	MakerDenylist []common.Address
+	// AssetDenylist is a set of token addresses. Orders whose asset data
+	// involves any of these addresses will be rejected.
+	AssetDenylist []common.Address
+	// TransferSimulationMode determines whether and how orders are checked for
+	// maker transfers which revert or deliver a different amount than was
+	// transferred (e.g. fee-on-transfer or rebasing tokens). Defaults to
+	// TransferSimulationOff.
+	TransferSimulationMode TransferSimulationMode
+	// TakerRestrictedOrderPolicy determines whether orders with a non-null
+	// takerAddress are stored. Defaults to RestrictedOrdersAccept.
+	TakerRestrictedOrderPolicy RestrictedOrderPolicy
+	// SenderRestrictedOrderPolicy determines whether orders with a non-null
+	// senderAddress are stored. Defaults to RestrictedOrdersReject, since
+	// orders with a senderAddress can be canceled off-chain.
+	SenderRestrictedOrderPolicy RestrictedOrderPolicy
+	// ReplaceSupersededOrders enables automatic replacement of orders: when a
+	// new order is added, stored orders with the same maker and asset pair but
+	// a lower salt are removed and a REPLACED event is emitted for each of them.
+	// New orders with a lower salt than a stored order with the same maker and
+	// asset pair are rejected with the OrderSuperseded code. It assumes that
+	// makers increase the salt of each new order.
+	ReplaceSupersededOrders bool
+	// DryRun makes ValidateAndStoreValidOrders validate new orders without
+	// storing them or removing the orders they supersede. Orders which were
+	// already stored are still watched.
+	DryRun bool
+	// PriceOracle, if non-nil, is used to compute approximate USD notional
+	// values for orders. When order storage is full, the orders with the lowest
+	// notional values are removed first.
+	PriceOracle priceoracle.PriceOracle
+	// MinOrderNotionalUSD is the minimum approximate USD notional value of new
+	// orders. Orders whose value cannot be determined are not affected. It has
+	// no effect if PriceOracle is nil.
+	MinOrderNotionalUSD float64
+	// CleanupInterval is the minimum amount of time between cleanups, which
+	// re-validate orders that have not been updated recently. Defaults to 1
+	// hour.
+	CleanupInterval time.Duration
+	// CleanupJitter is the maximum random delay added to CleanupInterval. It
+	// can be used to avoid several Mesh nodes which share an Ethereum RPC
+	// endpoint running cleanups at the same time. Defaults to 0.
+	CleanupJitter time.Duration
+	// CleanupMaxOrdersPerRun is the maximum number of orders re-validated by a
+	// single periodic cleanup. The least recently updated orders are
+	// re-validated first and any remaining orders are left for the next
+	// cleanup. If 0, there is no limit.
+	CleanupMaxOrdersPerRun int
+	// CleanupLastUpdatedBuffer specifies how long it must have been since an
+	// order was last updated in order to be re-validated by a periodic cleanup.
+	// Defaults to 30 minutes.
+	CleanupLastUpdatedBuffer time.Duration
+	// CustomContracts are additional contracts whose events can affect the
+	// fillability of orders, e.g. custom settlement contracts or forked
+	// Exchange contracts.
+	CustomContracts []CustomContract
+	// MaxOrderSizeInBytes is the maximum size of orders encoded as JSON. It
+	// cannot exceed constants.MaxOrderSizeInBytes, which is also the default.
+	MaxOrderSizeInBytes int
+	// AssetDataLimits are the limits on the size and MultiAsset nesting depth
+	// of the asset data of new orders. They are checked before any asset data
+	// is decoded. By default, asset data is not limited beyond
+	// MaxOrderSizeInBytes.
+	AssetDataLimits zeroex.AssetDataLimits
+	// OrderEventConfirmationDepth is the number of blocks that must be mined on
+	// top of the latest block at the time an order event was generated before
+	// the event is sent to subscribers. Order events which were only caused by
+	// blocks that are removed by a re-org before then are never sent. Order
+	// events which were not caused by contract events (e.g. ADDED) are not
+	// delayed unless there are pending order events for the same order.
+	// Pending order events are stored in the database. It does not delay any
+	// changes to the stored orders. Defaults to 0, which means that order
+	// events are sent immediately.
+	OrderEventConfirmationDepth int
+	// MaxConcurrentValidations is the maximum number of calls to
+	// ValidateAndStoreValidOrders which validate orders at the same time.
+	// Additional calls wait in the lane given by StoreOrdersOpts.Lane.
+	// Defaults to 4.
+	MaxConcurrentValidations int
+	// LocalValidationWeight and RemoteValidationWeight determine the share of
+	// validation slots given to each lane while both lanes have waiting
+	// validations. By default, local orders get 4 slots for every slot given to
+	// orders received from peers.
+	LocalValidationWeight  int
+	RemoteValidationWeight int
+	// RelayOnly makes the Watcher validate orders without making any calls to
+	// Ethereum. Only the properties of orders which can be checked offline,
+	// including their signatures, are validated and orders are expired
+	// according to the local clock. BlockWatcher is never subscribed to and
+	// OrderValidator may be nil. RevalidateAllOrders, Cleanup and
+	// BackfillOrderEvents must not be called in relay-only mode.
+	RelayOnly bool
+}
+
+// CustomEventHandler is called for every event emitted by a CustomContract.
+// It returns the orders which need to be re-validated because of the event.
+type CustomEventHandler func(log types.Log, event decoder.CustomEvent, meshDB *meshdb.MeshDB) ([]*meshdb.Order, error)
+
+// CustomContract is a contract which is not supported by Mesh out of the box,
+// but whose events can affect the fillability of orders.
+type CustomContract struct {
+	// Address is the address of the contract.
+	Address common.Address
+	// ABI is the JSON ABI of the contract. Only its events are used.
+	ABI string
+	// Handler is called for every event emitted by the contract which is
+	// found in ABI.
+	Handler CustomEventHandler
+}
+
+// CleanupStats contains information about the most recent cleanup.
+type CleanupStats struct {
+	// StartTime is when the cleanup started. It is the zero time if no cleanup
+	// has run yet.
+	StartTime time.Time
+	// Duration is how long the cleanup took.
+	Duration time.Duration
+	// NumOrdersChecked is the number of orders that were re-validated.
+	NumOrdersChecked int
+	// NumOrdersDeferred is the number of orders that needed to be re-validated
+	// but were left for the next cleanup because of CleanupMaxOrdersPerRun.
+	NumOrdersDeferred int
+	// NumOrderEvents is the number of order events emitted by the cleanup.
+	NumOrderEvents int
+	// Err is the error that caused the cleanup to fail, if any.
+	Err error
+}
+
+// ValidationStats contains cumulative information about the validation of new
+// orders via ValidateAndStoreValidOrders.
+type ValidationStats struct {
+	// NumValidations is the number of calls to ValidateAndStoreValidOrders.
+	NumValidations int
+	// TotalDuration is the total amount of time the calls took.
+	TotalDuration time.Duration
+}
+
+// RevalidationProgress contains information about the progress of the most
+// recent call to RevalidateAllOrders.
+type RevalidationProgress struct {
+	// StartTime is when the revalidation started. It is the zero time if no
+	// revalidation has been started.
+	StartTime time.Time
+	// EndTime is when the revalidation finished. It is the zero time if the
+	// revalidation has not finished yet.
+	EndTime time.Time
+	// NumOrdersTotal is the number of orders to be re-validated.
+	NumOrdersTotal int
+	// NumOrdersRevalidated is the number of orders re-validated so far.
+	NumOrdersRevalidated int
+	// Err is the error that caused the revalidation to fail, if any.
+	Err error
+}
+
+// InProgress returns true if the revalidation has been started but has not
+// finished yet.
+func (p RevalidationProgress) InProgress() bool {
+	return !p.StartTime.IsZero() && p.EndTime.IsZero()
 }
 
+// TransferSimulationMode determines how the results of simulating the transfer
+// of a new order's maker assets are used.
+type TransferSimulationMode string
+
+const (
+	// TransferSimulationOff disables transfer simulation.
+	TransferSimulationOff TransferSimulationMode = "off"
+	// TransferSimulationWarn stores orders for which the simulation failed, but
+	// flags them with TransferSimulationFailed.
+	TransferSimulationWarn TransferSimulationMode = "warn"
+	// TransferSimulationStrict rejects orders for which the simulation failed.
+	TransferSimulationStrict TransferSimulationMode = "strict"
+)
+
+// RestrictedOrderPolicy determines how orders which can only be filled by a
+// specific taker or submitted by a specific sender are handled. Such orders are
+// usually of no use to anyone else on the network.
+type RestrictedOrderPolicy string
+
+const (
+	// RestrictedOrdersAccept stores restricted orders like any other order.
+	RestrictedOrdersAccept RestrictedOrderPolicy = "accept"
+	// RestrictedOrdersLocal stores restricted orders, but they are not shared
+	// with peers. The Watcher itself treats it like RestrictedOrdersAccept.
+	RestrictedOrdersLocal RestrictedOrderPolicy = "local"
+	// RestrictedOrdersReject rejects restricted orders.
+	RestrictedOrdersReject RestrictedOrderPolicy = "reject"
+)
+
 // New instantiates a new order watcher
 func New(config Config) (*Watcher, error) {
 	decoder, err := decoder.New()
@@ -131,6 +363,94 @@ func New(config Config) (*Watcher, error) {
 		// MaxExpirationTime should never be in the past.
 		config.MaxExpirationTime = big.NewInt(time.Now().Unix())
 	}
+	if config.CleanupInterval < 0 {
+		return nil, errors.New("config.CleanupInterval cannot be negative")
+	} else if config.CleanupInterval == 0 {
+		config.CleanupInterval = defaultCleanupInterval
+	}
+	if config.CleanupJitter < 0 {
+		return nil, errors.New("config.CleanupJitter cannot be negative")
+	}
+	if config.CleanupMaxOrdersPerRun < 0 {
+		return nil, errors.New("config.CleanupMaxOrdersPerRun cannot be negative")
+	}
+	if config.CleanupLastUpdatedBuffer < 0 {
+		return nil, errors.New("config.CleanupLastUpdatedBuffer cannot be negative")
+	} else if config.CleanupLastUpdatedBuffer == 0 {
+		config.CleanupLastUpdatedBuffer = defaultLastUpdatedBuffer
+	}
+	if config.MaxOrderSizeInBytes < 0 {
+		return nil, errors.New("config.MaxOrderSizeInBytes cannot be negative")
+	} else if config.MaxOrderSizeInBytes > constants.MaxOrderSizeInBytes {
+		return nil, fmt.Errorf("config.MaxOrderSizeInBytes cannot exceed %d", constants.MaxOrderSizeInBytes)
+	} else if config.MaxOrderSizeInBytes == 0 {
+		config.MaxOrderSizeInBytes = constants.MaxOrderSizeInBytes
+	}
+	if config.AssetDataLimits.MaxSizeInBytes < 0 {
+		return nil, errors.New("config.AssetDataLimits.MaxSizeInBytes cannot be negative")
+	}
+	if config.AssetDataLimits.MaxMultiAssetNestingDepth < 0 {
+		return nil, errors.New("config.AssetDataLimits.MaxMultiAssetNestingDepth cannot be negative")
+	}
+	if config.OrderEventConfirmationDepth < 0 {
+		return nil, errors.New("config.OrderEventConfirmationDepth cannot be negative")
+	}
+	if config.MaxConcurrentValidations < 0 {
+		return nil, errors.New("config.MaxConcurrentValidations cannot be negative")
+	} else if config.MaxConcurrentValidations == 0 {
+		config.MaxConcurrentValidations = defaultMaxConcurrentValidations
+	}
+	if config.LocalValidationWeight < 0 {
+		return nil, errors.New("config.LocalValidationWeight cannot be negative")
+	} else if config.LocalValidationWeight == 0 {
+		config.LocalValidationWeight = defaultLocalValidationWeight
+	}
+	if config.RemoteValidationWeight < 0 {
+		return nil, errors.New("config.RemoteValidationWeight cannot be negative")
+	} else if config.RemoteValidationWeight == 0 {
+		config.RemoteValidationWeight = defaultRemoteValidationWeight
+	}
+	switch config.TransferSimulationMode {
+	case "":
+		config.TransferSimulationMode = TransferSimulationOff
+	case TransferSimulationOff, TransferSimulationWarn, TransferSimulationStrict:
+	default:
+		return nil, fmt.Errorf("invalid config.TransferSimulationMode: %q", config.TransferSimulationMode)
+	}
+	switch config.TakerRestrictedOrderPolicy {
+	case "":
+		config.TakerRestrictedOrderPolicy = RestrictedOrdersAccept
+	case RestrictedOrdersAccept, RestrictedOrdersLocal, RestrictedOrdersReject:
+	default:
+		return nil, fmt.Errorf("invalid config.TakerRestrictedOrderPolicy: %q", config.TakerRestrictedOrderPolicy)
+	}
+	switch config.SenderRestrictedOrderPolicy {
+	case "":
+		config.SenderRestrictedOrderPolicy = RestrictedOrdersReject
+	case RestrictedOrdersAccept, RestrictedOrdersLocal, RestrictedOrdersReject:
+	default:
+		return nil, fmt.Errorf("invalid config.SenderRestrictedOrderPolicy: %q", config.SenderRestrictedOrderPolicy)
+	}
+
+	if config.RelayOnly {
+		if config.TransferSimulationMode != TransferSimulationOff {
+			return nil, errors.New("config.TransferSimulationMode must be off in relay-only mode")
+		}
+		if config.OrderEventConfirmationDepth != 0 {
+			return nil, errors.New("config.OrderEventConfirmationDepth must be 0 in relay-only mode")
+		}
+	}
+
+	customEventHandlers := map[common.Address]CustomEventHandler{}
+	for _, contract := range config.CustomContracts {
+		if contract.Handler == nil {
+			return nil, fmt.Errorf("missing handler for custom contract %s", contract.Address.Hex())
+		}
+		if err := decoder.AddKnownCustomContract(contract.Address, contract.ABI); err != nil {
+			return nil, fmt.Errorf("invalid ABI for custom contract %s: %s", contract.Address.Hex(), err.Error())
+		}
+		customEventHandlers[contract.Address] = contract.Handler
+	}
 
 	// Configure a SlowCounter to be used for increasing max expiration time.
 	slowCounterConfig := slowcounter.Config{
@@ -145,20 +465,49 @@ func New(config Config) (*Watcher, error) {
 	}
 
 	w := &Watcher{
-		meshDB:                     config.MeshDB,
-		blockWatcher:               config.BlockWatcher,
-		expirationWatcher:          expirationwatch.New(),
-		contractAddressToSeenCount: map[common.Address]uint{},
-		orderValidator:             config.OrderValidator,
-		eventDecoder:               decoder,
-		assetDataDecoder:           assetDataDecoder,
-		contractAddresses:          config.ContractAddresses,
-		maxExpirationTime:          big.NewInt(0).Set(config.MaxExpirationTime),
-		maxExpirationCounter:       maxExpirationCounter,
-		maxOrders:                  config.MaxOrders,
-		blockEventsChan:            make(chan []*blockwatch.Event, 100),
-		atLeastOneBlockProcessed:   make(chan struct{}),
-		didProcessABlock:           false,
+		meshDB:                      config.MeshDB,
+		blockWatcher:                config.BlockWatcher,
+		expirationWatcher:           expirationwatch.New(),
+		contractAddressToSeenCount:  map[common.Address]uint{},
+		orderValidator:              config.OrderValidator,
+		eventDecoder:                decoder,
+		customEventHandlers:         customEventHandlers,
+		erc721Approvals:             newERC721ApprovalTracker(),
+		assetDataDecoder:            assetDataDecoder,
+		contractAddresses:           config.ContractAddresses,
+		maxExpirationTime:           big.NewInt(0).Set(config.MaxExpirationTime),
+		maxExpirationCounter:        maxExpirationCounter,
+		maxOrders:                   config.MaxOrders,
+		makerAllowlist:              newAddressSet(config.MakerAllowlist),
+		makerDenylist:               newAddressSet(config.MakerDenylist),
+		assetDenylist:               newAddressSet(config.AssetDenylist),
+		transferSimulationMode:      config.TransferSimulationMode,
+		takerRestrictedPolicy:       config.TakerRestrictedOrderPolicy,
+		senderRestrictedPolicy:      config.SenderRestrictedOrderPolicy,
+		replaceSupersededOrders:     config.ReplaceSupersededOrders,
+		dryRun:                      config.DryRun,
+		priceOracle:                 config.PriceOracle,
+		minOrderNotionalUSD:         config.MinOrderNotionalUSD,
+		blockEventsChan:             make(chan []*blockwatch.Event, 100),
+		atLeastOneBlockProcessed:    make(chan struct{}),
+		didProcessABlock:            false,
+		cleanupInterval:             config.CleanupInterval,
+		cleanupJitter:               config.CleanupJitter,
+		cleanupRand:                 rand.New(rand.NewSource(time.Now().UnixNano())),
+		cleanupMaxOrdersPerRun:      config.CleanupMaxOrdersPerRun,
+		cleanupLastUpdatedBuffer:    config.CleanupLastUpdatedBuffer,
+		maxOrderSizeInBytes:         config.MaxOrderSizeInBytes,
+		assetDataLimits:             config.AssetDataLimits,
+		orderEventConfirmationDepth: config.OrderEventConfirmationDepth,
+		relayOnly:                   config.RelayOnly,
+		recentBlockOrderEvents:      map[common.Hash]*blockOrderEvents{},
+		validationLanes:             newLaneScheduler(config.MaxConcurrentValidations, config.LocalValidationWeight, config.RemoteValidationWeight),
+	}
+	if err := w.loadLastSequenceNumber(); err != nil {
+		return nil, err
+	}
+	if err := w.loadPendingOrderEvents(); err != nil {
+		return nil, err
 	}
 
 	// Check if any orders need to be removed right away due to high expiration
@@ -167,7 +516,7 @@ func New(config Config) (*Watcher, error) {
 	if err != nil {
 		return nil, err
 	}
-	w.orderFeed.Send(orderEvents)
+	w.sendOrderEvents(orderEvents)
 
 	// Pre-populate the OrderWatcher with all orders already stored in the DB
 	orders := []*meshdb.Order{}
@@ -182,6 +531,12 @@ func New(config Config) (*Watcher, error) {
 		}
 	}
 
+	// The maker lists and asset denylist may have changed since the last time
+	// Mesh was run, so we remove any stored orders that are no longer allowed.
+	if _, err := w.removeDisallowedOrders(); err != nil {
+		return nil, err
+	}
+
 	return w, nil
 }
 
@@ -207,18 +562,27 @@ func (w *Watcher) Watch(ctx context.Context) error {
 
 	// Start four independent goroutines. The main loop, cleanup loop, removed orders
 	// checker and max expirationTime checker. Use four separate channels to communicate errors.
+	// In relay-only mode, there are no blocks to process and no orders to
+	// re-validate, so the main loop is replaced by the relay-only loop and the
+	// cleanup loop is not started.
 	mainLoopErrChan := make(chan error, 1)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
+		if w.relayOnly {
+			mainLoopErrChan <- w.relayOnlyLoop(innerCtx)
+			return
+		}
 		mainLoopErrChan <- w.mainLoop(innerCtx)
 	}()
 	cleanupLoopErrChan := make(chan error, 1)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		cleanupLoopErrChan <- w.cleanupLoop(innerCtx)
-	}()
+	if !w.relayOnly {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cleanupLoopErrChan <- w.cleanupLoop(innerCtx)
+		}()
+	}
 	maxExpirationTimeLoopErrChan := make(chan error, 1)
 	wg.Add(1)
 	go func() {
@@ -316,20 +680,46 @@ func (w *Watcher) cleanupLoop(ctx context.Context) error {
 		select {
 		case <-ctx.Done():
 			return nil
-		case <-time.After(minCleanupInterval - time.Since(start)):
-			// Wait minCleanupInterval before calling cleanup again. Since
-			// we only start sleeping _after_ cleanup completes, we will never
-			// have multiple calls to cleanup running in parallel
+		case <-time.After(w.nextCleanupDelay(start)):
+			// Wait cleanupInterval (plus jitter) before calling cleanup again.
+			// Since we only start sleeping _after_ cleanup completes, we will
+			// never have multiple calls to cleanup running in parallel
 			break
 		}
 
 		start = time.Now()
-		if err := w.Cleanup(ctx, defaultLastUpdatedBuffer); err != nil {
+		if err := w.cleanup(ctx, w.cleanupLastUpdatedBuffer, w.cleanupMaxOrdersPerRun); err != nil {
 			return err
 		}
 	}
 }
 
+// nextCleanupDelay returns how long to wait before running the next periodic
+// cleanup, given that the previous one started at start.
+func (w *Watcher) nextCleanupDelay(start time.Time) time.Duration {
+	delay := w.cleanupInterval - time.Since(start)
+	if w.cleanupJitter > 0 {
+		// Only accessed by cleanupLoop, so no locking is needed.
+		delay += time.Duration(w.cleanupRand.Int63n(int64(w.cleanupJitter)))
+	}
+	return delay
+}
+
+// ValidationStats returns cumulative information about the validation of new
+// orders since the Watcher was created.
+func (w *Watcher) ValidationStats() ValidationStats {
+	w.validationStatsMu.Lock()
+	defer w.validationStatsMu.Unlock()
+	return w.validationStats
+}
+
+// LastCleanupStats returns information about the most recent cleanup.
+func (w *Watcher) LastCleanupStats() CleanupStats {
+	w.lastCleanupStatsMu.RLock()
+	defer w.lastCleanupStatsMu.RUnlock()
+	return w.lastCleanupStats
+}
+
 func (w *Watcher) maxExpirationTimeLoop(ctx context.Context) error {
 	ticker := time.NewTicker(maxExpirationTimeCheckInterval)
 	for {
@@ -401,6 +791,7 @@ func (w *Watcher) handleOrderExpirations(ordersColTxn *db.Transaction, latestBlo
 				SignedOrder:              order.SignedOrder,
 				FillableTakerAssetAmount: big.NewInt(0),
 				EndState:                 zeroex.ESOrderExpired,
+				Metadata:                 order.Metadata,
 			}
 			orderEvents = append(orderEvents, orderEvent)
 		}
@@ -431,6 +822,7 @@ func (w *Watcher) handleOrderExpirations(ordersColTxn *db.Transaction, latestBlo
 					SignedOrder:              order.SignedOrder,
 					FillableTakerAssetAmount: order.FillableTakerAssetAmount,
 					EndState:                 zeroex.ESOrderUnexpired,
+					Metadata:                 order.Metadata,
 				}
 				orderEvents = append(orderEvents, orderEvent)
 			}
@@ -472,7 +864,8 @@ func (w *Watcher) handleBlockEvents(
 	if previousLatestBlock != nil {
 		previousLatestBlockTimestamp = previousLatestBlock.Timestamp
 	}
-	latestBlockNumber, latestBlockTimestamp := w.getBlockchainState(events)
+	latestBlock := w.getLatestBlockHeader(events)
+	latestBlockTimestamp := latestBlock.Timestamp
 
 	err = updateBlockHeadersStoredInDB(miniHeadersColTxn, events)
 	if err != nil {
@@ -481,6 +874,8 @@ func (w *Watcher) handleBlockEvents(
 
 	orderHashToDBOrder := map[common.Hash]*meshdb.Order{}
 	orderHashToEvents := map[common.Hash][]*zeroex.ContractEvent{}
+	contractEvents := []*zeroex.ContractEvent{}
+	numLogsDecodedByKind := map[string]int{}
 	for _, event := range events {
 		for _, log := range event.BlockHeader.Logs {
 			eventType, err := w.eventDecoder.FindEventType(log)
@@ -506,7 +901,9 @@ func (w *Watcher) handleBlockEvents(
 				TxHash:    log.TxHash,
 				TxIndex:   log.TxIndex,
 				LogIndex:  log.Index,
-				IsRemoved: log.Removed,
+				// Logs of blocks removed by a re-org are not flagged by the block
+				// watcher, so we use the type of the block event instead.
+				IsRemoved: log.Removed || event.Type == blockwatch.Removed,
 				Address:   log.Address,
 				Kind:      eventType,
 			}
@@ -562,6 +959,8 @@ func (w *Watcher) handleBlockEvents(
 					return err
 				}
 				contractEvent.Parameters = transferEvent
+				// Transfers reset the approved address of the token.
+				w.erc721Approvals.clearTokenApproval(log.Address, transferEvent.TokenId, contractEvent.IsRemoved)
 				fromOrders, err := w.findOrdersByTokenAddressAndTokenID(transferEvent.From, log.Address, transferEvent.TokenId)
 				if err != nil {
 					return err
@@ -583,9 +982,14 @@ func (w *Watcher) handleBlockEvents(
 					return err
 				}
 				contractEvent.Parameters = approvalEvent
-				orders, err = w.findOrdersByTokenAddressAndTokenID(approvalEvent.Owner, log.Address, approvalEvent.TokenId)
-				if err != nil {
-					return err
+				// Only approvals that grant or revoke the AssetProxy's approval for
+				// this token can change the fillability of orders.
+				proxyApproved := approvalEvent.Approved == w.contractAddresses.ERC721Proxy
+				if w.erc721Approvals.setTokenApproval(log.Address, approvalEvent.Owner, approvalEvent.TokenId, proxyApproved, contractEvent.IsRemoved) {
+					orders, err = w.findOrdersByTokenAddressAndTokenID(approvalEvent.Owner, log.Address, approvalEvent.TokenId)
+					if err != nil {
+						return err
+					}
 				}
 
 			case "ERC721ApprovalForAllEvent":
@@ -602,9 +1006,11 @@ func (w *Watcher) handleBlockEvents(
 					continue
 				}
 				contractEvent.Parameters = approvalForAllEvent
-				orders, err = w.findOrdersByTokenAddressAndTokenID(approvalForAllEvent.Owner, log.Address, nil)
-				if err != nil {
-					return err
+				if w.erc721Approvals.setApprovalForAll(log.Address, approvalForAllEvent.Owner, approvalForAllEvent.Approved, contractEvent.IsRemoved) {
+					orders, err = w.findOrdersByTokenAddressAndTokenID(approvalForAllEvent.Owner, log.Address, nil)
+					if err != nil {
+						return err
+					}
 				}
 
 			case "ERC1155TransferSingleEvent":
@@ -746,7 +1152,8 @@ func (w *Watcher) handleBlockEvents(
 					return err
 				}
 				contractEvent.Parameters = exchangeCancelUpToEvent
-				cancelledOrders, err := w.meshDB.FindOrdersByMakerAddressAndMaxSalt(exchangeCancelUpToEvent.MakerAddress, exchangeCancelUpToEvent.OrderEpoch)
+				w.updateCancelEpoch(exchangeCancelUpToEvent, contractEvent.IsRemoved)
+				cancelledOrders, err := w.meshDB.FindOrdersCancelledByEpoch(exchangeCancelUpToEvent.MakerAddress, exchangeCancelUpToEvent.OrderSenderAddress, exchangeCancelUpToEvent.OrderEpoch)
 				if err != nil {
 					logger.WithFields(logger.Fields{
 						"error": err.Error(),
@@ -755,6 +1162,110 @@ func (w *Watcher) handleBlockEvents(
 				}
 				orders = append(orders, cancelledOrders...)
 
+			case "ExchangeTransactionExecutionEvent":
+				var transactionExecutionEvent decoder.ExchangeTransactionExecutionEvent
+				err = w.eventDecoder.Decode(log, &transactionExecutionEvent)
+				if err != nil {
+					if isNonCritical := w.checkDecodeErr(err, eventType); isNonCritical {
+						continue
+					}
+					return err
+				}
+				contractEvent.Parameters = transactionExecutionEvent
+				// Orders filled or cancelled via a meta-transaction emit their own
+				// Fill and Cancel events in the same transaction, so no orders need
+				// to be re-validated here.
+
+			case "ExchangeSignatureValidatorApprovalEvent":
+				var signatureValidatorApprovalEvent decoder.ExchangeSignatureValidatorApprovalEvent
+				err = w.eventDecoder.Decode(log, &signatureValidatorApprovalEvent)
+				if err != nil {
+					if isNonCritical := w.checkDecodeErr(err, eventType); isNonCritical {
+						continue
+					}
+					return err
+				}
+				contractEvent.Parameters = signatureValidatorApprovalEvent
+				// Orders signed with the Validator signature type become invalid (or
+				// valid again) when the maker revokes (or grants) approval.
+				signerOrders, err := w.meshDB.FindOrdersByMakerAddress(signatureValidatorApprovalEvent.SignerAddress)
+				if err != nil {
+					logger.WithFields(logger.Fields{
+						"error": err.Error(),
+					}).Error("unexpected query error encountered")
+					return err
+				}
+				orders = append(orders, signerOrders...)
+
+			case "ExchangeAssetProxyRegisteredEvent":
+				var assetProxyRegisteredEvent decoder.ExchangeAssetProxyRegisteredEvent
+				err = w.eventDecoder.Decode(log, &assetProxyRegisteredEvent)
+				if err != nil {
+					if isNonCritical := w.checkDecodeErr(err, eventType); isNonCritical {
+						continue
+					}
+					return err
+				}
+				contractEvent.Parameters = assetProxyRegisteredEvent
+				// Stored orders only use asset proxies which were already
+				// registered, so they are not affected.
+
+			case "ExchangeProtocolFeeMultiplierEvent", "ExchangeProtocolFeeCollectorAddressEvent":
+				if eventType == "ExchangeProtocolFeeMultiplierEvent" {
+					var protocolFeeMultiplierEvent decoder.ExchangeProtocolFeeMultiplierEvent
+					err = w.eventDecoder.Decode(log, &protocolFeeMultiplierEvent)
+					contractEvent.Parameters = protocolFeeMultiplierEvent
+				} else {
+					var protocolFeeCollectorAddressEvent decoder.ExchangeProtocolFeeCollectorAddressEvent
+					err = w.eventDecoder.Decode(log, &protocolFeeCollectorAddressEvent)
+					contractEvent.Parameters = protocolFeeCollectorAddressEvent
+				}
+				if err != nil {
+					if isNonCritical := w.checkDecodeErr(err, eventType); isNonCritical {
+						continue
+					}
+					return err
+				}
+				// Changes to the protocol fee can affect the fillability of any order,
+				// so all stored orders are re-validated. These events are very rare.
+				logger.WithFields(logger.Fields{
+					"eventType": eventType,
+					"txHash":    log.TxHash.Hex(),
+				}).Info("protocol fee changed; re-validating all orders")
+				allOrders, err := w.meshDB.FindAllOrdersSortedByExpirationTime()
+				if err != nil {
+					logger.WithFields(logger.Fields{
+						"error": err.Error(),
+					}).Error("unexpected query error encountered")
+					return err
+				}
+				orders = append(orders, allOrders...)
+
+			case "CustomEvent":
+				var customEvent decoder.CustomEvent
+				err = w.eventDecoder.Decode(log, &customEvent)
+				if err != nil {
+					if isNonCritical := w.checkDecodeErr(err, eventType); isNonCritical {
+						continue
+					}
+					return err
+				}
+				contractEvent.Parameters = customEvent
+				handler := w.customEventHandlers[log.Address]
+				customOrders, err := handler(log, customEvent, w.meshDB)
+				if err != nil {
+					// A failing handler shouldn't prevent the rest of the block
+					// from being processed.
+					logger.WithFields(logger.Fields{
+						"error":           err.Error(),
+						"eventName":       customEvent.Name,
+						"contractAddress": log.Address.Hex(),
+						"txHash":          log.TxHash.Hex(),
+					}).Error("custom event handler failed")
+					continue
+				}
+				orders = append(orders, customOrders...)
+
 			default:
 				logger.WithFields(logger.Fields{
 					"eventType": eventType,
@@ -762,6 +1273,8 @@ func (w *Watcher) handleBlockEvents(
 				}).Error("unknown eventType encountered")
 				return err
 			}
+			contractEvents = append(contractEvents, contractEvent)
+			numLogsDecodedByKind[eventType]++
 			for _, order := range orders {
 				orderHashToDBOrder[order.Hash] = order
 				if _, ok := orderHashToEvents[order.Hash]; !ok {
@@ -781,27 +1294,32 @@ func (w *Watcher) handleBlockEvents(
 	// This timeout of 1min is for limiting how long this call should block at the ETH RPC rate limiter
 	ctx, done := context.WithTimeout(ctx, 1*time.Minute)
 	defer done()
-	postValidationOrderEvents, err := w.generateOrderEventsIfChanged(ctx, ordersColTxn, orderHashToDBOrder, orderHashToEvents, latestBlockNumber, latestBlockTimestamp)
+	ctx, validationCalls := ordervalidator.WithCallCounter(ctx)
+	numOrdersRevalidated := len(orderHashToDBOrder)
+	postValidationOrderEvents, err := w.generateOrderEventsIfChanged(ctx, ordersColTxn, orderHashToDBOrder, orderHashToEvents, latestBlock)
 	if err != nil {
 		return err
 	}
 
-	if err := ordersColTxn.Commit(); err != nil {
+	// The stored MiniHeaders are the checkpoint from which the block watcher
+	// resumes after a restart, so they are committed atomically with the
+	// updated orders. Otherwise, a crash between the two commits would cause
+	// the events in these blocks to be processed again and duplicate order
+	// events to be emitted.
+	if err := db.CommitTransactions(ordersColTxn, miniHeadersColTxn); err != nil {
 		logger.WithFields(logger.Fields{
 			"error": err.Error(),
-		}).Error("Failed to commit orders collection transaction")
-		return err
-	}
-	if err := miniHeadersColTxn.Commit(); err != nil {
-		logger.WithFields(logger.Fields{
-			"error": err.Error(),
-		}).Error("Failed to commit miniheaders collection transaction")
+		}).Error("Failed to commit orders and miniheaders collection transactions")
 		return err
 	}
+	w.chainStats.recordBlockEvents(events, latestBlock.Number, numLogsDecodedByKind, numOrdersRevalidated, validationCalls.Count())
 
 	orderEvents := append(expirationOrderEvents, postValidationOrderEvents...)
-	if len(orderEvents) > 0 {
-		w.orderFeed.Send(orderEvents)
+	w.linkSupersededOrderEvents(events, latestBlock, orderEvents)
+	orderEvents = w.confirmOrderEvents(events, latestBlock, orderEvents)
+	w.sendOrderEvents(orderEvents)
+	if len(contractEvents) > 0 {
+		w.contractEventFeed.Send(contractEvents)
 	}
 
 	w.atLeastOneBlockProcessedMu.Lock()
@@ -824,6 +1342,22 @@ func (w *Watcher) handleBlockEvents(
 // Cleanup re-validates all orders in DB which haven't been re-validated in
 // `lastUpdatedBuffer` time to make sure all orders are still up-to-date
 func (w *Watcher) Cleanup(ctx context.Context, lastUpdatedBuffer time.Duration) error {
+	return w.cleanup(ctx, lastUpdatedBuffer, 0)
+}
+
+// cleanup re-validates up to maxOrders orders in DB which haven't been
+// re-validated in `lastUpdatedBuffer` time, least recently updated first. If
+// maxOrders is 0, all such orders are re-validated.
+func (w *Watcher) cleanup(ctx context.Context, lastUpdatedBuffer time.Duration, maxOrders int) (err error) {
+	stats := CleanupStats{StartTime: time.Now()}
+	defer func() {
+		stats.Duration = time.Since(stats.StartTime)
+		stats.Err = err
+		w.lastCleanupStatsMu.Lock()
+		w.lastCleanupStats = stats
+		w.lastCleanupStatsMu.Unlock()
+	}()
+
 	// Pause block event processing until we finished cleaning up at current block height
 	w.handleBlockEventsMu.RLock()
 	defer w.handleBlockEventsMu.RUnlock()
@@ -833,7 +1367,7 @@ func (w *Watcher) Cleanup(ctx context.Context, lastUpdatedBuffer time.Duration)
 		_ = ordersColTxn.Discard()
 	}()
 	lastUpdatedCutOff := time.Now().Add(-lastUpdatedBuffer)
-	orders, err := w.meshDB.FindOrdersLastUpdatedBefore(lastUpdatedCutOff)
+	orders, err := w.meshDB.FindOrdersLastUpdatedBefore(lastUpdatedCutOff, maxOrders)
 	if err != nil {
 		logger.WithFields(logger.Fields{
 			"error":             err.Error(),
@@ -841,6 +1375,18 @@ func (w *Watcher) Cleanup(ctx context.Context, lastUpdatedBuffer time.Duration)
 		}).Error("Failed to find orders by LastUpdatedBefore")
 		return err
 	}
+	if maxOrders != 0 && len(orders) == maxOrders {
+		numStaleOrders, err := w.meshDB.CountOrdersLastUpdatedBefore(lastUpdatedCutOff)
+		if err != nil {
+			return err
+		}
+		stats.NumOrdersDeferred = numStaleOrders - len(orders)
+	}
+
+	latestBlock, err := w.meshDB.FindLatestMiniHeader()
+	if err != nil {
+		return err
+	}
 	orderHashToDBOrder := map[common.Hash]*meshdb.Order{}
 	orderHashToEvents := map[common.Hash][]*zeroex.ContractEvent{} // No events when running cleanup job
 	for _, order := range orders {
@@ -849,21 +1395,41 @@ func (w *Watcher) Cleanup(ctx context.Context, lastUpdatedBuffer time.Duration)
 			return nil
 		default:
 		}
+		if order.WasValidatedAt(latestBlock) || order.IsReplaced() {
+			// The chain hasn't advanced since the order was last validated
+			// (e.g. because Mesh was restarted) or the order was replaced, so
+			// revalidating it would not change anything.
+			if maxOrders != 0 {
+				w.updateOrderDBEntry(ordersColTxn, order)
+			}
+			continue
+		}
 		orderHashToDBOrder[order.Hash] = order
 		orderHashToEvents[order.Hash] = []*zeroex.ContractEvent{}
 	}
-
-	latestBlock, err := w.meshDB.FindLatestMiniHeader()
-	if err != nil {
-		return err
-	}
+	stats.NumOrdersChecked = len(orderHashToDBOrder)
 	// This timeout of 30min is for limiting how long this call should block at the ETH RPC rate limiter
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
 	defer cancel()
-	orderEvents, err := w.generateOrderEventsIfChanged(ctx, ordersColTxn, orderHashToDBOrder, orderHashToEvents, latestBlock.Number, latestBlock.Timestamp)
+	orderEvents, err := w.generateOrderEventsIfChanged(ctx, ordersColTxn, orderHashToDBOrder, orderHashToEvents, latestBlock)
 	if err != nil {
 		return err
 	}
+	if maxOrders != 0 {
+		// Orders whose state didn't change are not updated by
+		// generateOrderEventsIfChanged. Mark them as updated so that the next
+		// cleanup moves on to other orders instead of checking the same ones
+		// again.
+		orderHashesWithEvents := map[common.Hash]struct{}{}
+		for _, orderEvent := range orderEvents {
+			orderHashesWithEvents[orderEvent.OrderHash] = struct{}{}
+		}
+		for orderHash, order := range orderHashToDBOrder {
+			if _, found := orderHashesWithEvents[orderHash]; !found && !order.IsRemoved {
+				w.updateOrderDBEntry(ordersColTxn, order)
+			}
+		}
+	}
 
 	if err := ordersColTxn.Commit(); err != nil {
 		logger.WithFields(logger.Fields{
@@ -871,10 +1437,130 @@ func (w *Watcher) Cleanup(ctx context.Context, lastUpdatedBuffer time.Duration)
 		}).Error("Failed to commit orders collection transaction")
 	}
 
-	if len(orderEvents) > 0 {
-		w.orderFeed.Send(orderEvents)
+	stats.NumOrderEvents = len(orderEvents)
+	w.sendOrderEvents(orderEvents)
+
+	return nil
+}
+
+// RevalidateAllOrders re-validates all stored orders, soonest-expiring first.
+// Orders are re-validated in batches of batchSize with a delay of batchDelay
+// between batches in order to avoid overwhelming the Ethereum RPC endpoint. If
+// batchSize is 0, all orders are re-validated in a single batch. Progress can
+// be monitored via RevalidationProgress. It blocks until all orders have been
+// re-validated or the given context is canceled, in which case it returns the
+// error of the context.
+func (w *Watcher) RevalidateAllOrders(ctx context.Context, batchSize int, batchDelay time.Duration) (err error) {
+	if batchSize < 0 {
+		return errors.New("batchSize cannot be negative")
+	}
+	progress := RevalidationProgress{StartTime: time.Now()}
+	w.setRevalidationProgress(progress)
+	defer func() {
+		progress.EndTime = time.Now()
+		progress.Err = err
+		w.setRevalidationProgress(progress)
+	}()
+
+	orders, err := w.meshDB.FindAllOrdersSortedByExpirationTime()
+	if err != nil {
+		return err
 	}
+	// Only the hashes are kept because the orders might be updated by block
+	// events before their batch is re-validated.
+	orderHashes := make([]common.Hash, len(orders))
+	for i, order := range orders {
+		orderHashes[i] = order.Hash
+	}
+	progress.NumOrdersTotal = len(orderHashes)
+	w.setRevalidationProgress(progress)
 
+	if batchSize == 0 {
+		batchSize = len(orderHashes)
+	}
+	for start := 0; start < len(orderHashes); start += batchSize {
+		if start > 0 && batchDelay > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(batchDelay):
+			}
+		}
+		end := start + batchSize
+		if end > len(orderHashes) {
+			end = len(orderHashes)
+		}
+		if err := w.revalidateOrders(ctx, orderHashes[start:end]); err != nil {
+			return err
+		}
+		progress.NumOrdersRevalidated = end
+		w.setRevalidationProgress(progress)
+		logger.WithFields(logger.Fields{
+			"numOrdersRevalidated": progress.NumOrdersRevalidated,
+			"numOrdersTotal":       progress.NumOrdersTotal,
+		}).Debug("re-validated batch of orders")
+	}
+	return nil
+}
+
+// RevalidationProgress returns information about the progress of the most
+// recent call to RevalidateAllOrders.
+func (w *Watcher) RevalidationProgress() RevalidationProgress {
+	w.revalidationProgressMu.RLock()
+	defer w.revalidationProgressMu.RUnlock()
+	return w.revalidationProgress
+}
+
+func (w *Watcher) setRevalidationProgress(progress RevalidationProgress) {
+	w.revalidationProgressMu.Lock()
+	defer w.revalidationProgressMu.Unlock()
+	w.revalidationProgress = progress
+}
+
+// revalidateOrders re-validates the orders with the given hashes at the latest
+// block and emits order events for any orders whose state changed.
+func (w *Watcher) revalidateOrders(ctx context.Context, orderHashes []common.Hash) error {
+	// Pause block event processing until we finished re-validating at current
+	// block height
+	w.handleBlockEventsMu.RLock()
+	defer w.handleBlockEventsMu.RUnlock()
+
+	ordersColTxn := w.meshDB.Orders.OpenTransaction()
+	defer func() {
+		_ = ordersColTxn.Discard()
+	}()
+	latestBlock, err := w.meshDB.FindLatestMiniHeader()
+	if err != nil {
+		return err
+	}
+	orderHashToDBOrder := map[common.Hash]*meshdb.Order{}
+	orderHashToEvents := map[common.Hash][]*zeroex.ContractEvent{} // No events when re-validating
+	for _, orderHash := range orderHashes {
+		order := &meshdb.Order{}
+		if err := w.meshDB.Orders.FindByID(orderHash.Bytes(), order); err != nil {
+			if _, ok := err.(db.NotFoundError); ok {
+				// The order was permanently deleted in the meantime.
+				continue
+			}
+			return err
+		}
+		if order.WasValidatedAt(latestBlock) {
+			continue
+		}
+		orderHashToDBOrder[orderHash] = order
+		orderHashToEvents[orderHash] = []*zeroex.ContractEvent{}
+	}
+
+	orderEvents, err := w.generateOrderEventsIfChanged(ctx, ordersColTxn, orderHashToDBOrder, orderHashToEvents, latestBlock)
+	if err != nil {
+		return err
+	}
+	if err := ordersColTxn.Commit(); err != nil {
+		logger.WithFields(logger.Fields{
+			"error": err.Error(),
+		}).Error("Failed to commit orders collection transaction")
+	}
+	w.sendOrderEvents(orderEvents)
 	return nil
 }
 
@@ -884,7 +1570,13 @@ func (w *Watcher) permanentlyDeleteStaleRemovedOrders(ctx context.Context) error
 		return err
 	}
 
+	now := time.Now()
 	for _, order := range removedOrders {
+		if order.IsReplaced() && now.Unix() < order.SignedOrder.ExpirationTimeSeconds.Int64() {
+			// Replaced orders are kept until they expire so that they can't be
+			// added again.
+			continue
+		}
 		if time.Since(order.LastUpdated) > permanentlyDeleteAfter {
 			if err := w.permanentlyDeleteOrder(w.meshDB.Orders, order); err != nil {
 				return err
@@ -897,11 +1589,12 @@ func (w *Watcher) permanentlyDeleteStaleRemovedOrders(ctx context.Context) error
 }
 
 // add adds a 0x order to the DB and watches it for changes in fillability. It
-// will no-op (and return nil) if the order has already been added. If pinned is
-// true, the orders will be marked as pinned. Pinned orders will not be affected
-// by any DDoS prevention or incentive mechanisms and will always stay in
-// storage until they are no longer fillable.
-func (w *Watcher) add(orderInfos []*ordervalidator.AcceptedOrderInfo, validationBlockNumber *big.Int, pinned bool) ([]*zeroex.OrderEvent, error) {
+// will no-op (and return nil) if the order has already been added. If
+// opts.Pinned is true, the orders will be marked as pinned. Pinned orders will
+// not be affected by any DDoS prevention or incentive mechanisms and will always
+// stay in storage until they are no longer fillable. The metadata and
+// provenance in opts are stored along with the orders.
+func (w *Watcher) add(orderInfos []*ordervalidator.AcceptedOrderInfo, validationBlock *miniheader.MiniHeader, opts StoreOrdersOpts) ([]*zeroex.OrderEvent, error) {
 	orderEvents, err := w.decreaseMaxExpirationTimeIfNeeded()
 	if err != nil {
 		return orderEvents, err
@@ -925,11 +1618,19 @@ func (w *Watcher) add(orderInfos []*ordervalidator.AcceptedOrderInfo, validation
 			LastUpdated:              now,
 			FillableTakerAssetAmount: orderInfo.FillableTakerAssetAmount,
 			IsRemoved:                false,
-			IsPinned:                 pinned,
+			IsPinned:                 opts.Pinned,
+			TransferSimulationFailed: orderInfo.TransferSimulationFailed,
+			LastValidatedBlockNumber: validationBlock.Number,
+			LastValidatedBlockHash:   validationBlock.Hash,
+			LastValidationResult:     meshdb.ValidationResultFillable,
+			Metadata:                 opts.Metadata[orderInfo.OrderHash],
+			Source:                   opts.Source,
+			SourcePeerID:             opts.SourcePeerIDs[orderInfo.OrderHash],
+			ReceivedAt:               now,
 		}
 		// Final expiration time check before inserting the order. We might have just
 		// changed max expiration time above.
-		if !pinned && orderInfo.SignedOrder.ExpirationTimeSeconds.Cmp(w.maxExpirationTime) == 1 {
+		if !opts.Pinned && orderInfo.SignedOrder.ExpirationTimeSeconds.Cmp(w.maxExpirationTime) == 1 {
 			// HACK(albrow): This is technically not the ideal way to respond to this
 			// situation, but it is a lot easier to implement for the time being. In the
 			// future, we should return an error and then react to that error
@@ -949,6 +1650,7 @@ func (w *Watcher) add(orderInfos []*ordervalidator.AcceptedOrderInfo, validation
 				SignedOrder:              orderInfo.SignedOrder,
 				FillableTakerAssetAmount: orderInfo.FillableTakerAssetAmount,
 				EndState:                 zeroex.ESOrderAdded,
+				Metadata:                 opts.Metadata[orderInfo.OrderHash],
 			}
 			orderEvents = append(orderEvents, addedEvent)
 			stoppedWatchingEvent := &zeroex.OrderEvent{
@@ -957,6 +1659,7 @@ func (w *Watcher) add(orderInfos []*ordervalidator.AcceptedOrderInfo, validation
 				SignedOrder:              orderInfo.SignedOrder,
 				FillableTakerAssetAmount: orderInfo.FillableTakerAssetAmount,
 				EndState:                 zeroex.ESStoppedWatching,
+				Metadata:                 opts.Metadata[orderInfo.OrderHash],
 			}
 			orderEvents = append(orderEvents, stoppedWatchingEvent)
 		} else {
@@ -995,6 +1698,7 @@ func (w *Watcher) add(orderInfos []*ordervalidator.AcceptedOrderInfo, validation
 			SignedOrder:              orderInfo.SignedOrder,
 			FillableTakerAssetAmount: orderInfo.FillableTakerAssetAmount,
 			EndState:                 zeroex.ESOrderAdded,
+			Metadata:                 opts.Metadata[orderInfo.OrderHash],
 		}
 		orderEvents = append(orderEvents, addedOrderEvent)
 	}
@@ -1006,10 +1710,21 @@ func (w *Watcher) trimOrdersAndGenerateEvents() ([]*zeroex.OrderEvent, error) {
 	orderEvents := []*zeroex.OrderEvent{}
 
 	targetMaxOrders := int(maxOrdersTrimRatio * float64(w.maxOrders))
-	newMaxExpirationTime, removedOrders, err := w.meshDB.TrimOrdersByExpirationTime(targetMaxOrders)
+	removedOrders := []*meshdb.Order{}
+	if w.priceOracle != nil {
+		// Remove the least valuable orders first. Any remaining orders over the
+		// target are removed by expiration time below.
+		lowValueOrders, err := w.trimLowValueOrders(targetMaxOrders)
+		if err != nil {
+			return orderEvents, err
+		}
+		removedOrders = append(removedOrders, lowValueOrders...)
+	}
+	newMaxExpirationTime, expirationTrimmedOrders, err := w.meshDB.TrimOrdersByExpirationTime(targetMaxOrders)
 	if err != nil {
 		return orderEvents, err
 	}
+	removedOrders = append(removedOrders, expirationTrimmedOrders...)
 	if len(removedOrders) > 0 {
 		logger.WithFields(logger.Fields{
 			"numOrdersRemoved": len(removedOrders),
@@ -1025,6 +1740,7 @@ func (w *Watcher) trimOrdersAndGenerateEvents() ([]*zeroex.OrderEvent, error) {
 			SignedOrder:              removedOrder.SignedOrder,
 			FillableTakerAssetAmount: removedOrder.FillableTakerAssetAmount,
 			EndState:                 zeroex.ESStoppedWatching,
+			Metadata:                 removedOrder.Metadata,
 		}
 		orderEvents = append(orderEvents, orderEvent)
 
@@ -1152,6 +1868,15 @@ func (w *Watcher) Subscribe(sink chan<- []*zeroex.OrderEvent) event.Subscription
 	return w.orderScope.Track(w.orderFeed.Subscribe(sink))
 }
 
+// SubscribeToContractEvents allows one to subscribe to all the contract events
+// decoded by the OrderWatcher, including those which don't affect any stored
+// orders. Events are sent once the blocks containing them have been processed.
+// The sink channel should have ample buffer space to avoid blocking other
+// subscribers. Slow subscribers are not dropped.
+func (w *Watcher) SubscribeToContractEvents(sink chan<- []*zeroex.ContractEvent) event.Subscription {
+	return w.contractEventScope.Track(w.contractEventFeed.Subscribe(sink))
+}
+
 func (w *Watcher) findOrder(orderHash common.Hash) *meshdb.Order {
 	order := meshdb.Order{}
 	err := w.meshDB.Orders.FindByID(orderHash.Bytes(), &order)
@@ -1225,6 +1950,7 @@ func (w *Watcher) convertValidationResultsIntoOrderEvents(
 				FillableTakerAssetAmount: acceptedOrderInfo.FillableTakerAssetAmount,
 				EndState:                 zeroex.ESOrderAdded,
 				ContractEvents:           orderHashToEvents[order.Hash],
+				Metadata:                 order.Metadata,
 			}
 			orderEvents = append(orderEvents, orderEvent)
 		} else {
@@ -1240,6 +1966,7 @@ func (w *Watcher) convertValidationResultsIntoOrderEvents(
 						SignedOrder:              order.SignedOrder,
 						FillableTakerAssetAmount: order.FillableTakerAssetAmount,
 						EndState:                 zeroex.ESOrderUnexpired,
+						Metadata:                 order.Metadata,
 					}
 					orderEvents = append(orderEvents, orderEvent)
 				}
@@ -1256,6 +1983,7 @@ func (w *Watcher) convertValidationResultsIntoOrderEvents(
 						SignedOrder:              order.SignedOrder,
 						FillableTakerAssetAmount: order.FillableTakerAssetAmount,
 						EndState:                 zeroex.ESOrderUnexpired,
+						Metadata:                 order.Metadata,
 					}
 					orderEvents = append(orderEvents, orderEvent)
 				} else {
@@ -1270,6 +1998,7 @@ func (w *Watcher) convertValidationResultsIntoOrderEvents(
 					EndState:                 zeroex.ESOrderFilled,
 					FillableTakerAssetAmount: acceptedOrderInfo.FillableTakerAssetAmount,
 					ContractEvents:           orderHashToEvents[order.Hash],
+					Metadata:                 order.Metadata,
 				}
 				orderEvents = append(orderEvents, orderEvent)
 			} else if oldFillableAmount.Cmp(big.NewInt(0)) == 1 && !oldAmountIsMoreThenNewAmount {
@@ -1283,6 +2012,7 @@ func (w *Watcher) convertValidationResultsIntoOrderEvents(
 						SignedOrder:              order.SignedOrder,
 						FillableTakerAssetAmount: order.FillableTakerAssetAmount,
 						EndState:                 zeroex.ESOrderUnexpired,
+						Metadata:                 order.Metadata,
 					}
 					orderEvents = append(orderEvents, orderEvent)
 				} else {
@@ -1296,6 +2026,7 @@ func (w *Watcher) convertValidationResultsIntoOrderEvents(
 					EndState:                 zeroex.ESOrderFillabilityIncreased,
 					FillableTakerAssetAmount: acceptedOrderInfo.FillableTakerAssetAmount,
 					ContractEvents:           orderHashToEvents[order.Hash],
+					Metadata:                 order.Metadata,
 				}
 				orderEvents = append(orderEvents, orderEvent)
 			}
@@ -1334,6 +2065,7 @@ func (w *Watcher) convertValidationResultsIntoOrderEvents(
 					FillableTakerAssetAmount: big.NewInt(0),
 					EndState:                 endState,
 					ContractEvents:           orderHashToEvents[order.Hash],
+					Metadata:                 order.Metadata,
 				}
 				orderEvents = append(orderEvents, orderEvent)
 			}
@@ -1352,11 +2084,15 @@ func (w *Watcher) generateOrderEventsIfChanged(
 	ordersColTxn *db.Transaction,
 	orderHashToDBOrder map[common.Hash]*meshdb.Order,
 	orderHashToEvents map[common.Hash][]*zeroex.ContractEvent,
-	validationBlockNumber *big.Int,
-	validationBlockTimestamp time.Time,
+	validationBlock *miniheader.MiniHeader,
 ) ([]*zeroex.OrderEvent, error) {
 	signedOrders := []*zeroex.SignedOrder{}
 	for _, order := range orderHashToDBOrder {
+		if order.IsReplaced() {
+			// Replaced orders must not be revived by a revalidation. They are
+			// deleted by permanentlyDeleteStaleRemovedOrders once they expire.
+			continue
+		}
 		if order.IsRemoved && time.Since(order.LastUpdated) > permanentlyDeleteAfter {
 			if err := w.permanentlyDeleteOrder(ordersColTxn, order); err != nil {
 				return nil, err
@@ -1369,28 +2105,117 @@ func (w *Watcher) generateOrderEventsIfChanged(
 		return nil, nil
 	}
 	areNewOrders := false
-	validationResults := w.orderValidator.BatchValidate(ctx, signedOrders, areNewOrders, validationBlockNumber)
+	validationResults := w.orderValidator.BatchValidate(ctx, signedOrders, areNewOrders, validationBlock.Number)
+	validatedOrders := recordValidationResults(validationResults, orderHashToDBOrder, validationBlock)
 
-	return w.convertValidationResultsIntoOrderEvents(
-		ordersColTxn, validationResults, orderHashToDBOrder, orderHashToEvents, validationBlockTimestamp,
+	orderEvents, err := w.convertValidationResultsIntoOrderEvents(
+		ordersColTxn, validationResults, orderHashToDBOrder, orderHashToEvents, validationBlock.Timestamp,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Persist the validation results. Orders whose state changed have already
+	// been updated, but updating them again is harmless.
+	for _, order := range validatedOrders {
+		if err := ordersColTxn.Update(order); err != nil {
+			logger.WithFields(logger.Fields{
+				"error": err.Error(),
+				"order": order,
+			}).Error("Failed to update order")
+		}
+	}
+	return orderEvents, nil
+}
+
+// recordValidationResults sets the validation history fields of the orders in
+// orderHashToDBOrder according to the given validation results and returns the
+// orders that were validated. Orders which could not be validated because of
+// an error (e.g. a failed Ethereum RPC request) are not affected.
+func recordValidationResults(validationResults *ordervalidator.ValidationResults, orderHashToDBOrder map[common.Hash]*meshdb.Order, validationBlock *miniheader.MiniHeader) []*meshdb.Order {
+	validatedOrders := []*meshdb.Order{}
+	record := func(orderHash common.Hash, result string) {
+		order, found := orderHashToDBOrder[orderHash]
+		if !found {
+			return
+		}
+		order.LastValidatedBlockNumber = validationBlock.Number
+		order.LastValidatedBlockHash = validationBlock.Hash
+		order.LastValidationResult = result
+		validatedOrders = append(validatedOrders, order)
+	}
+	for _, acceptedOrderInfo := range validationResults.Accepted {
+		record(acceptedOrderInfo.OrderHash, meshdb.ValidationResultFillable)
+	}
+	for _, rejectedOrderInfo := range validationResults.Rejected {
+		if rejectedOrderInfo.Kind == ordervalidator.ZeroExValidation {
+			record(rejectedOrderInfo.OrderHash, string(rejectedOrderInfo.Status.Code))
+		}
+	}
+	return validatedOrders
 }
 
 // ValidateAndStoreValidOrders applies general 0x validation and Mesh-specific validation to
 // the given orders and if they are valid, adds them to the OrderWatcher
 func (w *Watcher) ValidateAndStoreValidOrders(ctx context.Context, orders []*zeroex.SignedOrder, pinned bool, chainID int) (*ordervalidator.ValidationResults, error) {
+	return w.ValidateAndStoreValidOrdersWithOpts(ctx, orders, StoreOrdersOpts{Pinned: pinned}, chainID)
+}
+
+// StoreOrdersOpts are options for ValidateAndStoreValidOrdersWithOpts.
+type StoreOrdersOpts struct {
+	// Pinned orders are not removed from the database unless they become
+	// unfillable.
+	Pinned bool
+	// Metadata maps order hashes to opaque metadata which is stored along with
+	// the orders. The metadata of orders which are already stored is updated.
+	Metadata map[common.Hash]json.RawMessage
+	// Source is stored as the source of new orders (see meshdb.Order).
+	Source string
+	// SourcePeerIDs maps order hashes to the IDs of the peers the orders were
+	// received from.
+	SourcePeerIDs map[common.Hash]string
+	// Lane determines the priority with which the orders are validated.
+	// Defaults to RemoteValidationLane.
+	Lane ValidationLane
+}
+
+// ValidateAndStoreValidOrdersWithOpts is like ValidateAndStoreValidOrders but
+// also stores the metadata and provenance in opts along with the orders.
+func (w *Watcher) ValidateAndStoreValidOrdersWithOpts(ctx context.Context, orders []*zeroex.SignedOrder, opts StoreOrdersOpts, chainID int) (*ordervalidator.ValidationResults, error) {
+	start := time.Now()
+	defer func() {
+		w.validationStatsMu.Lock()
+		defer w.validationStatsMu.Unlock()
+		w.validationStats.NumValidations++
+		w.validationStats.TotalDuration += time.Since(start)
+	}()
+
 	results, validMeshOrders, err := w.meshSpecificOrderValidation(orders, chainID)
 	if err != nil {
 		return nil, err
 	}
 
+	// Wait for a validation slot before locking down the processing of block
+	// events, so that waiting doesn't delay block processing.
+	if err := w.validationLanes.acquire(ctx, opts.Lane); err != nil {
+		return nil, err
+	}
+	defer w.validationLanes.release()
+
 	// Lock down the processing of additional block events until we've validated and added these new orders
 	w.handleBlockEventsMu.RLock()
 	defer w.handleBlockEventsMu.RUnlock()
 
-	validationBlock, zeroexResults, err := w.onchainOrderValidation(ctx, validMeshOrders)
-	if err != nil {
-		return nil, err
+	var validationBlock *miniheader.MiniHeader
+	var zeroexResults *ordervalidator.ValidationResults
+	if w.relayOnly {
+		validationBlock = relayOnlyValidationBlock
+		zeroexResults = w.offlineOrderValidation(validMeshOrders)
+	} else {
+		validationBlock, zeroexResults, err = w.onchainOrderValidation(ctx, validMeshOrders)
+		if err != nil {
+			return nil, err
+		}
 	}
 	results.Accepted = append(results.Accepted, zeroexResults.Accepted...)
 	results.Rejected = append(results.Rejected, zeroexResults.Rejected...)
@@ -1403,15 +2228,32 @@ func (w *Watcher) ValidateAndStoreValidOrders(ctx context.Context, orders []*zer
 			newOrderInfos = append(newOrderInfos, acceptedOrderInfo)
 		}
 	}
+	newOrderInfos = w.simulateMakerTransfers(ctx, results, newOrderInfos, validationBlock.Number)
+	newOrderInfos = w.rejectLowNotionalOrders(ctx, results, newOrderInfos)
+	newOrderInfos, err = w.rejectSupersededOrders(results, newOrderInfos)
+	if err != nil {
+		return nil, err
+	}
+	if w.dryRun {
+		return results, nil
+	}
 
 	// Add the order to the OrderWatcher. This also saves the order in the
 	// database.
 	allOrderEvents := []*zeroex.OrderEvent{}
-	orderEvents, err := w.add(newOrderInfos, validationBlock.Number, pinned)
+	orderEvents, err := w.add(newOrderInfos, validationBlock, opts)
 	if err != nil {
 		return nil, err
 	}
+	if err := w.updateOrderMetadata(results, opts.Metadata); err != nil {
+		return nil, err
+	}
 	allOrderEvents = append(allOrderEvents, orderEvents...)
+	replacedOrderEvents, err := w.removeSupersededOrders(newOrderInfos)
+	if err != nil {
+		return nil, err
+	}
+	allOrderEvents = append(allOrderEvents, replacedOrderEvents...)
 
 	if len(allOrderEvents) > 0 {
 		// NOTE(albrow): Send can block if the subscriber(s) are slow. Blocking here can cause problems when Mesh is
@@ -1419,7 +2261,7 @@ func (w *Watcher) ValidateAndStoreValidOrders(ctx context.Context, orders []*zer
 		// is done.
 		done := make(chan interface{})
 		go func() {
-			w.orderFeed.Send(allOrderEvents)
+			w.sendOrderEvents(allOrderEvents)
 			done <- struct{}{}
 		}()
 		select {
@@ -1433,6 +2275,56 @@ func (w *Watcher) ValidateAndStoreValidOrders(ctx context.Context, orders []*zer
 	return results, nil
 }
 
+// simulateMakerTransfers checks the given new orders for maker assets that fail
+// to transfer as expected according to the configured TransferSimulationMode.
+// In warn mode, failing orders are flagged. In strict mode, they are moved from
+// results.Accepted to results.Rejected. It returns the new orders that should
+// still be added. Errors encountered while simulating are logged but do not
+// prevent orders from being added.
+func (w *Watcher) simulateMakerTransfers(ctx context.Context, results *ordervalidator.ValidationResults, newOrderInfos []*ordervalidator.AcceptedOrderInfo, blockNumber *big.Int) []*ordervalidator.AcceptedOrderInfo {
+	if w.transferSimulationMode == TransferSimulationOff || len(newOrderInfos) == 0 {
+		return newOrderInfos
+	}
+	failedOrderHashes, err := w.orderValidator.SimulateMakerTransfers(ctx, newOrderInfos, blockNumber)
+	if err != nil {
+		logger.WithError(err).Warn("could not simulate maker transfers for new orders")
+	}
+	if len(failedOrderHashes) == 0 {
+		return newOrderInfos
+	}
+
+	if w.transferSimulationMode == TransferSimulationWarn {
+		for _, orderInfo := range newOrderInfos {
+			if _, found := failedOrderHashes[orderInfo.OrderHash]; found {
+				orderInfo.TransferSimulationFailed = true
+			}
+		}
+		return newOrderInfos
+	}
+
+	remainingOrderInfos := []*ordervalidator.AcceptedOrderInfo{}
+	for _, orderInfo := range newOrderInfos {
+		if _, found := failedOrderHashes[orderInfo.OrderHash]; !found {
+			remainingOrderInfos = append(remainingOrderInfos, orderInfo)
+		}
+	}
+	accepted := []*ordervalidator.AcceptedOrderInfo{}
+	for _, orderInfo := range results.Accepted {
+		if _, found := failedOrderHashes[orderInfo.OrderHash]; found {
+			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
+				OrderHash:   orderInfo.OrderHash,
+				SignedOrder: orderInfo.SignedOrder,
+				Kind:        ordervalidator.MeshValidation,
+				Status:      ordervalidator.ROTransferSimulationFailed,
+			})
+			continue
+		}
+		accepted = append(accepted, orderInfo)
+	}
+	results.Accepted = accepted
+	return remainingOrderInfos
+}
+
 func (w *Watcher) onchainOrderValidation(ctx context.Context, orders []*zeroex.SignedOrder) (*miniheader.MiniHeader, *ordervalidator.ValidationResults, error) {
 	// HACK(fabio): While we wait for EIP-1898 support in Parity, we have no choice but to do the `eth_call`
 	// at the latest known block _number_. As outlined in the `Rationale` section of EIP-1898, this approach cannot account
@@ -1467,6 +2359,17 @@ func (w *Watcher) meshSpecificOrderValidation(orders []*zeroex.SignedOrder, chai
 			})
 			continue
 		}
+		// Check the asset data limits first, since most of the other checks
+		// decode the asset data.
+		if status, ok := w.checkAssetDataLimits(order); !ok {
+			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
+				OrderHash:   orderHash,
+				SignedOrder: order,
+				Kind:        ordervalidator.MeshValidation,
+				Status:      status,
+			})
+			continue
+		}
 		if order.ExpirationTimeSeconds.Cmp(w.MaxExpirationTime()) == 1 {
 			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
 				OrderHash:   orderHash,
@@ -1476,13 +2379,32 @@ func (w *Watcher) meshSpecificOrderValidation(orders []*zeroex.SignedOrder, chai
 			})
 			continue
 		}
+		if !w.isMakerAllowed(order.MakerAddress) {
+			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
+				OrderHash:   orderHash,
+				SignedOrder: order,
+				Kind:        ordervalidator.MeshValidation,
+				Status:      ordervalidator.ROMakerNotAllowed,
+			})
+			continue
+		}
+		if !w.areAssetsAllowed(order) {
+			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
+				OrderHash:   orderHash,
+				SignedOrder: order,
+				Kind:        ordervalidator.MeshValidation,
+				Status:      ordervalidator.ROAssetNotAllowed,
+			})
+			continue
+		}
 		// Note(albrow): Orders with a sender address can be canceled or invalidated
 		// off-chain which is difficult to support since we need to prune
 		// canceled/invalidated orders from the database. We can special-case some
 		// sender addresses over time. (For example we already have support for
 		// validating Coordinator orders. What we're missing is a way to effeciently
-		// remove orders that are soft-canceled via the Coordinator API).
-		if order.SenderAddress != constants.NullAddress {
+		// remove orders that are soft-canceled via the Coordinator API). That's
+		// why they are rejected unless the node is configured otherwise.
+		if order.IsSenderRestricted() && w.senderRestrictedPolicy == RestrictedOrdersReject {
 			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
 				OrderHash:   orderHash,
 				SignedOrder: order,
@@ -1491,6 +2413,15 @@ func (w *Watcher) meshSpecificOrderValidation(orders []*zeroex.SignedOrder, chai
 			})
 			continue
 		}
+		if order.IsTakerRestricted() && w.takerRestrictedPolicy == RestrictedOrdersReject {
+			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
+				OrderHash:   orderHash,
+				SignedOrder: order,
+				Kind:        ordervalidator.MeshValidation,
+				Status:      ordervalidator.ROTakerAddressNotAllowed,
+			})
+			continue
+		}
 		if order.ChainID.Cmp(big.NewInt(int64(chainID))) != 0 {
 			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
 				OrderHash:   orderHash,
@@ -1516,13 +2447,17 @@ func (w *Watcher) meshSpecificOrderValidation(orders []*zeroex.SignedOrder, chai
 			}
 		}
 
-		if err := validateOrderSize(order); err != nil {
+		if err := w.validateOrderSize(order); err != nil {
 			if err == constants.ErrMaxOrderSize {
+				status := ordervalidator.ROMaxOrderSizeExceeded
+				if w.maxOrderSizeInBytes != constants.MaxOrderSizeInBytes {
+					status = status.WithMessage(fmt.Sprintf("order exceeds the maximum encoded size of %d bytes", w.maxOrderSizeInBytes))
+				}
 				results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
 					OrderHash:   orderHash,
 					SignedOrder: order,
 					Kind:        ordervalidator.MeshValidation,
-					Status:      ordervalidator.ROMaxOrderSizeExceeded,
+					Status:      status,
 				})
 				continue
 			} else {
@@ -1537,6 +2472,16 @@ func (w *Watcher) meshSpecificOrderValidation(orders []*zeroex.SignedOrder, chai
 			}
 		}
 
+		if w.isCancelledByEpoch(order) {
+			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
+				OrderHash:   orderHash,
+				SignedOrder: order,
+				Kind:        ordervalidator.ZeroExValidation,
+				Status:      ordervalidator.ROCancelled.WithMessage("order cancelled via cancelOrdersUpTo"),
+			})
+			continue
+		}
+
 		// Check if order is already stored in DB
 		var dbOrder meshdb.Order
 		err = w.meshDB.Orders.FindByID(orderHash.Bytes(), &dbOrder)
@@ -1548,6 +2493,17 @@ func (w *Watcher) meshSpecificOrderValidation(orders []*zeroex.SignedOrder, chai
 			// If the error is a db.NotFoundError, it just means the order is not currently stored in
 			// the database. There's nothing else in the database to check, so we can continue.
 		} else {
+			// If replaced by a newer order, reject it for as long as the replaced
+			// order is kept
+			if dbOrder.IsReplaced() {
+				results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
+					OrderHash:   orderHash,
+					SignedOrder: order,
+					Kind:        ordervalidator.MeshValidation,
+					Status:      ordervalidator.ROOrderReplaced.WithMessage(fmt.Sprintf("order was replaced by %s and can't be added again", dbOrder.ReplacedBy.Hex())),
+				})
+				continue
+			}
 			// If stored but flagged for removal, reject it
 			if dbOrder.IsRemoved {
 				results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
@@ -1575,17 +2531,64 @@ func (w *Watcher) meshSpecificOrderValidation(orders []*zeroex.SignedOrder, chai
 	return results, validMeshOrders, nil
 }
 
-func validateOrderSize(order *zeroex.SignedOrder) error {
+// updateCancelEpoch updates the index of order epochs for the given CancelUpTo
+// event. If the event was removed by a block re-org, the stored epoch is
+// deleted, since the previous epoch is unknown. Orders from the maker are then
+// validated as usual until the next CancelUpTo event.
+func (w *Watcher) updateCancelEpoch(event decoder.ExchangeCancelUpToEvent, isRemoved bool) {
+	var err error
+	if isRemoved {
+		err = w.meshDB.DeleteCancelEpoch(event.MakerAddress, event.OrderSenderAddress)
+	} else {
+		err = w.meshDB.SaveCancelEpoch(event.MakerAddress, event.OrderSenderAddress, event.OrderEpoch)
+	}
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"error":        err.Error(),
+			"makerAddress": event.MakerAddress.Hex(),
+		}).Error("could not update order epoch")
+	}
+}
+
+// isCancelledByEpoch returns true if the given order has a salt below the
+// order epoch which its maker set for its sender address via
+// `cancelOrdersUpTo`. Such orders are cancelled on-chain and can be rejected
+// without validating them via Ethereum RPC.
+func (w *Watcher) isCancelledByEpoch(order *zeroex.SignedOrder) bool {
+	orderEpoch, err := w.meshDB.FindCancelEpoch(order.MakerAddress, order.SenderAddress)
+	if err != nil {
+		logger.WithField("error", err).Error("could not find order epoch")
+		return false
+	}
+	return orderEpoch != nil && order.Salt.Cmp(orderEpoch) < 0
+}
+
+func (w *Watcher) validateOrderSize(order *zeroex.SignedOrder) error {
 	encoded, err := json.Marshal(order)
 	if err != nil {
 		return err
 	}
-	if len(encoded) > constants.MaxOrderSizeInBytes {
+	if len(encoded) > w.maxOrderSizeInBytes {
 		return constants.ErrMaxOrderSize
 	}
 	return nil
 }
 
+// checkAssetDataLimits checks all asset data of the given order against the
+// configured limits. It returns the status the order should be rejected with
+// and false if any of them is exceeded.
+func (w *Watcher) checkAssetDataLimits(order *zeroex.SignedOrder) (ordervalidator.RejectedOrderStatus, bool) {
+	for _, assetData := range [][]byte{order.MakerAssetData, order.TakerAssetData, order.MakerFeeAssetData, order.TakerFeeAssetData} {
+		switch w.assetDataDecoder.CheckLimits(assetData, w.assetDataLimits) {
+		case zeroex.ErrAssetDataTooLarge:
+			return ordervalidator.ROAssetDataTooLarge, false
+		case zeroex.ErrMultiAssetNestingTooDeep:
+			return ordervalidator.ROMultiAssetNestingTooDeep, false
+		}
+	}
+	return ordervalidator.RejectedOrderStatus{}, true
+}
+
 type orderUpdater interface {
 	Update(model db.Model) error
 }
@@ -1778,6 +2781,7 @@ func (w *Watcher) removeAssetDataAddressFromEventDecoder(assetData []byte) error
 		w.contractAddressToSeenCount[decodedAssetData.Address] = w.contractAddressToSeenCount[decodedAssetData.Address] - 1
 		if w.contractAddressToSeenCount[decodedAssetData.Address] == 0 {
 			w.eventDecoder.RemoveKnownERC721(decodedAssetData.Address)
+			w.erc721Approvals.removeToken(decodedAssetData.Address)
 		}
 	case "ERC1155Assets":
 		var decodedAssetData zeroex.ERC1155AssetData
@@ -1829,6 +2833,11 @@ func (w *Watcher) increaseMaxExpirationTimeIfPossible() error {
 	if orderCount, err := w.meshDB.Orders.Count(); err != nil {
 		return err
 	} else if orderCount < w.maxOrders {
+		if orderCount < int(maxOrdersTrimRatio*float64(w.maxOrders)) {
+			// Orders have been removed since the last time storage was trimmed, so
+			// low-value orders are welcome again.
+			w.resetEvictedNotional()
+		}
 		// We have enough space for new orders. Set the new max expiration time to the
 		// value of slow counter.
 		newMaxExpiration := w.maxExpirationCounter.Count()
@@ -1855,14 +2864,12 @@ func (w *Watcher) saveMaxExpirationTime(maxExpirationTime *big.Int) {
 	}
 }
 
-func (w *Watcher) getBlockchainState(events []*blockwatch.Event) (*big.Int, time.Time) {
-	var latestBlockNumber *big.Int
-	var latestBlockTimestamp time.Time
+func (w *Watcher) getLatestBlockHeader(events []*blockwatch.Event) *miniheader.MiniHeader {
+	var latestBlockHeader *miniheader.MiniHeader
 	for _, event := range events {
-		latestBlockNumber = event.BlockHeader.Number
-		latestBlockTimestamp = event.BlockHeader.Timestamp
+		latestBlockHeader = event.BlockHeader
 	}
-	return latestBlockNumber, latestBlockTimestamp
+	return latestBlockHeader
 }
 
 // WaitForAtLeastOneBlockToBeProcessed waits until the OrderWatcher has processed it's
@@ -720,6 +720,45 @@ func TestOrderWatcherCancelUpTo(t *testing.T) {
 	assert.Equal(t, orderEvent.OrderHash, orders[0].Hash)
 	assert.Equal(t, true, orders[0].IsRemoved)
 	assert.Equal(t, big.NewInt(0), orders[0].FillableTakerAssetAmount)
+
+	// The new order epoch is stored, so that cancelled orders which are
+	// received later can be rejected without validating them.
+	orderEpoch, err := meshDB.FindCancelEpoch(signedOrder.MakerAddress, constants.NullAddress)
+	require.NoError(t, err)
+	assert.Equal(t, new(big.Int).Add(targetOrderEpoch, big.NewInt(1)), orderEpoch)
+}
+
+func TestIsCancelledByEpoch(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/leveldb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+	w := &Watcher{meshDB: meshDB}
+
+	makerAddress := constants.GanacheAccount0
+	otherSenderAddress := constants.GanacheAccount1
+	require.NoError(t, meshDB.SaveCancelEpoch(makerAddress, constants.NullAddress, big.NewInt(10)))
+
+	testCases := []struct {
+		senderAddress common.Address
+		salt          int64
+		isCancelled   bool
+	}{
+		{senderAddress: constants.NullAddress, salt: 9, isCancelled: true},
+		{senderAddress: constants.NullAddress, salt: 10, isCancelled: false},
+		// The epoch is scoped to the sender address, so it doesn't apply to
+		// orders from the same maker with a different sender address.
+		{senderAddress: otherSenderAddress, salt: 9, isCancelled: false},
+	}
+	for i, tc := range testCases {
+		order := &zeroex.SignedOrder{
+			Order: zeroex.Order{
+				MakerAddress:  makerAddress,
+				SenderAddress: tc.senderAddress,
+				Salt:          big.NewInt(tc.salt),
+			},
+		}
+		assert.Equal(t, tc.isCancelled, w.isCancelledByEpoch(order), "test case %d", i)
+	}
 }
 
 func TestOrderWatcherERC20Filled(t *testing.T) {
@@ -1103,6 +1142,160 @@ func TestOrderWatcherCleanup(t *testing.T) {
 	case <-time.After(100 * time.Millisecond):
 		// Noop
 	}
+
+	stats := orderWatcher.LastCleanupStats()
+	assert.NoError(t, stats.Err)
+	assert.Equal(t, 1, stats.NumOrdersChecked)
+	assert.Equal(t, 0, stats.NumOrdersDeferred)
+	assert.Equal(t, 0, stats.NumOrderEvents)
+}
+
+func TestOrderWatcherCleanupMaxOrdersPerRun(t *testing.T) {
+	if !serialTestsEnabled {
+		t.Skip("Serial tests (tests which cannot run in parallel) are disabled. You can enable them with the --serial flag")
+	}
+
+	teardownSubTest := setupSubTest(t)
+	defer teardownSubTest(t)
+
+	meshDB, err := meshdb.New("/tmp/leveldb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	blockWatcher, orderWatcher := setupOrderWatcher(ctx, t, ethRPCClient, meshDB)
+
+	// Create and add three orders to OrderWatcher and make all of them stale.
+	orderOptions := scenario.OptionsForAll(orderopts.SetupMakerState(true))
+	signedOrders := scenario.NewSignedTestOrdersBatch(t, 3, orderOptions)
+	for _, signedOrder := range signedOrders {
+		watchOrder(ctx, t, orderWatcher, blockWatcher, ethClient, signedOrder)
+	}
+	for _, signedOrder := range signedOrders {
+		orderHash, err := signedOrder.ComputeOrderHash()
+		require.NoError(t, err)
+		dbOrder := &meshdb.Order{}
+		require.NoError(t, meshDB.Orders.FindByID(orderHash.Bytes(), dbOrder))
+		dbOrder.LastUpdated = time.Now().Add(-defaultLastUpdatedBuffer - 1*time.Minute)
+		// Forget when the order was last validated so that cleanup doesn't skip
+		// it.
+		dbOrder.LastValidatedBlockNumber = nil
+		require.NoError(t, meshDB.Orders.Update(dbOrder))
+	}
+
+	require.NoError(t, orderWatcher.cleanup(ctx, defaultLastUpdatedBuffer, 2))
+	stats := orderWatcher.LastCleanupStats()
+	assert.NoError(t, stats.Err)
+	assert.Equal(t, 2, stats.NumOrdersChecked)
+	assert.Equal(t, 1, stats.NumOrdersDeferred)
+	assert.False(t, stats.StartTime.IsZero())
+
+	// The orders which were checked are no longer stale, so the next cleanup
+	// should check the remaining one.
+	require.NoError(t, orderWatcher.cleanup(ctx, defaultLastUpdatedBuffer, 2))
+	stats = orderWatcher.LastCleanupStats()
+	assert.Equal(t, 1, stats.NumOrdersChecked)
+	assert.Equal(t, 0, stats.NumOrdersDeferred)
+}
+
+func TestOrderWatcherRevalidateAllOrders(t *testing.T) {
+	if !serialTestsEnabled {
+		t.Skip("Serial tests (tests which cannot run in parallel) are disabled. You can enable them with the --serial flag")
+	}
+
+	teardownSubTest := setupSubTest(t)
+	defer teardownSubTest(t)
+
+	meshDB, err := meshdb.New("/tmp/leveldb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	blockWatcher, orderWatcher := setupOrderWatcher(ctx, t, ethRPCClient, meshDB)
+
+	// Create and add three orders to OrderWatcher and forget when they were
+	// last validated, as if they were stored by an older version of Mesh.
+	orderOptions := scenario.OptionsForAll(orderopts.SetupMakerState(true))
+	signedOrders := scenario.NewSignedTestOrdersBatch(t, 3, orderOptions)
+	for _, signedOrder := range signedOrders {
+		watchOrder(ctx, t, orderWatcher, blockWatcher, ethClient, signedOrder)
+	}
+	for _, signedOrder := range signedOrders {
+		orderHash, err := signedOrder.ComputeOrderHash()
+		require.NoError(t, err)
+		dbOrder := &meshdb.Order{}
+		require.NoError(t, meshDB.Orders.FindByID(orderHash.Bytes(), dbOrder))
+		dbOrder.LastValidatedBlockNumber = nil
+		dbOrder.LastValidationResult = ""
+		require.NoError(t, meshDB.Orders.Update(dbOrder))
+	}
+
+	assert.False(t, orderWatcher.RevalidationProgress().InProgress())
+	require.NoError(t, orderWatcher.RevalidateAllOrders(ctx, 2, 0))
+	progress := orderWatcher.RevalidationProgress()
+	assert.NoError(t, progress.Err)
+	assert.False(t, progress.InProgress())
+	assert.Equal(t, 3, progress.NumOrdersTotal)
+	assert.Equal(t, 3, progress.NumOrdersRevalidated)
+
+	latestBlock, err := meshDB.FindLatestMiniHeader()
+	require.NoError(t, err)
+	for _, signedOrder := range signedOrders {
+		orderHash, err := signedOrder.ComputeOrderHash()
+		require.NoError(t, err)
+		dbOrder := &meshdb.Order{}
+		require.NoError(t, meshDB.Orders.FindByID(orderHash.Bytes(), dbOrder))
+		assert.True(t, dbOrder.WasValidatedAt(latestBlock), "order should have been re-validated at the latest block")
+		assert.Equal(t, meshdb.ValidationResultFillable, dbOrder.LastValidationResult)
+	}
+}
+
+func TestOrderWatcherRevalidateAllOrdersCanceled(t *testing.T) {
+	if !serialTestsEnabled {
+		t.Skip("Serial tests (tests which cannot run in parallel) are disabled. You can enable them with the --serial flag")
+	}
+
+	teardownSubTest := setupSubTest(t)
+	defer teardownSubTest(t)
+
+	meshDB, err := meshdb.New("/tmp/leveldb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	blockWatcher, orderWatcher := setupOrderWatcher(ctx, t, ethRPCClient, meshDB)
+
+	orderOptions := scenario.OptionsForAll(orderopts.SetupMakerState(true))
+	signedOrders := scenario.NewSignedTestOrdersBatch(t, 2, orderOptions)
+	for _, signedOrder := range signedOrders {
+		watchOrder(ctx, t, orderWatcher, blockWatcher, ethClient, signedOrder)
+	}
+
+	// Cancel the re-validation while it waits to re-validate the second batch.
+	revalidationCtx, cancelRevalidation := context.WithCancel(ctx)
+	defer cancelRevalidation()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- orderWatcher.RevalidateAllOrders(revalidationCtx, 1, time.Hour)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for orderWatcher.RevalidationProgress().NumOrdersRevalidated != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the first batch to be re-validated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancelRevalidation()
+	select {
+	case err := <-errChan:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("RevalidateAllOrders did not return after its context was canceled")
+	}
+	progress := orderWatcher.RevalidationProgress()
+	assert.Equal(t, context.Canceled, progress.Err)
+	assert.Equal(t, 2, progress.NumOrdersTotal)
+	assert.Equal(t, 1, progress.NumOrdersRevalidated)
 }
 
 func TestOrderWatcherUpdateBlockHeadersStoredInDBHeaderExists(t *testing.T) {
@@ -1603,6 +1796,100 @@ func TestConvertValidationResultsIntoOrderEventsUnexpired(t *testing.T) {
 	assert.Equal(t, false, existingOrder.IsRemoved)
 }
 
+func TestOrderWatcherMakerDenylist(t *testing.T) {
+	if !serialTestsEnabled {
+		t.Skip("Serial tests (tests which cannot run in parallel) are disabled. You can enable them with the --serial flag")
+	}
+
+	teardownSubTest := setupSubTest(t)
+	defer teardownSubTest(t)
+
+	meshDB, err := meshdb.New("/tmp/leveldb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+
+	signedOrder := scenario.NewSignedTestOrder(t,
+		orderopts.SetupMakerState(true),
+		orderopts.MakerAssetData(scenario.ZRXAssetData),
+		orderopts.TakerAssetData(scenario.WETHAssetData),
+	)
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	blockWatcher, orderWatcher := setupOrderWatcher(ctx, t, ethRPCClient, meshDB)
+	watchOrder(ctx, t, orderWatcher, blockWatcher, ethClient, signedOrder)
+	orderEventsChan := make(chan []*zeroex.OrderEvent, 10)
+	orderWatcher.Subscribe(orderEventsChan)
+
+	// Denying the maker should remove the stored order.
+	numOrdersRemoved, err := orderWatcher.SetMakerDenylist([]common.Address{signedOrder.MakerAddress})
+	require.NoError(t, err)
+	assert.Equal(t, 1, numOrdersRemoved)
+	assert.Equal(t, []common.Address{signedOrder.MakerAddress}, orderWatcher.MakerDenylist())
+
+	orderEvents := waitForOrderEvents(t, orderEventsChan, 1, 4*time.Second)
+	require.Len(t, orderEvents, 1)
+	assert.Equal(t, zeroex.ESStoppedWatching, orderEvents[0].EndState)
+
+	var orders []*meshdb.Order
+	err = meshDB.Orders.FindAll(&orders)
+	require.NoError(t, err)
+	assert.Len(t, orders, 0)
+
+	// New orders from the maker should be rejected.
+	validationResults, err := orderWatcher.ValidateAndStoreValidOrders(ctx, []*zeroex.SignedOrder{signedOrder}, false, constants.TestChainID)
+	require.NoError(t, err)
+	require.Len(t, validationResults.Rejected, 1)
+	assert.Equal(t, ordervalidator.ROMakerNotAllowed, validationResults.Rejected[0].Status)
+
+	// An allowlist which doesn't include the maker should also reject its orders.
+	_, err = orderWatcher.SetMakerDenylist([]common.Address{})
+	require.NoError(t, err)
+	_, err = orderWatcher.SetMakerAllowlist([]common.Address{constants.GanacheAccount4})
+	require.NoError(t, err)
+	validationResults, err = orderWatcher.ValidateAndStoreValidOrders(ctx, []*zeroex.SignedOrder{signedOrder}, false, constants.TestChainID)
+	require.NoError(t, err)
+	require.Len(t, validationResults.Rejected, 1)
+	assert.Equal(t, ordervalidator.ROMakerNotAllowed, validationResults.Rejected[0].Status)
+}
+
+func TestOrderWatcherAssetDenylist(t *testing.T) {
+	if !serialTestsEnabled {
+		t.Skip("Serial tests (tests which cannot run in parallel) are disabled. You can enable them with the --serial flag")
+	}
+
+	teardownSubTest := setupSubTest(t)
+	defer teardownSubTest(t)
+
+	meshDB, err := meshdb.New("/tmp/leveldb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+
+	signedOrder := scenario.NewSignedTestOrder(t,
+		orderopts.SetupMakerState(true),
+		orderopts.MakerAssetData(scenario.ZRXAssetData),
+		orderopts.TakerAssetData(scenario.WETHAssetData),
+	)
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	blockWatcher, orderWatcher := setupOrderWatcher(ctx, t, ethRPCClient, meshDB)
+	watchOrder(ctx, t, orderWatcher, blockWatcher, ethClient, signedOrder)
+	orderEventsChan := make(chan []*zeroex.OrderEvent, 10)
+	orderWatcher.Subscribe(orderEventsChan)
+
+	// Denying the maker token should remove the stored order.
+	numOrdersRemoved, err := orderWatcher.SetAssetDenylist([]common.Address{ganacheAddresses.ZRXToken})
+	require.NoError(t, err)
+	assert.Equal(t, 1, numOrdersRemoved)
+
+	orderEvents := waitForOrderEvents(t, orderEventsChan, 1, 4*time.Second)
+	require.Len(t, orderEvents, 1)
+	assert.Equal(t, zeroex.ESStoppedWatching, orderEvents[0].EndState)
+
+	// New orders involving the token should be rejected.
+	validationResults, err := orderWatcher.ValidateAndStoreValidOrders(ctx, []*zeroex.SignedOrder{signedOrder}, false, constants.TestChainID)
+	require.NoError(t, err)
+	require.Len(t, validationResults.Rejected, 1)
+	assert.Equal(t, ordervalidator.ROAssetNotAllowed, validationResults.Rejected[0].Status)
+}
+
 func TestDrainAllBlockEventsChan(t *testing.T) {
 	blockEventsChan := make(chan []*blockwatch.Event, 100)
 	ts := time.Now().Add(1 * time.Hour)
@@ -1686,7 +1973,15 @@ func setupOrderWatcher(ctx context.Context, t *testing.T, ethRPCClient ethrpccli
 		Client:          blockWatcherClient,
 	}
 	blockWatcher := blockwatch.New(blockWatcherConfig)
-	orderValidator, err := ordervalidator.New(ethRPCClient, constants.TestChainID, ethereumRPCMaxContentLength, ganacheAddresses)
+	// Mesh creates the metadata, which holds the last order event sequence
+	// number, before creating the Watcher.
+	if _, err := meshDB.GetMetadata(); err != nil {
+		require.NoError(t, meshDB.SaveMetadata(&meshdb.Metadata{
+			EthereumChainID:   constants.TestChainID,
+			MaxExpirationTime: constants.UnlimitedExpirationTime,
+		}))
+	}
+	orderValidator, err := ordervalidator.New(ethRPCClient, constants.TestChainID, ethereumRPCMaxContentLength, ganacheAddresses, ordervalidator.ValidationStrategyDevUtils, ordervalidator.CallOptions{})
 	require.NoError(t, err)
 	orderWatcher, err := New(Config{
 		MeshDB:            meshDB,
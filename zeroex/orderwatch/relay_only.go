@@ -0,0 +1,156 @@
+package orderwatch
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/0xProject/0x-mesh/ethereum/miniheader"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	logger "github.com/sirupsen/logrus"
+)
+
+// relayOnlyExpirationCheckInterval is how often expired orders are removed in
+// relay-only mode, where expiration is based on the local clock instead of
+// block timestamps.
+const relayOnlyExpirationCheckInterval = 10 * time.Second
+
+// relayOnlyValidationBlock is used as the validation block of orders stored in
+// relay-only mode, which are never validated at any block.
+var relayOnlyValidationBlock = &miniheader.MiniHeader{Number: big.NewInt(0)}
+
+// offlineOrderValidation validates orders in relay-only mode without making
+// any calls to Ethereum. It only checks the properties of the orders which can
+// be checked offline, including EIP712 and EthSign signatures and expiration
+// according to the local clock. Orders which pass all checks are assumed to be
+// fully fillable. Orders with signatures that can only be validated on-chain
+// are rejected.
+func (w *Watcher) offlineOrderValidation(orders []*zeroex.SignedOrder) *ordervalidator.ValidationResults {
+	results := &ordervalidator.ValidationResults{}
+	now := big.NewInt(time.Now().Unix())
+	for _, order := range orders {
+		orderHash, err := order.ComputeOrderHash()
+		if err != nil {
+			logger.WithField("error", err).Error("could not compute order hash")
+			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
+				OrderHash:   orderHash,
+				SignedOrder: order,
+				Kind:        ordervalidator.MeshError,
+				Status:      ordervalidator.ROInternalError,
+			})
+			continue
+		}
+		if status, ok := w.offlineOrderStatus(order, now); !ok {
+			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
+				OrderHash:   orderHash,
+				SignedOrder: order,
+				Kind:        ordervalidator.ZeroExValidation,
+				Status:      status,
+			})
+			continue
+		}
+		signatureStatus, err := zeroex.ValidateSignatureOffline(orderHash, order.MakerAddress, order.Signature)
+		switch signatureStatus {
+		case zeroex.SignatureValid:
+		case zeroex.SignatureRequiresOnChainValidation:
+			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
+				OrderHash:   orderHash,
+				SignedOrder: order,
+				Kind:        ordervalidator.MeshValidation,
+				Status:      ordervalidator.ROSignatureRequiresOnChainValidation,
+			})
+			continue
+		default:
+			status := ordervalidator.ROInvalidSignature
+			if err != nil {
+				status = status.WithMessage(err.Error())
+			}
+			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
+				OrderHash:   orderHash,
+				SignedOrder: order,
+				Kind:        ordervalidator.ZeroExValidation,
+				Status:      status,
+			})
+			continue
+		}
+		results.Accepted = append(results.Accepted, &ordervalidator.AcceptedOrderInfo{
+			OrderHash:                orderHash,
+			SignedOrder:              order,
+			FillableTakerAssetAmount: new(big.Int).Set(order.TakerAssetAmount),
+			IsNew:                    true,
+		})
+	}
+	return results
+}
+
+// offlineOrderStatus checks the amounts, asset data and expiration time of
+// the given order. now is the current Unix time. It returns false and the
+// reason if the order is invalid.
+func (w *Watcher) offlineOrderStatus(order *zeroex.SignedOrder, now *big.Int) (ordervalidator.RejectedOrderStatus, bool) {
+	if order.MakerAssetAmount.Sign() == 0 {
+		return ordervalidator.ROInvalidMakerAssetAmount, false
+	}
+	if order.TakerAssetAmount.Sign() == 0 {
+		return ordervalidator.ROInvalidTakerAssetAmount, false
+	}
+	if _, err := w.assetDataDecoder.GetName(order.MakerAssetData); err != nil {
+		return ordervalidator.ROInvalidMakerAssetData, false
+	}
+	if _, err := w.assetDataDecoder.GetName(order.TakerAssetData); err != nil {
+		return ordervalidator.ROInvalidTakerAssetData, false
+	}
+	if len(order.MakerFeeAssetData) != 0 {
+		if _, err := w.assetDataDecoder.GetName(order.MakerFeeAssetData); err != nil {
+			return ordervalidator.ROInvalidMakerFeeAssetData, false
+		}
+	}
+	if len(order.TakerFeeAssetData) != 0 {
+		if _, err := w.assetDataDecoder.GetName(order.TakerFeeAssetData); err != nil {
+			return ordervalidator.ROInvalidTakerFeeAssetData, false
+		}
+	}
+	if order.ExpirationTimeSeconds.Cmp(now) <= 0 {
+		return ordervalidator.ROExpired.WithMessage("order expired according to the local clock"), false
+	}
+	return ordervalidator.RejectedOrderStatus{}, true
+}
+
+// relayOnlyLoop takes the place of the main loop in relay-only mode. Since no
+// blocks are processed, it removes expired orders according to the local
+// clock every relayOnlyExpirationCheckInterval until ctx is canceled.
+func (w *Watcher) relayOnlyLoop(ctx context.Context) error {
+	ticker := time.NewTicker(relayOnlyExpirationCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := w.removeExpiredOrders(time.Now()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// removeExpiredOrders removes the orders which expired at or before now and
+// emits EXPIRED events for them.
+func (w *Watcher) removeExpiredOrders(now time.Time) error {
+	w.handleBlockEventsMu.Lock()
+	defer w.handleBlockEventsMu.Unlock()
+
+	ordersColTxn := w.meshDB.Orders.OpenTransaction()
+	defer func() {
+		_ = ordersColTxn.Discard()
+	}()
+	orderEvents, err := w.handleOrderExpirations(ordersColTxn, now, time.Time{}, nil)
+	if err != nil {
+		return err
+	}
+	if err := ordersColTxn.Commit(); err != nil {
+		return err
+	}
+	w.sendOrderEvents(orderEvents)
+	return nil
+}
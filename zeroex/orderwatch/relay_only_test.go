@@ -0,0 +1,44 @@
+// +build !js
+
+package orderwatch
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xProject/0x-mesh/scenario"
+	"github.com/0xProject/0x-mesh/scenario/orderopts"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineOrderValidation(t *testing.T) {
+	w := &Watcher{assetDataDecoder: zeroex.NewAssetDataDecoder()}
+
+	validOrder := scenario.NewSignedTestOrder(t)
+	expiredOrder := scenario.NewSignedTestOrder(t, orderopts.ExpirationTimeSeconds(big.NewInt(time.Now().Add(-time.Minute).Unix())))
+	// Changing the order after signing it invalidates the signature.
+	invalidSignatureOrder := scenario.NewSignedTestOrder(t)
+	invalidSignatureOrder.MakerAssetAmount = big.NewInt(1000)
+	walletOrder := scenario.NewSignedTestOrder(t)
+	walletOrder.Signature = []byte{byte(zeroex.EIP1271WalletSignature)}
+
+	results := w.offlineOrderValidation([]*zeroex.SignedOrder{validOrder, expiredOrder, invalidSignatureOrder, walletOrder})
+
+	require.Len(t, results.Accepted, 1)
+	assert.Equal(t, validOrder, results.Accepted[0].SignedOrder)
+	assert.Equal(t, validOrder.TakerAssetAmount, results.Accepted[0].FillableTakerAssetAmount)
+	assert.True(t, results.Accepted[0].IsNew)
+
+	require.Len(t, results.Rejected, 3)
+	assert.Equal(t, expiredOrder, results.Rejected[0].SignedOrder)
+	assert.Equal(t, ordervalidator.ROExpired.Code, results.Rejected[0].Status.Code)
+	assert.Equal(t, invalidSignatureOrder, results.Rejected[1].SignedOrder)
+	assert.Equal(t, ordervalidator.ROInvalidSignature.Code, results.Rejected[1].Status.Code)
+	assert.Equal(t, walletOrder, results.Rejected[2].SignedOrder)
+	assert.Equal(t, ordervalidator.ROSignatureRequiresOnChainValidation, results.Rejected[2].Status)
+	assert.Equal(t, ordervalidator.MeshValidation, results.Rejected[2].Kind)
+}
@@ -0,0 +1,95 @@
+package orderwatch
+
+import (
+	"math/big"
+
+	"github.com/0xProject/0x-mesh/ethereum/blockwatch"
+	"github.com/0xProject/0x-mesh/ethereum/miniheader"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// blockOrderEvents are the order events caused by contract events in a
+// single block.
+type blockOrderEvents struct {
+	blockNumber *big.Int
+	// orderEvents maps order hashes to the order events for that order.
+	orderEvents map[common.Hash][]*zeroex.SupersededOrderEvent
+}
+
+// linkSupersededOrderEvents sets the Supersedes field of the given order
+// events, which were generated by processing the given block events. An order
+// event supersedes the earlier order events for the same order which were
+// caused by blocks that the block events removed. The order events caused by
+// the block events are then remembered until the block that caused them can
+// no longer be removed by a re-org, i.e. until it is older than the mini
+// header retention limit. It MUST only be called from handleBlockEvents.
+func (w *Watcher) linkSupersededOrderEvents(events []*blockwatch.Event, latestBlock *miniheader.MiniHeader, orderEvents []*zeroex.OrderEvent) {
+	blockNumbers := map[common.Hash]*big.Int{}
+	for _, event := range events {
+		blockNumbers[event.BlockHeader.Hash] = event.BlockHeader.Number
+	}
+
+	for _, orderEvent := range orderEvents {
+		seenBlocks := map[common.Hash]struct{}{}
+		for _, contractEvent := range orderEvent.ContractEvents {
+			if !contractEvent.IsRemoved {
+				continue
+			}
+			if _, seen := seenBlocks[contractEvent.BlockHash]; seen {
+				continue
+			}
+			seenBlocks[contractEvent.BlockHash] = struct{}{}
+			if recorded, found := w.recentBlockOrderEvents[contractEvent.BlockHash]; found {
+				orderEvent.Supersedes = append(orderEvent.Supersedes, recorded.orderEvents[orderEvent.OrderHash]...)
+			}
+		}
+	}
+
+	// The order events caused by removed blocks can no longer be superseded.
+	for _, event := range events {
+		if event.Type == blockwatch.Removed {
+			delete(w.recentBlockOrderEvents, event.BlockHeader.Hash)
+		}
+	}
+
+	for _, orderEvent := range orderEvents {
+		seenBlocks := map[common.Hash]struct{}{}
+		for _, contractEvent := range orderEvent.ContractEvents {
+			if contractEvent.IsRemoved {
+				continue
+			}
+			if _, seen := seenBlocks[contractEvent.BlockHash]; seen {
+				continue
+			}
+			seenBlocks[contractEvent.BlockHash] = struct{}{}
+			blockNumber, found := blockNumbers[contractEvent.BlockHash]
+			if !found {
+				continue
+			}
+			recorded, found := w.recentBlockOrderEvents[contractEvent.BlockHash]
+			if !found {
+				recorded = &blockOrderEvents{
+					blockNumber: blockNumber,
+					orderEvents: map[common.Hash][]*zeroex.SupersededOrderEvent{},
+				}
+				w.recentBlockOrderEvents[contractEvent.BlockHash] = recorded
+			}
+			recorded.orderEvents[orderEvent.OrderHash] = append(recorded.orderEvents[orderEvent.OrderHash], &zeroex.SupersededOrderEvent{
+				BlockHash:                contractEvent.BlockHash,
+				Timestamp:                orderEvent.Timestamp,
+				EndState:                 orderEvent.EndState,
+				FillableTakerAssetAmount: orderEvent.FillableTakerAssetAmount,
+			})
+		}
+	}
+
+	// Blocks older than the retention limit cannot be removed by a re-org
+	// which Mesh can handle.
+	minBlockNumber := new(big.Int).Sub(latestBlock.Number, big.NewInt(int64(w.meshDB.MiniHeaderRetentionLimit)))
+	for blockHash, recorded := range w.recentBlockOrderEvents {
+		if recorded.blockNumber.Cmp(minBlockNumber) < 0 {
+			delete(w.recentBlockOrderEvents, blockHash)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// +build !js
+
+package orderwatch
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xProject/0x-mesh/ethereum/blockwatch"
+	"github.com/0xProject/0x-mesh/ethereum/miniheader"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkSupersededOrderEvents(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+	w := &Watcher{
+		meshDB:                 meshDB,
+		recentBlockOrderEvents: map[common.Hash]*blockOrderEvents{},
+	}
+	orderHash := common.HexToHash("0x1")
+
+	block10 := &miniheader.MiniHeader{Hash: common.HexToHash("0x10"), Number: big.NewInt(10)}
+	filledEvent := &zeroex.OrderEvent{
+		Timestamp:                time.Now(),
+		OrderHash:                orderHash,
+		EndState:                 zeroex.ESOrderFilled,
+		FillableTakerAssetAmount: big.NewInt(50),
+		ContractEvents:           []*zeroex.ContractEvent{{BlockHash: block10.Hash}},
+	}
+	w.linkSupersededOrderEvents([]*blockwatch.Event{{Type: blockwatch.Added, BlockHeader: block10}}, block10, []*zeroex.OrderEvent{filledEvent})
+	assert.Empty(t, filledEvent.Supersedes)
+
+	// Block 10 is removed by a re-org, which reverts the fill.
+	replacementBlock10 := &miniheader.MiniHeader{Hash: common.HexToHash("0x1010"), Number: big.NewInt(10)}
+	correctionEvent := &zeroex.OrderEvent{
+		Timestamp:                time.Now(),
+		OrderHash:                orderHash,
+		EndState:                 zeroex.ESOrderFillabilityIncreased,
+		FillableTakerAssetAmount: big.NewInt(100),
+		ContractEvents:           []*zeroex.ContractEvent{{BlockHash: block10.Hash, IsRemoved: true}},
+	}
+	blockEvents := []*blockwatch.Event{
+		{Type: blockwatch.Removed, BlockHeader: block10},
+		{Type: blockwatch.Added, BlockHeader: replacementBlock10},
+	}
+	w.linkSupersededOrderEvents(blockEvents, replacementBlock10, []*zeroex.OrderEvent{correctionEvent})
+	expectedSupersedes := []*zeroex.SupersededOrderEvent{
+		{
+			BlockHash:                block10.Hash,
+			Timestamp:                filledEvent.Timestamp,
+			EndState:                 zeroex.ESOrderFilled,
+			FillableTakerAssetAmount: big.NewInt(50),
+		},
+	}
+	assert.Equal(t, expectedSupersedes, correctionEvent.Supersedes)
+	assert.NotContains(t, w.recentBlockOrderEvents, block10.Hash)
+
+	// Order events are forgotten once their block is older than the retention
+	// limit.
+	block11 := &miniheader.MiniHeader{Hash: common.HexToHash("0x11"), Number: big.NewInt(11)}
+	unfundedEvent := &zeroex.OrderEvent{
+		Timestamp:                time.Now(),
+		OrderHash:                orderHash,
+		EndState:                 zeroex.ESOrderBecameUnfunded,
+		FillableTakerAssetAmount: big.NewInt(0),
+		ContractEvents:           []*zeroex.ContractEvent{{BlockHash: block11.Hash}},
+	}
+	w.linkSupersededOrderEvents([]*blockwatch.Event{{Type: blockwatch.Added, BlockHeader: block11}}, block11, []*zeroex.OrderEvent{unfundedEvent})
+	require.Contains(t, w.recentBlockOrderEvents, block11.Hash)
+	laterBlock := &miniheader.MiniHeader{Hash: common.HexToHash("0x99"), Number: big.NewInt(int64(12 + meshDB.MiniHeaderRetentionLimit))}
+	w.linkSupersededOrderEvents([]*blockwatch.Event{{Type: blockwatch.Added, BlockHeader: laterBlock}}, laterBlock, nil)
+	assert.NotContains(t, w.recentBlockOrderEvents, block11.Hash)
+}
@@ -0,0 +1,246 @@
+package orderwatch
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/0xProject/0x-mesh/db"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/ethereum/go-ethereum/common"
+	logger "github.com/sirupsen/logrus"
+)
+
+// orderReplacement describes a stored order which is replaced by a newer
+// order.
+type orderReplacement struct {
+	replacedOrder *meshdb.Order
+	replacedBy    common.Hash
+}
+
+// ReplaceOrders removes stored orders which were explicitly replaced by newer
+// orders. replacements maps the hash of a new order to the hash of the order it
+// replaces. A replacement is ignored unless both orders are stored and have the
+// same maker and asset pair, so that an order can only ever be replaced by its
+// maker. A REPLACED event is emitted for each removed order and replaced
+// orders are rejected if they are added again. It returns the number of orders
+// that were removed.
+func (w *Watcher) ReplaceOrders(replacements map[common.Hash]common.Hash) (int, error) {
+	if len(replacements) == 0 {
+		return 0, nil
+	}
+	// Pause block event processing and order additions while we remove orders.
+	w.handleBlockEventsMu.Lock()
+	defer w.handleBlockEventsMu.Unlock()
+
+	validReplacements := []orderReplacement{}
+	for newOrderHash, replacedOrderHash := range replacements {
+		newOrder, err := w.findStoredOrder(newOrderHash)
+		if err != nil {
+			return 0, err
+		}
+		replacedOrder, err := w.findStoredOrder(replacedOrderHash)
+		if err != nil {
+			return 0, err
+		}
+		if newOrder == nil || replacedOrder == nil || newOrderHash == replacedOrderHash {
+			continue
+		}
+		if !haveSameMakerAndPair(newOrder.SignedOrder, replacedOrder.SignedOrder) {
+			logger.WithFields(logger.Fields{
+				"orderHash":         newOrderHash.Hex(),
+				"replacedOrderHash": replacedOrderHash.Hex(),
+			}).Debug("ignoring replacement of an order with a different maker or asset pair")
+			continue
+		}
+		validReplacements = append(validReplacements, orderReplacement{
+			replacedOrder: replacedOrder,
+			replacedBy:    newOrderHash,
+		})
+	}
+	orderEvents, err := w.removeReplacedOrders(validReplacements)
+	if err != nil {
+		return 0, err
+	}
+	w.sendOrderEvents(orderEvents)
+	return len(orderEvents), nil
+}
+
+// findStoredOrder returns the stored order with the given hash or nil if there
+// is no such order or it has been flagged for removal.
+func (w *Watcher) findStoredOrder(orderHash common.Hash) (*meshdb.Order, error) {
+	var order meshdb.Order
+	if err := w.meshDB.Orders.FindByID(orderHash.Bytes(), &order); err != nil {
+		if _, ok := err.(db.NotFoundError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if order.IsRemoved {
+		return nil, nil
+	}
+	return &order, nil
+}
+
+// haveSameMakerAndPair returns true if the given orders have the same maker,
+// maker asset data and taker asset data.
+func haveSameMakerAndPair(a, b *zeroex.SignedOrder) bool {
+	return a.MakerAddress == b.MakerAddress &&
+		bytes.Equal(a.MakerAssetData, b.MakerAssetData) &&
+		bytes.Equal(a.TakerAssetData, b.TakerAssetData)
+}
+
+// findOrdersWithSameMakerAndPair returns the stored orders which have not been
+// flagged for removal and have the same maker and asset pair as the given
+// order, excluding the order itself.
+func (w *Watcher) findOrdersWithSameMakerAndPair(order *zeroex.SignedOrder, orderHash common.Hash) ([]*meshdb.Order, error) {
+	makerOrders, err := w.meshDB.FindOrdersByMakerAddress(order.MakerAddress)
+	if err != nil {
+		return nil, err
+	}
+	// Orders can be returned more than once since they are found via a multi
+	// index.
+	seen := map[common.Hash]struct{}{}
+	orders := []*meshdb.Order{}
+	for _, makerOrder := range makerOrders {
+		if _, found := seen[makerOrder.Hash]; found {
+			continue
+		}
+		seen[makerOrder.Hash] = struct{}{}
+		if makerOrder.IsRemoved || makerOrder.Hash == orderHash || !haveSameMakerAndPair(order, makerOrder.SignedOrder) {
+			continue
+		}
+		orders = append(orders, makerOrder)
+	}
+	return orders, nil
+}
+
+// rejectSupersededOrders moves the given new orders for which a stored order
+// with the same maker and asset pair but a higher salt exists from
+// results.Accepted to results.Rejected. It returns the new orders that should
+// still be added. It has no effect unless ReplaceSupersededOrders is set.
+//
+// Salts are not ordered by the protocol. This heuristic assumes that makers
+// which opt into it increase the salt of each new order (e.g. by using a
+// timestamp). Orders from makers which use random salts will be rejected or
+// replaced arbitrarily.
+func (w *Watcher) rejectSupersededOrders(results *ordervalidator.ValidationResults, newOrderInfos []*ordervalidator.AcceptedOrderInfo) ([]*ordervalidator.AcceptedOrderInfo, error) {
+	if !w.replaceSupersededOrders || len(newOrderInfos) == 0 {
+		return newOrderInfos, nil
+	}
+	supersededOrderHashes := map[common.Hash]struct{}{}
+	remainingOrderInfos := []*ordervalidator.AcceptedOrderInfo{}
+	for _, orderInfo := range newOrderInfos {
+		orders, err := w.findOrdersWithSameMakerAndPair(orderInfo.SignedOrder, orderInfo.OrderHash)
+		if err != nil {
+			return nil, err
+		}
+		isSuperseded := false
+		for _, order := range orders {
+			if order.SignedOrder.Salt.Cmp(orderInfo.SignedOrder.Salt) == 1 {
+				isSuperseded = true
+				break
+			}
+		}
+		if isSuperseded {
+			supersededOrderHashes[orderInfo.OrderHash] = struct{}{}
+			continue
+		}
+		remainingOrderInfos = append(remainingOrderInfos, orderInfo)
+	}
+	if len(supersededOrderHashes) == 0 {
+		return newOrderInfos, nil
+	}
+	accepted := []*ordervalidator.AcceptedOrderInfo{}
+	for _, orderInfo := range results.Accepted {
+		if _, found := supersededOrderHashes[orderInfo.OrderHash]; found {
+			results.Rejected = append(results.Rejected, &ordervalidator.RejectedOrderInfo{
+				OrderHash:   orderInfo.OrderHash,
+				SignedOrder: orderInfo.SignedOrder,
+				Kind:        ordervalidator.MeshValidation,
+				Status:      ordervalidator.ROOrderSuperseded,
+			})
+			continue
+		}
+		accepted = append(accepted, orderInfo)
+	}
+	results.Accepted = accepted
+	return remainingOrderInfos, nil
+}
+
+// removeSupersededOrders removes the stored orders which are superseded by the
+// given newly added orders, i.e. orders with the same maker and asset pair but
+// a lower salt. It returns a REPLACED event for each removed order. It has no
+// effect unless ReplaceSupersededOrders is set. Like rejectSupersededOrders, it
+// assumes that salts increase.
+func (w *Watcher) removeSupersededOrders(newOrderInfos []*ordervalidator.AcceptedOrderInfo) ([]*zeroex.OrderEvent, error) {
+	if !w.replaceSupersededOrders || len(newOrderInfos) == 0 {
+		return nil, nil
+	}
+	replacements := []orderReplacement{}
+	for _, orderInfo := range newOrderInfos {
+		// New orders are not always stored, e.g. if they exceed the maximum
+		// expiration time which was lowered while adding them.
+		if storedOrder, err := w.findStoredOrder(orderInfo.OrderHash); err != nil {
+			return nil, err
+		} else if storedOrder == nil {
+			continue
+		}
+		orders, err := w.findOrdersWithSameMakerAndPair(orderInfo.SignedOrder, orderInfo.OrderHash)
+		if err != nil {
+			return nil, err
+		}
+		for _, order := range orders {
+			if order.SignedOrder.Salt.Cmp(orderInfo.SignedOrder.Salt) == -1 {
+				replacements = append(replacements, orderReplacement{
+					replacedOrder: order,
+					replacedBy:    orderInfo.OrderHash,
+				})
+			}
+		}
+	}
+	return w.removeReplacedOrders(replacements)
+}
+
+// removeReplacedOrders flags the replaced orders for removal and returns a
+// REPLACED event for each of them. The orders are kept as tombstones, which
+// record the hash of the replacing order, until they expire, so that they are
+// neither revived by a revalidation nor accepted if they are added again. The
+// caller must hold handleBlockEventsMu.
+func (w *Watcher) removeReplacedOrders(replacements []orderReplacement) ([]*zeroex.OrderEvent, error) {
+	if len(replacements) == 0 {
+		return nil, nil
+	}
+	ordersColTxn := w.meshDB.Orders.OpenTransaction()
+	defer func() {
+		_ = ordersColTxn.Discard()
+	}()
+	now := time.Now().UTC()
+	removedOrderHashes := map[common.Hash]struct{}{}
+	orderEvents := []*zeroex.OrderEvent{}
+	for _, replacement := range replacements {
+		order := replacement.replacedOrder
+		if _, found := removedOrderHashes[order.Hash]; found {
+			continue
+		}
+		removedOrderHashes[order.Hash] = struct{}{}
+		order.ReplacedBy = replacement.replacedBy
+		order.LastValidationResult = ordervalidator.ROOrderReplaced.Code
+		w.unwatchOrder(ordersColTxn, order, order.FillableTakerAssetAmount)
+		orderEvents = append(orderEvents, &zeroex.OrderEvent{
+			Timestamp:                now,
+			OrderHash:                order.Hash,
+			SignedOrder:              order.SignedOrder,
+			FillableTakerAssetAmount: order.FillableTakerAssetAmount,
+			EndState:                 zeroex.ESOrderReplaced,
+			ReplacedBy:               replacement.replacedBy,
+			Metadata:                 order.Metadata,
+		})
+	}
+	if err := ordersColTxn.Commit(); err != nil {
+		return nil, err
+	}
+	logger.WithField("numOrdersReplaced", len(orderEvents)).Debug("removed replaced orders")
+	return orderEvents, nil
+}
@@ -0,0 +1,191 @@
+// +build !js
+
+package orderwatch
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/expirationwatch"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/scenario"
+	"github.com/0xProject/0x-mesh/scenario/orderopts"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/0xProject/0x-mesh/zeroex/ordervalidator"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceOrders(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+	require.NoError(t, meshDB.SaveMetadata(&meshdb.Metadata{}))
+	w := &Watcher{
+		meshDB:            meshDB,
+		expirationWatcher: expirationwatch.New(),
+	}
+	orderEventsChan := make(chan []*zeroex.OrderEvent, 10)
+	subscription := w.Subscribe(orderEventsChan)
+	defer subscription.Unsubscribe()
+
+	oldOrder := storeTestOrder(t, meshDB, scenario.NewSignedTestOrder(t, orderopts.Salt(big.NewInt(1))))
+	newOrder := storeTestOrder(t, meshDB, scenario.NewSignedTestOrder(t, orderopts.Salt(big.NewInt(2))))
+	otherMakerOrder := storeTestOrder(t, meshDB, scenario.NewSignedTestOrder(t, orderopts.MakerAddress(constants.GanacheAccount2)))
+
+	// An order can't be replaced by an order from a different maker.
+	numOrdersRemoved, err := w.ReplaceOrders(map[common.Hash]common.Hash{otherMakerOrder.Hash: oldOrder.Hash})
+	require.NoError(t, err)
+	assert.Equal(t, 0, numOrdersRemoved)
+	assertOrderNotReplaced(t, meshDB, oldOrder.Hash)
+
+	numOrdersRemoved, err = w.ReplaceOrders(map[common.Hash]common.Hash{newOrder.Hash: oldOrder.Hash})
+	require.NoError(t, err)
+	assert.Equal(t, 1, numOrdersRemoved)
+	orderEvents := waitForOrderEvents(t, orderEventsChan, 1, 4*time.Second)
+	require.Len(t, orderEvents, 1)
+	assert.Equal(t, oldOrder.Hash, orderEvents[0].OrderHash)
+	assert.Equal(t, zeroex.ESOrderReplaced, orderEvents[0].EndState)
+	assert.Equal(t, newOrder.Hash, orderEvents[0].ReplacedBy)
+
+	// The replaced order is kept as a tombstone.
+	var storedOrder meshdb.Order
+	require.NoError(t, meshDB.Orders.FindByID(oldOrder.Hash.Bytes(), &storedOrder))
+	assert.True(t, storedOrder.IsRemoved)
+	assert.True(t, storedOrder.IsReplaced())
+	assert.Equal(t, newOrder.Hash, storedOrder.ReplacedBy)
+	assert.Equal(t, ordervalidator.ROOrderReplaced.Code, storedOrder.Status())
+	assertOrderNotReplaced(t, meshDB, newOrder.Hash)
+
+	// Tombstones are only deleted once the replaced order expires.
+	storedOrder.LastUpdated = time.Now().Add(-2 * permanentlyDeleteAfter)
+	require.NoError(t, meshDB.Orders.Update(&storedOrder))
+	require.NoError(t, w.permanentlyDeleteStaleRemovedOrders(context.Background()))
+	require.NoError(t, meshDB.Orders.FindByID(oldOrder.Hash.Bytes(), &storedOrder))
+}
+
+func TestAutoReplaceOrders(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+	w := &Watcher{
+		meshDB:                  meshDB,
+		expirationWatcher:       expirationwatch.New(),
+		replaceSupersededOrders: true,
+	}
+
+	storedOrder := storeTestOrder(t, meshDB, scenario.NewSignedTestOrder(t, orderopts.Salt(big.NewInt(2))))
+
+	// Orders with a lower salt are rejected. Orders with a higher salt and
+	// orders from other makers are not.
+	lowerSaltOrderInfo := newAcceptedOrderInfo(t, scenario.NewSignedTestOrder(t, orderopts.Salt(big.NewInt(1))))
+	higherSaltOrderInfo := newAcceptedOrderInfo(t, scenario.NewSignedTestOrder(t, orderopts.Salt(big.NewInt(3))))
+	otherMakerOrderInfo := newAcceptedOrderInfo(t, scenario.NewSignedTestOrder(t, orderopts.Salt(big.NewInt(1)), orderopts.MakerAddress(constants.GanacheAccount2)))
+	newOrderInfos := []*ordervalidator.AcceptedOrderInfo{lowerSaltOrderInfo, higherSaltOrderInfo, otherMakerOrderInfo}
+	results := &ordervalidator.ValidationResults{
+		Accepted: newOrderInfos,
+		Rejected: []*ordervalidator.RejectedOrderInfo{},
+	}
+	remainingOrderInfos, err := w.rejectSupersededOrders(results, newOrderInfos)
+	require.NoError(t, err)
+	assert.Equal(t, []*ordervalidator.AcceptedOrderInfo{higherSaltOrderInfo, otherMakerOrderInfo}, remainingOrderInfos)
+	assert.Equal(t, remainingOrderInfos, results.Accepted)
+	require.Len(t, results.Rejected, 1)
+	assert.Equal(t, lowerSaltOrderInfo.OrderHash, results.Rejected[0].OrderHash)
+	assert.Equal(t, ordervalidator.ROOrderSuperseded, results.Rejected[0].Status)
+
+	// Adding the order with the higher salt replaces the stored order.
+	storeTestOrder(t, meshDB, higherSaltOrderInfo.SignedOrder)
+	storeTestOrder(t, meshDB, otherMakerOrderInfo.SignedOrder)
+	orderEvents, err := w.removeSupersededOrders(remainingOrderInfos)
+	require.NoError(t, err)
+	require.Len(t, orderEvents, 1)
+	assert.Equal(t, storedOrder.Hash, orderEvents[0].OrderHash)
+	assert.Equal(t, zeroex.ESOrderReplaced, orderEvents[0].EndState)
+	assert.Equal(t, higherSaltOrderInfo.OrderHash, orderEvents[0].ReplacedBy)
+	replacedOrder, err := w.findStoredOrder(storedOrder.Hash)
+	require.NoError(t, err)
+	assert.Nil(t, replacedOrder)
+	assertOrderNotReplaced(t, meshDB, otherMakerOrderInfo.OrderHash)
+}
+
+func TestOrderWatcherReplacedOrderCannotBeAddedAgain(t *testing.T) {
+	if !serialTestsEnabled {
+		t.Skip("Serial tests (tests which cannot run in parallel) are disabled. You can enable them with the --serial flag")
+	}
+
+	teardownSubTest := setupSubTest(t)
+	defer teardownSubTest(t)
+
+	meshDB, err := meshdb.New("/tmp/leveldb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+
+	signedOrders := scenario.NewSignedTestOrdersBatch(t, 2, func(index int) []orderopts.Option {
+		return []orderopts.Option{
+			orderopts.SetupMakerState(true),
+			orderopts.Salt(big.NewInt(int64(index))),
+		}
+	})
+	oldOrder, newOrder := signedOrders[0], signedOrders[1]
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	blockWatcher, orderWatcher := setupOrderWatcher(ctx, t, ethRPCClient, meshDB)
+	watchOrder(ctx, t, orderWatcher, blockWatcher, ethClient, oldOrder)
+	watchOrder(ctx, t, orderWatcher, blockWatcher, ethClient, newOrder)
+
+	oldOrderHash, err := oldOrder.ComputeOrderHash()
+	require.NoError(t, err)
+	newOrderHash, err := newOrder.ComputeOrderHash()
+	require.NoError(t, err)
+	numOrdersRemoved, err := orderWatcher.ReplaceOrders(map[common.Hash]common.Hash{newOrderHash: oldOrderHash})
+	require.NoError(t, err)
+	require.Equal(t, 1, numOrdersRemoved)
+
+	validationResults, err := orderWatcher.ValidateAndStoreValidOrders(ctx, []*zeroex.SignedOrder{oldOrder}, false, constants.TestChainID)
+	require.NoError(t, err)
+	assert.Empty(t, validationResults.Accepted)
+	require.Len(t, validationResults.Rejected, 1)
+	assert.Equal(t, ordervalidator.ROOrderReplaced.Code, validationResults.Rejected[0].Status.Code)
+
+	// Revalidating all orders doesn't revive the replaced order either.
+	require.NoError(t, orderWatcher.Cleanup(ctx, 0))
+	replacedOrder, err := orderWatcher.findStoredOrder(oldOrderHash)
+	require.NoError(t, err)
+	assert.Nil(t, replacedOrder)
+}
+
+func storeTestOrder(t *testing.T, meshDB *meshdb.MeshDB, signedOrder *zeroex.SignedOrder) *meshdb.Order {
+	orderHash, err := signedOrder.ComputeOrderHash()
+	require.NoError(t, err)
+	order := &meshdb.Order{
+		Hash:                     orderHash,
+		SignedOrder:              signedOrder,
+		LastUpdated:              time.Now().UTC(),
+		FillableTakerAssetAmount: signedOrder.TakerAssetAmount,
+	}
+	require.NoError(t, meshDB.Orders.Insert(order))
+	return order
+}
+
+func newAcceptedOrderInfo(t *testing.T, signedOrder *zeroex.SignedOrder) *ordervalidator.AcceptedOrderInfo {
+	orderHash, err := signedOrder.ComputeOrderHash()
+	require.NoError(t, err)
+	return &ordervalidator.AcceptedOrderInfo{
+		OrderHash:                orderHash,
+		SignedOrder:              signedOrder,
+		FillableTakerAssetAmount: signedOrder.TakerAssetAmount,
+		IsNew:                    true,
+	}
+}
+
+func assertOrderNotReplaced(t *testing.T, meshDB *meshdb.MeshDB, orderHash common.Hash) {
+	var order meshdb.Order
+	require.NoError(t, meshDB.Orders.FindByID(orderHash.Bytes(), &order))
+	assert.False(t, order.IsRemoved)
+	assert.False(t, order.IsReplaced())
+}
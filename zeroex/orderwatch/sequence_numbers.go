@@ -0,0 +1,54 @@
+package orderwatch
+
+import (
+	"fmt"
+
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/zeroex"
+)
+
+// loadLastSequenceNumber loads the last order event sequence number from the
+// database. It is called once when the Watcher is created, before any order
+// events are published. From then on, the last sequence number is kept in
+// memory.
+func (w *Watcher) loadLastSequenceNumber() error {
+	metadata, err := w.meshDB.GetMetadata()
+	if err != nil {
+		return fmt.Errorf("could not load last order event sequence number from database: %s", err)
+	}
+	w.sequenceNumberMu.Lock()
+	w.lastSequenceNumber = metadata.LastOrderEventSequenceNumber
+	w.sequenceNumberMu.Unlock()
+	return nil
+}
+
+// publishOrderEvents assigns sequence numbers to the given order events and
+// sends them to subscribers. The sequence numbers continue from the last
+// sequence number and the new last sequence number is stored before the order
+// events are sent, so sequence numbers are never reused, even across restarts.
+// If it cannot be stored, the order events are not sent and an error is
+// returned. They are kept and published ahead of the order events passed to
+// the next call, which assigns their sequence numbers again. Holding
+// sequenceNumberMu while sending guarantees that subscribers receive the order
+// events in the order of their sequence numbers.
+func (w *Watcher) publishOrderEvents(orderEvents []*zeroex.OrderEvent) error {
+	w.sequenceNumberMu.Lock()
+	defer w.sequenceNumberMu.Unlock()
+	orderEvents = append(w.unpublishedOrderEvents, orderEvents...)
+	w.unpublishedOrderEvents = nil
+	lastSequenceNumber := w.lastSequenceNumber
+	for _, orderEvent := range orderEvents {
+		lastSequenceNumber++
+		orderEvent.SequenceNumber = lastSequenceNumber
+	}
+	if err := w.meshDB.UpdateMetadata(func(metadata meshdb.Metadata) meshdb.Metadata {
+		metadata.LastOrderEventSequenceNumber = lastSequenceNumber
+		return metadata
+	}); err != nil {
+		w.unpublishedOrderEvents = orderEvents
+		return fmt.Errorf("could not save last order event sequence number in database: %s", err)
+	}
+	w.lastSequenceNumber = lastSequenceNumber
+	w.orderFeed.Send(orderEvents)
+	return nil
+}
@@ -0,0 +1,97 @@
+// +build !js
+
+package orderwatch
+
+import (
+	"testing"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/0xProject/0x-mesh/meshdb"
+	"github.com/0xProject/0x-mesh/zeroex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishOrderEventsAssignsSequenceNumbers(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+	require.NoError(t, meshDB.SaveMetadata(&meshdb.Metadata{LastOrderEventSequenceNumber: 41}))
+
+	w := &Watcher{meshDB: meshDB}
+	require.NoError(t, w.loadLastSequenceNumber())
+	sink := make(chan []*zeroex.OrderEvent, 10)
+	subscription := w.orderFeed.Subscribe(sink)
+	defer subscription.Unsubscribe()
+
+	orderEvents := []*zeroex.OrderEvent{
+		{OrderHash: common.HexToHash("0x1"), EndState: zeroex.ESOrderAdded},
+		{OrderHash: common.HexToHash("0x2"), EndState: zeroex.ESOrderAdded},
+	}
+	require.NoError(t, w.publishOrderEvents(orderEvents))
+	require.Len(t, sink, 1)
+	received := <-sink
+	assert.Equal(t, uint64(42), received[0].SequenceNumber)
+	assert.Equal(t, uint64(43), received[1].SequenceNumber)
+
+	// A new watcher (e.g. after a restart) continues where the last one left
+	// off.
+	metadata, err := meshDB.GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(43), metadata.LastOrderEventSequenceNumber)
+	restarted := &Watcher{meshDB: meshDB}
+	require.NoError(t, restarted.loadLastSequenceNumber())
+	orderEvent := &zeroex.OrderEvent{OrderHash: common.HexToHash("0x3"), EndState: zeroex.ESOrderExpired}
+	require.NoError(t, restarted.publishOrderEvents([]*zeroex.OrderEvent{orderEvent}))
+	assert.Equal(t, uint64(44), orderEvent.SequenceNumber)
+}
+
+func TestNewFailsWithoutLastSequenceNumber(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	// Without metadata, the last sequence number cannot be loaded and the
+	// Watcher must not start numbering order events from 0.
+	_, err = New(Config{
+		MeshDB:            meshDB,
+		ChainID:           constants.TestChainID,
+		ContractAddresses: ganacheAddresses,
+		MaxExpirationTime: constants.UnlimitedExpirationTime,
+		MaxOrders:         1000,
+	})
+	assert.Error(t, err)
+}
+
+func TestPublishOrderEventsSaveFailure(t *testing.T) {
+	meshDB, err := meshdb.New("/tmp/meshdb_testing/"+uuid.New().String(), ganacheAddresses)
+	require.NoError(t, err)
+	defer meshDB.Close()
+
+	// There is no metadata, so the last sequence number cannot be saved.
+	w := &Watcher{meshDB: meshDB, lastSequenceNumber: 41}
+	sink := make(chan []*zeroex.OrderEvent, 10)
+	subscription := w.orderFeed.Subscribe(sink)
+	defer subscription.Unsubscribe()
+
+	firstOrderEvent := &zeroex.OrderEvent{OrderHash: common.HexToHash("0x1"), EndState: zeroex.ESOrderAdded}
+	err = w.publishOrderEvents([]*zeroex.OrderEvent{firstOrderEvent})
+	assert.Error(t, err)
+	assert.Len(t, sink, 0)
+	assert.Equal(t, uint64(41), w.lastSequenceNumber)
+
+	// Once the last sequence number can be saved, the order events that could
+	// not be published are published ahead of the new ones.
+	require.NoError(t, meshDB.SaveMetadata(&meshdb.Metadata{LastOrderEventSequenceNumber: 41}))
+	secondOrderEvent := &zeroex.OrderEvent{OrderHash: common.HexToHash("0x2"), EndState: zeroex.ESOrderExpired}
+	require.NoError(t, w.publishOrderEvents([]*zeroex.OrderEvent{secondOrderEvent}))
+	require.Len(t, sink, 1)
+	assert.Equal(t, []*zeroex.OrderEvent{firstOrderEvent, secondOrderEvent}, <-sink)
+	assert.Equal(t, uint64(42), firstOrderEvent.SequenceNumber)
+	assert.Equal(t, uint64(43), secondOrderEvent.SequenceNumber)
+	metadata, err := meshDB.GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(43), metadata.LastOrderEventSequenceNumber)
+}
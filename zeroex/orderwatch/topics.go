@@ -1,7 +1,11 @@
 package orderwatch
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/0xProject/0x-mesh/zeroex/orderwatch/decoder"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -17,3 +21,22 @@ func GetRelevantTopics() []common.Hash {
 
 	return topics
 }
+
+// GetCustomContractTopics returns the topics of the events of the given custom
+// contracts. They need to be added to the topics used when filtering the logs
+// retrieved for Ethereum blocks in order for the custom contracts' events to be
+// handled by the OrderWatcher.
+func GetCustomContractTopics(contracts []CustomContract) ([]common.Hash, error) {
+	topics := []common.Hash{}
+	for _, contract := range contracts {
+		contractABI, err := abi.JSON(strings.NewReader(contract.ABI))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ABI for custom contract %s: %s", contract.Address.Hex(), err.Error())
+		}
+		for _, event := range contractABI.Events {
+			topics = append(topics, event.ID())
+		}
+	}
+
+	return topics, nil
+}
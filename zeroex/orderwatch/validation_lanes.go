@@ -0,0 +1,152 @@
+package orderwatch
+
+import (
+	"context"
+	"sync"
+)
+
+// ValidationLane determines the priority with which new orders are validated
+// when more orders are waiting to be validated than can be validated at once.
+type ValidationLane int
+
+const (
+	// RemoteValidationLane is used for orders received from peers. It is the
+	// default.
+	RemoteValidationLane ValidationLane = iota
+	// LocalValidationLane is used for orders submitted locally (e.g. via the
+	// JSON-RPC API), so that they don't have to wait for a backlog of orders
+	// received from peers.
+	LocalValidationLane
+	numValidationLanes
+)
+
+const (
+	// defaultMaxConcurrentValidations is the default value for
+	// Config.MaxConcurrentValidations.
+	defaultMaxConcurrentValidations = 4
+	// defaultLocalValidationWeight and defaultRemoteValidationWeight are the
+	// default values for Config.LocalValidationWeight and
+	// Config.RemoteValidationWeight.
+	defaultLocalValidationWeight  = 4
+	defaultRemoteValidationWeight = 1
+)
+
+// laneWaiter is a caller of laneScheduler.acquire which is waiting for a slot.
+type laneWaiter struct {
+	// ready is closed when the waiter has been granted a slot.
+	ready chan struct{}
+}
+
+// laneScheduler limits the number of concurrent validations and decides which
+// lane gets the next free slot. When both lanes have waiting validations,
+// slots are distributed in proportion to the weights of the lanes using smooth
+// weighted round-robin, so that neither lane can be starved. Within a lane,
+// slots are granted in FIFO order.
+type laneScheduler struct {
+	mu      sync.Mutex
+	slots   int
+	inUse   int
+	weights [numValidationLanes]int
+	current [numValidationLanes]int
+	waiting [numValidationLanes][]*laneWaiter
+}
+
+func newLaneScheduler(slots int, localWeight int, remoteWeight int) *laneScheduler {
+	s := &laneScheduler{slots: slots}
+	s.weights[LocalValidationLane] = localWeight
+	s.weights[RemoteValidationLane] = remoteWeight
+	return s
+}
+
+// acquire blocks until a validation slot is granted to the given lane. If it
+// returns nil, the caller must call release once it is done. If ctx is done
+// before a slot is granted, ctx.Err() is returned.
+func (s *laneScheduler) acquire(ctx context.Context, lane ValidationLane) error {
+	s.mu.Lock()
+	if s.inUse < s.slots && s.numWaiting() == 0 {
+		s.inUse++
+		s.mu.Unlock()
+		return nil
+	}
+	waiter := &laneWaiter{ready: make(chan struct{})}
+	s.waiting[lane] = append(s.waiting[lane], waiter)
+	s.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-waiter.ready:
+			// The slot was granted concurrently, so pass it on.
+			s.inUse--
+			s.dispatch()
+		default:
+			s.removeWaiter(lane, waiter)
+		}
+		return ctx.Err()
+	}
+}
+
+// release frees a slot granted by acquire.
+func (s *laneScheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inUse--
+	s.dispatch()
+}
+
+// dispatch grants free slots to waiters. s.mu must be held.
+func (s *laneScheduler) dispatch() {
+	for s.inUse < s.slots {
+		lane, ok := s.nextLane()
+		if !ok {
+			return
+		}
+		waiter := s.waiting[lane][0]
+		s.waiting[lane] = s.waiting[lane][1:]
+		s.inUse++
+		close(waiter.ready)
+	}
+}
+
+// nextLane picks the lane which gets the next slot among the lanes with
+// waiters. s.mu must be held.
+func (s *laneScheduler) nextLane() (ValidationLane, bool) {
+	totalWeight := 0
+	best := ValidationLane(-1)
+	for lane := ValidationLane(0); lane < numValidationLanes; lane++ {
+		if len(s.waiting[lane]) == 0 {
+			continue
+		}
+		s.current[lane] += s.weights[lane]
+		totalWeight += s.weights[lane]
+		if best == -1 || s.current[lane] > s.current[best] {
+			best = lane
+		}
+	}
+	if best == -1 {
+		return 0, false
+	}
+	s.current[best] -= totalWeight
+	return best, true
+}
+
+func (s *laneScheduler) numWaiting() int {
+	total := 0
+	for _, waiters := range s.waiting {
+		total += len(waiters)
+	}
+	return total
+}
+
+func (s *laneScheduler) removeWaiter(lane ValidationLane, waiter *laneWaiter) {
+	for i, w := range s.waiting[lane] {
+		if w == waiter {
+			s.waiting[lane] = append(s.waiting[lane][:i], s.waiting[lane][i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// +build !js
+
+package orderwatch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// acquireAsync calls acquire in a goroutine and returns a channel which
+// receives its result. It waits until the call is queued.
+func acquireAsync(t *testing.T, s *laneScheduler, ctx context.Context, lane ValidationLane) chan error {
+	s.mu.Lock()
+	numWaiting := s.numWaiting()
+	s.mu.Unlock()
+	result := make(chan error, 1)
+	go func() {
+		result <- s.acquire(ctx, lane)
+	}()
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.mu.Lock()
+		queued := s.numWaiting() == numWaiting+1
+		s.mu.Unlock()
+		if queued {
+			return result
+		}
+		require.True(t, time.Now().Before(deadline), "timed out waiting for acquire to be queued")
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLaneSchedulerPrioritizesLocalLane(t *testing.T) {
+	s := newLaneScheduler(1, 2, 1)
+	ctx := context.Background()
+
+	// Occupy the only slot.
+	require.NoError(t, s.acquire(ctx, RemoteValidationLane))
+
+	remote := []chan error{}
+	for i := 0; i < 3; i++ {
+		remote = append(remote, acquireAsync(t, s, ctx, RemoteValidationLane))
+	}
+	local := []chan error{}
+	for i := 0; i < 3; i++ {
+		local = append(local, acquireAsync(t, s, ctx, LocalValidationLane))
+	}
+
+	// With weights of 2 and 1, local validations get two out of every three
+	// slots even though the remote validations were queued first.
+	order := []string{}
+	for i := 0; i < 6; i++ {
+		s.release()
+		select {
+		case err := <-local[0]:
+			require.NoError(t, err)
+			order = append(order, "local")
+			local = local[1:]
+		case err := <-remote[0]:
+			require.NoError(t, err)
+			order = append(order, "remote")
+			remote = remote[1:]
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for slot to be granted")
+		}
+	}
+	assert.Equal(t, []string{"local", "remote", "local", "local", "remote", "remote"}, order)
+}
+
+func TestLaneSchedulerAcquireRespectsContext(t *testing.T) {
+	s := newLaneScheduler(1, 4, 1)
+	require.NoError(t, s.acquire(context.Background(), LocalValidationLane))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := acquireAsync(t, s, ctx, RemoteValidationLane)
+	cancel()
+	assert.Equal(t, context.Canceled, <-result)
+
+	// The canceled waiter doesn't take up the slot once it is released.
+	s.release()
+	require.NoError(t, s.acquire(context.Background(), RemoteValidationLane))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Equal(t, 1, s.inUse)
+	assert.Equal(t, 0, s.numWaiting())
+}
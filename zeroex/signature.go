@@ -0,0 +1,67 @@
+package zeroex
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/0xProject/0x-mesh/zeroex/orderhash"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrSignatureNotRecoverable is returned by RecoverSigner for signature types
+// which don't consist of an ECDSA signature (e.g. Wallet or Validator
+// signatures). Such signatures can only be verified on-chain.
+var ErrSignatureNotRecoverable = orderhash.ErrSignatureNotRecoverable
+
+// RecoverSigner returns the address of the account that produced the given
+// EIP712 or EthSign signature for the order with the given hash. It returns
+// ErrSignatureNotRecoverable for all other signature types.
+func RecoverSigner(orderHash common.Hash, signature []byte) (common.Address, error) {
+	return orderhash.RecoverSigner(orderHash, signature)
+}
+
+// OfflineSignatureStatus is the result of validating a signature without
+// making any calls to Ethereum.
+type OfflineSignatureStatus = orderhash.OfflineSignatureStatus
+
+// OfflineSignatureStatus values
+const (
+	// SignatureInvalid means that the signature is malformed, has an
+	// unsupported type or was not produced by the maker.
+	SignatureInvalid = orderhash.SignatureInvalid
+	// SignatureValid means that the signature is an EIP712 or EthSign
+	// signature produced by the maker.
+	SignatureValid = orderhash.SignatureValid
+	// SignatureRequiresOnChainValidation means that the signature is well
+	// formed, but its type (Wallet, Validator, PreSigned or EIP1271Wallet) can
+	// only be validated by calling the Exchange contract or DevUtils.
+	SignatureRequiresOnChainValidation = orderhash.SignatureRequiresOnChainValidation
+)
+
+// ValidateSignatureOffline checks the signature of the order with the given
+// hash and maker without making any calls to Ethereum. EIP712 and EthSign
+// signatures are fully validated by recovering the signer and comparing it to
+// the maker. For all other signature types, only the format of the signature
+// is checked and SignatureRequiresOnChainValidation is returned if it is well
+// formed. If the signature is invalid, the returned error describes why.
+func ValidateSignatureOffline(orderHash common.Hash, makerAddress common.Address, signature []byte) (OfflineSignatureStatus, error) {
+	return orderhash.ValidateSignatureOffline(orderHash, makerAddress, signature)
+}
+
+// ValidateSignatureOffline checks the signature of the order without making
+// any calls to Ethereum. See the package-level ValidateSignatureOffline for
+// details.
+func (s *SignedOrder) ValidateSignatureOffline() (OfflineSignatureStatus, error) {
+	orderHash, err := s.ComputeOrderHash()
+	if err != nil {
+		return SignatureInvalid, err
+	}
+	return ValidateSignatureOffline(orderHash, s.MakerAddress, s.Signature)
+}
+
+// SignOrderHash produces an EIP712 or EthSign signature in the 0x format for
+// the order with the given hash. It returns ErrSignatureNotRecoverable for all
+// other signature types. Signatures are deterministic (RFC 6979), so the same
+// private key and order hash always result in the same signature.
+func SignOrderHash(privateKey *ecdsa.PrivateKey, orderHash common.Hash, signatureType SignatureType) ([]byte, error) {
+	return orderhash.SignOrderHash(privateKey, orderHash, signatureType)
+}
@@ -0,0 +1,89 @@
+package zeroex
+
+import (
+	"testing"
+
+	"github.com/0xProject/0x-mesh/constants"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverSigner(t *testing.T) {
+	signedOrder, err := SignTestOrder(testOrder)
+	require.NoError(t, err)
+	orderHash, err := signedOrder.ComputeOrderHash()
+	require.NoError(t, err)
+
+	signer, err := RecoverSigner(orderHash, signedOrder.Signature)
+	require.NoError(t, err)
+	assert.Equal(t, constants.GanacheAccount0, signer)
+
+	// A signature for a different order hash recovers a different address.
+	signer, err = RecoverSigner(common.HexToHash("0x1"), signedOrder.Signature)
+	require.NoError(t, err)
+	assert.NotEqual(t, constants.GanacheAccount0, signer)
+}
+
+func TestRecoverSignerErrors(t *testing.T) {
+	orderHash := common.HexToHash("0x1")
+
+	_, err := RecoverSigner(orderHash, []byte{})
+	assert.Error(t, err)
+
+	walletSignature := []byte{byte(WalletSignature)}
+	_, err = RecoverSigner(orderHash, walletSignature)
+	assert.Equal(t, ErrSignatureNotRecoverable, err)
+
+	tooShort := []byte{27, 1, 2, byte(EthSignSignature)}
+	_, err = RecoverSigner(orderHash, tooShort)
+	assert.Error(t, err)
+
+	invalidV := make([]byte, 66)
+	invalidV[0] = 30
+	invalidV[65] = byte(EIP712Signature)
+	_, err = RecoverSigner(orderHash, invalidV)
+	assert.Error(t, err)
+}
+
+func TestValidateSignatureOffline(t *testing.T) {
+	signedOrder, err := SignTestOrder(testOrder)
+	require.NoError(t, err)
+	orderHash, err := signedOrder.ComputeOrderHash()
+	require.NoError(t, err)
+
+	status, err := signedOrder.ValidateSignatureOffline()
+	require.NoError(t, err)
+	assert.Equal(t, SignatureValid, status)
+
+	// An ECDSA signature by someone other than the maker is invalid.
+	status, err = ValidateSignatureOffline(orderHash, constants.GanacheAccount1, signedOrder.Signature)
+	assert.Error(t, err)
+	assert.Equal(t, SignatureInvalid, status)
+
+	validatorSignature := append(constants.GanacheAccount1.Bytes(), byte(ValidatorSignature))
+	testCases := []struct {
+		signature      []byte
+		expectedStatus OfflineSignatureStatus
+	}{
+		{signature: []byte{}, expectedStatus: SignatureInvalid},
+		{signature: []byte{byte(IllegalSignature)}, expectedStatus: SignatureInvalid},
+		{signature: []byte{byte(InvalidSignature)}, expectedStatus: SignatureInvalid},
+		{signature: []byte{byte(NSignatureTypesSignature)}, expectedStatus: SignatureInvalid},
+		{signature: []byte{27, 1, 2, byte(EIP712Signature)}, expectedStatus: SignatureInvalid},
+		{signature: []byte{byte(ValidatorSignature)}, expectedStatus: SignatureInvalid},
+		{signature: validatorSignature, expectedStatus: SignatureRequiresOnChainValidation},
+		{signature: []byte{byte(WalletSignature)}, expectedStatus: SignatureRequiresOnChainValidation},
+		{signature: []byte{byte(PreSignedSignature)}, expectedStatus: SignatureRequiresOnChainValidation},
+		{signature: []byte{byte(EIP1271WalletSignature)}, expectedStatus: SignatureRequiresOnChainValidation},
+	}
+	for i, testCase := range testCases {
+		status, err := ValidateSignatureOffline(orderHash, constants.GanacheAccount0, testCase.signature)
+		assert.Equal(t, testCase.expectedStatus, status, "test case %d", i)
+		if testCase.expectedStatus == SignatureInvalid {
+			assert.Error(t, err, "test case %d", i)
+		} else {
+			assert.NoError(t, err, "test case %d", i)
+		}
+	}
+}
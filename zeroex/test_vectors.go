@@ -0,0 +1,93 @@
+package zeroex
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// OrderTestVectors is the format of the order hashing and signing test vectors
+// in zeroex/testdata/order_test_vectors.json. The file is generated by
+// cmd/generate-order-test-vectors and can be used by implementations in other
+// languages (e.g. @0x/order-utils) to verify that they are byte-exact
+// compatible with this package.
+type OrderTestVectors struct {
+	Vectors []*OrderTestVector `json:"vectors"`
+}
+
+// OrderTestVector contains an order together with its expected hash and its
+// expected signatures by the given private key.
+type OrderTestVector struct {
+	Description   string `json:"description"`
+	PrivateKey    string `json:"privateKey"`
+	SignerAddress string `json:"signerAddress"`
+	// Order is the order in the same format as a SignedOrder encoded as JSON.
+	// Its signature is always "0x". The signatures are listed in Signatures.
+	Order      *SignedOrder           `json:"order"`
+	OrderHash  string                 `json:"orderHash"`
+	Signatures []*SignatureTestVector `json:"signatures"`
+}
+
+// SignatureTestVector is the expected signature of an order of the given
+// signature type.
+type SignatureTestVector struct {
+	SignatureType string `json:"signatureType"`
+	Signature     string `json:"signature"`
+}
+
+// testVectorSignatureTypes are the signature types included in test vectors
+// and their names. Only signature types which can be produced off-chain are
+// included.
+var testVectorSignatureTypes = []struct {
+	signatureType SignatureType
+	name          string
+}{
+	{signatureType: EIP712Signature, name: "EIP712"},
+	{signatureType: EthSignSignature, name: "EthSign"},
+}
+
+// NewOrderTestVector computes the hash of the given order and its signatures
+// by the given private key.
+func NewOrderTestVector(description string, privateKey *ecdsa.PrivateKey, order *Order) (*OrderTestVector, error) {
+	// Copy the order so that any cached hash is not reused.
+	orderCopy := *order
+	orderCopy.ResetHash()
+	orderHash, err := orderCopy.ComputeOrderHash()
+	if err != nil {
+		return nil, err
+	}
+	signatures := make([]*SignatureTestVector, len(testVectorSignatureTypes))
+	for i, signatureType := range testVectorSignatureTypes {
+		signature, err := SignOrderHash(privateKey, orderHash, signatureType.signatureType)
+		if err != nil {
+			return nil, err
+		}
+		signatures[i] = &SignatureTestVector{
+			SignatureType: signatureType.name,
+			Signature:     fmt.Sprintf("0x%s", common.Bytes2Hex(signature)),
+		}
+	}
+	orderCopy.ResetHash()
+	return &OrderTestVector{
+		Description:   description,
+		PrivateKey:    fmt.Sprintf("0x%s", common.Bytes2Hex(crypto.FromECDSA(privateKey))),
+		SignerAddress: strings.ToLower(crypto.PubkeyToAddress(privateKey.PublicKey).Hex()),
+		Order:         &SignedOrder{Order: orderCopy},
+		OrderHash:     orderHash.Hex(),
+		Signatures:    signatures,
+	}, nil
+}
+
+// MarshalOrderTestVectors encodes the given test vectors in the format of
+// zeroex/testdata/order_test_vectors.json.
+func MarshalOrderTestVectors(vectors *OrderTestVectors) ([]byte, error) {
+	encoded, err := json.MarshalIndent(vectors, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(encoded, '\n'), nil
+}
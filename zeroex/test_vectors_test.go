@@ -0,0 +1,64 @@
+package zeroex
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const orderTestVectorsPath = "testdata/order_test_vectors.json"
+
+func TestOrderTestVectors(t *testing.T) {
+	data, err := ioutil.ReadFile(orderTestVectorsPath)
+	require.NoError(t, err)
+	var expected OrderTestVectors
+	require.NoError(t, json.Unmarshal(data, &expected))
+	require.NotEmpty(t, expected.Vectors)
+
+	actual := &OrderTestVectors{}
+	for _, vector := range expected.Vectors {
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(vector.PrivateKey, "0x"))
+		require.NoError(t, err)
+		actualVector, err := NewOrderTestVector(vector.Description, privateKey, &vector.Order.Order)
+		require.NoError(t, err)
+		actual.Vectors = append(actual.Vectors, actualVector)
+
+		// All signatures must be recoverable to the signer address.
+		orderHash := common.HexToHash(vector.OrderHash)
+		for _, signature := range vector.Signatures {
+			signer, err := RecoverSigner(orderHash, common.FromHex(signature.Signature))
+			require.NoError(t, err)
+			assert.Equal(t, common.HexToAddress(vector.SignerAddress), signer, "%s: %s", vector.Description, signature.SignatureType)
+		}
+	}
+
+	// The test vectors are compared byte for byte so that the file doesn't
+	// silently diverge from what cmd/generate-order-test-vectors produces.
+	actualData, err := MarshalOrderTestVectors(actual)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(actualData))
+}
+
+func TestSignOrderHash(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	orderHash := common.HexToHash("0x1")
+
+	for _, signatureType := range []SignatureType{EIP712Signature, EthSignSignature} {
+		signature, err := SignOrderHash(privateKey, orderHash, signatureType)
+		require.NoError(t, err)
+		assert.Equal(t, byte(signatureType), signature[65])
+		signer, err := RecoverSigner(orderHash, signature)
+		require.NoError(t, err)
+		assert.Equal(t, crypto.PubkeyToAddress(privateKey.PublicKey), signer)
+	}
+
+	_, err = SignOrderHash(privateKey, orderHash, WalletSignature)
+	assert.Equal(t, ErrSignatureNotRecoverable, err)
+}